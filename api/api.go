@@ -0,0 +1,9 @@
+// Package api embeds the OpenAPI specification of the InfiniteTalk HTTP API.
+package api
+
+import _ "embed"
+
+// OpenAPIYAML is the OpenAPI 3 specification in openapi.yaml.
+//
+//go:embed openapi.yaml
+var OpenAPIYAML []byte
@@ -4,11 +4,13 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -25,11 +27,17 @@ func main() {
 }
 
 func run() error {
-	// Load configuration from environment
-	cfg, err := config.Load()
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML or JSON config file; environment variables override it")
+	flag.Parse()
+
+	// Load configuration from the config file, if any, and the environment
+	cfg, err := loadConfig(*configFile)
 	if err != nil {
 		return fmt.Errorf("load config: %w", err)
 	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("validate config: %w", err)
+	}
 
 	// Create structured logger
 	logger := cfg.NewLogger()
@@ -37,11 +45,16 @@ func run() error {
 
 	logger.Info("starting InfiniteTalk API",
 		slog.Int("port", cfg.Port),
+		slog.Int("admin_port", cfg.AdminPort),
+		slog.Bool("admin_jobs_api", cfg.AdminAPIKey != ""),
+		slog.String("config_file", *configFile),
 		slog.String("log_format", cfg.LogFormat),
 		slog.String("log_level", cfg.LogLevel),
+		slog.String("access_log_format", cfg.AccessLogFormat),
 		slog.String("temp_dir", cfg.TempDir),
 		slog.Int("chunk_target_sec", cfg.ChunkTargetSec),
 		slog.Bool("s3_enabled", cfg.S3Enabled()),
+		slog.Bool("gcs_enabled", cfg.GCSEnabled()),
 		slog.Bool("beam_enabled", cfg.BeamEnabled()),
 		slog.String("runpod_endpoint_id", cfg.RunPodEndpointID),
 	)
@@ -52,9 +65,49 @@ func run() error {
 		return fmt.Errorf("initialize dependencies: %w", err)
 	}
 
+	// Warm up providers in the background so startup is not delayed
+	if cfg.Prewarm {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.PrewarmTimeoutSec)*time.Second)
+			defer cancel()
+			deps.VideoService.PrewarmProviders(ctx)
+		}()
+	}
+
+	// Purge expired jobs and orphaned temp files until shutdown
+	janitorCtx, stopJanitor := context.WithCancel(context.Background())
+	defer stopJanitor()
+	var janitorDone sync.WaitGroup
+	if deps.Janitor != nil {
+		janitorDone.Add(1)
+		go func() {
+			defer janitorDone.Done()
+			deps.Janitor.Run(janitorCtx)
+		}()
+	}
+
+	// Start the workers that process jobs in the background
+	deps.Dispatcher.Start()
+
 	// Initialize HTTP handlers and router
-	handlers := server.NewHandlers(deps.VideoService, logger)
-	router := server.NewRouter(handlers, logger, server.DefaultConfig())
+	handlers := server.NewHandlers(deps.VideoService, logger,
+		server.WithDispatcher(deps.Dispatcher),
+		server.WithVideoReadBudget(time.Duration(cfg.VideoReadBudgetSec)*time.Second),
+		server.WithResultCompression(cfg.ResultCompression),
+		server.WithReadinessChecks(deps.ReadinessChecks...),
+		server.WithInputTypeCheck(cfg.InputTypeCheck),
+		server.WithDimensionMultiple(cfg.DimensionMultiple),
+		server.WithAllowedDimensions(cfg.AllowedDimensions...),
+		server.WithIdempotencyTTL(time.Duration(cfg.IdempotencyTTLSec)*time.Second),
+		server.WithMaxRequestBytes(cfg.MaxRequestBytes),
+	)
+	serverCfg := server.DefaultConfig()
+	serverCfg.AccessLogFormat = cfg.AccessLogFormat
+	serverCfg.SeparateAdmin = cfg.AdminPort != 0
+	serverCfg.GzipMinBytes = cfg.GzipMinBytes
+	serverCfg.RequestTimeout = time.Duration(cfg.RequestTimeoutSec) * time.Second
+	serverCfg.AdminAPIKey = cfg.AdminAPIKey
+	router := server.NewRouter(handlers, logger, serverCfg)
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -64,20 +117,34 @@ func run() error {
 		WriteTimeout: 300 * time.Second, // Allow for long video processing
 		IdleTimeout:  60 * time.Second,
 	}
+	servers := []*http.Server{srv}
+
+	// Serve admin endpoints on their own listener when ADMIN_PORT is set
+	if cfg.AdminPort != 0 {
+		servers = append(servers, &http.Server{
+			Addr:         fmt.Sprintf(":%d", cfg.AdminPort),
+			Handler:      server.NewAdminRouter(handlers, logger, serverCfg),
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: 10 * time.Second,
+			IdleTimeout:  60 * time.Second,
+		})
+	}
 
 	// Graceful shutdown handling
 	shutdownCh := make(chan os.Signal, 1)
 	signal.Notify(shutdownCh, os.Interrupt, syscall.SIGTERM)
 
-	errCh := make(chan error, 1)
-	go func() {
-		logger.Info("HTTP server listening",
-			slog.String("addr", srv.Addr),
-		)
-		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			errCh <- fmt.Errorf("server failed: %w", err)
-		}
-	}()
+	errCh := make(chan error, len(servers))
+	for _, s := range servers {
+		go func(s *http.Server) {
+			logger.Info("HTTP server listening",
+				slog.String("addr", s.Addr),
+			)
+			if err := s.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- fmt.Errorf("server %s failed: %w", s.Addr, err)
+			}
+		}(s)
+	}
 
 	// Wait for shutdown signal or error
 	select {
@@ -86,18 +153,67 @@ func run() error {
 			slog.String("signal", sig.String()),
 		)
 	case err := <-errCh:
+		// Stop any listener that is still running before returning
+		for _, s := range servers {
+			_ = s.Close()
+		}
+		stopJanitor()
+		janitorDone.Wait()
 		return err
 	}
 
+	// Stop the janitor so it does not delete jobs during shutdown
+	stopJanitor()
+	janitorDone.Wait()
+
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	logger.Info("shutting down server...")
-	if err := srv.Shutdown(ctx); err != nil {
+	if err := shutdownServers(ctx, servers); err != nil {
 		return fmt.Errorf("shutdown failed: %w", err)
 	}
 
+	// Let jobs in progress finish; the servers no longer start new ones
+	logger.Info("waiting for jobs in progress",
+		slog.Int("drain_sec", cfg.ShutdownDrainSec),
+	)
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownDrainSec)*time.Second)
+	defer cancelDrain()
+	if err := handlers.Drain(drainCtx); err != nil {
+		logger.Warn("jobs did not finish before shutdown",
+			slog.String("error", err.Error()),
+		)
+	}
+
 	logger.Info("server stopped gracefully")
 	return nil
 }
+
+// shutdownServers gracefully shuts down all servers concurrently and returns
+// the joined errors.
+func shutdownServers(ctx context.Context, servers []*http.Server) error {
+	errs := make([]error, len(servers))
+	var wg sync.WaitGroup
+	for i, s := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.Shutdown(ctx); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", s.Addr, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// loadConfig loads configuration from path with environment overrides, or
+// from the environment alone when path is empty.
+func loadConfig(path string) (*config.Config, error) {
+	if path == "" {
+		return config.Load()
+	}
+	return config.LoadFromFile(path)
+}
@@ -99,10 +99,13 @@ func (s *FFmpegSplitter) Split(ctx context.Context, inputWav, outputDir string,
 		return nil, fmt.Errorf("get audio duration: %w", err)
 	}
 
+	// Chunks are resampled to the target rate and channels as they are extracted
+	format := formatArgs(opts)
+
 	// If audio is shorter than or equal to target, return single file
 	if duration <= float64(opts.ChunkTargetSec) {
 		outputPath := filepath.Join(outputDir, "chunk_000.wav")
-		if err := s.copyAudio(ctx, inputWav, outputPath); err != nil {
+		if err := s.copyAudio(ctx, inputWav, outputPath, format); err != nil {
 			return nil, fmt.Errorf("copy audio: %w", err)
 		}
 		return []string{outputPath}, nil
@@ -115,10 +118,10 @@ func (s *FFmpegSplitter) Split(ctx context.Context, inputWav, outputDir string,
 	}
 
 	// Calculate split points based on target chunk duration
-	splitPoints := s.calculateSplitPoints(silences, duration, opts.ChunkTargetSec)
+	splitPoints := s.calculateSplitPoints(silences, duration, opts.ChunkTargetSec, opts.MaxChunks)
 
 	// Extract chunks
-	chunks, err := s.extractChunks(ctx, inputWav, outputDir, splitPoints, duration)
+	chunks, err := s.extractChunks(ctx, inputWav, outputDir, splitPoints, duration, format)
 	if err != nil {
 		return nil, fmt.Errorf("extract chunks: %w", err)
 	}
@@ -126,6 +129,19 @@ func (s *FFmpegSplitter) Split(ctx context.Context, inputWav, outputDir string,
 	return chunks, nil
 }
 
+// formatArgs returns the ffmpeg arguments that resample audio to the target
+// sample rate and channels of opts. Unset targets keep the source values.
+func formatArgs(opts SplitOpts) []string {
+	var args []string
+	if opts.TargetSampleRate > 0 {
+		args = append(args, "-ar", strconv.Itoa(opts.TargetSampleRate))
+	}
+	if opts.TargetChannels > 0 {
+		args = append(args, "-ac", strconv.Itoa(opts.TargetChannels))
+	}
+	return args
+}
+
 // getAudioDuration returns the duration of an audio file in seconds.
 func (s *FFmpegSplitter) getAudioDuration(ctx context.Context, inputPath string) (float64, error) {
 	// #nosec G204 - ffmpegPath is set by the application, not user input
@@ -232,17 +248,19 @@ func parseSilenceOutput(output string) ([]SilenceInterval, error) {
 }
 
 // calculateSplitPoints determines optimal split points based on silence intervals.
-func (s *FFmpegSplitter) calculateSplitPoints(silences []SilenceInterval, totalDuration float64, targetSec int) []float64 {
+// If maxChunks is positive, at most maxChunks-1 points are returned so the
+// remaining audio ends up in the final chunk.
+func (s *FFmpegSplitter) calculateSplitPoints(silences []SilenceInterval, totalDuration float64, targetSec, maxChunks int) []float64 {
 	if len(silences) == 0 {
 		// No silences detected, split at fixed intervals
-		return s.fixedSplitPoints(totalDuration, targetSec)
+		return s.fixedSplitPoints(totalDuration, targetSec, maxChunks)
 	}
 
 	target := float64(targetSec)
 	var splitPoints []float64
 	lastSplit := 0.0
 
-	for lastSplit < totalDuration-target/2 {
+	for lastSplit < totalDuration-target/2 && !splitLimitReached(splitPoints, maxChunks) {
 		// Find the best silence boundary near the target
 		idealPoint := lastSplit + target
 		bestSilence := findBestSilence(silences, idealPoint, target/3) // Allow 1/3 deviation
@@ -273,17 +291,22 @@ func (s *FFmpegSplitter) calculateSplitPoints(silences []SilenceInterval, totalD
 }
 
 // fixedSplitPoints generates evenly spaced split points when no silences are found.
-func (s *FFmpegSplitter) fixedSplitPoints(totalDuration float64, targetSec int) []float64 {
+func (s *FFmpegSplitter) fixedSplitPoints(totalDuration float64, targetSec, maxChunks int) []float64 {
 	var points []float64
 	target := float64(targetSec)
 
-	for t := target; t < totalDuration-1; t += target {
+	for t := target; t < totalDuration-1 && !splitLimitReached(points, maxChunks); t += target {
 		points = append(points, t)
 	}
 
 	return points
 }
 
+// splitLimitReached reports whether points already yields maxChunks chunks.
+func splitLimitReached(points []float64, maxChunks int) bool {
+	return maxChunks > 0 && len(points) >= maxChunks-1
+}
+
 // findBestSilence finds the silence interval closest to the ideal point within tolerance.
 func findBestSilence(silences []SilenceInterval, idealPoint, tolerance float64) *SilenceInterval {
 	var best *SilenceInterval
@@ -318,8 +341,9 @@ func abs(x float64) float64 {
 	return x
 }
 
-// extractChunks creates audio chunk files based on split points.
-func (s *FFmpegSplitter) extractChunks(ctx context.Context, inputPath, outputDir string, splitPoints []float64, totalDuration float64) ([]string, error) {
+// extractChunks creates audio chunk files based on split points, encoded with
+// the format arguments (see formatArgs).
+func (s *FFmpegSplitter) extractChunks(ctx context.Context, inputPath, outputDir string, splitPoints []float64, totalDuration float64, format []string) ([]string, error) {
 	// Create output directory if it doesn't exist
 	if err := os.MkdirAll(outputDir, 0750); err != nil {
 		return nil, fmt.Errorf("create output directory: %w", err)
@@ -339,7 +363,7 @@ func (s *FFmpegSplitter) extractChunks(ctx context.Context, inputPath, outputDir
 	for i, seg := range segments {
 		outputPath := filepath.Join(outputDir, fmt.Sprintf("chunk_%03d.wav", i))
 
-		if err := s.extractSegment(ctx, inputPath, outputPath, seg[0], seg[1]-seg[0]); err != nil {
+		if err := s.extractSegment(ctx, inputPath, outputPath, seg[0], seg[1]-seg[0], format); err != nil {
 			// Cleanup already created chunks on error (best-effort, ignore errors)
 			for _, chunk := range chunks {
 				_ = os.Remove(chunk)
@@ -353,12 +377,13 @@ func (s *FFmpegSplitter) extractChunks(ctx context.Context, inputPath, outputDir
 	return chunks, nil
 }
 
-// extractSegment extracts a portion of audio to a new WAV file with pcm_s16le encoding.
-// It places -ss after -i for precise seeking and uses -to for accurate timing.
+// extractSegment extracts a portion of audio to a new WAV file with pcm_s16le encoding,
+// resampled with the format arguments. It places -ss after -i for precise seeking
+// and uses -to for accurate timing.
 // If extraction or validation fails, it retries with normalized settings (16kHz mono).
-func (s *FFmpegSplitter) extractSegment(ctx context.Context, inputPath, outputPath string, start, duration float64) error {
-	// Try extraction with source sample rate/channels first
-	err := s.extractSegmentWithArgs(ctx, inputPath, outputPath, start, duration, nil)
+func (s *FFmpegSplitter) extractSegment(ctx context.Context, inputPath, outputPath string, start, duration float64, format []string) error {
+	// Try extraction with the target (or source) sample rate/channels first
+	err := s.extractSegmentWithArgs(ctx, inputPath, outputPath, start, duration, format)
 	if err == nil {
 		// Validate the output file format
 		info, validateErr := s.validateWAVChunk(ctx, outputPath)
@@ -424,16 +449,17 @@ func (s *FFmpegSplitter) extractSegmentWithArgs(ctx context.Context, inputPath,
 	return nil
 }
 
-// copyAudio copies an audio file to a new location as WAV with pcm_s16le encoding.
+// copyAudio copies an audio file to a new location as WAV with pcm_s16le encoding,
+// resampled with the format arguments.
 // If the initial copy fails or validation fails, it retries with normalized settings (16kHz mono).
-func (s *FFmpegSplitter) copyAudio(ctx context.Context, src, dst string) error {
+func (s *FFmpegSplitter) copyAudio(ctx context.Context, src, dst string, format []string) error {
 	// Create output directory if it doesn't exist
 	if err := os.MkdirAll(filepath.Dir(dst), 0750); err != nil {
 		return fmt.Errorf("create output directory: %w", err)
 	}
 
-	// Try with source sample rate/channels first
-	err := s.copyAudioWithArgs(ctx, src, dst, nil)
+	// Try with the target (or source) sample rate/channels first
+	err := s.copyAudioWithArgs(ctx, src, dst, format)
 	if err == nil {
 		// Validate the output file format
 		info, validateErr := s.validateWAVChunk(ctx, dst)
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"testing"
 	"time"
@@ -234,6 +235,79 @@ func TestFFmpegSplitter_NoSilences(t *testing.T) {
 	}
 }
 
+func TestFFmpegSplitter_MaxChunks(t *testing.T) {
+	checkFFmpeg(t)
+
+	tmpDir := t.TempDir()
+	inputPath := filepath.Join(tmpDir, "long.wav")
+	outputDir := filepath.Join(tmpDir, "output")
+
+	// 60 second audio with a 5s target would produce 12 chunks without a cap
+	createTestWAV(t, inputPath, 60, nil)
+
+	splitter := NewFFmpegSplitter("")
+	opts := SplitOpts{
+		ChunkTargetSec:  5,
+		MinSilenceMs:    500,
+		SilenceThreshDB: -40,
+		MaxChunks:       3,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	chunks, err := splitter.Split(ctx, inputPath, outputDir, opts)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	if len(chunks) != 3 {
+		t.Fatalf("expected chunk count capped at 3, got %d", len(chunks))
+	}
+
+	// The final chunk absorbs the remaining audio
+	info, err := splitter.ValidateChunk(ctx, chunks[2])
+	if err != nil {
+		t.Fatalf("ValidateChunk failed: %v", err)
+	}
+	if info.Duration < 45 {
+		t.Errorf("expected final chunk to hold the remaining ~50s, got %.2fs", info.Duration)
+	}
+}
+
+func TestCalculateSplitPoints_MaxChunks(t *testing.T) {
+	splitter := NewFFmpegSplitter("")
+
+	// A silence every 10 seconds over 300 seconds of audio
+	var silences []SilenceInterval
+	for start := 9.5; start < 290; start += 10 {
+		silences = append(silences, SilenceInterval{Start: start, End: start + 1})
+	}
+
+	tests := []struct {
+		name      string
+		silences  []SilenceInterval
+		maxChunks int
+		want      int
+	}{
+		{"silences capped", silences, 4, 3},
+		{"silences uncapped", silences, 0, 29},
+		{"fixed intervals capped", nil, 4, 3},
+		{"fixed intervals uncapped", nil, 0, 29},
+		{"cap above natural count", nil, 100, 29},
+		{"single chunk", nil, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points := splitter.calculateSplitPoints(tt.silences, 300, 10, tt.maxChunks)
+			if len(points) != tt.want {
+				t.Errorf("expected %d split points, got %d: %v", tt.want, len(points), points)
+			}
+		})
+	}
+}
+
 func TestFFmpegSplitter_ContextCancellation(t *testing.T) {
 	checkFFmpeg(t)
 
@@ -279,6 +353,62 @@ func TestDefaultSplitOpts(t *testing.T) {
 	if opts.SilenceThreshDB != -40 {
 		t.Errorf("SilenceThreshDB: got %f, want -40", opts.SilenceThreshDB)
 	}
+	if opts.MaxChunks != 100 {
+		t.Errorf("MaxChunks: got %d, want 100", opts.MaxChunks)
+	}
+	if opts.TargetSampleRate != 16000 {
+		t.Errorf("TargetSampleRate: got %d, want 16000", opts.TargetSampleRate)
+	}
+	if opts.TargetChannels != 1 {
+		t.Errorf("TargetChannels: got %d, want 1", opts.TargetChannels)
+	}
+}
+
+func TestFormatArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts SplitOpts
+		want []string
+	}{
+		{"defaults", DefaultSplitOpts(), []string{"-ar", "16000", "-ac", "1"}},
+		{"sample rate only", SplitOpts{TargetSampleRate: 24000}, []string{"-ar", "24000"}},
+		{"channels only", SplitOpts{TargetChannels: 2}, []string{"-ac", "2"}},
+		{"source format", SplitOpts{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatArgs(tt.opts); !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEstimateChunks(t *testing.T) {
+	tests := []struct {
+		name      string
+		duration  float64
+		target    int
+		maxChunks int
+		want      int
+	}{
+		{"shorter than target", 30, 45, 0, 1},
+		{"equal to target", 45, 45, 0, 1},
+		{"matches fixed split points", 300, 10, 0, 30},
+		{"tail under a second is not a chunk", 100.5, 50, 0, 2},
+		{"capped", 300, 10, 4, 4},
+		{"no target", 300, 0, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EstimateChunks(tt.duration, SplitOpts{ChunkTargetSec: tt.target, MaxChunks: tt.maxChunks})
+			if got != tt.want {
+				t.Errorf("EstimateChunks() = %d, want %d", got, tt.want)
+			}
+		})
+	}
 }
 
 func TestParseSilenceOutput(t *testing.T) {
@@ -475,6 +605,71 @@ func TestSplitProducesPCM16LE(t *testing.T) {
 	}
 }
 
+// createStereoWAV creates a 44.1kHz stereo sine WAV, like a typical user upload.
+func createStereoWAV(t *testing.T, outputPath string, durationSec float64) {
+	t.Helper()
+	cmd := exec.Command("ffmpeg", "-y",
+		"-f", "lavfi", "-i", "sine=frequency=440:sample_rate=44100:duration="+formatDuration(durationSec),
+		"-ac", "2",
+		outputPath,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to create stereo WAV: %v\noutput: %s", err, output)
+	}
+}
+
+func TestSplitResamplesToTarget(t *testing.T) {
+	checkFFmpeg(t)
+	checkFFprobe(t)
+
+	tmpDir := t.TempDir()
+	inputPath := filepath.Join(tmpDir, "stereo.wav")
+	createStereoWAV(t, inputPath, 12)
+
+	splitter := NewFFmpegSplitter("")
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name         string
+		targetSec    int
+		rate, chans  int
+		wantRate     int
+		wantChannels int
+	}{
+		{"single chunk", 45, 16000, 1, 16000, 1},
+		{"several chunks", 5, 16000, 1, 16000, 1},
+		{"source format", 5, 0, 0, 44100, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := SplitOpts{
+				ChunkTargetSec:   tt.targetSec,
+				MinSilenceMs:     500,
+				SilenceThreshDB:  -40,
+				TargetSampleRate: tt.rate,
+				TargetChannels:   tt.chans,
+			}
+			chunks, err := splitter.Split(ctx, inputPath, filepath.Join(tmpDir, tt.name), opts)
+			if err != nil {
+				t.Fatalf("Split failed: %v", err)
+			}
+
+			for i, chunk := range chunks {
+				info, err := splitter.ValidateChunk(ctx, chunk)
+				if err != nil {
+					t.Fatalf("chunk %d validation failed: %v", i, err)
+				}
+				if info.SampleRate != tt.wantRate || info.Channels != tt.wantChannels {
+					t.Errorf("chunk %d: expected %d Hz with %d channels, got %d Hz with %d channels",
+						i, tt.wantRate, tt.wantChannels, info.SampleRate, info.Channels)
+				}
+			}
+		})
+	}
+}
+
 func TestParseFFprobeOutput(t *testing.T) {
 	// Sample ffprobe JSON output
 	output := `{
@@ -19,15 +19,49 @@ type SplitOpts struct {
 	// audio is considered silence.
 	// Default: -40 dBFS.
 	SilenceThreshDB float64
+
+	// MaxChunks caps the number of chunks produced. Once the cap is reached,
+	// the remaining audio is placed in the final chunk. Zero means no limit.
+	// Default: 100 chunks.
+	MaxChunks int
+
+	// TargetSampleRate is the sample rate in Hz the chunks are resampled to,
+	// matching what the model expects. Zero keeps the source sample rate.
+	// Default: 16000 Hz.
+	TargetSampleRate int
+
+	// TargetChannels is the number of channels the chunks are mixed to.
+	// Zero keeps the source channels.
+	// Default: 1 (mono).
+	TargetChannels int
 }
 
 // DefaultSplitOpts returns the default options for audio splitting.
 func DefaultSplitOpts() SplitOpts {
 	return SplitOpts{
-		ChunkTargetSec:  45,
-		MinSilenceMs:    500,
-		SilenceThreshDB: -40,
+		ChunkTargetSec:   45,
+		MinSilenceMs:     500,
+		SilenceThreshDB:  -40,
+		MaxChunks:        100,
+		TargetSampleRate: 16000,
+		TargetChannels:   1,
+	}
+}
+
+// EstimateChunks returns how many chunks audio of durationSec is split into
+// with opts, assuming cut points at multiples of ChunkTargetSec. Splitting at
+// silences moves the cut points but yields about the same count.
+func EstimateChunks(durationSec float64, opts SplitOpts) int {
+	if opts.ChunkTargetSec <= 0 || durationSec <= float64(opts.ChunkTargetSec) {
+		return 1
+	}
+
+	chunks := 1
+	target := float64(opts.ChunkTargetSec)
+	for t := target; t < durationSec-1 && (opts.MaxChunks <= 0 || chunks < opts.MaxChunks); t += target {
+		chunks++
 	}
+	return chunks
 }
 
 // Splitter defines the interface for splitting audio files at silence boundaries.
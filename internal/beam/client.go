@@ -7,11 +7,18 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
 	"os"
 	"time"
 )
 
+// DefaultAPIURL is the base URL of the Beam API used for task status and cancellation.
+const DefaultAPIURL = "https://api.beam.cloud/v2"
+
+// DefaultMaxBackoff is the longest delay between retries when no maximum is configured.
+const DefaultMaxBackoff = 30 * time.Second
+
 // Static errors for Beam client operations.
 var (
 	// ErrQueueURLRequired is returned when the queue URL is not provided.
@@ -41,20 +48,35 @@ type Client interface {
 	// Submit sends a lip-sync task to Beam and returns the task ID.
 	Submit(ctx context.Context, imageB64, audioB64 string, opts SubmitOptions) (taskID string, err error)
 
+	// SubmitByURL sends a lip-sync task whose image and audio Beam downloads
+	// from the given URLs, and returns the task ID.
+	SubmitByURL(ctx context.Context, imageURL, audioURL string, opts SubmitOptions) (taskID string, err error)
+
 	// Poll checks the status of a task and returns the result.
 	Poll(ctx context.Context, taskID string) (PollResult, error)
 
 	// DownloadOutput downloads the video from the output URL to the specified path.
 	DownloadOutput(ctx context.Context, outputURL, destPath string) error
+
+	// Cancel requests cancellation of a pending or running task.
+	Cancel(ctx context.Context, taskID string) error
+
+	// Ping checks that the Beam API is reachable and the token is accepted.
+	Ping(ctx context.Context) error
 }
 
 // HTTPClient is the HTTP implementation of the Beam Client interface.
 type HTTPClient struct {
 	token       string
 	queueURL    string
+	apiURL      string
 	httpClient  *http.Client
 	maxRetries  int
 	baseBackoff time.Duration
+	// maxBackoff caps the delay between retries before jitter is applied.
+	maxBackoff time.Duration
+	// jitter randomizes a retry delay; replaced in tests.
+	jitter func(time.Duration) time.Duration
 }
 
 // ClientOption is a function that configures an HTTPClient.
@@ -74,6 +96,13 @@ func WithHTTPClient(c *http.Client) ClientOption {
 	}
 }
 
+// WithAPIURL sets a custom base URL for the Beam task API.
+func WithAPIURL(url string) ClientOption {
+	return func(hc *HTTPClient) {
+		hc.apiURL = url
+	}
+}
+
 // WithMaxRetries sets the maximum number of retries for transient failures.
 func WithMaxRetries(n int) ClientOption {
 	return func(hc *HTTPClient) {
@@ -88,6 +117,16 @@ func WithBaseBackoff(d time.Duration) ClientOption {
 	}
 }
 
+// WithMaxBackoff caps the backoff between retries, which otherwise doubles
+// on every attempt. Values <= 0 keep DefaultMaxBackoff.
+func WithMaxBackoff(d time.Duration) ClientOption {
+	return func(hc *HTTPClient) {
+		if d > 0 {
+			hc.maxBackoff = d
+		}
+	}
+}
+
 // NewClient creates a new Beam HTTP client.
 // The token can be set via the WithToken option. If not provided,
 // it is read from the environment variable BEAM_TOKEN.
@@ -99,9 +138,12 @@ func NewClient(queueURL string, opts ...ClientOption) (*HTTPClient, error) {
 
 	c := &HTTPClient{
 		queueURL:    queueURL,
+		apiURL:      DefaultAPIURL,
 		httpClient:  &http.Client{Timeout: 30 * time.Second},
 		maxRetries:  3,
 		baseBackoff: 1 * time.Second,
+		maxBackoff:  DefaultMaxBackoff,
+		jitter:      equalJitter,
 	}
 
 	// Apply options first to allow WithToken to set the token
@@ -123,15 +165,31 @@ func NewClient(queueURL string, opts ...ClientOption) (*HTTPClient, error) {
 
 // Submit sends a lip-sync task to Beam and returns the task ID.
 func (c *HTTPClient) Submit(ctx context.Context, imageB64, audioB64 string, opts SubmitOptions) (string, error) {
-	reqBody := taskRequest{
+	return c.submit(ctx, taskRequest{
 		Prompt:       opts.Prompt,
 		Width:        opts.Width,
 		Height:       opts.Height,
 		ImageBase64:  imageB64,
 		WavBase64:    audioB64,
 		ForceOffload: &opts.ForceOffload,
-	}
+	})
+}
+
+// SubmitByURL sends a lip-sync task whose inputs Beam downloads from
+// imageURL and audioURL, avoiding base64 payloads for large media.
+func (c *HTTPClient) SubmitByURL(ctx context.Context, imageURL, audioURL string, opts SubmitOptions) (string, error) {
+	return c.submit(ctx, taskRequest{
+		Prompt:       opts.Prompt,
+		Width:        opts.Width,
+		Height:       opts.Height,
+		ImageURL:     imageURL,
+		WavURL:       audioURL,
+		ForceOffload: &opts.ForceOffload,
+	})
+}
 
+// submit posts a task request to the queue and returns the task ID.
+func (c *HTTPClient) submit(ctx context.Context, reqBody taskRequest) (string, error) {
 	bodyBytes, err := json.Marshal(reqBody)
 	if err != nil {
 		return "", fmt.Errorf("beam: marshal request: %w", err)
@@ -158,7 +216,7 @@ func (c *HTTPClient) Poll(ctx context.Context, taskID string) (PollResult, error
 		return PollResult{}, ErrTaskIDRequired
 	}
 
-	url := fmt.Sprintf("https://api.beam.cloud/v2/task/%s/", taskID)
+	url := fmt.Sprintf("%s/task/%s/", c.apiURL, taskID)
 
 	var resp statusResponse
 	if err := c.doRequestWithRetry(ctx, http.MethodGet, url, nil, &resp); err != nil {
@@ -248,18 +306,43 @@ func (c *HTTPClient) DownloadOutput(ctx context.Context, outputURL, destPath str
 	return nil
 }
 
+// Cancel requests cancellation of a pending or running task.
+// Cancellation is best-effort: Beam may take time to stop the container.
+func (c *HTTPClient) Cancel(ctx context.Context, taskID string) error {
+	if taskID == "" {
+		return ErrTaskIDRequired
+	}
+
+	bodyBytes, err := json.Marshal(cancelRequest{TaskIDs: []string{taskID}})
+	if err != nil {
+		return fmt.Errorf("beam: marshal cancel request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/task/cancel/", c.apiURL)
+	return c.doRequestWithRetry(ctx, http.MethodPost, url, bodyBytes, nil)
+}
+
+// Ping lists a single task of the workspace, the cheapest authenticated call
+// of the task API. It makes a single attempt so that a readiness probe
+// reflects the current state instead of waiting out the retries.
+func (c *HTTPClient) Ping(ctx context.Context) error {
+	url := fmt.Sprintf("%s/task/?limit=1", c.apiURL)
+	if err := c.doRequest(ctx, http.MethodGet, url, nil, nil); err != nil {
+		return fmt.Errorf("beam: health check: %w", err)
+	}
+	return nil
+}
+
 // doRequestWithRetry performs an HTTP request with exponential backoff retry.
 func (c *HTTPClient) doRequestWithRetry(ctx context.Context, method, url string, body []byte, result interface{}) error {
 	var lastErr error
-	backoff := c.baseBackoff
 
 	for attempt := 0; attempt <= c.maxRetries; attempt++ {
 		if attempt > 0 {
 			select {
 			case <-ctx.Done():
 				return fmt.Errorf("beam: context cancelled: %w", ctx.Err())
-			case <-time.After(backoff):
-				backoff *= 2 // Exponential backoff
+			case <-time.After(c.retryDelay(attempt)):
 			}
 		}
 
@@ -279,6 +362,27 @@ func (c *HTTPClient) doRequestWithRetry(ctx context.Context, method, url string,
 	return fmt.Errorf("beam: max retries exceeded: %w", lastErr)
 }
 
+// retryDelay returns how long to wait before retry attempt (1-based): the
+// base backoff doubled for every earlier retry, capped at the maximum backoff
+// and jittered so that requests failing together do not retry in lockstep.
+func (c *HTTPClient) retryDelay(attempt int) time.Duration {
+	delay := c.baseBackoff
+	for i := 1; i < attempt && delay < c.maxBackoff; i++ {
+		delay *= 2
+	}
+	return c.jitter(min(delay, c.maxBackoff))
+}
+
+// equalJitter returns a random duration between d/2 and d, which keeps
+// retries spread out while still backing off by at least half of d.
+func equalJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half+1) // #nosec G404 - jitter does not need a secure source
+}
+
 // doRequest performs a single HTTP request.
 func (c *HTTPClient) doRequest(ctx context.Context, method, url string, body []byte, result interface{}) error {
 	var bodyReader io.Reader
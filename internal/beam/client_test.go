@@ -6,7 +6,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -80,6 +82,39 @@ func TestHTTPClient_Submit(t *testing.T) {
 	assert.Equal(t, "task-123", taskID)
 }
 
+func TestHTTPClient_SubmitByURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		assert.Equal(t, "https://bucket/image.png", body["image_url"])
+		assert.Equal(t, "https://bucket/chunk.wav", body["wav_url"])
+		assert.NotContains(t, body, "image_base64")
+		assert.NotContains(t, body, "wav_base64")
+		assert.Equal(t, "test prompt", body["prompt"])
+		assert.Equal(t, true, body["force_offload"])
+
+		json.NewEncoder(w).Encode(taskResponse{TaskID: "task-123", Status: "PENDING"})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, WithToken("test-token"))
+	require.NoError(t, err)
+
+	taskID, err := client.SubmitByURL(context.Background(), "https://bucket/image.png", "https://bucket/chunk.wav", SubmitOptions{
+		Prompt:       "test prompt",
+		Width:        512,
+		Height:       512,
+		ForceOffload: true,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "task-123", taskID)
+}
+
 func TestHTTPClient_Submit_Error(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
@@ -242,6 +277,101 @@ func TestHTTPClient_Poll_EmptyTaskID(t *testing.T) {
 	assert.ErrorIs(t, err, ErrTaskIDRequired)
 }
 
+func TestHTTPClient_Poll_APIURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/task/task-123/", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		json.NewEncoder(w).Encode(statusResponse{TaskID: "task-123", Status: "RUNNING"})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("https://queue.url", WithToken("test-token"), WithAPIURL(server.URL))
+	require.NoError(t, err)
+
+	result, err := client.Poll(context.Background(), "task-123")
+	require.NoError(t, err)
+	assert.Equal(t, StatusRunning, result.Status)
+}
+
+func TestHTTPClient_Cancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/task/cancel/", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var req cancelRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, []string{"task-123"}, req.TaskIDs)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("https://queue.url", WithToken("test-token"), WithAPIURL(server.URL))
+	require.NoError(t, err)
+
+	err = client.Cancel(context.Background(), "task-123")
+	require.NoError(t, err)
+}
+
+func TestHTTPClient_Cancel_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("https://queue.url", WithToken("test-token"), WithAPIURL(server.URL))
+	require.NoError(t, err)
+
+	err = client.Cancel(context.Background(), "task-123")
+	assert.ErrorIs(t, err, ErrRequestFailed)
+}
+
+func TestHTTPClient_Ping(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/task/", r.URL.Path)
+		assert.Equal(t, "1", r.URL.Query().Get("limit"))
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("https://queue.url", WithToken("test-token"), WithAPIURL(server.URL))
+	require.NoError(t, err)
+
+	require.NoError(t, client.Ping(context.Background()))
+}
+
+func TestHTTPClient_Ping_Unhealthy(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("https://queue.url", WithToken("test-token"), WithAPIURL(server.URL), WithBaseBackoff(time.Millisecond))
+	require.NoError(t, err)
+
+	err = client.Ping(context.Background())
+	assert.ErrorIs(t, err, ErrServerError)
+	// A ping is not retried
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestHTTPClient_Cancel_EmptyTaskID(t *testing.T) {
+	client, err := NewClient("https://queue.url", WithToken("token"))
+	require.NoError(t, err)
+
+	err = client.Cancel(context.Background(), "")
+	assert.ErrorIs(t, err, ErrTaskIDRequired)
+}
+
 func TestHTTPClient_DownloadOutput(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("video content"))
@@ -297,3 +427,47 @@ func TestDefaultSubmitOptions(t *testing.T) {
 	assert.Equal(t, 540, opts.Height)
 	assert.True(t, opts.ForceOffload)
 }
+
+func TestRetryDelay_DoublesUpToMaxBackoff(t *testing.T) {
+	client, err := NewClient("https://queue.url",
+		WithToken("token"),
+		WithBaseBackoff(100*time.Millisecond),
+		WithMaxBackoff(time.Second),
+	)
+	require.NoError(t, err)
+	client.jitter = func(d time.Duration) time.Duration { return d }
+
+	want := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+	for i, w := range want {
+		assert.Equal(t, w, client.retryDelay(i+1), "attempt %d", i+1)
+	}
+	assert.Equal(t, time.Second, client.retryDelay(100), "many retries must not overflow past the cap")
+}
+
+func TestRetryDelay_JitterStaysWithinBounds(t *testing.T) {
+	client, err := NewClient("https://queue.url",
+		WithToken("token"),
+		WithBaseBackoff(100*time.Millisecond),
+		WithMaxBackoff(time.Second),
+	)
+	require.NoError(t, err)
+
+	for attempt := 1; attempt <= 6; attempt++ {
+		ceiling := min(100*time.Millisecond<<(attempt-1), time.Second)
+		seen := make(map[time.Duration]bool)
+		for range 200 {
+			got := client.retryDelay(attempt)
+			require.GreaterOrEqual(t, got, ceiling/2, "attempt %d", attempt)
+			require.LessOrEqual(t, got, ceiling, "attempt %d", attempt)
+			seen[got] = true
+		}
+		assert.Greater(t, len(seen), 1, "attempt %d: expected jittered delays", attempt)
+	}
+}
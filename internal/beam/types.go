@@ -70,6 +70,11 @@ type statusResponse struct {
 	Error   string       `json:"error,omitempty"`
 }
 
+// cancelRequest represents the request body for Beam's task cancel endpoint.
+type cancelRequest struct {
+	TaskIDs []string `json:"task_ids"`
+}
+
 // taskOutput represents a single output file from a Beam task.
 type taskOutput struct {
 	Name string `json:"name,omitempty"`
@@ -2,22 +2,33 @@
 package bootstrap
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"os/exec"
+	"time"
 
 	"github.com/maauso/infinitetalk-api/internal/audio"
 	"github.com/maauso/infinitetalk-api/internal/beam"
 	"github.com/maauso/infinitetalk-api/internal/config"
+	"github.com/maauso/infinitetalk-api/internal/fetch"
 	"github.com/maauso/infinitetalk-api/internal/job"
 	"github.com/maauso/infinitetalk-api/internal/media"
 	"github.com/maauso/infinitetalk-api/internal/runpod"
+	"github.com/maauso/infinitetalk-api/internal/server"
 	"github.com/maauso/infinitetalk-api/internal/storage"
+	"github.com/maauso/infinitetalk-api/internal/urlsafe"
 )
 
 // Dependencies holds all initialized dependencies for the HTTP server.
 type Dependencies struct {
 	VideoService *job.ProcessVideoService
+	// ReadinessChecks are the dependency checks served by GET /readyz.
+	ReadinessChecks []server.HealthChecker
+	// Janitor purges expired jobs and orphaned temp files. Nil when JOB_TTL_SEC is 0.
+	Janitor *job.Janitor
+	// Dispatcher processes jobs on a bounded worker pool. It must be started before use.
+	Dispatcher *job.Dispatcher
 }
 
 // NewDependencies creates and initializes all dependencies for the application.
@@ -27,16 +38,39 @@ func NewDependencies(cfg *config.Config, logger *slog.Logger) (*Dependencies, er
 	if err != nil {
 		return nil, err
 	}
+	if c, ok := store.(interface{ SetCleanupConcurrency(int) }); ok {
+		c.SetCleanupConcurrency(cfg.TempCleanupConcurrency)
+	}
 
 	// Initialize RunPod client
-	runpodClient, err := runpod.NewClient(cfg.RunPodEndpointID, runpod.WithAPIKey(cfg.RunPodAPIKey))
+	httpClient, err := runpod.NewClient(cfg.RunPodEndpointID,
+		runpod.WithAPIKey(cfg.RunPodAPIKey),
+		runpod.WithRequestTimeout(
+			time.Duration(cfg.RunPodSubmitTimeoutSec)*time.Second,
+			time.Duration(cfg.RunPodPollTimeoutSec)*time.Second,
+		),
+		runpod.WithPollCacheTTL(time.Duration(cfg.RunPodPollCacheTTLMs)*time.Millisecond),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("create RunPod client: %w", err)
 	}
+	var runpodClient runpod.Client = httpClient
+	if cfg.RunPodBreakerThreshold > 0 {
+		runpodClient = runpod.NewCircuitBreaker(httpClient,
+			runpod.WithBreakerThreshold(cfg.RunPodBreakerThreshold),
+			runpod.WithBreakerCooldown(time.Duration(cfg.RunPodBreakerCooldownSec)*time.Second),
+		)
+	}
 	// Log RunPod initialization without exposing API key
 	logger.Info("RunPod client initialized",
 		slog.String("endpoint_id", cfg.RunPodEndpointID),
 		slog.Bool("api_key_set", cfg.RunPodAPIKey != ""),
+		slog.Int("poll_interval_ms", cfg.RunPodPollIntervalMs),
+		slog.Float64("poll_backoff_factor", cfg.PollBackoffFactor),
+		slog.Int("submit_timeout_sec", cfg.RunPodSubmitTimeoutSec),
+		slog.Int("poll_timeout_sec", cfg.RunPodPollTimeoutSec),
+		slog.Int("poll_cache_ttl_ms", cfg.RunPodPollCacheTTLMs),
+		slog.Int("breaker_threshold", cfg.RunPodBreakerThreshold),
 	)
 
 	// Initialize Beam client if enabled
@@ -50,37 +84,88 @@ func NewDependencies(cfg *config.Config, logger *slog.Logger) (*Dependencies, er
 			slog.String("queue_url", cfg.BeamQueueURL),
 			slog.Int("poll_interval_ms", cfg.BeamPollIntervalMs),
 			slog.Int("poll_timeout_sec", cfg.BeamPollTimeoutSec),
+			slog.Bool("submit_by_url", cfg.SubmitByURLEnabled()),
 		)
 	} else {
 		logger.Info("Beam provider disabled")
 	}
 
 	// Initialize media processor and audio splitter
-	processor := media.NewFFmpegProcessor("")
+	encodeOpts := media.EncodeOptions{
+		VideoCodec:   cfg.VideoCodec,
+		Preset:       cfg.VideoPreset,
+		CRF:          &cfg.VideoCRF,
+		AudioCodec:   cfg.AudioCodec,
+		AudioBitrate: cfg.AudioBitrate,
+		HWAccel:      cfg.VideoHWAccel,
+	}
+	processor := media.NewFFmpegProcessorWithOptions("", encodeOpts)
+
+	// Fall back to software encoding if the hardware encoder is unknown or unavailable
+	if hwErr := processor.CheckHWAccel(context.Background()); hwErr != nil {
+		logger.Warn("hardware encoding unavailable; falling back to software",
+			slog.String("hwaccel", cfg.VideoHWAccel),
+			slog.String("error", hwErr.Error()),
+		)
+		encodeOpts.HWAccel = ""
+		processor = media.NewFFmpegProcessorWithOptions("", encodeOpts)
+	}
+	if err := processor.SetPadColor(cfg.ImagePadColor); err != nil {
+		return nil, fmt.Errorf("configure IMAGE_PAD_COLOR: %w", err)
+	}
+	if err := processor.SetConcatStrategy(media.ConcatStrategy(cfg.VideoConcatStrategy)); err != nil {
+		return nil, fmt.Errorf("configure VIDEO_CONCAT_STRATEGY: %w", err)
+	}
 	splitter := audio.NewFFmpegSplitter("")
 
-	// Check for ffmpeg binary availability and log processor details
-	if ffPath, ffErr := exec.LookPath("ffmpeg"); ffErr != nil {
-		logger.Warn("ffmpeg not found in PATH; processor may fail",
-			slog.String("ffmpeg_path", "not_found"),
+	// Fail fast when ffmpeg is missing or too old, since every job would fail
+	ffVersion, ffErr := media.CheckFFmpeg("")
+	if ffErr != nil {
+		if !cfg.AllowMissingFFmpeg {
+			return nil, fmt.Errorf("check ffmpeg (set ALLOW_MISSING_FFMPEG=true to start anyway): %w", ffErr)
+		}
+		logger.Warn("ffmpeg is missing or unsupported; jobs will fail",
+			slog.String("ffmpeg_version", ffVersion),
+			slog.String("error", ffErr.Error()),
 		)
 	} else {
 		logger.Info("media processor initialized",
-			slog.String("ffmpeg_path", ffPath),
+			slog.String("ffmpeg_version", ffVersion),
+			slog.String("video_codec", cfg.VideoCodec),
+			slog.Int("video_crf", cfg.VideoCRF),
+			slog.String("hwaccel", encodeOpts.HWAccel),
+			slog.String("concat_strategy", cfg.VideoConcatStrategy),
 		)
 	}
 	logger.Info("audio splitter initialized")
 
 	// Initialize job repository
-	repo := job.NewMemoryRepository()
+	repo := job.NewMemoryRepositoryWithCap(cfg.MaxStoredJobs)
 
 	// Configure audio split options
 	splitOpts := audio.SplitOpts{
-		ChunkTargetSec:  cfg.ChunkTargetSec,
-		MinSilenceMs:    500,
-		SilenceThreshDB: -40,
+		ChunkTargetSec:   cfg.ChunkTargetSec,
+		MinSilenceMs:     cfg.MinSilenceMs,
+		SilenceThreshDB:  cfg.SilenceThreshDB,
+		MaxChunks:        cfg.MaxChunks,
+		TargetSampleRate: cfg.AudioSampleRate,
+		TargetChannels:   cfg.AudioChannels,
 	}
 
+	// Initialize the URL input fetcher, shared across jobs to bound concurrent downloads.
+	// The guard keeps client-supplied URLs from reaching internal addresses
+	urlGuard := urlsafe.New(
+		urlsafe.WithAllowedHosts(cfg.FetchAllowedHosts...),
+		urlsafe.WithAllowedSchemes(cfg.FetchAllowedSchemes...),
+		urlsafe.WithPrivateNetworks(cfg.FetchAllowPrivateNetworks),
+	)
+	inputFetcher := fetch.NewHTTPFetcher(
+		fetch.WithConcurrency(cfg.InputDownloadConcurrency),
+		fetch.WithTimeout(time.Duration(cfg.InputDownloadTimeoutSec)*time.Second),
+		fetch.WithMaxBytes(int64(cfg.InputDownloadMaxMB)<<20),
+		fetch.WithGuard(urlGuard),
+	)
+
 	// Initialize ProcessVideoService
 	svc := job.NewProcessVideoService(
 		repo,
@@ -91,14 +176,102 @@ func NewDependencies(cfg *config.Config, logger *slog.Logger) (*Dependencies, er
 		store,
 		logger,
 		job.WithSplitOpts(splitOpts),
+		job.WithMaxAudioDuration(time.Duration(cfg.MaxAudioDurationSec)*time.Second),
+		job.WithMaxImagePixels(cfg.MaxImagePixels),
+		job.WithPollInterval(time.Duration(cfg.RunPodPollIntervalMs)*time.Millisecond),
+		job.WithAdaptivePolling(cfg.PollBackoffFactor, time.Duration(cfg.PollMaxIntervalMs)*time.Millisecond),
+		job.WithMaxConcurrentChunks(cfg.MaxConcurrentChunks),
+		job.WithMaxGlobalConcurrency(cfg.MaxGlobalConcurrency),
+		job.WithInputFetcher(inputFetcher),
+		job.WithInMemoryResize(cfg.ImageResizeInMemory),
+		job.WithThumbnails(cfg.ThumbnailEnabled),
+		job.WithThumbnailAt(cfg.ThumbnailAtSec),
+		job.WithMaxChunkRetries(cfg.MaxChunkRetries),
+		job.WithChunkRetryBackoff(time.Duration(cfg.ChunkRetryBackoffMs)*time.Millisecond),
+		job.WithChunkTimeout(time.Duration(cfg.ChunkTimeoutSec)*time.Second),
+		job.WithMaxJoinRetries(cfg.MaxJoinRetries),
+		job.WithJoinRetryBackoff(time.Duration(cfg.JoinRetryBackoffMs)*time.Millisecond),
+		job.WithJobTTL(time.Duration(cfg.JobTTLSec)*time.Second),
+		job.WithMaxRetention(time.Duration(cfg.MaxRetentionSec)*time.Second),
+		job.WithJobLogLines(cfg.JobLogLines),
+		job.WithStuckJobThreshold(time.Duration(cfg.StuckJobThresholdSec)*time.Second),
+		job.WithKeepLocalOutput(cfg.KeepLocalOutput),
+		job.WithSubmitByURL(cfg.SubmitByURLEnabled()),
+	)
+
+	dispatcher := job.NewDispatcher(svc,
+		job.WithDispatcherWorkers(cfg.JobWorkers),
+		job.WithDispatcherQueueDepth(cfg.JobQueueDepth),
+	)
+	logger.Info("job dispatcher initialized",
+		slog.Int("workers", cfg.JobWorkers),
+		slog.Int("queue_depth", cfg.JobQueueDepth),
 	)
 
 	return &Dependencies{
-		VideoService: svc,
+		VideoService:    svc,
+		ReadinessChecks: readinessChecks(cfg, store, runpodClient, beamClient),
+		Janitor:         newJanitor(cfg, svc, store, logger),
+		Dispatcher:      dispatcher,
 	}, nil
 }
 
+// newJanitor creates the janitor that purges expired jobs. It always runs,
+// since jobs may request their own retention even when JOB_TTL_SEC is unset.
+func newJanitor(cfg *config.Config, svc *job.ProcessVideoService, store storage.Storage, logger *slog.Logger) *job.Janitor {
+	opts := []job.JanitorOption{
+		job.WithJanitorInterval(time.Duration(cfg.JanitorIntervalSec) * time.Second),
+	}
+	if t, ok := store.(interface{ TempDir() string }); ok {
+		opts = append(opts, job.WithJanitorTempDir(t.TempDir()))
+	}
+
+	logger.Info("janitor enabled",
+		slog.Int("job_ttl_sec", cfg.JobTTLSec),
+		slog.Int("max_retention_sec", cfg.MaxRetentionSec),
+		slog.Int("interval_sec", cfg.JanitorIntervalSec),
+	)
+	return job.NewJanitor(svc, time.Duration(cfg.JobTTLSec)*time.Second, opts...)
+}
+
+// readinessChecks builds the dependency checks reported by GET /readyz.
+// beamClient is nil when Beam is disabled.
+func readinessChecks(cfg *config.Config, store storage.Storage, runpodClient runpod.Client, beamClient beam.Client) []server.HealthChecker {
+	checks := []server.HealthChecker{
+		server.NewHealthCheck("ffmpeg", func(context.Context) error {
+			_, err := exec.LookPath("ffmpeg")
+			return err
+		}),
+	}
+
+	if w, ok := store.(interface{ CheckWritable(context.Context) error }); ok {
+		checks = append(checks, server.NewHealthCheck("temp_dir", w.CheckWritable))
+	}
+
+	// Pinging S3 costs a request per probe, so it is opt-in
+	if cfg.ReadinessCheckS3 {
+		if p, ok := store.(interface{ Ping(context.Context) error }); ok {
+			checks = append(checks, server.NewHealthCheck("s3", p.Ping))
+		}
+	}
+
+	// So is calling the providers
+	if cfg.ReadinessCheckProvider {
+		checks = append(checks, server.NewHealthCheck("runpod", runpodClient.Ping))
+		if beamClient != nil {
+			checks = append(checks, server.NewHealthCheck("beam", beamClient.Ping))
+		}
+	}
+
+	return checks
+}
+
+// gcsCredentialsTimeout bounds the access token fetched at startup to check
+// the GCS credentials.
+const gcsCredentialsTimeout = 15 * time.Second
+
 // initStorage creates the appropriate storage backend based on configuration.
+// GCS credentials are checked up front so that a missing key fails startup.
 func initStorage(cfg *config.Config, logger *slog.Logger) (storage.Storage, error) {
 	if cfg.S3Enabled() {
 		s3Cfg := storage.S3Config{
@@ -106,6 +279,18 @@ func initStorage(cfg *config.Config, logger *slog.Logger) (storage.Storage, erro
 			Region:          cfg.S3Region,
 			AccessKeyID:     cfg.AWSAccessKeyID,
 			SecretAccessKey: cfg.AWSSecretAccessKey,
+
+			Endpoint:             cfg.S3Endpoint,
+			VirtualHostedStyle:   !cfg.S3ForcePathStyle,
+			AllowedEndpointHosts: cfg.S3AllowedEndpoints,
+			KeyPrefix:            cfg.S3KeyPrefix,
+
+			MultipartThreshold: int64(cfg.S3MultipartThresholdMB) << 20,
+			PartSize:           int64(cfg.S3MultipartPartSizeMB) << 20,
+			UploadConcurrency:  cfg.S3MultipartConcurrency,
+		}
+		if cfg.S3Presign {
+			s3Cfg.PresignTTL = time.Duration(cfg.S3PresignTTLSec) * time.Second
 		}
 		s3Store, err := storage.NewS3Storage(cfg.TempDir, s3Cfg)
 		if err != nil {
@@ -114,10 +299,37 @@ func initStorage(cfg *config.Config, logger *slog.Logger) (storage.Storage, erro
 		logger.Info("S3 storage configured",
 			slog.String("bucket", cfg.S3Bucket),
 			slog.String("region", cfg.S3Region),
+			slog.String("endpoint", cfg.S3Endpoint),
+			slog.String("key_prefix", cfg.S3KeyPrefix),
+			slog.Bool("presign", cfg.S3Presign),
 		)
 		return s3Store, nil
 	}
 
+	if cfg.GCSEnabled() {
+		gcsStore, err := storage.NewGCSStorage(cfg.TempDir, storage.GCSConfig{
+			Bucket:          cfg.GCSBucket,
+			Endpoint:        cfg.GCSEndpoint,
+			KeyPrefix:       cfg.GCSKeyPrefix,
+			CredentialsFile: cfg.GoogleApplicationCredentials,
+			Timeout:         time.Duration(cfg.GCSTimeoutSec) * time.Second,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create GCS storage: %w", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), gcsCredentialsTimeout)
+		defer cancel()
+		if err := gcsStore.CheckCredentials(ctx); err != nil {
+			return nil, fmt.Errorf("create GCS storage: %w", err)
+		}
+		logger.Info("GCS storage configured",
+			slog.String("bucket", cfg.GCSBucket),
+			slog.String("key_prefix", cfg.GCSKeyPrefix),
+			slog.Bool("credentials_file", cfg.GoogleApplicationCredentials != ""),
+		)
+		return gcsStore, nil
+	}
+
 	localStore, err := storage.NewLocalStorage(cfg.TempDir)
 	if err != nil {
 		return nil, fmt.Errorf("create local storage: %w", err)
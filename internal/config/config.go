@@ -1,4 +1,5 @@
-// Package config provides configuration loading from environment variables.
+// Package config provides configuration loading from environment variables
+// and, optionally, a YAML or JSON config file.
 package config
 
 import (
@@ -6,7 +7,9 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/sethvargo/go-envconfig"
@@ -18,28 +21,176 @@ var (
 	ErrRunPodAPIKeyRequired = errors.New("config: RUNPOD_API_KEY is required")
 	// ErrRunPodEndpointIDRequired is returned when RUNPOD_ENDPOINT_ID is not set.
 	ErrRunPodEndpointIDRequired = errors.New("config: RUNPOD_ENDPOINT_ID is required")
+	// ErrAdminPortConflict is returned when ADMIN_PORT is the same as PORT.
+	ErrAdminPortConflict = errors.New("config: ADMIN_PORT must differ from PORT")
+	// ErrInvalidPresignTTL is returned when S3_PRESIGN is set and S3_PRESIGN_TTL_SEC is outside 1s..7d.
+	ErrInvalidPresignTTL = errors.New("config: S3_PRESIGN_TTL_SEC must be between 1 and 604800")
+	// ErrInvalidMultipartConfig is returned when S3 is enabled and the multipart settings are out of range.
+	ErrInvalidMultipartConfig = errors.New("config: S3_MULTIPART_PART_SIZE_MB must be at least 5 and S3_MULTIPART_THRESHOLD_MB and S3_MULTIPART_CONCURRENCY at least 1")
+	// ErrMultipleStorageBackends is returned when both S3 and GCS are configured.
+	ErrMultipleStorageBackends = errors.New("config: S3_BUCKET and GCS_BUCKET are mutually exclusive")
+	// ErrInvalidS3Endpoint is returned when S3_ENDPOINT is not an http or https URL.
+	ErrInvalidS3Endpoint = errors.New("config: S3_ENDPOINT must be an http or https URL")
+	// ErrInvalidAllowedDimensions is returned when an ALLOWED_DIMENSIONS entry is not WxH.
+	ErrInvalidAllowedDimensions = errors.New("config: ALLOWED_DIMENSIONS entries must be WxH with positive integers, e.g. 384x576")
+	// ErrInvalidFetchSchemes is returned when a FETCH_ALLOWED_SCHEMES entry is not http or https.
+	ErrInvalidFetchSchemes = errors.New("config: FETCH_ALLOWED_SCHEMES entries must be http or https")
+	// ErrInvalidVideoCRF is returned when VIDEO_CRF is outside ffmpeg's CRF range.
+	ErrInvalidVideoCRF = errors.New("config: VIDEO_CRF must be between 0 and 51")
 )
 
+// minPartSizeMB is the smallest part size S3 accepts for all but the last part.
+const minPartSizeMB = 5
+
+// maxPresignTTLSec is the longest expiry SigV4 presigned URLs support (7 days).
+const maxPresignTTLSec = 7 * 24 * 60 * 60
+
+// maxVideoCRF is the highest constant rate factor x264 and x265 accept.
+const maxVideoCRF = 51
+
 // Config holds all configuration for the application.
 type Config struct {
 	// Server settings
 	Port int `env:"PORT, default=8080" json:"port"`
+	// AdminPort serves /health, /livez, /readyz and /metrics on a separate listener when set
+	AdminPort int `env:"ADMIN_PORT, default=0" json:"admin_port"` // 0 keeps them on PORT
+	// AdminAPIKey enables the /admin/jobs endpoints and is required to call them
+	AdminAPIKey string `env:"ADMIN_API_KEY" json:"-"` // Masked in JSON
+	// ReadinessCheckS3 makes GET /readyz ping the S3 bucket (one request per probe)
+	ReadinessCheckS3 bool `env:"READINESS_CHECK_S3, default=false" json:"readiness_check_s3"`
+	// ReadinessCheckProvider makes GET /readyz ping RunPod and, when enabled, Beam (one API call each per probe)
+	ReadinessCheckProvider bool `env:"READINESS_CHECK_PROVIDER, default=false" json:"readiness_check_provider"`
+	// VideoReadBudgetSec bounds reading and encoding the output video in GET /jobs/{id}
+	VideoReadBudgetSec int `env:"VIDEO_READ_BUDGET_SEC, default=30" json:"video_read_budget_sec"` // 0 disables the budget
+	// MaxRequestBytes caps request bodies such as the base64 payload of POST /jobs
+	MaxRequestBytes int64 `env:"MAX_REQUEST_BYTES, default=52428800" json:"max_request_bytes"` // 50MB, 0 disables the limit
+	// InputTypeCheck rejects jobs whose image/audio inputs do not sniff as an image/audio (e.g. swapped inputs)
+	InputTypeCheck bool `env:"INPUT_TYPE_CHECK, default=true" json:"input_type_check"`
+	// DimensionMultiple is the factor the width and height of new jobs must be divisible by
+	DimensionMultiple int `env:"DIMENSION_MULTIPLE, default=16" json:"dimension_multiple"` // 0 or 1 disables the check
+	// AllowedDimensions restricts the width and height of new jobs to these WxH presets (comma-separated)
+	AllowedDimensions []string `env:"ALLOWED_DIMENSIONS" json:"allowed_dimensions,omitempty"` // empty allows any size
+	// IdempotencyTTLSec is how long Idempotency-Key headers on POST /jobs are remembered
+	IdempotencyTTLSec int `env:"IDEMPOTENCY_TTL_SEC, default=86400" json:"idempotency_ttl_sec"` // 0 disables idempotency keys
+	// RequestTimeoutSec bounds how long API handlers may take; streaming the video is exempt
+	RequestTimeoutSec int `env:"REQUEST_TIMEOUT_SEC, default=60" json:"request_timeout_sec"` // 0 disables the timeout
+	// GzipMinBytes is the smallest response gzipped for clients sending Accept-Encoding: gzip
+	GzipMinBytes int `env:"GZIP_MIN_BYTES, default=1024" json:"gzip_min_bytes"` // 0 disables response compression
+	// ResultCompression gzips GET /jobs/{id} responses carrying an inline video when the client accepts it
+	ResultCompression bool `env:"RESULT_COMPRESSION_ENABLED, default=true" json:"result_compression"`
+	// ShutdownDrainSec is how long shutdown waits for jobs in progress before marking them TIMED_OUT
+	ShutdownDrainSec int `env:"SHUTDOWN_DRAIN_SEC, default=30" json:"shutdown_drain_sec"`
 
 	// RunPod settings
-	RunPodAPIKey     string `env:"RUNPOD_API_KEY, required" json:"-"` // Masked in JSON
-	RunPodEndpointID string `env:"RUNPOD_ENDPOINT_ID, required" json:"runpod_endpoint_id"`
+	RunPodAPIKey         string `env:"RUNPOD_API_KEY, required" json:"-"` // Masked in JSON
+	RunPodEndpointID     string `env:"RUNPOD_ENDPOINT_ID, required" json:"runpod_endpoint_id"`
+	RunPodPollIntervalMs int    `env:"RUNPOD_POLL_INTERVAL_MS, default=5000" json:"runpod_poll_interval_ms"` // Default 5s
+	// PollBackoffFactor multiplies the poll interval while a provider job is queued; it resets once running
+	PollBackoffFactor float64 `env:"POLL_BACKOFF_FACTOR, default=1" json:"poll_backoff_factor"` // 1 keeps a fixed interval
+	// PollMaxIntervalMs caps the poll interval reached by POLL_BACKOFF_FACTOR
+	PollMaxIntervalMs int `env:"POLL_MAX_INTERVAL_MS, default=60000" json:"poll_max_interval_ms"`
+	// Per-request RunPod timeouts; submits upload the inputs and need longer than status polls
+	RunPodSubmitTimeoutSec int `env:"RUNPOD_SUBMIT_TIMEOUT_SEC, default=120" json:"runpod_submit_timeout_sec"`
+	RunPodPollTimeoutSec   int `env:"RUNPOD_POLL_TIMEOUT_SEC, default=30" json:"runpod_poll_timeout_sec"`
+	// RunPodPollCacheTTLMs lets status polls of the same RunPod job within this window share one request
+	RunPodPollCacheTTLMs int `env:"RUNPOD_POLL_CACHE_TTL_MS, default=0" json:"runpod_poll_cache_ttl_ms"` // 0 disables the cache
+	// RunPod circuit breaker; opens after this many consecutive provider failures (0 disables it)
+	RunPodBreakerThreshold   int `env:"RUNPOD_BREAKER_THRESHOLD, default=5" json:"runpod_breaker_threshold"`
+	RunPodBreakerCooldownSec int `env:"RUNPOD_BREAKER_COOLDOWN_SEC, default=30" json:"runpod_breaker_cooldown_sec"`
 
 	// Beam settings (optional)
 	BeamToken          string `env:"BEAM_TOKEN" json:"-"`                               // Masked in JSON
 	BeamQueueURL       string `env:"BEAM_QUEUE_URL" json:"beam_queue_url,omitempty"`   // Task queue webhook URL
 	BeamPollIntervalMs int    `env:"BEAM_POLL_INTERVAL_MS, default=5000" json:"beam_poll_interval_ms"` // Default 5s
 	BeamPollTimeoutSec int    `env:"BEAM_POLL_TIMEOUT_SEC, default=600" json:"beam_poll_timeout_sec"`  // Default 10min
+	// BeamSubmitByURL uploads Beam inputs to remote storage and sends their URLs instead of base64.
+	// Opt-in, as the bucket must be readable by Beam; presigned S3 URLs enable it anyway.
+	BeamSubmitByURL bool `env:"BEAM_SUBMIT_BY_URL, default=false" json:"beam_submit_by_url"` // Needs S3 or GCS
+
+	// Provider warmup at startup (opt-in, reduces cold starts)
+	Prewarm           bool `env:"PREWARM, default=false" json:"prewarm"`
+	PrewarmTimeoutSec int  `env:"PREWARM_TIMEOUT_SEC, default=600" json:"prewarm_timeout_sec"`
 
 	// Storage settings
 	TempDir string `env:"TEMP_DIR, default=/tmp/infinitetalk" json:"temp_dir"`
+	// TempCleanupConcurrency is how many temp files of a job are removed at once
+	TempCleanupConcurrency int `env:"TEMP_CLEANUP_CONCURRENCY, default=1" json:"temp_cleanup_concurrency"` // 1 removes them one at a time
+
+	// JobTTLSec is how long after creation job results are retained; reported as expires_at
+	JobTTLSec int `env:"JOB_TTL_SEC, default=0" json:"job_ttl_sec"` // 0 disables expiry
+	// MaxRetentionSec bounds the retention_sec a job may request instead of JobTTLSec
+	MaxRetentionSec int `env:"MAX_RETENTION_SEC, default=604800" json:"max_retention_sec"` // 0 = no limit
+	// JanitorIntervalSec is how often expired jobs and orphaned temp files are purged
+	JanitorIntervalSec int `env:"JANITOR_INTERVAL_SEC, default=300" json:"janitor_interval_sec"`
+	// MaxStoredJobs caps the jobs kept in memory; the oldest finished jobs are evicted first
+	MaxStoredJobs int `env:"MAX_STORED_JOBS, default=0" json:"max_stored_jobs"` // 0 = unbounded
+	// JobLogLines is how many log lines are kept per job for GET /jobs/{id}/logs
+	JobLogLines int `env:"JOB_LOG_LINES, default=500" json:"job_log_lines"` // 0 disables capture
+	// StuckJobThresholdSec is how long a RUNNING job may go without an update before it is reported as stuck
+	StuckJobThresholdSec int `env:"STUCK_JOB_THRESHOLD_SEC, default=1800" json:"stuck_job_threshold_sec"`
+	// KeepLocalOutput keeps the local output video of jobs pushed to remote storage
+	KeepLocalOutput bool `env:"KEEP_LOCAL_OUTPUT, default=false" json:"keep_local_output"`
+
+	// Job worker pool; POST /jobs returns 503 once the queue is full
+	JobWorkers    int `env:"JOB_WORKERS, default=4" json:"job_workers"`
+	JobQueueDepth int `env:"JOB_QUEUE_DEPTH, default=100" json:"job_queue_depth"`
 
 	// Processing settings
-	ChunkTargetSec int `env:"CHUNK_TARGET_SEC, default=45" json:"chunk_target_sec"`
+	ChunkTargetSec      int `env:"CHUNK_TARGET_SEC, default=45" json:"chunk_target_sec"`
+	MaxChunks           int `env:"MAX_CHUNKS, default=100" json:"max_chunks"` // 0 disables the cap
+	MaxConcurrentChunks int `env:"MAX_CONCURRENT_CHUNKS, default=3" json:"max_concurrent_chunks"`
+	// MaxGlobalConcurrency bounds the chunks running at the provider across all jobs
+	MaxGlobalConcurrency int `env:"MAX_GLOBAL_CONCURRENCY, default=0" json:"max_global_concurrency"` // 0 = unlimited
+	// MaxAudioDurationSec rejects jobs with longer audio before it is split
+	MaxAudioDurationSec int `env:"MAX_AUDIO_DURATION_SEC, default=0" json:"max_audio_duration_sec"` // 0 = no limit
+	// MaxImagePixels rejects images with more pixels (width x height) before they are decoded
+	MaxImagePixels int64 `env:"MAX_IMAGE_PIXELS, default=50000000" json:"max_image_pixels"` // 0 = no limit
+	// Silence detection used to pick chunk cut points
+	MinSilenceMs    int     `env:"MIN_SILENCE_MS, default=500" json:"min_silence_ms"`
+	SilenceThreshDB float64 `env:"SILENCE_THRESH_DB, default=-40" json:"silence_thresh_db"`
+	// Audio format the chunks are resampled to, as expected by the model
+	AudioSampleRate int `env:"AUDIO_SAMPLE_RATE, default=16000" json:"audio_sample_rate"` // 0 keeps the source rate
+	AudioChannels   int `env:"AUDIO_CHANNELS, default=1" json:"audio_channels"`           // 0 keeps the source channels
+
+	// Chunk retry settings (transient provider failures)
+	MaxChunkRetries     int `env:"MAX_CHUNK_RETRIES, default=2" json:"max_chunk_retries"`              // 0 disables retries
+	ChunkRetryBackoffMs int `env:"CHUNK_RETRY_BACKOFF_MS, default=2000" json:"chunk_retry_backoff_ms"` // Doubles per retry
+	ChunkTimeoutSec     int `env:"CHUNK_TIMEOUT_SEC, default=1800" json:"chunk_timeout_sec"`           // 0 disables the timeout
+
+	// Join retry settings, applied when joining the chunk videos fails
+	MaxJoinRetries     int `env:"MAX_JOIN_RETRIES, default=2" json:"max_join_retries"`              // 0 disables retries
+	JoinRetryBackoffMs int `env:"JOIN_RETRY_BACKOFF_MS, default=1000" json:"join_retry_backoff_ms"` // Doubles per retry
+
+	// URL input download settings (shared across all jobs)
+	InputDownloadConcurrency int `env:"INPUT_DOWNLOAD_CONCURRENCY, default=4" json:"input_download_concurrency"`
+	InputDownloadTimeoutSec  int `env:"INPUT_DOWNLOAD_TIMEOUT_SEC, default=60" json:"input_download_timeout_sec"`
+	InputDownloadMaxMB       int `env:"INPUT_DOWNLOAD_MAX_MB, default=100" json:"input_download_max_mb"`
+
+	// SSRF protection for server-side fetches of client-supplied URLs. Hosts and
+	// schemes are comma-separated; empty allows any public host over http or https
+	FetchAllowedHosts         []string `env:"FETCH_ALLOWED_HOSTS" json:"fetch_allowed_hosts,omitempty"`
+	FetchAllowedSchemes       []string `env:"FETCH_ALLOWED_SCHEMES" json:"fetch_allowed_schemes,omitempty"`
+	FetchAllowPrivateNetworks bool     `env:"FETCH_ALLOW_PRIVATE_NETWORKS, default=false" json:"fetch_allow_private_networks"` // For development only
+
+	// Video encoding settings (used when joining requires re-encoding)
+	VideoCodec   string `env:"VIDEO_CODEC, default=libx264" json:"video_codec"`
+	VideoPreset  string `env:"VIDEO_PRESET, default=fast" json:"video_preset"`
+	VideoCRF     int    `env:"VIDEO_CRF, default=23" json:"video_crf"`
+	AudioCodec   string `env:"AUDIO_CODEC, default=aac" json:"audio_codec"`
+	AudioBitrate string `env:"AUDIO_BITRATE, default=128k" json:"audio_bitrate"`
+	VideoHWAccel string `env:"VIDEO_HWACCEL" json:"video_hwaccel,omitempty"` // "nvenc", "qsv", "videotoolbox" or empty
+	// VideoConcatStrategy selects how chunks are joined: "demuxer", "filter" or "auto"
+	VideoConcatStrategy string `env:"VIDEO_CONCAT_STRATEGY, default=demuxer" json:"video_concat_strategy"`
+	// AllowMissingFFmpeg starts the server even when ffmpeg is missing or too old
+	AllowMissingFFmpeg bool `env:"ALLOW_MISSING_FFMPEG, default=false" json:"allow_missing_ffmpeg"`
+
+	// Image settings
+	ImagePadColor       string `env:"IMAGE_PAD_COLOR, default=black" json:"image_pad_color"` // ffmpeg color name or #RRGGBB
+	ImageResizeInMemory bool   `env:"IMAGE_RESIZE_IN_MEMORY, default=true" json:"image_resize_in_memory"`
+
+	// Thumbnail settings
+	ThumbnailEnabled bool    `env:"THUMBNAIL_ENABLED, default=true" json:"thumbnail_enabled"`
+	ThumbnailAtSec   float64 `env:"THUMBNAIL_AT_SEC, default=0" json:"thumbnail_at_sec"` // 0 = mid-point
 
 	// Optional S3 settings
 	S3Bucket           string `env:"S3_BUCKET" json:"s3_bucket,omitempty"`
@@ -47,16 +198,62 @@ type Config struct {
 	AWSAccessKeyID     string `env:"AWS_ACCESS_KEY_ID" json:"-"`     // Masked in JSON
 	AWSSecretAccessKey string `env:"AWS_SECRET_ACCESS_KEY" json:"-"` // Masked in JSON
 
+	// S3KeyPrefix is prepended to the keys of uploaded objects
+	S3KeyPrefix string `env:"S3_KEY_PREFIX" json:"s3_key_prefix,omitempty"`
+	// S3Presign returns presigned GET URLs valid for S3PresignTTLSec instead of public URLs
+	S3Presign       bool `env:"S3_PRESIGN, default=false" json:"s3_presign"`
+	S3PresignTTLSec int  `env:"S3_PRESIGN_TTL_SEC, default=3600" json:"s3_presign_ttl_sec"` // SigV4 allows at most 7 days
+	// Uploads of at least S3MultipartThresholdMB use multipart upload with
+	// S3MultipartPartSizeMB parts, S3MultipartConcurrency of them in parallel
+	S3MultipartThresholdMB int `env:"S3_MULTIPART_THRESHOLD_MB, default=32" json:"s3_multipart_threshold_mb"`
+	S3MultipartPartSizeMB  int `env:"S3_MULTIPART_PART_SIZE_MB, default=8" json:"s3_multipart_part_size_mb"`
+	S3MultipartConcurrency int `env:"S3_MULTIPART_CONCURRENCY, default=4" json:"s3_multipart_concurrency"`
+	// S3AllowedEndpoints restricts custom S3 endpoints to these hosts (comma-separated)
+	S3AllowedEndpoints []string `env:"S3_ALLOWED_ENDPOINTS" json:"s3_allowed_endpoints,omitempty"`
+	// S3Endpoint is a custom S3-compatible endpoint, e.g. MinIO, Cloudflare R2 or Wasabi
+	S3Endpoint string `env:"S3_ENDPOINT" json:"s3_endpoint,omitempty"`
+	// S3ForcePathStyle addresses buckets on S3Endpoint as endpoint/bucket rather than bucket.endpoint
+	S3ForcePathStyle bool `env:"S3_FORCE_PATH_STYLE, default=true" json:"s3_force_path_style"`
+
+	// Optional Google Cloud Storage settings, used instead of S3
+	GCSBucket    string `env:"GCS_BUCKET" json:"gcs_bucket,omitempty"`
+	GCSKeyPrefix string `env:"GCS_KEY_PREFIX" json:"gcs_key_prefix,omitempty"`
+	GCSEndpoint  string `env:"GCS_ENDPOINT" json:"gcs_endpoint,omitempty"` // e.g. a fake-gcs-server emulator
+	// GCSTimeoutSec bounds each GCS API request, uploads included
+	GCSTimeoutSec int `env:"GCS_TIMEOUT_SEC, default=600" json:"gcs_timeout_sec"`
+	// GoogleApplicationCredentials is the path of a service account JSON key for GCS outside GCP
+	GoogleApplicationCredentials string `env:"GOOGLE_APPLICATION_CREDENTIALS" json:"google_application_credentials,omitempty"`
+
 	// Logging settings
 	LogFormat string `env:"LOG_FORMAT, default=text" json:"log_format"` // "json" or "text"
 	LogLevel  string `env:"LOG_LEVEL, default=info" json:"log_level"`   // "debug", "info", "warn", "error"
+
+	// AccessLogFormat selects the HTTP access log format
+	AccessLogFormat string `env:"ACCESS_LOG_FORMAT, default=slog" json:"access_log_format"` // "slog", "combined" or "none"
 }
 
-// S3Enabled returns true if S3 configuration is provided.
+// S3Enabled returns true if S3 configuration is provided. S3-compatible
+// services need a region too (e.g. "auto" for R2); S3Endpoint alone does not
+// enable S3.
 func (c *Config) S3Enabled() bool {
 	return c.S3Bucket != "" && c.S3Region != ""
 }
 
+// GCSEnabled returns true if Google Cloud Storage configuration is provided.
+func (c *Config) GCSEnabled() bool {
+	return c.GCSBucket != ""
+}
+
+// SubmitByURLEnabled returns true if Beam inputs are uploaded and sent by URL.
+// That needs remote storage whose URLs Beam can read: presigned S3 URLs, or
+// a public bucket the operator vouches for with BEAM_SUBMIT_BY_URL.
+func (c *Config) SubmitByURLEnabled() bool {
+	if c.S3Enabled() && c.S3Presign {
+		return true
+	}
+	return c.BeamSubmitByURL && (c.S3Enabled() || c.GCSEnabled())
+}
+
 // BeamEnabled returns true if Beam configuration is provided.
 func (c *Config) BeamEnabled() bool {
 	return c.BeamToken != "" && c.BeamQueueURL != ""
@@ -65,9 +262,17 @@ func (c *Config) BeamEnabled() bool {
 // Load reads configuration from environment variables using go-envconfig.
 // It returns an error if required variables are not set.
 func Load() (*Config, error) {
+	return load(envconfig.OsLookuper())
+}
+
+// load processes configuration from the variables returned by lookuper.
+func load(lookuper envconfig.Lookuper) (*Config, error) {
 	cfg := &Config{}
 
-	if err := envconfig.Process(context.Background(), cfg); err != nil {
+	if err := envconfig.ProcessWith(context.Background(), &envconfig.Config{
+		Target:   cfg,
+		Lookuper: lookuper,
+	}); err != nil {
 		// Map envconfig errors to our domain errors for required fields
 		if strings.Contains(err.Error(), "RUNPOD_API_KEY") {
 			return nil, ErrRunPodAPIKeyRequired
@@ -89,9 +294,58 @@ func (c *Config) Validate() error {
 	if c.RunPodEndpointID == "" {
 		return ErrRunPodEndpointIDRequired
 	}
+	if c.AdminPort != 0 && c.AdminPort == c.Port {
+		return ErrAdminPortConflict
+	}
+	if c.S3Presign && (c.S3PresignTTLSec < 1 || c.S3PresignTTLSec > maxPresignTTLSec) {
+		return ErrInvalidPresignTTL
+	}
+	if c.S3Enabled() && (c.S3MultipartPartSizeMB < minPartSizeMB || c.S3MultipartThresholdMB < 1 || c.S3MultipartConcurrency < 1) {
+		return ErrInvalidMultipartConfig
+	}
+	if c.S3Enabled() && c.GCSEnabled() {
+		return ErrMultipleStorageBackends
+	}
+	if c.S3Endpoint != "" {
+		u, err := url.Parse(c.S3Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidS3Endpoint
+		}
+	}
+	if c.VideoCRF < 0 || c.VideoCRF > maxVideoCRF {
+		return ErrInvalidVideoCRF
+	}
+	for _, preset := range c.AllowedDimensions {
+		if !isDimensionPreset(preset) {
+			return fmt.Errorf("%w: %q", ErrInvalidAllowedDimensions, preset)
+		}
+	}
+	for _, scheme := range c.FetchAllowedSchemes {
+		if s := strings.ToLower(strings.TrimSpace(scheme)); s != "http" && s != "https" {
+			return fmt.Errorf("%w: %q", ErrInvalidFetchSchemes, scheme)
+		}
+	}
 	return nil
 }
 
+// isDimensionPreset reports whether s is written as WxH, e.g. 384x576, with
+// positive integers and no leading zeros, so it can be compared as a string.
+func isDimensionPreset(s string) bool {
+	w, h, ok := strings.Cut(s, "x")
+	if !ok {
+		return false
+	}
+	width, err := strconv.Atoi(w)
+	if err != nil || width < 1 {
+		return false
+	}
+	height, err := strconv.Atoi(h)
+	if err != nil || height < 1 {
+		return false
+	}
+	return s == strconv.Itoa(width)+"x"+strconv.Itoa(height)
+}
+
 // NewLogger creates a structured logger based on the configuration.
 // When LogFormat is "json", it outputs JSON logs suitable for production.
 // Otherwise, it outputs human-readable text logs.
@@ -65,36 +65,280 @@ func TestLoad_Defaults(t *testing.T) {
 
 	assert.Equal(t, 8080, cfg.Port)
 	assert.Equal(t, "/tmp/infinitetalk", cfg.TempDir)
+	assert.Equal(t, 1, cfg.TempCleanupConcurrency)
+	assert.Equal(t, 0, cfg.JobTTLSec)
+	assert.Equal(t, 604800, cfg.MaxRetentionSec)
+	assert.Equal(t, 0, cfg.MaxStoredJobs)
+	assert.Equal(t, 500, cfg.JobLogLines)
+	assert.Equal(t, 1800, cfg.StuckJobThresholdSec)
+	assert.False(t, cfg.KeepLocalOutput)
+	assert.Equal(t, 300, cfg.JanitorIntervalSec)
 	assert.Equal(t, 45, cfg.ChunkTargetSec)
+	assert.Equal(t, 100, cfg.MaxChunks)
+	assert.Equal(t, 0, cfg.MaxAudioDurationSec)
+	assert.Equal(t, int64(50000000), cfg.MaxImagePixels)
+	assert.Equal(t, 3, cfg.MaxConcurrentChunks)
+	assert.Equal(t, 0, cfg.MaxGlobalConcurrency)
+	assert.Equal(t, 4, cfg.JobWorkers)
+	assert.Equal(t, 100, cfg.JobQueueDepth)
+	assert.Equal(t, 5000, cfg.RunPodPollIntervalMs)
+	assert.Equal(t, 1.0, cfg.PollBackoffFactor)
+	assert.Equal(t, 60000, cfg.PollMaxIntervalMs)
+	assert.Equal(t, 120, cfg.RunPodSubmitTimeoutSec)
+	assert.Equal(t, 30, cfg.RunPodPollTimeoutSec)
+	assert.Equal(t, 0, cfg.RunPodPollCacheTTLMs)
+	assert.Equal(t, 5, cfg.RunPodBreakerThreshold)
+	assert.Equal(t, 30, cfg.RunPodBreakerCooldownSec)
+	assert.Equal(t, 500, cfg.MinSilenceMs)
+	assert.Equal(t, -40.0, cfg.SilenceThreshDB)
+	assert.Equal(t, 16000, cfg.AudioSampleRate)
+	assert.Equal(t, 1, cfg.AudioChannels)
+	assert.Equal(t, 2, cfg.MaxChunkRetries)
+	assert.Equal(t, 2000, cfg.ChunkRetryBackoffMs)
+	assert.Equal(t, 1800, cfg.ChunkTimeoutSec)
+	assert.Equal(t, 2, cfg.MaxJoinRetries)
+	assert.Equal(t, 1000, cfg.JoinRetryBackoffMs)
+	assert.False(t, cfg.Prewarm)
+	assert.Equal(t, 600, cfg.PrewarmTimeoutSec)
+	assert.Equal(t, 4, cfg.InputDownloadConcurrency)
+	assert.Equal(t, 60, cfg.InputDownloadTimeoutSec)
+	assert.Equal(t, 100, cfg.InputDownloadMaxMB)
+	assert.Empty(t, cfg.FetchAllowedHosts)
+	assert.Empty(t, cfg.FetchAllowedSchemes)
+	assert.False(t, cfg.FetchAllowPrivateNetworks)
 	assert.Equal(t, "text", cfg.LogFormat)
 	assert.Equal(t, "info", cfg.LogLevel)
+	assert.Equal(t, "slog", cfg.AccessLogFormat)
+	assert.Equal(t, 30, cfg.VideoReadBudgetSec)
+	assert.Equal(t, 0, cfg.AdminPort)
+	assert.Empty(t, cfg.AdminAPIKey)
+	assert.False(t, cfg.ReadinessCheckS3)
+	assert.False(t, cfg.ReadinessCheckProvider)
+	assert.True(t, cfg.InputTypeCheck)
+	assert.Equal(t, 16, cfg.DimensionMultiple)
+	assert.Empty(t, cfg.AllowedDimensions)
+	assert.Equal(t, 86400, cfg.IdempotencyTTLSec)
+	assert.Equal(t, int64(50<<20), cfg.MaxRequestBytes)
+	assert.Equal(t, 1024, cfg.GzipMinBytes)
+	assert.Equal(t, 60, cfg.RequestTimeoutSec)
+	assert.True(t, cfg.ResultCompression)
+	assert.Equal(t, 30, cfg.ShutdownDrainSec)
+	assert.Empty(t, cfg.S3AllowedEndpoints)
+	assert.Empty(t, cfg.S3Endpoint)
+	assert.True(t, cfg.S3ForcePathStyle)
+	assert.Empty(t, cfg.S3KeyPrefix)
+	assert.False(t, cfg.S3Presign)
+	assert.Equal(t, 3600, cfg.S3PresignTTLSec)
+	assert.Equal(t, 32, cfg.S3MultipartThresholdMB)
+	assert.Equal(t, 8, cfg.S3MultipartPartSizeMB)
+	assert.Equal(t, 4, cfg.S3MultipartConcurrency)
+	assert.Empty(t, cfg.GCSBucket)
+	assert.Empty(t, cfg.GCSKeyPrefix)
+	assert.Empty(t, cfg.GCSEndpoint)
+	assert.Equal(t, 600, cfg.GCSTimeoutSec)
+	assert.Empty(t, cfg.GoogleApplicationCredentials)
+	assert.False(t, cfg.GCSEnabled())
+	assert.Equal(t, "libx264", cfg.VideoCodec)
+	assert.Equal(t, "fast", cfg.VideoPreset)
+	assert.Equal(t, 23, cfg.VideoCRF)
+	assert.Equal(t, "aac", cfg.AudioCodec)
+	assert.Equal(t, "128k", cfg.AudioBitrate)
+	assert.Empty(t, cfg.VideoHWAccel)
+	assert.Equal(t, "demuxer", cfg.VideoConcatStrategy)
+	assert.False(t, cfg.AllowMissingFFmpeg)
+	assert.Equal(t, "black", cfg.ImagePadColor)
+	assert.True(t, cfg.ImageResizeInMemory)
+	assert.True(t, cfg.ThumbnailEnabled)
+	assert.Zero(t, cfg.ThumbnailAtSec)
 }
 
 func TestLoad_CustomValues(t *testing.T) {
 	t.Setenv("RUNPOD_API_KEY", "custom-api-key")
 	t.Setenv("RUNPOD_ENDPOINT_ID", "custom-endpoint")
 	t.Setenv("PORT", "3000")
+	t.Setenv("ADMIN_PORT", "9090")
+	t.Setenv("READINESS_CHECK_S3", "true")
+	t.Setenv("READINESS_CHECK_PROVIDER", "true")
+	t.Setenv("INPUT_TYPE_CHECK", "false")
+	t.Setenv("DIMENSION_MULTIPLE", "8")
+	t.Setenv("ALLOWED_DIMENSIONS", "384x576,512x512")
+	t.Setenv("IDEMPOTENCY_TTL_SEC", "3600")
+	t.Setenv("MAX_REQUEST_BYTES", "1048576")
+	t.Setenv("GZIP_MIN_BYTES", "0")
+	t.Setenv("REQUEST_TIMEOUT_SEC", "15")
 	t.Setenv("TEMP_DIR", "/custom/temp")
+	t.Setenv("TEMP_CLEANUP_CONCURRENCY", "8")
+	t.Setenv("JOB_TTL_SEC", "86400")
+	t.Setenv("MAX_RETENTION_SEC", "172800")
+	t.Setenv("MAX_STORED_JOBS", "1000")
+	t.Setenv("JOB_LOG_LINES", "50")
+	t.Setenv("STUCK_JOB_THRESHOLD_SEC", "600")
+	t.Setenv("ADMIN_API_KEY", "admin-secret")
+	t.Setenv("KEEP_LOCAL_OUTPUT", "true")
+	t.Setenv("JANITOR_INTERVAL_SEC", "60")
 	t.Setenv("CHUNK_TARGET_SEC", "60")
+	t.Setenv("MAX_CHUNKS", "20")
+	t.Setenv("MAX_AUDIO_DURATION_SEC", "900")
+	t.Setenv("MAX_IMAGE_PIXELS", "16000000")
+	t.Setenv("MAX_CONCURRENT_CHUNKS", "1")
+	t.Setenv("MAX_GLOBAL_CONCURRENCY", "8")
+	t.Setenv("JOB_WORKERS", "2")
+	t.Setenv("JOB_QUEUE_DEPTH", "10")
+	t.Setenv("RUNPOD_POLL_INTERVAL_MS", "2000")
+	t.Setenv("POLL_BACKOFF_FACTOR", "1.5")
+	t.Setenv("POLL_MAX_INTERVAL_MS", "30000")
+	t.Setenv("RUNPOD_SUBMIT_TIMEOUT_SEC", "300")
+	t.Setenv("RUNPOD_POLL_TIMEOUT_SEC", "10")
+	t.Setenv("RUNPOD_POLL_CACHE_TTL_MS", "500")
+	t.Setenv("RUNPOD_BREAKER_THRESHOLD", "0")
+	t.Setenv("RUNPOD_BREAKER_COOLDOWN_SEC", "60")
+	t.Setenv("MIN_SILENCE_MS", "300")
+	t.Setenv("SILENCE_THRESH_DB", "-32.5")
+	t.Setenv("AUDIO_SAMPLE_RATE", "0")
+	t.Setenv("AUDIO_CHANNELS", "2")
+	t.Setenv("MAX_CHUNK_RETRIES", "0")
+	t.Setenv("CHUNK_RETRY_BACKOFF_MS", "500")
+	t.Setenv("CHUNK_TIMEOUT_SEC", "600")
+	t.Setenv("MAX_JOIN_RETRIES", "4")
+	t.Setenv("JOIN_RETRY_BACKOFF_MS", "250")
+	t.Setenv("PREWARM", "true")
+	t.Setenv("PREWARM_TIMEOUT_SEC", "120")
+	t.Setenv("INPUT_DOWNLOAD_CONCURRENCY", "8")
+	t.Setenv("INPUT_DOWNLOAD_TIMEOUT_SEC", "30")
+	t.Setenv("INPUT_DOWNLOAD_MAX_MB", "25")
+	t.Setenv("FETCH_ALLOWED_HOSTS", "cdn.example.com,media.example.com:8443")
+	t.Setenv("FETCH_ALLOWED_SCHEMES", "https")
+	t.Setenv("FETCH_ALLOW_PRIVATE_NETWORKS", "true")
 	t.Setenv("S3_BUCKET", "my-bucket")
 	t.Setenv("S3_REGION", "us-east-1")
+	t.Setenv("S3_ALLOWED_ENDPOINTS", "minio.internal,localhost:4566")
+	t.Setenv("S3_ENDPOINT", "https://minio.internal:9000")
+	t.Setenv("S3_FORCE_PATH_STYLE", "false")
+	t.Setenv("S3_KEY_PREFIX", "prod/infinitetalk")
+	t.Setenv("S3_PRESIGN", "true")
+	t.Setenv("S3_PRESIGN_TTL_SEC", "900")
+	t.Setenv("S3_MULTIPART_THRESHOLD_MB", "64")
+	t.Setenv("S3_MULTIPART_PART_SIZE_MB", "16")
+	t.Setenv("S3_MULTIPART_CONCURRENCY", "2")
+	t.Setenv("GCS_BUCKET", "gcs-bucket")
+	t.Setenv("GCS_KEY_PREFIX", "videos")
+	t.Setenv("GCS_ENDPOINT", "http://localhost:4443")
+	t.Setenv("GCS_TIMEOUT_SEC", "120")
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "/secrets/gcs-key.json")
 	t.Setenv("AWS_ACCESS_KEY_ID", "access-key")
 	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret-key")
 	t.Setenv("LOG_FORMAT", "json")
 	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("ACCESS_LOG_FORMAT", "combined")
+	t.Setenv("VIDEO_READ_BUDGET_SEC", "5")
+	t.Setenv("RESULT_COMPRESSION_ENABLED", "false")
+	t.Setenv("SHUTDOWN_DRAIN_SEC", "120")
+	t.Setenv("VIDEO_CODEC", "libx265")
+	t.Setenv("VIDEO_PRESET", "slow")
+	t.Setenv("VIDEO_CRF", "28")
+	t.Setenv("AUDIO_CODEC", "libopus")
+	t.Setenv("AUDIO_BITRATE", "96k")
+	t.Setenv("VIDEO_HWACCEL", "nvenc")
+	t.Setenv("VIDEO_CONCAT_STRATEGY", "auto")
+	t.Setenv("ALLOW_MISSING_FFMPEG", "true")
+	t.Setenv("IMAGE_PAD_COLOR", "#1a2b3c")
+	t.Setenv("IMAGE_RESIZE_IN_MEMORY", "false")
+	t.Setenv("THUMBNAIL_ENABLED", "false")
+	t.Setenv("THUMBNAIL_AT_SEC", "2.5")
 
 	cfg, err := Load()
 	require.NoError(t, err)
 
 	assert.Equal(t, 3000, cfg.Port)
+	assert.Equal(t, 9090, cfg.AdminPort)
+	assert.Equal(t, "admin-secret", cfg.AdminAPIKey)
+	assert.True(t, cfg.ReadinessCheckS3)
+	assert.True(t, cfg.ReadinessCheckProvider)
+	assert.False(t, cfg.InputTypeCheck)
+	assert.Equal(t, 8, cfg.DimensionMultiple)
+	assert.Equal(t, []string{"384x576", "512x512"}, cfg.AllowedDimensions)
+	assert.Equal(t, 3600, cfg.IdempotencyTTLSec)
+	assert.Equal(t, int64(1<<20), cfg.MaxRequestBytes)
+	assert.Equal(t, 0, cfg.GzipMinBytes)
+	assert.Equal(t, 15, cfg.RequestTimeoutSec)
 	assert.Equal(t, "/custom/temp", cfg.TempDir)
+	assert.Equal(t, 8, cfg.TempCleanupConcurrency)
+	assert.Equal(t, 86400, cfg.JobTTLSec)
+	assert.Equal(t, 172800, cfg.MaxRetentionSec)
+	assert.Equal(t, 1000, cfg.MaxStoredJobs)
+	assert.Equal(t, 50, cfg.JobLogLines)
+	assert.Equal(t, 600, cfg.StuckJobThresholdSec)
+	assert.True(t, cfg.KeepLocalOutput)
+	assert.Equal(t, 60, cfg.JanitorIntervalSec)
 	assert.Equal(t, 60, cfg.ChunkTargetSec)
+	assert.Equal(t, 20, cfg.MaxChunks)
+	assert.Equal(t, 900, cfg.MaxAudioDurationSec)
+	assert.Equal(t, int64(16000000), cfg.MaxImagePixels)
+	assert.Equal(t, 1, cfg.MaxConcurrentChunks)
+	assert.Equal(t, 8, cfg.MaxGlobalConcurrency)
+	assert.Equal(t, 2, cfg.JobWorkers)
+	assert.Equal(t, 10, cfg.JobQueueDepth)
+	assert.Equal(t, 2000, cfg.RunPodPollIntervalMs)
+	assert.Equal(t, 1.5, cfg.PollBackoffFactor)
+	assert.Equal(t, 30000, cfg.PollMaxIntervalMs)
+	assert.Equal(t, 300, cfg.RunPodSubmitTimeoutSec)
+	assert.Equal(t, 10, cfg.RunPodPollTimeoutSec)
+	assert.Equal(t, 500, cfg.RunPodPollCacheTTLMs)
+	assert.Equal(t, 0, cfg.RunPodBreakerThreshold)
+	assert.Equal(t, 60, cfg.RunPodBreakerCooldownSec)
+	assert.Equal(t, 300, cfg.MinSilenceMs)
+	assert.Equal(t, -32.5, cfg.SilenceThreshDB)
+	assert.Equal(t, 0, cfg.AudioSampleRate)
+	assert.Equal(t, 2, cfg.AudioChannels)
+	assert.Equal(t, 0, cfg.MaxChunkRetries)
+	assert.Equal(t, 500, cfg.ChunkRetryBackoffMs)
+	assert.Equal(t, 600, cfg.ChunkTimeoutSec)
+	assert.Equal(t, 4, cfg.MaxJoinRetries)
+	assert.Equal(t, 250, cfg.JoinRetryBackoffMs)
+	assert.True(t, cfg.Prewarm)
+	assert.Equal(t, 120, cfg.PrewarmTimeoutSec)
+	assert.Equal(t, 8, cfg.InputDownloadConcurrency)
+	assert.Equal(t, 30, cfg.InputDownloadTimeoutSec)
+	assert.Equal(t, 25, cfg.InputDownloadMaxMB)
+	assert.Equal(t, []string{"cdn.example.com", "media.example.com:8443"}, cfg.FetchAllowedHosts)
+	assert.Equal(t, []string{"https"}, cfg.FetchAllowedSchemes)
+	assert.True(t, cfg.FetchAllowPrivateNetworks)
 	assert.Equal(t, "my-bucket", cfg.S3Bucket)
 	assert.Equal(t, "us-east-1", cfg.S3Region)
+	assert.Equal(t, []string{"minio.internal", "localhost:4566"}, cfg.S3AllowedEndpoints)
+	assert.Equal(t, "https://minio.internal:9000", cfg.S3Endpoint)
+	assert.False(t, cfg.S3ForcePathStyle)
+	assert.Equal(t, "prod/infinitetalk", cfg.S3KeyPrefix)
+	assert.True(t, cfg.S3Presign)
+	assert.Equal(t, 900, cfg.S3PresignTTLSec)
+	assert.Equal(t, 64, cfg.S3MultipartThresholdMB)
+	assert.Equal(t, 16, cfg.S3MultipartPartSizeMB)
+	assert.Equal(t, 2, cfg.S3MultipartConcurrency)
+	assert.Equal(t, "gcs-bucket", cfg.GCSBucket)
+	assert.Equal(t, "videos", cfg.GCSKeyPrefix)
+	assert.Equal(t, "http://localhost:4443", cfg.GCSEndpoint)
+	assert.Equal(t, 120, cfg.GCSTimeoutSec)
+	assert.Equal(t, "/secrets/gcs-key.json", cfg.GoogleApplicationCredentials)
 	assert.Equal(t, "access-key", cfg.AWSAccessKeyID)
 	assert.Equal(t, "secret-key", cfg.AWSSecretAccessKey)
 	assert.Equal(t, "json", cfg.LogFormat)
 	assert.Equal(t, "debug", cfg.LogLevel)
+	assert.Equal(t, "combined", cfg.AccessLogFormat)
+	assert.Equal(t, 5, cfg.VideoReadBudgetSec)
+	assert.False(t, cfg.ResultCompression)
+	assert.Equal(t, 120, cfg.ShutdownDrainSec)
+	assert.Equal(t, "libx265", cfg.VideoCodec)
+	assert.Equal(t, "slow", cfg.VideoPreset)
+	assert.Equal(t, 28, cfg.VideoCRF)
+	assert.Equal(t, "libopus", cfg.AudioCodec)
+	assert.Equal(t, "96k", cfg.AudioBitrate)
+	assert.Equal(t, "nvenc", cfg.VideoHWAccel)
+	assert.Equal(t, "auto", cfg.VideoConcatStrategy)
+	assert.True(t, cfg.AllowMissingFFmpeg)
+	assert.Equal(t, "#1a2b3c", cfg.ImagePadColor)
+	assert.False(t, cfg.ImageResizeInMemory)
+	assert.False(t, cfg.ThumbnailEnabled)
+	assert.InDelta(t, 2.5, cfg.ThumbnailAtSec, 0.001)
 }
 
 func TestLoad_InvalidIntegerDefaults(t *testing.T) {
@@ -113,25 +357,50 @@ func TestConfig_S3Enabled(t *testing.T) {
 		name     string
 		bucket   string
 		region   string
+		endpoint string
 		expected bool
 	}{
-		{"both set", "bucket", "region", true},
-		{"only bucket", "bucket", "", false},
-		{"only region", "", "region", false},
-		{"neither set", "", "", false},
+		{"both set", "bucket", "region", "", true},
+		{"only bucket", "bucket", "", "", false},
+		{"only region", "", "region", "", false},
+		{"neither set", "", "", "", false},
+		{"custom endpoint", "bucket", "auto", "https://account.r2.cloudflarestorage.com", true},
+		{"custom endpoint without region", "bucket", "", "http://minio:9000", false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := &Config{
-				S3Bucket: tt.bucket,
-				S3Region: tt.region,
+				S3Bucket:   tt.bucket,
+				S3Region:   tt.region,
+				S3Endpoint: tt.endpoint,
 			}
 			assert.Equal(t, tt.expected, cfg.S3Enabled())
 		})
 	}
 }
 
+func TestConfig_SubmitByURLEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      Config
+		expected bool
+	}{
+		{"no remote storage", Config{BeamSubmitByURL: true}, false},
+		{"private S3 bucket", Config{S3Bucket: "bucket", S3Region: "region"}, false},
+		{"presigned S3 URLs", Config{S3Bucket: "bucket", S3Region: "region", S3Presign: true}, true},
+		{"public S3 bucket opted in", Config{S3Bucket: "bucket", S3Region: "region", BeamSubmitByURL: true}, true},
+		{"GCS", Config{GCSBucket: "bucket"}, false},
+		{"public GCS bucket opted in", Config{GCSBucket: "bucket", BeamSubmitByURL: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.cfg.SubmitByURLEnabled())
+		})
+	}
+}
+
 func TestConfig_BeamEnabled(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -165,6 +434,7 @@ func TestLoad_BeamDefaults(t *testing.T) {
 
 	assert.Equal(t, 5000, cfg.BeamPollIntervalMs)
 	assert.Equal(t, 600, cfg.BeamPollTimeoutSec)
+	assert.False(t, cfg.BeamSubmitByURL)
 }
 
 func TestLoad_BeamCustomValues(t *testing.T) {
@@ -174,6 +444,7 @@ func TestLoad_BeamCustomValues(t *testing.T) {
 	t.Setenv("BEAM_QUEUE_URL", "https://api.beam.cloud/v1/task_queue/123/tasks")
 	t.Setenv("BEAM_POLL_INTERVAL_MS", "3000")
 	t.Setenv("BEAM_POLL_TIMEOUT_SEC", "300")
+	t.Setenv("BEAM_SUBMIT_BY_URL", "true")
 
 	cfg, err := Load()
 	require.NoError(t, err)
@@ -182,6 +453,7 @@ func TestLoad_BeamCustomValues(t *testing.T) {
 	assert.Equal(t, "https://api.beam.cloud/v1/task_queue/123/tasks", cfg.BeamQueueURL)
 	assert.Equal(t, 3000, cfg.BeamPollIntervalMs)
 	assert.Equal(t, 300, cfg.BeamPollTimeoutSec)
+	assert.True(t, cfg.BeamSubmitByURL)
 }
 
 func TestConfig_String(t *testing.T) {
@@ -288,4 +560,138 @@ func TestConfig_Validate(t *testing.T) {
 		err := cfg.Validate()
 		assert.ErrorIs(t, err, ErrRunPodEndpointIDRequired)
 	})
+
+	t.Run("admin port same as port", func(t *testing.T) {
+		cfg := &Config{
+			RunPodAPIKey:     "key",
+			RunPodEndpointID: "endpoint",
+			Port:             8080,
+			AdminPort:        8080,
+		}
+		err := cfg.Validate()
+		assert.ErrorIs(t, err, ErrAdminPortConflict)
+	})
+
+	t.Run("presign TTL out of range", func(t *testing.T) {
+		for _, ttl := range []int{0, 604801} {
+			cfg := &Config{
+				RunPodAPIKey:     "key",
+				RunPodEndpointID: "endpoint",
+				S3Presign:        true,
+				S3PresignTTLSec:  ttl,
+			}
+			assert.ErrorIs(t, cfg.Validate(), ErrInvalidPresignTTL, "ttl %d", ttl)
+		}
+	})
+
+	t.Run("presign TTL ignored when presigning is off", func(t *testing.T) {
+		cfg := &Config{
+			RunPodAPIKey:     "key",
+			RunPodEndpointID: "endpoint",
+			S3PresignTTLSec:  0,
+		}
+		assert.NoError(t, cfg.Validate())
+	})
+
+	t.Run("S3 and GCS both configured", func(t *testing.T) {
+		cfg := &Config{
+			RunPodAPIKey:           "key",
+			RunPodEndpointID:       "endpoint",
+			S3Bucket:               "bucket",
+			S3Region:               "us-east-1",
+			S3MultipartThresholdMB: 32,
+			S3MultipartPartSizeMB:  8,
+			S3MultipartConcurrency: 4,
+			GCSBucket:              "gcs-bucket",
+		}
+		assert.ErrorIs(t, cfg.Validate(), ErrMultipleStorageBackends)
+	})
+
+	t.Run("invalid S3 endpoint", func(t *testing.T) {
+		for _, endpoint := range []string{"minio:9000", "ftp://minio:9000", "http://", "://bad"} {
+			cfg := &Config{
+				RunPodAPIKey:     "key",
+				RunPodEndpointID: "endpoint",
+				S3Endpoint:       endpoint,
+			}
+			assert.ErrorIs(t, cfg.Validate(), ErrInvalidS3Endpoint, "endpoint %q", endpoint)
+		}
+
+		cfg := &Config{
+			RunPodAPIKey:     "key",
+			RunPodEndpointID: "endpoint",
+			S3Endpoint:       "http://localhost:9000",
+		}
+		assert.NoError(t, cfg.Validate())
+	})
+
+	t.Run("invalid fetch schemes", func(t *testing.T) {
+		cfg := &Config{
+			RunPodAPIKey:        "key",
+			RunPodEndpointID:    "endpoint",
+			FetchAllowedSchemes: []string{"https", "file"},
+		}
+		assert.ErrorIs(t, cfg.Validate(), ErrInvalidFetchSchemes)
+
+		cfg.FetchAllowedSchemes = []string{"HTTPS", "http"}
+		assert.NoError(t, cfg.Validate())
+	})
+
+	t.Run("invalid allowed dimensions", func(t *testing.T) {
+		for _, preset := range []string{"384", "384x", "x576", "0x576", "384x-576", "384X576", "0384x576", "384 x 576"} {
+			cfg := &Config{
+				RunPodAPIKey:      "key",
+				RunPodEndpointID:  "endpoint",
+				AllowedDimensions: []string{"512x512", preset},
+			}
+			assert.ErrorIs(t, cfg.Validate(), ErrInvalidAllowedDimensions, "preset %q", preset)
+		}
+
+		cfg := &Config{
+			RunPodAPIKey:      "key",
+			RunPodEndpointID:  "endpoint",
+			AllowedDimensions: []string{"384x576", "512x512"},
+		}
+		assert.NoError(t, cfg.Validate())
+	})
+
+	t.Run("multipart settings out of range", func(t *testing.T) {
+		for _, tc := range []struct{ threshold, partSize, concurrency int }{
+			{32, 4, 4},
+			{0, 8, 4},
+			{32, 8, 0},
+		} {
+			cfg := &Config{
+				RunPodAPIKey:           "key",
+				RunPodEndpointID:       "endpoint",
+				S3Bucket:               "bucket",
+				S3Region:               "us-east-1",
+				S3MultipartThresholdMB: tc.threshold,
+				S3MultipartPartSizeMB:  tc.partSize,
+				S3MultipartConcurrency: tc.concurrency,
+			}
+			assert.ErrorIs(t, cfg.Validate(), ErrInvalidMultipartConfig, "%+v", tc)
+		}
+	})
+
+	t.Run("video CRF out of range", func(t *testing.T) {
+		for _, crf := range []int{-1, 52} {
+			cfg := &Config{
+				RunPodAPIKey:     "key",
+				RunPodEndpointID: "endpoint",
+				VideoCRF:         crf,
+			}
+			assert.ErrorIs(t, cfg.Validate(), ErrInvalidVideoCRF, "crf %d", crf)
+		}
+
+		// 0 is lossless and 51 the lowest quality
+		for _, crf := range []int{0, 51} {
+			cfg := &Config{
+				RunPodAPIKey:     "key",
+				RunPodEndpointID: "endpoint",
+				VideoCRF:         crf,
+			}
+			assert.NoError(t, cfg.Validate(), "crf %d", crf)
+		}
+	})
 }
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sethvargo/go-envconfig"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnsupportedConfigFile is returned when the config file extension is not
+// .yaml, .yml or .json.
+var ErrUnsupportedConfigFile = errors.New("config: config file must be .yaml, .yml or .json")
+
+// LoadFromFile reads configuration from a YAML or JSON file and then applies
+// environment variables on top, so a variable set in the environment always
+// wins over the file. File keys are the environment variable names, e.g.:
+//
+//	RUNPOD_ENDPOINT_ID: abc123
+//	MAX_CONCURRENT_CHUNKS: 2
+//	S3_ALLOWED_ENDPOINTS: [minio.internal, localhost]
+//
+// Defaults and required variables are applied after the merge, so a required
+// value may come from either source.
+func LoadFromFile(path string) (*Config, error) {
+	values, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return load(envconfig.MultiLookuper(envconfig.OsLookuper(), envconfig.MapLookuper(values)))
+}
+
+// readConfigFile parses a YAML or JSON config file into environment-style
+// string values keyed by variable name.
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: read config file: %w", err)
+	}
+
+	var raw map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".json":
+		err = json.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedConfigFile, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config: parse %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		s, err := envValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("config: %s in %s: %w", key, path, err)
+		}
+		values[strings.ToUpper(key)] = s
+	}
+	return values, nil
+}
+
+// envValue formats a parsed file value the way it would be written in the
+// environment. Lists become comma-separated strings; nested objects are rejected.
+func envValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			s, err := envValue(item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = s
+		}
+		return strings.Join(items, ","), nil
+	case map[string]any:
+		return "", errors.New("nested objects are not supported")
+	case float64:
+		// JSON decodes every number as float64; keep integers free of exponents
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
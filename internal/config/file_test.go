@@ -0,0 +1,138 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unsetEnv unsets the variables for the duration of the test.
+func unsetEnv(t *testing.T, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+}
+
+// writeConfigFile writes content to a file with the given name in a temp dir.
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadFromFile_YAML(t *testing.T) {
+	unsetEnv(t, "RUNPOD_API_KEY", "RUNPOD_ENDPOINT_ID", "PORT", "MAX_CONCURRENT_CHUNKS", "SILENCE_THRESH_DB", "S3_ALLOWED_ENDPOINTS", "LOG_FORMAT")
+
+	path := writeConfigFile(t, "config.yaml", `
+RUNPOD_API_KEY: file-key
+RUNPOD_ENDPOINT_ID: file-endpoint
+PORT: 9090
+MAX_CONCURRENT_CHUNKS: 2
+SILENCE_THRESH_DB: -35.5
+S3_ALLOWED_ENDPOINTS: [minio.internal, localhost]
+`)
+
+	cfg, err := LoadFromFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "file-key", cfg.RunPodAPIKey)
+	assert.Equal(t, "file-endpoint", cfg.RunPodEndpointID)
+	assert.Equal(t, 9090, cfg.Port)
+	assert.Equal(t, 2, cfg.MaxConcurrentChunks)
+	assert.InDelta(t, -35.5, cfg.SilenceThreshDB, 0.001)
+	assert.Equal(t, []string{"minio.internal", "localhost"}, cfg.S3AllowedEndpoints)
+	// Unset keys keep their defaults
+	assert.Equal(t, "text", cfg.LogFormat)
+}
+
+func TestLoadFromFile_JSON(t *testing.T) {
+	unsetEnv(t, "RUNPOD_API_KEY", "RUNPOD_ENDPOINT_ID", "PORT", "CHUNK_TARGET_SEC", "S3_PRESIGN")
+
+	path := writeConfigFile(t, "config.json", `{
+		"RUNPOD_API_KEY": "file-key",
+		"RUNPOD_ENDPOINT_ID": "file-endpoint",
+		"PORT": 9090,
+		"CHUNK_TARGET_SEC": 30,
+		"S3_PRESIGN": true
+	}`)
+
+	cfg, err := LoadFromFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "file-key", cfg.RunPodAPIKey)
+	assert.Equal(t, 9090, cfg.Port)
+	assert.Equal(t, 30, cfg.ChunkTargetSec)
+	assert.True(t, cfg.S3Presign)
+}
+
+func TestLoadFromFile_EnvOverridesFile(t *testing.T) {
+	unsetEnv(t, "RUNPOD_API_KEY", "LOG_LEVEL")
+	t.Setenv("RUNPOD_ENDPOINT_ID", "env-endpoint")
+	t.Setenv("PORT", "7070")
+
+	path := writeConfigFile(t, "config.yml", `
+RUNPOD_API_KEY: file-key
+RUNPOD_ENDPOINT_ID: file-endpoint
+PORT: 9090
+LOG_LEVEL: debug
+`)
+
+	cfg, err := LoadFromFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "env-endpoint", cfg.RunPodEndpointID)
+	assert.Equal(t, 7070, cfg.Port)
+	// Values missing from the environment come from the file
+	assert.Equal(t, "file-key", cfg.RunPodAPIKey)
+	assert.Equal(t, "debug", cfg.LogLevel)
+}
+
+func TestLoadFromFile_EnvOnly(t *testing.T) {
+	t.Setenv("RUNPOD_API_KEY", "env-key")
+	t.Setenv("RUNPOD_ENDPOINT_ID", "env-endpoint")
+
+	cfg, err := LoadFromFile(writeConfigFile(t, "config.yaml", "{}"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "env-key", cfg.RunPodAPIKey)
+	assert.Equal(t, "env-endpoint", cfg.RunPodEndpointID)
+}
+
+func TestLoadFromFile_RequiredAfterMerge(t *testing.T) {
+	unsetEnv(t, "RUNPOD_API_KEY", "RUNPOD_ENDPOINT_ID")
+
+	_, err := LoadFromFile(writeConfigFile(t, "config.yaml", "RUNPOD_ENDPOINT_ID: file-endpoint\n"))
+	assert.ErrorIs(t, err, ErrRunPodAPIKeyRequired)
+
+	t.Setenv("RUNPOD_API_KEY", "env-key")
+	_, err = LoadFromFile(writeConfigFile(t, "config.yaml", "PORT: 9090\n"))
+	assert.ErrorIs(t, err, ErrRunPodEndpointIDRequired)
+}
+
+func TestLoadFromFile_Errors(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadFromFile(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("unsupported extension", func(t *testing.T) {
+		_, err := LoadFromFile(writeConfigFile(t, "config.toml", "PORT = 9090"))
+		assert.ErrorIs(t, err, ErrUnsupportedConfigFile)
+	})
+
+	t.Run("invalid syntax", func(t *testing.T) {
+		_, err := LoadFromFile(writeConfigFile(t, "config.json", "{"))
+		assert.Error(t, err)
+	})
+
+	t.Run("nested object", func(t *testing.T) {
+		_, err := LoadFromFile(writeConfigFile(t, "config.yaml", "S3:\n  BUCKET: videos\n"))
+		assert.ErrorContains(t, err, "nested objects")
+	})
+}
@@ -0,0 +1,193 @@
+// Package fetch downloads remote input media (images and audio referenced by URL)
+// with a bounded number of concurrent downloads shared across all jobs.
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/maauso/infinitetalk-api/internal/urlsafe"
+)
+
+// Static errors for fetch operations.
+var (
+	// ErrTooLarge is returned when a download exceeds the configured size cap.
+	ErrTooLarge = errors.New("fetch: download exceeds maximum size")
+	// ErrUnexpectedStatus is returned when the remote server responds with a non-200 status.
+	ErrUnexpectedStatus = errors.New("fetch: unexpected status")
+	// ErrTimeout is returned when a download does not finish within the per-download timeout.
+	ErrTimeout = errors.New("fetch: download timed out")
+	// ErrDisallowedScheme is returned for URLs whose scheme is not http or https.
+	ErrDisallowedScheme = errors.New("fetch: URL scheme not allowed")
+)
+
+// allowedSchemes are the URL schemes Fetch downloads from.
+var allowedSchemes = []string{"http", "https"}
+
+// Fetcher downloads remote input media.
+type Fetcher interface {
+	// Fetch downloads the resource at rawURL and returns its content.
+	Fetch(ctx context.Context, rawURL string) ([]byte, error)
+}
+
+// HTTPFetcher is the HTTP implementation of Fetcher.
+// A single HTTPFetcher should be shared across jobs so that its concurrency
+// limit applies globally.
+type HTTPFetcher struct {
+	httpClient *http.Client
+	slots      chan struct{}
+	timeout    time.Duration
+	maxBytes   int64
+	guard      *urlsafe.Guard
+}
+
+// Option is a function that configures an HTTPFetcher.
+type Option func(*HTTPFetcher)
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(c *http.Client) Option {
+	return func(f *HTTPFetcher) {
+		f.httpClient = c
+	}
+}
+
+// WithConcurrency sets the maximum number of downloads in flight at once.
+func WithConcurrency(n int) Option {
+	return func(f *HTTPFetcher) {
+		if n > 0 {
+			f.slots = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithTimeout sets the per-download timeout, covering the whole transfer.
+func WithTimeout(d time.Duration) Option {
+	return func(f *HTTPFetcher) {
+		if d > 0 {
+			f.timeout = d
+		}
+	}
+}
+
+// WithMaxBytes sets the maximum accepted download size.
+func WithMaxBytes(n int64) Option {
+	return func(f *HTTPFetcher) {
+		if n > 0 {
+			f.maxBytes = n
+		}
+	}
+}
+
+// WithGuard checks every URL, redirect and dialed address with g, so that
+// downloads cannot reach internal addresses.
+func WithGuard(g *urlsafe.Guard) Option {
+	return func(f *HTTPFetcher) {
+		f.guard = g
+	}
+}
+
+// NewHTTPFetcher creates a new HTTPFetcher.
+// Defaults: 4 concurrent downloads, 60s timeout, 100 MB size cap, no guard.
+func NewHTTPFetcher(opts ...Option) *HTTPFetcher {
+	f := &HTTPFetcher{
+		httpClient: &http.Client{},
+		slots:      make(chan struct{}, 4),
+		timeout:    60 * time.Second,
+		maxBytes:   100 << 20,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if f.guard != nil {
+		f.httpClient = f.guard.Client(f.httpClient)
+	}
+	return f
+}
+
+// Fetch downloads rawURL, waiting for a free download slot first.
+// URLs that are not http or https fail with ErrDisallowedScheme, URLs
+// rejected by the guard fail with its urlsafe errors, downloads larger than
+// the size cap fail with ErrTooLarge and downloads exceeding the timeout fail
+// with ErrTimeout.
+func (f *HTTPFetcher) Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	if err := checkScheme(rawURL); err != nil {
+		return nil, err
+	}
+	if f.guard != nil {
+		if err := f.guard.Check(ctx, rawURL); err != nil {
+			return nil, err
+		}
+	}
+
+	select {
+	case f.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("fetch: waiting for download slot: %w", ctx.Err())
+	}
+	defer func() { <-f.slots }()
+
+	dlCtx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	data, err := f.download(dlCtx, rawURL)
+	if err != nil && errors.Is(dlCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return nil, fmt.Errorf("%w after %s: %s", ErrTimeout, f.timeout, rawURL)
+	}
+	return data, err
+}
+
+// checkScheme rejects URLs that do not parse or whose scheme is not allowed.
+func checkScheme(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("fetch: parse URL: %w", err)
+	}
+	if !slices.Contains(allowedSchemes, strings.ToLower(u.Scheme)) {
+		return fmt.Errorf("%w: %q", ErrDisallowedScheme, u.Scheme)
+	}
+	return nil
+}
+
+// download performs the GET request and reads at most maxBytes of the body.
+func (f *HTTPFetcher) download(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch: create request: %w", err)
+	}
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch: request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+	if resp.ContentLength > f.maxBytes {
+		return nil, fmt.Errorf("%w: %d bytes (max %d)", ErrTooLarge, resp.ContentLength, f.maxBytes)
+	}
+
+	// Read one byte past the cap to detect oversized bodies without a Content-Length
+	data, err := io.ReadAll(io.LimitReader(resp.Body, f.maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetch: read body: %w", err)
+	}
+	if int64(len(data)) > f.maxBytes {
+		return nil, fmt.Errorf("%w: max %d bytes", ErrTooLarge, f.maxBytes)
+	}
+
+	return data, nil
+}
+
+// Verify interface implementation at compile time.
+var _ Fetcher = (*HTTPFetcher)(nil)
@@ -0,0 +1,196 @@
+package fetch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maauso/infinitetalk-api/internal/urlsafe"
+)
+
+func TestNewHTTPFetcher_Defaults(t *testing.T) {
+	f := NewHTTPFetcher()
+
+	assert.Equal(t, 4, cap(f.slots))
+	assert.Equal(t, 60*time.Second, f.timeout)
+	assert.Equal(t, int64(100<<20), f.maxBytes)
+}
+
+func TestNewHTTPFetcher_Options(t *testing.T) {
+	f := NewHTTPFetcher(
+		WithConcurrency(2),
+		WithTimeout(5*time.Second),
+		WithMaxBytes(1024),
+	)
+
+	assert.Equal(t, 2, cap(f.slots))
+	assert.Equal(t, 5*time.Second, f.timeout)
+	assert.Equal(t, int64(1024), f.maxBytes)
+}
+
+func TestFetch_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("image-bytes"))
+	}))
+	defer server.Close()
+
+	data, err := NewHTTPFetcher().Fetch(context.Background(), server.URL+"/image.png")
+	require.NoError(t, err)
+	assert.Equal(t, "image-bytes", string(data))
+}
+
+func TestFetch_BoundsConcurrency(t *testing.T) {
+	const limit = 2
+	var inFlight, maxInFlight atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			prev := maxInFlight.Load()
+			if n <= prev || maxInFlight.CompareAndSwap(prev, n) {
+				break
+			}
+		}
+		time.Sleep(30 * time.Millisecond)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	f := NewHTTPFetcher(WithConcurrency(limit))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := f.Fetch(context.Background(), server.URL)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(limit))
+	assert.Equal(t, int32(limit), maxInFlight.Load())
+}
+
+func TestFetch_TooLarge(t *testing.T) {
+	t.Run("content length", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(strings.Repeat("x", 100)))
+		}))
+		defer server.Close()
+
+		_, err := NewHTTPFetcher(WithMaxBytes(10)).Fetch(context.Background(), server.URL)
+		assert.ErrorIs(t, err, ErrTooLarge)
+	})
+
+	t.Run("chunked body", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Flushing before writing the body forces chunked encoding (no Content-Length)
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte(strings.Repeat("x", 100)))
+		}))
+		defer server.Close()
+
+		_, err := NewHTTPFetcher(WithMaxBytes(10)).Fetch(context.Background(), server.URL)
+		assert.ErrorIs(t, err, ErrTooLarge)
+	})
+}
+
+func TestFetch_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	_, err := NewHTTPFetcher(WithTimeout(20*time.Millisecond)).Fetch(context.Background(), server.URL)
+	assert.ErrorIs(t, err, ErrTimeout)
+}
+
+func TestFetch_UnexpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPFetcher().Fetch(context.Background(), server.URL)
+	assert.ErrorIs(t, err, ErrUnexpectedStatus)
+}
+
+func TestFetch_DisallowedScheme(t *testing.T) {
+	f := NewHTTPFetcher(WithConcurrency(1))
+	f.slots <- struct{}{} // a rejected URL must not wait for a slot
+
+	for _, rawURL := range []string{
+		"file:///etc/passwd",
+		"ftp://example.com/face.png",
+		"gopher://example.com:70/_",
+		"example.com/face.png",
+	} {
+		_, err := f.Fetch(context.Background(), rawURL)
+		assert.ErrorIs(t, err, ErrDisallowedScheme, rawURL)
+	}
+}
+
+func TestFetch_Guard(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte("data"))
+	}))
+	defer server.Close()
+
+	t.Run("blocks internal addresses", func(t *testing.T) {
+		_, err := NewHTTPFetcher(WithGuard(urlsafe.New())).Fetch(context.Background(), server.URL)
+		assert.ErrorIs(t, err, urlsafe.ErrBlockedAddress)
+		assert.Zero(t, requests.Load())
+	})
+
+	t.Run("allowed when private networks are allowed", func(t *testing.T) {
+		f := NewHTTPFetcher(WithGuard(urlsafe.New(urlsafe.WithPrivateNetworks(true))))
+		data, err := f.Fetch(context.Background(), server.URL)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("data"), data)
+	})
+}
+
+func TestFetch_GuardChecksRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://metadata.internal/latest", http.StatusFound)
+	}))
+	defer server.Close()
+
+	guard := urlsafe.New(
+		urlsafe.WithPrivateNetworks(true),
+		urlsafe.WithAllowedHosts(strings.TrimPrefix(server.URL, "http://")),
+	)
+	_, err := NewHTTPFetcher(WithGuard(guard)).Fetch(context.Background(), server.URL)
+	assert.ErrorIs(t, err, urlsafe.ErrHostNotAllowed)
+}
+
+func TestFetch_ContextCancelledWhileWaitingForSlot(t *testing.T) {
+	f := NewHTTPFetcher(WithConcurrency(1))
+	f.slots <- struct{}{} // occupy the only slot
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := f.Fetch(ctx, "http://example.invalid")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotErrorIs(t, err, ErrTimeout)
+}
@@ -32,6 +32,21 @@ func (a *BeamAdapter) Submit(ctx context.Context, imageB64, audioB64 string, opt
 	return taskID, nil
 }
 
+// SubmitByURL sends a lip-sync task to Beam with inputs Beam downloads from the given URLs.
+func (a *BeamAdapter) SubmitByURL(ctx context.Context, imageURL, audioURL string, opts SubmitOptions) (string, error) {
+	beamOpts := beam.SubmitOptions{
+		Prompt:       opts.Prompt,
+		Width:        opts.Width,
+		Height:       opts.Height,
+		ForceOffload: opts.ForceOffload,
+	}
+	taskID, err := a.client.SubmitByURL(ctx, imageURL, audioURL, beamOpts)
+	if err != nil {
+		return "", fmt.Errorf("beam adapter submit by URL: %w", err)
+	}
+	return taskID, nil
+}
+
 // Poll checks the status of a Beam task.
 func (a *BeamAdapter) Poll(ctx context.Context, taskID string) (PollResult, error) {
 	result, err := a.client.Poll(ctx, taskID)
@@ -71,5 +86,16 @@ func (a *BeamAdapter) DownloadOutput(ctx context.Context, outputURL, destPath st
 	return nil
 }
 
+// Cancel requests cancellation of a Beam task.
+func (a *BeamAdapter) Cancel(ctx context.Context, taskID string) error {
+	if err := a.client.Cancel(ctx, taskID); err != nil {
+		return fmt.Errorf("beam adapter cancel: %w", err)
+	}
+	return nil
+}
+
 // Compile-time check that BeamAdapter implements Generator.
 var _ Generator = (*BeamAdapter)(nil)
+
+// Compile-time check that BeamAdapter implements URLSubmitter.
+var _ URLSubmitter = (*BeamAdapter)(nil)
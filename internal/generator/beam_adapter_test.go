@@ -21,6 +21,11 @@ func (m *mockBeamClient) Submit(ctx context.Context, imageB64, audioB64 string,
 	return args.String(0), args.Error(1)
 }
 
+func (m *mockBeamClient) SubmitByURL(ctx context.Context, imageURL, audioURL string, opts beam.SubmitOptions) (string, error) {
+	args := m.Called(ctx, imageURL, audioURL, opts)
+	return args.String(0), args.Error(1)
+}
+
 func (m *mockBeamClient) Poll(ctx context.Context, taskID string) (beam.PollResult, error) {
 	args := m.Called(ctx, taskID)
 	return args.Get(0).(beam.PollResult), args.Error(1)
@@ -31,6 +36,16 @@ func (m *mockBeamClient) DownloadOutput(ctx context.Context, outputURL, destPath
 	return args.Error(0)
 }
 
+func (m *mockBeamClient) Cancel(ctx context.Context, taskID string) error {
+	args := m.Called(ctx, taskID)
+	return args.Error(0)
+}
+
+func (m *mockBeamClient) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
 func TestBeamAdapter_Submit(t *testing.T) {
 	ctx := context.Background()
 	mockClient := &mockBeamClient{}
@@ -152,3 +167,35 @@ func TestBeamAdapter_DownloadOutput_Error(t *testing.T) {
 	require.Error(t, err)
 	mockClient.AssertExpectations(t)
 }
+
+func TestBeamAdapter_Cancel(t *testing.T) {
+	ctx := context.Background()
+	mockClient := &mockBeamClient{}
+	adapter := NewBeamAdapter(mockClient)
+
+	mockClient.On("Cancel", ctx, "task-123").Return(nil).Once()
+	mockClient.On("Cancel", ctx, "task-456").Return(errors.New("not found")).Once()
+
+	require.NoError(t, adapter.Cancel(ctx, "task-123"))
+
+	err := adapter.Cancel(ctx, "task-456")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "beam adapter cancel")
+	mockClient.AssertExpectations(t)
+}
+
+func TestBeamAdapter_SubmitByURL(t *testing.T) {
+	ctx := context.Background()
+	mockClient := &mockBeamClient{}
+	adapter := NewBeamAdapter(mockClient)
+
+	opts := SubmitOptions{Prompt: "test prompt", Width: 512, Height: 512, ForceOffload: true}
+	mockClient.On("SubmitByURL", ctx, "https://bucket/image.png", "https://bucket/chunk.wav", beam.SubmitOptions{
+		Prompt: "test prompt", Width: 512, Height: 512, ForceOffload: true,
+	}).Return("task-789", nil)
+
+	taskID, err := adapter.SubmitByURL(ctx, "https://bucket/image.png", "https://bucket/chunk.wav", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "task-789", taskID)
+	mockClient.AssertExpectations(t)
+}
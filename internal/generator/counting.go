@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Activity is a snapshot of the provider calls currently in flight.
+type Activity struct {
+	Submits int64 `json:"submits"` // Submit calls in progress
+	Polls   int64 `json:"polls"`   // Poll calls in progress
+}
+
+// Counters tracks in-flight provider calls. It is safe for concurrent use and
+// is meant to be shared by every generator of the same provider.
+type Counters struct {
+	submits atomic.Int64
+	polls   atomic.Int64
+}
+
+// Snapshot returns the current number of in-flight calls.
+func (c *Counters) Snapshot() Activity {
+	return Activity{
+		Submits: c.submits.Load(),
+		Polls:   c.polls.Load(),
+	}
+}
+
+// CountingGenerator wraps a Generator and records in-flight Submit and Poll
+// calls in shared Counters. Counters are decremented when the call returns,
+// whether it succeeded, failed or panicked.
+type CountingGenerator struct {
+	gen      Generator
+	counters *Counters
+}
+
+// NewCountingGenerator wraps gen so that its calls are recorded in counters.
+func NewCountingGenerator(gen Generator, counters *Counters) *CountingGenerator {
+	return &CountingGenerator{gen: gen, counters: counters}
+}
+
+// Submit forwards to the wrapped generator while counting the call as in flight.
+func (g *CountingGenerator) Submit(ctx context.Context, imageB64, audioB64 string, opts SubmitOptions) (string, error) {
+	g.counters.submits.Add(1)
+	defer g.counters.submits.Add(-1)
+	return g.gen.Submit(ctx, imageB64, audioB64, opts)
+}
+
+// SubmitByURL forwards to the wrapped generator while counting the call as in
+// flight. It returns ErrURLSubmitNotSupported when the wrapped generator
+// cannot submit by URL.
+func (g *CountingGenerator) SubmitByURL(ctx context.Context, imageURL, audioURL string, opts SubmitOptions) (string, error) {
+	u, ok := g.gen.(URLSubmitter)
+	if !ok {
+		return "", ErrURLSubmitNotSupported
+	}
+	g.counters.submits.Add(1)
+	defer g.counters.submits.Add(-1)
+	return u.SubmitByURL(ctx, imageURL, audioURL, opts)
+}
+
+// Poll forwards to the wrapped generator while counting the call as in flight.
+func (g *CountingGenerator) Poll(ctx context.Context, jobID string) (PollResult, error) {
+	g.counters.polls.Add(1)
+	defer g.counters.polls.Add(-1)
+	return g.gen.Poll(ctx, jobID)
+}
+
+// DownloadOutput forwards to the wrapped generator.
+func (g *CountingGenerator) DownloadOutput(ctx context.Context, outputURL, destPath string) error {
+	return g.gen.DownloadOutput(ctx, outputURL, destPath)
+}
+
+// Cancel forwards to the wrapped generator.
+func (g *CountingGenerator) Cancel(ctx context.Context, jobID string) error {
+	return g.gen.Cancel(ctx, jobID)
+}
+
+// Compile-time check that CountingGenerator implements Generator.
+var _ Generator = (*CountingGenerator)(nil)
+
+// Compile-time check that CountingGenerator implements URLSubmitter.
+var _ URLSubmitter = (*CountingGenerator)(nil)
@@ -0,0 +1,149 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingGenerator waits on release before returning, failing every other call.
+type blockingGenerator struct {
+	release chan struct{}
+	mu      sync.Mutex
+	calls   int
+}
+
+func (g *blockingGenerator) fail() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.calls++
+	return g.calls%2 == 0
+}
+
+func (g *blockingGenerator) Submit(ctx context.Context, _, _ string, _ SubmitOptions) (string, error) {
+	fail := g.fail()
+	<-g.release
+	if fail {
+		return "", errors.New("submit failed")
+	}
+	return "job-1", nil
+}
+
+func (g *blockingGenerator) Poll(ctx context.Context, _ string) (PollResult, error) {
+	fail := g.fail()
+	<-g.release
+	if fail {
+		return PollResult{}, errors.New("poll failed")
+	}
+	return PollResult{Status: StatusRunning}, nil
+}
+
+func (g *blockingGenerator) DownloadOutput(context.Context, string, string) error {
+	return nil
+}
+
+func (g *blockingGenerator) Cancel(context.Context, string) error {
+	return nil
+}
+
+func TestCountingGenerator_CountsReturnToZero(t *testing.T) {
+	const n = 20
+	inner := &blockingGenerator{release: make(chan struct{})}
+	counters := &Counters{}
+	gen := NewCountingGenerator(inner, counters)
+	ctx := context.Background()
+
+	var started, done sync.WaitGroup
+	for i := 0; i < n; i++ {
+		started.Add(2)
+		done.Add(2)
+		go func() {
+			defer done.Done()
+			started.Done()
+			_, _ = gen.Submit(ctx, "img", "audio", SubmitOptions{})
+		}()
+		go func() {
+			defer done.Done()
+			started.Done()
+			_, _ = gen.Poll(ctx, "job-1")
+		}()
+	}
+	started.Wait()
+
+	// Every call is blocked inside the wrapped generator
+	waitFor(t, func() bool {
+		a := counters.Snapshot()
+		return a.Submits == n && a.Polls == n
+	})
+
+	close(inner.release)
+	done.Wait()
+
+	if got := counters.Snapshot(); got != (Activity{}) {
+		t.Errorf("expected counters to return to zero, got %+v", got)
+	}
+}
+
+func TestCountingGenerator_DecrementsOnPanic(t *testing.T) {
+	counters := &Counters{}
+	gen := NewCountingGenerator(panickingGenerator{}, counters)
+
+	func() {
+		defer func() { _ = recover() }()
+		_, _ = gen.Submit(context.Background(), "img", "audio", SubmitOptions{})
+	}()
+
+	if got := counters.Snapshot(); got != (Activity{}) {
+		t.Errorf("expected counters to return to zero, got %+v", got)
+	}
+}
+
+func TestCountingGenerator_SubmitByURL_NotSupported(t *testing.T) {
+	gen := NewCountingGenerator(panickingGenerator{}, &Counters{})
+
+	if _, err := gen.SubmitByURL(context.Background(), "image", "audio", SubmitOptions{}); !errors.Is(err, ErrURLSubmitNotSupported) {
+		t.Errorf("expected ErrURLSubmitNotSupported, got %v", err)
+	}
+}
+
+// errCancelFailed is returned by panickingGenerator.Cancel.
+var errCancelFailed = errors.New("cancel failed")
+
+func TestCountingGenerator_Cancel(t *testing.T) {
+	gen := NewCountingGenerator(panickingGenerator{}, &Counters{})
+
+	if err := gen.Cancel(context.Background(), "job-1"); !errors.Is(err, errCancelFailed) {
+		t.Errorf("expected the wrapped generator's error, got %v", err)
+	}
+}
+
+type panickingGenerator struct{}
+
+func (panickingGenerator) Submit(context.Context, string, string, SubmitOptions) (string, error) {
+	panic("boom")
+}
+
+func (panickingGenerator) Poll(context.Context, string) (PollResult, error) {
+	panic("boom")
+}
+
+func (panickingGenerator) DownloadOutput(context.Context, string, string) error {
+	return nil
+}
+
+func (panickingGenerator) Cancel(context.Context, string) error {
+	return errCancelFailed
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for i := 0; i < 1000; i++ {
+		if cond() {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("condition not met")
+}
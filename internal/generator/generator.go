@@ -2,7 +2,13 @@
 // Both RunPod and Beam adapters implement this interface.
 package generator
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrURLSubmitNotSupported is returned when the provider cannot fetch inputs by URL.
+var ErrURLSubmitNotSupported = errors.New("provider does not support submitting inputs by URL")
 
 // Status represents the status of a generation job.
 type Status string
@@ -34,6 +40,8 @@ type SubmitOptions struct {
 	Width        int    // Video width in pixels
 	Height       int    // Video height in pixels
 	ForceOffload bool   // Whether to force offload (supported by both Beam and RunPod)
+	PersonCount  string // Number of people to animate, "single" or "multi" (RunPod only)
+	InputType    string // Source media type, "image" or "video" (RunPod only)
 }
 
 // PollResult contains the result of polling a job's status.
@@ -57,4 +65,16 @@ type Generator interface {
 	// For RunPod, this is a no-op since it returns base64 directly.
 	// For Beam, this downloads from the output URL to local temp storage.
 	DownloadOutput(ctx context.Context, outputURL, destPath string) error
+
+	// Cancel requests cancellation of the job with the given ID.
+	// Cancellation is best-effort: providers may take time to stop the worker.
+	Cancel(ctx context.Context, jobID string) error
+}
+
+// URLSubmitter is implemented by generators whose provider can download the
+// inputs itself, which avoids sending large media as base64.
+type URLSubmitter interface {
+	// SubmitByURL sends a lip-sync generation job for the image and audio at
+	// the given URLs and returns a job ID.
+	SubmitByURL(ctx context.Context, imageURL, audioURL string, opts SubmitOptions) (jobID string, err error)
 }
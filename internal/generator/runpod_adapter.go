@@ -24,6 +24,8 @@ func (a *RunPodAdapter) Submit(ctx context.Context, imageB64, audioB64 string, o
 		Width:        opts.Width,
 		Height:       opts.Height,
 		ForceOffload: opts.ForceOffload,
+		PersonCount:  opts.PersonCount,
+		InputType:    opts.InputType,
 	}
 	jobID, err := a.client.Submit(ctx, imageB64, audioB64, runpodOpts)
 	if err != nil {
@@ -71,5 +73,13 @@ func (a *RunPodAdapter) DownloadOutput(ctx context.Context, outputURL, destPath
 	return nil
 }
 
+// Cancel requests cancellation of a RunPod job.
+func (a *RunPodAdapter) Cancel(ctx context.Context, jobID string) error {
+	if err := a.client.Cancel(ctx, jobID); err != nil {
+		return fmt.Errorf("runpod adapter cancel: %w", err)
+	}
+	return nil
+}
+
 // Compile-time check that RunPodAdapter implements Generator.
 var _ Generator = (*RunPodAdapter)(nil)
@@ -26,6 +26,16 @@ func (m *mockRunPodClient) Poll(ctx context.Context, jobID string) (runpod.PollR
 	return args.Get(0).(runpod.PollResult), args.Error(1)
 }
 
+func (m *mockRunPodClient) Cancel(ctx context.Context, jobID string) error {
+	args := m.Called(ctx, jobID)
+	return args.Error(0)
+}
+
+func (m *mockRunPodClient) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
 func TestRunPodAdapter_Submit(t *testing.T) {
 	ctx := context.Background()
 	mockClient := &mockRunPodClient{}
@@ -124,3 +134,19 @@ func TestRunPodAdapter_DownloadOutput(t *testing.T) {
 	err := adapter.DownloadOutput(context.Background(), "http://example.com/video.mp4", "/tmp/video.mp4")
 	assert.NoError(t, err)
 }
+
+func TestRunPodAdapter_Cancel(t *testing.T) {
+	ctx := context.Background()
+	mockClient := &mockRunPodClient{}
+	adapter := NewRunPodAdapter(mockClient)
+
+	mockClient.On("Cancel", ctx, "job-123").Return(nil).Once()
+	mockClient.On("Cancel", ctx, "job-456").Return(errors.New("not found")).Once()
+
+	require.NoError(t, adapter.Cancel(ctx, "job-123"))
+
+	err := adapter.Cancel(ctx, "job-456")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "runpod adapter cancel")
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,140 @@
+package job
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/maauso/infinitetalk-api/internal/media"
+)
+
+// InputHash identifies a submission by the SHA-256 of its decoded inputs and
+// the options that affect the generated video, so identical submissions hash
+// alike. It returns "" when the inputs are URLs, whose content may change, or
+// are not valid base64.
+func InputHash(input ProcessVideoInput) string {
+	if input.ImageURL != "" || input.AudioURL != "" {
+		return ""
+	}
+
+	inputType := input.InputType
+	if inputType == "" {
+		inputType = InputTypeImage
+	}
+	source := input.ImageBase64
+	if inputType == InputTypeVideo {
+		source = input.VideoBase64
+	}
+	sourceSum, err := base64SHA256(source)
+	if err != nil {
+		return ""
+	}
+	audioSum, err := base64SHA256(input.AudioBase64)
+	if err != nil {
+		return ""
+	}
+
+	// Empty options are normalized to their defaults so that they hash the
+	// same as the explicit values
+	prompt := input.Prompt
+	if strings.TrimSpace(prompt) == "" {
+		prompt = DefaultPrompt
+	}
+	provider := input.Provider
+	if provider == "" {
+		provider = string(ProviderRunPod)
+	}
+	resizeMode := input.ResizeMode
+	if resizeMode == "" {
+		resizeMode = string(media.ResizeModePad)
+	}
+	personCount := input.PersonCount
+	if personCount == "" {
+		personCount = PersonCountSingle
+	}
+	outputFormat := input.OutputFormat
+	if outputFormat == "" {
+		outputFormat = string(media.OutputFormatMP4)
+	}
+	continuity := input.ContinuityMode
+	if continuity == "" {
+		continuity = ContinuityNone
+	}
+
+	key, _ := json.Marshal(struct {
+		Source       string  `json:"source"`
+		Audio        string  `json:"audio"`
+		InputType    string  `json:"input_type"`
+		Width        int     `json:"width"`
+		Height       int     `json:"height"`
+		Prompt       string  `json:"prompt"`
+		Provider     string  `json:"provider"`
+		PushToS3     bool    `json:"push_to_s3"`
+		DryRun       bool    `json:"dry_run"`
+		ForceOffload bool    `json:"force_offload"`
+		ResizeMode   string  `json:"resize_mode"`
+		PersonCount  string  `json:"person_count"`
+		OutputFormat string  `json:"output_format"`
+		TargetFPS    float64 `json:"target_fps"`
+		StrictDims   bool    `json:"strict_dimensions"`
+		OrigAudio    bool    `json:"use_original_audio"`
+		Continuity   string  `json:"continuity_mode"`
+	}{sourceSum, audioSum, inputType, input.Width, input.Height, prompt, provider,
+		input.PushToS3, input.DryRun, input.ForceOffload, resizeMode, personCount, outputFormat,
+		input.TargetFPS, input.StrictDimensions, input.UseOriginalAudio, continuity})
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:])
+}
+
+// base64SHA256 returns the hex SHA-256 of the data encoded in s.
+func base64SHA256(s string) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, base64.NewDecoder(base64.StdEncoding, strings.NewReader(s))); err != nil {
+		return "", fmt.Errorf("decode base64: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// FindReusableJob returns the most recently completed job with the same
+// InputHash as input whose video is still available, so that its result can
+// be returned instead of processing identical inputs again.
+// Returns ErrJobNotFound when there is none.
+func (s *ProcessVideoService) FindReusableJob(ctx context.Context, input ProcessVideoInput) (*Job, error) {
+	hash := InputHash(input)
+	if hash == "" {
+		return nil, ErrJobNotFound
+	}
+
+	jobs, err := s.repo.FindByInputHash(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("find jobs by input hash: %w", err)
+	}
+
+	now := s.now()
+	var reusable *Job
+	for _, job := range jobs {
+		if job.Status != StatusCompleted || (job.OutputVideoPath == "" && job.S3Key == "") {
+			continue
+		}
+		if !job.ExpiresAt.IsZero() && !now.Before(job.ExpiresAt) {
+			continue
+		}
+		if reusable == nil || job.CompletedAt.After(reusable.CompletedAt) {
+			reusable = job
+		}
+	}
+	if reusable == nil {
+		return nil, ErrJobNotFound
+	}
+
+	s.log(ctx).Info("found completed job with identical inputs",
+		slog.String("job_id", reusable.ID),
+	)
+	return reusable, nil
+}
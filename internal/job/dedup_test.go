@@ -0,0 +1,132 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInputHash(t *testing.T) {
+	base := validateInput()
+	hash := InputHash(base)
+	if hash == "" {
+		t.Fatal("expected a hash for base64 inputs")
+	}
+	if again := InputHash(validateInput()); again != hash {
+		t.Errorf("expected identical inputs to hash alike, got %q and %q", hash, again)
+	}
+
+	defaults := base
+	defaults.Prompt = DefaultPrompt
+	defaults.Provider = string(ProviderRunPod)
+	defaults.ResizeMode = "pad"
+	defaults.PersonCount = PersonCountSingle
+	defaults.InputType = InputTypeImage
+	defaults.OutputFormat = "mp4"
+	defaults.ContinuityMode = ContinuityNone
+	if got := InputHash(defaults); got != hash {
+		t.Errorf("expected explicit defaults to hash like empty options, got %q want %q", got, hash)
+	}
+
+	changes := map[string]func(*ProcessVideoInput){
+		"image":       func(in *ProcessVideoInput) { in.ImageBase64 = "b3RoZXItaW1hZ2U=" },
+		"audio":       func(in *ProcessVideoInput) { in.AudioBase64 = "b3RoZXItYXVkaW8=" },
+		"width":       func(in *ProcessVideoInput) { in.Width = 512 },
+		"prompt":      func(in *ProcessVideoInput) { in.Prompt = "a man singing" },
+		"provider":    func(in *ProcessVideoInput) { in.Provider = string(ProviderBeam) },
+		"resize mode": func(in *ProcessVideoInput) { in.ResizeMode = "crop" },
+		"dry run":     func(in *ProcessVideoInput) { in.DryRun = true },
+		"format":      func(in *ProcessVideoInput) { in.OutputFormat = "webm" },
+		"target fps":  func(in *ProcessVideoInput) { in.TargetFPS = 25 },
+		"strict dims": func(in *ProcessVideoInput) { in.StrictDimensions = true },
+		"orig audio":  func(in *ProcessVideoInput) { in.UseOriginalAudio = true },
+		"continuity":  func(in *ProcessVideoInput) { in.ContinuityMode = ContinuityLastFrame },
+	}
+	for name, change := range changes {
+		t.Run(name, func(t *testing.T) {
+			in := validateInput()
+			change(&in)
+			if got := InputHash(in); got == hash || got == "" {
+				t.Errorf("expected a different hash, got %q", got)
+			}
+		})
+	}
+
+	t.Run("URL inputs are not hashed", func(t *testing.T) {
+		in := validateInput()
+		in.AudioBase64 = ""
+		in.AudioURL = "https://example.com/audio.wav"
+		if got := InputHash(in); got != "" {
+			t.Errorf("expected no hash, got %q", got)
+		}
+	})
+
+	t.Run("invalid base64 is not hashed", func(t *testing.T) {
+		in := validateInput()
+		in.AudioBase64 = "not base64!"
+		if got := InputHash(in); got != "" {
+			t.Errorf("expected no hash, got %q", got)
+		}
+	})
+}
+
+func TestProcessVideoService_FindReusableJob(t *testing.T) {
+	svc, _, _, _, _, repo := newTestService(t)
+	ctx := context.Background()
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	input := validateInput()
+	save := func(status Status, completedAt time.Time, videoPath string, expiresAt time.Time) *Job {
+		t.Helper()
+		job := finishedJob(status, completedAt)
+		job.InputHash = InputHash(input)
+		job.OutputVideoPath = videoPath
+		job.ExpiresAt = expiresAt
+		if err := repo.Save(ctx, job); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return job
+	}
+
+	if _, err := svc.FindReusableJob(ctx, input); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("expected ErrJobNotFound with no jobs, got %v", err)
+	}
+
+	save(StatusFailed, now.Add(-time.Minute), "", time.Time{})
+	save(StatusCompleted, now.Add(-time.Minute), "", time.Time{})                           // video deleted
+	save(StatusCompleted, now.Add(-time.Second), "/tmp/expired.mp4", now.Add(-time.Second)) // expired
+	if _, err := svc.FindReusableJob(ctx, input); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("expected ErrJobNotFound without a usable job, got %v", err)
+	}
+
+	save(StatusCompleted, now.Add(-time.Hour), "/tmp/older.mp4", time.Time{})
+	newer := save(StatusCompleted, now.Add(-time.Minute), "/tmp/newer.mp4", time.Time{})
+
+	got, err := svc.FindReusableJob(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ID != newer.ID {
+		t.Errorf("expected the most recently completed job %s, got %s", newer.ID, got.ID)
+	}
+
+	other := input
+	other.Width = 512
+	if _, err := svc.FindReusableJob(ctx, other); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected different options to bypass dedup, got %v", err)
+	}
+}
+
+func TestProcessVideoService_CreateJob_StoresInputHash(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+
+	job, err := svc.CreateJob(context.Background(), validateInput())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.InputHash != InputHash(validateInput()) {
+		t.Errorf("expected the job to store its input hash, got %q", job.InputHash)
+	}
+}
@@ -0,0 +1,339 @@
+package job
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultDispatcherWorkers is how many jobs are processed at once when no worker count is configured.
+	DefaultDispatcherWorkers = 4
+	// DefaultDispatcherQueueDepth is how many jobs may wait for a worker when no depth is configured.
+	DefaultDispatcherQueueDepth = 100
+)
+
+// Errors returned by Dispatcher.Enqueue and Dispatcher.EnqueueRetry.
+var (
+	// ErrQueueFull is returned when every worker is busy and the queue is at capacity.
+	ErrQueueFull = errors.New("job queue is full")
+	// ErrDispatcherClosed is returned once the dispatcher has been shut down.
+	ErrDispatcherClosed = errors.New("job dispatcher is shut down")
+)
+
+// dispatchTask is a job waiting for a worker.
+type dispatchTask struct {
+	// ctx carries request-scoped values such as the request ID; it is never cancelled.
+	ctx   context.Context
+	jobID string
+	// retry processes the job from the inputs retained on it instead of input.
+	retry bool
+	input ProcessVideoInput
+
+	// priority, createdAt and seq order the task in the queue.
+	priority  Priority
+	createdAt time.Time
+	seq       uint64
+}
+
+// before reports whether t is picked up before u: higher priority first,
+// then older jobs, then the order in which they were enqueued.
+func (t *dispatchTask) before(u *dispatchTask) bool {
+	if tr, ur := t.priority.rank(), u.priority.rank(); tr != ur {
+		return tr < ur
+	}
+	if !t.createdAt.Equal(u.createdAt) {
+		return t.createdAt.Before(u.createdAt)
+	}
+	return t.seq < u.seq
+}
+
+// taskQueue is a heap of dispatch tasks; see dispatchTask.before.
+type taskQueue []*dispatchTask
+
+func (q taskQueue) Len() int           { return len(q) }
+func (q taskQueue) Less(i, j int) bool { return q[i].before(q[j]) }
+func (q taskQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *taskQueue) Push(x any)        { *q = append(*q, x.(*dispatchTask)) }
+func (q *taskQueue) Pop() any {
+	old := *q
+	n := len(old)
+	task := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return task
+}
+
+// Dispatcher processes jobs on a fixed pool of workers fed by a bounded queue,
+// so that bursts of job creation cannot start an unbounded number of workflows.
+// Queued jobs are picked up by priority, then in the order they were created.
+type Dispatcher struct {
+	svc     *ProcessVideoService
+	workers int
+	depth   int
+
+	// mu guards queue, closed, idle and seq; ready is signalled when a task
+	// is queued or the dispatcher is shut down.
+	mu     sync.Mutex
+	ready  *sync.Cond
+	queue  taskQueue
+	closed bool
+	// idle is how many workers are waiting for a task.
+	idle int
+	seq  uint64
+
+	startOnce sync.Once
+	wg        sync.WaitGroup
+
+	// abandon is closed when Shutdown gives up waiting; queued jobs are then
+	// timed out instead of processed.
+	abandon     chan struct{}
+	abandonOnce sync.Once
+}
+
+// DispatcherOption is a function that configures a Dispatcher.
+type DispatcherOption func(*Dispatcher)
+
+// WithDispatcherWorkers sets how many jobs are processed at once. Values < 1 are ignored.
+func WithDispatcherWorkers(n int) DispatcherOption {
+	return func(d *Dispatcher) {
+		if n > 0 {
+			d.workers = n
+		}
+	}
+}
+
+// WithDispatcherQueueDepth sets how many jobs may wait for a free worker
+// before Enqueue fails with ErrQueueFull. Negative values are ignored.
+func WithDispatcherQueueDepth(n int) DispatcherOption {
+	return func(d *Dispatcher) {
+		if n >= 0 {
+			d.depth = n
+		}
+	}
+}
+
+// NewDispatcher creates a Dispatcher that processes jobs of svc.
+// Call Start to launch its workers.
+func NewDispatcher(svc *ProcessVideoService, opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		svc:     svc,
+		workers: DefaultDispatcherWorkers,
+		depth:   DefaultDispatcherQueueDepth,
+		abandon: make(chan struct{}),
+	}
+	d.ready = sync.NewCond(&d.mu)
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Start launches the workers. Calling it more than once has no effect.
+func (d *Dispatcher) Start() {
+	d.startOnce.Do(func() {
+		for range d.workers {
+			d.wg.Add(1)
+			go d.work()
+		}
+	})
+}
+
+// Enqueue queues a job created with ProcessVideoService.CreateJob for
+// processing with input. When the queue is full the job is deleted and
+// ErrQueueFull is returned, so the client can simply resubmit it.
+func (d *Dispatcher) Enqueue(ctx context.Context, jobID string, input ProcessVideoInput) error {
+	err := d.enqueue(ctx, &dispatchTask{jobID: jobID, input: input})
+	if errors.Is(err, ErrQueueFull) || errors.Is(err, ErrDispatcherClosed) {
+		if delErr := d.svc.DeleteJob(context.WithoutCancel(ctx), jobID); delErr != nil {
+			d.svc.log(ctx).Warn("failed to delete job rejected by the dispatcher",
+				slog.String("job_id", jobID),
+				slog.String("error", delErr.Error()),
+			)
+		}
+	}
+	return err
+}
+
+// EnqueueRetry queues a job reset by ProcessVideoService.RetryJob for
+// processing. When the queue is full the job is marked TIMED_OUT again, so it
+// can be retried later, and ErrQueueFull is returned.
+func (d *Dispatcher) EnqueueRetry(ctx context.Context, jobID string) error {
+	err := d.enqueue(ctx, &dispatchTask{jobID: jobID, retry: true})
+	if errors.Is(err, ErrQueueFull) || errors.Is(err, ErrDispatcherClosed) {
+		d.expire(context.WithoutCancel(ctx), jobID, err.Error())
+	}
+	return err
+}
+
+// enqueue adds task to the queue without blocking. The task is ordered by
+// the priority and creation time of its job.
+func (d *Dispatcher) enqueue(ctx context.Context, task *dispatchTask) error {
+	task.ctx = context.WithoutCancel(ctx)
+	task.priority = PriorityNormal
+	task.createdAt = d.svc.now()
+	if job, err := d.svc.repo.FindByID(ctx, task.jobID); err == nil {
+		task.priority = job.Priority
+		task.createdAt = job.CreatedAt
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrDispatcherClosed
+	}
+	// Idle workers take queued tasks right away, so they do not count against the depth
+	if len(d.queue) >= d.depth+d.idle {
+		d.svc.log(ctx).Warn("job queue is full",
+			slog.String("job_id", task.jobID),
+			slog.Int("queue_depth", d.depth),
+		)
+		return ErrQueueFull
+	}
+	d.seq++
+	task.seq = d.seq
+	heap.Push(&d.queue, task)
+	d.ready.Signal()
+	return nil
+}
+
+// QueuePosition returns the 1-based position of a job waiting in the queue,
+// counting the jobs that will be picked up before it. It returns false when
+// the job is not waiting in the queue.
+func (d *Dispatcher) QueuePosition(jobID string) (int, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var task *dispatchTask
+	for _, t := range d.queue {
+		if t.jobID == jobID {
+			task = t
+			break
+		}
+	}
+	if task == nil {
+		return 0, false
+	}
+
+	position := 1
+	for _, t := range d.queue {
+		if t.before(task) {
+			position++
+		}
+	}
+	return position, true
+}
+
+// Shutdown stops accepting jobs and waits until the queued jobs have been
+// processed. If ctx ends first, jobs still waiting in the queue are marked
+// TIMED_OUT instead of processed and ctx's error is returned; jobs already
+// being processed are left to the caller (see ProcessVideoService.TimeoutActiveJobs).
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	d.closed = true
+	d.ready.Broadcast()
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		d.abandonOnce.Do(func() { close(d.abandon) })
+		return fmt.Errorf("shutdown dispatcher: %w", ctx.Err())
+	}
+}
+
+// Wait blocks until all workers have exited, which happens after Shutdown.
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// work processes queued jobs until the dispatcher is shut down and the queue is empty.
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+
+	for {
+		task, ok := d.next()
+		if !ok {
+			return
+		}
+		select {
+		case <-d.abandon:
+			d.expire(task.ctx, task.jobID, "service shut down before the job started")
+			continue
+		default:
+		}
+		d.process(task)
+	}
+}
+
+// next waits for the next task to process. It returns false once the
+// dispatcher is shut down and the queue is empty.
+func (d *Dispatcher) next() (*dispatchTask, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.idle++
+	for len(d.queue) == 0 && !d.closed {
+		d.ready.Wait()
+	}
+	d.idle--
+	if len(d.queue) == 0 {
+		return nil, false
+	}
+	return heap.Pop(&d.queue).(*dispatchTask), true
+}
+
+// process runs the workflow for task.
+func (d *Dispatcher) process(task *dispatchTask) {
+	var err error
+	if task.retry {
+		_, err = d.svc.ProcessRetriedJob(task.ctx, task.jobID)
+	} else {
+		_, err = d.svc.ProcessExistingJob(task.ctx, task.jobID, task.input)
+	}
+	if err != nil {
+		d.svc.log(task.ctx).Error("background processing failed",
+			slog.String("job_id", task.jobID),
+			slog.Bool("retry", task.retry),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// expire marks a job that never left the queue as TIMED_OUT with reason.
+func (d *Dispatcher) expire(ctx context.Context, jobID, reason string) {
+	job, err := d.svc.repo.FindByID(ctx, jobID)
+	if err != nil {
+		return
+	}
+
+	if err := job.Timeout(); err != nil {
+		return // cancelled or picked up in the meantime
+	}
+	job.mu.Lock()
+	job.Error = reason
+	job.mu.Unlock()
+	if err := d.svc.repo.Save(ctx, job); err != nil {
+		d.svc.log(ctx).Error("failed to save expired job",
+			slog.String("job_id", jobID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	d.svc.log(ctx).Warn("queued job timed out",
+		slog.String("job_id", jobID),
+		slog.String("reason", reason),
+	)
+}
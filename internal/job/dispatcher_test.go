@@ -0,0 +1,315 @@
+package job
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// dispatchInput returns job input whose image decodes to name, so that the
+// order in which jobs are processed can be observed through SaveTemp.
+func dispatchInput(name string) ProcessVideoInput {
+	input := validateInput()
+	input.ImageBase64 = base64.StdEncoding.EncodeToString([]byte(name))
+	return input
+}
+
+// createDispatchJob creates a job for input and returns its ID.
+func createDispatchJob(t *testing.T, svc *ProcessVideoService, input ProcessVideoInput) string {
+	t.Helper()
+	job, err := svc.CreateJob(context.Background(), input)
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	return job.ID
+}
+
+// recordImageSaves makes saving an image record its content and fail, which
+// ends the job right after it is picked up. Saves block until release is
+// closed when release is not nil.
+func recordImageSaves(storageClient *mockStorage, release <-chan struct{}) (started <-chan string, order func() []string) {
+	var mu sync.Mutex
+	var seen []string
+	startedCh := make(chan string, 10)
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).
+		Run(func(args mock.Arguments) {
+			data, _ := io.ReadAll(args.Get(2).(io.Reader))
+			mu.Lock()
+			seen = append(seen, string(data))
+			mu.Unlock()
+			startedCh <- string(data)
+			if release != nil {
+				<-release
+			}
+		}).
+		Return("", errors.New("stop"))
+	return startedCh, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), seen...)
+	}
+}
+
+func TestDispatcher_ProcessesJobsInOrder(t *testing.T) {
+	svc, _, _, _, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+	_, order := recordImageSaves(storageClient, nil)
+
+	d := NewDispatcher(svc, WithDispatcherWorkers(1), WithDispatcherQueueDepth(10))
+	names := []string{"first", "second", "third", "fourth"}
+	ids := make([]string, len(names))
+	for i, name := range names {
+		ids[i] = createDispatchJob(t, svc, dispatchInput(name))
+		if err := d.Enqueue(ctx, ids[i], dispatchInput(name)); err != nil {
+			t.Fatalf("enqueue %s: %v", name, err)
+		}
+	}
+
+	d.Start()
+	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := d.Shutdown(shutdownCtx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	got := order()
+	if len(got) != len(names) {
+		t.Fatalf("expected %d jobs processed, got %v", len(names), got)
+	}
+	for i := range names {
+		if got[i] != names[i] {
+			t.Errorf("expected processing order %v, got %v", names, got)
+			break
+		}
+	}
+	for _, id := range ids {
+		job, err := repo.FindByID(ctx, id)
+		if err != nil {
+			t.Fatalf("find job: %v", err)
+		}
+		if job.Status != StatusFailed {
+			t.Errorf("job %s: expected FAILED after processing, got %s", id, job.Status)
+		}
+	}
+}
+
+func TestDispatcher_SkipsJobsCancelledWhileQueued(t *testing.T) {
+	svc, _, _, _, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+	_, order := recordImageSaves(storageClient, nil)
+
+	d := NewDispatcher(svc, WithDispatcherWorkers(1), WithDispatcherQueueDepth(10))
+	names := []string{"first", "cancelled", "last"}
+	ids := make(map[string]string, len(names))
+	for _, name := range names {
+		ids[name] = createDispatchJob(t, svc, dispatchInput(name))
+		if err := d.Enqueue(ctx, ids[name], dispatchInput(name)); err != nil {
+			t.Fatalf("enqueue %s: %v", name, err)
+		}
+	}
+	if _, err := svc.CancelJob(ctx, ids["cancelled"]); err != nil {
+		t.Fatalf("cancel queued job: %v", err)
+	}
+
+	d.Start()
+	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := d.Shutdown(shutdownCtx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	if got := order(); len(got) != 2 || got[0] != "first" || got[1] != "last" {
+		t.Errorf("expected only first and last to be processed, got %v", got)
+	}
+	job, err := repo.FindByID(ctx, ids["cancelled"])
+	if err != nil {
+		t.Fatalf("find job: %v", err)
+	}
+	if job.Status != StatusCancelled {
+		t.Errorf("expected CANCELLED, got %s", job.Status)
+	}
+	if !job.StartedAt.IsZero() {
+		t.Errorf("expected the cancelled job never to start, started at %v", job.StartedAt)
+	}
+}
+
+func TestDispatcher_QueueFull(t *testing.T) {
+	svc, _, _, _, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+	release := make(chan struct{})
+	started, _ := recordImageSaves(storageClient, release)
+
+	d := NewDispatcher(svc, WithDispatcherWorkers(1), WithDispatcherQueueDepth(1))
+	d.Start()
+	defer func() {
+		close(release)
+		_ = d.Shutdown(ctx)
+	}()
+
+	// The only worker is busy with the first job and the second fills the queue
+	running := createDispatchJob(t, svc, dispatchInput("running"))
+	if err := d.Enqueue(ctx, running, dispatchInput("running")); err != nil {
+		t.Fatalf("enqueue running job: %v", err)
+	}
+	<-started
+	queued := createDispatchJob(t, svc, dispatchInput("queued"))
+	if err := d.Enqueue(ctx, queued, dispatchInput("queued")); err != nil {
+		t.Fatalf("enqueue queued job: %v", err)
+	}
+
+	rejected := createDispatchJob(t, svc, dispatchInput("rejected"))
+	if err := d.Enqueue(ctx, rejected, dispatchInput("rejected")); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+	if _, err := repo.FindByID(ctx, rejected); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected rejected job to be deleted, got %v", err)
+	}
+	if _, err := repo.FindByID(ctx, queued); err != nil {
+		t.Errorf("expected queued job to be kept, got %v", err)
+	}
+}
+
+func TestDispatcher_RetryQueueFull(t *testing.T) {
+	svc, _, _, _, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+	release := make(chan struct{})
+	started, _ := recordImageSaves(storageClient, release)
+
+	d := NewDispatcher(svc, WithDispatcherWorkers(1), WithDispatcherQueueDepth(1))
+	d.Start()
+	defer func() {
+		close(release)
+		_ = d.Shutdown(ctx)
+	}()
+
+	for _, name := range []string{"running", "queued"} {
+		id := createDispatchJob(t, svc, dispatchInput(name))
+		if err := d.Enqueue(ctx, id, dispatchInput(name)); err != nil {
+			t.Fatalf("enqueue %s job: %v", name, err)
+		}
+		if name == "running" {
+			<-started
+		}
+	}
+
+	retried := New()
+	_ = repo.Save(ctx, retried)
+	if err := d.EnqueueRetry(ctx, retried.ID); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, retried.ID)
+	if err != nil {
+		t.Fatalf("find job: %v", err)
+	}
+	if found.Status != StatusTimedOut || found.Error != ErrQueueFull.Error() {
+		t.Errorf("expected TIMED_OUT with %q, got %s with %q", ErrQueueFull, found.Status, found.Error)
+	}
+	if !found.IsRetryable() {
+		t.Error("expected the job to be retryable again")
+	}
+}
+
+func TestDispatcher_ShutdownDeadlineTimesOutQueuedJobs(t *testing.T) {
+	svc, _, _, _, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+	release := make(chan struct{})
+	started, _ := recordImageSaves(storageClient, release)
+
+	d := NewDispatcher(svc, WithDispatcherWorkers(1), WithDispatcherQueueDepth(5))
+	d.Start()
+
+	running := createDispatchJob(t, svc, dispatchInput("running"))
+	_ = d.Enqueue(ctx, running, dispatchInput("running"))
+	<-started
+	queued := createDispatchJob(t, svc, dispatchInput("queued"))
+	_ = d.Enqueue(ctx, queued, dispatchInput("queued"))
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := d.Shutdown(shutdownCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	if err := d.Enqueue(ctx, "job-late", dispatchInput("late")); !errors.Is(err, ErrDispatcherClosed) {
+		t.Errorf("expected ErrDispatcherClosed after shutdown, got %v", err)
+	}
+
+	close(release)
+	d.Wait()
+
+	found, err := repo.FindByID(ctx, queued)
+	if err != nil {
+		t.Fatalf("find job: %v", err)
+	}
+	if found.Status != StatusTimedOut {
+		t.Errorf("expected queued job to be TIMED_OUT, got %s", found.Status)
+	}
+	select {
+	case name := <-started:
+		t.Errorf("expected queued job not to be processed, but %q was", name)
+	default:
+	}
+}
+
+func TestDispatcher_HighPriorityJumpsAhead(t *testing.T) {
+	svc, _, _, _, storageClient, _ := newTestService(t)
+	ctx := context.Background()
+	_, order := recordImageSaves(storageClient, nil)
+
+	d := NewDispatcher(svc, WithDispatcherWorkers(1), WithDispatcherQueueDepth(10))
+	jobs := []struct {
+		name     string
+		priority string
+	}{
+		{"low", "low"},
+		{"normal-1", ""},
+		{"normal-2", "normal"},
+		{"normal-3", ""},
+		{"high", "high"},
+	}
+	ids := make(map[string]string, len(jobs))
+	for _, j := range jobs {
+		input := dispatchInput(j.name)
+		input.Priority = j.priority
+		ids[j.name] = createDispatchJob(t, svc, input)
+		if err := d.Enqueue(ctx, ids[j.name], input); err != nil {
+			t.Fatalf("enqueue %s: %v", j.name, err)
+		}
+	}
+
+	if pos, ok := d.QueuePosition(ids["high"]); !ok || pos != 1 {
+		t.Errorf("expected the high-priority job at position 1, got %d (%v)", pos, ok)
+	}
+	if pos, ok := d.QueuePosition(ids["low"]); !ok || pos != 5 {
+		t.Errorf("expected the low-priority job at position 5, got %d (%v)", pos, ok)
+	}
+
+	d.Start()
+	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := d.Shutdown(shutdownCtx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	want := []string{"high", "normal-1", "normal-2", "normal-3", "low"}
+	got := order()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d jobs processed, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected processing order %v, got %v", want, got)
+			break
+		}
+	}
+	if _, ok := d.QueuePosition(ids["low"]); ok {
+		t.Error("expected no queue position once the job was picked up")
+	}
+}
@@ -0,0 +1,213 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultJanitorInterval is how often the janitor sweeps when no interval is configured.
+const DefaultJanitorInterval = 5 * time.Minute
+
+// Janitor periodically removes finished jobs older than a TTL, or past the
+// expiry of their own retention, together with their artifacts, and deletes
+// orphaned files left in the temp directory.
+type Janitor struct {
+	svc *ProcessVideoService
+	// ttl is how long after a job finishes it is kept.
+	ttl time.Duration
+	// interval is the time between sweeps.
+	interval time.Duration
+	// tempDir is swept for orphaned files. Empty disables the temp sweep.
+	tempDir string
+	// now returns the current time; replaced in tests.
+	now func() time.Time
+}
+
+// JanitorOption is a function that configures a Janitor.
+type JanitorOption func(*Janitor)
+
+// WithJanitorInterval sets the time between sweeps. Values <= 0 are ignored.
+func WithJanitorInterval(d time.Duration) JanitorOption {
+	return func(j *Janitor) {
+		if d > 0 {
+			j.interval = d
+		}
+	}
+}
+
+// WithJanitorTempDir sets the directory swept for orphaned temp files.
+func WithJanitorTempDir(dir string) JanitorOption {
+	return func(j *Janitor) {
+		j.tempDir = dir
+	}
+}
+
+// NewJanitor creates a Janitor that deletes jobs of svc once they have been
+// finished for longer than ttl. Jobs with their own retention are deleted
+// once past their ExpiresAt instead; a zero ttl only deletes those.
+func NewJanitor(svc *ProcessVideoService, ttl time.Duration, opts ...JanitorOption) *Janitor {
+	j := &Janitor{
+		svc:      svc,
+		ttl:      ttl,
+		interval: DefaultJanitorInterval,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(j)
+	}
+	return j
+}
+
+// Run sweeps on every interval until ctx is cancelled.
+func (j *Janitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.Sweep(ctx)
+		}
+	}
+}
+
+// Sweep deletes expired jobs through the regular delete flow, then removes
+// temp files older than the TTL that no remaining job refers to.
+func (j *Janitor) Sweep(ctx context.Context) {
+	jobs, err := j.svc.repo.List(ctx)
+	if err != nil {
+		j.svc.logger.Warn("janitor failed to list jobs",
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	now := j.now()
+	remaining := make([]*Job, 0, len(jobs))
+	expired := 0
+	for _, job := range jobs {
+		if !j.expired(job, now) {
+			remaining = append(remaining, job)
+			continue
+		}
+		if err := j.svc.DeleteJob(ctx, job.ID); err != nil {
+			j.svc.logger.Warn("janitor failed to delete expired job",
+				slog.String("job_id", job.ID),
+				slog.String("error", err.Error()),
+			)
+			remaining = append(remaining, job)
+			continue
+		}
+		expired++
+	}
+
+	removed := j.sweepTempDir(remaining, now)
+
+	if expired > 0 || removed > 0 {
+		j.svc.logger.Info("janitor sweep completed",
+			slog.Int("expired_jobs", expired),
+			slog.Int("removed_files", removed),
+		)
+	}
+}
+
+// expired reports whether job has passed the ExpiresAt of its own retention,
+// or, when it has none, finished more than the TTL before now. Jobs that are
+// still queued or running never expire.
+func (j *Janitor) expired(job *Job, now time.Time) bool {
+	if !job.IsTerminal() || job.CompletedAt.IsZero() {
+		return false
+	}
+	// A job deleted after download keeps the TTL until it is downloaded
+	if job.Retention > 0 || (job.DeleteAfterDownload && !job.VideoDownloadedAt.IsZero()) {
+		return !job.ExpiresAt.IsZero() && !now.Before(job.ExpiresAt)
+	}
+	if j.ttl <= 0 {
+		return false
+	}
+	return now.Sub(job.CompletedAt) > j.ttl
+}
+
+// sweepTempDir removes regular files in the temp directory that are older
+// than the TTL and do not belong to any of jobs, and returns how many it removed.
+func (j *Janitor) sweepTempDir(jobs []*Job, now time.Time) int {
+	if j.tempDir == "" || j.ttl <= 0 {
+		return 0
+	}
+
+	entries, err := os.ReadDir(j.tempDir)
+	if err != nil {
+		j.svc.logger.Warn("janitor failed to read temp dir",
+			slog.String("temp_dir", j.tempDir),
+			slog.String("error", err.Error()),
+		)
+		return 0
+	}
+
+	referenced := referencedFiles(jobs)
+	removed := 0
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if !j.orphaned(entry.Name(), info.ModTime(), now, referenced, jobs) {
+			continue
+		}
+
+		path := filepath.Join(j.tempDir, entry.Name())
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			j.svc.logger.Warn("janitor failed to remove temp file",
+				slog.String("path", path),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		removed++
+	}
+	return removed
+}
+
+// orphaned reports whether a temp file may be removed: it is older than the
+// TTL, no job refers to it and its name does not contain a job's ID.
+func (j *Janitor) orphaned(name string, modTime, now time.Time, referenced map[string]bool, jobs []*Job) bool {
+	if now.Sub(modTime) <= j.ttl || referenced[name] {
+		return false
+	}
+	for _, job := range jobs {
+		if strings.Contains(name, job.ID) {
+			return false
+		}
+	}
+	return true
+}
+
+// referencedFiles returns the base names of the files that jobs refer to.
+func referencedFiles(jobs []*Job) map[string]bool {
+	referenced := make(map[string]bool)
+	add := func(path string) {
+		if path != "" {
+			referenced[filepath.Base(path)] = true
+		}
+	}
+	for _, job := range jobs {
+		add(job.InputImagePath)
+		add(job.InputAudioPath)
+		add(job.OutputVideoPath)
+		add(job.ThumbnailPath)
+		for _, chunk := range job.Chunks {
+			add(chunk.InputPath)
+			add(chunk.OutputPath)
+		}
+	}
+	return referenced
+}
@@ -0,0 +1,253 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJanitor_Expired(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+	j := NewJanitor(svc, time.Hour)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		status      Status
+		completedAt time.Time
+		want        bool
+	}{
+		{"completed past ttl", StatusCompleted, now.Add(-2 * time.Hour), true},
+		{"failed past ttl", StatusFailed, now.Add(-2 * time.Hour), true},
+		{"cancelled past ttl", StatusCancelled, now.Add(-61 * time.Minute), true},
+		{"completed within ttl", StatusCompleted, now.Add(-30 * time.Minute), false},
+		{"completed exactly at ttl", StatusCompleted, now.Add(-time.Hour), false},
+		{"running", StatusRunning, now.Add(-2 * time.Hour), false},
+		{"queued", StatusInQueue, time.Time{}, false},
+		{"terminal without completion time", StatusCompleted, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := New()
+			job.Status = tt.status
+			job.CompletedAt = tt.completedAt
+			if got := j.expired(job, now); got != tt.want {
+				t.Errorf("expired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJanitor_Expired_ZeroTTL(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+	j := NewJanitor(svc, 0)
+
+	job := finishedJob(StatusCompleted, time.Now().Add(-24*time.Hour))
+	if j.expired(job, time.Now()) {
+		t.Error("expected jobs never to expire with a zero TTL")
+	}
+}
+
+func TestJanitor_Expired_JobRetention(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	completed := now.Add(-2 * time.Hour)
+
+	tests := []struct {
+		name       string
+		ttl        time.Duration
+		retention  time.Duration
+		deleteDL   bool
+		downloaded time.Time
+		expiresAt  time.Time
+		want       bool
+	}{
+		{name: "longer retention outlives the TTL", ttl: time.Hour, retention: 24 * time.Hour, expiresAt: now.Add(20 * time.Hour), want: false},
+		{name: "shorter retention expires within the TTL", ttl: 24 * time.Hour, retention: time.Hour, expiresAt: now.Add(-time.Minute), want: true},
+		{name: "retention without a TTL", retention: time.Hour, expiresAt: now.Add(-time.Minute), want: true},
+		{name: "retention exactly at expiry", ttl: 24 * time.Hour, retention: time.Hour, expiresAt: now, want: true},
+		{name: "downloaded with delete after download", ttl: 24 * time.Hour, deleteDL: true, downloaded: now.Add(-time.Second), expiresAt: now.Add(-time.Second), want: true},
+		{name: "not downloaded falls back to the TTL", ttl: 24 * time.Hour, deleteDL: true, expiresAt: now.Add(20 * time.Hour), want: false},
+		{name: "not downloaded past the TTL", ttl: time.Hour, deleteDL: true, expiresAt: now.Add(-time.Hour), want: true},
+		{name: "not downloaded without a TTL", deleteDL: true, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := NewJanitor(svc, tt.ttl)
+			job := finishedJob(StatusCompleted, completed)
+			job.Retention = tt.retention
+			job.DeleteAfterDownload = tt.deleteDL
+			job.VideoDownloadedAt = tt.downloaded
+			job.ExpiresAt = tt.expiresAt
+			if got := j.expired(job, now); got != tt.want {
+				t.Errorf("expired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("running jobs never expire", func(t *testing.T) {
+		job := New()
+		_ = job.Start()
+		job.Retention = time.Minute
+		job.ExpiresAt = now.Add(-time.Hour)
+		if NewJanitor(svc, time.Hour).expired(job, now) {
+			t.Error("expected a running job not to expire")
+		}
+	})
+}
+
+func TestJanitor_Orphaned(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+	j := NewJanitor(svc, time.Hour)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-2 * time.Hour)
+
+	running := NewWithID("job-running")
+	referenced := map[string]bool{"audio.wav_123": true}
+	jobs := []*Job{running}
+
+	tests := []struct {
+		name    string
+		file    string
+		modTime time.Time
+		want    bool
+	}{
+		{"old unreferenced file", "image.png_456", old, true},
+		{"recent file", "image.png_789", now.Add(-time.Minute), false},
+		{"referenced by a job", "audio.wav_123", old, false},
+		{"named after a job", "output_job-running.mp4", old, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := j.orphaned(tt.file, tt.modTime, now, referenced, jobs); got != tt.want {
+				t.Errorf("orphaned() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJanitor_Sweep(t *testing.T) {
+	svc, _, _, _, _, repo := newTestService(t)
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	now := time.Now()
+
+	expiredJob := finishedJob(StatusCompleted, now.Add(-2*time.Hour))
+	recentJob := finishedJob(StatusCompleted, now.Add(-time.Minute))
+	runningJob := New()
+	_ = runningJob.Start()
+	runningJob.InputAudioPath = filepath.Join(tempDir, "audio.wav_running")
+	for _, job := range []*Job{expiredJob, recentJob, runningJob} {
+		_ = repo.Save(ctx, job)
+	}
+
+	old := now.Add(-2 * time.Hour)
+	files := map[string]bool{ // name -> expected to survive
+		"image.png_orphan":                 false,
+		"audio.wav_running":                true,
+		"output_" + recentJob.ID + ".mp4":  true,
+		"output_" + expiredJob.ID + ".mp4": false,
+	}
+	for name := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte("data"), 0600); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "image.png_fresh"), []byte("data"), 0600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	files["image.png_fresh"] = true
+
+	j := NewJanitor(svc, time.Hour, WithJanitorTempDir(tempDir))
+	j.now = func() time.Time { return now }
+	j.Sweep(ctx)
+
+	if _, err := repo.FindByID(ctx, expiredJob.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected expired job to be deleted, got %v", err)
+	}
+	for _, job := range []*Job{recentJob, runningJob} {
+		if _, err := repo.FindByID(ctx, job.ID); err != nil {
+			t.Errorf("expected job %s to be kept, got %v", job.ID, err)
+		}
+	}
+
+	for name, keep := range files {
+		_, err := os.Stat(filepath.Join(tempDir, name))
+		if exists := err == nil; exists != keep {
+			t.Errorf("file %s: exists = %v, want %v", name, exists, keep)
+		}
+	}
+}
+
+func TestJanitor_Sweep_JobRetention(t *testing.T) {
+	svc, _, _, _, _, repo := newTestService(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	// Within the TTL, but past its own shorter retention
+	shortRetention := finishedJob(StatusCompleted, now.Add(-time.Minute))
+	shortRetention.Retention = time.Minute
+	shortRetention.ExpiresAt = now.Add(-time.Second)
+	// Past the TTL, but within its own longer retention
+	longRetention := finishedJob(StatusCompleted, now.Add(-2*time.Hour))
+	longRetention.Retention = 48 * time.Hour
+	longRetention.ExpiresAt = now.Add(46 * time.Hour)
+	// Downloaded, so expired regardless of the TTL
+	downloaded := finishedJob(StatusCompleted, now.Add(-time.Minute))
+	downloaded.DeleteAfterDownload = true
+	downloaded.VideoDownloadedAt = now.Add(-time.Second)
+	downloaded.ExpiresAt = downloaded.VideoDownloadedAt
+	// Not downloaded yet, and within the TTL
+	notDownloaded := finishedJob(StatusCompleted, now.Add(-time.Minute))
+	notDownloaded.DeleteAfterDownload = true
+	notDownloaded.ExpiresAt = now.Add(time.Hour)
+	for _, job := range []*Job{shortRetention, longRetention, downloaded, notDownloaded} {
+		_ = repo.Save(ctx, job)
+	}
+
+	j := NewJanitor(svc, time.Hour)
+	j.now = func() time.Time { return now }
+	j.Sweep(ctx)
+
+	for _, job := range []*Job{shortRetention, downloaded} {
+		if _, err := repo.FindByID(ctx, job.ID); !errors.Is(err, ErrJobNotFound) {
+			t.Errorf("expected job %s to be deleted, got %v", job.ID, err)
+		}
+	}
+	for _, job := range []*Job{longRetention, notDownloaded} {
+		if _, err := repo.FindByID(ctx, job.ID); err != nil {
+			t.Errorf("expected job %s to be kept, got %v", job.ID, err)
+		}
+	}
+}
+
+func TestJanitor_Run_StopsOnCancel(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+	j := NewJanitor(svc, time.Hour, WithJanitorInterval(time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		j.Run(ctx)
+		close(done)
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancellation")
+	}
+}
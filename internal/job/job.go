@@ -5,6 +5,8 @@ package job
 
 import (
 	"errors"
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
@@ -26,6 +28,81 @@ func (p Provider) IsValid() bool {
 	return p == ProviderRunPod || p == ProviderBeam
 }
 
+// DefaultPrompt is the generation prompt used when a job does not set one.
+const DefaultPrompt = "high quality, realistic, speaking naturally"
+
+// Input types of the source media a job animates.
+const (
+	// InputTypeImage animates a still image (default).
+	InputTypeImage = "image"
+	// InputTypeVideo re-lip-syncs an existing talking video.
+	InputTypeVideo = "video"
+)
+
+// Person counts accepted by the provider.
+const (
+	// PersonCountSingle animates one person (default).
+	PersonCountSingle = "single"
+	// PersonCountMulti animates several people speaking in the same image.
+	PersonCountMulti = "multi"
+)
+
+// Continuity modes select the source image each chunk is animated from.
+const (
+	// ContinuityNone animates every chunk from the resized source image (default).
+	ContinuityNone = "none"
+	// ContinuityLastFrame animates each chunk from the last frame of the
+	// previous chunk's video, so motion carries over between chunks. The
+	// chunks are then generated one at a time.
+	ContinuityLastFrame = "last_frame"
+)
+
+// Priority orders queued jobs: higher-priority jobs are picked up first.
+type Priority string
+
+const (
+	// PriorityHigh is for interactive jobs a user is waiting on.
+	PriorityHigh Priority = "high"
+	// PriorityNormal is the default priority.
+	PriorityNormal Priority = "normal"
+	// PriorityLow is for batch jobs that can wait.
+	PriorityLow Priority = "low"
+)
+
+// IsValid returns true if the priority is valid.
+func (p Priority) IsValid() bool {
+	return p == PriorityHigh || p == PriorityNormal || p == PriorityLow
+}
+
+// rank returns how soon jobs of priority p are picked up; lower is sooner.
+// Unknown priorities rank as normal.
+func (p Priority) rank() int {
+	switch p {
+	case PriorityHigh:
+		return 0
+	case PriorityLow:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Stage is the processing phase of a RUNNING job.
+type Stage string
+
+const (
+	// StagePreprocessing covers saving, probing and resizing the inputs and splitting the audio.
+	StagePreprocessing Stage = "preprocessing"
+	// StageGenerating is while the provider generates the chunk videos.
+	StageGenerating Stage = "generating"
+	// StageJoining is while the chunk videos are joined into the output video.
+	StageJoining Stage = "joining"
+	// StageUploading is while the output video is uploaded to remote storage.
+	StageUploading Stage = "uploading"
+	// StageDone is set once the job completed.
+	StageDone Stage = "done"
+)
+
 // Status represents the current state of a Job.
 // States are aligned with RunPod job states.
 type Status string
@@ -86,6 +163,30 @@ const (
 	ChunkStatusFailed ChunkStatus = "FAILED"
 )
 
+// FailureStage identifies the processing step in which a chunk failed.
+type FailureStage string
+
+const (
+	// FailureStagePrepare indicates the chunk input could not be prepared.
+	FailureStagePrepare FailureStage = "prepare"
+	// FailureStageSubmit indicates the provider rejected the submission.
+	FailureStageSubmit FailureStage = "submit"
+	// FailureStagePoll indicates the provider job failed or polling stopped.
+	FailureStagePoll FailureStage = "poll"
+	// FailureStageOutput indicates the provider output could not be retrieved.
+	FailureStageOutput FailureStage = "output"
+)
+
+// ChunkFailure carries structured details about why a chunk failed.
+type ChunkFailure struct {
+	// Stage is the step in which the chunk failed.
+	Stage FailureStage
+	// ProviderStatus is the last status reported by the provider, if any.
+	ProviderStatus string
+	// ProviderError is the error message reported by the provider, if any.
+	ProviderError string
+}
+
 // Chunk represents a segment of audio/video being processed.
 type Chunk struct {
 	// ID is the unique identifier for this chunk.
@@ -102,10 +203,24 @@ type Chunk struct {
 	RunPodJobID string
 	// Error contains any error message if processing failed.
 	Error string
-	// StartedAt is when chunk processing started.
+	// StartedAt is when the chunk was last submitted to the provider.
 	StartedAt time.Time
 	// CompletedAt is when chunk processing finished.
 	CompletedAt time.Time
+	// CancelRequested is true when a provider cancel was issued for this chunk.
+	CancelRequested bool
+	// CancelConfirmed is true when the provider acknowledged the cancel request.
+	CancelConfirmed bool
+	// CancelError contains the provider error if the cancel request failed.
+	CancelError string
+	// Attempts is the number of times this chunk was submitted to the provider.
+	Attempts int
+	// FailureStage is the step in which the chunk failed.
+	FailureStage FailureStage
+	// ProviderStatus is the last provider status seen when the chunk failed.
+	ProviderStatus string
+	// ProviderError is the error message reported by the provider when the chunk failed.
+	ProviderError string
 }
 
 // Job represents a video generation job aggregate.
@@ -123,11 +238,15 @@ type Job struct {
 	Chunks []Chunk
 	// Progress is the percentage of completion (0-100).
 	Progress int
+	// Stage is the processing phase the job reached. Empty until processing
+	// starts; kept when the job fails so it shows where processing stopped.
+	Stage Stage
 	// Error contains any error message if the job failed.
 	Error string
 	// Prompt is the text prompt for video generation.
 	Prompt string
-	// InputImagePath is the path to the source image.
+	// InputImagePath is the path to the source image, or to the source video
+	// when InputType is InputTypeVideo.
 	InputImagePath string
 	// InputAudioPath is the path to the source audio.
 	InputAudioPath string
@@ -139,8 +258,48 @@ type Job struct {
 	Height int
 	// PushToS3 indicates whether to upload the result to S3.
 	PushToS3 bool
-	// VideoURL is the S3 URL if PushToS3 was true.
-	VideoURL string
+	// DryRun skips provider calls and completes after preprocessing.
+	DryRun bool
+	// ForceOffload is passed to the provider to offload the model after processing.
+	ForceOffload bool
+	// ResizeMode selects how the input image is fitted ("pad" or "crop").
+	ResizeMode string
+	// PersonCount is the number of people the provider animates ("single" or "multi").
+	PersonCount string
+	// InputType is the type of the source media ("image" or "video").
+	InputType string
+	// Priority orders the job in the dispatch queue.
+	Priority Priority
+	// OutputFormat is the container of the output video ("mp4", "mov" or "webm").
+	OutputFormat string
+	// TargetFPS is the frame rate chunk videos are normalized to before joining.
+	// Zero keeps the provider's frame rate.
+	TargetFPS float64
+	// StrictDimensions fails the job when a chunk video does not have the
+	// requested dimensions instead of resizing it.
+	StrictDimensions bool
+	// UseOriginalAudio replaces the audio of the joined video with the
+	// uploaded audio track.
+	UseOriginalAudio bool
+	// ContinuityMode selects the source image of each chunk ("none" or "last_frame").
+	ContinuityMode string
+	// InputHash identifies the inputs and options the job was created with
+	// (see InputHash). Empty when the inputs could not be hashed.
+	InputHash string
+	// Metadata holds client-defined key/value pairs, such as a customer ID,
+	// that are stored with the job and returned unchanged.
+	Metadata map[string]string
+	// S3Key is the storage key the output video was uploaded under if PushToS3 was true.
+	// Only the key is stored: URLs are resolved when served, as presigned ones expire.
+	S3Key string
+	// ThumbnailPath is the path to the preview image of the output video.
+	ThumbnailPath string
+	// ThumbnailKey is the storage key the preview image was uploaded under if PushToS3 was true.
+	ThumbnailKey string
+	// TempFiles are the temporary files created while processing the job, such
+	// as the resized image and the audio chunks. They are removed when
+	// processing ends, or as soon as the job is cancelled.
+	TempFiles []string
 	// CreatedAt is when the job was created.
 	CreatedAt time.Time
 	// UpdatedAt is when the job was last updated.
@@ -149,6 +308,22 @@ type Job struct {
 	StartedAt time.Time
 	// CompletedAt is when processing finished.
 	CompletedAt time.Time
+	// ExpiresAt is when the job and its artifacts may be purged.
+	// Zero means the job does not expire.
+	ExpiresAt time.Time
+	// Retention is how long after creation the job is kept, as requested for
+	// this job. Zero uses the service's job TTL.
+	Retention time.Duration
+	// DeleteAfterDownload makes the job expire as soon as its video has been
+	// downloaded, instead of after a retention period.
+	DeleteAfterDownload bool
+	// VideoDownloadedAt is when the output video was first downloaded in full
+	// through the API. Zero if it has not been.
+	VideoDownloadedAt time.Time
+	// Logs captures the log lines of the job. It is shared by clones, so
+	// lines logged during processing are visible to every copy. Nil when
+	// log capture is disabled.
+	Logs *LogBuffer
 }
 
 // New creates a new Job with a generated ID and initial IN_QUEUE status.
@@ -158,6 +333,7 @@ func New() *Job {
 	return &Job{
 		ID:        id.Generate(),
 		Provider:  ProviderRunPod,
+		Priority:  PriorityNormal,
 		Status:    StatusInQueue,
 		Chunks:    make([]Chunk, 0),
 		CreatedAt: now,
@@ -173,6 +349,7 @@ func NewWithID(jobID string) *Job {
 	return &Job{
 		ID:        jobID,
 		Provider:  ProviderRunPod,
+		Priority:  PriorityNormal,
 		Status:    StatusInQueue,
 		Chunks:    make([]Chunk, 0),
 		CreatedAt: now,
@@ -237,6 +414,40 @@ func (j *Job) Timeout() error {
 	return j.TransitionTo(StatusTimedOut)
 }
 
+// IsRetryable reports whether the job ended in a state that RetryJob can restart.
+func (j *Job) IsRetryable() bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.Status == StatusFailed || j.Status == StatusTimedOut
+}
+
+// ResetForRetry moves a FAILED or TIMED_OUT job back to IN_QUEUE and clears
+// the results of the previous run. Input paths and settings are kept.
+// Returns ErrInvalidTransition for any other status.
+func (j *Job) ResetForRetry() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.Status != StatusFailed && j.Status != StatusTimedOut {
+		return ErrInvalidTransition
+	}
+
+	j.Status = StatusInQueue
+	j.Chunks = make([]Chunk, 0)
+	j.Progress = 0
+	j.Stage = ""
+	j.Error = ""
+	j.OutputVideoPath = ""
+	j.S3Key = ""
+	j.ThumbnailPath = ""
+	j.ThumbnailKey = ""
+	j.TempFiles = nil
+	j.StartedAt = time.Time{}
+	j.CompletedAt = time.Time{}
+	j.UpdatedAt = time.Now()
+	return nil
+}
+
 // GetStatus returns the current job status (thread-safe).
 func (j *Job) GetStatus() Status {
 	j.mu.RLock()
@@ -262,6 +473,44 @@ func (j *Job) UpdateChunk(index int, chunk Chunk) {
 	}
 }
 
+// MarkPendingChunkCancels flags every chunk that was submitted to the provider
+// but has not finished as needing a provider cancel, and returns copies of them.
+func (j *Job) MarkPendingChunkCancels() []Chunk {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var pending []Chunk
+	for i := range j.Chunks {
+		c := &j.Chunks[i]
+		if c.RunPodJobID == "" || c.Status == ChunkStatusCompleted || c.CancelRequested {
+			continue
+		}
+		c.CancelRequested = true
+		pending = append(pending, *c)
+	}
+	if len(pending) > 0 {
+		j.UpdatedAt = time.Now()
+	}
+	return pending
+}
+
+// SetChunkCancelResult records the outcome of a provider cancel request for a chunk.
+// A nil err marks the cancel as confirmed.
+func (j *Job) SetChunkCancelResult(index int, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if index < 0 || index >= len(j.Chunks) {
+		return
+	}
+	if err != nil {
+		j.Chunks[index].CancelConfirmed = false
+		j.Chunks[index].CancelError = err.Error()
+	} else {
+		j.Chunks[index].CancelConfirmed = true
+		j.Chunks[index].CancelError = ""
+	}
+	j.UpdatedAt = time.Now()
+}
+
 // UpdateProgress sets the progress percentage (0-100).
 func (j *Job) UpdateProgress(progress int) {
 	j.mu.Lock()
@@ -276,22 +525,155 @@ func (j *Job) UpdateProgress(progress int) {
 	j.UpdatedAt = time.Now()
 }
 
-// SetOutput sets the output video path and optional S3 URL.
-func (j *Job) SetOutput(videoPath, videoURL string) {
+// SetStage records the processing phase the job entered.
+func (j *Job) SetStage(stage Stage) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Stage = stage
+	j.UpdatedAt = time.Now()
+}
+
+// GetStage returns the current processing phase (thread-safe).
+func (j *Job) GetStage() Stage {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.Stage
+}
+
+// etaWindow is how many of the most recently completed chunks the
+// remaining-time estimate averages over.
+const etaWindow = 5
+
+// EstimateRemaining estimates how long the job's remaining chunks will take,
+// from the average duration of the most recently completed chunks. Chunks
+// still processing are credited with the time they have already run, and
+// the work is spread over up to parallelism chunks at once. It reports false
+// when the job is not running or no chunk has completed yet.
+func (j *Job) EstimateRemaining(now time.Time, parallelism int) (time.Duration, bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if j.Status != StatusRunning {
+		return 0, false
+	}
+
+	var completed []Chunk
+	for _, c := range j.Chunks {
+		if c.Status == ChunkStatusCompleted && !c.StartedAt.IsZero() && c.CompletedAt.After(c.StartedAt) {
+			completed = append(completed, c)
+		}
+	}
+	if len(completed) == 0 {
+		return 0, false
+	}
+	slices.SortFunc(completed, func(a, b Chunk) int { return a.CompletedAt.Compare(b.CompletedAt) })
+	completed = completed[max(len(completed)-etaWindow, 0):]
+
+	var total time.Duration
+	for _, c := range completed {
+		total += c.CompletedAt.Sub(c.StartedAt)
+	}
+	avg := total / time.Duration(len(completed))
+
+	var remaining time.Duration
+	var left int
+	for _, c := range j.Chunks {
+		switch c.Status {
+		case ChunkStatusPending:
+			remaining += avg
+			left++
+		case ChunkStatusProcessing:
+			if !c.StartedAt.IsZero() {
+				remaining += max(avg-now.Sub(c.StartedAt), 0)
+			} else {
+				remaining += avg
+			}
+			left++
+		}
+	}
+	if left == 0 {
+		return 0, true
+	}
+	return remaining / time.Duration(min(max(parallelism, 1), left)), true
+}
+
+// SetOutput sets the output video path.
+func (j *Job) SetOutput(videoPath string) {
 	j.mu.Lock()
 	defer j.mu.Unlock()
 	j.OutputVideoPath = videoPath
-	j.VideoURL = videoURL
 	j.UpdatedAt = time.Now()
 }
 
-// ClearOutput clears the output video path and URL.
+// AddTempFiles records temporary files created while processing the job.
+func (j *Job) AddTempFiles(paths ...string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.TempFiles = append(j.TempFiles, paths...)
+}
+
+// GetTempFiles returns the temporary files recorded for the job.
+func (j *Job) GetTempFiles() []string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return slices.Clone(j.TempFiles)
+}
+
+// PartialArtifacts returns the recorded temporary files of the job along with
+// the audio and video files of its chunks, i.e. everything a cancelled job
+// leaves behind.
+func (j *Job) PartialArtifacts() []string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	paths := slices.Clone(j.TempFiles)
+	for _, chunk := range j.Chunks {
+		if chunk.InputPath != "" {
+			paths = append(paths, chunk.InputPath)
+		}
+		if chunk.OutputPath != "" {
+			paths = append(paths, chunk.OutputPath)
+		}
+	}
+	return paths
+}
+
+// SetS3Key records the storage key the output video was uploaded under.
+func (j *Job) SetS3Key(key string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.S3Key = key
+	j.UpdatedAt = time.Now()
+}
+
+// SetPublished records that the output video was uploaded to remote storage
+// under key after the job completed, as if it had been created with PushToS3.
+func (j *Job) SetPublished(key string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.PushToS3 = true
+	j.S3Key = key
+	j.UpdatedAt = time.Now()
+}
+
+// SetThumbnail sets the preview image path and optional storage key.
+func (j *Job) SetThumbnail(thumbnailPath, thumbnailKey string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.ThumbnailPath = thumbnailPath
+	j.ThumbnailKey = thumbnailKey
+	j.UpdatedAt = time.Now()
+}
+
+// ClearOutput clears the output video and thumbnail paths and keys.
 // This is used when deleting the job's video file.
 func (j *Job) ClearOutput() {
 	j.mu.Lock()
 	defer j.mu.Unlock()
 	j.OutputVideoPath = ""
-	j.VideoURL = ""
+	j.S3Key = ""
+	j.ThumbnailPath = ""
+	j.ThumbnailKey = ""
 	j.UpdatedAt = time.Now()
 }
 
@@ -305,6 +687,28 @@ func (j *Job) IsTerminal() bool {
 		j.Status == StatusTimedOut
 }
 
+// HasMetadata reports whether the job's Metadata contains every key/value
+// pair of tags. An empty tags matches every job.
+func (j *Job) HasMetadata(tags map[string]string) bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	for k, v := range tags {
+		if got, ok := j.Metadata[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// IsStuck reports whether the job is RUNNING and has not been updated for at
+// least olderThan before now, e.g. because the worker processing it crashed.
+func (j *Job) IsStuck(now time.Time, olderThan time.Duration) bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.Status == StatusRunning && now.Sub(j.UpdatedAt) >= olderThan
+}
+
 // Clone creates a deep copy of the job for safe reads.
 func (j *Job) Clone() *Job {
 	j.mu.RLock()
@@ -314,23 +718,45 @@ func (j *Job) Clone() *Job {
 	copy(chunks, j.Chunks)
 
 	return &Job{
-		ID:              j.ID,
-		Provider:        j.Provider,
-		Status:          j.Status,
-		Chunks:          chunks,
-		Progress:        j.Progress,
-		Error:           j.Error,
-		Prompt:          j.Prompt,
-		InputImagePath:  j.InputImagePath,
-		InputAudioPath:  j.InputAudioPath,
-		OutputVideoPath: j.OutputVideoPath,
-		Width:           j.Width,
-		Height:          j.Height,
-		PushToS3:        j.PushToS3,
-		VideoURL:        j.VideoURL,
-		CreatedAt:       j.CreatedAt,
-		UpdatedAt:       j.UpdatedAt,
-		StartedAt:       j.StartedAt,
-		CompletedAt:     j.CompletedAt,
+		ID:                  j.ID,
+		Provider:            j.Provider,
+		Status:              j.Status,
+		Chunks:              chunks,
+		Progress:            j.Progress,
+		Stage:               j.Stage,
+		Error:               j.Error,
+		Prompt:              j.Prompt,
+		InputImagePath:      j.InputImagePath,
+		InputAudioPath:      j.InputAudioPath,
+		OutputVideoPath:     j.OutputVideoPath,
+		Width:               j.Width,
+		Height:              j.Height,
+		PushToS3:            j.PushToS3,
+		DryRun:              j.DryRun,
+		ForceOffload:        j.ForceOffload,
+		ResizeMode:          j.ResizeMode,
+		PersonCount:         j.PersonCount,
+		InputType:           j.InputType,
+		Priority:            j.Priority,
+		OutputFormat:        j.OutputFormat,
+		TargetFPS:           j.TargetFPS,
+		StrictDimensions:    j.StrictDimensions,
+		UseOriginalAudio:    j.UseOriginalAudio,
+		ContinuityMode:      j.ContinuityMode,
+		InputHash:           j.InputHash,
+		Metadata:            maps.Clone(j.Metadata),
+		S3Key:               j.S3Key,
+		ThumbnailPath:       j.ThumbnailPath,
+		ThumbnailKey:        j.ThumbnailKey,
+		TempFiles:           slices.Clone(j.TempFiles),
+		CreatedAt:           j.CreatedAt,
+		UpdatedAt:           j.UpdatedAt,
+		StartedAt:           j.StartedAt,
+		CompletedAt:         j.CompletedAt,
+		ExpiresAt:           j.ExpiresAt,
+		Retention:           j.Retention,
+		DeleteAfterDownload: j.DeleteAfterDownload,
+		VideoDownloadedAt:   j.VideoDownloadedAt,
+		Logs:                j.Logs,
 	}
 }
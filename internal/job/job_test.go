@@ -1,6 +1,7 @@
 package job
 
 import (
+	"slices"
 	"testing"
 	"time"
 )
@@ -234,6 +235,78 @@ func TestJob_IsTerminal(t *testing.T) {
 	}
 }
 
+func TestJob_IsRetryable(t *testing.T) {
+	tests := []struct {
+		status    Status
+		retryable bool
+	}{
+		{StatusInQueue, false},
+		{StatusRunning, false},
+		{StatusCompleted, false},
+		{StatusFailed, true},
+		{StatusCancelled, false},
+		{StatusTimedOut, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			job := NewWithID("test")
+			job.Status = tt.status
+
+			if got := job.IsRetryable(); got != tt.retryable {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestJob_ResetForRetry(t *testing.T) {
+	job := New()
+	job.InputImagePath = "/tmp/image.png"
+	job.InputAudioPath = "/tmp/audio.wav"
+	job.Prompt = "hello"
+	_ = job.Start()
+	job.SetChunks([]Chunk{{ID: "chunk-1", Index: 0, Status: ChunkStatusFailed}})
+	job.UpdateProgress(40)
+	job.SetStage(StageGenerating)
+	job.AddTempFiles("/tmp/chunk_0.wav")
+	_ = job.Fail("provider error")
+
+	if err := job.ResetForRetry(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if job.Status != StatusInQueue {
+		t.Errorf("expected status %s, got %s", StatusInQueue, job.Status)
+	}
+	if len(job.Chunks) != 0 || job.Progress != 0 || job.Stage != "" || job.Error != "" || len(job.TempFiles) != 0 {
+		t.Errorf("expected previous run to be cleared, got chunks=%d progress=%d stage=%q error=%q temp files=%v",
+			len(job.Chunks), job.Progress, job.Stage, job.Error, job.TempFiles)
+	}
+	if !job.StartedAt.IsZero() || !job.CompletedAt.IsZero() {
+		t.Error("expected StartedAt and CompletedAt to be reset")
+	}
+	if job.InputImagePath != "/tmp/image.png" || job.InputAudioPath != "/tmp/audio.wav" || job.Prompt != "hello" {
+		t.Error("expected inputs and settings to be kept")
+	}
+}
+
+func TestJob_ResetForRetry_NotRetryable(t *testing.T) {
+	for _, status := range []Status{StatusInQueue, StatusRunning, StatusCompleted, StatusCancelled} {
+		t.Run(string(status), func(t *testing.T) {
+			job := NewWithID("test")
+			job.Status = status
+
+			if err := job.ResetForRetry(); err != ErrInvalidTransition {
+				t.Errorf("expected ErrInvalidTransition, got %v", err)
+			}
+			if job.Status != status {
+				t.Errorf("expected status to stay %s, got %s", status, job.Status)
+			}
+		})
+	}
+}
+
 func TestJob_SetChunks(t *testing.T) {
 	job := New()
 	chunks := []Chunk{
@@ -251,6 +324,27 @@ func TestJob_SetChunks(t *testing.T) {
 	}
 }
 
+func TestJob_PartialArtifacts(t *testing.T) {
+	job := New()
+	job.AddTempFiles("/tmp/resized.png")
+	job.AddTempFiles("/tmp/chunk_0.wav", "/tmp/chunk_1.wav")
+	job.SetChunks([]Chunk{
+		{ID: "chunk-0", Index: 0, Status: ChunkStatusCompleted, InputPath: "/tmp/chunk_0.wav", OutputPath: "/tmp/chunk_0.mp4"},
+		{ID: "chunk-1", Index: 1, Status: ChunkStatusProcessing, InputPath: "/tmp/chunk_1.wav"},
+	})
+
+	want := []string{"/tmp/resized.png", "/tmp/chunk_0.wav", "/tmp/chunk_1.wav", "/tmp/chunk_0.wav", "/tmp/chunk_0.mp4", "/tmp/chunk_1.wav"}
+	if got := job.PartialArtifacts(); !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	temp := job.GetTempFiles()
+	temp[0] = "/tmp/changed.png"
+	if job.TempFiles[0] != "/tmp/resized.png" {
+		t.Error("expected GetTempFiles to return a copy")
+	}
+}
+
 func TestJob_UpdateChunk(t *testing.T) {
 	job := New()
 	job.SetChunks([]Chunk{
@@ -298,19 +392,31 @@ func TestJob_UpdateProgress(t *testing.T) {
 func TestJob_SetOutput(t *testing.T) {
 	job := New()
 
-	job.SetOutput("/tmp/video.mp4", "https://s3.example.com/video.mp4")
+	job.SetOutput("/tmp/video.mp4")
 
 	if job.OutputVideoPath != "/tmp/video.mp4" {
 		t.Errorf("expected OutputVideoPath /tmp/video.mp4, got %s", job.OutputVideoPath)
 	}
-	if job.VideoURL != "https://s3.example.com/video.mp4" {
-		t.Errorf("expected VideoURL https://s3.example.com/video.mp4, got %s", job.VideoURL)
+}
+
+func TestJob_SetThumbnail(t *testing.T) {
+	job := New()
+
+	job.SetThumbnail("/tmp/thumb.jpg", "thumbnails/thumb.jpg")
+
+	if job.ThumbnailPath != "/tmp/thumb.jpg" {
+		t.Errorf("expected ThumbnailPath /tmp/thumb.jpg, got %s", job.ThumbnailPath)
+	}
+	if job.ThumbnailKey != "thumbnails/thumb.jpg" {
+		t.Errorf("expected ThumbnailKey thumbnails/thumb.jpg, got %s", job.ThumbnailKey)
 	}
 }
 
 func TestJob_ClearOutput(t *testing.T) {
 	job := New()
-	job.SetOutput("/tmp/video.mp4", "https://s3.example.com/video.mp4")
+	job.SetOutput("/tmp/video.mp4")
+	job.SetS3Key("videos/video.mp4")
+	job.SetThumbnail("/tmp/thumb.jpg", "thumbnails/thumb.jpg")
 
 	beforeClear := time.Now()
 	job.ClearOutput()
@@ -318,8 +424,11 @@ func TestJob_ClearOutput(t *testing.T) {
 	if job.OutputVideoPath != "" {
 		t.Errorf("expected OutputVideoPath to be empty, got %s", job.OutputVideoPath)
 	}
-	if job.VideoURL != "" {
-		t.Errorf("expected VideoURL to be empty, got %s", job.VideoURL)
+	if job.S3Key != "" {
+		t.Errorf("expected S3Key to be empty, got %s", job.S3Key)
+	}
+	if job.ThumbnailPath != "" || job.ThumbnailKey != "" {
+		t.Errorf("expected thumbnail to be cleared, got %q / %q", job.ThumbnailPath, job.ThumbnailKey)
 	}
 	if job.UpdatedAt.Before(beforeClear) {
 		t.Error("expected UpdatedAt to be updated after ClearOutput")
@@ -330,6 +439,7 @@ func TestJob_Clone(t *testing.T) {
 	job := New()
 	job.Status = StatusRunning
 	job.Progress = 50
+	job.SetS3Key("videos/video.mp4")
 	job.SetChunks([]Chunk{
 		{ID: "chunk-1", Index: 0, Status: ChunkStatusCompleted},
 	})
@@ -346,6 +456,9 @@ func TestJob_Clone(t *testing.T) {
 	if clone.Progress != job.Progress {
 		t.Errorf("expected Progress %d, got %d", job.Progress, clone.Progress)
 	}
+	if clone.S3Key != job.S3Key {
+		t.Errorf("expected S3Key %s, got %s", job.S3Key, clone.S3Key)
+	}
 
 	// Verify clone is independent
 	clone.Status = StatusCompleted
@@ -360,6 +473,43 @@ func TestJob_Clone(t *testing.T) {
 	}
 }
 
+func TestJob_Clone_Metadata(t *testing.T) {
+	job := New()
+	job.Metadata = map[string]string{"customer": "acme"}
+
+	clone := job.Clone()
+	if clone.Metadata["customer"] != "acme" {
+		t.Errorf("expected metadata to be cloned, got %v", clone.Metadata)
+	}
+
+	clone.Metadata["customer"] = "other"
+	if job.Metadata["customer"] != "acme" {
+		t.Error("modifying clone metadata should not affect original")
+	}
+}
+
+func TestJob_HasMetadata(t *testing.T) {
+	job := New()
+	job.Metadata = map[string]string{"customer": "acme", "row": "42"}
+
+	tests := []struct {
+		tags map[string]string
+		want bool
+	}{
+		{nil, true},
+		{map[string]string{"customer": "acme"}, true},
+		{map[string]string{"customer": "acme", "row": "42"}, true},
+		{map[string]string{"customer": "other"}, false},
+		{map[string]string{"customer": "acme", "row": "43"}, false},
+		{map[string]string{"missing": ""}, false},
+	}
+	for _, tt := range tests {
+		if got := job.HasMetadata(tt.tags); got != tt.want {
+			t.Errorf("HasMetadata(%v): expected %v, got %v", tt.tags, tt.want, got)
+		}
+	}
+}
+
 func TestJob_GetStatus_ThreadSafe(t *testing.T) {
 	job := New()
 
@@ -382,3 +532,130 @@ func TestJob_GetStatus_ThreadSafe(t *testing.T) {
 	<-done
 	// If no race conditions, test passes
 }
+
+func TestJob_EstimateRemaining(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	done := func(idx int, took time.Duration, ago time.Duration) Chunk {
+		end := now.Add(-ago)
+		return Chunk{Index: idx, Status: ChunkStatusCompleted, StartedAt: end.Add(-took), CompletedAt: end}
+	}
+	pending := func(idx int) Chunk { return Chunk{Index: idx, Status: ChunkStatusPending} }
+
+	tests := []struct {
+		name        string
+		status      Status
+		chunks      []Chunk
+		parallelism int
+		want        time.Duration
+		wantOK      bool
+	}{
+		{
+			name:   "no completed chunks",
+			status: StatusRunning,
+			chunks: []Chunk{pending(0), pending(1)},
+			wantOK: false,
+		},
+		{
+			name:        "average of completed chunks times remaining",
+			status:      StatusRunning,
+			chunks:      []Chunk{done(0, 60*time.Second, 2*time.Minute), done(1, 120*time.Second, time.Minute), pending(2), pending(3)},
+			parallelism: 1,
+			want:        3 * time.Minute,
+			wantOK:      true,
+		},
+		{
+			name:   "processing chunk is credited with elapsed time",
+			status: StatusRunning,
+			chunks: []Chunk{
+				done(0, 60*time.Second, time.Minute),
+				{Index: 1, Status: ChunkStatusProcessing, StartedAt: now.Add(-20 * time.Second)},
+				pending(2),
+			},
+			parallelism: 1,
+			want:        100 * time.Second,
+			wantOK:      true,
+		},
+		{
+			name:        "remaining work is spread over parallel chunks",
+			status:      StatusRunning,
+			chunks:      []Chunk{done(0, 60*time.Second, 0), pending(1), pending(2), pending(3), pending(4)},
+			parallelism: 2,
+			want:        2 * time.Minute,
+			wantOK:      true,
+		},
+		{
+			name:   "only recent chunks are averaged",
+			status: StatusRunning,
+			chunks: []Chunk{
+				done(0, 10*time.Minute, 10*time.Minute), // outside the window
+				done(1, 30*time.Second, 5*time.Minute),
+				done(2, 30*time.Second, 4*time.Minute),
+				done(3, 30*time.Second, 3*time.Minute),
+				done(4, 30*time.Second, 2*time.Minute),
+				done(5, 30*time.Second, time.Minute),
+				pending(6),
+			},
+			parallelism: 1,
+			want:        30 * time.Second,
+			wantOK:      true,
+		},
+		{
+			name:        "all chunks completed",
+			status:      StatusRunning,
+			chunks:      []Chunk{done(0, time.Minute, 0)},
+			parallelism: 1,
+			want:        0,
+			wantOK:      true,
+		},
+		{
+			name:   "finished job",
+			status: StatusCompleted,
+			chunks: []Chunk{done(0, time.Minute, 0), pending(1)},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := New()
+			job.Status = tt.status
+			job.SetChunks(tt.chunks)
+
+			got, ok := job.EstimateRemaining(now, tt.parallelism)
+			if ok != tt.wantOK {
+				t.Fatalf("EstimateRemaining() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("EstimateRemaining() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJob_IsStuck(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		status    Status
+		updatedAt time.Time
+		want      bool
+	}{
+		{"running past threshold", StatusRunning, now.Add(-time.Hour), true},
+		{"running exactly at threshold", StatusRunning, now.Add(-30 * time.Minute), true},
+		{"running within threshold", StatusRunning, now.Add(-29 * time.Minute), false},
+		{"queued", StatusInQueue, now.Add(-time.Hour), false},
+		{"timed out", StatusTimedOut, now.Add(-time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := New()
+			job.Status = tt.status
+			job.UpdatedAt = tt.updatedAt
+			if got := job.IsStuck(now, 30*time.Minute); got != tt.want {
+				t.Errorf("IsStuck() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultJobLogLines is how many log lines are kept per job when no limit is configured.
+const DefaultJobLogLines = 500
+
+// LogEntry is a log line captured while a job was created or processed.
+type LogEntry struct {
+	// Time is when the line was logged.
+	Time time.Time
+	// Level is the log level, e.g. "INFO".
+	Level string
+	// Message is the log message.
+	Message string
+	// Attrs holds the attributes of the line, formatted as strings.
+	Attrs map[string]string
+}
+
+// LogBuffer keeps the most recent log lines of a job. It is safe for
+// concurrent use; a nil LogBuffer captures nothing.
+type LogBuffer struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	// next is where the following entry is written once the buffer is full.
+	next int
+	// dropped counts the entries overwritten because the buffer was full.
+	dropped int
+}
+
+// NewLogBuffer creates a buffer keeping the last capacity lines.
+// It returns nil when capacity < 1.
+func NewLogBuffer(capacity int) *LogBuffer {
+	if capacity < 1 {
+		return nil
+	}
+	return &LogBuffer{entries: make([]LogEntry, 0, capacity)}
+}
+
+// Add appends e, overwriting the oldest entry when the buffer is full.
+func (b *LogBuffer) Add(e LogEntry) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.entries) < cap(b.entries) {
+		b.entries = append(b.entries, e)
+		return
+	}
+	b.entries[b.next] = e
+	b.next = (b.next + 1) % len(b.entries)
+	b.dropped++
+}
+
+// Entries returns the captured entries, oldest first, and how many older
+// entries were dropped to stay within the capacity.
+func (b *LogBuffer) Entries() ([]LogEntry, int) {
+	if b == nil {
+		return nil, 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entries := make([]LogEntry, 0, len(b.entries))
+	entries = append(entries, b.entries[b.next:]...)
+	entries = append(entries, b.entries[:b.next]...)
+	return entries, b.dropped
+}
+
+// jobLogKey is the context key of the LogBuffer of the job being processed.
+type jobLogKey struct{}
+
+// withJobLog returns a copy of ctx whose service logs are also captured in buf.
+func withJobLog(ctx context.Context, buf *LogBuffer) context.Context {
+	if buf == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, jobLogKey{}, buf)
+}
+
+// jobLogFrom returns the LogBuffer stored in ctx, or nil if there is none.
+func jobLogFrom(ctx context.Context) *LogBuffer {
+	buf, _ := ctx.Value(jobLogKey{}).(*LogBuffer)
+	return buf
+}
+
+// jobLogHandler is a slog.Handler that captures Info and higher records in
+// a job's LogBuffer before passing them on to the server's handler.
+type jobLogHandler struct {
+	next slog.Handler
+	buf  *LogBuffer
+	// attrs are the attributes added with WithAttrs, keys qualified by their groups.
+	attrs []slog.Attr
+	// group is the dot-separated group prefix of attributes added from now on.
+	group string
+}
+
+// Enabled reports whether records at level are captured or handled by next.
+func (h *jobLogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= slog.LevelInfo || h.next.Enabled(ctx, level)
+}
+
+// Handle captures r and passes it on to next when next is enabled for it.
+func (h *jobLogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelInfo {
+		entry := LogEntry{
+			Time:    r.Time,
+			Level:   r.Level.String(),
+			Message: r.Message,
+			Attrs:   make(map[string]string, len(h.attrs)+r.NumAttrs()),
+		}
+		for _, a := range h.attrs {
+			addLogAttr(entry.Attrs, "", a)
+		}
+		r.Attrs(func(a slog.Attr) bool {
+			addLogAttr(entry.Attrs, h.group, a)
+			return true
+		})
+		h.buf.Add(entry)
+	}
+	if !h.next.Enabled(ctx, r.Level) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a handler that adds attrs to every record.
+func (h *jobLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithAttrs(attrs)
+	clone.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, a := range attrs {
+		if h.group != "" {
+			a.Key = h.group + "." + a.Key
+		}
+		clone.attrs = append(clone.attrs, a)
+	}
+	return &clone
+}
+
+// WithGroup returns a handler that qualifies the following attributes with name.
+func (h *jobLogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	clone.group = name
+	if h.group != "" {
+		clone.group = h.group + "." + name
+	}
+	return &clone
+}
+
+// addLogAttr stores a in attrs under its group-qualified key, flattening groups.
+func addLogAttr(attrs map[string]string, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	key := a.Key
+	if prefix != "" {
+		key = prefix + "." + key
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		for _, ga := range a.Value.Group() {
+			addLogAttr(attrs, key, ga)
+		}
+		return
+	}
+	attrs[key] = a.Value.String()
+}
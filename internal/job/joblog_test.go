@@ -0,0 +1,64 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestLogBuffer_KeepsMostRecent(t *testing.T) {
+	buf := NewLogBuffer(3)
+	for i := range 5 {
+		buf.Add(LogEntry{Message: fmt.Sprintf("line %d", i)})
+	}
+
+	entries, dropped := buf.Entries()
+	if dropped != 2 {
+		t.Errorf("expected 2 dropped lines, got %d", dropped)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(entries))
+	}
+	for i, e := range entries {
+		if want := fmt.Sprintf("line %d", i+2); e.Message != want {
+			t.Errorf("entry %d: expected %q, got %q", i, want, e.Message)
+		}
+	}
+}
+
+func TestLogBuffer_Disabled(t *testing.T) {
+	buf := NewLogBuffer(0)
+	if buf != nil {
+		t.Fatal("expected a nil buffer for capacity 0")
+	}
+	buf.Add(LogEntry{Message: "ignored"})
+	if entries, dropped := buf.Entries(); len(entries) != 0 || dropped != 0 {
+		t.Errorf("expected no lines, got %d (dropped %d)", len(entries), dropped)
+	}
+}
+
+func TestService_LogCapturesJobLogs(t *testing.T) {
+	// The server only logs warnings; job logs still capture Info.
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	svc := NewProcessVideoService(nil, nil, nil, nil, nil, nil, logger)
+	buf := NewLogBuffer(10)
+	ctx := withJobLog(context.Background(), buf)
+
+	svc.log(ctx).With("job_id", "job-1").WithGroup("chunk").Info("chunk submitted", slog.Int("index", 2))
+	svc.log(ctx).Debug("not captured")
+	svc.log(context.Background()).Info("other job")
+
+	entries, _ := buf.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 line, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.Level != "INFO" || e.Message != "chunk submitted" {
+		t.Errorf("unexpected line %s %q", e.Level, e.Message)
+	}
+	if e.Attrs["job_id"] != "job-1" || e.Attrs["chunk.index"] != "2" {
+		t.Errorf("unexpected attrs %v", e.Attrs)
+	}
+}
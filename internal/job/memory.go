@@ -3,6 +3,7 @@ package job
 import (
 	"context"
 	"sync"
+	"time"
 )
 
 // Compile-time check that MemoryRepository implements Repository.
@@ -14,24 +15,97 @@ var _ Repository = (*MemoryRepository)(nil)
 type MemoryRepository struct {
 	mu   sync.RWMutex
 	jobs map[string]*Job
+	// byHash indexes the IDs of jobs with an InputHash by that hash.
+	byHash map[string]map[string]struct{}
+	// maxJobs caps the number of stored jobs. Zero means unbounded.
+	maxJobs int
 }
 
-// NewMemoryRepository creates a new in-memory job repository.
+// NewMemoryRepository creates a new, unbounded in-memory job repository.
 func NewMemoryRepository() *MemoryRepository {
 	return &MemoryRepository{
-		jobs: make(map[string]*Job),
+		jobs:   make(map[string]*Job),
+		byHash: make(map[string]map[string]struct{}),
 	}
 }
 
+// NewMemoryRepositoryWithCap creates an in-memory job repository holding at
+// most maxJobs jobs. When a save exceeds the cap, the terminal jobs that
+// finished first are evicted. Jobs still queued or running are never evicted,
+// so the cap can be exceeded while they are in flight. Zero or negative
+// values mean unbounded.
+func NewMemoryRepositoryWithCap(maxJobs int) *MemoryRepository {
+	r := NewMemoryRepository()
+	r.maxJobs = max(maxJobs, 0)
+	return r
+}
+
 // Save persists a job to the in-memory storage.
 // Creates a clone to avoid external mutations.
 func (r *MemoryRepository) Save(_ context.Context, job *Job) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.jobs[job.ID] = job.Clone()
+	if old, ok := r.jobs[job.ID]; ok {
+		r.unindex(old)
+	}
+	stored := job.Clone()
+	r.jobs[job.ID] = stored
+	r.index(stored)
+	r.evict()
 	return nil
 }
 
+// index adds job to the input hash index. The caller must hold r.mu.
+func (r *MemoryRepository) index(job *Job) {
+	if job.InputHash == "" {
+		return
+	}
+	ids, ok := r.byHash[job.InputHash]
+	if !ok {
+		ids = make(map[string]struct{})
+		r.byHash[job.InputHash] = ids
+	}
+	ids[job.ID] = struct{}{}
+}
+
+// unindex removes job from the input hash index. The caller must hold r.mu.
+func (r *MemoryRepository) unindex(job *Job) {
+	ids, ok := r.byHash[job.InputHash]
+	if !ok {
+		return
+	}
+	delete(ids, job.ID)
+	if len(ids) == 0 {
+		delete(r.byHash, job.InputHash)
+	}
+}
+
+// evict removes the oldest terminal jobs until the repository is within its
+// cap or only non-terminal jobs remain. The caller must hold r.mu.
+func (r *MemoryRepository) evict() {
+	for r.maxJobs > 0 && len(r.jobs) > r.maxJobs {
+		var oldestID string
+		var oldest time.Time
+		for id, job := range r.jobs {
+			if !job.IsTerminal() {
+				continue
+			}
+			finished := job.CompletedAt
+			if finished.IsZero() {
+				finished = job.CreatedAt
+			}
+			if oldestID == "" || finished.Before(oldest) {
+				oldestID, oldest = id, finished
+			}
+		}
+		if oldestID == "" {
+			return
+		}
+		r.unindex(r.jobs[oldestID])
+		delete(r.jobs, oldestID)
+	}
+}
+
 // FindByID retrieves a job by its ID.
 // Returns a clone to prevent external mutations.
 func (r *MemoryRepository) FindByID(_ context.Context, id string) (*Job, error) {
@@ -44,6 +118,19 @@ func (r *MemoryRepository) FindByID(_ context.Context, id string) (*Job, error)
 	return job.Clone(), nil
 }
 
+// FindByInputHash returns the jobs whose InputHash is hash.
+// Returns clones to prevent external mutations.
+func (r *MemoryRepository) FindByInputHash(_ context.Context, hash string) ([]*Job, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := r.byHash[hash]
+	result := make([]*Job, 0, len(ids))
+	for id := range ids {
+		result = append(result, r.jobs[id].Clone())
+	}
+	return result, nil
+}
+
 // List returns all jobs in the repository.
 // Returns clones to prevent external mutations.
 func (r *MemoryRepository) List(_ context.Context) ([]*Job, error) {
@@ -60,9 +147,11 @@ func (r *MemoryRepository) List(_ context.Context) ([]*Job, error) {
 func (r *MemoryRepository) Delete(_ context.Context, id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if _, ok := r.jobs[id]; !ok {
+	job, ok := r.jobs[id]
+	if !ok {
 		return ErrJobNotFound
 	}
+	r.unindex(job)
 	delete(r.jobs, id)
 	return nil
 }
@@ -2,7 +2,9 @@ package job
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 )
 
 func TestMemoryRepository_Save(t *testing.T) {
@@ -156,6 +158,151 @@ func TestMemoryRepository_Delete_NotFound(t *testing.T) {
 	}
 }
 
+// finishedJob returns a job in the given terminal status that finished at completedAt.
+func TestMemoryRepository_FindByInputHash(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	first := New()
+	first.InputHash = "abc"
+	second := New()
+	second.InputHash = "abc"
+	unhashed := New()
+	for _, job := range []*Job{first, second, unhashed} {
+		if err := repo.Save(ctx, job); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	ids := func(hash string) map[string]bool {
+		t.Helper()
+		jobs, err := repo.FindByInputHash(ctx, hash)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		found := make(map[string]bool, len(jobs))
+		for _, job := range jobs {
+			found[job.ID] = true
+		}
+		return found
+	}
+
+	if got := ids("abc"); len(got) != 2 || !got[first.ID] || !got[second.ID] {
+		t.Errorf("expected both hashed jobs, got %v", got)
+	}
+	if got := ids(""); len(got) != 0 {
+		t.Errorf("expected jobs without a hash not to be indexed, got %v", got)
+	}
+
+	// Changing the hash on update moves the job in the index
+	second.InputHash = "def"
+	_ = repo.Save(ctx, second)
+	if got := ids("abc"); len(got) != 1 || !got[first.ID] {
+		t.Errorf("expected only the first job under the old hash, got %v", got)
+	}
+	if got := ids("def"); len(got) != 1 || !got[second.ID] {
+		t.Errorf("expected the second job under the new hash, got %v", got)
+	}
+
+	_ = repo.Delete(ctx, first.ID)
+	if got := ids("abc"); len(got) != 0 {
+		t.Errorf("expected deleted jobs to leave the index, got %v", got)
+	}
+}
+
+func finishedJob(status Status, completedAt time.Time) *Job {
+	job := New()
+	job.Status = status
+	job.CompletedAt = completedAt
+	return job
+}
+
+func TestMemoryRepositoryWithCap_EvictsOldestTerminalJobs(t *testing.T) {
+	repo := NewMemoryRepositoryWithCap(3)
+	ctx := context.Background()
+	base := time.Now()
+
+	running := New()
+	_ = running.Start()
+	oldest := finishedJob(StatusCompleted, base)
+	older := finishedJob(StatusFailed, base.Add(time.Second))
+	newer := finishedJob(StatusCompleted, base.Add(2*time.Second))
+
+	for _, job := range []*Job{running, oldest, older, newer} {
+		if err := repo.Save(ctx, job); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if _, err := repo.FindByID(ctx, oldest.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected the oldest completed job to be evicted, got %v", err)
+	}
+	for _, job := range []*Job{running, older, newer} {
+		if _, err := repo.FindByID(ctx, job.ID); err != nil {
+			t.Errorf("expected job %s to be retained, got %v", job.ID, err)
+		}
+	}
+}
+
+func TestMemoryRepositoryWithCap_NeverEvictsActiveJobs(t *testing.T) {
+	repo := NewMemoryRepositoryWithCap(2)
+	ctx := context.Background()
+
+	completed := finishedJob(StatusCompleted, time.Now())
+	queued := New()
+	running := New()
+	_ = running.Start()
+
+	for _, job := range []*Job{completed, queued, running} {
+		_ = repo.Save(ctx, job)
+	}
+
+	// The completed job makes room; the active ones stay even above the cap
+	another := New()
+	_ = repo.Save(ctx, another)
+
+	jobs, _ := repo.List(ctx)
+	if len(jobs) != 3 {
+		t.Errorf("expected 3 active jobs to be kept above the cap, got %d", len(jobs))
+	}
+	if _, err := repo.FindByID(ctx, completed.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected completed job to be evicted, got %v", err)
+	}
+}
+
+func TestMemoryRepositoryWithCap_UsesCreatedAtWithoutCompletedAt(t *testing.T) {
+	repo := NewMemoryRepositoryWithCap(1)
+	ctx := context.Background()
+
+	first := finishedJob(StatusCancelled, time.Time{})
+	first.CreatedAt = time.Now().Add(-time.Hour)
+	second := finishedJob(StatusCompleted, time.Now())
+
+	_ = repo.Save(ctx, first)
+	_ = repo.Save(ctx, second)
+
+	if _, err := repo.FindByID(ctx, first.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected the older job to be evicted, got %v", err)
+	}
+	if _, err := repo.FindByID(ctx, second.ID); err != nil {
+		t.Errorf("expected the newer job to be retained, got %v", err)
+	}
+}
+
+func TestNewMemoryRepositoryWithCap_ZeroIsUnbounded(t *testing.T) {
+	repo := NewMemoryRepositoryWithCap(0)
+	ctx := context.Background()
+
+	for range 5 {
+		_ = repo.Save(ctx, finishedJob(StatusCompleted, time.Now()))
+	}
+
+	jobs, _ := repo.List(ctx)
+	if len(jobs) != 5 {
+		t.Errorf("expected 5 jobs, got %d", len(jobs))
+	}
+}
+
 func TestMemoryRepository_ConcurrentAccess(t *testing.T) {
 	repo := NewMemoryRepository()
 	ctx := context.Background()
@@ -163,6 +163,74 @@ func (_c *MockRepository_FindByID_Call) RunAndReturn(run func(ctx context.Contex
 	return _c
 }
 
+// FindByInputHash provides a mock function for the type MockRepository
+func (_mock *MockRepository) FindByInputHash(ctx context.Context, hash string) ([]*job.Job, error) {
+	ret := _mock.Called(ctx, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByInputHash")
+	}
+
+	var r0 []*job.Job
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]*job.Job, error)); ok {
+		return returnFunc(ctx, hash)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []*job.Job); ok {
+		r0 = returnFunc(ctx, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*job.Job)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_FindByInputHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByInputHash'
+type MockRepository_FindByInputHash_Call struct {
+	*mock.Call
+}
+
+// FindByInputHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - hash string
+func (_e *MockRepository_Expecter) FindByInputHash(ctx interface{}, hash interface{}) *MockRepository_FindByInputHash_Call {
+	return &MockRepository_FindByInputHash_Call{Call: _e.mock.On("FindByInputHash", ctx, hash)}
+}
+
+func (_c *MockRepository_FindByInputHash_Call) Run(run func(ctx context.Context, hash string)) *MockRepository_FindByInputHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_FindByInputHash_Call) Return(jobs []*job.Job, err error) *MockRepository_FindByInputHash_Call {
+	_c.Call.Return(jobs, err)
+	return _c
+}
+
+func (_c *MockRepository_FindByInputHash_Call) RunAndReturn(run func(ctx context.Context, hash string) ([]*job.Job, error)) *MockRepository_FindByInputHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // List provides a mock function for the type MockRepository
 func (_mock *MockRepository) List(ctx context.Context) ([]*job.Job, error) {
 	ret := _mock.Called(ctx)
@@ -0,0 +1,141 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/png"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/maauso/infinitetalk-api/internal/generator"
+)
+
+// Warmup job parameters: the smallest input that still makes the provider
+// start a worker.
+const (
+	warmupSize       = 64
+	warmupSampleRate = 16000
+	warmupPrompt     = "warmup"
+)
+
+// PrewarmProviders warms up every configured provider concurrently and waits
+// for all warmups to finish. Failures are logged and do not affect other providers.
+// It is meant to run in the background at startup.
+func (s *ProcessVideoService) PrewarmProviders(ctx context.Context) {
+	providers := []Provider{ProviderRunPod}
+	if s.beamClient != nil {
+		providers = append(providers, ProviderBeam)
+	}
+
+	var wg sync.WaitGroup
+	for _, provider := range providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = s.Prewarm(ctx, provider)
+		}()
+	}
+	wg.Wait()
+}
+
+// Prewarm submits a tiny warmup job to the provider so a serverless worker is
+// started before the first real job arrives. It is not tied to any user job.
+// Prewarm waits for the warmup job to finish and returns its final status;
+// if ctx ends first, the warmup job is cancelled on a best-effort basis.
+func (s *ProcessVideoService) Prewarm(ctx context.Context, provider Provider) (generator.Status, error) {
+	gen, err := s.getGenerator(provider)
+	if err != nil {
+		return "", err
+	}
+
+	imageB64, audioB64, err := warmupInputs()
+	if err != nil {
+		return "", fmt.Errorf("build warmup inputs: %w", err)
+	}
+
+	start := time.Now()
+	providerJobID, err := gen.Submit(ctx, imageB64, audioB64, generator.SubmitOptions{
+		Prompt: warmupPrompt,
+		Width:  warmupSize,
+		Height: warmupSize,
+	})
+	if err != nil {
+		s.logger.Warn("provider warmup submit failed",
+			slog.String("provider", string(provider)),
+			slog.String("error", err.Error()),
+		)
+		return "", fmt.Errorf("submit warmup job: %w", err)
+	}
+
+	s.logger.Info("provider warmup submitted",
+		slog.String("provider", string(provider)),
+		slog.String("provider_job_id", providerJobID),
+	)
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		result, err := gen.Poll(ctx, providerJobID)
+		if err == nil && result.Status.IsTerminal() {
+			s.logger.Info("provider warmup finished",
+				slog.String("provider", string(provider)),
+				slog.String("provider_job_id", providerJobID),
+				slog.String("status", string(result.Status)),
+				slog.Duration("duration", time.Since(start)),
+			)
+			return result.Status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Warn("provider warmup did not finish",
+				slog.String("provider", string(provider)),
+				slog.String("provider_job_id", providerJobID),
+				slog.Duration("duration", time.Since(start)),
+			)
+			cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), providerCancelTimeout)
+			_ = gen.Cancel(cancelCtx, providerJobID)
+			cancel()
+			return "", fmt.Errorf("warmup job %s: %w", providerJobID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// warmupInputs returns a small black PNG and one second of silent 16-bit mono
+// WAV audio, both base64-encoded.
+func warmupInputs() (imageB64, audioB64 string, err error) {
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewGray(image.Rect(0, 0, warmupSize, warmupSize))); err != nil {
+		return "", "", err
+	}
+
+	samples := make([]byte, warmupSampleRate*2)
+	var wav bytes.Buffer
+	wav.WriteString("RIFF")
+	_ = binary.Write(&wav, binary.LittleEndian, uint32(36+len(samples)))
+	wav.WriteString("WAVEfmt ")
+	for _, v := range []any{
+		uint32(16),                   // fmt chunk size
+		uint16(1),                    // PCM
+		uint16(1),                    // mono
+		uint32(warmupSampleRate),     // sample rate
+		uint32(warmupSampleRate * 2), // byte rate
+		uint16(2),                    // block align
+		uint16(16),                   // bits per sample
+	} {
+		_ = binary.Write(&wav, binary.LittleEndian, v)
+	}
+	wav.WriteString("data")
+	_ = binary.Write(&wav, binary.LittleEndian, uint32(len(samples)))
+	wav.Write(samples)
+
+	return base64.StdEncoding.EncodeToString(img.Bytes()),
+		base64.StdEncoding.EncodeToString(wav.Bytes()), nil
+}
@@ -0,0 +1,119 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/maauso/infinitetalk-api/internal/generator"
+	"github.com/maauso/infinitetalk-api/internal/runpod"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProcessVideoService_Prewarm(t *testing.T) {
+	svc, _, _, runpodClient, _, repo := newTestService(t)
+	ctx := context.Background()
+
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.MatchedBy(func(o runpod.SubmitOptions) bool {
+		return o.Prompt == warmupPrompt && o.Width == warmupSize && o.Height == warmupSize
+	})).Return("warmup-job", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "warmup-job").
+		Return(runpod.PollResult{Status: runpod.StatusInQueue}, nil).Once()
+	runpodClient.On("Poll", mock.Anything, "warmup-job").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted}, nil).Once()
+
+	status, err := svc.Prewarm(ctx, ProviderRunPod)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != generator.StatusCompleted {
+		t.Errorf("expected status COMPLETED, got %s", status)
+	}
+
+	// The warmup is not tied to any user job
+	jobs, _ := repo.List(ctx)
+	if len(jobs) != 0 {
+		t.Errorf("expected no jobs in repository, got %d", len(jobs))
+	}
+
+	runpodClient.AssertExpectations(t)
+}
+
+func TestProcessVideoService_PrewarmProviders(t *testing.T) {
+	svc, _, _, runpodClient, _, _ := newTestService(t)
+
+	// Beam is not configured in newTestService, so only RunPod is warmed up
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("warmup-job", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "warmup-job").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted}, nil).Once()
+
+	svc.PrewarmProviders(context.Background())
+
+	runpodClient.AssertExpectations(t)
+}
+
+func TestProcessVideoService_Prewarm_SubmitFails(t *testing.T) {
+	svc, _, _, runpodClient, _, _ := newTestService(t)
+
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("", errors.New("endpoint unavailable")).Once()
+
+	if _, err := svc.Prewarm(context.Background(), ProviderRunPod); err == nil {
+		t.Fatal("expected error when warmup submit fails")
+	}
+	runpodClient.AssertNotCalled(t, "Poll", mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_Prewarm_TimeoutCancels(t *testing.T) {
+	svc, _, _, runpodClient, _, _ := newTestService(t)
+
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("warmup-job", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "warmup-job").
+		Return(runpod.PollResult{Status: runpod.StatusInQueue}, nil)
+	runpodClient.On("Cancel", mock.Anything, "warmup-job").Return(nil).Once()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := svc.Prewarm(ctx, ProviderRunPod)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	runpodClient.AssertExpectations(t)
+}
+
+func TestWarmupInputs(t *testing.T) {
+	imageB64, audioB64, err := warmupInputs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	imageData, err := base64.StdEncoding.DecodeString(imageB64)
+	if err != nil {
+		t.Fatalf("image is not base64: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(imageData))
+	if err != nil {
+		t.Fatalf("image is not a PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != warmupSize || b.Dy() != warmupSize {
+		t.Errorf("expected %dx%d image, got %dx%d", warmupSize, warmupSize, b.Dx(), b.Dy())
+	}
+
+	audioData, err := base64.StdEncoding.DecodeString(audioB64)
+	if err != nil {
+		t.Fatalf("audio is not base64: %v", err)
+	}
+	if string(audioData[:4]) != "RIFF" || string(audioData[8:16]) != "WAVEfmt " || string(audioData[36:40]) != "data" {
+		t.Error("audio is not a WAV file")
+	}
+	if len(audioData) != 44+warmupSampleRate*2 {
+		t.Errorf("expected one second of 16-bit mono audio, got %d bytes", len(audioData))
+	}
+}
@@ -0,0 +1,48 @@
+package job
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// RedactPayload returns a placeholder naming the size of a base64 media
+// payload, for logging in place of the payload itself. Empty payloads stay empty.
+func RedactPayload(payload string) string {
+	if payload == "" {
+		return ""
+	}
+	return fmt.Sprintf("[redacted %d bytes]", len(payload))
+}
+
+// LogValue implements slog.LogValuer so that logging an input never writes
+// the base64 media. The payloads are replaced by their size.
+func (in ProcessVideoInput) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("image_base64", RedactPayload(in.ImageBase64)),
+		slog.String("audio_base64", RedactPayload(in.AudioBase64)),
+		slog.String("video_base64", RedactPayload(in.VideoBase64)),
+		slog.String("image_url", in.ImageURL),
+		slog.String("audio_url", in.AudioURL),
+		slog.Int("width", in.Width),
+		slog.Int("height", in.Height),
+		slog.String("prompt", in.Prompt),
+		slog.String("provider", in.Provider),
+		slog.Bool("push_to_s3", in.PushToS3),
+		slog.Bool("dry_run", in.DryRun),
+		slog.Bool("force_offload", in.ForceOffload),
+		slog.String("resize_mode", in.ResizeMode),
+		slog.String("person_count", in.PersonCount),
+		slog.String("input_type", in.InputType),
+		slog.String("priority", in.Priority),
+		slog.String("output_format", in.OutputFormat),
+		slog.Float64("target_fps", in.TargetFPS),
+		slog.Bool("strict_dimensions", in.StrictDimensions),
+		slog.Bool("use_original_audio", in.UseOriginalAudio),
+		slog.String("continuity_mode", in.ContinuityMode),
+		slog.Any("metadata", in.Metadata),
+	}
+	if in.RetentionSec != nil {
+		attrs = append(attrs, slog.Int("retention_sec", *in.RetentionSec))
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,41 @@
+package job
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedactPayload(t *testing.T) {
+	if got := RedactPayload(""); got != "" {
+		t.Errorf("expected an empty payload to stay empty, got %q", got)
+	}
+	if got := RedactPayload("aGVsbG8="); got != "[redacted 8 bytes]" {
+		t.Errorf("expected [redacted 8 bytes], got %q", got)
+	}
+}
+
+func TestProcessVideoInput_LogValue(t *testing.T) {
+	input := validateInput()
+	input.ImageBase64 = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("image-bytes"), 100))
+	input.AudioBase64 = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("audio-bytes"), 100))
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("processing", slog.Any("input", input))
+
+	out := buf.String()
+	for name, payload := range map[string]string{"image": input.ImageBase64, "audio": input.AudioBase64} {
+		if strings.Contains(out, payload[:64]) {
+			t.Errorf("the %s payload must not be logged: %s", name, out)
+		}
+	}
+	if want := fmt.Sprintf("input.image_base64=\"[redacted %d bytes]\"", len(input.ImageBase64)); !strings.Contains(out, want) {
+		t.Errorf("expected the image payload size to be logged, got %s", out)
+	}
+	if !strings.Contains(out, "input.width=384") {
+		t.Errorf("expected the other fields to be logged, got %s", out)
+	}
+}
@@ -19,6 +19,10 @@ type Repository interface {
 	// Returns ErrJobNotFound if the job does not exist.
 	FindByID(ctx context.Context, id string) (*Job, error)
 
+	// FindByInputHash returns the jobs whose InputHash is hash.
+	// Returns an empty slice if there are none.
+	FindByInputHash(ctx context.Context, hash string) ([]*Job, error)
+
 	// List returns all jobs.
 	List(ctx context.Context) ([]*Job, error)
 
@@ -8,15 +8,22 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/maauso/infinitetalk-api/internal/audio"
 	"github.com/maauso/infinitetalk-api/internal/beam"
+	"github.com/maauso/infinitetalk-api/internal/fetch"
 	"github.com/maauso/infinitetalk-api/internal/generator"
 	"github.com/maauso/infinitetalk-api/internal/media"
+	"github.com/maauso/infinitetalk-api/internal/requestid"
 	"github.com/maauso/infinitetalk-api/internal/runpod"
 	"github.com/maauso/infinitetalk-api/internal/storage"
 )
@@ -31,6 +38,19 @@ var (
 	ErrRunPodJobTimedOut = errors.New("RunPod job timed out")
 	// ErrInvalidProvider is returned when an invalid provider is specified.
 	ErrInvalidProvider = errors.New("invalid provider")
+	// ErrInvalidPriority is returned when an invalid job priority is specified.
+	ErrInvalidPriority = errors.New("invalid priority")
+	// ErrInvalidOutputFormat is returned when an unsupported output format is specified.
+	ErrInvalidOutputFormat = errors.New("invalid output format")
+	// ErrInvalidTargetFPS is returned when a negative target frame rate is specified.
+	ErrInvalidTargetFPS = errors.New("invalid target fps")
+	// ErrInvalidContinuityMode is returned when an unsupported continuity mode is specified.
+	ErrInvalidContinuityMode = errors.New("invalid continuity mode")
+	// ErrInvalidRetention is returned when a job's retention is negative or above the maximum.
+	ErrInvalidRetention = errors.New("invalid retention")
+	// ErrChunkDimensionsMismatch is returned when a chunk video does not have the
+	// requested dimensions and the job asked for strict dimensions.
+	ErrChunkDimensionsMismatch = errors.New("chunk dimensions do not match the requested size")
 	// ErrBeamClientNotInitialized is returned when Beam provider is requested but client is not initialized.
 	ErrBeamClientNotInitialized = errors.New("beam client not initialized")
 	// ErrNoVideoOutput is returned when provider returns neither base64 nor URL.
@@ -41,6 +61,42 @@ var (
 	ErrProviderJobCancelled = errors.New("provider job cancelled")
 	// ErrProviderJobTimedOut is returned when provider job times out.
 	ErrProviderJobTimedOut = errors.New("provider job timed out")
+	// ErrJobNotCancellable is returned when cancelling a job that already reached a terminal state.
+	ErrJobNotCancellable = errors.New("job is not cancellable")
+	// ErrFetcherNotConfigured is returned when a URL input is given but no input fetcher is configured.
+	ErrFetcherNotConfigured = errors.New("input fetcher not configured")
+	// ErrDryRunSideEffect is returned when a dry-run job attempts a provider call.
+	ErrDryRunSideEffect = errors.New("dry-run must not call providers")
+	// ErrJobNotRetryable is returned when retrying a job that is not FAILED or TIMED_OUT.
+	ErrJobNotRetryable = errors.New("job is not retryable")
+	// ErrRetryInputsUnavailable is returned when the inputs of a failed job are no longer on disk.
+	ErrRetryInputsUnavailable = errors.New("job inputs are no longer available for retry")
+	// ErrJobNotDeletable is returned when deleting a job that is still being processed.
+	ErrJobNotDeletable = errors.New("job is still being processed")
+	// ErrVideoNotRemote is returned when downloading the video of a job that was not pushed to remote storage.
+	ErrVideoNotRemote = errors.New("job video is not in remote storage")
+	// ErrUnsupportedInputType is returned when the provider cannot animate the requested input type.
+	ErrUnsupportedInputType = errors.New("input type not supported by provider")
+	// ErrVideoNotPublishable is returned when publishing a job that has no local output video.
+	ErrVideoNotPublishable = errors.New("job has no local video to publish")
+	// ErrJobNotStuck is returned when timing out a job that is not RUNNING or was updated recently.
+	ErrJobNotStuck = errors.New("job is not stuck")
+)
+
+// providerCancelTimeout bounds each best-effort provider cancel request.
+const providerCancelTimeout = 30 * time.Second
+
+// DefaultMaxPollInterval caps the chunk poll interval under adaptive polling
+// when no maximum is configured (see WithAdaptivePolling).
+const DefaultMaxPollInterval = time.Minute
+
+// Progress reported at the end of each processing stage. Generating the
+// chunks moves progress up to progressGenerated; the remainder is left for
+// joining and uploading the output video.
+const (
+	progressGenerated = 90
+	progressJoined    = 95
+	progressUploaded  = 98
 )
 
 // ProcessVideoInput contains the input parameters for video processing.
@@ -49,6 +105,13 @@ type ProcessVideoInput struct {
 	ImageBase64 string
 	// AudioBase64 is the base64-encoded source audio.
 	AudioBase64 string
+	// ImageURL is a URL to download the source image from, used instead of ImageBase64.
+	ImageURL string
+	// VideoBase64 is the base64-encoded source video, used instead of the
+	// image when InputType is InputTypeVideo.
+	VideoBase64 string
+	// AudioURL is a URL to download the source audio from, used instead of AudioBase64.
+	AudioURL string
 	// Width is the target video width.
 	Width int
 	// Height is the target video height.
@@ -63,6 +126,42 @@ type ProcessVideoInput struct {
 	DryRun bool
 	// ForceOffload forces offload on the provider. Defaults to true if not specified.
 	ForceOffload bool
+	// ResizeMode selects how the image is fitted: "pad" (default) or "crop".
+	ResizeMode string
+	// PersonCount is the number of people to animate: "single" (default) or "multi".
+	// Audio of multi-person jobs is not split, so every speaker stays in one chunk.
+	PersonCount string
+	// InputType is the type of the source media: "image" (default) or "video".
+	// Video sources skip the image resize and are passed to the provider as-is.
+	InputType string
+	// Priority orders the job in the dispatch queue: "high", "normal" (default) or "low".
+	Priority string
+	// OutputFormat is the container of the output video: "mp4" (default), "mov" or "webm".
+	OutputFormat string
+	// TargetFPS normalizes every chunk video to this frame rate before joining.
+	// Zero keeps the frame rate returned by the provider.
+	TargetFPS float64
+	// StrictDimensions fails the job when a chunk video does not have the
+	// requested Width and Height. By default such chunks are resized to match.
+	StrictDimensions bool
+	// UseOriginalAudio replaces the audio of the joined chunk videos with the
+	// uploaded audio, so the output keeps the source track untouched.
+	UseOriginalAudio bool
+	// ContinuityMode selects the source image of each chunk: "none" (default)
+	// animates every chunk from the resized image, "last_frame" from the last
+	// frame of the previous chunk. Only image inputs support "last_frame".
+	ContinuityMode string
+	// Metadata holds client-defined key/value pairs stored with the job.
+	Metadata map[string]string
+	// RetentionSec is how long after creation the job and its artifacts are
+	// kept, overriding the job TTL. Zero deletes them once the video has been
+	// downloaded. Nil uses the job TTL.
+	RetentionSec *int
+
+	// imagePath and audioPath point at inputs retained from a previous run.
+	// They are set by ProcessRetriedJob and take precedence over base64/URL inputs.
+	imagePath string
+	audioPath string
 }
 
 // ProcessVideoOutput contains the result of video processing.
@@ -75,6 +174,10 @@ type ProcessVideoOutput struct {
 	VideoPath string
 	// VideoURL is the S3 URL of the output video (if pushed to S3).
 	VideoURL string
+	// ThumbnailPath is the local path to the preview image, if one was generated.
+	ThumbnailPath string
+	// ThumbnailURL is the S3 URL of the preview image (if pushed to S3).
+	ThumbnailURL string
 	// Error contains any error message if processing failed.
 	Error string
 }
@@ -92,8 +195,71 @@ type ProcessVideoService struct {
 	logger     *slog.Logger
 	// splitOpts configures audio splitting behavior.
 	splitOpts audio.SplitOpts
+	// maxAudioDuration rejects jobs with longer audio before splitting. Zero means no limit.
+	maxAudioDuration time.Duration
+	// maxImagePixels rejects images with more pixels before they are decoded. Zero means no limit.
+	maxImagePixels int64
 	// pollInterval is the duration between RunPod status polls.
 	pollInterval time.Duration
+	// pollBackoff multiplies the poll interval after each poll that finds the
+	// provider job still queued. Values <= 1 keep polling at pollInterval.
+	pollBackoff float64
+	// maxPollInterval caps the poll interval reached by pollBackoff.
+	maxPollInterval time.Duration
+	// maxConcurrentChunks is how many chunks of a job are processed at once.
+	maxConcurrentChunks int
+	// providerSlots bounds the chunks running at the provider across all jobs.
+	// Nil means no global limit.
+	providerSlots chan struct{}
+	// fetcher downloads URL inputs. Shared across jobs to bound concurrent downloads.
+	fetcher fetch.Fetcher
+	// inMemoryResize pipes the resized image from ffmpeg instead of
+	// writing it to disk and reading it back.
+	inMemoryResize bool
+	// thumbnails enables generating a preview image for completed jobs.
+	thumbnails bool
+	// thumbnailAtSec is the timestamp of the preview frame; <= 0 uses the mid-point.
+	thumbnailAtSec float64
+	// maxChunkRetries is how many times a chunk is resubmitted after a retryable failure.
+	maxChunkRetries int
+	// chunkRetryBackoff is the delay before the first chunk retry; it doubles on each retry.
+	chunkRetryBackoff time.Duration
+	// chunkTimeout bounds how long a single chunk is polled. Zero means no limit.
+	chunkTimeout time.Duration
+	// maxJoinRetries is how many times joining the chunk videos is retried after a failure.
+	maxJoinRetries int
+	// joinRetryBackoff is the delay before the first join retry; it doubles on each retry.
+	joinRetryBackoff time.Duration
+	// jobTTL is how long after creation a job's results are retained. Zero means forever.
+	jobTTL time.Duration
+	// maxRetention bounds the retention a job may request. Zero means no limit.
+	maxRetention time.Duration
+	// submitByURL uploads Beam inputs to remote storage and submits their URLs
+	// instead of base64 payloads.
+	submitByURL bool
+	// jobLogLines is how many log lines are captured per job. Zero disables capture.
+	jobLogLines int
+	// keepLocalOutput keeps the local output video after it is uploaded to S3.
+	keepLocalOutput bool
+	// stuckThreshold is how long a RUNNING job may go without an update
+	// before it is considered stuck.
+	stuckThreshold time.Duration
+	// now returns the current time; replaced in tests.
+	now func() time.Time
+
+	// providerCounters tracks in-flight provider calls, keyed by provider.
+	providerCounters map[Provider]*generator.Counters
+
+	// activeMu guards active.
+	activeMu sync.Mutex
+	// active tracks jobs currently being processed so they can be cancelled.
+	active map[string]*activeJob
+}
+
+// activeJob is a job being processed together with the cancel func of its context.
+type activeJob struct {
+	job    *Job
+	cancel context.CancelFunc
 }
 
 // ServiceOption is a function that configures a ProcessVideoService.
@@ -106,6 +272,27 @@ func WithSplitOpts(opts audio.SplitOpts) ServiceOption {
 	}
 }
 
+// WithMaxAudioDuration rejects jobs whose audio is longer than d with
+// ErrAudioTooLong before the audio is split. Zero disables the limit.
+func WithMaxAudioDuration(d time.Duration) ServiceOption {
+	return func(s *ProcessVideoService) {
+		if d >= 0 {
+			s.maxAudioDuration = d
+		}
+	}
+}
+
+// WithMaxImagePixels rejects jobs whose image has more than n pixels
+// (width x height) with ErrImageTooLarge before it is converted or resized.
+// Zero disables the limit.
+func WithMaxImagePixels(n int64) ServiceOption {
+	return func(s *ProcessVideoService) {
+		if n >= 0 {
+			s.maxImagePixels = n
+		}
+	}
+}
+
 // WithPollInterval sets the polling interval for RunPod status checks.
 func WithPollInterval(d time.Duration) ServiceOption {
 	return func(s *ProcessVideoService) {
@@ -115,6 +302,182 @@ func WithPollInterval(d time.Duration) ServiceOption {
 	}
 }
 
+// WithAdaptivePolling makes chunk polling back off while the provider job is
+// queued: each poll that finds it IN_QUEUE multiplies the interval by factor,
+// up to maxInterval, and the interval returns to the poll interval once the
+// job is running. A factor <= 1 keeps the fixed poll interval; a maxInterval
+// <= 0 uses DefaultMaxPollInterval.
+func WithAdaptivePolling(factor float64, maxInterval time.Duration) ServiceOption {
+	return func(s *ProcessVideoService) {
+		s.pollBackoff = factor
+		if maxInterval > 0 {
+			s.maxPollInterval = maxInterval
+		}
+	}
+}
+
+// WithMaxConcurrentChunks sets how many chunks of a job are submitted to the
+// provider at once. Values below 1 are ignored.
+func WithMaxConcurrentChunks(n int) ServiceOption {
+	return func(s *ProcessVideoService) {
+		if n > 0 {
+			s.maxConcurrentChunks = n
+		}
+	}
+}
+
+// WithMaxGlobalConcurrency sets how many chunks, across all jobs, may run at
+// the provider at once. A chunk holds its slot from submission until polling
+// ends. Values below 1 disable the limit.
+func WithMaxGlobalConcurrency(n int) ServiceOption {
+	return func(s *ProcessVideoService) {
+		if n > 0 {
+			s.providerSlots = make(chan struct{}, n)
+		} else {
+			s.providerSlots = nil
+		}
+	}
+}
+
+// WithInputFetcher sets the fetcher used to download URL inputs.
+func WithInputFetcher(f fetch.Fetcher) ServiceOption {
+	return func(s *ProcessVideoService) {
+		s.fetcher = f
+	}
+}
+
+// WithInMemoryResize makes the service resize the input image into memory
+// rather than writing it to a temp file and re-reading it.
+func WithInMemoryResize(enabled bool) ServiceOption {
+	return func(s *ProcessVideoService) {
+		s.inMemoryResize = enabled
+	}
+}
+
+// WithThumbnails enables generating a JPEG preview image for completed jobs.
+func WithThumbnails(enabled bool) ServiceOption {
+	return func(s *ProcessVideoService) {
+		s.thumbnails = enabled
+	}
+}
+
+// WithThumbnailAt sets the timestamp of the preview frame in seconds.
+// Zero or negative values use the mid-point of the output video.
+func WithThumbnailAt(sec float64) ServiceOption {
+	return func(s *ProcessVideoService) {
+		s.thumbnailAtSec = sec
+	}
+}
+
+// WithMaxChunkRetries sets how many times a chunk is resubmitted when the
+// provider reports a transient failure. Zero disables retries.
+func WithMaxChunkRetries(n int) ServiceOption {
+	return func(s *ProcessVideoService) {
+		if n >= 0 {
+			s.maxChunkRetries = n
+		}
+	}
+}
+
+// WithChunkRetryBackoff sets the delay before the first chunk retry.
+// The delay doubles on each subsequent retry.
+func WithChunkRetryBackoff(d time.Duration) ServiceOption {
+	return func(s *ProcessVideoService) {
+		if d > 0 {
+			s.chunkRetryBackoff = d
+		}
+	}
+}
+
+// WithChunkTimeout sets the maximum time spent polling a single chunk before
+// it is failed with ErrProviderJobTimedOut. Zero disables the limit.
+func WithChunkTimeout(d time.Duration) ServiceOption {
+	return func(s *ProcessVideoService) {
+		if d >= 0 {
+			s.chunkTimeout = d
+		}
+	}
+}
+
+// WithMaxJoinRetries sets how many times joining the chunk videos is retried
+// when it fails, independently of chunk retries. Zero disables retries.
+func WithMaxJoinRetries(n int) ServiceOption {
+	return func(s *ProcessVideoService) {
+		if n >= 0 {
+			s.maxJoinRetries = n
+		}
+	}
+}
+
+// WithJoinRetryBackoff sets the delay before the first join retry.
+// The delay doubles on each subsequent retry.
+func WithJoinRetryBackoff(d time.Duration) ServiceOption {
+	return func(s *ProcessVideoService) {
+		if d > 0 {
+			s.joinRetryBackoff = d
+		}
+	}
+}
+
+// WithJobTTL sets how long after creation a job and its artifacts are
+// retained. Jobs get an ExpiresAt of creation time plus d. Zero disables expiry.
+func WithJobTTL(d time.Duration) ServiceOption {
+	return func(s *ProcessVideoService) {
+		if d >= 0 {
+			s.jobTTL = d
+		}
+	}
+}
+
+// WithMaxRetention sets the longest retention a job may request through
+// ProcessVideoInput.RetentionSec. Zero means no limit.
+func WithMaxRetention(d time.Duration) ServiceOption {
+	return func(s *ProcessVideoService) {
+		if d >= 0 {
+			s.maxRetention = d
+		}
+	}
+}
+
+// WithJobLogLines sets how many of a job's most recent log lines are kept
+// for GET /jobs/{id}/logs. Zero disables capturing job logs.
+func WithJobLogLines(n int) ServiceOption {
+	return func(s *ProcessVideoService) {
+		if n >= 0 {
+			s.jobLogLines = n
+		}
+	}
+}
+
+// WithSubmitByURL makes Beam jobs upload the resized image and audio chunks
+// to remote storage and pass their URLs to Beam instead of base64 payloads.
+// The storage URLs must be readable by Beam (public or presigned). Jobs fall
+// back to base64 when an upload fails.
+func WithSubmitByURL(enabled bool) ServiceOption {
+	return func(s *ProcessVideoService) {
+		s.submitByURL = enabled
+	}
+}
+
+// WithKeepLocalOutput keeps the local copy of the output video after it is
+// uploaded to S3 instead of deleting it, so it can be served without a round
+// trip to remote storage.
+func WithKeepLocalOutput(keep bool) ServiceOption {
+	return func(s *ProcessVideoService) {
+		s.keepLocalOutput = keep
+	}
+}
+
+// WithStuckJobThreshold sets how long a RUNNING job may go without an update
+// before StuckJobs reports it. Values <= 0 are ignored.
+func WithStuckJobThreshold(d time.Duration) ServiceOption {
+	return func(s *ProcessVideoService) {
+		if d > 0 {
+			s.stuckThreshold = d
+		}
+	}
+}
+
 // NewProcessVideoService creates a new ProcessVideoService with all dependencies.
 func NewProcessVideoService(
 	repo Repository,
@@ -139,6 +502,20 @@ func NewProcessVideoService(
 		logger:       logger,
 		splitOpts:    audio.DefaultSplitOpts(),
 		pollInterval: 5 * time.Second,
+		pollBackoff:  1,
+		active:       make(map[string]*activeJob),
+		now:          time.Now,
+
+		maxConcurrentChunks: 1,
+		jobLogLines:         DefaultJobLogLines,
+		stuckThreshold:      DefaultStuckJobThreshold,
+		maxPollInterval:     DefaultMaxPollInterval,
+		chunkRetryBackoff:   2 * time.Second,
+		joinRetryBackoff:    time.Second,
+		providerCounters: map[Provider]*generator.Counters{
+			ProviderRunPod: {},
+			ProviderBeam:   {},
+		},
 	}
 	for _, opt := range opts {
 		opt(s)
@@ -146,19 +523,61 @@ func NewProcessVideoService(
 	return s
 }
 
+// dryRunGenerator is a generator.Generator that refuses every provider call.
+type dryRunGenerator struct{}
+
+func (dryRunGenerator) Submit(context.Context, string, string, generator.SubmitOptions) (string, error) {
+	return "", ErrDryRunSideEffect
+}
+
+func (dryRunGenerator) Poll(context.Context, string) (generator.PollResult, error) {
+	return generator.PollResult{}, ErrDryRunSideEffect
+}
+
+func (dryRunGenerator) DownloadOutput(context.Context, string, string) error {
+	return ErrDryRunSideEffect
+}
+
+func (dryRunGenerator) Cancel(context.Context, string) error {
+	return ErrDryRunSideEffect
+}
+
 // getGenerator returns the appropriate generator based on the provider.
+// Calls through the returned generator are counted in the provider's counters.
 func (s *ProcessVideoService) getGenerator(provider Provider) (generator.Generator, error) {
+	var gen generator.Generator
 	switch provider {
 	case ProviderRunPod:
-		return generator.NewRunPodAdapter(s.runpod), nil
+		gen = generator.NewRunPodAdapter(s.runpod)
 	case ProviderBeam:
 		if s.beamClient == nil {
 			return nil, ErrBeamClientNotInitialized
 		}
-		return generator.NewBeamAdapter(s.beamClient), nil
+		gen = generator.NewBeamAdapter(s.beamClient)
 	default:
 		return nil, fmt.Errorf("%w: %s", ErrInvalidProvider, provider)
 	}
+	return generator.NewCountingGenerator(gen, s.providerCounters[provider]), nil
+}
+
+// log returns the service logger, tagged with the request ID carried by ctx
+// so that logs from background processing can be matched to the request.
+// When ctx carries a job's LogBuffer, the logs are also captured in it.
+func (s *ProcessVideoService) log(ctx context.Context) *slog.Logger {
+	logger := s.logger
+	if buf := jobLogFrom(ctx); buf != nil {
+		logger = slog.New(&jobLogHandler{next: logger.Handler(), buf: buf})
+	}
+	return requestid.Logger(ctx, logger)
+}
+
+// ProviderActivity returns the number of in-flight submit and poll calls per provider.
+func (s *ProcessVideoService) ProviderActivity() map[string]generator.Activity {
+	activity := make(map[string]generator.Activity, len(s.providerCounters))
+	for provider, counters := range s.providerCounters {
+		activity[string(provider)] = counters.Snapshot()
+	}
+	return activity
 }
 
 // CreateJob creates a new job and persists it to the repository.
@@ -169,13 +588,49 @@ func (s *ProcessVideoService) getGenerator(provider Provider) (generator.Generat
 // resulting file paths will be stored in InputImagePath and InputAudioPath.
 func (s *ProcessVideoService) CreateJob(ctx context.Context, input ProcessVideoInput) (*Job, error) {
 	job := New()
+	job.CreatedAt = s.now()
+	job.UpdatedAt = job.CreatedAt
+	if input.RetentionSec != nil {
+		retention := time.Duration(*input.RetentionSec) * time.Second
+		switch {
+		case retention < 0:
+			return nil, fmt.Errorf("%w: %ds is negative", ErrInvalidRetention, *input.RetentionSec)
+		case s.maxRetention > 0 && retention > s.maxRetention:
+			return nil, fmt.Errorf("%w: %ds exceeds the maximum of %ds", ErrInvalidRetention, *input.RetentionSec, int(s.maxRetention/time.Second))
+		}
+		job.Retention = retention
+		job.DeleteAfterDownload = retention == 0
+	}
+	job.ExpiresAt = s.expiresAt(job)
 	job.Width = input.Width
 	job.Height = input.Height
 	job.PushToS3 = input.PushToS3
+	job.DryRun = input.DryRun
+	job.ForceOffload = input.ForceOffload
+	job.ResizeMode = input.ResizeMode
+	job.PersonCount = input.PersonCount
+	job.InputType = input.InputType
+	if input.Priority != "" {
+		job.Priority = Priority(input.Priority)
+	}
+	job.OutputFormat = input.OutputFormat
+	if job.OutputFormat == "" {
+		job.OutputFormat = string(media.OutputFormatMP4)
+	}
+	job.TargetFPS = input.TargetFPS
+	job.StrictDimensions = input.StrictDimensions
+	job.UseOriginalAudio = input.UseOriginalAudio
+	job.ContinuityMode = input.ContinuityMode
+	if job.ContinuityMode == "" {
+		job.ContinuityMode = ContinuityNone
+	}
+	job.Metadata = maps.Clone(input.Metadata)
+	job.InputHash = InputHash(input)
+	job.Logs = NewLogBuffer(s.jobLogLines)
 
-	// Set prompt (default to "A person talking naturally" if not provided)
-	if input.Prompt == "" {
-		job.Prompt = "high quality, realistic, speaking naturally"
+	// Set prompt (default to DefaultPrompt if not provided)
+	if strings.TrimSpace(input.Prompt) == "" {
+		job.Prompt = DefaultPrompt
 	} else {
 		job.Prompt = input.Prompt
 	}
@@ -192,7 +647,30 @@ func (s *ProcessVideoService) CreateJob(ctx context.Context, input ProcessVideoI
 		return nil, fmt.Errorf("%w: %s", ErrInvalidProvider, input.Provider)
 	}
 
-	s.logger.Info("creating new job",
+	if !job.Priority.IsValid() {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPriority, input.Priority)
+	}
+	if !media.OutputFormat(job.OutputFormat).IsValid() {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidOutputFormat, input.OutputFormat)
+	}
+	if input.TargetFPS < 0 {
+		return nil, fmt.Errorf("%w: %g", ErrInvalidTargetFPS, input.TargetFPS)
+	}
+	if job.ContinuityMode != ContinuityNone && job.ContinuityMode != ContinuityLastFrame {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidContinuityMode, input.ContinuityMode)
+	}
+
+	// Only RunPod accepts video sources
+	if input.InputType == InputTypeVideo && job.Provider != ProviderRunPod {
+		return nil, fmt.Errorf("%w: %s does not accept %s inputs", ErrUnsupportedInputType, job.Provider, input.InputType)
+	}
+	// A video source has no single image to replace with the previous frame
+	if input.InputType == InputTypeVideo && job.ContinuityMode == ContinuityLastFrame {
+		return nil, fmt.Errorf("%w: %s continuity needs an image input", ErrUnsupportedInputType, ContinuityLastFrame)
+	}
+
+	ctx = withJobLog(ctx, job.Logs)
+	s.log(ctx).Info("creating new job",
 		slog.String("job_id", job.ID),
 		slog.String("provider", string(job.Provider)),
 		slog.String("prompt", job.Prompt),
@@ -200,10 +678,17 @@ func (s *ProcessVideoService) CreateJob(ctx context.Context, input ProcessVideoI
 		slog.Int("height", input.Height),
 		slog.Bool("push_to_s3", input.PushToS3),
 		slog.Bool("force_offload", input.ForceOffload),
+		slog.String("person_count", input.PersonCount),
+		slog.String("input_type", input.InputType),
+		slog.String("priority", string(job.Priority)),
+		slog.Float64("target_fps", input.TargetFPS),
+		slog.String("continuity_mode", job.ContinuityMode),
+		slog.Duration("retention", job.Retention),
+		slog.Bool("delete_after_download", job.DeleteAfterDownload),
 	)
 
 	if err := s.repo.Save(ctx, job); err != nil {
-		s.logger.Error("failed to save job",
+		s.log(ctx).Error("failed to save job",
 			slog.String("job_id", job.ID),
 			slog.String("error", err.Error()),
 		)
@@ -222,6 +707,29 @@ func (s *ProcessVideoService) GetJob(ctx context.Context, id string) (*Job, erro
 	return job, nil
 }
 
+// ListJobs returns the jobs whose Metadata contains every key/value pair of
+// tags, newest first. An empty tags returns every job.
+func (s *ProcessVideoService) ListJobs(ctx context.Context, tags map[string]string) ([]*Job, error) {
+	jobs, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list jobs: %w", err)
+	}
+
+	matched := jobs[:0]
+	for _, job := range jobs {
+		if job.HasMetadata(tags) {
+			matched = append(matched, job)
+		}
+	}
+	slices.SortFunc(matched, func(a, b *Job) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return matched, nil
+}
+
 // ProcessExistingJob executes the video processing workflow for an existing job.
 // This is used when the job has already been created and needs to be processed.
 func (s *ProcessVideoService) ProcessExistingJob(ctx context.Context, jobID string, input ProcessVideoInput) (*ProcessVideoOutput, error) {
@@ -234,6 +742,76 @@ func (s *ProcessVideoService) ProcessExistingJob(ctx context.Context, jobID stri
 	return s.processJob(ctx, job, input)
 }
 
+// RetryJob resets a FAILED or TIMED_OUT job back to IN_QUEUE so it can be
+// reprocessed from its retained inputs with ProcessRetriedJob.
+func (s *ProcessVideoService) RetryJob(ctx context.Context, jobID string) (*Job, error) {
+	job, err := s.repo.FindByID(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("find job: %w", err)
+	}
+
+	if !job.IsRetryable() {
+		return nil, fmt.Errorf("%w: status %s", ErrJobNotRetryable, job.GetStatus())
+	}
+
+	for _, path := range []string{job.InputImagePath, job.InputAudioPath} {
+		if path == "" {
+			return nil, ErrRetryInputsUnavailable
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRetryInputsUnavailable, err)
+		}
+	}
+
+	if err := job.ResetForRetry(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrJobNotRetryable, err)
+	}
+
+	if err := s.repo.Save(ctx, job); err != nil {
+		return nil, fmt.Errorf("save job: %w", err)
+	}
+
+	ctx = withJobLog(ctx, job.Logs)
+	s.log(ctx).Info("job queued for retry",
+		slog.String("job_id", job.ID),
+		slog.String("provider", string(job.Provider)),
+	)
+
+	return job, nil
+}
+
+// ProcessRetriedJob executes the video processing workflow for a job reset by
+// RetryJob, reusing the inputs and settings recorded on the job.
+func (s *ProcessVideoService) ProcessRetriedJob(ctx context.Context, jobID string) (*ProcessVideoOutput, error) {
+	job, err := s.repo.FindByID(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("find job: %w", err)
+	}
+
+	input := ProcessVideoInput{
+		Prompt:           job.Prompt,
+		Width:            job.Width,
+		Height:           job.Height,
+		PushToS3:         job.PushToS3,
+		Provider:         string(job.Provider),
+		DryRun:           job.DryRun,
+		ForceOffload:     job.ForceOffload,
+		ResizeMode:       job.ResizeMode,
+		PersonCount:      job.PersonCount,
+		InputType:        job.InputType,
+		OutputFormat:     job.OutputFormat,
+		TargetFPS:        job.TargetFPS,
+		StrictDimensions: job.StrictDimensions,
+		UseOriginalAudio: job.UseOriginalAudio,
+		ContinuityMode:   job.ContinuityMode,
+		Metadata:         job.Metadata,
+		imagePath:        job.InputImagePath,
+		audioPath:        job.InputAudioPath,
+	}
+
+	return s.processJob(ctx, job, input)
+}
+
 // Process executes the complete video processing workflow.
 //
 // The workflow:
@@ -257,19 +835,57 @@ func (s *ProcessVideoService) Process(ctx context.Context, input ProcessVideoInp
 
 // processJob executes the video processing workflow for the given job.
 func (s *ProcessVideoService) processJob(ctx context.Context, job *Job, input ProcessVideoInput) (*ProcessVideoOutput, error) {
+	ctx = withJobLog(ctx, job.Logs)
+
 	// Get appropriate generator for the provider
 	gen, err := s.getGenerator(job.Provider)
 	if err != nil {
 		return s.failJob(ctx, job, err.Error())
 	}
 
-	// Track temporary files for cleanup
-	var tempFiles []string
+	// Dry-run jobs must never reach the provider or storage uploads, even if the
+	// workflow below is reordered. The early return after splitting is the normal
+	// exit; these guards only matter if that return is ever bypassed.
+	if input.DryRun {
+		gen = dryRunGenerator{}
+		input.PushToS3 = false
+	}
+
+	// Register the job so CancelJob can stop processing
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s.trackActive(job, cancel)
+	defer s.untrackActive(job.ID)
+
+	// Skip jobs cancelled (or otherwise finished) while they waited in the
+	// queue, before any file is created. The status is read again now that
+	// the job is registered: CancelJob either saved the cancellation before
+	// this point or finds the job active and stops it.
+	current, err := s.repo.FindByID(ctx, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("find job: %w", err)
+	}
+	if status := current.GetStatus(); status != StatusInQueue {
+		s.log(ctx).Info("skipping job that is no longer queued",
+			slog.String("job_id", job.ID),
+			slog.String("status", string(status)),
+		)
+		return &ProcessVideoOutput{JobID: job.ID, Status: status}, nil
+	}
+
+	// Track temporary files for cleanup on the job, so that CancelJob can remove
+	// them right away. Inputs are kept when the job fails or times out so that
+	// RetryJob can reprocess them.
+	var inputFiles []string
 	defer func() { //nolint:contextcheck // Using context.Background() intentionally for cleanup
+		tempFiles := job.GetTempFiles()
+		if !job.IsRetryable() {
+			tempFiles = append(tempFiles, inputFiles...)
+		}
 		if len(tempFiles) > 0 {
 			// Cleanup should happen even after the original context is cancelled
 			if cleanupErr := s.storage.CleanupTemp(context.Background(), tempFiles); cleanupErr != nil {
-				s.logger.Warn("failed to cleanup temp files",
+				s.log(ctx).Warn("failed to cleanup temp files",
 					slog.String("job_id", job.ID),
 					slog.String("error", cleanupErr.Error()),
 				)
@@ -279,97 +895,188 @@ func (s *ProcessVideoService) processJob(ctx context.Context, job *Job, input Pr
 
 	// Transition to RUNNING state
 	if err := job.Start(); err != nil {
-		s.logger.Error("failed to start job",
+		s.log(ctx).Error("failed to start job",
 			slog.String("job_id", job.ID),
 			slog.String("error", err.Error()),
 		)
 		return s.failJob(ctx, job, fmt.Sprintf("failed to start job: %v", err))
 	}
+	job.SetStage(StagePreprocessing)
 	if err := s.repo.Save(ctx, job); err != nil {
 		return nil, fmt.Errorf("save job: %w", err)
 	}
 
-	s.logger.Info("job started, processing video",
+	s.log(ctx).Info("job started, processing video",
 		slog.String("job_id", job.ID),
 		slog.String("provider", string(job.Provider)),
 	)
 
-	// Step 1: Decode and save input image
-	imagePath, err := s.saveBase64ToTemp(ctx, input.ImageBase64, "image.png")
-	if err != nil {
-		s.logger.Error("failed to save image",
-			slog.String("job_id", job.ID),
-			slog.String("error", err.Error()),
-		)
-		return s.failJob(ctx, job, fmt.Sprintf("failed to save image: %v", err))
+	// Step 1: Decode (or download) and save the source image or video, unless retained from a previous run
+	isVideo := input.InputType == InputTypeVideo
+	imagePath := input.imagePath
+	if imagePath == "" {
+		source := "image"
+		if isVideo {
+			source = "video"
+			imagePath, err = s.saveInputToTemp(ctx, input.VideoBase64, "", "video.mp4")
+		} else {
+			imagePath, err = s.saveInputToTemp(ctx, input.ImageBase64, input.ImageURL, "image.png")
+		}
+		if err != nil {
+			s.log(ctx).Error("failed to save "+source,
+				slog.String("job_id", job.ID),
+				slog.String("error", err.Error()),
+			)
+			return s.failJob(ctx, job, fmt.Sprintf("failed to save %s: %v", source, err))
+		}
 	}
-	tempFiles = append(tempFiles, imagePath)
+	inputFiles = append(inputFiles, imagePath)
 	job.InputImagePath = imagePath
 
-	// Step 2: Decode and save input audio
-	audioPath, err := s.saveBase64ToTemp(ctx, input.AudioBase64, "audio.wav")
-	if err != nil {
-		s.logger.Error("failed to save audio",
-			slog.String("job_id", job.ID),
-			slog.String("error", err.Error()),
-		)
-		return s.failJob(ctx, job, fmt.Sprintf("failed to save audio: %v", err))
+	// Step 2: Decode (or download) and save input audio, unless retained from a previous run
+	audioPath := input.audioPath
+	if audioPath == "" {
+		audioPath, err = s.saveInputToTemp(ctx, input.AudioBase64, input.AudioURL, "audio.wav")
+		if err != nil {
+			s.log(ctx).Error("failed to save audio",
+				slog.String("job_id", job.ID),
+				slog.String("error", err.Error()),
+			)
+			return s.failJob(ctx, job, fmt.Sprintf("failed to save audio: %v", err))
+		}
 	}
-	tempFiles = append(tempFiles, audioPath)
+	inputFiles = append(inputFiles, audioPath)
 	job.InputAudioPath = audioPath
 
-	s.logger.Info("input files saved",
+	s.log(ctx).Info("input files saved",
 		slog.String("job_id", job.ID),
 		slog.String("image_path", imagePath),
 		slog.String("audio_path", audioPath),
 	)
 
-	// Step 3: Resize image with padding
-	// Image is always resized to 1024x1024 (optimal resolution for lip-sync model)
-	// The input.Width and input.Height are used only for output video dimensions
-	const imageResizeWidth = 1024
-	const imageResizeHeight = 1024
-	resizedImagePath := filepath.Join(filepath.Dir(imagePath), fmt.Sprintf("resized_%s.png", job.ID))
-	if err := s.processor.ResizeImageWithPadding(ctx, imagePath, resizedImagePath, imageResizeWidth, imageResizeHeight); err != nil {
-		s.logger.Error("failed to resize image",
+	// Probe the inputs so that empty or corrupt files fail here with a clear
+	// error instead of deep inside splitting or at the provider
+	probe, err := s.probeInputs(ctx, isVideo, imagePath, audioPath)
+	if err != nil {
+		s.log(ctx).Error("invalid input",
 			slog.String("job_id", job.ID),
 			slog.String("error", err.Error()),
 		)
-		return s.failJob(ctx, job, fmt.Sprintf("failed to resize image: %v", err))
+		return s.failJob(ctx, job, fmt.Sprintf("invalid input: %v", err))
 	}
-	tempFiles = append(tempFiles, resizedImagePath)
+	s.log(ctx).Info("inputs probed",
+		slog.String("job_id", job.ID),
+		slog.Int("source_width", probe.ImageWidth),
+		slog.Int("source_height", probe.ImageHeight),
+		slog.Float64("audio_duration_sec", probe.AudioDurationSec),
+	)
 
-	// Read resized image as base64
-	resizedImageB64, err := s.fileToBase64(resizedImagePath)
-	if err != nil {
-		s.logger.Error("failed to encode resized image",
+	// Providers only accept PNG and JPEG, so other decodable formats (WEBP,
+	// GIF, ...) are converted to PNG first
+	if !isVideo && !media.IsSupportedImageCodec(probe.ImageCodec) {
+		normalizedPath := filepath.Join(filepath.Dir(imagePath), fmt.Sprintf("normalized_%s.png", job.ID))
+		if err := s.processor.NormalizeImage(ctx, imagePath, normalizedPath); err != nil {
+			s.log(ctx).Error("failed to convert image",
+				slog.String("job_id", job.ID),
+				slog.String("codec", probe.ImageCodec),
+				slog.String("error", err.Error()),
+			)
+			return s.failJob(ctx, job, fmt.Sprintf("invalid input: image format %q cannot be converted: %v", probe.ImageCodec, err))
+		}
+		job.AddTempFiles(normalizedPath)
+		s.log(ctx).Info("image converted to PNG",
 			slog.String("job_id", job.ID),
-			slog.String("error", err.Error()),
+			slog.String("codec", probe.ImageCodec),
 		)
-		return s.failJob(ctx, job, fmt.Sprintf("failed to encode resized image: %v", err))
+		imagePath = normalizedPath
 	}
 
-	s.logger.Info("image resized",
-		slog.String("job_id", job.ID),
-		slog.Int("image_width", imageResizeWidth),
-		slog.Int("image_height", imageResizeHeight),
-		slog.Int("video_width", input.Width),
-		slog.Int("video_height", input.Height),
-	)
+	// Step 3: Prepare the source. Videos are passed through as-is; images are
+	// resized with padding (or crop-to-fill when requested)
+	var sourceB64 string
+	if isVideo {
+		// The provider scales the video to the requested output dimensions itself
+		sourceB64, err = s.fileToBase64(imagePath)
+		if err != nil {
+			s.log(ctx).Error("failed to encode video",
+				slog.String("job_id", job.ID),
+				slog.String("error", err.Error()),
+			)
+			return s.failJob(ctx, job, fmt.Sprintf("failed to encode video: %v", err))
+		}
+	} else {
+		// Image is always resized to 1024x1024 (optimal resolution for lip-sync model)
+		// The input.Width and input.Height are used only for output video dimensions
+		const imageResizeWidth = 1024
+		const imageResizeHeight = 1024
+		if s.inMemoryResize {
+			data, err := s.processor.ResizeImageToPNG(ctx, imagePath, imageResizeWidth, imageResizeHeight, media.ResizeMode(input.ResizeMode))
+			if err != nil {
+				s.log(ctx).Error("failed to resize image",
+					slog.String("job_id", job.ID),
+					slog.String("error", err.Error()),
+				)
+				return s.failJob(ctx, job, fmt.Sprintf("failed to resize image: %v", err))
+			}
+			sourceB64 = base64.StdEncoding.EncodeToString(data)
+		} else {
+			resizedImagePath := filepath.Join(filepath.Dir(imagePath), fmt.Sprintf("resized_%s.png", job.ID))
+			resize := s.processor.ResizeImageWithPadding
+			if media.ResizeMode(input.ResizeMode) == media.ResizeModeCrop {
+				resize = s.processor.ResizeImageCropToFill
+			}
+			if err := resize(ctx, imagePath, resizedImagePath, imageResizeWidth, imageResizeHeight); err != nil {
+				s.log(ctx).Error("failed to resize image",
+					slog.String("job_id", job.ID),
+					slog.String("error", err.Error()),
+				)
+				return s.failJob(ctx, job, fmt.Sprintf("failed to resize image: %v", err))
+			}
+			job.AddTempFiles(resizedImagePath)
+
+			// Read resized image as base64
+			sourceB64, err = s.fileToBase64(resizedImagePath)
+			if err != nil {
+				s.log(ctx).Error("failed to encode resized image",
+					slog.String("job_id", job.ID),
+					slog.String("error", err.Error()),
+				)
+				return s.failJob(ctx, job, fmt.Sprintf("failed to encode resized image: %v", err))
+			}
+		}
+
+		s.log(ctx).Info("image resized",
+			slog.String("job_id", job.ID),
+			slog.String("resize_mode", input.ResizeMode),
+			slog.Int("image_width", imageResizeWidth),
+			slog.Int("image_height", imageResizeHeight),
+			slog.Int("video_width", input.Width),
+			slog.Int("video_height", input.Height),
+		)
+	}
+
+	// Beam downloads inputs itself, so large media need not travel as base64
+	image := sourceImage{b64: sourceB64}
+	if s.submitByURL && job.Provider == ProviderBeam && !input.DryRun {
+		image.url = s.uploadInput(ctx, job, inputImageKey(job.ID), base64.NewDecoder(base64.StdEncoding, strings.NewReader(sourceB64)))
+		if image.url != "" {
+			defer s.removeInputObjects(context.WithoutCancel(ctx), job) //nolint:contextcheck // cleanup must outlive cancellation
+		}
+	}
 
 	// Step 4: Split audio into chunks
 	outputDir := filepath.Dir(audioPath)
-	audioChunks, err := s.splitter.Split(ctx, audioPath, outputDir, s.splitOpts)
+	audioChunks, err := s.splitter.Split(ctx, audioPath, outputDir, s.splitOptsFor(input.PersonCount))
 	if err != nil {
-		s.logger.Error("failed to split audio",
+		s.log(ctx).Error("failed to split audio",
 			slog.String("job_id", job.ID),
 			slog.String("error", err.Error()),
 		)
 		return s.failJob(ctx, job, fmt.Sprintf("failed to split audio: %v", err))
 	}
-	tempFiles = append(tempFiles, audioChunks...)
+	job.AddTempFiles(audioChunks...)
 
-	s.logger.Info("audio split into chunks",
+	s.log(ctx).Info("audio split into chunks",
 		slog.String("job_id", job.ID),
 		slog.Int("chunk_count", len(audioChunks)),
 	)
@@ -391,12 +1098,13 @@ func (s *ProcessVideoService) processJob(ctx context.Context, job *Job, input Pr
 
 	// Dry-run mode: skip provider processing and complete immediately
 	if input.DryRun {
-		s.logger.Info("dry-run mode: skipping provider processing",
+		s.log(ctx).Info("dry-run mode: skipping provider processing",
 			slog.String("job_id", job.ID),
 			slog.String("provider", string(job.Provider)),
 			slog.Int("chunk_count", len(audioChunks)),
 		)
 		job.UpdateProgress(100)
+		job.SetStage(StageDone)
 		if err := job.Complete(); err != nil {
 			return nil, fmt.Errorf("complete job: %w", err)
 		}
@@ -409,43 +1117,102 @@ func (s *ProcessVideoService) processJob(ctx context.Context, job *Job, input Pr
 		}, nil
 	}
 
-	// Step 5: Process chunks sequentially with frame continuity
-	videoPaths, err := s.processChunksSequential(ctx, job, gen, resizedImageB64, audioChunks, input.Width, input.Height, input.ForceOffload)
+	// Step 5: Process chunks, several at a time when configured
+	s.enterStage(ctx, job, StageGenerating)
+	videoPaths, err := s.processChunks(ctx, job, gen, image, audioChunks, input.Width, input.Height, input.ForceOffload)
 	if err != nil {
-		s.logger.Error("failed to process chunks",
+		s.log(ctx).Error("failed to process chunks",
 			slog.String("job_id", job.ID),
 			slog.String("error", err.Error()),
 		)
 		return s.failJob(ctx, job, err.Error())
 	}
-	tempFiles = append(tempFiles, videoPaths...)
+	job.AddTempFiles(videoPaths...)
 
-	s.logger.Info("all chunks processed",
+	s.log(ctx).Info("all chunks processed",
 		slog.String("job_id", job.ID),
 		slog.Int("video_count", len(videoPaths)),
 	)
 
-	// Step 6: Join videos
-	outputVideoPath := filepath.Join(outputDir, fmt.Sprintf("output_%s.mp4", job.ID))
-	if err := s.processor.JoinVideos(ctx, videoPaths, outputVideoPath); err != nil {
-		s.logger.Error("failed to join videos",
+	// Step 6: Join videos, after making sure they all have the requested
+	// dimensions and normalizing their frame rate when requested
+	videoPaths, resized, err := s.fitChunkDimensions(ctx, job, videoPaths, input)
+	job.AddTempFiles(resized...)
+	if err != nil {
+		s.log(ctx).Error("chunk dimensions check failed",
 			slog.String("job_id", job.ID),
 			slog.String("error", err.Error()),
 		)
-		return s.failJob(ctx, job, fmt.Sprintf("failed to join videos: %v", err))
+		return s.failJob(ctx, job, err.Error())
+	}
+
+	if input.TargetFPS > 0 {
+		videoPaths, err = s.normalizeChunkFPS(ctx, job, videoPaths, input.TargetFPS)
+		job.AddTempFiles(videoPaths...)
+		if err != nil {
+			s.log(ctx).Error("failed to normalize chunk frame rate",
+				slog.String("job_id", job.ID),
+				slog.Float64("target_fps", input.TargetFPS),
+				slog.String("error", err.Error()),
+			)
+			return s.failJob(ctx, job, fmt.Sprintf("failed to normalize frame rate: %v", err))
+		}
+	}
+
+	outputFormat := media.OutputFormat(input.OutputFormat)
+	if !outputFormat.IsValid() {
+		outputFormat = media.OutputFormatMP4
+	}
+	outputVideoPath := filepath.Join(outputDir, "output_"+job.ID+outputFormat.Extension())
+	joinedVideoPath := outputVideoPath
+	if input.UseOriginalAudio {
+		// Join into an intermediate file whose audio is replaced below
+		joinedVideoPath = filepath.Join(outputDir, "joined_"+job.ID+outputFormat.Extension())
+		job.AddTempFiles(joinedVideoPath)
+	}
+	s.enterStage(ctx, job, StageJoining)
+	if err := s.joinVideosWithRetry(ctx, job, videoPaths, joinedVideoPath); err != nil {
+		s.log(ctx).Error("failed to join videos",
+			slog.String("job_id", job.ID),
+			slog.String("error", err.Error()),
+		)
+		return s.failJob(ctx, job, fmt.Sprintf("failed to join videos: %v", err))
+	}
+
+	if input.UseOriginalAudio {
+		if err := s.processor.ReplaceAudio(ctx, joinedVideoPath, audioPath, outputVideoPath); err != nil {
+			s.log(ctx).Error("failed to replace audio",
+				slog.String("job_id", job.ID),
+				slog.String("error", err.Error()),
+			)
+			return s.failJob(ctx, job, fmt.Sprintf("failed to replace audio: %v", err))
+		}
+	}
+
+	s.log(ctx).Info("videos joined",
+		slog.String("job_id", job.ID),
+		slog.String("output_path", outputVideoPath),
+	)
+	job.UpdateProgress(progressJoined)
+	s.saveProgress(ctx, job)
+
+	// Step 6b: Optional preview thumbnail (best effort, never fails the job)
+	var thumbnailPath, thumbnailURL string
+	if s.thumbnails {
+		thumbnailPath, thumbnailURL = s.createThumbnail(ctx, job, outputVideoPath, input.PushToS3)
+		if thumbnailURL != "" {
+			// Thumbnail is in S3, so the local copy is only temporary
+			job.AddTempFiles(thumbnailPath)
+		}
 	}
 
-	s.logger.Info("videos joined",
-		slog.String("job_id", job.ID),
-		slog.String("output_path", outputVideoPath),
-	)
-
 	// Step 7: Optional S3 upload
 	var videoURL string
 	if input.PushToS3 {
+		s.enterStage(ctx, job, StageUploading)
 		videoFile, err := os.Open(outputVideoPath) // #nosec G304 - outputVideoPath is constructed internally
 		if err != nil {
-			s.logger.Error("failed to open output video for S3 upload",
+			s.log(ctx).Error("failed to open output video for S3 upload",
 				slog.String("job_id", job.ID),
 				slog.String("error", err.Error()),
 			)
@@ -453,30 +1220,42 @@ func (s *ProcessVideoService) processJob(ctx context.Context, job *Job, input Pr
 		}
 		defer func() { _ = videoFile.Close() }()
 
-		s3Key := fmt.Sprintf("videos/%s.mp4", job.ID)
-		videoURL, err = s.storage.UploadToS3(ctx, s3Key, videoFile)
+		s3Key := videoKey(job.ID, outputVideoPath)
+		videoURL, err = s.storage.Upload(ctx, s3Key, videoFile)
 		if err != nil {
-			s.logger.Error("failed to upload to S3",
+			s.log(ctx).Error("failed to upload to S3",
 				slog.String("job_id", job.ID),
 				slog.String("error", err.Error()),
 			)
 			return s.failJob(ctx, job, fmt.Sprintf("failed to upload to S3: %v", err))
 		}
 
-		s.logger.Info("video uploaded to S3",
+		s.log(ctx).Info("video uploaded to S3",
 			slog.String("job_id", job.ID),
 			slog.String("video_url", videoURL),
 		)
-
-		// Add output video to temp files for cleanup since it's now in S3
-		tempFiles = append(tempFiles, outputVideoPath)
+		job.SetS3Key(s3Key)
+		job.UpdateProgress(progressUploaded)
+		s.saveProgress(ctx, job)
+
+		// Add output video to temp files for cleanup since it's now in S3,
+		// unless the local copy is kept
+		if !s.keepLocalOutput {
+			job.AddTempFiles(outputVideoPath)
+		}
 	}
 
 	// Step 8: Complete job
-	job.SetOutput(outputVideoPath, videoURL)
+	job.SetOutput(outputVideoPath)
+	if thumbnailURL != "" {
+		job.SetThumbnail(thumbnailPath, thumbnailKey(job.ID))
+	} else if thumbnailPath != "" {
+		job.SetThumbnail(thumbnailPath, "")
+	}
 	job.UpdateProgress(100)
+	job.SetStage(StageDone)
 	if err := job.Complete(); err != nil {
-		s.logger.Error("failed to complete job",
+		s.log(ctx).Error("failed to complete job",
 			slog.String("job_id", job.ID),
 			slog.String("error", err.Error()),
 		)
@@ -486,108 +1265,468 @@ func (s *ProcessVideoService) processJob(ctx context.Context, job *Job, input Pr
 		return nil, fmt.Errorf("save job: %w", err)
 	}
 
-	s.logger.Info("job completed successfully",
+	s.log(ctx).Info("job completed successfully",
 		slog.String("job_id", job.ID),
 		slog.String("status", string(job.Status)),
 	)
 
 	return &ProcessVideoOutput{
-		JobID:     job.ID,
-		Status:    job.Status,
-		VideoPath: outputVideoPath,
-		VideoURL:  videoURL,
+		JobID:         job.ID,
+		Status:        job.Status,
+		VideoPath:     outputVideoPath,
+		VideoURL:      videoURL,
+		ThumbnailPath: thumbnailPath,
+		ThumbnailURL:  thumbnailURL,
 	}, nil
 }
 
-// processChunksSequential processes audio chunks one by one, using the same
-// source image for all chunks to maintain visual consistency and avoid
-// cumulative visual drift.
-func (s *ProcessVideoService) processChunksSequential(
+// createThumbnail extracts a preview image next to the output video and, when
+// pushToS3 is set, uploads it. Failures are logged and yield empty results so
+// a missing preview never fails an otherwise successful job.
+func (s *ProcessVideoService) createThumbnail(ctx context.Context, job *Job, videoPath string, pushToS3 bool) (thumbnailPath, thumbnailURL string) {
+	thumbnailPath = filepath.Join(filepath.Dir(videoPath), fmt.Sprintf("thumbnail_%s.jpg", job.ID))
+	if err := s.processor.GenerateThumbnail(ctx, videoPath, thumbnailPath, s.thumbnailAtSec); err != nil {
+		s.log(ctx).Warn("failed to generate thumbnail",
+			slog.String("job_id", job.ID),
+			slog.String("error", err.Error()),
+		)
+		return "", ""
+	}
+
+	if !pushToS3 {
+		return thumbnailPath, ""
+	}
+
+	thumbFile, err := os.Open(thumbnailPath) // #nosec G304 - thumbnailPath is constructed internally
+	if err != nil {
+		s.log(ctx).Warn("failed to open thumbnail for S3 upload",
+			slog.String("job_id", job.ID),
+			slog.String("error", err.Error()),
+		)
+		return thumbnailPath, ""
+	}
+	defer func() { _ = thumbFile.Close() }()
+
+	thumbnailURL, err = s.storage.Upload(ctx, thumbnailKey(job.ID), thumbFile)
+	if err != nil {
+		s.log(ctx).Warn("failed to upload thumbnail to S3",
+			slog.String("job_id", job.ID),
+			slog.String("error", err.Error()),
+		)
+		return thumbnailPath, ""
+	}
+
+	s.log(ctx).Info("thumbnail uploaded to S3",
+		slog.String("job_id", job.ID),
+		slog.String("thumbnail_url", thumbnailURL),
+	)
+	return thumbnailPath, thumbnailURL
+}
+
+// enterStage records that job entered stage and persists it so pollers see the new phase.
+func (s *ProcessVideoService) enterStage(ctx context.Context, job *Job, stage Stage) {
+	job.SetStage(stage)
+	s.saveProgress(ctx, job)
+}
+
+// saveProgress persists an intermediate progress or stage update. Failures
+// are only logged: the job keeps running and the next save catches up.
+func (s *ProcessVideoService) saveProgress(ctx context.Context, job *Job) {
+	if err := s.repo.Save(ctx, job); err != nil {
+		s.log(ctx).Warn("failed to save job progress",
+			slog.String("job_id", job.ID),
+			slog.String("stage", string(job.GetStage())),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// sourceImage is the resized image (or source video) every chunk of a job is generated from.
+type sourceImage struct {
+	b64 string // base64-encoded PNG, or the video for video inputs
+	url string // remote copy for providers that fetch inputs by URL; empty when not uploaded
+}
+
+// processChunks generates a video for each audio chunk, running up to
+// maxConcurrentChunks chunks at once. Every chunk uses the same source image,
+// which keeps chunks independent and avoids cumulative visual drift.
+// The first chunk failure cancels the chunks still running.
+//
+// Jobs using ContinuityLastFrame are handed to processChunksChained instead.
+func (s *ProcessVideoService) processChunks(
 	ctx context.Context,
 	job *Job,
 	gen generator.Generator,
-	initialImageB64 string,
+	image sourceImage,
 	audioChunks []string,
 	width, height int,
 	forceOffload bool,
 ) ([]string, error) {
-	videoPaths := make([]string, 0, len(audioChunks))
+	if job.ContinuityMode == ContinuityLastFrame && len(audioChunks) > 1 {
+		return s.processChunksChained(ctx, job, gen, image, audioChunks, width, height, forceOffload)
+	}
+
+	chunkCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	videoPaths := make([]string, len(audioChunks))
+	sem := make(chan struct{}, s.maxConcurrentChunks)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		done     int
+		firstErr error
+	)
 
 	for i, chunkPath := range audioChunks {
-		// Check context before starting each chunk
+		// Wait for a free slot, stopping early once a chunk has failed
 		select {
-		case <-ctx.Done():
-			return nil, fmt.Errorf("context cancelled: %w", ctx.Err())
-		default:
+		case <-chunkCtx.Done():
+		case sem <- struct{}{}:
+		}
+		if chunkCtx.Err() != nil {
+			break
 		}
 
-		s.logger.Info("processing chunk sequentially",
+		s.log(ctx).Info("processing chunk",
 			slog.String("job_id", job.ID),
 			slog.Int("chunk_index", i),
 			slog.Int("total_chunks", len(audioChunks)),
 		)
 
-		// Process this chunk with the original image
-		videoPath, err := s.processChunkWithGenerator(
-			ctx, job, gen, i, initialImageB64, chunkPath, width, height, forceOffload,
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			videoPath, err := s.processChunkWithRetry(
+				chunkCtx, job, gen, i, image, chunkPath, width, height, forceOffload,
+			)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("chunk %d failed: %w", i, err)
+					cancel()
+				}
+				return
+			}
+			videoPaths[i] = videoPath
+
+			// Update progress
+			done++
+			job.UpdateProgress(done * progressGenerated / len(audioChunks))
+			s.saveProgress(ctx, job)
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+	return videoPaths, nil
+}
+
+// processChunksChained generates the chunk videos one after the other,
+// animating each chunk from the last frame of the previous chunk's video so
+// that motion carries over between chunks. The first chunk uses image.
+func (s *ProcessVideoService) processChunksChained(
+	ctx context.Context,
+	job *Job,
+	gen generator.Generator,
+	image sourceImage,
+	audioChunks []string,
+	width, height int,
+	forceOffload bool,
+) ([]string, error) {
+	videoPaths := make([]string, len(audioChunks))
+	for i, chunkPath := range audioChunks {
+		s.log(ctx).Info("processing chunk",
+			slog.String("job_id", job.ID),
+			slog.Int("chunk_index", i),
+			slog.Int("total_chunks", len(audioChunks)),
+			slog.String("continuity_mode", ContinuityLastFrame),
 		)
 
+		videoPath, err := s.processChunkWithRetry(ctx, job, gen, i, image, chunkPath, width, height, forceOffload)
 		if err != nil {
 			return nil, fmt.Errorf("chunk %d failed: %w", i, err)
 		}
-		videoPaths = append(videoPaths, videoPath)
+		videoPaths[i] = videoPath
 
-		// Update progress
-		progress := ((i + 1) * 90) / len(audioChunks) // Reserve 10% for joining
-		job.UpdateProgress(progress)
-		if err := s.repo.Save(ctx, job); err != nil {
-			s.logger.Warn("failed to save job progress",
-				slog.String("job_id", job.ID),
-				slog.String("error", err.Error()),
-			)
+		job.UpdateProgress((i + 1) * progressGenerated / len(audioChunks))
+		s.saveProgress(ctx, job)
+
+		if i == len(audioChunks)-1 {
+			break
+		}
+		frame, err := s.processor.ExtractLastFrame(ctx, videoPath)
+		if err != nil {
+			return nil, fmt.Errorf("chunk %d failed: extract last frame: %w", i, err)
 		}
+		// The frame only exists locally, so it is always submitted as base64
+		image = sourceImage{b64: base64.StdEncoding.EncodeToString(frame)}
 	}
 
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
 	return videoPaths, nil
 }
 
+// processChunkWithRetry processes a chunk, resubmitting it up to maxChunkRetries
+// times with exponential backoff when the provider fails transiently. A timed
+// out provider job may still be running, so it is cancelled before the chunk
+// is resubmitted.
+func (s *ProcessVideoService) processChunkWithRetry(
+	ctx context.Context,
+	job *Job,
+	gen generator.Generator,
+	idx int,
+	image sourceImage,
+	audioPath string,
+	width, height int,
+	forceOffload bool,
+) (string, error) {
+	backoff := s.chunkRetryBackoff
+	for retry := 0; ; retry++ {
+		videoPath, err := s.processChunkWithGenerator(ctx, job, gen, idx, image, audioPath, width, height, forceOffload)
+		if err == nil || retry >= s.maxChunkRetries || !isRetryableChunkError(err) {
+			return videoPath, err
+		}
+
+		s.log(ctx).Warn("chunk failed, retrying",
+			slog.String("job_id", job.ID),
+			slog.Int("chunk_index", idx),
+			slog.Int("retry", retry+1),
+			slog.Int("max_retries", s.maxChunkRetries),
+			slog.Duration("backoff", backoff),
+			slog.String("error", err.Error()),
+		)
+		if errors.Is(err, ErrProviderJobTimedOut) {
+			s.cancelTimedOutChunk(ctx, job, gen, idx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("context cancelled: %w", ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// cancelTimedOutChunk issues a best-effort provider cancel for the last
+// submission of a chunk, so a retry does not leave it running on a GPU.
+func (s *ProcessVideoService) cancelTimedOutChunk(ctx context.Context, job *Job, gen generator.Generator, idx int) {
+	job.mu.Lock()
+	var providerJobID string
+	if idx < len(job.Chunks) {
+		providerJobID = job.Chunks[idx].RunPodJobID
+	}
+	job.mu.Unlock()
+	if providerJobID == "" {
+		return
+	}
+
+	cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), providerCancelTimeout)
+	defer cancel()
+	if err := gen.Cancel(cancelCtx, providerJobID); err != nil {
+		s.log(ctx).Warn("provider cancel of timed out chunk failed",
+			slog.String("job_id", job.ID),
+			slog.Int("chunk_index", idx),
+			slog.String("provider_job_id", providerJobID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// joinVideosWithRetry joins the chunk videos, retrying up to maxJoinRetries
+// times with exponential backoff so a transient failure (e.g. a temp disk
+// hiccup) does not discard chunks that were already generated.
+func (s *ProcessVideoService) joinVideosWithRetry(ctx context.Context, job *Job, videoPaths []string, outputPath string) error {
+	backoff := s.joinRetryBackoff
+	for retry := 0; ; retry++ {
+		err := s.processor.JoinVideos(ctx, videoPaths, outputPath)
+		if err == nil || retry >= s.maxJoinRetries || ctx.Err() != nil {
+			return err
+		}
+
+		s.log(ctx).Warn("joining videos failed, retrying",
+			slog.String("job_id", job.ID),
+			slog.Int("retry", retry+1),
+			slog.Int("max_retries", s.maxJoinRetries),
+			slog.Duration("backoff", backoff),
+			slog.String("error", err.Error()),
+		)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context cancelled: %w", ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// fitChunkDimensions probes every chunk video and compares its size with the
+// requested width and height. A mismatching chunk fails the job with
+// ErrChunkDimensionsMismatch when StrictDimensions is set; otherwise it is
+// resized to the requested size using the job's resize mode. It returns the
+// paths to join along with the resized videos, which are temporary.
+func (s *ProcessVideoService) fitChunkDimensions(ctx context.Context, job *Job, videoPaths []string, input ProcessVideoInput) ([]string, []string, error) {
+	mode := media.ResizeMode(input.ResizeMode)
+	if mode == "" {
+		mode = media.ResizeModePad
+	}
+
+	fitted := make([]string, 0, len(videoPaths))
+	var resized []string
+	for idx, src := range videoPaths {
+		info, err := s.processor.ProbeVideo(ctx, src)
+		if err != nil {
+			return nil, resized, fmt.Errorf("probe chunk %d video: %w", idx, err)
+		}
+		if info.Width == input.Width && info.Height == input.Height {
+			fitted = append(fitted, src)
+			continue
+		}
+
+		if input.StrictDimensions {
+			return nil, resized, fmt.Errorf("%w: chunk %d is %dx%d, expected %dx%d",
+				ErrChunkDimensionsMismatch, idx, info.Width, info.Height, input.Width, input.Height)
+		}
+
+		s.log(ctx).Warn("chunk dimensions do not match, resizing",
+			slog.String("job_id", job.ID),
+			slog.Int("chunk_index", idx),
+			slog.Int("width", info.Width),
+			slog.Int("height", info.Height),
+			slog.Int("expected_width", input.Width),
+			slog.Int("expected_height", input.Height),
+		)
+		ext := filepath.Ext(src)
+		dst := fmt.Sprintf("%s_%dx%d%s", strings.TrimSuffix(src, ext), input.Width, input.Height, ext)
+		if err := s.processor.ResizeVideo(ctx, src, dst, input.Width, input.Height, mode); err != nil {
+			return nil, resized, fmt.Errorf("resize chunk %d video: %w", idx, err)
+		}
+		resized = append(resized, dst)
+		fitted = append(fitted, dst)
+	}
+
+	return fitted, resized, nil
+}
+
+// normalizeChunkFPS re-encodes every chunk video to fps and returns the paths
+// of the normalized videos, written next to the originals. On failure the
+// paths normalized so far are returned along with the error, so they can be
+// cleaned up.
+func (s *ProcessVideoService) normalizeChunkFPS(ctx context.Context, job *Job, videoPaths []string, fps float64) ([]string, error) {
+	normalized := make([]string, 0, len(videoPaths))
+	for _, src := range videoPaths {
+		ext := filepath.Ext(src)
+		dst := fmt.Sprintf("%s_%gfps%s", strings.TrimSuffix(src, ext), fps, ext)
+		if err := s.processor.NormalizeFPS(ctx, src, dst, fps); err != nil {
+			return normalized, fmt.Errorf("normalize %s: %w", filepath.Base(src), err)
+		}
+		normalized = append(normalized, dst)
+	}
+
+	s.log(ctx).Info("chunk frame rate normalized",
+		slog.String("job_id", job.ID),
+		slog.Float64("target_fps", fps),
+		slog.Int("video_count", len(normalized)),
+	)
+	return normalized, nil
+}
+
+// splitOptsFor returns the audio split options for a job. Multi-person audio
+// is kept in a single chunk: cutting at the pauses between speakers would
+// generate each turn independently and lose who is talking to whom.
+func (s *ProcessVideoService) splitOptsFor(personCount string) audio.SplitOpts {
+	opts := s.splitOpts
+	if personCount == PersonCountMulti {
+		opts.MaxChunks = 1
+	}
+	return opts
+}
+
+// isRetryableChunkError reports whether a chunk failure is worth resubmitting.
+// Provider failures and timeouts are retried; cancellations are not.
+func isRetryableChunkError(err error) bool {
+	return errors.Is(err, ErrProviderJobFailed) || errors.Is(err, ErrProviderJobTimedOut)
+}
+
 // processChunkWithGenerator processes a single audio chunk using a generator interface.
 func (s *ProcessVideoService) processChunkWithGenerator(
 	ctx context.Context,
 	job *Job,
 	gen generator.Generator,
 	idx int,
-	imageB64, audioPath string,
+	image sourceImage,
+	audioPath string,
 	width, height int,
 	forceOffload bool,
 ) (string, error) {
 	// Update chunk status to processing
 	s.updateChunkStatus(job, idx, ChunkStatusProcessing, "")
 
-	s.logger.Info("processing chunk",
+	s.log(ctx).Info("processing chunk",
 		slog.String("job_id", job.ID),
 		slog.String("provider", string(job.Provider)),
 		slog.Int("chunk_index", idx),
 		slog.Bool("force_offload", forceOffload),
 	)
 
-	// Read audio as base64
-	audioB64, err := s.fileToBase64(audioPath)
-	if err != nil {
-		s.updateChunkStatus(job, idx, ChunkStatusFailed, err.Error())
-		return "", fmt.Errorf("failed to encode audio: %w", err)
-	}
-
 	// Submit using generator interface
 	submitOpts := generator.SubmitOptions{
 		Prompt:       job.Prompt,
 		Width:        width,
 		Height:       height,
 		ForceOffload: forceOffload,
+		PersonCount:  job.PersonCount,
+		InputType:    job.InputType,
+	}
+	job.mu.Lock()
+	if idx < len(job.Chunks) {
+		job.Chunks[idx].Attempts++
+	}
+	job.mu.Unlock()
+
+	// Submit by URL when the image was uploaded, falling back to base64
+	var audioURL string
+	urlGen, byURL := gen.(generator.URLSubmitter)
+	if byURL && image.url != "" {
+		audioURL = s.uploadChunkAudio(ctx, job, idx, audioPath)
 	}
-	providerJobID, err := gen.Submit(ctx, imageB64, audioB64, submitOpts)
+
+	// Wait for a global provider slot, held until polling ends
+	release, err := s.acquireProviderSlot(ctx)
 	if err != nil {
-		s.updateChunkStatus(job, idx, ChunkStatusFailed, err.Error())
+		s.failChunk(ctx, job, idx, err.Error(), ChunkFailure{Stage: FailureStageSubmit})
+		return "", fmt.Errorf("wait for provider slot: %w", err)
+	}
+	defer release()
+
+	var providerJobID string
+	if audioURL != "" {
+		providerJobID, err = urlGen.SubmitByURL(ctx, image.url, audioURL, submitOpts)
+	} else {
+		// Read audio as base64
+		audioB64, encodeErr := s.fileToBase64(audioPath)
+		if encodeErr != nil {
+			s.failChunk(ctx, job, idx, encodeErr.Error(), ChunkFailure{Stage: FailureStagePrepare})
+			return "", fmt.Errorf("failed to encode audio: %w", encodeErr)
+		}
+		providerJobID, err = gen.Submit(ctx, image.b64, audioB64, submitOpts)
+	}
+	if err != nil {
+		s.failChunk(ctx, job, idx, err.Error(), ChunkFailure{Stage: FailureStageSubmit, ProviderError: err.Error()})
 		return "", fmt.Errorf("failed to submit to provider: %w", err)
 	}
 
@@ -595,11 +1734,11 @@ func (s *ProcessVideoService) processChunkWithGenerator(
 	job.mu.Lock()
 	if idx < len(job.Chunks) {
 		job.Chunks[idx].RunPodJobID = providerJobID // Reuse this field for both providers
-		job.Chunks[idx].StartedAt = time.Now()
+		job.Chunks[idx].StartedAt = s.now()
 	}
 	job.mu.Unlock()
 
-	s.logger.Info("chunk submitted to provider",
+	s.log(ctx).Info("chunk submitted to provider",
 		slog.String("job_id", job.ID),
 		slog.String("provider", string(job.Provider)),
 		slog.Int("chunk_index", idx),
@@ -608,36 +1747,43 @@ func (s *ProcessVideoService) processChunkWithGenerator(
 
 	// Poll for result using generator
 	pollResult, err := s.pollForResultWithGenerator(ctx, gen, job.ID, idx, providerJobID)
+	release()
 	if err != nil {
-		s.updateChunkStatus(job, idx, ChunkStatusFailed, err.Error())
+		s.failChunk(ctx, job, idx, err.Error(), ChunkFailure{
+			Stage:          FailureStagePoll,
+			ProviderStatus: string(pollResult.Status),
+			ProviderError:  pollResult.Error,
+		})
 		return "", fmt.Errorf("failed to poll provider: %w", err)
 	}
 
-	// Handle video output differently based on provider
+	// The output comes back inline as base64 (RunPod) or as a URL to
+	// download (Beam); either way it ends up as a file in temp storage.
 	var videoPath string
+	videoFileName := fmt.Sprintf("chunk_%s_%d.mp4", job.ID, idx)
 	switch {
 	case pollResult.VideoBase64 != "":
-		// RunPod path: decode base64 to file
 		videoData, err := base64.StdEncoding.DecodeString(pollResult.VideoBase64)
 		if err != nil {
-			s.updateChunkStatus(job, idx, ChunkStatusFailed, err.Error())
+			s.failChunk(ctx, job, idx, err.Error(), outputFailure(pollResult))
 			return "", fmt.Errorf("failed to decode video: %w", err)
 		}
-		videoFileName := fmt.Sprintf("chunk_%s_%d.mp4", job.ID, idx)
 		videoPath, err = s.storage.SaveTemp(ctx, videoFileName, bytes.NewReader(videoData))
 		if err != nil {
-			s.updateChunkStatus(job, idx, ChunkStatusFailed, err.Error())
+			s.failChunk(ctx, job, idx, err.Error(), outputFailure(pollResult))
 			return "", fmt.Errorf("failed to save video: %w", err)
 		}
 	case pollResult.VideoURL != "":
-		// Beam path: download to temp file
-		videoPath = filepath.Join(filepath.Dir(audioPath), fmt.Sprintf("chunk_%s_%d.mp4", job.ID, idx))
+		// Download next to the chunk audio, which lives in temp storage
+		videoPath = filepath.Join(filepath.Dir(audioPath), videoFileName)
 		if err := gen.DownloadOutput(ctx, pollResult.VideoURL, videoPath); err != nil {
-			s.updateChunkStatus(job, idx, ChunkStatusFailed, err.Error())
+			// Don't leave a partial download behind
+			_ = os.Remove(videoPath)
+			s.failChunk(ctx, job, idx, err.Error(), outputFailure(pollResult))
 			return "", fmt.Errorf("failed to download video: %w", err)
 		}
 	default:
-		s.updateChunkStatus(job, idx, ChunkStatusFailed, ErrNoVideoOutput.Error())
+		s.failChunk(ctx, job, idx, ErrNoVideoOutput.Error(), outputFailure(pollResult))
 		return "", ErrNoVideoOutput
 	}
 
@@ -646,11 +1792,11 @@ func (s *ProcessVideoService) processChunkWithGenerator(
 	if idx < len(job.Chunks) {
 		job.Chunks[idx].Status = ChunkStatusCompleted
 		job.Chunks[idx].OutputPath = videoPath
-		job.Chunks[idx].CompletedAt = time.Now()
+		job.Chunks[idx].CompletedAt = s.now()
 	}
 	job.mu.Unlock()
 
-	s.logger.Info("chunk processing completed",
+	s.log(ctx).Info("chunk processing completed",
 		slog.String("job_id", job.ID),
 		slog.Int("chunk_index", idx),
 		slog.String("video_path", videoPath),
@@ -659,6 +1805,23 @@ func (s *ProcessVideoService) processChunkWithGenerator(
 	return videoPath, nil
 }
 
+// acquireProviderSlot waits for a global provider slot and returns the func
+// that frees it. The returned func may be called more than once.
+func (s *ProcessVideoService) acquireProviderSlot(ctx context.Context) (func(), error) {
+	if s.providerSlots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case s.providerSlots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var once sync.Once
+	return func() { once.Do(func() { <-s.providerSlots }) }, nil
+}
+
 // pollForResultWithGenerator polls using the generator interface until the job completes or fails.
 func (s *ProcessVideoService) pollForResultWithGenerator(
 	ctx context.Context,
@@ -667,24 +1830,44 @@ func (s *ProcessVideoService) pollForResultWithGenerator(
 	chunkIdx int,
 	providerJobID string,
 ) (generator.PollResult, error) {
-	ticker := time.NewTicker(s.pollInterval)
+	interval := s.pollInterval
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	// Bound polling of this chunk so a stuck provider job cannot poll forever
+	pollCtx := ctx
+	if s.chunkTimeout > 0 {
+		var cancel context.CancelFunc
+		pollCtx, cancel = context.WithTimeout(ctx, s.chunkTimeout)
+		defer cancel()
+	}
+
 	var (
 		attempt    int
 		prevStatus generator.Status
 		firstPoll  = true
+		lastResult generator.PollResult
 	)
 
 	for {
 		select {
-		case <-ctx.Done():
+		case <-pollCtx.Done():
+			if ctx.Err() == nil {
+				s.log(ctx).Warn("chunk polling timed out",
+					slog.String("job_id", jobID),
+					slog.Int("chunk_index", chunkIdx),
+					slog.String("provider_job_id", providerJobID),
+					slog.Duration("timeout", s.chunkTimeout),
+					slog.String("last_status", string(lastResult.Status)),
+				)
+				return lastResult, fmt.Errorf("%w: no result after %s", ErrProviderJobTimedOut, s.chunkTimeout)
+			}
 			return generator.PollResult{}, fmt.Errorf("context cancelled: %w", ctx.Err())
 		case <-ticker.C:
 			attempt++
-			pollResult, err := gen.Poll(ctx, providerJobID)
+			pollResult, err := gen.Poll(pollCtx, providerJobID)
 			if err != nil {
-				s.logger.Warn("poll error, retrying",
+				s.log(ctx).Warn("poll error, retrying",
 					slog.String("job_id", jobID),
 					slog.Int("chunk_index", chunkIdx),
 					slog.String("provider_job_id", providerJobID),
@@ -703,7 +1886,7 @@ func (s *ProcessVideoService) pollForResultWithGenerator(
 			if firstPoll {
 				prevStatusStr = "initial"
 			}
-			s.logger.Log(ctx, logLevel, "provider poll update",
+			s.log(ctx).Log(ctx, logLevel, "provider poll update",
 				slog.String("job_id", jobID),
 				slog.Int("chunk_index", chunkIdx),
 				slog.String("provider_job_id", providerJobID),
@@ -714,7 +1897,7 @@ func (s *ProcessVideoService) pollForResultWithGenerator(
 			)
 
 			if pollResult.Error != "" {
-				s.logger.Info("provider reported error",
+				s.log(ctx).Info("provider reported error",
 					slog.String("job_id", jobID),
 					slog.Int("chunk_index", chunkIdx),
 					slog.String("provider_job_id", providerJobID),
@@ -724,7 +1907,7 @@ func (s *ProcessVideoService) pollForResultWithGenerator(
 
 			// If status changed since last poll (and not first poll), record it at info level.
 			if pollResult.Status != prevStatus && !firstPoll {
-				s.logger.Info("provider status changed",
+				s.log(ctx).Info("provider status changed",
 					slog.String("job_id", jobID),
 					slog.Int("chunk_index", chunkIdx),
 					slog.String("provider_job_id", providerJobID),
@@ -734,6 +1917,12 @@ func (s *ProcessVideoService) pollForResultWithGenerator(
 			}
 			firstPoll = false
 			prevStatus = pollResult.Status
+			lastResult = pollResult
+
+			if next := s.nextPollInterval(interval, pollResult.Status); next != interval {
+				interval = next
+				ticker.Reset(interval)
+			}
 
 			// Map generator status to job status and handle terminal states
 			switch pollResult.Status {
@@ -751,7 +1940,7 @@ func (s *ProcessVideoService) pollForResultWithGenerator(
 			case generator.StatusPending, generator.StatusInQueue, generator.StatusRunning:
 				// Continue polling
 			default:
-				s.logger.Warn("unknown provider status",
+				s.log(ctx).Warn("unknown provider status",
 					slog.String("job_id", jobID),
 					slog.String("status", string(pollResult.Status)),
 				)
@@ -760,22 +1949,77 @@ func (s *ProcessVideoService) pollForResultWithGenerator(
 	}
 }
 
+// nextPollInterval returns the interval until the next poll of a provider job
+// last seen in status, given the current interval. Queued jobs back off by
+// pollBackoff up to maxPollInterval; any other status returns to pollInterval.
+func (s *ProcessVideoService) nextPollInterval(current time.Duration, status generator.Status) time.Duration {
+	if s.pollBackoff <= 1 {
+		return s.pollInterval
+	}
+	switch status {
+	case generator.StatusPending, generator.StatusInQueue:
+		next := time.Duration(float64(current) * s.pollBackoff)
+		return min(next, max(s.maxPollInterval, s.pollInterval))
+	default:
+		return s.pollInterval
+	}
+}
+
 // updateChunkStatus updates the status of a chunk in the job.
+// Failures should go through failChunk so structured details are recorded;
+// any other status clears failure details left by a previous attempt.
 func (s *ProcessVideoService) updateChunkStatus(job *Job, idx int, status ChunkStatus, errMsg string) {
 	job.mu.Lock()
 	defer job.mu.Unlock()
 	if idx >= 0 && idx < len(job.Chunks) {
 		job.Chunks[idx].Status = status
 		job.Chunks[idx].Error = errMsg
-		switch status {
-		case ChunkStatusProcessing:
-			job.Chunks[idx].StartedAt = time.Now()
-		case ChunkStatusCompleted, ChunkStatusFailed:
-			job.Chunks[idx].CompletedAt = time.Now()
+		if status != ChunkStatusFailed {
+			job.Chunks[idx].FailureStage = ""
+			job.Chunks[idx].ProviderStatus = ""
+			job.Chunks[idx].ProviderError = ""
+		}
+		// StartedAt is stamped once the chunk is submitted to the provider
+		if status == ChunkStatusCompleted || status == ChunkStatusFailed {
+			job.Chunks[idx].CompletedAt = s.now()
 		}
 	}
 }
 
+// failChunk marks a chunk as failed, records structured failure details and
+// logs them, so they also show up in the job log.
+func (s *ProcessVideoService) failChunk(ctx context.Context, job *Job, idx int, errMsg string, failure ChunkFailure) {
+	job.mu.Lock()
+	if idx >= 0 && idx < len(job.Chunks) {
+		c := &job.Chunks[idx]
+		c.Status = ChunkStatusFailed
+		c.Error = errMsg
+		c.CompletedAt = s.now()
+		c.FailureStage = failure.Stage
+		c.ProviderStatus = failure.ProviderStatus
+		c.ProviderError = failure.ProviderError
+	}
+	job.mu.Unlock()
+
+	s.log(ctx).Warn("chunk failed",
+		slog.String("job_id", job.ID),
+		slog.Int("chunk_index", idx),
+		slog.String("failure_stage", string(failure.Stage)),
+		slog.String("provider_status", failure.ProviderStatus),
+		slog.String("provider_error", failure.ProviderError),
+		slog.String("error", errMsg),
+	)
+}
+
+// outputFailure describes a failure retrieving the output of a completed provider job.
+func outputFailure(result generator.PollResult) ChunkFailure {
+	return ChunkFailure{
+		Stage:          FailureStageOutput,
+		ProviderStatus: string(result.Status),
+		ProviderError:  result.Error,
+	}
+}
+
 // saveBase64ToTemp decodes base64 data and saves it to temporary storage.
 func (s *ProcessVideoService) saveBase64ToTemp(ctx context.Context, b64Data, fileName string) (string, error) {
 	data, err := base64.StdEncoding.DecodeString(b64Data)
@@ -791,6 +2035,29 @@ func (s *ProcessVideoService) saveBase64ToTemp(ctx context.Context, b64Data, fil
 	return path, nil
 }
 
+// saveInputToTemp saves an input to a temp file, downloading it when rawURL is
+// set and decoding b64Data otherwise.
+func (s *ProcessVideoService) saveInputToTemp(ctx context.Context, b64Data, rawURL, fileName string) (string, error) {
+	if rawURL == "" {
+		return s.saveBase64ToTemp(ctx, b64Data, fileName)
+	}
+	if s.fetcher == nil {
+		return "", ErrFetcherNotConfigured
+	}
+
+	data, err := s.fetcher.Fetch(ctx, rawURL)
+	if err != nil {
+		return "", fmt.Errorf("download input: %w", err)
+	}
+
+	path, err := s.storage.SaveTemp(ctx, fileName, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("save to temp: %w", err)
+	}
+
+	return path, nil
+}
+
 // fileToBase64 reads a file and returns its base64-encoded content.
 func (s *ProcessVideoService) fileToBase64(path string) (string, error) {
 	data, err := os.ReadFile(path) // #nosec G304 - path is constructed internally
@@ -802,21 +2069,34 @@ func (s *ProcessVideoService) fileToBase64(path string) (string, error) {
 
 // failJob marks the job as failed and returns the appropriate output.
 // The second return value is always nil, as we want to return a valid output with error info.
+// A job that was cancelled or timed out while processing keeps its status.
 func (s *ProcessVideoService) failJob(ctx context.Context, job *Job, errMsg string) (*ProcessVideoOutput, error) { //nolint:unparam
+	if status := job.GetStatus(); status == StatusCancelled || status == StatusTimedOut {
+		s.log(ctx).Info("job processing stopped",
+			slog.String("job_id", job.ID),
+			slog.String("status", string(status)),
+			slog.String("reason", errMsg),
+		)
+		return &ProcessVideoOutput{
+			JobID:  job.ID,
+			Status: status,
+		}, nil
+	}
+
 	if err := job.Fail(errMsg); err != nil {
-		s.logger.Error("failed to transition job to failed state",
+		s.log(ctx).Error("failed to transition job to failed state",
 			slog.String("job_id", job.ID),
 			slog.String("error", err.Error()),
 		)
 	}
 	if err := s.repo.Save(ctx, job); err != nil {
-		s.logger.Error("failed to save failed job",
+		s.log(ctx).Error("failed to save failed job",
 			slog.String("job_id", job.ID),
 			slog.String("error", err.Error()),
 		)
 	}
 
-	s.logger.Error("job failed",
+	s.log(ctx).Error("job failed",
 		slog.String("job_id", job.ID),
 		slog.String("error", errMsg),
 	)
@@ -828,6 +2108,247 @@ func (s *ProcessVideoService) failJob(ctx context.Context, job *Job, errMsg stri
 	}, nil
 }
 
+// trackActive registers a job being processed along with its cancel func.
+func (s *ProcessVideoService) trackActive(job *Job, cancel context.CancelFunc) {
+	s.activeMu.Lock()
+	defer s.activeMu.Unlock()
+	s.active[job.ID] = &activeJob{job: job, cancel: cancel}
+}
+
+// untrackActive removes a job from the active registry.
+func (s *ProcessVideoService) untrackActive(jobID string) {
+	s.activeMu.Lock()
+	defer s.activeMu.Unlock()
+	delete(s.active, jobID)
+}
+
+// lookupActive returns the active entry for a job, or nil if it is not being processed.
+func (s *ProcessVideoService) lookupActive(jobID string) *activeJob {
+	s.activeMu.Lock()
+	defer s.activeMu.Unlock()
+	return s.active[jobID]
+}
+
+// PollInterval returns the interval between status polls of a provider job,
+// which bounds how often the status of a running job can change.
+func (s *ProcessVideoService) PollInterval() time.Duration {
+	return s.pollInterval
+}
+
+// EstimateRemaining estimates how long a job's remaining chunks will take,
+// given how many chunks of the job run at once (one when they are chained by
+// ContinuityLastFrame). It reports false when no
+// estimate is available (see Job.EstimateRemaining).
+func (s *ProcessVideoService) EstimateRemaining(job *Job) (time.Duration, bool) {
+	parallelism := s.maxConcurrentChunks
+	if job.ContinuityMode == ContinuityLastFrame {
+		parallelism = 1
+	}
+	return job.EstimateRemaining(s.now(), parallelism)
+}
+
+// TimeoutActiveJobs marks every job still being processed as TIMED_OUT and
+// stops its processing. It is used at shutdown once the drain deadline has
+// passed. Returns the IDs of the jobs that were timed out.
+func (s *ProcessVideoService) TimeoutActiveJobs(ctx context.Context) []string {
+	s.activeMu.Lock()
+	active := make([]*activeJob, 0, len(s.active))
+	for _, a := range s.active {
+		active = append(active, a)
+	}
+	s.activeMu.Unlock()
+
+	var timedOut []string
+	for _, a := range active {
+		if err := a.job.Timeout(); err != nil {
+			continue // finished in the meantime
+		}
+		a.cancel()
+		if err := s.repo.Save(ctx, a.job); err != nil {
+			s.log(ctx).Error("failed to save timed out job",
+				slog.String("job_id", a.job.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+		s.log(ctx).Warn("job timed out at shutdown",
+			slog.String("job_id", a.job.ID),
+		)
+		timedOut = append(timedOut, a.job.ID)
+	}
+	return timedOut
+}
+
+// CancelJob marks a job as CANCELLED and stops any in-flight processing.
+// It returns as soon as the job state is persisted; cancellation of chunks
+// already submitted to the provider is issued asynchronously and recorded
+// per chunk (CancelConfirmed/CancelError) once the provider responds.
+// Returns ErrJobNotFound if the job does not exist and ErrJobNotCancellable
+// if it already reached a terminal state.
+func (s *ProcessVideoService) CancelJob(ctx context.Context, jobID string) (*Job, error) {
+	// Prefer the live job so the processing goroutine observes the new status
+	var job *Job
+	active := s.lookupActive(jobID)
+	if active != nil {
+		job = active.job
+	} else {
+		found, err := s.repo.FindByID(ctx, jobID)
+		if err != nil {
+			return nil, fmt.Errorf("find job: %w", err)
+		}
+		job = found
+	}
+
+	if err := job.Cancel(); err != nil {
+		return nil, fmt.Errorf("%w: status %s", ErrJobNotCancellable, job.GetStatus())
+	}
+
+	// Snapshot submitted chunks before stopping processing marks them failed
+	pending := job.MarkPendingChunkCancels()
+
+	if active != nil {
+		active.cancel()
+	}
+
+	if err := s.repo.Save(ctx, job); err != nil {
+		return nil, fmt.Errorf("save job: %w", err)
+	}
+
+	// A queued job may have been picked up by a worker after the lookup above,
+	// and the worker may have checked its status before the save; stop it too
+	if active == nil {
+		if picked := s.lookupActive(jobID); picked != nil {
+			_ = picked.job.Cancel()
+			picked.cancel()
+			if err := s.repo.Save(ctx, picked.job); err != nil {
+				return nil, fmt.Errorf("save job: %w", err)
+			}
+		}
+	}
+
+	ctx = withJobLog(ctx, job.Logs)
+	s.log(ctx).Info("job cancelled",
+		slog.String("job_id", job.ID),
+		slog.Bool("was_processing", active != nil),
+		slog.Int("provider_cancels", len(pending)),
+	)
+
+	if len(pending) > 0 {
+		go s.cancelProviderJobs(context.WithoutCancel(ctx), job, pending)
+	}
+
+	s.removePartialArtifacts(context.WithoutCancel(ctx), job)
+
+	return job.Clone(), nil
+}
+
+// removePartialArtifacts deletes the temporary files and chunk videos a
+// cancelled or stuck job produced so far, rather than leaving them until its
+// processing winds down, which may take a while or never happen. The inputs
+// are left to the processing cleanup.
+func (s *ProcessVideoService) removePartialArtifacts(ctx context.Context, job *Job) {
+	paths := job.PartialArtifacts()
+	if len(paths) == 0 {
+		return
+	}
+
+	if err := s.storage.CleanupTemp(ctx, paths); err != nil {
+		s.log(ctx).Warn("failed to cleanup partial job files",
+			slog.String("job_id", job.ID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	s.log(ctx).Info("partial job files removed",
+		slog.String("job_id", job.ID),
+		slog.Int("file_count", len(paths)),
+	)
+}
+
+// cancelProviderJobs issues best-effort provider cancels for the given chunks
+// and records the outcome on the job.
+func (s *ProcessVideoService) cancelProviderJobs(ctx context.Context, job *Job, chunks []Chunk) {
+	gen, err := s.getGenerator(job.Provider)
+	if err != nil {
+		s.log(ctx).Warn("cannot cancel provider jobs",
+			slog.String("job_id", job.ID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	for _, chunk := range chunks {
+		cancelCtx, cancel := context.WithTimeout(ctx, providerCancelTimeout)
+		cancelErr := gen.Cancel(cancelCtx, chunk.RunPodJobID)
+		cancel()
+		job.SetChunkCancelResult(chunk.Index, cancelErr)
+
+		if cancelErr != nil {
+			s.log(ctx).Warn("provider cancel failed",
+				slog.String("job_id", job.ID),
+				slog.Int("chunk_index", chunk.Index),
+				slog.String("provider_job_id", chunk.RunPodJobID),
+				slog.String("error", cancelErr.Error()),
+			)
+		} else {
+			s.log(ctx).Info("provider cancel confirmed",
+				slog.String("job_id", job.ID),
+				slog.Int("chunk_index", chunk.Index),
+				slog.String("provider_job_id", chunk.RunPodJobID),
+			)
+		}
+	}
+
+	if err := s.repo.Save(ctx, job); err != nil {
+		s.log(ctx).Error("failed to save cancel results",
+			slog.String("job_id", job.ID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// expiresAt returns when job may be purged: its own retention after
+// creation, or the job TTL when it has none. A job deleted after download
+// expires when it is downloaded and falls back to the job TTL until then.
+// The zero time means it does not expire.
+func (s *ProcessVideoService) expiresAt(job *Job) time.Time {
+	switch {
+	case job.DeleteAfterDownload && !job.VideoDownloadedAt.IsZero():
+		return job.VideoDownloadedAt
+	case job.Retention > 0:
+		return job.CreatedAt.Add(job.Retention)
+	case s.jobTTL > 0:
+		return job.CreatedAt.Add(s.jobTTL)
+	}
+	return time.Time{}
+}
+
+// MarkVideoDownloaded records that the output video of a job was downloaded
+// in full. Only the first download is recorded; for a job created with a
+// retention of zero it makes the job expire, so the janitor purges it on its
+// next sweep.
+func (s *ProcessVideoService) MarkVideoDownloaded(ctx context.Context, jobID string) error {
+	job, err := s.repo.FindByID(ctx, jobID)
+	if err != nil {
+		return fmt.Errorf("find job: %w", err)
+	}
+	if !job.VideoDownloadedAt.IsZero() {
+		return nil
+	}
+
+	job.VideoDownloadedAt = s.now()
+	job.ExpiresAt = s.expiresAt(job)
+	if err := s.repo.Save(ctx, job); err != nil {
+		return fmt.Errorf("save job: %w", err)
+	}
+
+	if job.DeleteAfterDownload {
+		s.log(ctx).Info("job video downloaded, job expires",
+			slog.String("job_id", jobID),
+		)
+	}
+	return nil
+}
+
 // DeleteJobVideo deletes the local video file for a job and clears output metadata.
 // This operation is idempotent - it returns success even if the file is already missing.
 // Returns ErrJobNotFound if the job does not exist.
@@ -838,31 +2359,25 @@ func (s *ProcessVideoService) DeleteJobVideo(ctx context.Context, jobID string)
 		return fmt.Errorf("find job: %w", err)
 	}
 
-	s.logger.Info("deleting job video",
+	s.log(ctx).Info("deleting job video",
 		slog.String("job_id", jobID),
 		slog.String("output_path", job.OutputVideoPath),
 	)
 
-	// If there's an output path, attempt to delete the file
-	if job.OutputVideoPath != "" {
-		if err := os.Remove(job.OutputVideoPath); err != nil {
-			// Treat file not found as success (idempotent)
-			if !os.IsNotExist(err) {
-				s.logger.Error("failed to delete video file",
-					slog.String("job_id", jobID),
-					slog.String("path", job.OutputVideoPath),
-					slog.String("error", err.Error()),
-				)
-				return fmt.Errorf("delete video file: %w", err)
-			}
-			s.logger.Info("video file already missing (idempotent delete)",
-				slog.String("job_id", jobID),
-				slog.String("path", job.OutputVideoPath),
-			)
-		} else {
-			s.logger.Info("video file deleted",
+	if err := s.removeRemoteObjects(ctx, job); err != nil {
+		return err
+	}
+	if err := s.removeOutputVideo(ctx, jobID, job.OutputVideoPath); err != nil {
+		return err
+	}
+
+	// The thumbnail belongs to the video, so remove it as well (best effort)
+	if job.ThumbnailPath != "" {
+		if err := os.Remove(job.ThumbnailPath); err != nil && !os.IsNotExist(err) {
+			s.log(ctx).Warn("failed to delete thumbnail file",
 				slog.String("job_id", jobID),
-				slog.String("path", job.OutputVideoPath),
+				slog.String("path", job.ThumbnailPath),
+				slog.String("error", err.Error()),
 			)
 		}
 	}
@@ -873,9 +2388,326 @@ func (s *ProcessVideoService) DeleteJobVideo(ctx context.Context, jobID string)
 		return fmt.Errorf("save job: %w", err)
 	}
 
-	s.logger.Info("job video deletion completed",
+	s.log(ctx).Info("job video deletion completed",
+		slog.String("job_id", jobID),
+	)
+
+	return nil
+}
+
+// PublishJobVideo uploads the local output video of a completed job that was
+// not pushed to remote storage, and its thumbnail when there is one, so the
+// job gets a remote copy whose URL is resolved with VideoURL. Publishing a
+// job that was already pushed returns it unchanged. Returns ErrJobNotFound if
+// the job does not exist, ErrVideoNotPublishable if it has not completed or
+// its video file is gone, and storage.ErrRemoteNotConfigured if there is no
+// remote storage.
+func (s *ProcessVideoService) PublishJobVideo(ctx context.Context, jobID string) (*Job, error) {
+	job, err := s.repo.FindByID(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("find job: %w", err)
+	}
+
+	if job.PushToS3 && job.S3Key != "" {
+		return job, nil
+	}
+	if job.Status != StatusCompleted || job.OutputVideoPath == "" {
+		return nil, fmt.Errorf("%w: status %s", ErrVideoNotPublishable, job.Status)
+	}
+
+	videoFile, err := os.Open(job.OutputVideoPath) // #nosec G304 - OutputVideoPath is constructed internally
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: video file was deleted", ErrVideoNotPublishable)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open output video: %w", err)
+	}
+	defer func() { _ = videoFile.Close() }()
+
+	key := videoKey(job.ID, job.OutputVideoPath)
+	videoURL, err := s.storage.Upload(ctx, key, videoFile)
+	if err != nil {
+		s.log(ctx).Error("failed to publish video",
+			slog.String("job_id", job.ID),
+			slog.String("error", err.Error()),
+		)
+		return nil, fmt.Errorf("upload video: %w", err)
+	}
+	job.SetPublished(key)
+
+	if job.ThumbnailPath != "" && job.ThumbnailKey == "" && s.publishThumbnail(ctx, job) {
+		job.SetThumbnail(job.ThumbnailPath, thumbnailKey(job.ID))
+	}
+
+	if err := s.repo.Save(ctx, job); err != nil {
+		return nil, fmt.Errorf("save job: %w", err)
+	}
+
+	s.log(ctx).Info("job video published",
+		slog.String("job_id", job.ID),
+		slog.String("video_url", videoURL),
+	)
+
+	return job, nil
+}
+
+// publishThumbnail uploads the preview image of a job being published and
+// reports whether it succeeded. Failures are logged, leaving the thumbnail
+// served locally.
+func (s *ProcessVideoService) publishThumbnail(ctx context.Context, job *Job) bool {
+	thumbFile, err := os.Open(job.ThumbnailPath) // #nosec G304 - ThumbnailPath is constructed internally
+	if err != nil {
+		s.log(ctx).Warn("failed to open thumbnail for publishing",
+			slog.String("job_id", job.ID),
+			slog.String("error", err.Error()),
+		)
+		return false
+	}
+	defer func() { _ = thumbFile.Close() }()
+
+	if _, err := s.storage.Upload(ctx, thumbnailKey(job.ID), thumbFile); err != nil {
+		s.log(ctx).Warn("failed to publish thumbnail",
+			slog.String("job_id", job.ID),
+			slog.String("error", err.Error()),
+		)
+		return false
+	}
+	return true
+}
+
+// VideoURL returns the URL of the output video a job pushed to remote storage,
+// or an empty URL when it was not pushed. The URL is resolved on every call
+// so that presigned URLs are always fresh.
+func (s *ProcessVideoService) VideoURL(ctx context.Context, job *Job) (string, error) {
+	if !job.PushToS3 || job.S3Key == "" {
+		return "", nil
+	}
+	url, err := s.storage.ObjectURL(ctx, job.S3Key)
+	if err != nil {
+		return "", fmt.Errorf("resolve video URL: %w", err)
+	}
+	return url, nil
+}
+
+// ThumbnailURL returns the URL of the preview image a job pushed to remote
+// storage, or an empty URL when it was not pushed. Like VideoURL, it is
+// resolved on every call.
+func (s *ProcessVideoService) ThumbnailURL(ctx context.Context, job *Job) (string, error) {
+	if job.ThumbnailKey == "" {
+		return "", nil
+	}
+	url, err := s.storage.ObjectURL(ctx, job.ThumbnailKey)
+	if err != nil {
+		return "", fmt.Errorf("resolve thumbnail URL: %w", err)
+	}
+	return url, nil
+}
+
+// DeleteJob removes a job record together with its output video, thumbnail,
+// retained inputs and any remaining chunk files.
+// Missing files are ignored, so deleting a job whose artifacts are already gone succeeds.
+// Returns ErrJobNotFound if the job does not exist and ErrJobNotDeletable if it
+// is still being processed.
+func (s *ProcessVideoService) DeleteJob(ctx context.Context, jobID string) error {
+	job, err := s.repo.FindByID(ctx, jobID)
+	if err != nil {
+		return fmt.Errorf("find job: %w", err)
+	}
+
+	// A running workflow would save the job again after it is deleted
+	if s.lookupActive(jobID) != nil {
+		return fmt.Errorf("%w: status %s", ErrJobNotDeletable, job.GetStatus())
+	}
+
+	s.log(ctx).Info("deleting job",
+		slog.String("job_id", jobID),
+		slog.String("status", string(job.GetStatus())),
+	)
+
+	if err := s.removeRemoteObjects(ctx, job); err != nil {
+		return err
+	}
+	if err := s.removeOutputVideo(ctx, jobID, job.OutputVideoPath); err != nil {
+		return err
+	}
+
+	var tempFiles []string
+	for _, path := range []string{job.ThumbnailPath, job.InputImagePath, job.InputAudioPath} {
+		if path != "" {
+			tempFiles = append(tempFiles, path)
+		}
+	}
+	for _, chunk := range job.Chunks {
+		if chunk.InputPath != "" {
+			tempFiles = append(tempFiles, chunk.InputPath)
+		}
+		if chunk.OutputPath != "" {
+			tempFiles = append(tempFiles, chunk.OutputPath)
+		}
+	}
+	if len(tempFiles) > 0 {
+		if err := s.storage.CleanupTemp(ctx, tempFiles); err != nil {
+			s.log(ctx).Warn("failed to cleanup job files",
+				slog.String("job_id", jobID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	// A concurrent delete may have removed the record already
+	if err := s.repo.Delete(ctx, jobID); err != nil && !errors.Is(err, ErrJobNotFound) {
+		return fmt.Errorf("delete job: %w", err)
+	}
+
+	s.log(ctx).Info("job deleted",
 		slog.String("job_id", jobID),
 	)
 
 	return nil
 }
+
+// DownloadJobVideo opens the output video of a job that was pushed to remote
+// storage. The caller is responsible for closing the returned ReadCloser.
+// Returns ErrVideoNotRemote if the video was not pushed to remote storage.
+func (s *ProcessVideoService) DownloadJobVideo(ctx context.Context, job *Job) (io.ReadCloser, error) {
+	if !job.PushToS3 || job.S3Key == "" {
+		return nil, ErrVideoNotRemote
+	}
+	rc, err := s.storage.Download(ctx, job.S3Key)
+	if err != nil {
+		return nil, fmt.Errorf("download video: %w", err)
+	}
+	return rc, nil
+}
+
+// videoKey returns the remote storage key of a job's output video, keeping
+// the extension of the local file at outputPath.
+func videoKey(jobID, outputPath string) string {
+	return "videos/" + jobID + media.OutputFormatFromPath(outputPath).Extension()
+}
+
+// thumbnailKey returns the remote storage key of a job's preview image.
+func thumbnailKey(jobID string) string {
+	return fmt.Sprintf("thumbnails/%s.jpg", jobID)
+}
+
+// inputImageKey returns the remote storage key of a job's uploaded source image.
+func inputImageKey(jobID string) string {
+	return fmt.Sprintf("inputs/%s/image.png", jobID)
+}
+
+// inputChunkKey returns the remote storage key of a job's uploaded audio chunk.
+func inputChunkKey(jobID string, idx int) string {
+	return fmt.Sprintf("inputs/%s/chunk_%d.wav", jobID, idx)
+}
+
+// uploadInput uploads a provider input to remote storage and returns its URL.
+// Failures are logged and return an empty URL so the caller can fall back to base64.
+func (s *ProcessVideoService) uploadInput(ctx context.Context, job *Job, key string, data io.Reader) string {
+	url, err := s.storage.Upload(ctx, key, data)
+	if err != nil {
+		s.log(ctx).Warn("failed to upload input, submitting as base64",
+			slog.String("job_id", job.ID),
+			slog.String("key", key),
+			slog.String("error", err.Error()),
+		)
+		return ""
+	}
+	return url
+}
+
+// uploadChunkAudio uploads a chunk's audio to remote storage and returns its
+// URL, or an empty URL if the upload fails.
+func (s *ProcessVideoService) uploadChunkAudio(ctx context.Context, job *Job, idx int, audioPath string) string {
+	f, err := os.Open(audioPath) // #nosec G304 - audioPath is constructed internally
+	if err != nil {
+		s.log(ctx).Warn("failed to open audio chunk for upload, submitting as base64",
+			slog.String("job_id", job.ID),
+			slog.Int("chunk_index", idx),
+			slog.String("error", err.Error()),
+		)
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+	return s.uploadInput(ctx, job, inputChunkKey(job.ID, idx), f)
+}
+
+// removeInputObjects deletes the inputs uploaded for a job's provider
+// submissions. Failures are logged; the objects are no longer needed.
+func (s *ProcessVideoService) removeInputObjects(ctx context.Context, job *Job) {
+	keys := []string{inputImageKey(job.ID)}
+	for i := range job.Clone().Chunks {
+		keys = append(keys, inputChunkKey(job.ID, i))
+	}
+	for _, key := range keys {
+		if err := s.storage.DeleteObject(ctx, key); err != nil {
+			s.log(ctx).Warn("failed to delete uploaded input",
+				slog.String("job_id", job.ID),
+				slog.String("key", key),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// removeRemoteObjects deletes the video and thumbnail a job pushed to remote
+// storage. Objects that are already gone are ignored.
+func (s *ProcessVideoService) removeRemoteObjects(ctx context.Context, job *Job) error {
+	if !job.PushToS3 {
+		return nil
+	}
+
+	var keys []string
+	if job.S3Key != "" {
+		keys = append(keys, job.S3Key)
+	}
+	if job.ThumbnailKey != "" {
+		keys = append(keys, job.ThumbnailKey)
+	}
+
+	for _, key := range keys {
+		if err := s.storage.DeleteObject(ctx, key); err != nil {
+			s.log(ctx).Error("failed to delete remote object",
+				slog.String("job_id", job.ID),
+				slog.String("key", key),
+				slog.String("error", err.Error()),
+			)
+			return fmt.Errorf("delete remote object %s: %w", key, err)
+		}
+		s.log(ctx).Info("remote object deleted",
+			slog.String("job_id", job.ID),
+			slog.String("key", key),
+		)
+	}
+	return nil
+}
+
+// removeOutputVideo deletes the output video file of a job.
+// A missing file is treated as success so that deletes are idempotent.
+func (s *ProcessVideoService) removeOutputVideo(ctx context.Context, jobID, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	if err := os.Remove(path); err != nil {
+		if !os.IsNotExist(err) {
+			s.log(ctx).Error("failed to delete video file",
+				slog.String("job_id", jobID),
+				slog.String("path", path),
+				slog.String("error", err.Error()),
+			)
+			return fmt.Errorf("delete video file: %w", err)
+		}
+		s.log(ctx).Info("video file already missing (idempotent delete)",
+			slog.String("job_id", jobID),
+			slog.String("path", path),
+		)
+		return nil
+	}
+
+	s.log(ctx).Info("video file deleted",
+		slog.String("job_id", jobID),
+		slog.String("path", path),
+	)
+	return nil
+}
@@ -8,13 +8,24 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/maauso/infinitetalk-api/internal/audio"
+	"github.com/maauso/infinitetalk-api/internal/beam"
+	"github.com/maauso/infinitetalk-api/internal/fetch"
 	"github.com/maauso/infinitetalk-api/internal/generator"
+	"github.com/maauso/infinitetalk-api/internal/media"
+	"github.com/maauso/infinitetalk-api/internal/requestid"
 	"github.com/maauso/infinitetalk-api/internal/runpod"
+	"github.com/maauso/infinitetalk-api/internal/storage"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -30,11 +41,103 @@ func (m *mockProcessor) ResizeImageWithPadding(ctx context.Context, src, dst str
 	return args.Error(0)
 }
 
+func (m *mockProcessor) ResizeImageCropToFill(ctx context.Context, src, dst string, w, h int) error {
+	args := m.Called(ctx, src, dst, w, h)
+	return args.Error(0)
+}
+
+func (m *mockProcessor) ResizeImageToPNG(ctx context.Context, src string, w, h int, mode media.ResizeMode) ([]byte, error) {
+	args := m.Called(ctx, src, w, h, mode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *mockProcessor) NormalizeImage(ctx context.Context, src, dst string) error {
+	args := m.Called(ctx, src, dst)
+	return args.Error(0)
+}
+
 func (m *mockProcessor) JoinVideos(ctx context.Context, videoPaths []string, output string) error {
 	args := m.Called(ctx, videoPaths, output)
 	return args.Error(0)
 }
 
+func (m *mockProcessor) ReplaceAudio(ctx context.Context, video, audio, output string) error {
+	args := m.Called(ctx, video, audio, output)
+	return args.Error(0)
+}
+
+func (m *mockProcessor) ResizeVideo(ctx context.Context, src, dst string, w, h int, mode media.ResizeMode) error {
+	args := m.Called(ctx, src, dst, w, h, mode)
+	return args.Error(0)
+}
+
+func (m *mockProcessor) NormalizeFPS(ctx context.Context, src, dst string, fps float64) error {
+	args := m.Called(ctx, src, dst, fps)
+	return args.Error(0)
+}
+
+func (m *mockProcessor) ExtractFirstFrame(ctx context.Context, videoPath string) ([]byte, error) {
+	args := m.Called(ctx, videoPath)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *mockProcessor) ExtractLastFrame(ctx context.Context, videoPath string) ([]byte, error) {
+	args := m.Called(ctx, videoPath)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *mockProcessor) GenerateThumbnail(ctx context.Context, videoPath, dst string, atSec float64) error {
+	args := m.Called(ctx, videoPath, dst, atSec)
+	return args.Error(0)
+}
+
+// The probe methods report valid media unless the test set expectations for
+// them, so that tests of the processing pipeline need not mock them. Videos
+// have the dimensions of validateInput, so chunk videos need no resizing.
+
+func (m *mockProcessor) ProbeVideo(ctx context.Context, path string) (media.VideoInfo, error) {
+	if !m.expects("ProbeVideo") {
+		return media.VideoInfo{Width: 384, Height: 576, DurationSec: 10, Codec: "h264"}, nil
+	}
+	args := m.Called(ctx, path)
+	return args.Get(0).(media.VideoInfo), args.Error(1)
+}
+
+func (m *mockProcessor) ProbeImage(ctx context.Context, path string) (media.ImageInfo, error) {
+	if !m.expects("ProbeImage") {
+		return media.ImageInfo{Width: 1024, Height: 1024, Codec: "png"}, nil
+	}
+	args := m.Called(ctx, path)
+	return args.Get(0).(media.ImageInfo), args.Error(1)
+}
+
+func (m *mockProcessor) ProbeAudio(ctx context.Context, path string) (media.AudioInfo, error) {
+	if !m.expects("ProbeAudio") {
+		return media.AudioInfo{DurationSec: 10, SampleRate: 16000, Channels: 1, Codec: "pcm_s16le"}, nil
+	}
+	args := m.Called(ctx, path)
+	return args.Get(0).(media.AudioInfo), args.Error(1)
+}
+
+// expects reports whether the test set an expectation for method.
+func (m *mockProcessor) expects(method string) bool {
+	for _, call := range m.ExpectedCalls {
+		if call.Method == method {
+			return true
+		}
+	}
+	return false
+}
+
 // mockSplitter implements audio.Splitter for testing
 type mockSplitter struct {
 	mock.Mock
@@ -63,6 +166,51 @@ func (m *mockRunpodClient) Poll(ctx context.Context, jobID string) (runpod.PollR
 	return args.Get(0).(runpod.PollResult), args.Error(1)
 }
 
+func (m *mockRunpodClient) Cancel(ctx context.Context, jobID string) error {
+	args := m.Called(ctx, jobID)
+	return args.Error(0)
+}
+
+func (m *mockRunpodClient) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// mockBeamClient implements beam.Client for testing
+type mockBeamClient struct {
+	mock.Mock
+}
+
+func (m *mockBeamClient) Submit(ctx context.Context, imageB64, audioB64 string, opts beam.SubmitOptions) (string, error) {
+	args := m.Called(ctx, imageB64, audioB64, opts)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockBeamClient) SubmitByURL(ctx context.Context, imageURL, audioURL string, opts beam.SubmitOptions) (string, error) {
+	args := m.Called(ctx, imageURL, audioURL, opts)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockBeamClient) Poll(ctx context.Context, taskID string) (beam.PollResult, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).(beam.PollResult), args.Error(1)
+}
+
+func (m *mockBeamClient) DownloadOutput(ctx context.Context, outputURL, destPath string) error {
+	args := m.Called(ctx, outputURL, destPath)
+	return args.Error(0)
+}
+
+func (m *mockBeamClient) Cancel(ctx context.Context, taskID string) error {
+	args := m.Called(ctx, taskID)
+	return args.Error(0)
+}
+
+func (m *mockBeamClient) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
 // mockStorage implements storage.Storage for testing
 type mockStorage struct {
 	mock.Mock
@@ -86,11 +234,42 @@ func (m *mockStorage) CleanupTemp(ctx context.Context, paths []string) error {
 	return args.Error(0)
 }
 
-func (m *mockStorage) UploadToS3(ctx context.Context, key string, data io.Reader) (string, error) {
+func (m *mockStorage) Upload(ctx context.Context, key string, data io.Reader) (string, error) {
 	args := m.Called(ctx, key, data)
 	return args.String(0), args.Error(1)
 }
 
+func (m *mockStorage) ObjectURL(ctx context.Context, key string) (string, error) {
+	args := m.Called(ctx, key)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockStorage) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *mockStorage) DeleteObject(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+// mockFetcher implements fetch.Fetcher for testing
+type mockFetcher struct {
+	mock.Mock
+}
+
+func (m *mockFetcher) Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	args := m.Called(ctx, rawURL)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
 // Helper function to create a test service with all mocks
 func newTestService(t *testing.T) (*ProcessVideoService, *mockProcessor, *mockSplitter, *mockRunpodClient, *mockStorage, Repository) {
 	repo := NewMemoryRepository()
@@ -141,6 +320,8 @@ func TestNewProcessVideoService_WithOptions(t *testing.T) {
 	svc := NewProcessVideoService(repo, processor, splitter, runpodClient, nil, storageClient, nil,
 		WithSplitOpts(audio.SplitOpts{ChunkTargetSec: 30}),
 		WithPollInterval(10*time.Second),
+		WithMaxConcurrentChunks(3),
+		WithSubmitByURL(true),
 	)
 
 	if svc.splitOpts.ChunkTargetSec != 30 {
@@ -149,6 +330,246 @@ func TestNewProcessVideoService_WithOptions(t *testing.T) {
 	if svc.pollInterval != 10*time.Second {
 		t.Errorf("expected pollInterval 10s, got %v", svc.pollInterval)
 	}
+	if svc.maxConcurrentChunks != 3 {
+		t.Errorf("expected maxConcurrentChunks 3, got %d", svc.maxConcurrentChunks)
+	}
+	if !svc.submitByURL {
+		t.Error("expected submitByURL to be enabled")
+	}
+}
+
+// concurrencyGenerator is a generator.Generator that records how many jobs are
+// in flight at once. Jobs complete after a short delay, except that
+// failSubmit makes Submit fail and blockPoll keeps jobs running until ctx ends.
+type concurrencyGenerator struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	submitted   int
+	failSubmit  bool
+	blockPoll   bool
+}
+
+func (g *concurrencyGenerator) Submit(_ context.Context, _, _ string, _ generator.SubmitOptions) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.submitted++
+	if g.failSubmit && g.submitted == 1 {
+		return "", errors.New("submit failed")
+	}
+	g.inFlight++
+	g.maxInFlight = max(g.maxInFlight, g.inFlight)
+	return fmt.Sprintf("provider-job-%d", g.submitted), nil
+}
+
+func (g *concurrencyGenerator) Poll(ctx context.Context, jobID string) (generator.PollResult, error) {
+	if g.blockPoll {
+		<-ctx.Done()
+		return generator.PollResult{}, ctx.Err()
+	}
+	time.Sleep(20 * time.Millisecond)
+	g.mu.Lock()
+	g.inFlight--
+	g.mu.Unlock()
+	return generator.PollResult{Status: generator.StatusCompleted, VideoURL: "https://example.com/" + jobID}, nil
+}
+
+func (g *concurrencyGenerator) DownloadOutput(context.Context, string, string) error { return nil }
+
+func (g *concurrencyGenerator) Cancel(context.Context, string) error { return nil }
+
+// clockedGenerator is a concurrencyGenerator whose Submit and Poll advance
+// a test clock, by 5s and a minute respectively.
+type clockedGenerator struct {
+	concurrencyGenerator
+	advance func(time.Duration)
+}
+
+func (g *clockedGenerator) Submit(ctx context.Context, imageB64, audioB64 string, opts generator.SubmitOptions) (string, error) {
+	g.advance(5 * time.Second)
+	return g.concurrencyGenerator.Submit(ctx, imageB64, audioB64, opts)
+}
+
+func (g *clockedGenerator) Poll(ctx context.Context, jobID string) (generator.PollResult, error) {
+	g.advance(time.Minute)
+	return g.concurrencyGenerator.Poll(ctx, jobID)
+}
+
+// newChunkAudio writes n audio chunk files and returns their paths.
+func newChunkAudio(t *testing.T, n int) []string {
+	t.Helper()
+	dir := t.TempDir()
+	paths := make([]string, n)
+	for i := range paths {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("chunk_%d.wav", i))
+		if err := os.WriteFile(paths[i], []byte("audio"), 0644); err != nil {
+			t.Fatalf("failed to write chunk: %v", err)
+		}
+	}
+	return paths
+}
+
+func TestProcessVideoService_ProcessChunks_Concurrent(t *testing.T) {
+	svc, _, _, _, _, repo := newTestService(t)
+	WithMaxConcurrentChunks(2)(svc)
+	ctx := context.Background()
+
+	job := New()
+	job.SetChunks(make([]Chunk, 4))
+	if err := repo.Save(ctx, job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+	audioChunks := newChunkAudio(t, 4)
+	gen := &concurrencyGenerator{}
+
+	videoPaths, err := svc.processChunks(ctx, job, gen, sourceImage{b64: "image"}, audioChunks, 384, 576, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gen.maxInFlight != 2 {
+		t.Errorf("expected 2 chunks in flight at most, got %d", gen.maxInFlight)
+	}
+	// Results keep the chunk order regardless of completion order
+	for i, path := range videoPaths {
+		if want := fmt.Sprintf("chunk_%s_%d.mp4", job.ID, i); filepath.Base(path) != want {
+			t.Errorf("video %d: expected %s, got %s", i, want, filepath.Base(path))
+		}
+	}
+	if job.Progress != 90 {
+		t.Errorf("expected progress 90, got %d", job.Progress)
+	}
+}
+
+func TestProcessVideoService_ProcessChunks_SequentialByDefault(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+
+	job := New()
+	job.SetChunks(make([]Chunk, 3))
+	gen := &concurrencyGenerator{}
+
+	if _, err := svc.processChunks(context.Background(), job, gen, sourceImage{b64: "image"}, newChunkAudio(t, 3), 384, 576, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gen.maxInFlight != 1 {
+		t.Errorf("expected one chunk in flight at a time, got %d", gen.maxInFlight)
+	}
+}
+
+// urlGenerator is a generator.Generator that also accepts inputs by URL and
+// records how each job was submitted.
+type urlGenerator struct {
+	concurrencyGenerator
+	byURL   [][2]string // image and audio URL of each SubmitByURL call
+	byB64   [][2]string // image and audio base64 of each Submit call
+	callsMu sync.Mutex
+}
+
+func (g *urlGenerator) Submit(ctx context.Context, imageB64, audioB64 string, opts generator.SubmitOptions) (string, error) {
+	g.callsMu.Lock()
+	g.byB64 = append(g.byB64, [2]string{imageB64, audioB64})
+	g.callsMu.Unlock()
+	return g.concurrencyGenerator.Submit(ctx, imageB64, audioB64, opts)
+}
+
+func (g *urlGenerator) SubmitByURL(ctx context.Context, imageURL, audioURL string, opts generator.SubmitOptions) (string, error) {
+	g.callsMu.Lock()
+	g.byURL = append(g.byURL, [2]string{imageURL, audioURL})
+	g.callsMu.Unlock()
+	return g.concurrencyGenerator.Submit(ctx, "", "", opts)
+}
+
+func TestProcessVideoService_ProcessChunks_SubmitByURL(t *testing.T) {
+	svc, _, _, _, storageClient, _ := newTestService(t)
+	WithSubmitByURL(true)(svc)
+
+	job := New()
+	job.SetChunks(make([]Chunk, 2))
+	gen := &urlGenerator{}
+
+	storageClient.On("Upload", mock.Anything, inputChunkKey(job.ID, 0), mock.Anything).
+		Return("https://bucket/chunk_0.wav", nil).Once()
+	// A failed upload falls back to base64 for that chunk
+	storageClient.On("Upload", mock.Anything, inputChunkKey(job.ID, 1), mock.Anything).
+		Return("", errors.New("upload failed")).Once()
+
+	image := sourceImage{b64: "aW1hZ2U=", url: "https://bucket/image.png"}
+	if _, err := svc.processChunks(context.Background(), job, gen, image, newChunkAudio(t, 2), 384, 576, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := [][2]string{{"https://bucket/image.png", "https://bucket/chunk_0.wav"}}; !reflect.DeepEqual(gen.byURL, want) {
+		t.Errorf("expected URL submissions %v, got %v", want, gen.byURL)
+	}
+	if want := [][2]string{{"aW1hZ2U=", "YXVkaW8="}}; !reflect.DeepEqual(gen.byB64, want) {
+		t.Errorf("expected base64 submissions %v, got %v", want, gen.byB64)
+	}
+	storageClient.AssertExpectations(t)
+}
+
+func TestProcessVideoService_ProcessChunks_Base64WithoutImageURL(t *testing.T) {
+	svc, _, _, _, storageClient, _ := newTestService(t)
+	WithSubmitByURL(true)(svc)
+
+	job := New()
+	job.SetChunks(make([]Chunk, 1))
+	gen := &urlGenerator{}
+
+	// Without an uploaded image nothing is uploaded and inputs go as base64
+	if _, err := svc.processChunks(context.Background(), job, gen, sourceImage{b64: "aW1hZ2U="}, newChunkAudio(t, 1), 384, 576, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(gen.byURL) != 0 || len(gen.byB64) != 1 {
+		t.Errorf("expected one base64 submission, got %d by URL and %d as base64", len(gen.byURL), len(gen.byB64))
+	}
+	storageClient.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_RemoveInputObjects(t *testing.T) {
+	svc, _, _, _, storageClient, _ := newTestService(t)
+
+	job := New()
+	job.SetChunks(make([]Chunk, 2))
+
+	storageClient.On("DeleteObject", mock.Anything, inputImageKey(job.ID)).Return(nil).Once()
+	storageClient.On("DeleteObject", mock.Anything, inputChunkKey(job.ID, 0)).Return(nil).Once()
+	// Failures are logged and do not stop the remaining deletes
+	storageClient.On("DeleteObject", mock.Anything, inputChunkKey(job.ID, 1)).Return(errors.New("denied")).Once()
+
+	svc.removeInputObjects(context.Background(), job)
+
+	storageClient.AssertExpectations(t)
+}
+
+func TestProcessVideoService_ProcessChunks_FailureCancelsOthers(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+	WithMaxConcurrentChunks(2)(svc)
+
+	job := New()
+	job.SetChunks(make([]Chunk, 4))
+	gen := &concurrencyGenerator{failSubmit: true, blockPoll: true}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := svc.processChunks(context.Background(), job, gen, sourceImage{b64: "image"}, newChunkAudio(t, 4), 384, 576, false)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "submit failed") {
+			t.Fatalf("expected the submit failure, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("a failed chunk did not cancel the running ones")
+	}
+
+	gen.mu.Lock()
+	defer gen.mu.Unlock()
+	if gen.submitted > 2 {
+		t.Errorf("expected no chunks to start after the failure, got %d submits", gen.submitted)
+	}
 }
 
 func TestProcessVideoService_CreateJob(t *testing.T) {
@@ -183,6 +604,9 @@ func TestProcessVideoService_CreateJob(t *testing.T) {
 	if !job.PushToS3 {
 		t.Error("expected PushToS3 to be true")
 	}
+	if job.Priority != PriorityNormal {
+		t.Errorf("expected priority %s, got %s", PriorityNormal, job.Priority)
+	}
 
 	// Verify job was saved
 	saved, err := repo.FindByID(ctx, job.ID)
@@ -194,37 +618,388 @@ func TestProcessVideoService_CreateJob(t *testing.T) {
 	}
 }
 
-func TestProcessVideoService_GetJob(t *testing.T) {
+func TestProcessVideoService_FailChunk_LogsFailureDetails(t *testing.T) {
 	svc, _, _, _, _, _ := newTestService(t)
-	ctx := context.Background()
+	buf := NewLogBuffer(10)
+	ctx := withJobLog(context.Background(), buf)
 
-	// Create job first
-	created, err := svc.CreateJob(ctx, ProcessVideoInput{Width: 512, Height: 512})
+	job := New()
+	job.SetChunks([]Chunk{{ID: "chunk-0", Index: 0, Status: ChunkStatusProcessing}})
+
+	svc.failChunk(ctx, job, 0, "failed to poll provider", ChunkFailure{
+		Stage:          FailureStagePoll,
+		ProviderStatus: string(generator.StatusFailed),
+		ProviderError:  "CUDA out of memory",
+	})
+
+	if job.Chunks[0].FailureStage != FailureStagePoll {
+		t.Errorf("expected failure stage %q, got %q", FailureStagePoll, job.Chunks[0].FailureStage)
+	}
+	entries, _ := buf.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 job log line, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.Level != "WARN" || e.Message != "chunk failed" {
+		t.Errorf("unexpected line %s %q", e.Level, e.Message)
+	}
+	want := map[string]string{
+		"failure_stage":   "poll",
+		"provider_status": string(generator.StatusFailed),
+		"provider_error":  "CUDA out of memory",
+	}
+	for key, value := range want {
+		if e.Attrs[key] != value {
+			t.Errorf("expected %s=%q, got %q", key, value, e.Attrs[key])
+		}
+	}
+}
+
+func TestProcessVideoService_LogsCarryRequestID(t *testing.T) {
+	svc, _, _, _, storageClient, _ := newTestService(t)
+	var logs bytes.Buffer
+	svc.logger = slog.New(slog.NewTextHandler(&logs, nil))
+
+	storageClient.On("SaveTemp", mock.Anything, mock.Anything, mock.Anything).
+		Return("", errors.New("disk full"))
+
+	ctx := requestid.NewContext(context.Background(), "req-abc")
+	input := ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+		Width:       384,
+		Height:      576,
+	}
+
+	job, err := svc.CreateJob(ctx, input)
 	if err != nil {
-		t.Fatalf("failed to create job: %v", err)
+		t.Fatalf("unexpected error: %v", err)
 	}
+	// Background processing runs on a detached context, as the HTTP handler does
+	_, _ = svc.ProcessExistingJob(context.WithoutCancel(ctx), job.ID, input)
 
-	// Get job
-	found, err := svc.GetJob(ctx, created.ID)
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("expected logs from job creation and processing, got %q", logs.String())
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "request_id=req-abc") {
+			t.Errorf("expected log line to carry the request ID: %s", line)
+		}
+	}
+}
+
+func TestProcessVideoService_CreateJob_Metadata(t *testing.T) {
+	svc, _, _, _, _, repo := newTestService(t)
+
+	metadata := map[string]string{"customer": "acme", "source_row": "42"}
+	created, err := svc.CreateJob(context.Background(), ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+		Width:       384,
+		Height:      576,
+		Metadata:    metadata,
+	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if found.ID != created.ID {
-		t.Errorf("expected ID %s, got %s", created.ID, found.ID)
+
+	// The job keeps its own copy of the metadata
+	metadata["customer"] = "changed"
+
+	saved, err := repo.FindByID(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"customer": "acme", "source_row": "42"}
+	if !maps.Equal(saved.Metadata, want) {
+		t.Errorf("expected metadata %v, got %v", want, saved.Metadata)
 	}
 }
 
-func TestProcessVideoService_GetJob_NotFound(t *testing.T) {
+func TestProcessVideoService_ListJobs(t *testing.T) {
 	svc, _, _, _, _, _ := newTestService(t)
 	ctx := context.Background()
 
-	_, err := svc.GetJob(ctx, "nonexistent")
-	if !errors.Is(err, ErrJobNotFound) {
-		t.Errorf("expected ErrJobNotFound, got %v", err)
+	base := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	var ids []string
+	for i, metadata := range []map[string]string{
+		{"customer": "acme", "row": "1"},
+		{"customer": "globex"},
+		{"customer": "acme", "row": "2"},
+		nil,
+	} {
+		svc.now = func() time.Time { return base.Add(time.Duration(i) * time.Minute) }
+		job, err := svc.CreateJob(ctx, ProcessVideoInput{
+			ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+			AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+			Width:       384,
+			Height:      576,
+			Metadata:    metadata,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, job.ID)
+	}
+
+	tests := []struct {
+		name string
+		tags map[string]string
+		want []string
+	}{
+		{"no filter lists all newest first", nil, []string{ids[3], ids[2], ids[1], ids[0]}},
+		{"single tag", map[string]string{"customer": "acme"}, []string{ids[2], ids[0]}},
+		{"every tag must match", map[string]string{"customer": "acme", "row": "1"}, []string{ids[0]}},
+		{"no match", map[string]string{"customer": "initech"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs, err := svc.ListJobs(ctx, tt.tags)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := make([]string, 0, len(jobs))
+			for _, j := range jobs {
+				got = append(got, j.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
 	}
 }
 
-func TestProcessVideoService_Process_Success(t *testing.T) {
+func TestProcessVideoService_EstimateRemaining_UsesClock(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+	svc.maxConcurrentChunks = 1
+
+	// A clock far from the wall clock makes any time.Now() chunk timestamp skew the estimate
+	now := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	job := New()
+	if err := job.Start(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	job.SetChunks([]Chunk{
+		{Index: 0, Status: ChunkStatusPending},
+		{Index: 1, Status: ChunkStatusPending},
+		{Index: 2, Status: ChunkStatusPending},
+	})
+
+	// Submitting takes 5s and the provider job a minute, on the service clock
+	gen := &clockedGenerator{advance: func(d time.Duration) { now = now.Add(d) }}
+	audioPaths := newChunkAudio(t, 1)
+	start := now
+	if _, err := svc.processChunkWithGenerator(context.Background(), job, gen, 0, sourceImage{b64: "image"}, audioPaths[0], 384, 576, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c := job.Chunks[0]; !c.StartedAt.Equal(start.Add(5*time.Second)) || !c.CompletedAt.Equal(start.Add(65*time.Second)) {
+		t.Errorf("expected chunk timestamps from the service clock after submit, got started %v completed %v", c.StartedAt, c.CompletedAt)
+	}
+
+	job.Chunks[1].Status = ChunkStatusProcessing
+	job.Chunks[1].StartedAt = now
+	now = now.Add(20 * time.Second)
+
+	// The processing chunk has 40s left and the pending one a full minute
+	got, ok := svc.EstimateRemaining(job)
+	if !ok || got != 100*time.Second {
+		t.Errorf("EstimateRemaining() = %v, %v, want %v, true", got, ok, 100*time.Second)
+	}
+}
+
+func TestProcessVideoService_CreateJob_ExpiresAt(t *testing.T) {
+	created := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	input := ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+		Width:       384,
+		Height:      576,
+	}
+
+	t.Run("with TTL", func(t *testing.T) {
+		svc, _, _, _, _, _ := newTestService(t)
+		WithJobTTL(24 * time.Hour)(svc)
+		svc.now = func() time.Time { return created }
+
+		job, err := svc.CreateJob(context.Background(), input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !job.CreatedAt.Equal(created) {
+			t.Errorf("expected CreatedAt %v, got %v", created, job.CreatedAt)
+		}
+		if want := created.Add(24 * time.Hour); !job.ExpiresAt.Equal(want) {
+			t.Errorf("expected ExpiresAt %v, got %v", want, job.ExpiresAt)
+		}
+	})
+
+	t.Run("retention disabled", func(t *testing.T) {
+		svc, _, _, _, _, _ := newTestService(t)
+		svc.now = func() time.Time { return created }
+
+		job, err := svc.CreateJob(context.Background(), input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !job.ExpiresAt.IsZero() {
+			t.Errorf("expected no expiry, got %v", job.ExpiresAt)
+		}
+	})
+}
+
+func TestProcessVideoService_CreateJob_Retention(t *testing.T) {
+	created := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	seconds := func(n int) *int { return &n }
+
+	tests := []struct {
+		name                string
+		ttl                 time.Duration
+		retentionSec        *int
+		wantExpiresAt       time.Time
+		wantDeleteAfterDown bool
+		wantErr             error
+	}{
+		{name: "job TTL by default", ttl: 24 * time.Hour, wantExpiresAt: created.Add(24 * time.Hour)},
+		{name: "shorter than the TTL", ttl: 24 * time.Hour, retentionSec: seconds(3600), wantExpiresAt: created.Add(time.Hour)},
+		{name: "longer than the TTL", ttl: time.Hour, retentionSec: seconds(86400), wantExpiresAt: created.Add(24 * time.Hour)},
+		{name: "without a TTL", retentionSec: seconds(600), wantExpiresAt: created.Add(10 * time.Minute)},
+		{name: "at the maximum", retentionSec: seconds(7 * 86400), wantExpiresAt: created.Add(7 * 24 * time.Hour)},
+		{name: "delete after download keeps the TTL until downloaded", ttl: time.Hour, retentionSec: seconds(0), wantExpiresAt: created.Add(time.Hour), wantDeleteAfterDown: true},
+		{name: "delete after download without a TTL", retentionSec: seconds(0), wantDeleteAfterDown: true},
+		{name: "above the maximum", retentionSec: seconds(7*86400 + 1), wantErr: ErrInvalidRetention},
+		{name: "negative", retentionSec: seconds(-1), wantErr: ErrInvalidRetention},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, _, _, _, _ := newTestService(t)
+			WithJobTTL(tt.ttl)(svc)
+			WithMaxRetention(7 * 24 * time.Hour)(svc)
+			svc.now = func() time.Time { return created }
+
+			job, err := svc.CreateJob(context.Background(), ProcessVideoInput{
+				Width:        384,
+				Height:       576,
+				RetentionSec: tt.retentionSec,
+			})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !job.ExpiresAt.Equal(tt.wantExpiresAt) {
+				t.Errorf("expected ExpiresAt %v, got %v", tt.wantExpiresAt, job.ExpiresAt)
+			}
+			if job.DeleteAfterDownload != tt.wantDeleteAfterDown {
+				t.Errorf("expected DeleteAfterDownload %v, got %v", tt.wantDeleteAfterDown, job.DeleteAfterDownload)
+			}
+		})
+	}
+}
+
+func TestProcessVideoService_MarkVideoDownloaded(t *testing.T) {
+	created := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	downloaded := created.Add(10 * time.Minute)
+	ctx := context.Background()
+
+	t.Run("delete after download expires on first download", func(t *testing.T) {
+		svc, _, _, _, _, repo := newTestService(t)
+		WithJobTTL(time.Hour)(svc)
+		svc.now = func() time.Time { return created }
+		zero := 0
+		job, err := svc.CreateJob(ctx, ProcessVideoInput{Width: 384, Height: 576, RetentionSec: &zero})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		svc.now = func() time.Time { return downloaded }
+		if err := svc.MarkVideoDownloaded(ctx, job.ID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// Later downloads keep the first download time
+		svc.now = func() time.Time { return downloaded.Add(time.Minute) }
+		if err := svc.MarkVideoDownloaded(ctx, job.ID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		saved, _ := repo.FindByID(ctx, job.ID)
+		if !saved.VideoDownloadedAt.Equal(downloaded) {
+			t.Errorf("expected VideoDownloadedAt %v, got %v", downloaded, saved.VideoDownloadedAt)
+		}
+		if !saved.ExpiresAt.Equal(downloaded) {
+			t.Errorf("expected ExpiresAt %v, got %v", downloaded, saved.ExpiresAt)
+		}
+	})
+
+	t.Run("other jobs keep their expiry", func(t *testing.T) {
+		svc, _, _, _, _, repo := newTestService(t)
+		WithJobTTL(time.Hour)(svc)
+		svc.now = func() time.Time { return created }
+		job, err := svc.CreateJob(ctx, ProcessVideoInput{Width: 384, Height: 576})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		svc.now = func() time.Time { return downloaded }
+		if err := svc.MarkVideoDownloaded(ctx, job.ID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		saved, _ := repo.FindByID(ctx, job.ID)
+		if !saved.VideoDownloadedAt.Equal(downloaded) {
+			t.Errorf("expected VideoDownloadedAt %v, got %v", downloaded, saved.VideoDownloadedAt)
+		}
+		if want := created.Add(time.Hour); !saved.ExpiresAt.Equal(want) {
+			t.Errorf("expected ExpiresAt %v, got %v", want, saved.ExpiresAt)
+		}
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		svc, _, _, _, _, _ := newTestService(t)
+		if err := svc.MarkVideoDownloaded(ctx, "missing"); !errors.Is(err, ErrJobNotFound) {
+			t.Errorf("expected ErrJobNotFound, got %v", err)
+		}
+	})
+}
+
+func TestProcessVideoService_GetJob(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+	ctx := context.Background()
+
+	// Create job first
+	created, err := svc.CreateJob(ctx, ProcessVideoInput{Width: 512, Height: 512})
+	if err != nil {
+		t.Fatalf("failed to create job: %v", err)
+	}
+
+	// Get job
+	found, err := svc.GetJob(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if found.ID != created.ID {
+		t.Errorf("expected ID %s, got %s", created.ID, found.ID)
+	}
+}
+
+func TestProcessVideoService_GetJob_NotFound(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.GetJob(ctx, "nonexistent")
+	if !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestProcessVideoService_Process_Success(t *testing.T) {
 	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
 	ctx := context.Background()
 
@@ -300,6 +1075,23 @@ func TestProcessVideoService_Process_Success(t *testing.T) {
 		t.Errorf("expected progress 100, got %d", job.Progress)
 	}
 
+	// Verify the job lifecycle was captured in the job logs
+	entries, _ := job.Logs.Entries()
+	logged := make(map[string]LogEntry, len(entries))
+	for _, e := range entries {
+		logged[e.Message] = e
+	}
+	for _, msg := range []string{"creating new job", "job started, processing video", "chunk submitted to provider", "videos joined", "job completed successfully"} {
+		e, ok := logged[msg]
+		if !ok {
+			t.Errorf("expected %q in job logs", msg)
+			continue
+		}
+		if e.Attrs["job_id"] != output.JobID {
+			t.Errorf("expected %q to carry job_id %s, got %q", msg, output.JobID, e.Attrs["job_id"])
+		}
+	}
+
 	// Verify mock expectations
 	processor.AssertExpectations(t)
 	splitter.AssertExpectations(t)
@@ -353,7 +1145,7 @@ func TestProcessVideoService_Process_ResizeImageFails(t *testing.T) {
 
 	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
 	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
-	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+	// No CleanupTemp expectation - the inputs are retained so the failed job can be retried
 
 	processor.On("ResizeImageWithPadding", mock.Anything, "/tmp/image.png", mock.Anything, 1024, 1024).
 		Return(errors.New("resize error")).Once()
@@ -418,7 +1210,7 @@ func TestProcessVideoService_Process_SplitAudioFails(t *testing.T) {
 }
 
 func TestProcessVideoService_Process_RunPodSubmitFails(t *testing.T) {
-	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
 	ctx := context.Background()
 
 	imageData := []byte("test-image-data")
@@ -460,6 +1252,28 @@ func TestProcessVideoService_Process_RunPodSubmitFails(t *testing.T) {
 		t.Errorf("expected status FAILED, got %s", output.Status)
 	}
 
+	// Structured failure details are recorded on the chunk
+	job, err := repo.FindByID(ctx, output.JobID)
+	if err != nil {
+		t.Fatalf("job should exist in repository: %v", err)
+	}
+	chunk := job.Chunks[0]
+	if chunk.Status != ChunkStatusFailed {
+		t.Errorf("expected chunk status FAILED, got %s", chunk.Status)
+	}
+	if chunk.FailureStage != FailureStageSubmit {
+		t.Errorf("expected failure stage %q, got %q", FailureStageSubmit, chunk.FailureStage)
+	}
+	if !strings.Contains(chunk.ProviderError, "runpod submit error") {
+		t.Errorf("expected provider error to contain submit error, got %q", chunk.ProviderError)
+	}
+	if chunk.RunPodJobID != "" {
+		t.Errorf("expected no provider job ID, got %q", chunk.RunPodJobID)
+	}
+	if chunk.Attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", chunk.Attempts)
+	}
+
 	processor.AssertExpectations(t)
 	splitter.AssertExpectations(t)
 	runpodClient.AssertExpectations(t)
@@ -470,7 +1284,7 @@ func TestProcessVideoService_Process_RunPodSubmitFails(t *testing.T) {
 }
 
 func TestProcessVideoService_Process_RunPodPollFails(t *testing.T) {
-	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
 	ctx := context.Background()
 
 	imageData := []byte("test-image-data")
@@ -514,6 +1328,31 @@ func TestProcessVideoService_Process_RunPodPollFails(t *testing.T) {
 		t.Errorf("expected status FAILED, got %s", output.Status)
 	}
 
+	// Structured failure details are recorded on the chunk
+	job, err := repo.FindByID(ctx, output.JobID)
+	if err != nil {
+		t.Fatalf("job should exist in repository: %v", err)
+	}
+	chunk := job.Chunks[0]
+	if chunk.Status != ChunkStatusFailed {
+		t.Errorf("expected chunk status FAILED, got %s", chunk.Status)
+	}
+	if chunk.FailureStage != FailureStagePoll {
+		t.Errorf("expected failure stage %q, got %q", FailureStagePoll, chunk.FailureStage)
+	}
+	if chunk.ProviderStatus != string(generator.StatusFailed) {
+		t.Errorf("expected provider status FAILED, got %q", chunk.ProviderStatus)
+	}
+	if chunk.ProviderError != "RunPod processing error" {
+		t.Errorf("expected provider error %q, got %q", "RunPod processing error", chunk.ProviderError)
+	}
+	if chunk.RunPodJobID != "runpod-job-123" {
+		t.Errorf("expected provider job ID runpod-job-123, got %q", chunk.RunPodJobID)
+	}
+	if chunk.Attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", chunk.Attempts)
+	}
+
 	processor.AssertExpectations(t)
 	splitter.AssertExpectations(t)
 	runpodClient.AssertExpectations(t)
@@ -585,7 +1424,7 @@ func TestProcessVideoService_Process_JoinVideosFails(t *testing.T) {
 }
 
 func TestProcessVideoService_Process_WithS3Upload(t *testing.T) {
-	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
 	ctx := context.Background()
 
 	imageData := []byte("test-image-data")
@@ -605,7 +1444,7 @@ func TestProcessVideoService_Process_WithS3Upload(t *testing.T) {
 	storageClient.On("SaveTemp", mock.Anything, mock.MatchedBy(func(s string) bool {
 		return len(s) > 5 && s[:6] == "chunk_"
 	}), mock.Anything).Return("/tmp/chunk_0.mp4", nil).Once()
-	storageClient.On("UploadToS3", mock.Anything, mock.MatchedBy(func(s string) bool {
+	storageClient.On("Upload", mock.Anything, mock.MatchedBy(func(s string) bool {
 		return len(s) > 7 && s[:7] == "videos/"
 	}), mock.Anything).Return("https://s3.example.com/videos/output.mp4", nil).Once()
 	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
@@ -645,6 +1484,13 @@ func TestProcessVideoService_Process_WithS3Upload(t *testing.T) {
 	if output.VideoURL != "https://s3.example.com/videos/output.mp4" {
 		t.Errorf("expected S3 URL, got %s", output.VideoURL)
 	}
+	storedJob, err := repo.FindByID(ctx, output.JobID)
+	if err != nil {
+		t.Fatalf("failed to find job: %v", err)
+	}
+	if want := "videos/" + output.JobID + ".mp4"; storedJob.S3Key != want {
+		t.Errorf("expected S3Key %s, got %s", want, storedJob.S3Key)
+	}
 
 	processor.AssertExpectations(t)
 	splitter.AssertExpectations(t)
@@ -655,83 +1501,168 @@ func TestProcessVideoService_Process_WithS3Upload(t *testing.T) {
 	os.Remove("/tmp/image.png")
 }
 
-func TestProcessVideoService_Process_MultipleChunks(t *testing.T) {
+func TestProcessVideoService_Process_KeepLocalOutput(t *testing.T) {
+	for _, keep := range []bool{false, true} {
+		t.Run(fmt.Sprintf("keep=%v", keep), func(t *testing.T) {
+			svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
+			WithKeepLocalOutput(keep)(svc)
+			ctx := context.Background()
+
+			chunkPath := filepath.Join(t.TempDir(), "chunk_0.wav")
+			_ = os.WriteFile(chunkPath, []byte("audio"), 0600)
+			t.Cleanup(func() { os.Remove("/tmp/image.png") })
+
+			storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+			storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+			storageClient.On("SaveTemp", mock.Anything, mock.Anything, mock.Anything).Return("/tmp/chunk_0.mp4", nil).Once()
+			storageClient.On("Upload", mock.Anything, mock.Anything, mock.Anything).
+				Return("https://s3.example.com/videos/output.mp4", nil).Once()
+			// Cleanup really removes the files so the test sees what survives
+			storageClient.On("CleanupTemp", mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					for _, p := range args.Get(1).([]string) {
+						_ = os.Remove(p)
+					}
+				}).
+				Return(nil)
+			processor.On("ResizeImageWithPadding", mock.Anything, "/tmp/image.png", mock.Anything, 1024, 1024).
+				Run(func(args mock.Arguments) {
+					_ = os.WriteFile(args.Get(2).(string), []byte("image"), 0600)
+				}).
+				Return(nil).Once()
+			processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					_ = os.WriteFile(args.Get(2).(string), []byte("video"), 0600)
+				}).
+				Return(nil).Once()
+			splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return([]string{chunkPath}, nil).Once()
+			runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return("runpod-job-1", nil).Once()
+			runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+				Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: "dmlkZW8="}, nil).Once()
+
+			input := validateInput()
+			input.PushToS3 = true
+			output, err := svc.Process(ctx, input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			storedJob, err := repo.FindByID(ctx, output.JobID)
+			if err != nil {
+				t.Fatalf("failed to find job: %v", err)
+			}
+			if want := "videos/" + output.JobID + ".mp4"; storedJob.S3Key != want {
+				t.Errorf("expected S3Key %q, got %q", want, storedJob.S3Key)
+			}
+			if storedJob.OutputVideoPath == "" {
+				t.Fatal("expected the output path to be recorded")
+			}
+			t.Cleanup(func() { os.Remove(storedJob.OutputVideoPath) })
+
+			_, statErr := os.Stat(storedJob.OutputVideoPath)
+			if keep && statErr != nil {
+				t.Errorf("expected the local output to be kept, got %v", statErr)
+			}
+			if !keep && !os.IsNotExist(statErr) {
+				t.Errorf("expected the local output to be removed, got %v", statErr)
+			}
+		})
+	}
+}
+
+func TestProcessVideoService_Process_WithThumbnail(t *testing.T) {
 	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
+	WithThumbnails(true)(svc)
+	WithThumbnailAt(1.5)(svc)
 	ctx := context.Background()
 
 	imageData := []byte("test-image-data")
 	audioData := []byte("test-audio-data")
 	videoData := []byte("test-video-data")
-	videoB64 := base64.StdEncoding.EncodeToString(videoData)
 	input := ProcessVideoInput{
 		ImageBase64: base64.StdEncoding.EncodeToString(imageData),
 		AudioBase64: base64.StdEncoding.EncodeToString(audioData),
 		Width:       384,
 		Height:      576,
+		PushToS3:    true,
 	}
 
 	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
 	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
 	storageClient.On("SaveTemp", mock.Anything, mock.MatchedBy(func(s string) bool {
-		return len(s) > 5 && s[:6] == "chunk_"
-	}), mock.Anything).Return("/tmp/chunk.mp4", nil).Times(3)
+		return strings.HasPrefix(s, "chunk_")
+	}), mock.Anything).Return("/tmp/chunk_0.mp4", nil).Once()
+	storageClient.On("Upload", mock.Anything, mock.MatchedBy(func(s string) bool {
+		return strings.HasPrefix(s, "videos/")
+	}), mock.Anything).Return("https://s3.example.com/videos/output.mp4", nil).Once()
+	storageClient.On("Upload", mock.Anything, mock.MatchedBy(func(s string) bool {
+		return strings.HasPrefix(s, "thumbnails/") && strings.HasSuffix(s, ".jpg")
+	}), mock.Anything).Return("https://s3.example.com/thumbnails/output.jpg", nil).Once()
 	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
 
 	processor.On("ResizeImageWithPadding", mock.Anything, "/tmp/image.png", mock.Anything, 1024, 1024).
 		Run(func(args mock.Arguments) {
-			dst := args.Get(2).(string)
-			_ = os.WriteFile(dst, imageData, 0644)
+			_ = os.WriteFile(args.Get(2).(string), imageData, 0644)
+		}).
+		Return(nil).Once()
+	processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			_ = os.WriteFile(args.Get(2).(string), videoData, 0644)
+		}).
+		Return(nil).Once()
+	processor.On("GenerateThumbnail", mock.Anything, mock.Anything, mock.MatchedBy(func(dst string) bool {
+		return strings.HasPrefix(filepath.Base(dst), "thumbnail_") && strings.HasSuffix(dst, ".jpg")
+	}), 1.5).
+		Run(func(args mock.Arguments) {
+			_ = os.WriteFile(args.Get(2).(string), []byte("jpeg"), 0644)
 		}).
 		Return(nil).Once()
-	processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
 
-	// Return 3 audio chunks
 	splitter.On("Split", mock.Anything, "/tmp/audio.wav", "/tmp", mock.Anything).
-		Return([]string{"/tmp/chunk_0.wav", "/tmp/chunk_1.wav", "/tmp/chunk_2.wav"}, nil).Once()
+		Return([]string{"/tmp/chunk_0.wav"}, nil).Once()
 
-	// Create chunk files
-	for i := 0; i < 3; i++ {
-		_ = os.WriteFile(fmt.Sprintf("/tmp/chunk_%d.wav", i), audioData, 0644)
-	}
-	defer func() {
-		for i := 0; i < 3; i++ {
-			os.Remove(fmt.Sprintf("/tmp/chunk_%d.wav", i))
-		}
-	}()
+	_ = os.WriteFile("/tmp/chunk_0.wav", audioData, 0644)
+	defer os.Remove("/tmp/chunk_0.wav")
 
-	// Mock RunPod for each chunk
 	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
-		Return("runpod-job", nil).Times(3)
-	runpodClient.On("Poll", mock.Anything, "runpod-job").
-		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: videoB64}, nil).Times(3)
+		Return("runpod-job-123", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-123").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: base64.StdEncoding.EncodeToString(videoData)}, nil).Once()
 
 	output, err := svc.Process(ctx, input)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-
 	if output.Status != StatusCompleted {
-		t.Errorf("expected status COMPLETED, got %s", output.Status)
+		t.Fatalf("expected status COMPLETED, got %s (error: %s)", output.Status, output.Error)
+	}
+	if output.ThumbnailURL != "https://s3.example.com/thumbnails/output.jpg" {
+		t.Errorf("expected thumbnail S3 URL, got %q", output.ThumbnailURL)
 	}
 
-	// Verify job has correct chunk count
-	job, _ := repo.FindByID(ctx, output.JobID)
-	if len(job.Chunks) != 3 {
-		t.Errorf("expected 3 chunks, got %d", len(job.Chunks))
+	job, err := repo.FindByID(ctx, output.JobID)
+	if err != nil {
+		t.Fatalf("job should exist in repository: %v", err)
+	}
+	if job.ThumbnailPath != output.ThumbnailPath || job.ThumbnailPath == "" {
+		t.Errorf("expected job thumbnail path %q, got %q", output.ThumbnailPath, job.ThumbnailPath)
+	}
+	if job.ThumbnailKey != "thumbnails/"+job.ID+".jpg" {
+		t.Errorf("expected job thumbnail key thumbnails/%s.jpg, got %q", job.ID, job.ThumbnailKey)
 	}
 
 	processor.AssertExpectations(t)
-	splitter.AssertExpectations(t)
-	runpodClient.AssertExpectations(t)
 	storageClient.AssertExpectations(t)
 
-	// Cleanup
 	os.Remove("/tmp/image.png")
 }
 
-func TestProcessVideoService_Process_ContextCancellation(t *testing.T) {
-	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
-	ctx, cancel := context.WithCancel(context.Background())
+func TestProcessVideoService_Process_ThumbnailFailureIsNotFatal(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
+	WithThumbnails(true)(svc)
+	ctx := context.Background()
 
 	imageData := []byte("test-image-data")
 	audioData := []byte("test-audio-data")
@@ -744,14 +1675,19 @@ func TestProcessVideoService_Process_ContextCancellation(t *testing.T) {
 
 	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
 	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, mock.MatchedBy(func(s string) bool {
+		return strings.HasPrefix(s, "chunk_")
+	}), mock.Anything).Return("/tmp/chunk_0.mp4", nil).Once()
 	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
 
 	processor.On("ResizeImageWithPadding", mock.Anything, "/tmp/image.png", mock.Anything, 1024, 1024).
 		Run(func(args mock.Arguments) {
-			dst := args.Get(2).(string)
-			_ = os.WriteFile(dst, imageData, 0644)
+			_ = os.WriteFile(args.Get(2).(string), imageData, 0644)
 		}).
 		Return(nil).Once()
+	processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	processor.On("GenerateThumbnail", mock.Anything, mock.Anything, mock.Anything, 0.0).
+		Return(errors.New("ffmpeg failed")).Once()
 
 	splitter.On("Split", mock.Anything, "/tmp/audio.wav", "/tmp", mock.Anything).
 		Return([]string{"/tmp/chunk_0.wav"}, nil).Once()
@@ -762,282 +1698,1351 @@ func TestProcessVideoService_Process_ContextCancellation(t *testing.T) {
 	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return("runpod-job-123", nil).Once()
 	runpodClient.On("Poll", mock.Anything, "runpod-job-123").
-		Run(func(args mock.Arguments) {
-			// Cancel context during poll
-			cancel()
-		}).
-		Return(runpod.PollResult{Status: runpod.StatusRunning}, nil).Maybe()
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: base64.StdEncoding.EncodeToString([]byte("video"))}, nil).Once()
 
 	output, err := svc.Process(ctx, input)
 	if err != nil {
-		t.Fatalf("Process should not return error, got: %v", err)
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusCompleted {
+		t.Errorf("expected status COMPLETED, got %s (error: %s)", output.Status, output.Error)
 	}
 
-	if output.Status != StatusFailed {
-		t.Errorf("expected status FAILED due to context cancellation, got %s", output.Status)
+	job, err := repo.FindByID(ctx, output.JobID)
+	if err != nil {
+		t.Fatalf("job should exist in repository: %v", err)
+	}
+	if job.ThumbnailPath != "" || job.ThumbnailKey != "" {
+		t.Errorf("expected no thumbnail, got %q / %q", job.ThumbnailPath, job.ThumbnailKey)
 	}
 
-	// Cleanup
+	processor.AssertExpectations(t)
 	os.Remove("/tmp/image.png")
 }
 
-func TestProcessVideoService_Process_InvalidBase64Image(t *testing.T) {
-	svc, _, _, _, _, _ := newTestService(t)
-	ctx := context.Background()
+func TestProcessVideoService_Process_DryRunNeverUploads(t *testing.T) {
+	tests := []struct {
+		dryRun   bool
+		pushToS3 bool
+	}{
+		{dryRun: true, pushToS3: true},
+		{dryRun: true, pushToS3: false},
+		{dryRun: false, pushToS3: true},
+		{dryRun: false, pushToS3: false},
+	}
 
-	input := ProcessVideoInput{
-		ImageBase64: "not-valid-base64!!!",
-		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
-		Width:       384,
-		Height:      576,
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("dry_run=%t push_to_s3=%t", tt.dryRun, tt.pushToS3), func(t *testing.T) {
+			svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+			WithThumbnails(true)(svc)
+			ctx := context.Background()
+
+			imageData := []byte("test-image-data")
+			audioData := []byte("test-audio-data")
+			videoData := []byte("test-video-data")
+			input := ProcessVideoInput{
+				ImageBase64: base64.StdEncoding.EncodeToString(imageData),
+				AudioBase64: base64.StdEncoding.EncodeToString(audioData),
+				Width:       384,
+				Height:      576,
+				DryRun:      tt.dryRun,
+				PushToS3:    tt.pushToS3,
+			}
+
+			storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+			storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+			storageClient.On("SaveTemp", mock.Anything, mock.MatchedBy(func(s string) bool {
+				return strings.HasPrefix(s, "chunk_")
+			}), mock.Anything).Return("/tmp/chunk_0.mp4", nil).Maybe()
+			storageClient.On("Upload", mock.Anything, mock.Anything, mock.Anything).
+				Return("https://s3.example.com/object", nil).Maybe()
+			storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+
+			processor.On("ResizeImageWithPadding", mock.Anything, "/tmp/image.png", mock.Anything, 1024, 1024).
+				Run(func(args mock.Arguments) {
+					_ = os.WriteFile(args.Get(2).(string), imageData, 0644)
+				}).
+				Return(nil).Once()
+			processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					_ = os.WriteFile(args.Get(2).(string), videoData, 0644)
+				}).
+				Return(nil).Maybe()
+			processor.On("GenerateThumbnail", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					_ = os.WriteFile(args.Get(2).(string), []byte("jpeg"), 0644)
+				}).
+				Return(nil).Maybe()
+
+			splitter.On("Split", mock.Anything, "/tmp/audio.wav", "/tmp", mock.Anything).
+				Return([]string{"/tmp/chunk_0.wav"}, nil).Once()
+
+			_ = os.WriteFile("/tmp/chunk_0.wav", audioData, 0644)
+			defer os.Remove("/tmp/chunk_0.wav")
+
+			runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return("runpod-job-123", nil).Maybe()
+			runpodClient.On("Poll", mock.Anything, "runpod-job-123").
+				Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: base64.StdEncoding.EncodeToString(videoData)}, nil).Maybe()
+
+			output, err := svc.Process(ctx, input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output.Status != StatusCompleted {
+				t.Fatalf("expected status COMPLETED, got %s (error: %s)", output.Status, output.Error)
+			}
+
+			switch {
+			case tt.dryRun:
+				storageClient.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything)
+				runpodClient.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				runpodClient.AssertNotCalled(t, "Poll", mock.Anything, mock.Anything)
+			case tt.pushToS3:
+				// Video and thumbnail
+				storageClient.AssertNumberOfCalls(t, "Upload", 2)
+			default:
+				storageClient.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything)
+			}
+
+			os.Remove("/tmp/image.png")
+		})
 	}
+}
 
-	// No CleanupTemp expectation - the base64 decoding fails before any temp file is created
+func TestDryRunGenerator_RefusesProviderCalls(t *testing.T) {
+	ctx := context.Background()
+	gen := dryRunGenerator{}
 
-	output, err := svc.Process(ctx, input)
-	if err != nil {
-		t.Fatalf("Process should not return error, got: %v", err)
+	if _, err := gen.Submit(ctx, "image", "audio", generator.SubmitOptions{}); !errors.Is(err, ErrDryRunSideEffect) {
+		t.Errorf("Submit: expected ErrDryRunSideEffect, got %v", err)
 	}
-
-	if output.Status != StatusFailed {
-		t.Errorf("expected status FAILED, got %s", output.Status)
+	if _, err := gen.Poll(ctx, "job-1"); !errors.Is(err, ErrDryRunSideEffect) {
+		t.Errorf("Poll: expected ErrDryRunSideEffect, got %v", err)
 	}
-	if output.Error == "" {
-		t.Error("expected error message for invalid base64")
+	if err := gen.DownloadOutput(ctx, "https://example.com/out.mp4", "/tmp/out.mp4"); !errors.Is(err, ErrDryRunSideEffect) {
+		t.Errorf("DownloadOutput: expected ErrDryRunSideEffect, got %v", err)
 	}
 }
 
-func TestProcessVideoService_pollForResultWithGenerator_PollingWithRetry(t *testing.T) {
-	svc, _, _, runpodClient, _, _ := newTestService(t)
-	ctx := context.Background()
-
-	videoB64 := base64.StdEncoding.EncodeToString([]byte("video-data"))
+// setupSingleChunkRetryMocks prepares mocks for a one-chunk job whose provider
+// results are configured by the caller.
+func setupSingleChunkRetryMocks(processor *mockProcessor, splitter *mockSplitter, storageClient *mockStorage, imageData []byte) {
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, mock.MatchedBy(func(s string) bool {
+		return strings.HasPrefix(s, "chunk_")
+	}), mock.Anything).Return("/tmp/chunk_0.mp4", nil).Maybe()
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
 
-	// Create a generator adapter
-	gen := generator.NewRunPodAdapter(runpodClient)
+	processor.On("ResizeImageWithPadding", mock.Anything, "/tmp/image.png", mock.Anything, 1024, 1024).
+		Run(func(args mock.Arguments) {
+			_ = os.WriteFile(args.Get(2).(string), imageData, 0644)
+		}).
+		Return(nil).Once()
+	processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
 
-	// First call returns IN_QUEUE, second returns RUNNING, third returns COMPLETED
-	runpodClient.On("Poll", mock.Anything, "job-123").
-		Return(runpod.PollResult{Status: runpod.StatusInQueue}, nil).Once()
-	runpodClient.On("Poll", mock.Anything, "job-123").
-		Return(runpod.PollResult{Status: runpod.StatusRunning}, nil).Once()
-	runpodClient.On("Poll", mock.Anything, "job-123").
-		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: videoB64}, nil).Once()
+	splitter.On("Split", mock.Anything, "/tmp/audio.wav", "/tmp", mock.Anything).
+		Return([]string{"/tmp/chunk_0.wav"}, nil).Once()
+}
 
-	result, err := svc.pollForResultWithGenerator(ctx, gen, "test-job", 0, "job-123")
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
+func TestProcessVideoService_Process_ChunkRetrySucceeds(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
+	WithMaxChunkRetries(2)(svc)
+	WithChunkRetryBackoff(time.Millisecond)(svc)
+	ctx := context.Background()
 
-	if result.VideoBase64 != videoB64 {
-		t.Error("expected video base64 in result")
+	imageData := []byte("test-image-data")
+	audioData := []byte("test-audio-data")
+	setupSingleChunkRetryMocks(processor, splitter, storageClient, imageData)
+
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job-1", nil).Once()
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job-2", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+		Return(runpod.PollResult{Status: runpod.StatusFailed, Error: "worker crashed"}, nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-2").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: base64.StdEncoding.EncodeToString([]byte("video"))}, nil).Once()
+
+	_ = os.WriteFile("/tmp/chunk_0.wav", audioData, 0644)
+	defer os.Remove("/tmp/chunk_0.wav")
+
+	output, err := svc.Process(ctx, ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString(imageData),
+		AudioBase64: base64.StdEncoding.EncodeToString(audioData),
+		Width:       384,
+		Height:      576,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	if result.Status != generator.StatusCompleted {
-		t.Errorf("expected status COMPLETED, got %s", result.Status)
+	if output.Status != StatusCompleted {
+		t.Fatalf("expected status COMPLETED, got %s (error: %s)", output.Status, output.Error)
+	}
+
+	job, err := repo.FindByID(ctx, output.JobID)
+	if err != nil {
+		t.Fatalf("job should exist in repository: %v", err)
+	}
+	chunk := job.Chunks[0]
+	if chunk.Status != ChunkStatusCompleted {
+		t.Errorf("expected chunk status COMPLETED, got %s", chunk.Status)
+	}
+	if chunk.Attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", chunk.Attempts)
+	}
+	if chunk.RunPodJobID != "runpod-job-2" {
+		t.Errorf("expected provider job ID of the retry, got %q", chunk.RunPodJobID)
+	}
+	if chunk.Error != "" || chunk.FailureStage != "" {
+		t.Errorf("expected failure details to be cleared, got %q / %q", chunk.Error, chunk.FailureStage)
 	}
 
 	runpodClient.AssertExpectations(t)
+	os.Remove("/tmp/image.png")
 }
 
-func TestFileToBase64(t *testing.T) {
-	svc, _, _, _, _, _ := newTestService(t)
+func TestProcessVideoService_Process_ChunkRetryExhausted(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
+	WithMaxChunkRetries(1)(svc)
+	WithChunkRetryBackoff(time.Millisecond)(svc)
+	ctx := context.Background()
 
-	// Create a temp file
-	content := []byte("test content")
-	tmpFile := "/tmp/test_file_to_base64.txt"
-	if err := os.WriteFile(tmpFile, content, 0644); err != nil {
-		t.Fatalf("failed to create temp file: %v", err)
-	}
-	defer os.Remove(tmpFile)
+	imageData := []byte("test-image-data")
+	audioData := []byte("test-audio-data")
+	setupSingleChunkRetryMocks(processor, splitter, storageClient, imageData)
 
-	result, err := svc.fileToBase64(tmpFile)
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job-1", nil).Twice()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+		Return(runpod.PollResult{Status: runpod.StatusFailed, Error: "worker crashed"}, nil).Twice()
+
+	_ = os.WriteFile("/tmp/chunk_0.wav", audioData, 0644)
+	defer os.Remove("/tmp/chunk_0.wav")
+
+	output, err := svc.Process(ctx, ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString(imageData),
+		AudioBase64: base64.StdEncoding.EncodeToString(audioData),
+		Width:       384,
+		Height:      576,
+	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if output.Status != StatusFailed {
+		t.Fatalf("expected status FAILED, got %s", output.Status)
+	}
 
-	decoded, err := base64.StdEncoding.DecodeString(result)
+	job, err := repo.FindByID(ctx, output.JobID)
 	if err != nil {
-		t.Fatalf("result is not valid base64: %v", err)
+		t.Fatalf("job should exist in repository: %v", err)
 	}
-
-	if !bytes.Equal(decoded, content) {
-		t.Errorf("decoded content doesn't match: expected %s, got %s", content, decoded)
+	if job.Chunks[0].Attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", job.Chunks[0].Attempts)
 	}
-}
-
-func TestFileToBase64_NonExistentFile(t *testing.T) {
-	svc, _, _, _, _, _ := newTestService(t)
 
-	_, err := svc.fileToBase64("/tmp/nonexistent_file_12345.txt")
-	if err == nil {
-		t.Error("expected error for non-existent file")
-	}
+	runpodClient.AssertNumberOfCalls(t, "Submit", 2)
+	os.Remove("/tmp/image.png")
 }
 
-func TestProcessVideoService_Process_DryRun(t *testing.T) {
-	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
+func TestProcessVideoService_Process_ChunkCancelledNotRetried(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+	WithMaxChunkRetries(3)(svc)
+	WithChunkRetryBackoff(time.Millisecond)(svc)
 	ctx := context.Background()
 
 	imageData := []byte("test-image-data")
 	audioData := []byte("test-audio-data")
-	imageB64 := base64.StdEncoding.EncodeToString(imageData)
-	audioB64 := base64.StdEncoding.EncodeToString(audioData)
+	setupSingleChunkRetryMocks(processor, splitter, storageClient, imageData)
 
-	input := ProcessVideoInput{
-		ImageBase64: imageB64,
-		AudioBase64: audioB64,
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job-1", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+		Return(runpod.PollResult{Status: runpod.StatusCancelled}, nil).Once()
+
+	_ = os.WriteFile("/tmp/chunk_0.wav", audioData, 0644)
+	defer os.Remove("/tmp/chunk_0.wav")
+
+	output, err := svc.Process(ctx, ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString(imageData),
+		AudioBase64: base64.StdEncoding.EncodeToString(audioData),
 		Width:       384,
 		Height:      576,
-		PushToS3:    false,
-		DryRun:      true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusFailed {
+		t.Fatalf("expected status FAILED, got %s", output.Status)
 	}
 
-	// Setup mock expectations - only preprocessing steps should be called
-	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
-	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
-	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+	runpodClient.AssertNumberOfCalls(t, "Submit", 1)
+	os.Remove("/tmp/image.png")
+}
 
-	processor.On("ResizeImageWithPadding", mock.Anything, "/tmp/image.png", mock.Anything, 1024, 1024).
-		Run(func(args mock.Arguments) {
-			dst := args.Get(2).(string)
-			_ = os.WriteFile(dst, imageData, 0644)
-		}).
-		Return(nil).Once()
+func TestProcessVideoService_Process_ChunkTimeout(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
+	WithChunkTimeout(50 * time.Millisecond)(svc)
+	ctx := context.Background()
 
-	splitter.On("Split", mock.Anything, "/tmp/audio.wav", "/tmp", mock.Anything).
-		Return([]string{"/tmp/chunk_0.wav", "/tmp/chunk_1.wav"}, nil).Once()
+	imageData := []byte("test-image-data")
+	audioData := []byte("test-audio-data")
+	setupSingleChunkRetryMocks(processor, splitter, storageClient, imageData)
 
-	output, err := svc.Process(ctx, input)
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job-stuck", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-stuck").
+		Return(runpod.PollResult{Status: runpod.StatusRunning}, nil)
+
+	_ = os.WriteFile("/tmp/chunk_0.wav", audioData, 0644)
+	defer os.Remove("/tmp/chunk_0.wav")
+
+	start := time.Now()
+	output, err := svc.Process(ctx, ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString(imageData),
+		AudioBase64: base64.StdEncoding.EncodeToString(audioData),
+		Width:       384,
+		Height:      576,
+	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-
-	if output.JobID == "" {
-		t.Error("expected JobID to be set")
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected polling to stop near the chunk timeout, took %s", elapsed)
 	}
-	if output.Status != StatusCompleted {
-		t.Errorf("expected status %s, got %s", StatusCompleted, output.Status)
+	if output.Status != StatusFailed {
+		t.Fatalf("expected status FAILED, got %s", output.Status)
 	}
-	if output.Error != "" {
-		t.Errorf("expected no error, got %s", output.Error)
+	if !strings.Contains(output.Error, ErrProviderJobTimedOut.Error()) {
+		t.Errorf("expected timeout error, got %q", output.Error)
 	}
 
-	// Verify job in repository
 	job, err := repo.FindByID(ctx, output.JobID)
 	if err != nil {
 		t.Fatalf("job should exist in repository: %v", err)
 	}
-	if job.Status != StatusCompleted {
-		t.Errorf("expected job status COMPLETED, got %s", job.Status)
+	chunk := job.Chunks[0]
+	if chunk.Status != ChunkStatusFailed {
+		t.Errorf("expected chunk status FAILED, got %s", chunk.Status)
 	}
-	if job.Progress != 100 {
-		t.Errorf("expected progress 100, got %d", job.Progress)
+	if chunk.FailureStage != FailureStagePoll {
+		t.Errorf("expected failure stage %q, got %q", FailureStagePoll, chunk.FailureStage)
 	}
-	if len(job.Chunks) != 2 {
-		t.Errorf("expected 2 chunks, got %d", len(job.Chunks))
+	if chunk.ProviderStatus != string(generator.StatusRunning) {
+		t.Errorf("expected last provider status RUNNING, got %q", chunk.ProviderStatus)
 	}
 
-	// Verify RunPod was NOT called (dry-run should skip it)
-	runpodClient.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
-	runpodClient.AssertNotCalled(t, "Poll", mock.Anything, mock.Anything)
-
-	// Verify preprocessing mocks were called
-	processor.AssertExpectations(t)
-	splitter.AssertExpectations(t)
-	storageClient.AssertExpectations(t)
-
-	// Cleanup temp file created by test
 	os.Remove("/tmp/image.png")
 }
 
-func TestProcessVideoService_DeleteJobVideo_Success(t *testing.T) {
-	svc, _, _, _, _, repo := newTestService(t)
+func TestProcessVideoService_Process_ChunkTimeoutCancelledBeforeRetry(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
+	WithChunkTimeout(50 * time.Millisecond)(svc)
+	WithMaxChunkRetries(1)(svc)
+	WithChunkRetryBackoff(time.Millisecond)(svc)
 	ctx := context.Background()
 
-	// Create a temp video file
-	videoPath := "/tmp/test_delete_video.mp4"
-	if err := os.WriteFile(videoPath, []byte("video data"), 0644); err != nil {
-		t.Fatalf("failed to create temp file: %v", err)
-	}
-	defer os.Remove(videoPath)
+	imageData := []byte("test-image-data")
+	audioData := []byte("test-audio-data")
+	setupSingleChunkRetryMocks(processor, splitter, storageClient, imageData)
 
-	// Create a job with the video path
-	job := New()
-	job.SetOutput(videoPath, "")
-	if err := repo.Save(ctx, job); err != nil {
-		t.Fatalf("failed to save job: %v", err)
-	}
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job-stuck", nil).Once()
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job-2", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-stuck").
+		Return(runpod.PollResult{Status: runpod.StatusRunning}, nil)
+	runpodClient.On("Poll", mock.Anything, "runpod-job-2").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: base64.StdEncoding.EncodeToString([]byte("video"))}, nil).Once()
+	runpodClient.On("Cancel", mock.Anything, "runpod-job-stuck").Return(nil).Once()
 
-	// Delete the video
-	err := svc.DeleteJobVideo(ctx, job.ID)
+	_ = os.WriteFile("/tmp/chunk_0.wav", audioData, 0644)
+	defer os.Remove("/tmp/chunk_0.wav")
+
+	output, err := svc.Process(ctx, ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString(imageData),
+		AudioBase64: base64.StdEncoding.EncodeToString(audioData),
+		Width:       384,
+		Height:      576,
+	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-
-	// Verify file was deleted
-	if _, err := os.Stat(videoPath); !os.IsNotExist(err) {
-		t.Error("expected video file to be deleted")
+	if output.Status != StatusCompleted {
+		t.Fatalf("expected status COMPLETED, got %s (error: %s)", output.Status, output.Error)
 	}
 
-	// Verify job output was cleared
-	updatedJob, err := repo.FindByID(ctx, job.ID)
+	job, err := repo.FindByID(ctx, output.JobID)
 	if err != nil {
-		t.Fatalf("failed to find job: %v", err)
+		t.Fatalf("job should exist in repository: %v", err)
 	}
-	if updatedJob.OutputVideoPath != "" {
-		t.Errorf("expected OutputVideoPath to be empty, got %s", updatedJob.OutputVideoPath)
+	if job.Chunks[0].RunPodJobID != "runpod-job-2" {
+		t.Errorf("expected provider job ID of the retry, got %q", job.Chunks[0].RunPodJobID)
 	}
-	if updatedJob.VideoURL != "" {
-		t.Errorf("expected VideoURL to be empty, got %s", updatedJob.VideoURL)
+
+	runpodClient.AssertCalled(t, "Cancel", mock.Anything, "runpod-job-stuck")
+	runpodClient.AssertNumberOfCalls(t, "Submit", 2)
+	os.Remove("/tmp/image.png")
+}
+
+func TestIsRetryableChunkError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{fmt.Errorf("failed to poll provider: %w", ErrProviderJobFailed), true},
+		{fmt.Errorf("failed to poll provider: %w", ErrProviderJobTimedOut), true},
+		{fmt.Errorf("failed to poll provider: %w", ErrProviderJobCancelled), false},
+		{errors.New("failed to submit to provider"), false},
+		{context.Canceled, false},
+	}
+
+	for _, tt := range tests {
+		if got := isRetryableChunkError(tt.err); got != tt.want {
+			t.Errorf("isRetryableChunkError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
 	}
 }
 
-func TestProcessVideoService_DeleteJobVideo_FileAlreadyMissing(t *testing.T) {
-	svc, _, _, _, _, repo := newTestService(t)
+func TestProcessVideoService_Process_JoinRetrySucceeds(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+	WithMaxJoinRetries(2)(svc)
+	WithJoinRetryBackoff(time.Millisecond)(svc)
 	ctx := context.Background()
 
-	// Create a job with a non-existent video path
-	job := New()
-	job.SetOutput("/tmp/nonexistent_video_12345.mp4", "")
-	if err := repo.Save(ctx, job); err != nil {
-		t.Fatalf("failed to save job: %v", err)
-	}
+	imageData := []byte("test-image-data")
+	audioData := []byte("test-audio-data")
 
-	// Delete should succeed even if file is missing (idempotent)
-	err := svc.DeleteJobVideo(ctx, job.ID)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
+	// Registered before the catch-all JoinVideos expectation so it matches first
+	processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).
+		Return(errors.New("no space left on device")).Once()
+	setupSingleChunkRetryMocks(processor, splitter, storageClient, imageData)
 
-	// Verify job output was cleared
-	updatedJob, err := repo.FindByID(ctx, job.ID)
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job-1", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: base64.StdEncoding.EncodeToString([]byte("video"))}, nil).Once()
+
+	_ = os.WriteFile("/tmp/chunk_0.wav", audioData, 0644)
+	defer os.Remove("/tmp/chunk_0.wav")
+
+	output, err := svc.Process(ctx, ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString(imageData),
+		AudioBase64: base64.StdEncoding.EncodeToString(audioData),
+		Width:       384,
+		Height:      576,
+	})
 	if err != nil {
-		t.Fatalf("failed to find job: %v", err)
+		t.Fatalf("unexpected error: %v", err)
 	}
-	if updatedJob.OutputVideoPath != "" {
-		t.Errorf("expected OutputVideoPath to be empty, got %s", updatedJob.OutputVideoPath)
+	if output.Status != StatusCompleted {
+		t.Fatalf("expected status COMPLETED, got %s (error: %s)", output.Status, output.Error)
 	}
+
+	processor.AssertNumberOfCalls(t, "JoinVideos", 2)
+	// The join retry must not resubmit chunks
+	runpodClient.AssertNumberOfCalls(t, "Submit", 1)
 }
 
-func TestProcessVideoService_DeleteJobVideo_NoOutputPath(t *testing.T) {
-	svc, _, _, _, _, repo := newTestService(t)
+func TestProcessVideoService_Process_JoinRetryExhausted(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+	WithMaxJoinRetries(1)(svc)
+	WithJoinRetryBackoff(time.Millisecond)(svc)
 	ctx := context.Background()
 
-	// Create a job without an output path
-	job := New()
-	if err := repo.Save(ctx, job); err != nil {
-		t.Fatalf("failed to save job: %v", err)
-	}
+	imageData := []byte("test-image-data")
+	audioData := []byte("test-audio-data")
 
-	// Delete should succeed
-	err := svc.DeleteJobVideo(ctx, job.ID)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).
+		Return(errors.New("no space left on device")).Twice()
+	setupSingleChunkRetryMocks(processor, splitter, storageClient, imageData)
+
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job-1", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: base64.StdEncoding.EncodeToString([]byte("video"))}, nil).Once()
+
+	_ = os.WriteFile("/tmp/chunk_0.wav", audioData, 0644)
+	defer os.Remove("/tmp/chunk_0.wav")
+
+	output, _ := svc.Process(ctx, ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString(imageData),
+		AudioBase64: base64.StdEncoding.EncodeToString(audioData),
+		Width:       384,
+		Height:      576,
+	})
+	if output.Status != StatusFailed {
+		t.Fatalf("expected status FAILED, got %s", output.Status)
 	}
+	if !strings.Contains(output.Error, "failed to join videos") {
+		t.Errorf("expected join error, got %q", output.Error)
+	}
+
+	processor.AssertNumberOfCalls(t, "JoinVideos", 2)
 }
 
-func TestProcessVideoService_DeleteJobVideo_JobNotFound(t *testing.T) {
-	svc, _, _, _, _, _ := newTestService(t)
+func TestProcessVideoService_Process_MultipleChunks(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+
+	imageData := []byte("test-image-data")
+	audioData := []byte("test-audio-data")
+	videoData := []byte("test-video-data")
+	videoB64 := base64.StdEncoding.EncodeToString(videoData)
+	input := ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString(imageData),
+		AudioBase64: base64.StdEncoding.EncodeToString(audioData),
+		Width:       384,
+		Height:      576,
+	}
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, mock.MatchedBy(func(s string) bool {
+		return len(s) > 5 && s[:6] == "chunk_"
+	}), mock.Anything).Return("/tmp/chunk.mp4", nil).Times(3)
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+
+	processor.On("ResizeImageWithPadding", mock.Anything, "/tmp/image.png", mock.Anything, 1024, 1024).
+		Run(func(args mock.Arguments) {
+			dst := args.Get(2).(string)
+			_ = os.WriteFile(dst, imageData, 0644)
+		}).
+		Return(nil).Once()
+	processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+
+	// Return 3 audio chunks
+	splitter.On("Split", mock.Anything, "/tmp/audio.wav", "/tmp", mock.Anything).
+		Return([]string{"/tmp/chunk_0.wav", "/tmp/chunk_1.wav", "/tmp/chunk_2.wav"}, nil).Once()
+
+	// Create chunk files
+	for i := 0; i < 3; i++ {
+		_ = os.WriteFile(fmt.Sprintf("/tmp/chunk_%d.wav", i), audioData, 0644)
+	}
+	defer func() {
+		for i := 0; i < 3; i++ {
+			os.Remove(fmt.Sprintf("/tmp/chunk_%d.wav", i))
+		}
+	}()
+
+	// Mock RunPod for each chunk
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job", nil).Times(3)
+	runpodClient.On("Poll", mock.Anything, "runpod-job").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: videoB64}, nil).Times(3)
+
+	output, err := svc.Process(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if output.Status != StatusCompleted {
+		t.Errorf("expected status COMPLETED, got %s", output.Status)
+	}
+
+	// Verify job has correct chunk count
+	job, _ := repo.FindByID(ctx, output.JobID)
+	if len(job.Chunks) != 3 {
+		t.Errorf("expected 3 chunks, got %d", len(job.Chunks))
+	}
+
+	processor.AssertExpectations(t)
+	splitter.AssertExpectations(t)
+	runpodClient.AssertExpectations(t)
+	storageClient.AssertExpectations(t)
+
+	// Cleanup
+	os.Remove("/tmp/image.png")
+}
+
+// mockChunkedPipeline sets up the mocks for a job whose audio is split into n
+// chunks, each completing on its first poll. Chunk i's video is saved as
+// /tmp/chunk_<i>.mp4; the resized image is "image" ("aW1hZ2U=" as base64).
+func mockChunkedPipeline(t *testing.T, n int, processor *mockProcessor, splitter *mockSplitter, runpodClient *mockRunpodClient, storageClient *mockStorage) {
+	t.Helper()
+	dir := t.TempDir()
+	chunkPaths := make([]string, n)
+	for i := range chunkPaths {
+		chunkPaths[i] = filepath.Join(dir, fmt.Sprintf("chunk_%d.wav", i))
+		_ = os.WriteFile(chunkPaths[i], []byte("audio"), 0600)
+	}
+	t.Cleanup(func() { os.Remove("/tmp/image.png") })
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	for i := range n {
+		suffix := fmt.Sprintf("_%d.mp4", i)
+		storageClient.On("SaveTemp", mock.Anything, mock.MatchedBy(func(name string) bool {
+			return strings.HasSuffix(name, suffix)
+		}), mock.Anything).Return(fmt.Sprintf("/tmp/chunk_%d.mp4", i), nil).Once()
+	}
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+	processor.On("ResizeImageWithPadding", mock.Anything, "/tmp/image.png", mock.Anything, 1024, 1024).
+		Run(func(args mock.Arguments) {
+			_ = os.WriteFile(args.Get(2).(string), []byte("image"), 0600)
+		}).
+		Return(nil).Once()
+	processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(chunkPaths, nil).Once()
+	runpodClient.On("Poll", mock.Anything, mock.Anything).
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: "dmlkZW8="}, nil)
+}
+
+func TestProcessVideoService_Process_ContinuityNone(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+	WithMaxConcurrentChunks(3)(svc)
+
+	mockChunkedPipeline(t, 3, processor, splitter, runpodClient, storageClient)
+	runpodClient.On("Submit", mock.Anything, "aW1hZ2U=", mock.Anything, mock.Anything).
+		Return("runpod-job", nil).Times(3)
+
+	input := validateInput()
+	input.ContinuityMode = ContinuityNone
+	output, err := svc.Process(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusCompleted {
+		t.Fatalf("expected status COMPLETED, got %s (error: %s)", output.Status, output.Error)
+	}
+
+	processor.AssertNotCalled(t, "ExtractLastFrame", mock.Anything, mock.Anything)
+	runpodClient.AssertExpectations(t)
+}
+
+func TestProcessVideoService_Process_ContinuityLastFrame(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
+	WithMaxConcurrentChunks(3)(svc)
+	ctx := context.Background()
+
+	mockChunkedPipeline(t, 3, processor, splitter, runpodClient, storageClient)
+	// Every chunk but the last hands its last frame to the next one
+	for i := range 2 {
+		processor.On("ExtractLastFrame", mock.Anything, fmt.Sprintf("/tmp/chunk_%d.mp4", i)).
+			Return([]byte(fmt.Sprintf("frame-%d", i)), nil).Once()
+	}
+	runpodClient.On("Submit", mock.Anything, "aW1hZ2U=", mock.Anything, mock.Anything).
+		Return("runpod-job-0", nil).Once()
+	runpodClient.On("Submit", mock.Anything, base64.StdEncoding.EncodeToString([]byte("frame-0")), mock.Anything, mock.Anything).
+		Return("runpod-job-1", nil).Once()
+	runpodClient.On("Submit", mock.Anything, base64.StdEncoding.EncodeToString([]byte("frame-1")), mock.Anything, mock.Anything).
+		Return("runpod-job-2", nil).Once()
+
+	input := validateInput()
+	input.ContinuityMode = ContinuityLastFrame
+	output, err := svc.Process(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusCompleted {
+		t.Fatalf("expected status COMPLETED, got %s (error: %s)", output.Status, output.Error)
+	}
+
+	processor.AssertNumberOfCalls(t, "ExtractLastFrame", 2)
+	processor.AssertNotCalled(t, "ExtractLastFrame", mock.Anything, "/tmp/chunk_2.mp4")
+	processor.AssertCalled(t, "JoinVideos", mock.Anything,
+		[]string{"/tmp/chunk_0.mp4", "/tmp/chunk_1.mp4", "/tmp/chunk_2.mp4"}, output.VideoPath)
+	runpodClient.AssertExpectations(t)
+
+	stored, _ := repo.FindByID(ctx, output.JobID)
+	if stored.ContinuityMode != ContinuityLastFrame {
+		t.Errorf("expected continuity mode %q, got %q", ContinuityLastFrame, stored.ContinuityMode)
+	}
+}
+
+func TestProcessVideoService_Process_ContinuityLastFrameExtractFailure(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+
+	mockChunkedPipeline(t, 2, processor, splitter, runpodClient, storageClient)
+	processor.On("ExtractLastFrame", mock.Anything, "/tmp/chunk_0.mp4").
+		Return(nil, errors.New("no frames")).Once()
+	runpodClient.On("Submit", mock.Anything, "aW1hZ2U=", mock.Anything, mock.Anything).
+		Return("runpod-job-0", nil).Once()
+
+	input := validateInput()
+	input.ContinuityMode = ContinuityLastFrame
+	output, err := svc.Process(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusFailed {
+		t.Fatalf("expected status FAILED, got %s", output.Status)
+	}
+	if !strings.Contains(output.Error, "extract last frame") {
+		t.Errorf("expected the frame extraction error, got %q", output.Error)
+	}
+	// The second chunk is never submitted
+	runpodClient.AssertNumberOfCalls(t, "Submit", 1)
+	processor.AssertNotCalled(t, "JoinVideos", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_CreateJob_ContinuityMode(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+	ctx := context.Background()
+
+	job, err := svc.CreateJob(ctx, validateInput())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.ContinuityMode != ContinuityNone {
+		t.Errorf("expected default continuity mode %q, got %q", ContinuityNone, job.ContinuityMode)
+	}
+
+	input := validateInput()
+	input.ContinuityMode = "first_frame"
+	if _, err := svc.CreateJob(ctx, input); !errors.Is(err, ErrInvalidContinuityMode) {
+		t.Errorf("expected ErrInvalidContinuityMode, got %v", err)
+	}
+
+	input = validateInput()
+	input.InputType = InputTypeVideo
+	input.ContinuityMode = ContinuityLastFrame
+	if _, err := svc.CreateJob(ctx, input); !errors.Is(err, ErrUnsupportedInputType) {
+		t.Errorf("expected ErrUnsupportedInputType for a video input, got %v", err)
+	}
+}
+
+// countingRunpodClient is a runpod.Client that records the highest number of
+// jobs running at once. Each job completes on its first poll.
+type countingRunpodClient struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	submitted   int
+	videoB64    string
+}
+
+func (c *countingRunpodClient) Submit(context.Context, string, string, runpod.SubmitOptions) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.submitted++
+	c.inFlight++
+	c.maxInFlight = max(c.maxInFlight, c.inFlight)
+	return fmt.Sprintf("runpod-job-%d", c.submitted), nil
+}
+
+func (c *countingRunpodClient) Poll(context.Context, string) (runpod.PollResult, error) {
+	time.Sleep(20 * time.Millisecond)
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+	return runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: c.videoB64}, nil
+}
+
+func (c *countingRunpodClient) Cancel(context.Context, string) error { return nil }
+
+func (c *countingRunpodClient) Ping(context.Context) error { return nil }
+
+func TestProcessVideoService_Process_GlobalConcurrencyLimit(t *testing.T) {
+	const jobs, chunksPerJob, limit = 4, 3, 2
+
+	processor := &mockProcessor{}
+	splitter := &mockSplitter{}
+	storageClient := &mockStorage{}
+	client := &countingRunpodClient{videoB64: base64.StdEncoding.EncodeToString([]byte("video"))}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	svc := NewProcessVideoService(NewMemoryRepository(), processor, splitter, client, nil, storageClient, logger,
+		WithPollInterval(5*time.Millisecond),
+		WithMaxConcurrentChunks(chunksPerJob),
+		WithMaxGlobalConcurrency(limit),
+	)
+
+	dir := t.TempDir()
+	chunkPaths := newChunkAudio(t, chunksPerJob)
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return(filepath.Join(dir, "image.png"), nil)
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return(filepath.Join(dir, "audio.wav"), nil)
+	storageClient.On("SaveTemp", mock.Anything, mock.Anything, mock.Anything).Return(filepath.Join(dir, "chunk.mp4"), nil)
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+	processor.On("ResizeImageWithPadding", mock.Anything, mock.Anything, mock.Anything, 1024, 1024).
+		Run(func(args mock.Arguments) {
+			_ = os.WriteFile(args.String(2), []byte("image"), 0644)
+		}).Return(nil)
+	processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(chunkPaths, nil)
+
+	input := ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("image")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("audio")),
+		Width:       384,
+		Height:      576,
+	}
+
+	var wg sync.WaitGroup
+	outputs := make([]*ProcessVideoOutput, jobs)
+	for i := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outputs[i], _ = svc.Process(context.Background(), input)
+		}()
+	}
+	wg.Wait()
+
+	for i, output := range outputs {
+		if output == nil || output.Status != StatusCompleted {
+			t.Fatalf("job %d did not complete: %+v", i, output)
+		}
+	}
+	if client.submitted != jobs*chunksPerJob {
+		t.Errorf("expected %d submissions, got %d", jobs*chunksPerJob, client.submitted)
+	}
+	if client.maxInFlight > limit {
+		t.Errorf("expected at most %d chunks in flight across jobs, got %d", limit, client.maxInFlight)
+	}
+}
+
+func TestProcessVideoService_Process_ContextCancellation(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	imageData := []byte("test-image-data")
+	audioData := []byte("test-audio-data")
+	input := ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString(imageData),
+		AudioBase64: base64.StdEncoding.EncodeToString(audioData),
+		Width:       384,
+		Height:      576,
+	}
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+
+	processor.On("ResizeImageWithPadding", mock.Anything, "/tmp/image.png", mock.Anything, 1024, 1024).
+		Run(func(args mock.Arguments) {
+			dst := args.Get(2).(string)
+			_ = os.WriteFile(dst, imageData, 0644)
+		}).
+		Return(nil).Once()
+
+	splitter.On("Split", mock.Anything, "/tmp/audio.wav", "/tmp", mock.Anything).
+		Return([]string{"/tmp/chunk_0.wav"}, nil).Once()
+
+	_ = os.WriteFile("/tmp/chunk_0.wav", audioData, 0644)
+	defer os.Remove("/tmp/chunk_0.wav")
+
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job-123", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-123").
+		Run(func(args mock.Arguments) {
+			// Cancel context during poll
+			cancel()
+		}).
+		Return(runpod.PollResult{Status: runpod.StatusRunning}, nil).Maybe()
+
+	output, err := svc.Process(ctx, input)
+	if err != nil {
+		t.Fatalf("Process should not return error, got: %v", err)
+	}
+
+	if output.Status != StatusFailed {
+		t.Errorf("expected status FAILED due to context cancellation, got %s", output.Status)
+	}
+
+	// Cleanup
+	os.Remove("/tmp/image.png")
+}
+
+func TestProcessVideoService_Process_InvalidBase64Image(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+	ctx := context.Background()
+
+	input := ProcessVideoInput{
+		ImageBase64: "not-valid-base64!!!",
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+		Width:       384,
+		Height:      576,
+	}
+
+	// No CleanupTemp expectation - the base64 decoding fails before any temp file is created
+
+	output, err := svc.Process(ctx, input)
+	if err != nil {
+		t.Fatalf("Process should not return error, got: %v", err)
+	}
+
+	if output.Status != StatusFailed {
+		t.Errorf("expected status FAILED, got %s", output.Status)
+	}
+	if output.Error == "" {
+		t.Error("expected error message for invalid base64")
+	}
+}
+
+func TestProcessVideoService_pollForResultWithGenerator_PollingWithRetry(t *testing.T) {
+	svc, _, _, runpodClient, _, _ := newTestService(t)
+	ctx := context.Background()
+
+	videoB64 := base64.StdEncoding.EncodeToString([]byte("video-data"))
+
+	// Create a generator adapter
+	gen := generator.NewRunPodAdapter(runpodClient)
+
+	// First call returns IN_QUEUE, second returns RUNNING, third returns COMPLETED
+	runpodClient.On("Poll", mock.Anything, "job-123").
+		Return(runpod.PollResult{Status: runpod.StatusInQueue}, nil).Once()
+	runpodClient.On("Poll", mock.Anything, "job-123").
+		Return(runpod.PollResult{Status: runpod.StatusRunning}, nil).Once()
+	runpodClient.On("Poll", mock.Anything, "job-123").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: videoB64}, nil).Once()
+
+	result, err := svc.pollForResultWithGenerator(ctx, gen, "test-job", 0, "job-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.VideoBase64 != videoB64 {
+		t.Error("expected video base64 in result")
+	}
+	if result.Status != generator.StatusCompleted {
+		t.Errorf("expected status COMPLETED, got %s", result.Status)
+	}
+
+	runpodClient.AssertExpectations(t)
+}
+
+func TestProcessVideoService_nextPollInterval(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+	WithPollInterval(time.Second)(svc)
+
+	// Fixed interval by default
+	if got := svc.nextPollInterval(time.Second, generator.StatusInQueue); got != time.Second {
+		t.Errorf("expected the fixed interval by default, got %s", got)
+	}
+
+	WithAdaptivePolling(2, 5*time.Second)(svc)
+	tests := []struct {
+		name    string
+		current time.Duration
+		status  generator.Status
+		want    time.Duration
+	}{
+		{"queued backs off", time.Second, generator.StatusInQueue, 2 * time.Second},
+		{"pending backs off", 2 * time.Second, generator.StatusPending, 4 * time.Second},
+		{"capped at max", 4 * time.Second, generator.StatusInQueue, 5 * time.Second},
+		{"running resets", 5 * time.Second, generator.StatusRunning, time.Second},
+	}
+	for _, tt := range tests {
+		if got := svc.nextPollInterval(tt.current, tt.status); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestProcessVideoService_pollForResultWithGenerator_AdaptiveInterval(t *testing.T) {
+	svc, _, _, runpodClient, _, _ := newTestService(t)
+	WithPollInterval(20 * time.Millisecond)(svc)
+	WithAdaptivePolling(2, 160*time.Millisecond)(svc)
+	gen := generator.NewRunPodAdapter(runpodClient)
+
+	var polls []time.Time
+	record := func(mock.Arguments) { polls = append(polls, time.Now()) }
+	runpodClient.On("Poll", mock.Anything, "job-123").Run(record).
+		Return(runpod.PollResult{Status: runpod.StatusInQueue}, nil).Times(3)
+	runpodClient.On("Poll", mock.Anything, "job-123").Run(record).
+		Return(runpod.PollResult{Status: runpod.StatusRunning}, nil).Times(2)
+	runpodClient.On("Poll", mock.Anything, "job-123").Run(record).
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: "dmlkZW8="}, nil).Once()
+
+	start := time.Now()
+	if _, err := svc.pollForResultWithGenerator(context.Background(), gen, "test-job", 0, "job-123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	runpodClient.AssertExpectations(t)
+
+	// Polls 2-4 follow queued results (40ms, 80ms, 160ms); polls 5-6 follow
+	// running results and return to the base interval (20ms)
+	gaps := make([]time.Duration, 0, len(polls))
+	prev := start
+	for _, p := range polls {
+		gaps = append(gaps, p.Sub(prev))
+		prev = p
+	}
+	if !(gaps[1] < gaps[2] && gaps[2] < gaps[3]) {
+		t.Errorf("expected the interval to lengthen while queued, got gaps %v", gaps)
+	}
+	if gaps[4] >= gaps[3] || gaps[5] >= gaps[3] {
+		t.Errorf("expected the interval to shorten once running, got gaps %v", gaps)
+	}
+}
+
+func TestFileToBase64(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+
+	// Create a temp file
+	content := []byte("test content")
+	tmpFile := "/tmp/test_file_to_base64.txt"
+	if err := os.WriteFile(tmpFile, content, 0644); err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile)
+
+	result, err := svc.fileToBase64(tmpFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(result)
+	if err != nil {
+		t.Fatalf("result is not valid base64: %v", err)
+	}
+
+	if !bytes.Equal(decoded, content) {
+		t.Errorf("decoded content doesn't match: expected %s, got %s", content, decoded)
+	}
+}
+
+func TestFileToBase64_NonExistentFile(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+
+	_, err := svc.fileToBase64("/tmp/nonexistent_file_12345.txt")
+	if err == nil {
+		t.Error("expected error for non-existent file")
+	}
+}
+
+func TestProcessVideoService_Process_DryRun(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+
+	imageData := []byte("test-image-data")
+	audioData := []byte("test-audio-data")
+	imageB64 := base64.StdEncoding.EncodeToString(imageData)
+	audioB64 := base64.StdEncoding.EncodeToString(audioData)
+
+	input := ProcessVideoInput{
+		ImageBase64: imageB64,
+		AudioBase64: audioB64,
+		Width:       384,
+		Height:      576,
+		PushToS3:    false,
+		DryRun:      true,
+	}
+
+	// Setup mock expectations - only preprocessing steps should be called
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+
+	processor.On("ResizeImageWithPadding", mock.Anything, "/tmp/image.png", mock.Anything, 1024, 1024).
+		Run(func(args mock.Arguments) {
+			dst := args.Get(2).(string)
+			_ = os.WriteFile(dst, imageData, 0644)
+		}).
+		Return(nil).Once()
+
+	splitter.On("Split", mock.Anything, "/tmp/audio.wav", "/tmp", mock.Anything).
+		Return([]string{"/tmp/chunk_0.wav", "/tmp/chunk_1.wav"}, nil).Once()
+
+	output, err := svc.Process(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if output.JobID == "" {
+		t.Error("expected JobID to be set")
+	}
+	if output.Status != StatusCompleted {
+		t.Errorf("expected status %s, got %s", StatusCompleted, output.Status)
+	}
+	if output.Error != "" {
+		t.Errorf("expected no error, got %s", output.Error)
+	}
+
+	// Verify job in repository
+	job, err := repo.FindByID(ctx, output.JobID)
+	if err != nil {
+		t.Fatalf("job should exist in repository: %v", err)
+	}
+	if job.Status != StatusCompleted {
+		t.Errorf("expected job status COMPLETED, got %s", job.Status)
+	}
+	if job.Progress != 100 {
+		t.Errorf("expected progress 100, got %d", job.Progress)
+	}
+	if len(job.Chunks) != 2 {
+		t.Errorf("expected 2 chunks, got %d", len(job.Chunks))
+	}
+
+	// Verify RunPod was NOT called (dry-run should skip it)
+	runpodClient.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	runpodClient.AssertNotCalled(t, "Poll", mock.Anything, mock.Anything)
+
+	// Verify preprocessing mocks were called
+	processor.AssertExpectations(t)
+	splitter.AssertExpectations(t)
+	storageClient.AssertExpectations(t)
+
+	// Cleanup temp file created by test
+	os.Remove("/tmp/image.png")
+}
+
+func TestProcessVideoService_Process_ResizeModeCrop(t *testing.T) {
+	svc, processor, splitter, _, storageClient, _ := newTestService(t)
+	ctx := context.Background()
+
+	imageData := []byte("test-image-data")
+	input := ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString(imageData),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio-data")),
+		Width:       384,
+		Height:      576,
+		DryRun:      true,
+		ResizeMode:  "crop",
+	}
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+
+	processor.On("ResizeImageCropToFill", mock.Anything, "/tmp/image.png", mock.Anything, 1024, 1024).
+		Run(func(args mock.Arguments) {
+			dst := args.Get(2).(string)
+			_ = os.WriteFile(dst, imageData, 0644)
+		}).
+		Return(nil).Once()
+
+	splitter.On("Split", mock.Anything, "/tmp/audio.wav", "/tmp", mock.Anything).
+		Return([]string{"/tmp/chunk_0.wav"}, nil).Once()
+
+	output, err := svc.Process(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusCompleted {
+		t.Errorf("expected status %s, got %s", StatusCompleted, output.Status)
+	}
+
+	processor.AssertExpectations(t)
+	processor.AssertNotCalled(t, "ResizeImageWithPadding", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_Process_InMemoryResize(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+	WithInMemoryResize(true)(svc)
+	ctx := context.Background()
+
+	resizedData := []byte("resized-png-data")
+	audioData := []byte("test-audio-data")
+	videoB64 := base64.StdEncoding.EncodeToString([]byte("test-video-data"))
+
+	input := ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image-data")),
+		AudioBase64: base64.StdEncoding.EncodeToString(audioData),
+		Width:       384,
+		Height:      576,
+		ResizeMode:  "crop",
+	}
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, mock.MatchedBy(func(s string) bool {
+		return strings.HasPrefix(s, "chunk_")
+	}), mock.Anything).Return("/tmp/chunk_0.mp4", nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+
+	processor.On("ResizeImageToPNG", mock.Anything, "/tmp/image.png", 1024, 1024, media.ResizeModeCrop).
+		Return(resizedData, nil).Once()
+	processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+
+	splitter.On("Split", mock.Anything, "/tmp/audio.wav", "/tmp", mock.Anything).
+		Return([]string{"/tmp/chunk_0.wav"}, nil).Once()
+
+	// The provider receives the piped bytes, base64-encoded as the file path would produce
+	runpodClient.On("Submit", mock.Anything, base64.StdEncoding.EncodeToString(resizedData), mock.Anything, mock.Anything).
+		Return("runpod-job-123", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-123").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: videoB64}, nil).Once()
+
+	_ = os.WriteFile("/tmp/chunk_0.wav", audioData, 0644)
+	defer os.Remove("/tmp/chunk_0.wav")
+
+	output, err := svc.Process(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusCompleted {
+		t.Errorf("expected status %s, got %s (error: %s)", StatusCompleted, output.Status, output.Error)
+	}
+
+	processor.AssertExpectations(t)
+	runpodClient.AssertExpectations(t)
+	processor.AssertNotCalled(t, "ResizeImageWithPadding", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	processor.AssertNotCalled(t, "ResizeImageCropToFill", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_Process_InMemoryResizeFails(t *testing.T) {
+	svc, processor, _, _, storageClient, _ := newTestService(t)
+	WithInMemoryResize(true)(svc)
+	ctx := context.Background()
+
+	input := ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image-data")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio-data")),
+		Width:       384,
+		Height:      576,
+	}
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+
+	processor.On("ResizeImageToPNG", mock.Anything, "/tmp/image.png", 1024, 1024, media.ResizeMode("")).
+		Return(nil, errors.New("ffmpeg failed")).Once()
+
+	output, err := svc.Process(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusFailed {
+		t.Errorf("expected status %s, got %s", StatusFailed, output.Status)
+	}
+	if !strings.Contains(output.Error, "failed to resize image") {
+		t.Errorf("expected resize error, got %q", output.Error)
+	}
+}
+
+func TestProcessVideoService_DeleteJobVideo_Success(t *testing.T) {
+	svc, _, _, _, _, repo := newTestService(t)
+	ctx := context.Background()
+
+	// Create a temp video file
+	videoPath := "/tmp/test_delete_video.mp4"
+	if err := os.WriteFile(videoPath, []byte("video data"), 0644); err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(videoPath)
+
+	// Create a job with the video path
+	job := New()
+	job.SetOutput(videoPath)
+	if err := repo.Save(ctx, job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+
+	// Delete the video
+	err := svc.DeleteJobVideo(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Verify file was deleted
+	if _, err := os.Stat(videoPath); !os.IsNotExist(err) {
+		t.Error("expected video file to be deleted")
+	}
+
+	// Verify job output was cleared
+	updatedJob, err := repo.FindByID(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("failed to find job: %v", err)
+	}
+	if updatedJob.OutputVideoPath != "" {
+		t.Errorf("expected OutputVideoPath to be empty, got %s", updatedJob.OutputVideoPath)
+	}
+	if updatedJob.S3Key != "" {
+		t.Errorf("expected S3Key to be empty, got %s", updatedJob.S3Key)
+	}
+}
+
+func TestProcessVideoService_DeleteJobVideo_RemovesRemoteObjects(t *testing.T) {
+	svc, _, _, _, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+
+	job := New()
+	job.PushToS3 = true
+	job.SetS3Key("legacy/" + job.ID + ".mp4")
+	job.SetThumbnail("", "thumbnails/"+job.ID+".jpg")
+	if err := repo.Save(ctx, job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+
+	// The stored key is used even though it differs from the current key format
+	storageClient.On("DeleteObject", mock.Anything, "legacy/"+job.ID+".mp4").Return(nil).Once()
+	storageClient.On("DeleteObject", mock.Anything, "thumbnails/"+job.ID+".jpg").Return(nil).Once()
+
+	if err := svc.DeleteJobVideo(ctx, job.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	storageClient.AssertExpectations(t)
+
+	updatedJob, _ := repo.FindByID(ctx, job.ID)
+	if updatedJob.S3Key != "" || updatedJob.ThumbnailKey != "" {
+		t.Errorf("expected the keys to be cleared, got %q / %q", updatedJob.S3Key, updatedJob.ThumbnailKey)
+	}
+}
+
+func TestProcessVideoService_DeleteJobVideo_RemoteDeleteFails(t *testing.T) {
+	svc, _, _, _, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+
+	job := New()
+	job.PushToS3 = true
+	job.SetS3Key("videos/" + job.ID + ".mp4")
+	if err := repo.Save(ctx, job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+
+	storageClient.On("DeleteObject", mock.Anything, "videos/"+job.ID+".mp4").
+		Return(errors.New("access denied")).Once()
+
+	if err := svc.DeleteJobVideo(ctx, job.ID); err == nil {
+		t.Fatal("expected error when the remote delete fails")
+	}
+
+	// The key is kept so the delete can be retried
+	updatedJob, _ := repo.FindByID(ctx, job.ID)
+	if updatedJob.S3Key == "" {
+		t.Error("expected S3Key to be kept after a failed delete")
+	}
+}
+
+func TestProcessVideoService_DeleteJobVideo_FileAlreadyMissing(t *testing.T) {
+	svc, _, _, _, _, repo := newTestService(t)
+	ctx := context.Background()
+
+	// Create a job with a non-existent video path
+	job := New()
+	job.SetOutput("/tmp/nonexistent_video_12345.mp4")
+	if err := repo.Save(ctx, job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+
+	// Delete should succeed even if file is missing (idempotent)
+	err := svc.DeleteJobVideo(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Verify job output was cleared
+	updatedJob, err := repo.FindByID(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("failed to find job: %v", err)
+	}
+	if updatedJob.OutputVideoPath != "" {
+		t.Errorf("expected OutputVideoPath to be empty, got %s", updatedJob.OutputVideoPath)
+	}
+}
+
+func TestProcessVideoService_DeleteJobVideo_NoOutputPath(t *testing.T) {
+	svc, _, _, _, _, repo := newTestService(t)
+	ctx := context.Background()
+
+	// Create a job without an output path
+	job := New()
+	if err := repo.Save(ctx, job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+
+	// Delete should succeed
+	err := svc.DeleteJobVideo(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestProcessVideoService_DeleteJobVideo_JobNotFound(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
 	ctx := context.Background()
 
 	// Delete a non-existent job
@@ -1046,3 +3051,1862 @@ func TestProcessVideoService_DeleteJobVideo_JobNotFound(t *testing.T) {
 		t.Errorf("expected ErrJobNotFound, got %v", err)
 	}
 }
+
+// completedJobWithVideo saves a completed job whose output video is a local file.
+func completedJobWithVideo(t *testing.T, repo Repository) *Job {
+	t.Helper()
+	videoPath := filepath.Join(t.TempDir(), "output.mp4")
+	if err := os.WriteFile(videoPath, []byte("video data"), 0600); err != nil {
+		t.Fatalf("failed to create video file: %v", err)
+	}
+
+	job := New()
+	_ = job.Start()
+	_ = job.Complete()
+	job.SetOutput(videoPath)
+	if err := repo.Save(context.Background(), job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+	return job
+}
+
+func TestProcessVideoService_PublishJobVideo(t *testing.T) {
+	svc, _, _, _, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+	job := completedJobWithVideo(t, repo)
+
+	videoURL := "https://s3.example.com/videos/" + job.ID + ".mp4"
+	storageClient.On("Upload", mock.Anything, "videos/"+job.ID+".mp4", mock.Anything).
+		Run(func(args mock.Arguments) {
+			data, _ := io.ReadAll(args.Get(2).(io.Reader))
+			if string(data) != "video data" {
+				t.Errorf("expected the local video to be uploaded, got %q", data)
+			}
+		}).
+		Return(videoURL, nil).Once()
+
+	storageClient.On("ObjectURL", mock.Anything, "videos/"+job.ID+".mp4").Return(videoURL, nil)
+
+	published, err := svc.PublishJobVideo(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if url, _ := svc.VideoURL(ctx, published); url != videoURL {
+		t.Errorf("expected video URL %s, got %s", videoURL, url)
+	}
+
+	stored, _ := repo.FindByID(ctx, job.ID)
+	if !stored.PushToS3 || stored.S3Key != "videos/"+job.ID+".mp4" {
+		t.Errorf("expected the job to record the upload, got push=%v key=%q", stored.PushToS3, stored.S3Key)
+	}
+	if stored.OutputVideoPath != job.OutputVideoPath {
+		t.Errorf("expected the local video to be kept, got %q", stored.OutputVideoPath)
+	}
+
+	// Publishing again returns the job without uploading twice
+	again, err := svc.PublishJobVideo(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.S3Key != stored.S3Key {
+		t.Errorf("expected key %s, got %s", stored.S3Key, again.S3Key)
+	}
+	storageClient.AssertExpectations(t)
+}
+
+func TestProcessVideoService_PublishJobVideo_UploadsThumbnail(t *testing.T) {
+	svc, _, _, _, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+	job := completedJobWithVideo(t, repo)
+
+	thumbnailPath := filepath.Join(t.TempDir(), "thumbnail.jpg")
+	_ = os.WriteFile(thumbnailPath, []byte("jpeg"), 0600)
+	job.SetThumbnail(thumbnailPath, "")
+	_ = repo.Save(ctx, job)
+
+	storageClient.On("Upload", mock.Anything, "videos/"+job.ID+".mp4", mock.Anything).Return("https://s3/video", nil).Once()
+	storageClient.On("Upload", mock.Anything, "thumbnails/"+job.ID+".jpg", mock.Anything).Return("https://s3/thumb", nil).Once()
+
+	published, err := svc.PublishJobVideo(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if published.ThumbnailKey != "thumbnails/"+job.ID+".jpg" {
+		t.Errorf("expected thumbnail key to be set, got %q", published.ThumbnailKey)
+	}
+	storageClient.AssertExpectations(t)
+}
+
+func TestProcessVideoService_PublishJobVideo_NotPublishable(t *testing.T) {
+	svc, _, _, _, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+
+	running := New()
+	_ = running.Start()
+	_ = repo.Save(ctx, running)
+
+	deleted := completedJobWithVideo(t, repo)
+	_ = os.Remove(deleted.OutputVideoPath)
+
+	for name, id := range map[string]string{"not completed": running.ID, "video file deleted": deleted.ID} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := svc.PublishJobVideo(ctx, id); !errors.Is(err, ErrVideoNotPublishable) {
+				t.Errorf("expected ErrVideoNotPublishable, got %v", err)
+			}
+		})
+	}
+	if _, err := svc.PublishJobVideo(ctx, "nonexistent"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+	storageClient.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_PublishJobVideo_RemoteNotConfigured(t *testing.T) {
+	svc, _, _, _, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+	job := completedJobWithVideo(t, repo)
+
+	storageClient.On("Upload", mock.Anything, mock.Anything, mock.Anything).Return("", storage.ErrRemoteNotConfigured).Once()
+
+	if _, err := svc.PublishJobVideo(ctx, job.ID); !errors.Is(err, storage.ErrRemoteNotConfigured) {
+		t.Errorf("expected ErrRemoteNotConfigured, got %v", err)
+	}
+	stored, _ := repo.FindByID(ctx, job.ID)
+	if stored.PushToS3 || stored.S3Key != "" {
+		t.Errorf("expected the job to be unchanged, got push=%v key=%q", stored.PushToS3, stored.S3Key)
+	}
+}
+
+func TestProcessVideoService_DownloadJobVideo(t *testing.T) {
+	svc, _, _, _, storageClient, _ := newTestService(t)
+	ctx := context.Background()
+
+	job := New()
+	job.PushToS3 = true
+	job.SetS3Key("videos/" + job.ID + ".mp4")
+
+	storageClient.On("Download", mock.Anything, "videos/"+job.ID+".mp4").
+		Return(io.NopCloser(strings.NewReader("video data")), nil).Once()
+
+	rc, err := svc.DownloadJobVideo(ctx, job)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = rc.Close() }()
+	data, _ := io.ReadAll(rc)
+	if string(data) != "video data" {
+		t.Errorf("expected video data, got %q", data)
+	}
+	storageClient.AssertExpectations(t)
+}
+
+func TestProcessVideoService_DownloadJobVideo_NotRemote(t *testing.T) {
+	svc, _, _, _, storageClient, _ := newTestService(t)
+
+	job := New()
+	job.SetOutput("/tmp/output.mp4")
+
+	_, err := svc.DownloadJobVideo(context.Background(), job)
+	if !errors.Is(err, ErrVideoNotRemote) {
+		t.Errorf("expected ErrVideoNotRemote, got %v", err)
+	}
+	storageClient.AssertNotCalled(t, "Download", mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_DeleteJob_Success(t *testing.T) {
+	svc, _, _, _, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+
+	videoPath := filepath.Join(t.TempDir(), "output.mp4")
+	if err := os.WriteFile(videoPath, []byte("video data"), 0644); err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+
+	job := New()
+	job.InputImagePath = "/tmp/image.png"
+	job.InputAudioPath = "/tmp/audio.wav"
+	job.SetChunks([]Chunk{{ID: "chunk-0", Index: 0, InputPath: "/tmp/chunk_0.wav", OutputPath: "/tmp/chunk_0.mp4"}})
+	job.SetOutput(videoPath)
+	job.SetThumbnail("/tmp/thumb.jpg", "")
+	if err := repo.Save(ctx, job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+
+	storageClient.On("CleanupTemp", mock.Anything, []string{
+		"/tmp/thumb.jpg", "/tmp/image.png", "/tmp/audio.wav", "/tmp/chunk_0.wav", "/tmp/chunk_0.mp4",
+	}).Return(nil).Once()
+
+	if err := svc.DeleteJob(ctx, job.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(videoPath); !os.IsNotExist(err) {
+		t.Error("expected video file to be deleted")
+	}
+	if _, err := repo.FindByID(ctx, job.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected job to be removed from repository, got %v", err)
+	}
+
+	storageClient.AssertExpectations(t)
+}
+
+func TestProcessVideoService_DeleteJob_FilesAlreadyMissing(t *testing.T) {
+	svc, _, _, _, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+
+	job := New()
+	job.InputImagePath = "/tmp/nonexistent_image_12345.png"
+	job.SetOutput("/tmp/nonexistent_video_12345.mp4")
+	if err := repo.Save(ctx, job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil).Once()
+
+	if err := svc.DeleteJob(ctx, job.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.FindByID(ctx, job.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected job to be removed from repository, got %v", err)
+	}
+}
+
+func TestProcessVideoService_DeleteJob_RemovesRemoteVideo(t *testing.T) {
+	svc, _, _, _, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+
+	job := New()
+	job.PushToS3 = true
+	job.SetS3Key("videos/" + job.ID + ".mp4")
+	if err := repo.Save(ctx, job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+
+	storageClient.On("DeleteObject", mock.Anything, "videos/"+job.ID+".mp4").Return(nil).Once()
+
+	if err := svc.DeleteJob(ctx, job.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	storageClient.AssertExpectations(t)
+	if _, err := repo.FindByID(ctx, job.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected job to be removed from repository, got %v", err)
+	}
+}
+
+func TestProcessVideoService_DeleteJob_CleanupFailsStillDeletes(t *testing.T) {
+	svc, _, _, _, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+
+	job := New()
+	job.InputImagePath = "/tmp/image.png"
+	if err := repo.Save(ctx, job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(errors.New("permission denied")).Once()
+
+	if err := svc.DeleteJob(ctx, job.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.FindByID(ctx, job.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected job to be removed from repository, got %v", err)
+	}
+}
+
+func TestProcessVideoService_DeleteJob_ActiveJob(t *testing.T) {
+	svc, _, _, _, _, repo := newTestService(t)
+	ctx := context.Background()
+
+	job := New()
+	_ = job.Start()
+	if err := repo.Save(ctx, job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+	svc.trackActive(job, func() {})
+	defer svc.untrackActive(job.ID)
+
+	err := svc.DeleteJob(ctx, job.ID)
+	if !errors.Is(err, ErrJobNotDeletable) {
+		t.Errorf("expected ErrJobNotDeletable, got %v", err)
+	}
+	if _, err := repo.FindByID(ctx, job.ID); err != nil {
+		t.Errorf("expected job to be kept, got %v", err)
+	}
+}
+
+func TestProcessVideoService_DeleteJob_JobNotFound(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+
+	err := svc.DeleteJob(context.Background(), "nonexistent")
+	if !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestProcessVideoService_CancelJob_RunningJob(t *testing.T) {
+	svc, _, _, runpodClient, _, repo := newTestService(t)
+	ctx := context.Background()
+
+	job := New()
+	job.Provider = ProviderRunPod
+	if err := job.Start(); err != nil {
+		t.Fatalf("failed to start job: %v", err)
+	}
+	job.SetChunks([]Chunk{
+		{ID: "chunk-0", Index: 0, Status: ChunkStatusCompleted, RunPodJobID: "runpod-job-0"},
+		{ID: "chunk-1", Index: 1, Status: ChunkStatusProcessing, RunPodJobID: "runpod-job-1"},
+	})
+	if err := repo.Save(ctx, job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+
+	// Provider cancel is slow; CancelJob must not wait for it
+	cancelled := make(chan struct{})
+	runpodClient.On("Cancel", mock.Anything, "runpod-job-1").
+		Run(func(args mock.Arguments) {
+			time.Sleep(50 * time.Millisecond)
+			close(cancelled)
+		}).
+		Return(nil).Once()
+
+	start := time.Now()
+	result, err := svc.CancelJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 40*time.Millisecond {
+		t.Errorf("CancelJob should return before the provider cancel completes, took %s", elapsed)
+	}
+	if result.Status != StatusCancelled {
+		t.Errorf("expected status CANCELLED, got %s", result.Status)
+	}
+	if !result.Chunks[1].CancelRequested {
+		t.Error("expected in-flight chunk to be flagged for provider cancel")
+	}
+	if result.Chunks[0].CancelRequested {
+		t.Error("completed chunk should not be flagged for provider cancel")
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("provider cancel was not issued")
+	}
+
+	// Wait for the cancel result to be persisted
+	deadline := time.Now().Add(time.Second)
+	for {
+		saved, err := repo.FindByID(ctx, job.ID)
+		if err != nil {
+			t.Fatalf("failed to find job: %v", err)
+		}
+		if saved.Chunks[1].CancelConfirmed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected provider cancel to be confirmed on the chunk")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	runpodClient.AssertExpectations(t)
+}
+
+func TestProcessVideoService_CancelJob_ProviderCancelFails(t *testing.T) {
+	svc, _, _, runpodClient, _, repo := newTestService(t)
+	ctx := context.Background()
+
+	job := New()
+	job.Provider = ProviderRunPod
+	job.SetChunks([]Chunk{
+		{ID: "chunk-0", Index: 0, Status: ChunkStatusProcessing, RunPodJobID: "runpod-job-0"},
+	})
+	if err := repo.Save(ctx, job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+
+	runpodClient.On("Cancel", mock.Anything, "runpod-job-0").Return(errors.New("endpoint unavailable")).Once()
+
+	if _, err := svc.CancelJob(ctx, job.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		saved, err := repo.FindByID(ctx, job.ID)
+		if err != nil {
+			t.Fatalf("failed to find job: %v", err)
+		}
+		if saved.Chunks[0].CancelError != "" {
+			if saved.Chunks[0].CancelConfirmed {
+				t.Error("failed provider cancel should not be confirmed")
+			}
+			if saved.Status != StatusCancelled {
+				t.Errorf("expected status CANCELLED, got %s", saved.Status)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected provider cancel error to be recorded on the chunk")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestProcessVideoService_CancelJob_RemovesPartialArtifacts(t *testing.T) {
+	svc, _, _, runpodClient, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	artifact := func(name string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(name), 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		return path
+	}
+	resized := artifact("resized.png")
+	audioChunks := []string{artifact("chunk_0.wav"), artifact("chunk_1.wav")}
+	chunkVideo := artifact("chunk_0.mp4")
+	inputImage := artifact("image.png")
+
+	job := New()
+	job.Provider = ProviderRunPod
+	job.InputImagePath = inputImage
+	if err := job.Start(); err != nil {
+		t.Fatalf("failed to start job: %v", err)
+	}
+	job.AddTempFiles(resized)
+	job.AddTempFiles(audioChunks...)
+	job.SetChunks([]Chunk{
+		{ID: "chunk-0", Index: 0, Status: ChunkStatusCompleted, InputPath: audioChunks[0], OutputPath: chunkVideo},
+		{ID: "chunk-1", Index: 1, Status: ChunkStatusProcessing, InputPath: audioChunks[1], RunPodJobID: "runpod-job-1"},
+	})
+	if err := repo.Save(ctx, job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+
+	runpodClient.On("Cancel", mock.Anything, "runpod-job-1").Return(nil).Maybe()
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			for _, path := range args.Get(1).([]string) {
+				_ = os.Remove(path)
+			}
+		}).
+		Return(nil).Once()
+
+	if _, err := svc.CancelJob(ctx, job.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, path := range []string{resized, audioChunks[0], audioChunks[1], chunkVideo} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed on cancel, got %v", filepath.Base(path), err)
+		}
+	}
+	if _, err := os.Stat(inputImage); err != nil {
+		t.Errorf("expected the input to be left to the processing cleanup, got %v", err)
+	}
+	storageClient.AssertExpectations(t)
+}
+
+func TestProcessVideoService_CancelJob_TerminalJob(t *testing.T) {
+	svc, _, _, _, _, repo := newTestService(t)
+	ctx := context.Background()
+
+	job := New()
+	_ = job.Start()
+	_ = job.Complete()
+	if err := repo.Save(ctx, job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+
+	_, err := svc.CancelJob(ctx, job.ID)
+	if !errors.Is(err, ErrJobNotCancellable) {
+		t.Errorf("expected ErrJobNotCancellable, got %v", err)
+	}
+}
+
+func TestProcessVideoService_CancelJob_QueuedJobIsNeverProcessed(t *testing.T) {
+	svc, processor, _, runpodClient, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+
+	job, err := svc.CreateJob(ctx, validateInput())
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	cancelled, err := svc.CancelJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("cancel queued job: %v", err)
+	}
+	if cancelled.Status != StatusCancelled {
+		t.Fatalf("expected CANCELLED, got %s", cancelled.Status)
+	}
+
+	// The worker picks the job up later and must leave it alone
+	output, err := svc.ProcessExistingJob(ctx, job.ID, validateInput())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusCancelled {
+		t.Errorf("expected the output to report CANCELLED, got %s", output.Status)
+	}
+	stored, err := repo.FindByID(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("find job: %v", err)
+	}
+	if stored.Status != StatusCancelled || !stored.StartedAt.IsZero() {
+		t.Errorf("expected the job to stay CANCELLED without starting, got %s started at %v", stored.Status, stored.StartedAt)
+	}
+
+	storageClient.AssertNotCalled(t, "SaveTemp", mock.Anything, mock.Anything, mock.Anything)
+	storageClient.AssertNotCalled(t, "CleanupTemp", mock.Anything, mock.Anything)
+	processor.AssertNotCalled(t, "ResizeImageWithPadding", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	runpodClient.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_CancelJob_JobNotFound(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+
+	_, err := svc.CancelJob(context.Background(), "nonexistent")
+	if !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestProcessVideoService_CancelJob_StopsProcessing(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+	ctx := context.Background()
+
+	imageData := []byte("test-image-data")
+	audioData := []byte("test-audio-data")
+	input := ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString(imageData),
+		AudioBase64: base64.StdEncoding.EncodeToString(audioData),
+		Width:       384,
+		Height:      576,
+	}
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+
+	processor.On("ResizeImageWithPadding", mock.Anything, "/tmp/image.png", mock.Anything, 1024, 1024).
+		Run(func(args mock.Arguments) {
+			dst := args.Get(2).(string)
+			_ = os.WriteFile(dst, imageData, 0644)
+		}).
+		Return(nil).Once()
+
+	splitter.On("Split", mock.Anything, "/tmp/audio.wav", "/tmp", mock.Anything).
+		Return([]string{"/tmp/cancel_chunk_0.wav"}, nil).Once()
+
+	_ = os.WriteFile("/tmp/cancel_chunk_0.wav", audioData, 0644)
+	defer os.Remove("/tmp/cancel_chunk_0.wav")
+	defer os.Remove("/tmp/image.png")
+
+	// Signal once the chunk is being polled, i.e. it has a provider job ID
+	polling := make(chan struct{})
+	var pollOnce sync.Once
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job-123", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-123").
+		Run(func(args mock.Arguments) { pollOnce.Do(func() { close(polling) }) }).
+		Return(runpod.PollResult{Status: runpod.StatusRunning}, nil).Maybe()
+	runpodClient.On("Cancel", mock.Anything, "runpod-job-123").Return(nil).Once()
+
+	job, err := svc.CreateJob(ctx, input)
+	if err != nil {
+		t.Fatalf("failed to create job: %v", err)
+	}
+
+	done := make(chan *ProcessVideoOutput, 1)
+	go func() {
+		output, _ := svc.ProcessExistingJob(ctx, job.ID, input)
+		done <- output
+	}()
+
+	select {
+	case <-polling:
+	case <-time.After(time.Second):
+		t.Fatal("chunk was not submitted")
+	}
+
+	if _, err := svc.CancelJob(ctx, job.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case output := <-done:
+		if output == nil || output.Status != StatusCancelled {
+			t.Errorf("expected processing to stop with status CANCELLED, got %+v", output)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("processing did not stop after cancellation")
+	}
+
+	current, err := svc.GetJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("failed to get job: %v", err)
+	}
+	if current.Status != StatusCancelled {
+		t.Errorf("expected persisted status CANCELLED, got %s", current.Status)
+	}
+}
+
+// readerContains matches a *bytes.Reader holding want without consuming it.
+func readerContains(want []byte) any {
+	return mock.MatchedBy(func(r *bytes.Reader) bool {
+		data := make([]byte, r.Size())
+		_, _ = r.ReadAt(data, 0)
+		return bytes.Equal(data, want)
+	})
+}
+
+func TestProcessVideoService_ProviderActivity(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+	WithMaxChunkRetries(0)(svc)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	imagePath := filepath.Join(dir, "image.png")
+	audioPath := filepath.Join(dir, "audio.wav")
+	chunkPath := filepath.Join(dir, "chunk_0.wav")
+	for _, p := range []string{imagePath, audioPath, chunkPath} {
+		if err := os.WriteFile(p, []byte("data"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", p, err)
+		}
+	}
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return(imagePath, nil)
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return(audioPath, nil)
+	storageClient.On("SaveTemp", mock.Anything, mock.Anything, mock.Anything).Return(filepath.Join(dir, "chunk_0.mp4"), nil)
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+	processor.On("ResizeImageWithPadding", mock.Anything, imagePath, mock.Anything, 1024, 1024).
+		Run(func(args mock.Arguments) {
+			_ = os.WriteFile(args.Get(2).(string), []byte("resized"), 0644)
+		}).
+		Return(nil)
+	processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	splitter.On("Split", mock.Anything, audioPath, dir, mock.Anything).Return([]string{chunkPath}, nil)
+
+	// Hold every submit until all jobs are in flight; odd jobs fail to submit
+	release := make(chan struct{})
+	isFailing := func(o runpod.SubmitOptions) bool { return o.Prompt == "fail" }
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.MatchedBy(isFailing)).
+		Run(func(mock.Arguments) { <-release }).Return("", errors.New("provider unavailable"))
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { <-release }).Return("runpod-job-1", nil)
+	runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: base64.StdEncoding.EncodeToString([]byte("video"))}, nil)
+
+	const jobs = 6
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		prompt := "ok"
+		if i%2 == 1 {
+			prompt = "fail"
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = svc.Process(ctx, ProcessVideoInput{
+				ImageBase64: base64.StdEncoding.EncodeToString([]byte("data")),
+				AudioBase64: base64.StdEncoding.EncodeToString([]byte("data")),
+				Width:       384,
+				Height:      576,
+				Prompt:      prompt,
+			})
+		}()
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for svc.ProviderActivity()["runpod"].Submits != jobs {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d in-flight submits, got %+v", jobs, svc.ProviderActivity())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	close(release)
+	wg.Wait()
+
+	for provider, activity := range svc.ProviderActivity() {
+		if activity.Submits != 0 || activity.Polls != 0 {
+			t.Errorf("expected %s counters to return to zero, got %+v", provider, activity)
+		}
+	}
+}
+
+func TestProcessVideoService_RetryJob_ReprocessesRetainedInputs(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
+	WithMaxChunkRetries(0)(svc)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	imagePath := filepath.Join(dir, "image.png")
+	audioPath := filepath.Join(dir, "audio.wav")
+	chunkPath := filepath.Join(dir, "chunk_0.wav")
+	for _, p := range []string{imagePath, audioPath, chunkPath} {
+		if err := os.WriteFile(p, []byte("data"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", p, err)
+		}
+	}
+
+	// Inputs are only saved by the first run; the retry reuses them
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return(imagePath, nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return(audioPath, nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, mock.MatchedBy(func(s string) bool {
+		return strings.HasPrefix(s, "chunk_")
+	}), mock.Anything).Return(filepath.Join(dir, "chunk_0.mp4"), nil).Maybe()
+	var cleaned [][]string
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			cleaned = append(cleaned, args.Get(1).([]string))
+		}).
+		Return(nil)
+
+	processor.On("ResizeImageWithPadding", mock.Anything, imagePath, mock.Anything, 1024, 1024).
+		Run(func(args mock.Arguments) {
+			_ = os.WriteFile(args.Get(2).(string), []byte("resized"), 0644)
+		}).
+		Return(nil).Twice()
+	processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	splitter.On("Split", mock.Anything, audioPath, dir, mock.Anything).
+		Return([]string{chunkPath}, nil).Twice()
+
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("", errors.New("provider unavailable")).Once()
+
+	output, err := svc.Process(ctx, ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("data")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("data")),
+		Width:       384,
+		Height:      576,
+		Prompt:      "retry me",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusFailed {
+		t.Fatalf("expected status FAILED, got %s", output.Status)
+	}
+	for _, paths := range cleaned {
+		if slices.Contains(paths, imagePath) || slices.Contains(paths, audioPath) {
+			t.Fatalf("inputs of a failed job must be retained, cleaned %v", paths)
+		}
+	}
+
+	retried, err := svc.RetryJob(ctx, output.JobID)
+	if err != nil {
+		t.Fatalf("RetryJob failed: %v", err)
+	}
+	if retried.Status != StatusInQueue || retried.Error != "" {
+		t.Errorf("expected reset IN_QUEUE job, got %s (error: %q)", retried.Status, retried.Error)
+	}
+
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.MatchedBy(func(o runpod.SubmitOptions) bool {
+		return o.Prompt == "retry me" && o.Width == 384 && o.Height == 576
+	})).
+		Return("runpod-job-1", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: base64.StdEncoding.EncodeToString([]byte("video"))}, nil).Once()
+
+	output, err = svc.ProcessRetriedJob(ctx, retried.ID)
+	if err != nil {
+		t.Fatalf("ProcessRetriedJob failed: %v", err)
+	}
+	if output.Status != StatusCompleted {
+		t.Fatalf("expected status COMPLETED, got %s (error: %s)", output.Status, output.Error)
+	}
+
+	job, _ := repo.FindByID(ctx, retried.ID)
+	if job.Status != StatusCompleted {
+		t.Errorf("expected stored status COMPLETED, got %s", job.Status)
+	}
+	last := cleaned[len(cleaned)-1]
+	if !slices.Contains(last, imagePath) || !slices.Contains(last, audioPath) {
+		t.Errorf("expected inputs to be cleaned after a successful retry, got %v", last)
+	}
+
+	storageClient.AssertExpectations(t)
+	splitter.AssertExpectations(t)
+	runpodClient.AssertExpectations(t)
+}
+
+func TestProcessVideoService_RetryJob_NotRetryable(t *testing.T) {
+	svc, _, _, _, _, repo := newTestService(t)
+	ctx := context.Background()
+
+	for _, status := range []Status{StatusInQueue, StatusRunning, StatusCompleted, StatusCancelled} {
+		t.Run(string(status), func(t *testing.T) {
+			job := New()
+			job.Status = status
+			if err := repo.Save(ctx, job); err != nil {
+				t.Fatalf("failed to save job: %v", err)
+			}
+
+			_, err := svc.RetryJob(ctx, job.ID)
+			if !errors.Is(err, ErrJobNotRetryable) {
+				t.Errorf("expected ErrJobNotRetryable, got %v", err)
+			}
+		})
+	}
+}
+
+func TestProcessVideoService_RetryJob_InputsUnavailable(t *testing.T) {
+	svc, _, _, _, _, repo := newTestService(t)
+	ctx := context.Background()
+
+	job := New()
+	job.InputImagePath = filepath.Join(t.TempDir(), "missing.png")
+	job.InputAudioPath = filepath.Join(t.TempDir(), "missing.wav")
+	job.Status = StatusFailed
+	if err := repo.Save(ctx, job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+
+	_, err := svc.RetryJob(ctx, job.ID)
+	if !errors.Is(err, ErrRetryInputsUnavailable) {
+		t.Errorf("expected ErrRetryInputsUnavailable, got %v", err)
+	}
+
+	stored, _ := repo.FindByID(ctx, job.ID)
+	if stored.Status != StatusFailed {
+		t.Errorf("expected job to stay FAILED, got %s", stored.Status)
+	}
+}
+
+func TestProcessVideoService_RetryJob_JobNotFound(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+
+	_, err := svc.RetryJob(context.Background(), "nonexistent")
+	if !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestProcessVideoService_Process_URLInputs(t *testing.T) {
+	svc, processor, splitter, _, storageClient, _ := newTestService(t)
+	fetcher := &mockFetcher{}
+	WithInputFetcher(fetcher)(svc)
+	ctx := context.Background()
+
+	imageData := []byte("remote-image")
+	audioData := []byte("remote-audio")
+	input := ProcessVideoInput{
+		ImageURL: "https://cdn.example.com/face.png",
+		AudioURL: "https://cdn.example.com/voice.wav",
+		Width:    384,
+		Height:   576,
+		DryRun:   true,
+	}
+
+	fetcher.On("Fetch", mock.Anything, input.ImageURL).Return(imageData, nil).Once()
+	fetcher.On("Fetch", mock.Anything, input.AudioURL).Return(audioData, nil).Once()
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", readerContains(imageData)).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", readerContains(audioData)).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+
+	processor.On("ResizeImageWithPadding", mock.Anything, "/tmp/image.png", mock.Anything, 1024, 1024).
+		Run(func(args mock.Arguments) {
+			dst := args.Get(2).(string)
+			_ = os.WriteFile(dst, imageData, 0644)
+		}).
+		Return(nil).Once()
+
+	splitter.On("Split", mock.Anything, "/tmp/audio.wav", "/tmp", mock.Anything).
+		Return([]string{"/tmp/chunk_0.wav"}, nil).Once()
+
+	output, err := svc.Process(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusCompleted {
+		t.Errorf("expected status %s, got %s (error: %s)", StatusCompleted, output.Status, output.Error)
+	}
+
+	fetcher.AssertExpectations(t)
+	storageClient.AssertExpectations(t)
+}
+
+func TestProcessVideoService_Process_URLInputDownloadFails(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr string
+	}{
+		{"oversized", fetch.ErrTooLarge, "download exceeds maximum size"},
+		{"timed out", fetch.ErrTimeout, "download timed out"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, processor, _, runpodClient, storageClient, repo := newTestService(t)
+			fetcher := &mockFetcher{}
+			WithInputFetcher(fetcher)(svc)
+			ctx := context.Background()
+
+			input := ProcessVideoInput{
+				ImageURL:    "https://cdn.example.com/huge.png",
+				AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio-data")),
+				Width:       384,
+				Height:      576,
+			}
+
+			fetcher.On("Fetch", mock.Anything, input.ImageURL).Return(nil, tt.err).Once()
+
+			output, err := svc.Process(ctx, input)
+			if err != nil {
+				t.Fatalf("Process should not return error, got: %v", err)
+			}
+			if output.Status != StatusFailed {
+				t.Errorf("expected status FAILED, got %s", output.Status)
+			}
+			if !strings.Contains(output.Error, tt.wantErr) {
+				t.Errorf("expected error to contain %q, got %q", tt.wantErr, output.Error)
+			}
+
+			job, err := repo.FindByID(ctx, output.JobID)
+			if err != nil {
+				t.Fatalf("job should exist in repository: %v", err)
+			}
+			if job.Status != StatusFailed {
+				t.Errorf("expected persisted status FAILED, got %s", job.Status)
+			}
+
+			storageClient.AssertNotCalled(t, "SaveTemp", mock.Anything, mock.Anything, mock.Anything)
+			processor.AssertNotCalled(t, "ResizeImageWithPadding", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			runpodClient.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestProcessVideoService_Process_URLInputWithoutFetcher(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+
+	output, err := svc.Process(context.Background(), ProcessVideoInput{
+		ImageURL: "https://cdn.example.com/face.png",
+		Width:    384,
+		Height:   576,
+	})
+	if err != nil {
+		t.Fatalf("Process should not return error, got: %v", err)
+	}
+	if output.Status != StatusFailed {
+		t.Errorf("expected status FAILED, got %s", output.Status)
+	}
+	if !strings.Contains(output.Error, ErrFetcherNotConfigured.Error()) {
+		t.Errorf("expected error to mention missing fetcher, got %q", output.Error)
+	}
+}
+
+func TestProcessVideoService_Process_PersonCount(t *testing.T) {
+	tests := []struct {
+		name          string
+		personCount   string
+		wantMaxChunks int
+	}{
+		{"single person splits audio", PersonCountSingle, audio.DefaultSplitOpts().MaxChunks},
+		{"multi person keeps one chunk", PersonCountMulti, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
+			ctx := context.Background()
+
+			chunkPath := mockSingleChunkPipeline(t, processor, storageClient)
+			splitter.On("Split", mock.Anything, "/tmp/audio.wav", "/tmp", mock.MatchedBy(func(o audio.SplitOpts) bool {
+				return o.MaxChunks == tt.wantMaxChunks
+			})).
+				Return([]string{chunkPath}, nil).Once()
+			runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.MatchedBy(func(o runpod.SubmitOptions) bool {
+				return o.PersonCount == tt.personCount
+			})).
+				Return("runpod-job-1", nil).Once()
+			runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+				Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: base64.StdEncoding.EncodeToString([]byte("video"))}, nil).Once()
+
+			output, err := svc.Process(ctx, ProcessVideoInput{
+				ImageBase64: base64.StdEncoding.EncodeToString([]byte("image")),
+				AudioBase64: base64.StdEncoding.EncodeToString([]byte("audio")),
+				Width:       384,
+				Height:      576,
+				PersonCount: tt.personCount,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output.Status != StatusCompleted {
+				t.Fatalf("expected status COMPLETED, got %s (error: %s)", output.Status, output.Error)
+			}
+
+			job, _ := repo.FindByID(ctx, output.JobID)
+			if job.PersonCount != tt.personCount {
+				t.Errorf("expected stored person count %q, got %q", tt.personCount, job.PersonCount)
+			}
+
+			splitter.AssertExpectations(t)
+			runpodClient.AssertExpectations(t)
+		})
+	}
+}
+
+// mockSingleChunkPipeline sets up the storage and media mocks for a job whose
+// audio is a single chunk, and returns the path of that chunk.
+func mockSingleChunkPipeline(t *testing.T, processor *mockProcessor, storageClient *mockStorage) string {
+	t.Helper()
+	chunkPath := filepath.Join(t.TempDir(), "chunk_0.wav")
+	_ = os.WriteFile(chunkPath, []byte("audio"), 0600)
+	t.Cleanup(func() { os.Remove("/tmp/image.png") })
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, mock.Anything, mock.Anything).Return("/tmp/chunk_0.mp4", nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+	processor.On("ResizeImageWithPadding", mock.Anything, "/tmp/image.png", mock.Anything, 1024, 1024).
+		Run(func(args mock.Arguments) {
+			_ = os.WriteFile(args.Get(2).(string), []byte("image"), 0600)
+		}).
+		Return(nil).Once()
+	processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	return chunkPath
+}
+
+func TestProcessVideoService_Process_OutputFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		ext    string
+	}{
+		{"", ".mp4"},
+		{"mov", ".mov"},
+		{"webm", ".webm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ext, func(t *testing.T) {
+			svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
+			ctx := context.Background()
+
+			chunkPath := mockSingleChunkPipeline(t, processor, storageClient)
+			splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return([]string{chunkPath}, nil).Once()
+			runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return("runpod-job-1", nil).Once()
+			runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+				Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: "dmlkZW8="}, nil).Once()
+
+			input := validateInput()
+			input.OutputFormat = tt.format
+			output, err := svc.Process(ctx, input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if filepath.Ext(output.VideoPath) != tt.ext {
+				t.Errorf("expected output video with extension %s, got %s", tt.ext, output.VideoPath)
+			}
+			processor.AssertCalled(t, "JoinVideos", mock.Anything, mock.Anything, output.VideoPath)
+			stored, _ := repo.FindByID(ctx, output.JobID)
+			if want := "videos/" + output.JobID + tt.ext; videoKey(stored.ID, stored.OutputVideoPath) != want {
+				t.Errorf("expected remote key %s, got %s", want, videoKey(stored.ID, stored.OutputVideoPath))
+			}
+		})
+	}
+}
+
+// newBeamTestService is newTestService with a Beam client configured.
+func newBeamTestService(t *testing.T) (*ProcessVideoService, *mockProcessor, *mockSplitter, *mockBeamClient, *mockStorage) {
+	svc, processor, splitter, _, storageClient, _ := newTestService(t)
+	beamClient := &mockBeamClient{}
+	svc.beamClient = beamClient
+	return svc, processor, splitter, beamClient, storageClient
+}
+
+func TestProcessVideoService_Process_BeamDownloadsOutput(t *testing.T) {
+	svc, processor, splitter, beamClient, storageClient := newBeamTestService(t)
+	ctx := context.Background()
+
+	chunkPath := mockSingleChunkPipeline(t, processor, storageClient)
+	splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]string{chunkPath}, nil).Once()
+	beamClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("beam-task-1", nil).Once()
+	beamClient.On("Poll", mock.Anything, "beam-task-1").
+		Return(beam.PollResult{Status: beam.StatusCompleted, OutputURL: "https://beam.example/output.mp4"}, nil).Once()
+	beamClient.On("DownloadOutput", mock.Anything, "https://beam.example/output.mp4", mock.Anything).
+		Run(func(args mock.Arguments) {
+			_ = os.WriteFile(args.Get(2).(string), []byte("video"), 0600)
+		}).
+		Return(nil).Once()
+
+	input := validateInput()
+	input.Provider = string(ProviderBeam)
+	output, err := svc.Process(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusCompleted {
+		t.Fatalf("expected status COMPLETED, got %s (error: %s)", output.Status, output.Error)
+	}
+
+	// The download lands in temp storage next to the chunk audio
+	downloaded := filepath.Join(filepath.Dir(chunkPath), "chunk_"+output.JobID+"_0.mp4")
+	if data, err := os.ReadFile(downloaded); err != nil || string(data) != "video" {
+		t.Fatalf("expected the downloaded video at %s, got %q (%v)", downloaded, data, err)
+	}
+	processor.AssertCalled(t, "JoinVideos", mock.Anything, []string{downloaded}, output.VideoPath)
+	storageClient.AssertNotCalled(t, "SaveTemp", mock.Anything, "chunk_"+output.JobID+"_0.mp4", mock.Anything)
+	beamClient.AssertExpectations(t)
+}
+
+func TestProcessVideoService_Process_BeamDownloadFailure(t *testing.T) {
+	svc, processor, splitter, beamClient, storageClient := newBeamTestService(t)
+	ctx := context.Background()
+
+	chunkPath := mockSingleChunkPipeline(t, processor, storageClient)
+	splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]string{chunkPath}, nil).Once()
+	beamClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("beam-task-1", nil).Once()
+	beamClient.On("Poll", mock.Anything, "beam-task-1").
+		Return(beam.PollResult{Status: beam.StatusCompleted, OutputURL: "https://beam.example/output.mp4"}, nil).Once()
+	beamClient.On("DownloadOutput", mock.Anything, "https://beam.example/output.mp4", mock.Anything).
+		Run(func(args mock.Arguments) {
+			// A download cut short leaves a partial file
+			_ = os.WriteFile(args.Get(2).(string), []byte("vid"), 0600)
+		}).
+		Return(errors.New("connection reset")).Once()
+
+	input := validateInput()
+	input.Provider = string(ProviderBeam)
+	output, err := svc.Process(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusFailed {
+		t.Fatalf("expected status FAILED, got %s", output.Status)
+	}
+	if !strings.Contains(output.Error, "connection reset") {
+		t.Errorf("expected the download error, got %q", output.Error)
+	}
+
+	partial := filepath.Join(filepath.Dir(chunkPath), "chunk_"+output.JobID+"_0.mp4")
+	if _, err := os.Stat(partial); !os.IsNotExist(err) {
+		t.Errorf("expected the partial download to be removed, got %v", err)
+	}
+	processor.AssertNotCalled(t, "JoinVideos", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_CreateJob_InvalidOutputFormat(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+
+	input := validateInput()
+	input.OutputFormat = "avi"
+	if _, err := svc.CreateJob(context.Background(), input); !errors.Is(err, ErrInvalidOutputFormat) {
+		t.Errorf("expected ErrInvalidOutputFormat, got %v", err)
+	}
+}
+
+func TestProcessVideoService_Process_TargetFPS(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+
+	chunkPath := mockSingleChunkPipeline(t, processor, storageClient)
+	splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]string{chunkPath}, nil).Once()
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job-1", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: "dmlkZW8="}, nil).Once()
+	processor.On("NormalizeFPS", mock.Anything, "/tmp/chunk_0.mp4", "/tmp/chunk_0_25fps.mp4", 25.0).
+		Return(nil).Once()
+
+	input := validateInput()
+	input.TargetFPS = 25
+	output, err := svc.Process(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusCompleted {
+		t.Fatalf("expected status COMPLETED, got %s (error: %s)", output.Status, output.Error)
+	}
+
+	processor.AssertCalled(t, "JoinVideos", mock.Anything, []string{"/tmp/chunk_0_25fps.mp4"}, output.VideoPath)
+	storageClient.AssertCalled(t, "CleanupTemp", mock.Anything, mock.MatchedBy(func(paths []string) bool {
+		return slices.Contains(paths, "/tmp/chunk_0.mp4") && slices.Contains(paths, "/tmp/chunk_0_25fps.mp4")
+	}))
+	stored, _ := repo.FindByID(ctx, output.JobID)
+	if stored.TargetFPS != 25 {
+		t.Errorf("expected stored target fps 25, got %g", stored.TargetFPS)
+	}
+}
+
+func TestProcessVideoService_Process_TargetFPSFailure(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+	ctx := context.Background()
+
+	chunkPath := mockSingleChunkPipeline(t, processor, storageClient)
+	splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]string{chunkPath}, nil).Once()
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job-1", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: "dmlkZW8="}, nil).Once()
+	processor.On("NormalizeFPS", mock.Anything, mock.Anything, mock.Anything, 30.0).
+		Return(errors.New("ffmpeg failed")).Once()
+
+	input := validateInput()
+	input.TargetFPS = 30
+	output, err := svc.Process(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusFailed {
+		t.Fatalf("expected status FAILED, got %s", output.Status)
+	}
+	if !strings.Contains(output.Error, "normalize frame rate") {
+		t.Errorf("expected a frame rate error, got %q", output.Error)
+	}
+	processor.AssertNotCalled(t, "JoinVideos", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_Process_UseOriginalAudio(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+
+	chunkPath := mockSingleChunkPipeline(t, processor, storageClient)
+	splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]string{chunkPath}, nil).Once()
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job-1", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: "dmlkZW8="}, nil).Once()
+	processor.On("ReplaceAudio", mock.Anything, mock.Anything, "/tmp/audio.wav", mock.Anything).
+		Return(nil).Once()
+
+	input := validateInput()
+	input.UseOriginalAudio = true
+	output, err := svc.Process(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusCompleted {
+		t.Fatalf("expected status COMPLETED, got %s (error: %s)", output.Status, output.Error)
+	}
+
+	joined := filepath.Join("/tmp", "joined_"+output.JobID+".mp4")
+	processor.AssertCalled(t, "JoinVideos", mock.Anything, []string{"/tmp/chunk_0.mp4"}, joined)
+	processor.AssertCalled(t, "ReplaceAudio", mock.Anything, joined, "/tmp/audio.wav", output.VideoPath)
+	storageClient.AssertCalled(t, "CleanupTemp", mock.Anything, mock.MatchedBy(func(paths []string) bool {
+		return slices.Contains(paths, joined) && !slices.Contains(paths, output.VideoPath)
+	}))
+	stored, _ := repo.FindByID(ctx, output.JobID)
+	if !stored.UseOriginalAudio {
+		t.Error("expected the option to be stored with the job")
+	}
+}
+
+func TestProcessVideoService_Process_UseOriginalAudioDisabled(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+	ctx := context.Background()
+
+	chunkPath := mockSingleChunkPipeline(t, processor, storageClient)
+	splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]string{chunkPath}, nil).Once()
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job-1", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: "dmlkZW8="}, nil).Once()
+
+	output, err := svc.Process(ctx, validateInput())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusCompleted {
+		t.Fatalf("expected status COMPLETED, got %s (error: %s)", output.Status, output.Error)
+	}
+	processor.AssertCalled(t, "JoinVideos", mock.Anything, mock.Anything, output.VideoPath)
+	processor.AssertNotCalled(t, "ReplaceAudio", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_Process_ReplaceAudioFailure(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+	ctx := context.Background()
+
+	chunkPath := mockSingleChunkPipeline(t, processor, storageClient)
+	splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]string{chunkPath}, nil).Once()
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job-1", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: "dmlkZW8="}, nil).Once()
+	processor.On("ReplaceAudio", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(errors.New("ffmpeg failed")).Once()
+
+	input := validateInput()
+	input.UseOriginalAudio = true
+	output, err := svc.Process(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusFailed {
+		t.Fatalf("expected status FAILED, got %s", output.Status)
+	}
+	if !strings.Contains(output.Error, "replace audio") {
+		t.Errorf("expected a replace audio error, got %q", output.Error)
+	}
+}
+
+func TestProcessVideoService_Process_ChunkDimensionsMismatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		strict bool
+	}{
+		{"resized by default", false},
+		{"rejected when strict", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+			ctx := context.Background()
+
+			chunkPath := mockSingleChunkPipeline(t, processor, storageClient)
+			splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return([]string{chunkPath}, nil).Once()
+			runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return("runpod-job-1", nil).Once()
+			runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+				Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: "dmlkZW8="}, nil).Once()
+			processor.On("ProbeVideo", mock.Anything, "/tmp/chunk_0.mp4").
+				Return(media.VideoInfo{Width: 368, Height: 576, DurationSec: 4}, nil).Once()
+			if !tt.strict {
+				processor.On("ResizeVideo", mock.Anything, "/tmp/chunk_0.mp4", "/tmp/chunk_0_384x576.mp4", 384, 576, media.ResizeModePad).
+					Return(nil).Once()
+			}
+
+			input := validateInput()
+			input.StrictDimensions = tt.strict
+			output, err := svc.Process(ctx, input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.strict {
+				if output.Status != StatusFailed {
+					t.Fatalf("expected status FAILED, got %s", output.Status)
+				}
+				if !strings.Contains(output.Error, ErrChunkDimensionsMismatch.Error()) || !strings.Contains(output.Error, "368x576") {
+					t.Errorf("expected a dimensions mismatch error naming the chunk size, got %q", output.Error)
+				}
+				processor.AssertNotCalled(t, "ResizeVideo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				processor.AssertNotCalled(t, "JoinVideos", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			if output.Status != StatusCompleted {
+				t.Fatalf("expected status COMPLETED, got %s (error: %s)", output.Status, output.Error)
+			}
+			processor.AssertCalled(t, "JoinVideos", mock.Anything, []string{"/tmp/chunk_0_384x576.mp4"}, output.VideoPath)
+			storageClient.AssertCalled(t, "CleanupTemp", mock.Anything, mock.MatchedBy(func(paths []string) bool {
+				return slices.Contains(paths, "/tmp/chunk_0_384x576.mp4")
+			}))
+		})
+	}
+}
+
+func TestProcessVideoService_CreateJob_InvalidTargetFPS(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+
+	input := validateInput()
+	input.TargetFPS = -1
+	if _, err := svc.CreateJob(context.Background(), input); !errors.Is(err, ErrInvalidTargetFPS) {
+		t.Errorf("expected ErrInvalidTargetFPS, got %v", err)
+	}
+}
+
+func TestProcessVideoService_Process_Prompt(t *testing.T) {
+	tests := []struct {
+		name   string
+		prompt string
+		want   string
+	}{
+		{"custom prompt", "a woman talking calmly", "a woman talking calmly"},
+		{"empty prompt", "", DefaultPrompt},
+		{"blank prompt", "   ", DefaultPrompt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+			ctx := context.Background()
+
+			chunkPath := mockSingleChunkPipeline(t, processor, storageClient)
+			splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return([]string{chunkPath}, nil).Once()
+			runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.MatchedBy(func(o runpod.SubmitOptions) bool {
+				return o.Prompt == tt.want
+			})).
+				Return("runpod-job-1", nil).Once()
+			runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+				Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: base64.StdEncoding.EncodeToString([]byte("video"))}, nil).Once()
+
+			output, err := svc.Process(ctx, ProcessVideoInput{
+				ImageBase64: base64.StdEncoding.EncodeToString([]byte("image")),
+				AudioBase64: base64.StdEncoding.EncodeToString([]byte("audio")),
+				Width:       384,
+				Height:      576,
+				Prompt:      tt.prompt,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output.Status != StatusCompleted {
+				t.Fatalf("expected status COMPLETED, got %s (error: %s)", output.Status, output.Error)
+			}
+
+			runpodClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProcessVideoService_Process_VideoInput(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	videoPath := filepath.Join(tempDir, "video.mp4")
+	chunkPath := filepath.Join(tempDir, "chunk_0.wav")
+	_ = os.WriteFile(videoPath, []byte("source-video"), 0600)
+	_ = os.WriteFile(chunkPath, []byte("audio"), 0600)
+
+	storageClient.On("SaveTemp", mock.Anything, "video.mp4", mock.Anything).Return(videoPath, nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return(filepath.Join(tempDir, "audio.wav"), nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, mock.Anything, mock.Anything).Return(filepath.Join(tempDir, "chunk_0.mp4"), nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+	processor.On("ProbeVideo", mock.Anything, videoPath).
+		Return(media.VideoInfo{Width: 720, Height: 1280, DurationSec: 4}, nil).Once()
+	processor.On("ProbeVideo", mock.Anything, filepath.Join(tempDir, "chunk_0.mp4")).
+		Return(media.VideoInfo{Width: 384, Height: 576, DurationSec: 4}, nil).Once()
+	processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]string{chunkPath}, nil).Once()
+
+	videoB64 := base64.StdEncoding.EncodeToString([]byte("source-video"))
+	runpodClient.On("Submit", mock.Anything, videoB64, mock.Anything, mock.MatchedBy(func(o runpod.SubmitOptions) bool {
+		return o.InputType == InputTypeVideo
+	})).
+		Return("runpod-job-1", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: base64.StdEncoding.EncodeToString([]byte("video"))}, nil).Once()
+
+	output, err := svc.Process(ctx, ProcessVideoInput{
+		VideoBase64: videoB64,
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("audio")),
+		Width:       384,
+		Height:      576,
+		InputType:   InputTypeVideo,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusCompleted {
+		t.Fatalf("expected status COMPLETED, got %s (error: %s)", output.Status, output.Error)
+	}
+
+	job, _ := repo.FindByID(ctx, output.JobID)
+	if job.InputType != InputTypeVideo {
+		t.Errorf("expected stored input type %q, got %q", InputTypeVideo, job.InputType)
+	}
+
+	// The image-only resize step is bypassed for video inputs
+	processor.AssertNotCalled(t, "ResizeImageWithPadding", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	processor.AssertNotCalled(t, "ResizeImageCropToFill", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	processor.AssertExpectations(t)
+	runpodClient.AssertExpectations(t)
+}
+
+func TestProcessVideoService_Process_VideoInputNotAVideo(t *testing.T) {
+	svc, processor, _, runpodClient, storageClient, _ := newTestService(t)
+	ctx := context.Background()
+
+	storageClient.On("SaveTemp", mock.Anything, "video.mp4", mock.Anything).Return("/tmp/video.mp4", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	processor.On("ProbeVideo", mock.Anything, "/tmp/video.mp4").
+		Return(media.VideoInfo{}, media.ErrNoVideoStream).Once()
+
+	output, err := svc.Process(ctx, ProcessVideoInput{
+		VideoBase64: base64.StdEncoding.EncodeToString([]byte("not-a-video")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("audio")),
+		Width:       384,
+		Height:      576,
+		InputType:   InputTypeVideo,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusFailed {
+		t.Fatalf("expected status FAILED, got %s", output.Status)
+	}
+	if !strings.Contains(output.Error, ErrInvalidVideo.Error()) {
+		t.Errorf("expected invalid video error, got %q", output.Error)
+	}
+	runpodClient.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_Process_InvalidInputsFailFast(t *testing.T) {
+	tests := []struct {
+		name      string
+		image     media.ImageInfo
+		imageErr  error
+		audio     media.AudioInfo
+		audioErr  error
+		wantError string
+	}{
+		{
+			name:      "empty audio",
+			image:     media.ImageInfo{Width: 64, Height: 64, Codec: "png"},
+			audio:     media.AudioInfo{Codec: "pcm_s16le"},
+			wantError: "audio has no duration",
+		},
+		{
+			name:      "malformed audio",
+			image:     media.ImageInfo{Width: 64, Height: 64, Codec: "png"},
+			audioErr:  errors.New("ffprobe: Invalid data found when processing input"),
+			wantError: "Invalid data found",
+		},
+		{
+			name:      "unknown audio codec",
+			image:     media.ImageInfo{Width: 64, Height: 64, Codec: "png"},
+			audio:     media.AudioInfo{DurationSec: 3, Codec: "none"},
+			wantError: "unsupported audio codec",
+		},
+		{
+			name:      "malformed image",
+			imageErr:  media.ErrNoVideoStream,
+			wantError: ErrInvalidImage.Error(),
+		},
+		{
+			name:      "image without dimensions",
+			image:     media.ImageInfo{Codec: "png"},
+			wantError: "image has no dimensions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+			ctx := context.Background()
+
+			storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+			storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+			processor.On("ProbeImage", mock.Anything, "/tmp/image.png").Return(tt.image, tt.imageErr).Once()
+			processor.On("ProbeAudio", mock.Anything, "/tmp/audio.wav").Return(tt.audio, tt.audioErr).Maybe()
+
+			output, err := svc.Process(ctx, ProcessVideoInput{
+				ImageBase64: base64.StdEncoding.EncodeToString([]byte("image")),
+				AudioBase64: base64.StdEncoding.EncodeToString([]byte("audio")),
+				Width:       384,
+				Height:      576,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output.Status != StatusFailed {
+				t.Fatalf("expected status FAILED, got %s", output.Status)
+			}
+			if !strings.Contains(output.Error, "invalid input") || !strings.Contains(output.Error, tt.wantError) {
+				t.Errorf("expected error containing %q, got %q", tt.wantError, output.Error)
+			}
+
+			processor.AssertNotCalled(t, "ResizeImageWithPadding", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			splitter.AssertNotCalled(t, "Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			runpodClient.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestProcessVideoService_Process_AudioTooLong(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+	WithMaxAudioDuration(10 * time.Minute)(svc)
+	ctx := context.Background()
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	processor.On("ProbeAudio", mock.Anything, "/tmp/audio.wav").
+		Return(media.AudioInfo{DurationSec: 3600, SampleRate: 16000, Channels: 1, Codec: "pcm_s16le"}, nil).Once()
+
+	output, err := svc.Process(ctx, ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("image")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("audio")),
+		Width:       384,
+		Height:      576,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusFailed {
+		t.Fatalf("expected status FAILED, got %s", output.Status)
+	}
+	if !strings.Contains(output.Error, ErrAudioTooLong.Error()) || !strings.Contains(output.Error, "10m0s") {
+		t.Errorf("expected audio too long error naming the limit, got %q", output.Error)
+	}
+
+	splitter.AssertNotCalled(t, "Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	runpodClient.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_Process_ConvertsImage(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+	ctx := context.Background()
+
+	chunkPath := filepath.Join(t.TempDir(), "chunk_0.wav")
+	_ = os.WriteFile(chunkPath, []byte("audio"), 0600)
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, mock.Anything, mock.Anything).Return("/tmp/chunk_0.mp4", nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+	processor.On("ProbeImage", mock.Anything, "/tmp/image.png").
+		Return(media.ImageInfo{Width: 800, Height: 600, Codec: "webp"}, nil).Once()
+
+	var normalizedPath string
+	processor.On("NormalizeImage", mock.Anything, "/tmp/image.png", mock.Anything).
+		Run(func(args mock.Arguments) {
+			normalizedPath = args.Get(2).(string)
+			_ = os.WriteFile(normalizedPath, []byte("png"), 0600)
+		}).
+		Return(nil).Once()
+	t.Cleanup(func() { os.Remove(normalizedPath) })
+	processor.On("ResizeImageWithPadding", mock.Anything, mock.MatchedBy(func(src string) bool {
+		return src == normalizedPath
+	}), mock.Anything, 1024, 1024).
+		Run(func(args mock.Arguments) {
+			_ = os.WriteFile(args.Get(2).(string), []byte("image"), 0600)
+		}).
+		Return(nil).Once()
+	processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]string{chunkPath}, nil).Once()
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job-1", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: "dmlkZW8="}, nil).Once()
+
+	output, err := svc.Process(ctx, validateInput())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusCompleted {
+		t.Fatalf("expected status COMPLETED, got %s (%s)", output.Status, output.Error)
+	}
+	if filepath.Ext(normalizedPath) != ".png" {
+		t.Errorf("expected the image to be converted to a .png file, got %q", normalizedPath)
+	}
+
+	processor.AssertExpectations(t)
+}
+
+func TestProcessVideoService_Process_UnconvertibleImage(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+	ctx := context.Background()
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+	processor.On("ProbeImage", mock.Anything, "/tmp/image.png").
+		Return(media.ImageInfo{Width: 800, Height: 600, Codec: "hevc"}, nil).Once()
+	processor.On("NormalizeImage", mock.Anything, "/tmp/image.png", mock.Anything).
+		Return(media.ErrUnsupportedImageFormat).Once()
+
+	output, err := svc.Process(ctx, validateInput())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusFailed {
+		t.Fatalf("expected status FAILED, got %s", output.Status)
+	}
+	if !strings.Contains(output.Error, `"hevc"`) || !strings.Contains(output.Error, media.ErrUnsupportedImageFormat.Error()) {
+		t.Errorf("expected an error naming the image format, got %q", output.Error)
+	}
+
+	processor.AssertNotCalled(t, "ResizeImageWithPadding", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	splitter.AssertNotCalled(t, "Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	runpodClient.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_Process_ImageTooLarge(t *testing.T) {
+	svc, processor, splitter, _, storageClient, _ := newTestService(t)
+	WithMaxImagePixels(50_000_000)(svc)
+	ctx := context.Background()
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	processor.On("ProbeImage", mock.Anything, "/tmp/image.png").
+		Return(media.ImageInfo{Width: 12000, Height: 9000, Codec: "webp"}, nil).Once()
+
+	output, err := svc.Process(ctx, validateInput())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusFailed {
+		t.Fatalf("expected status FAILED, got %s", output.Status)
+	}
+	if !strings.Contains(output.Error, ErrImageTooLarge.Error()) || !strings.Contains(output.Error, "12000x9000") {
+		t.Errorf("expected image too large error naming the size, got %q", output.Error)
+	}
+
+	processor.AssertNotCalled(t, "NormalizeImage", mock.Anything, mock.Anything, mock.Anything)
+	splitter.AssertNotCalled(t, "Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_CreateJob_VideoInputRequiresRunPod(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+
+	_, err := svc.CreateJob(context.Background(), ProcessVideoInput{
+		VideoBase64: base64.StdEncoding.EncodeToString([]byte("video")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("audio")),
+		Width:       384,
+		Height:      576,
+		Provider:    string(ProviderBeam),
+		InputType:   InputTypeVideo,
+	})
+	if !errors.Is(err, ErrUnsupportedInputType) {
+		t.Errorf("expected ErrUnsupportedInputType, got %v", err)
+	}
+}
+
+func TestProcessVideoService_CreateJob_InvalidPriority(t *testing.T) {
+	svc, _, _, _, _, _ := newTestService(t)
+
+	input := validateInput()
+	input.Priority = "urgent"
+	if _, err := svc.CreateJob(context.Background(), input); !errors.Is(err, ErrInvalidPriority) {
+		t.Errorf("expected ErrInvalidPriority, got %v", err)
+	}
+}
+
+func TestProcessVideoService_Process_ForceOffload(t *testing.T) {
+	for _, forceOffload := range []bool{true, false} {
+		t.Run(fmt.Sprint(forceOffload), func(t *testing.T) {
+			svc, processor, splitter, runpodClient, storageClient, _ := newTestService(t)
+			ctx := context.Background()
+
+			chunkPath := mockSingleChunkPipeline(t, processor, storageClient)
+			splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return([]string{chunkPath}, nil).Once()
+
+			var submitted runpod.SubmitOptions
+			runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					submitted = args.Get(3).(runpod.SubmitOptions)
+				}).
+				Return("runpod-job-1", nil).Once()
+			runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+				Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: base64.StdEncoding.EncodeToString([]byte("video"))}, nil).Once()
+
+			output, err := svc.Process(ctx, ProcessVideoInput{
+				ImageBase64:  base64.StdEncoding.EncodeToString([]byte("image")),
+				AudioBase64:  base64.StdEncoding.EncodeToString([]byte("audio")),
+				Width:        384,
+				Height:       576,
+				ForceOffload: forceOffload,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output.Status != StatusCompleted {
+				t.Fatalf("expected status COMPLETED, got %s (error: %s)", output.Status, output.Error)
+			}
+			if submitted.ForceOffload != forceOffload {
+				t.Errorf("expected ForceOffload %v to reach the provider, got %v", forceOffload, submitted.ForceOffload)
+			}
+		})
+	}
+}
+
+// stageRecordingRepo records the stage and progress of every saved job.
+type stageRecordingRepo struct {
+	Repository
+	mu    sync.Mutex
+	saves []stageSnapshot
+}
+
+type stageSnapshot struct {
+	stage    Stage
+	progress int
+}
+
+func (r *stageRecordingRepo) Save(ctx context.Context, job *Job) error {
+	snapshot := job.Clone()
+	r.mu.Lock()
+	r.saves = append(r.saves, stageSnapshot{stage: snapshot.Stage, progress: snapshot.Progress})
+	r.mu.Unlock()
+	return r.Repository.Save(ctx, job)
+}
+
+func TestProcessVideoService_Process_StageTransitions(t *testing.T) {
+	svc, processor, splitter, runpodClient, storageClient, repo := newTestService(t)
+	rec := &stageRecordingRepo{Repository: repo}
+	svc.repo = rec
+	ctx := context.Background()
+
+	chunkPath := filepath.Join(t.TempDir(), "chunk_0.wav")
+	_ = os.WriteFile(chunkPath, []byte("audio"), 0600)
+	t.Cleanup(func() { os.Remove("/tmp/image.png") })
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, mock.Anything, mock.Anything).Return("/tmp/chunk_0.mp4", nil).Once()
+	storageClient.On("Upload", mock.Anything, mock.Anything, mock.Anything).Return("https://s3.example.com/videos/output.mp4", nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+	processor.On("ResizeImageWithPadding", mock.Anything, "/tmp/image.png", mock.Anything, 1024, 1024).
+		Run(func(args mock.Arguments) {
+			_ = os.WriteFile(args.Get(2).(string), []byte("image"), 0600)
+		}).
+		Return(nil).Once()
+	processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			_ = os.WriteFile(args.Get(2).(string), []byte("video"), 0600)
+		}).
+		Return(nil).Once()
+	splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]string{chunkPath}, nil).Once()
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("runpod-job-1", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: "dmlkZW8="}, nil).Once()
+
+	input := validateInput()
+	input.PushToS3 = true
+	output, err := svc.Process(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Status != StatusCompleted {
+		t.Fatalf("expected status COMPLETED, got %s", output.Status)
+	}
+
+	// Collapse consecutive saves of the same stage and progress
+	var got []stageSnapshot
+	for _, s := range rec.saves {
+		if len(got) == 0 || got[len(got)-1] != s {
+			got = append(got, s)
+		}
+	}
+	want := []stageSnapshot{
+		{"", 0},
+		{StagePreprocessing, 0},
+		{StageGenerating, 0},
+		{StageGenerating, 90},
+		{StageJoining, 90},
+		{StageJoining, 95},
+		{StageUploading, 95},
+		{StageUploading, 98},
+		{StageDone, 100},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected stage transitions %v, got %v", want, got)
+	}
+}
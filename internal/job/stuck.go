@@ -0,0 +1,95 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultStuckJobThreshold is how long a RUNNING job may go without an
+// update before it is considered stuck when no threshold is configured.
+const DefaultStuckJobThreshold = 30 * time.Minute
+
+// StuckJobs returns the RUNNING jobs that have not been updated for the
+// configured stuck threshold (see WithStuckJobThreshold), oldest update first.
+// Such jobs are typically left behind by a worker that crashed.
+func (s *ProcessVideoService) StuckJobs(ctx context.Context) ([]*Job, error) {
+	jobs, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list jobs: %w", err)
+	}
+
+	now := s.now()
+	stuck := jobs[:0]
+	for _, job := range jobs {
+		if job.IsStuck(now, s.stuckThreshold) {
+			stuck = append(stuck, job)
+		}
+	}
+	slices.SortFunc(stuck, func(a, b *Job) int {
+		if c := a.UpdatedAt.Compare(b.UpdatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return stuck, nil
+}
+
+// TimeoutStuckJob marks a stuck job as TIMED_OUT, stops its processing if it
+// is still running in this process, cancels its submitted chunks with the
+// provider and removes its partial artifacts. The inputs are kept so that the
+// job can be retried with RetryJob.
+// Returns ErrJobNotFound if the job does not exist and ErrJobNotStuck if it
+// is not RUNNING or was updated within the stuck threshold.
+func (s *ProcessVideoService) TimeoutStuckJob(ctx context.Context, jobID string) (*Job, error) {
+	// Prefer the live job so the processing goroutine observes the new status
+	var job *Job
+	active := s.lookupActive(jobID)
+	if active != nil {
+		job = active.job
+	} else {
+		found, err := s.repo.FindByID(ctx, jobID)
+		if err != nil {
+			return nil, fmt.Errorf("find job: %w", err)
+		}
+		job = found
+	}
+
+	lastUpdate := job.Clone().UpdatedAt
+	if !job.IsStuck(s.now(), s.stuckThreshold) {
+		return nil, fmt.Errorf("%w: status %s, last updated %s", ErrJobNotStuck, job.GetStatus(), lastUpdate.Format(time.RFC3339))
+	}
+	if err := job.Timeout(); err != nil {
+		return nil, fmt.Errorf("%w: status %s", ErrJobNotStuck, job.GetStatus())
+	}
+
+	// Snapshot submitted chunks before stopping processing marks them failed
+	pending := job.MarkPendingChunkCancels()
+
+	if active != nil {
+		active.cancel()
+	}
+
+	if err := s.repo.Save(ctx, job); err != nil {
+		return nil, fmt.Errorf("save job: %w", err)
+	}
+
+	ctx = withJobLog(ctx, job.Logs)
+	s.log(ctx).Warn("stuck job timed out",
+		slog.String("job_id", job.ID),
+		slog.Time("last_update", lastUpdate),
+		slog.Bool("was_processing", active != nil),
+		slog.Int("provider_cancels", len(pending)),
+	)
+
+	if len(pending) > 0 {
+		go s.cancelProviderJobs(context.WithoutCancel(ctx), job, pending)
+	}
+
+	s.removePartialArtifacts(context.WithoutCancel(ctx), job)
+
+	return job.Clone(), nil
+}
@@ -0,0 +1,141 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// saveJobUpdatedAt saves a job with the given status, last updated at updatedAt.
+func saveJobUpdatedAt(t *testing.T, repo Repository, status Status, updatedAt time.Time) *Job {
+	t.Helper()
+	job := New()
+	job.Provider = ProviderRunPod
+	job.Status = status
+	job.UpdatedAt = updatedAt
+	if err := repo.Save(context.Background(), job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+	return job
+}
+
+func TestProcessVideoService_StuckJobs(t *testing.T) {
+	svc, _, _, _, _, repo := newTestService(t)
+	WithStuckJobThreshold(30 * time.Minute)(svc)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	older := saveJobUpdatedAt(t, repo, StatusRunning, now.Add(-2*time.Hour))
+	old := saveJobUpdatedAt(t, repo, StatusRunning, now.Add(-30*time.Minute))
+	saveJobUpdatedAt(t, repo, StatusRunning, now.Add(-29*time.Minute))
+	saveJobUpdatedAt(t, repo, StatusInQueue, now.Add(-2*time.Hour))
+	saveJobUpdatedAt(t, repo, StatusFailed, now.Add(-2*time.Hour))
+
+	stuck, err := svc.StuckJobs(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []string
+	for _, job := range stuck {
+		ids = append(ids, job.ID)
+	}
+	if want := []string{older.ID, old.ID}; !slices.Equal(ids, want) {
+		t.Errorf("expected stuck jobs %v (oldest update first), got %v", want, ids)
+	}
+
+	// Time passes: the recently updated job becomes stuck too
+	now = now.Add(time.Minute)
+	stuck, _ = svc.StuckJobs(context.Background())
+	if len(stuck) != 3 {
+		t.Errorf("expected 3 stuck jobs a minute later, got %d", len(stuck))
+	}
+}
+
+func TestProcessVideoService_TimeoutStuckJob(t *testing.T) {
+	svc, _, _, runpodClient, storageClient, repo := newTestService(t)
+	now := time.Now().Add(time.Hour)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	job := New()
+	job.Provider = ProviderRunPod
+	job.Status = StatusRunning
+	job.InputImagePath = "/tmp/image.png"
+	job.AddTempFiles("/tmp/resized.png")
+	job.SetChunks([]Chunk{
+		{ID: "chunk-0", Index: 0, Status: ChunkStatusProcessing, InputPath: "/tmp/chunk_0.wav", RunPodJobID: "runpod-job-0"},
+	})
+	job.UpdatedAt = now.Add(-DefaultStuckJobThreshold)
+	if err := repo.Save(ctx, job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+
+	cancelled := make(chan struct{})
+	runpodClient.On("Cancel", mock.Anything, "runpod-job-0").
+		Run(func(args mock.Arguments) { close(cancelled) }).
+		Return(nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, mock.MatchedBy(func(paths []string) bool {
+		return slices.Equal(paths, []string{"/tmp/resized.png", "/tmp/chunk_0.wav"})
+	})).Return(nil).Once()
+
+	result, err := svc.TimeoutStuckJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusTimedOut {
+		t.Errorf("expected status TIMED_OUT, got %s", result.Status)
+	}
+	if !result.IsRetryable() {
+		t.Error("expected the timed out job to be retryable")
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the submitted chunk to be cancelled with the provider")
+	}
+	stored, _ := repo.FindByID(ctx, job.ID)
+	if stored.Status != StatusTimedOut {
+		t.Errorf("expected stored status TIMED_OUT, got %s", stored.Status)
+	}
+	storageClient.AssertExpectations(t)
+}
+
+func TestProcessVideoService_TimeoutStuckJob_NotStuck(t *testing.T) {
+	svc, _, _, _, _, repo := newTestService(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	tests := []struct {
+		name      string
+		status    Status
+		updatedAt time.Time
+	}{
+		{"recently updated", StatusRunning, now.Add(-time.Minute)},
+		{"queued", StatusInQueue, now.Add(-time.Hour)},
+		{"completed", StatusCompleted, now.Add(-time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := saveJobUpdatedAt(t, repo, tt.status, tt.updatedAt)
+
+			_, err := svc.TimeoutStuckJob(context.Background(), job.ID)
+			if !errors.Is(err, ErrJobNotStuck) {
+				t.Fatalf("expected ErrJobNotStuck, got %v", err)
+			}
+			stored, _ := repo.FindByID(context.Background(), job.ID)
+			if stored.Status != tt.status {
+				t.Errorf("expected status %s to be kept, got %s", tt.status, stored.Status)
+			}
+		})
+	}
+
+	if _, err := svc.TimeoutStuckJob(context.Background(), "missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/maauso/infinitetalk-api/internal/audio"
+)
+
+// Errors returned by ValidateInputs.
+var (
+	// ErrInvalidImage is returned when the image input cannot be probed as an image.
+	ErrInvalidImage = errors.New("image is not a valid image")
+	// ErrInvalidAudio is returned when the audio input cannot be probed as audio.
+	ErrInvalidAudio = errors.New("audio is not valid audio")
+	// ErrInvalidVideo is returned when the video input cannot be probed as video.
+	ErrInvalidVideo = errors.New("video is not a valid video")
+	// ErrAudioTooLong is returned when the audio input is longer than the configured maximum.
+	ErrAudioTooLong = errors.New("audio is longer than the maximum duration")
+	// ErrImageTooLarge is returned when the image input has more pixels than the configured maximum.
+	ErrImageTooLarge = errors.New("image exceeds the maximum pixel count")
+)
+
+// InputProbe describes job inputs as probed by ValidateInputs.
+type InputProbe struct {
+	// ImageWidth is the width of the input image (or video) in pixels.
+	ImageWidth int
+	// ImageHeight is the height of the input image (or video) in pixels.
+	ImageHeight int
+	// ImageCodec is the codec of the input image (e.g. "png", "webp"), or
+	// empty for video inputs.
+	ImageCodec string
+	// AudioDurationSec is the duration of the input audio in seconds.
+	AudioDurationSec float64
+	// EstimatedChunks is how many chunks the audio is expected to be split into.
+	EstimatedChunks int
+}
+
+// ValidateInputs decodes (or downloads) the job inputs and probes them the
+// same way processing does, without creating a job, resizing the image or
+// splitting the audio. It gives clients fast feedback on bad uploads. Returns
+// ErrInvalidImage, ErrInvalidVideo or ErrInvalidAudio when an input is invalid,
+// ErrImageTooLarge when the image exceeds the maximum pixel count and
+// ErrAudioTooLong when the audio exceeds the maximum duration.
+func (s *ProcessVideoService) ValidateInputs(ctx context.Context, input ProcessVideoInput) (*InputProbe, error) {
+	var tempFiles []string
+	defer func() {
+		if len(tempFiles) == 0 {
+			return
+		}
+		if err := s.storage.CleanupTemp(context.WithoutCancel(ctx), tempFiles); err != nil {
+			s.log(ctx).Warn("failed to cleanup validation files",
+				slog.String("error", err.Error()),
+			)
+		}
+	}()
+
+	isVideo := input.InputType == InputTypeVideo
+	var imagePath string
+	var err error
+	if isVideo {
+		imagePath, err = s.saveInputToTemp(ctx, input.VideoBase64, "", "video.mp4")
+	} else {
+		imagePath, err = s.saveInputToTemp(ctx, input.ImageBase64, input.ImageURL, "image.png")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("save source: %w", err)
+	}
+	tempFiles = append(tempFiles, imagePath)
+
+	audioPath, err := s.saveInputToTemp(ctx, input.AudioBase64, input.AudioURL, "audio.wav")
+	if err != nil {
+		return nil, fmt.Errorf("save audio: %w", err)
+	}
+	tempFiles = append(tempFiles, audioPath)
+
+	probe, err := s.probeInputs(ctx, isVideo, imagePath, audioPath)
+	if err != nil {
+		return nil, err
+	}
+	probe.EstimatedChunks = audio.EstimateChunks(probe.AudioDurationSec, s.splitOptsFor(input.PersonCount))
+	return probe, nil
+}
+
+// probeInputs checks that the saved source decodes as an image (or video)
+// with dimensions and the saved audio as audio with a duration and a known
+// codec. Returns ErrInvalidImage, ErrInvalidVideo or ErrInvalidAudio otherwise,
+// ErrImageTooLarge when the image exceeds the maximum pixel count and
+// ErrAudioTooLong when the audio exceeds the maximum duration.
+// EstimatedChunks is left unset.
+func (s *ProcessVideoService) probeInputs(ctx context.Context, isVideo bool, sourcePath, audioPath string) (*InputProbe, error) {
+	probe := &InputProbe{}
+	if isVideo {
+		video, err := s.processor.ProbeVideo(ctx, sourcePath)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidVideo, err)
+		}
+		probe.ImageWidth, probe.ImageHeight = video.Width, video.Height
+	} else {
+		image, err := s.processor.ProbeImage(ctx, sourcePath)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidImage, err)
+		}
+		if image.Width <= 0 || image.Height <= 0 {
+			return nil, fmt.Errorf("%w: image has no dimensions", ErrInvalidImage)
+		}
+		// Decoding huge images can exhaust ffmpeg's memory
+		if pixels := int64(image.Width) * int64(image.Height); s.maxImagePixels > 0 && pixels > s.maxImagePixels {
+			return nil, fmt.Errorf("%w: %dx%d exceeds the limit of %d pixels", ErrImageTooLarge, image.Width, image.Height, s.maxImagePixels)
+		}
+		probe.ImageWidth, probe.ImageHeight = image.Width, image.Height
+		probe.ImageCodec = image.Codec
+	}
+
+	sound, err := s.processor.ProbeAudio(ctx, audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAudio, err)
+	}
+	if sound.DurationSec <= 0 {
+		return nil, fmt.Errorf("%w: audio has no duration", ErrInvalidAudio)
+	}
+	if sound.Codec == "" || sound.Codec == "none" {
+		return nil, fmt.Errorf("%w: unsupported audio codec", ErrInvalidAudio)
+	}
+	if s.maxAudioDuration > 0 && sound.DurationSec > s.maxAudioDuration.Seconds() {
+		return nil, fmt.Errorf("%w: %.1fs exceeds the limit of %s", ErrAudioTooLong, sound.DurationSec, s.maxAudioDuration)
+	}
+	probe.AudioDurationSec = sound.DurationSec
+
+	return probe, nil
+}
@@ -0,0 +1,186 @@
+package job
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/maauso/infinitetalk-api/internal/media"
+)
+
+func validateInput() ProcessVideoInput {
+	return ProcessVideoInput{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+		Width:       384,
+		Height:      576,
+	}
+}
+
+func TestProcessVideoService_ValidateInputs(t *testing.T) {
+	svc, processor, splitter, _, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, []string{"/tmp/image.png", "/tmp/audio.wav"}).Return(nil).Once()
+	processor.On("ProbeImage", mock.Anything, "/tmp/image.png").
+		Return(media.ImageInfo{Width: 1024, Height: 1536, Codec: "png"}, nil)
+	processor.On("ProbeAudio", mock.Anything, "/tmp/audio.wav").
+		Return(media.AudioInfo{DurationSec: 100, SampleRate: 16000, Channels: 1, Codec: "pcm_s16le"}, nil)
+
+	probe, err := svc.ValidateInputs(ctx, validateInput())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if probe.ImageWidth != 1024 || probe.ImageHeight != 1536 {
+		t.Errorf("expected 1024x1536, got %dx%d", probe.ImageWidth, probe.ImageHeight)
+	}
+	if probe.AudioDurationSec != 100 {
+		t.Errorf("expected duration 100, got %f", probe.AudioDurationSec)
+	}
+	if probe.EstimatedChunks != 3 {
+		t.Errorf("expected 3 estimated chunks, got %d", probe.EstimatedChunks)
+	}
+
+	jobs, _ := repo.List(ctx)
+	if len(jobs) != 0 {
+		t.Errorf("expected no job to be created, got %d", len(jobs))
+	}
+
+	storageClient.AssertExpectations(t)
+	processor.AssertExpectations(t)
+	splitter.AssertNotCalled(t, "Split", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_ValidateInputs_InvalidImage(t *testing.T) {
+	svc, processor, _, _, storageClient, _ := newTestService(t)
+	ctx := context.Background()
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil).Once()
+	processor.On("ProbeImage", mock.Anything, "/tmp/image.png").
+		Return(media.ImageInfo{}, media.ErrNoVideoStream)
+
+	_, err := svc.ValidateInputs(ctx, validateInput())
+	if !errors.Is(err, ErrInvalidImage) {
+		t.Errorf("expected ErrInvalidImage, got %v", err)
+	}
+
+	storageClient.AssertExpectations(t)
+	processor.AssertNotCalled(t, "ProbeAudio", mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_ValidateInputs_ImageTooLarge(t *testing.T) {
+	svc, processor, _, _, storageClient, _ := newTestService(t)
+	WithMaxImagePixels(50_000_000)(svc)
+	ctx := context.Background()
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil).Once()
+	processor.On("ProbeImage", mock.Anything, "/tmp/image.png").
+		Return(media.ImageInfo{Width: 10000, Height: 8000, Codec: "png"}, nil)
+
+	_, err := svc.ValidateInputs(ctx, validateInput())
+	if !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("expected ErrImageTooLarge, got %v", err)
+	}
+
+	storageClient.AssertExpectations(t)
+	processor.AssertNotCalled(t, "ProbeAudio", mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_ValidateInputs_InvalidAudio(t *testing.T) {
+	tests := []struct {
+		name  string
+		info  media.AudioInfo
+		err   error
+		cause error
+	}{
+		{"no audio stream", media.AudioInfo{}, media.ErrNoAudioStream, media.ErrNoAudioStream},
+		{"zero duration", media.AudioInfo{Channels: 1}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, processor, _, _, storageClient, _ := newTestService(t)
+			ctx := context.Background()
+
+			storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+			storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+			storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil).Once()
+			processor.On("ProbeImage", mock.Anything, "/tmp/image.png").
+				Return(media.ImageInfo{Width: 64, Height: 64}, nil)
+			processor.On("ProbeAudio", mock.Anything, "/tmp/audio.wav").Return(tt.info, tt.err)
+
+			_, err := svc.ValidateInputs(ctx, validateInput())
+			if !errors.Is(err, ErrInvalidAudio) {
+				t.Errorf("expected ErrInvalidAudio, got %v", err)
+			}
+			if tt.cause != nil && !errors.Is(err, tt.cause) {
+				t.Errorf("expected error to wrap %v, got %v", tt.cause, err)
+			}
+
+			storageClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProcessVideoService_ValidateInputs_SaveFails(t *testing.T) {
+	svc, processor, _, _, storageClient, _ := newTestService(t)
+	ctx := context.Background()
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).
+		Return("", errors.New("storage error")).Once()
+	// CleanupTemp should NOT be called because no temp files were created
+
+	_, err := svc.ValidateInputs(ctx, validateInput())
+	if err == nil || errors.Is(err, ErrInvalidImage) {
+		t.Errorf("expected a storage error, got %v", err)
+	}
+
+	storageClient.AssertExpectations(t)
+	processor.AssertNotCalled(t, "ProbeImage", mock.Anything, mock.Anything)
+}
+
+func TestProcessVideoService_ValidateInputs_Video(t *testing.T) {
+	svc, processor, _, _, storageClient, _ := newTestService(t)
+	ctx := context.Background()
+
+	input := validateInput()
+	input.ImageBase64 = ""
+	input.VideoBase64 = base64.StdEncoding.EncodeToString([]byte("test-video"))
+	input.InputType = InputTypeVideo
+
+	storageClient.On("SaveTemp", mock.Anything, "video.mp4", mock.Anything).Return("/tmp/video.mp4", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, []string{"/tmp/video.mp4", "/tmp/audio.wav"}).Return(nil).Once()
+	processor.On("ProbeVideo", mock.Anything, "/tmp/video.mp4").
+		Return(media.VideoInfo{Width: 720, Height: 1280}, nil)
+	processor.On("ProbeAudio", mock.Anything, "/tmp/audio.wav").
+		Return(media.AudioInfo{DurationSec: 10, Codec: "aac"}, nil)
+
+	probe, err := svc.ValidateInputs(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if probe.ImageWidth != 720 || probe.ImageHeight != 1280 {
+		t.Errorf("expected 720x1280, got %dx%d", probe.ImageWidth, probe.ImageHeight)
+	}
+	processor.AssertNotCalled(t, "ProbeImage", mock.Anything, mock.Anything)
+
+	processor.On("ProbeVideo", mock.Anything, "/tmp/other.mp4").Return(media.VideoInfo{}, media.ErrNoVideoStream)
+	storageClient.On("SaveTemp", mock.Anything, "video.mp4", mock.Anything).Return("/tmp/other.mp4", nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil).Once()
+
+	if _, err := svc.ValidateInputs(ctx, input); !errors.Is(err, ErrInvalidVideo) {
+		t.Errorf("expected ErrInvalidVideo, got %v", err)
+	}
+}
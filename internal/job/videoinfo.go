@@ -0,0 +1,92 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/maauso/infinitetalk-api/internal/media"
+	"github.com/maauso/infinitetalk-api/internal/storage"
+)
+
+// ErrVideoNotFound is returned by ProbeJobVideo when the job has not
+// completed or its output video no longer exists.
+var ErrVideoNotFound = errors.New("job video not found")
+
+// ProbeJobVideo returns the dimensions, duration, size and codecs of the
+// output video of a completed job. The local video is probed when it still
+// exists; a video pushed to remote storage is downloaded to a temporary file
+// first. Returns ErrJobNotFound if the job does not exist and
+// ErrVideoNotFound if it has not completed or its video is gone.
+func (s *ProcessVideoService) ProbeJobVideo(ctx context.Context, jobID string) (media.VideoInfo, error) {
+	job, err := s.repo.FindByID(ctx, jobID)
+	if err != nil {
+		return media.VideoInfo{}, fmt.Errorf("find job: %w", err)
+	}
+	if job.Status != StatusCompleted {
+		return media.VideoInfo{}, fmt.Errorf("%w: status %s", ErrVideoNotFound, job.Status)
+	}
+
+	if job.OutputVideoPath != "" {
+		stat, err := os.Stat(job.OutputVideoPath)
+		if err == nil {
+			return s.probeVideoFile(ctx, job.OutputVideoPath, stat.Size())
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return media.VideoInfo{}, fmt.Errorf("stat output video: %w", err)
+		}
+	}
+
+	if job.S3Key == "" {
+		return media.VideoInfo{}, fmt.Errorf("%w: video file was deleted", ErrVideoNotFound)
+	}
+	return s.probeRemoteVideo(ctx, job)
+}
+
+// probeRemoteVideo downloads the remote copy of the job's output video to a
+// temporary file and probes it.
+func (s *ProcessVideoService) probeRemoteVideo(ctx context.Context, job *Job) (media.VideoInfo, error) {
+	body, err := s.storage.Download(ctx, job.S3Key)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return media.VideoInfo{}, fmt.Errorf("%w: remote object was deleted", ErrVideoNotFound)
+	}
+	if err != nil {
+		return media.VideoInfo{}, fmt.Errorf("download video: %w", err)
+	}
+	defer func() { _ = body.Close() }()
+
+	path, err := s.storage.SaveTemp(ctx, "info_"+job.ID+filepath.Ext(job.S3Key), body)
+	if err != nil {
+		return media.VideoInfo{}, fmt.Errorf("save video: %w", err)
+	}
+	defer func() {
+		if err := s.storage.CleanupTemp(context.WithoutCancel(ctx), []string{path}); err != nil {
+			s.log(ctx).Warn("failed to cleanup probed video",
+				slog.String("job_id", job.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}()
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return media.VideoInfo{}, fmt.Errorf("stat downloaded video: %w", err)
+	}
+	return s.probeVideoFile(ctx, path, stat.Size())
+}
+
+// probeVideoFile probes the video in path, taking the file size from size
+// when the container does not report it.
+func (s *ProcessVideoService) probeVideoFile(ctx context.Context, path string, size int64) (media.VideoInfo, error) {
+	info, err := s.processor.ProbeVideo(ctx, path)
+	if err != nil {
+		return media.VideoInfo{}, fmt.Errorf("probe video: %w", err)
+	}
+	if info.SizeBytes == 0 {
+		info.SizeBytes = size
+	}
+	return info, nil
+}
@@ -0,0 +1,106 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/maauso/infinitetalk-api/internal/media"
+	"github.com/maauso/infinitetalk-api/internal/storage"
+)
+
+// completedJob saves a COMPLETED job with the given output path and remote key.
+func completedJob(t *testing.T, repo Repository, outputPath, s3Key string) *Job {
+	t.Helper()
+	job := New()
+	_ = job.Start()
+	_ = job.Complete()
+	job.SetOutput(outputPath)
+	if s3Key != "" {
+		job.SetS3Key(s3Key)
+	}
+	if err := repo.Save(context.Background(), job); err != nil {
+		t.Fatalf("failed to save job: %v", err)
+	}
+	return job
+}
+
+func TestProcessVideoService_ProbeJobVideo_Local(t *testing.T) {
+	svc, processor, _, _, _, repo := newTestService(t)
+	videoPath := filepath.Join(t.TempDir(), "output.mp4")
+	if err := os.WriteFile(videoPath, []byte("local video"), 0600); err != nil {
+		t.Fatalf("failed to write video: %v", err)
+	}
+	job := completedJob(t, repo, videoPath, "")
+	processor.On("ProbeVideo", mock.Anything, videoPath).
+		Return(media.VideoInfo{Width: 384, Height: 576, DurationSec: 4, Codec: "h264", AudioCodec: "aac"}, nil).Once()
+
+	info, err := svc.ProbeJobVideo(context.Background(), job.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Width != 384 || info.Height != 576 || info.AudioCodec != "aac" {
+		t.Errorf("unexpected info %+v", info)
+	}
+	if info.SizeBytes != int64(len("local video")) {
+		t.Errorf("expected the file size when the container reports none, got %d", info.SizeBytes)
+	}
+	processor.AssertExpectations(t)
+}
+
+func TestProcessVideoService_ProbeJobVideo_Remote(t *testing.T) {
+	svc, processor, _, _, storageClient, repo := newTestService(t)
+	job := completedJob(t, repo, "/tmp/already-cleaned-up.mp4", "videos/remote.mp4")
+	tempPath := filepath.Join(t.TempDir(), "info.mp4")
+
+	storageClient.On("Download", mock.Anything, "videos/remote.mp4").
+		Return(io.NopCloser(strings.NewReader("remote video")), nil).Once()
+	storageClient.On("SaveTemp", mock.Anything, "info_"+job.ID+".mp4", mock.Anything).
+		Run(func(args mock.Arguments) {
+			data, _ := io.ReadAll(args.Get(2).(io.Reader))
+			_ = os.WriteFile(tempPath, data, 0600)
+		}).
+		Return(tempPath, nil).Once()
+	storageClient.On("CleanupTemp", mock.Anything, []string{tempPath}).Return(nil).Once()
+	processor.On("ProbeVideo", mock.Anything, tempPath).
+		Return(media.VideoInfo{Width: 384, Height: 576, SizeBytes: 4096}, nil).Once()
+
+	info, err := svc.ProbeJobVideo(context.Background(), job.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Width != 384 || info.Height != 576 || info.SizeBytes != 4096 {
+		t.Errorf("unexpected info %+v", info)
+	}
+	storageClient.AssertExpectations(t)
+	processor.AssertExpectations(t)
+}
+
+func TestProcessVideoService_ProbeJobVideo_NotFound(t *testing.T) {
+	svc, _, _, _, storageClient, repo := newTestService(t)
+	ctx := context.Background()
+
+	running := New()
+	_ = running.Start()
+	_ = repo.Save(ctx, running)
+	deleted := completedJob(t, repo, "/tmp/nonexistent_probe_output.mp4", "")
+	remoteDeleted := completedJob(t, repo, "", "videos/gone.mp4")
+	storageClient.On("Download", mock.Anything, "videos/gone.mp4").
+		Return(nil, fmt.Errorf("download from S3: %w", storage.ErrObjectNotFound))
+
+	for name, id := range map[string]string{"running": running.ID, "deleted": deleted.ID, "remote deleted": remoteDeleted.ID} {
+		if _, err := svc.ProbeJobVideo(ctx, id); !errors.Is(err, ErrVideoNotFound) {
+			t.Errorf("%s: expected ErrVideoNotFound, got %v", name, err)
+		}
+	}
+	if _, err := svc.ProbeJobVideo(ctx, "nonexistent"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package media
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ConcatStrategy selects how JoinVideos concatenates its inputs.
+type ConcatStrategy string
+
+const (
+	// ConcatDemuxer joins with the concat demuxer, copying the streams and
+	// re-encoding through the demuxer when the copy fails (default). It
+	// needs inputs with identical codec parameters.
+	ConcatDemuxer ConcatStrategy = "demuxer"
+	// ConcatFilter always re-encodes through the concat filter, scaling every
+	// input to the size and frame rate of the first one and resampling the
+	// audio, so inputs with differing parameters join cleanly.
+	ConcatFilter ConcatStrategy = "filter"
+	// ConcatAuto probes the inputs and uses ConcatDemuxer when their codec
+	// parameters match and ConcatFilter otherwise.
+	ConcatAuto ConcatStrategy = "auto"
+)
+
+// DefaultConcatStrategy is the strategy used when none is configured.
+const DefaultConcatStrategy = ConcatDemuxer
+
+// concatSampleRate and concatChannelLayout are the audio format every input
+// is resampled to by the concat filter.
+const (
+	concatSampleRate    = 48000
+	concatChannelLayout = "stereo"
+)
+
+// IsValid returns true if the strategy is supported.
+func (s ConcatStrategy) IsValid() bool {
+	return s == ConcatDemuxer || s == ConcatFilter || s == ConcatAuto
+}
+
+// sameStreamParams reports whether all videos share the codecs, size and
+// frame rate the concat demuxer needs to join them.
+func sameStreamParams(infos []VideoInfo) bool {
+	first := infos[0]
+	for _, info := range infos[1:] {
+		if info.Codec != first.Codec || info.AudioCodec != first.AudioCodec ||
+			info.Width != first.Width || info.Height != first.Height ||
+			math.Abs(info.FPS-first.FPS) > 0.01 {
+			return false
+		}
+	}
+	return true
+}
+
+// concatFilterGraph builds the filter_complex joining the videos described
+// by infos into the [v] and, when any input has audio, [a] outputs. Every
+// video is fitted into the size of the first one with padding, and inputs
+// without audio contribute silence of their duration.
+func concatFilterGraph(infos []VideoInfo) (graph string, hasAudio bool) {
+	for _, info := range infos {
+		if info.AudioCodec != "" {
+			hasAudio = true
+			break
+		}
+	}
+
+	w, h := infos[0].Width, infos[0].Height
+	videoFilter := fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2,setsar=1", w, h, w, h)
+	if fps := infos[0].FPS; fps > 0 {
+		videoFilter += ",fps=" + strconv.FormatFloat(fps, 'f', -1, 64)
+	}
+
+	var chains []string
+	var segments strings.Builder
+	for i, info := range infos {
+		chains = append(chains, fmt.Sprintf("[%d:v]%s[v%d]", i, videoFilter, i))
+		fmt.Fprintf(&segments, "[v%d]", i)
+		if !hasAudio {
+			continue
+		}
+		if info.AudioCodec != "" {
+			chains = append(chains, fmt.Sprintf("[%d:a]aformat=sample_rates=%d:channel_layouts=%s[a%d]", i, concatSampleRate, concatChannelLayout, i))
+		} else {
+			chains = append(chains, fmt.Sprintf("anullsrc=r=%d:cl=%s,atrim=duration=%s[a%d]", concatSampleRate, concatChannelLayout, strconv.FormatFloat(info.DurationSec, 'f', -1, 64), i))
+		}
+		fmt.Fprintf(&segments, "[a%d]", i)
+	}
+
+	audioOut := ""
+	a := 0
+	if hasAudio {
+		audioOut = "[a]"
+		a = 1
+	}
+	chains = append(chains, fmt.Sprintf("%sconcat=n=%d:v=1:a=%d[v]%s", segments.String(), len(infos), a, audioOut))
+	return strings.Join(chains, ";"), hasAudio
+}
@@ -0,0 +1,186 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetConcatStrategy(t *testing.T) {
+	p := NewFFmpegProcessor("")
+	if p.concat != ConcatDemuxer {
+		t.Errorf("expected default strategy %q, got %q", ConcatDemuxer, p.concat)
+	}
+
+	for _, s := range []ConcatStrategy{ConcatFilter, ConcatAuto, ConcatDemuxer} {
+		if err := p.SetConcatStrategy(s); err != nil {
+			t.Fatalf("%s: unexpected error: %v", s, err)
+		}
+		if p.concat != s {
+			t.Errorf("expected strategy %q, got %q", s, p.concat)
+		}
+	}
+
+	if err := p.SetConcatStrategy("copy"); !errors.Is(err, ErrInvalidConcatStrategy) {
+		t.Errorf("expected ErrInvalidConcatStrategy, got %v", err)
+	}
+
+	_ = p.SetConcatStrategy(ConcatFilter)
+	if err := p.SetConcatStrategy(""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.concat != DefaultConcatStrategy {
+		t.Errorf("expected empty strategy to restore %q, got %q", DefaultConcatStrategy, p.concat)
+	}
+}
+
+func TestSameStreamParams(t *testing.T) {
+	base := VideoInfo{Width: 384, Height: 576, FPS: 25, Codec: "h264", AudioCodec: "aac"}
+
+	tests := []struct {
+		name  string
+		other VideoInfo
+		same  bool
+	}{
+		{"identical", base, true},
+		{"different duration only", VideoInfo{Width: 384, Height: 576, FPS: 25, Codec: "h264", AudioCodec: "aac", DurationSec: 3}, true},
+		{"different size", VideoInfo{Width: 512, Height: 512, FPS: 25, Codec: "h264", AudioCodec: "aac"}, false},
+		{"different frame rate", VideoInfo{Width: 384, Height: 576, FPS: 30, Codec: "h264", AudioCodec: "aac"}, false},
+		{"different codec", VideoInfo{Width: 384, Height: 576, FPS: 25, Codec: "hevc", AudioCodec: "aac"}, false},
+		{"missing audio", VideoInfo{Width: 384, Height: 576, FPS: 25, Codec: "h264"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := sameStreamParams([]VideoInfo{base, tt.other}); got != tt.same {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.same, got)
+		}
+	}
+}
+
+func TestConcatFilterGraph(t *testing.T) {
+	t.Run("fits every input to the first and fills missing audio", func(t *testing.T) {
+		graph, hasAudio := concatFilterGraph([]VideoInfo{
+			{Width: 384, Height: 576, FPS: 25, AudioCodec: "aac"},
+			{Width: 512, Height: 512, FPS: 30, DurationSec: 2.5},
+		})
+
+		want := strings.Join([]string{
+			"[0:v]scale=384:576:force_original_aspect_ratio=decrease,pad=384:576:(ow-iw)/2:(oh-ih)/2,setsar=1,fps=25[v0]",
+			"[0:a]aformat=sample_rates=48000:channel_layouts=stereo[a0]",
+			"[1:v]scale=384:576:force_original_aspect_ratio=decrease,pad=384:576:(ow-iw)/2:(oh-ih)/2,setsar=1,fps=25[v1]",
+			"anullsrc=r=48000:cl=stereo,atrim=duration=2.5[a1]",
+			"[v0][a0][v1][a1]concat=n=2:v=1:a=1[v][a]",
+		}, ";")
+		if graph != want {
+			t.Errorf("unexpected graph:\n got %s\nwant %s", graph, want)
+		}
+		if !hasAudio {
+			t.Error("expected the graph to have audio")
+		}
+	})
+
+	t.Run("video only", func(t *testing.T) {
+		graph, hasAudio := concatFilterGraph([]VideoInfo{{Width: 64, Height: 64}, {Width: 64, Height: 64}})
+		if hasAudio {
+			t.Error("expected no audio")
+		}
+		if !strings.HasSuffix(graph, "[v0][v1]concat=n=2:v=1:a=0[v]") {
+			t.Errorf("unexpected graph %q", graph)
+		}
+		if strings.Contains(graph, "fps=") {
+			t.Errorf("expected no fps filter without a known frame rate, got %q", graph)
+		}
+	})
+}
+
+func TestFilterJoinArgs(t *testing.T) {
+	infos := []VideoInfo{{Width: 64, Height: 64, AudioCodec: "aac"}, {Width: 64, Height: 64, AudioCodec: "aac"}}
+
+	p := NewFFmpegProcessorWithOptions("", EncodeOptions{HWAccel: "nvenc"})
+	args := p.filterJoinArgs([]string{"a.mp4", "b.mp4"}, infos, "out.mp4")
+	joined := strings.Join(args, " ")
+
+	if n := strings.Count(joined, "-hwaccel cuda -i"); n != 2 {
+		t.Errorf("expected -hwaccel before both inputs, got %d in %q", n, joined)
+	}
+	for _, want := range []string{"-filter_complex", "-map [v] -map [a]", "-c:v h264_nvenc", "-c:a aac"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected args to contain %q, got %q", want, joined)
+		}
+	}
+	if args[len(args)-1] != "out.mp4" {
+		t.Errorf("expected output as last arg, got %q", args[len(args)-1])
+	}
+
+	args = p.filterJoinArgs([]string{"a.mp4", "b.mp4"}, infos, "out.webm")
+	joined = strings.Join(args, " ")
+	for _, notWant := range []string{"-hwaccel", "h264_nvenc"} {
+		if strings.Contains(joined, notWant) {
+			t.Errorf("expected WebM args not to contain %q, got %q", notWant, joined)
+		}
+	}
+}
+
+// createDifferingTestVideo creates a test video whose size, frame rate and
+// audio format differ from the clips of createTestVideo.
+func createDifferingTestVideo(t *testing.T, path string, duration float64) {
+	t.Helper()
+
+	cmd := exec.Command("ffmpeg",
+		"-y",
+		"-f", "lavfi",
+		"-i", fmt.Sprintf("color=c=yellow:s=96x48:r=15:d=%.1f", duration),
+		"-f", "lavfi",
+		"-i", fmt.Sprintf("anullsrc=r=48000:cl=stereo:d=%.1f", duration),
+		"-c:v", "libx264",
+		"-preset", "ultrafast",
+		"-c:a", "aac",
+		"-shortest",
+		path,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to create test video: %v\noutput: %s", err, output)
+	}
+}
+
+func TestJoinVideos_ConcatStrategies(t *testing.T) {
+	skipIfNoFFmpeg(t)
+
+	tmpDir := t.TempDir()
+	video1 := filepath.Join(tmpDir, "video1.mp4")
+	video2 := filepath.Join(tmpDir, "video2.mp4")
+	createTestVideo(t, video1, 0.5, "red")
+	createDifferingTestVideo(t, video2, 1.0)
+
+	for _, s := range []ConcatStrategy{ConcatDemuxer, ConcatFilter, ConcatAuto} {
+		t.Run(string(s), func(t *testing.T) {
+			p := NewFFmpegProcessor("")
+			if err := p.SetConcatStrategy(s); err != nil {
+				t.Fatalf("SetConcatStrategy failed: %v", err)
+			}
+
+			output := filepath.Join(tmpDir, "joined_"+string(s)+".mp4")
+			if err := p.JoinVideos(context.Background(), []string{video1, video2}, output); err != nil {
+				t.Fatalf("JoinVideos failed: %v", err)
+			}
+
+			info, err := p.ProbeVideo(context.Background(), output)
+			if err != nil {
+				t.Fatalf("joined video is not valid: %v", err)
+			}
+			if info.DurationSec < 1.3 || info.DurationSec > 1.7 {
+				t.Errorf("expected joined video duration ~1.5s, got %.2f", info.DurationSec)
+			}
+			if info.AudioCodec == "" {
+				t.Error("expected the joined video to keep its audio")
+			}
+			if s != ConcatDemuxer && (info.Width != 64 || info.Height != 64) {
+				t.Errorf("expected the size of the first clip (64x64), got %dx%d", info.Width, info.Height)
+			}
+		})
+	}
+}
@@ -3,11 +3,14 @@ package media
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -17,39 +20,215 @@ var (
 	ErrInvalidDimensions = errors.New("invalid dimensions: width and height must be positive")
 	// ErrNoVideoPaths is returned when no video paths are provided for joining.
 	ErrNoVideoPaths = errors.New("no video paths provided")
+	// ErrUnsupportedHWAccel is returned when the requested hardware acceleration is not known.
+	ErrUnsupportedHWAccel = errors.New("unsupported hardware acceleration")
+	// ErrEncoderUnavailable is returned when ffmpeg lacks the requested encoder.
+	ErrEncoderUnavailable = errors.New("encoder not available in ffmpeg build")
+	// ErrNoVideoStream is returned when a probed file has no video stream.
+	ErrNoVideoStream = errors.New("no video stream found")
+	// ErrNoAudioStream is returned when a probed file has no audio stream.
+	ErrNoAudioStream = errors.New("no audio stream found")
+	// ErrInvalidPadColor is returned when a padding color is neither a color name nor #RRGGBB.
+	ErrInvalidPadColor = errors.New("invalid pad color")
+	// ErrInvalidFPS is returned when a target frame rate is not positive.
+	ErrInvalidFPS = errors.New("invalid frame rate: must be positive")
+	// ErrUnsupportedImageFormat is returned when ffmpeg cannot decode an image.
+	ErrUnsupportedImageFormat = errors.New("unsupported image format")
+	// ErrInvalidConcatStrategy is returned when a concat strategy is not "demuxer", "filter" or "auto".
+	ErrInvalidConcatStrategy = errors.New("invalid concat strategy")
 )
 
+// hwEncoder describes how to drive a hardware encoder family.
+type hwEncoder struct {
+	// h264 and hevc are the ffmpeg encoder names for each codec.
+	h264, hevc string
+	// inputArgs are placed before -i (e.g. -hwaccel cuda).
+	inputArgs []string
+	// qualityFlag replaces -crf for this encoder; empty disables the quality setting.
+	qualityFlag string
+	// supportsPreset reports whether -preset is accepted.
+	supportsPreset bool
+}
+
+// hwEncoders maps supported HWAccel values to their encoder settings.
+var hwEncoders = map[string]hwEncoder{
+	"nvenc": {
+		h264: "h264_nvenc", hevc: "hevc_nvenc",
+		inputArgs:   []string{"-hwaccel", "cuda"},
+		qualityFlag: "-cq", supportsPreset: true,
+	},
+	"qsv": {
+		h264: "h264_qsv", hevc: "hevc_qsv",
+		inputArgs:   []string{"-hwaccel", "qsv"},
+		qualityFlag: "-global_quality", supportsPreset: true,
+	},
+	"videotoolbox": {
+		h264: "h264_videotoolbox", hevc: "hevc_videotoolbox",
+		inputArgs: []string{"-hwaccel", "videotoolbox"},
+	},
+}
+
+// IsValidHWAccel returns true if name is empty (software) or a known hardware accelerator.
+func IsValidHWAccel(name string) bool {
+	if name == "" {
+		return true
+	}
+	_, ok := hwEncoders[name]
+	return ok
+}
+
+// EncodeOptions configures the codecs and quality used when re-encoding videos.
+type EncodeOptions struct {
+	// VideoCodec is the ffmpeg video encoder (e.g. "libx264", "libx265").
+	VideoCodec string
+	// Preset is the encoder speed/quality preset (e.g. "fast", "medium").
+	Preset string
+	// CRF is the constant rate factor (lower = better quality, 0 = lossless).
+	// Nil means the default.
+	CRF *int
+	// AudioCodec is the ffmpeg audio encoder (e.g. "aac").
+	AudioCodec string
+	// AudioBitrate is the target audio bitrate (e.g. "128k").
+	AudioBitrate string
+	// HWAccel selects a hardware encoder ("nvenc", "qsv", "videotoolbox").
+	// Empty means software encoding with VideoCodec.
+	HWAccel string
+}
+
+// DefaultCRF is the constant rate factor used when EncodeOptions.CRF is nil.
+const DefaultCRF = 23
+
+// DefaultEncodeOptions returns the default re-encoding options (libx264/aac).
+func DefaultEncodeOptions() EncodeOptions {
+	crf := DefaultCRF
+	return EncodeOptions{
+		VideoCodec:   "libx264",
+		Preset:       "fast",
+		CRF:          &crf,
+		AudioCodec:   "aac",
+		AudioBitrate: "128k",
+	}
+}
+
+// withDefaults fills any zero-valued or nil fields with the default options.
+func (o EncodeOptions) withDefaults() EncodeOptions {
+	d := DefaultEncodeOptions()
+	if o.VideoCodec == "" {
+		o.VideoCodec = d.VideoCodec
+	}
+	if o.Preset == "" {
+		o.Preset = d.Preset
+	}
+	if o.CRF == nil {
+		o.CRF = d.CRF
+	}
+	if o.AudioCodec == "" {
+		o.AudioCodec = d.AudioCodec
+	}
+	if o.AudioBitrate == "" {
+		o.AudioBitrate = d.AudioBitrate
+	}
+	return o
+}
+
+// DefaultPadColor is the background color used by ResizeImageWithPadding.
+const DefaultPadColor = "black"
+
+// padColorPattern accepts ffmpeg color names (letters only) or #RRGGBB hex values,
+// which keeps user-supplied colors from injecting extra filter options.
+var padColorPattern = regexp.MustCompile(`^(?:[A-Za-z]+|#[0-9A-Fa-f]{6})$`)
+
+// ValidatePadColor reports whether color can be safely used as a pad color.
+func ValidatePadColor(color string) error {
+	if !padColorPattern.MatchString(color) {
+		return fmt.Errorf("%w: %q", ErrInvalidPadColor, color)
+	}
+	return nil
+}
+
 // FFmpegProcessor implements Processor using the ffmpeg CLI.
 type FFmpegProcessor struct {
 	// ffmpegPath is the path to the ffmpeg binary. Defaults to "ffmpeg".
 	ffmpegPath string
+	// ffprobePath is the path to the ffprobe binary, resolved next to ffmpegPath.
+	ffprobePath string
+	// encode configures the re-encode fallback used by JoinVideos.
+	encode EncodeOptions
+	// padColor is the background color behind the letterbox bars.
+	padColor string
+	// concat selects how JoinVideos concatenates its inputs.
+	concat ConcatStrategy
 }
 
 // NewFFmpegProcessor creates a new FFmpegProcessor.
 // If ffmpegPath is empty, it defaults to "ffmpeg" (found via PATH).
 func NewFFmpegProcessor(ffmpegPath string) *FFmpegProcessor {
+	return NewFFmpegProcessorWithOptions(ffmpegPath, DefaultEncodeOptions())
+}
+
+// NewFFmpegProcessorWithOptions creates a new FFmpegProcessor with custom
+// re-encoding options. Zero-valued fields in opts fall back to the defaults.
+func NewFFmpegProcessorWithOptions(ffmpegPath string, opts EncodeOptions) *FFmpegProcessor {
 	if ffmpegPath == "" {
 		ffmpegPath = "ffmpeg"
 	}
-	return &FFmpegProcessor{ffmpegPath: ffmpegPath}
+	return &FFmpegProcessor{
+		ffmpegPath:  ffmpegPath,
+		ffprobePath: probePathFor(ffmpegPath),
+		encode:      opts.withDefaults(),
+		padColor:    DefaultPadColor,
+		concat:      DefaultConcatStrategy,
+	}
+}
+
+// SetPadColor sets the background color used by ResizeImageWithPadding.
+// The color must be an ffmpeg color name (e.g. "white") or a #RRGGBB value.
+// An empty color restores DefaultPadColor.
+func (p *FFmpegProcessor) SetPadColor(color string) error {
+	if color == "" {
+		color = DefaultPadColor
+	}
+	if err := ValidatePadColor(color); err != nil {
+		return err
+	}
+	p.padColor = color
+	return nil
+}
+
+// SetConcatStrategy sets how JoinVideos concatenates its inputs.
+// An empty strategy restores DefaultConcatStrategy.
+func (p *FFmpegProcessor) SetConcatStrategy(s ConcatStrategy) error {
+	if s == "" {
+		s = DefaultConcatStrategy
+	}
+	if !s.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidConcatStrategy, s)
+	}
+	p.concat = s
+	return nil
+}
+
+// probePathFor returns the ffprobe binary that ships alongside ffmpegPath.
+// Custom binary names fall back to "ffprobe" in PATH.
+func probePathFor(ffmpegPath string) string {
+	if filepath.Base(ffmpegPath) != "ffmpeg" {
+		return "ffprobe"
+	}
+	return filepath.Join(filepath.Dir(ffmpegPath), "ffprobe")
 }
 
 // ResizeImageWithPadding resizes an image to the specified dimensions while
-// maintaining aspect ratio. Black padding is added to fill any remaining space.
+// maintaining aspect ratio. Padding in the configured pad color (black by
+// default) is added to fill any remaining space.
 func (p *FFmpegProcessor) ResizeImageWithPadding(ctx context.Context, src, dst string, w, h int) error {
 	if w <= 0 || h <= 0 {
 		return fmt.Errorf("%w: width=%d, height=%d", ErrInvalidDimensions, w, h)
 	}
 
-	// FFmpeg filter to scale with aspect ratio preservation and add black padding
-	// scale: scales to fit within w x h while maintaining aspect ratio
-	// pad: adds black padding to center the image and reach exact dimensions
-	filter := fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2:black", w, h, w, h)
-
 	args := []string{
 		"-y",      // Overwrite output file without asking
 		"-i", src, // Input file
-		"-vf", filter, // Video filter
+		"-vf", p.resizeFilter(ResizeModePad, w, h), // Video filter
 		"-frames:v", "1", // Output single frame (image)
 		dst, // Output file
 	}
@@ -57,19 +236,162 @@ func (p *FFmpegProcessor) ResizeImageWithPadding(ctx context.Context, src, dst s
 	return p.runFFmpeg(ctx, args)
 }
 
-// JoinVideos concatenates multiple video files into a single output file.
-// It first attempts a fast copy (no re-encoding) and falls back to re-encoding
-// with libx264/aac if the copy fails.
+// ResizeImageCropToFill scales an image to cover the specified dimensions while
+// maintaining aspect ratio and center-crops whatever exceeds them.
+func (p *FFmpegProcessor) ResizeImageCropToFill(ctx context.Context, src, dst string, w, h int) error {
+	if w <= 0 || h <= 0 {
+		return fmt.Errorf("%w: width=%d, height=%d", ErrInvalidDimensions, w, h)
+	}
+
+	args := []string{
+		"-y",
+		"-i", src,
+		"-vf", p.resizeFilter(ResizeModeCrop, w, h),
+		"-frames:v", "1",
+		dst,
+	}
+
+	return p.runFFmpeg(ctx, args)
+}
+
+// ResizeImageToPNG resizes an image like ResizeImageWithPadding (or
+// ResizeImageCropToFill for ResizeModeCrop) and returns the PNG bytes piped
+// from ffmpeg's stdout, avoiding a write and re-read of the resized file.
+func (p *FFmpegProcessor) ResizeImageToPNG(ctx context.Context, src string, w, h int, mode ResizeMode) ([]byte, error) {
+	if w <= 0 || h <= 0 {
+		return nil, fmt.Errorf("%w: width=%d, height=%d", ErrInvalidDimensions, w, h)
+	}
+
+	args := []string{
+		"-i", src,
+		"-vf", p.resizeFilter(mode, w, h),
+		"-frames:v", "1",
+		"-f", "image2pipe", // Write the frame to stdout
+		"-c:v", "png",
+		"-",
+	}
+
+	return p.runFFmpegOutput(ctx, args)
+}
+
+// NormalizeImage decodes the first frame of src and writes it to dst as PNG.
+// Animated images keep only their first frame. Decoding failures are reported
+// as ErrUnsupportedImageFormat.
+func (p *FFmpegProcessor) NormalizeImage(ctx context.Context, src, dst string) error {
+	args := []string{
+		"-y",
+		"-i", src,
+		"-frames:v", "1",
+		"-f", "image2",
+		"-c:v", "png",
+		dst,
+	}
+
+	err := p.runFFmpeg(ctx, args)
+	// ffmpeg ran but could not decode src
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("%w: %w", ErrUnsupportedImageFormat, err)
+	}
+	return err
+}
+
+// resizeFilter returns the ffmpeg filter that fits an image into w x h.
+// Any mode other than ResizeModeCrop pads.
+func (p *FFmpegProcessor) resizeFilter(mode ResizeMode, w, h int) string {
+	if mode == ResizeModeCrop {
+		// scale: scales to cover w x h while maintaining aspect ratio
+		// crop: trims the centered overflow to reach exact dimensions
+		return fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=increase,crop=%d:%d", w, h, w, h)
+	}
+	// scale: scales to fit within w x h while maintaining aspect ratio
+	// pad: adds padding to center the image and reach exact dimensions
+	return fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2:%s", w, h, w, h, p.padColor)
+}
+
+// ResizeVideo re-encodes src to exactly w x h, fitting the frames with mode
+// like the image resizes do (padding by default), and writes it to dst using
+// the configured software codecs. The audio stream is copied.
+func (p *FFmpegProcessor) ResizeVideo(ctx context.Context, src, dst string, w, h int, mode ResizeMode) error {
+	if w <= 0 || h <= 0 {
+		return fmt.Errorf("%w: width=%d, height=%d", ErrInvalidDimensions, w, h)
+	}
+
+	args := []string{
+		"-y",
+		"-i", src,
+		"-vf", p.resizeFilter(mode, w, h) + ",setsar=1", // Square pixels so players keep w x h
+		"-c:v", p.encode.VideoCodec,
+		"-preset", p.encode.Preset,
+		"-crf", strconv.Itoa(*p.encode.CRF),
+		"-c:a", "copy",
+		dst,
+	}
+
+	return p.runFFmpeg(ctx, args)
+}
+
+// NormalizeFPS re-encodes src to a constant fps frame rate and writes it to
+// dst, using the configured software codecs. Chunks normalized to the same
+// rate share their stream parameters, so JoinVideos can join them with a
+// stream copy instead of falling back to a re-encode.
+func (p *FFmpegProcessor) NormalizeFPS(ctx context.Context, src, dst string, fps float64) error {
+	if fps <= 0 {
+		return fmt.Errorf("%w: %g", ErrInvalidFPS, fps)
+	}
+
+	args := []string{
+		"-y",
+		"-i", src,
+		"-r", strconv.FormatFloat(fps, 'f', -1, 64), // Constant output frame rate
+		"-c:v", p.encode.VideoCodec,
+		"-preset", p.encode.Preset,
+		"-crf", strconv.Itoa(*p.encode.CRF),
+		"-c:a", p.encode.AudioCodec,
+		"-b:a", p.encode.AudioBitrate,
+		dst,
+	}
+
+	return p.runFFmpeg(ctx, args)
+}
+
+// JoinVideos concatenates multiple video files into a single output file
+// using the configured ConcatStrategy. With the concat demuxer (default) it
+// first attempts a fast copy (no re-encoding) and falls back to re-encoding
+// with the configured EncodeOptions (libx264/aac by default) if the copy fails.
+// The concat filter always re-encodes with those options.
+// The container follows the extension of output (see OutputFormat); WebM
+// outputs are re-encoded to VP9/Opus.
 func (p *FFmpegProcessor) JoinVideos(ctx context.Context, videoPaths []string, output string) error {
 	if len(videoPaths) == 0 {
 		return ErrNoVideoPaths
 	}
 
-	if len(videoPaths) == 1 {
-		// Single video: just copy the file
+	if len(videoPaths) == 1 && OutputFormatFromPath(videoPaths[0]) == OutputFormatFromPath(output) {
+		// Single video already in the right container: just copy the file
 		return p.copyFile(videoPaths[0], output)
 	}
 
+	if p.concat == ConcatFilter || p.concat == ConcatAuto {
+		infos := make([]VideoInfo, len(videoPaths))
+		for i, path := range videoPaths {
+			info, err := p.ProbeVideo(ctx, path)
+			if err != nil {
+				return fmt.Errorf("probe %s: %w", path, err)
+			}
+			infos[i] = info
+		}
+		if p.concat == ConcatFilter || !sameStreamParams(infos) {
+			return p.runFFmpeg(ctx, p.filterJoinArgs(videoPaths, infos, output))
+		}
+	}
+
+	return p.joinWithDemuxer(ctx, videoPaths, output)
+}
+
+// joinWithDemuxer concatenates videos with the concat demuxer, copying the
+// streams when possible and re-encoding otherwise.
+func (p *FFmpegProcessor) joinWithDemuxer(ctx context.Context, videoPaths []string, output string) error {
 	// Create a temporary file list for the concat demuxer
 	listFile, err := p.createConcatList(videoPaths)
 	if err != nil {
@@ -100,21 +422,156 @@ func (p *FFmpegProcessor) joinWithCopy(ctx context.Context, listFile, output str
 	return p.runFFmpeg(ctx, args)
 }
 
-// joinWithReencode concatenates videos by re-encoding with libx264/aac.
+// joinWithReencode concatenates videos by re-encoding with the configured codecs.
 func (p *FFmpegProcessor) joinWithReencode(ctx context.Context, listFile, output string) error {
-	args := []string{
-		"-y",           // Overwrite output file
+	return p.runFFmpeg(ctx, p.reencodeArgs(listFile, output))
+}
+
+// reencodeArgs builds the ffmpeg arguments for the re-encode join.
+// When a known HWAccel is configured, the matching hardware encoder replaces
+// the software codec and its -hwaccel flags are added before the input.
+// WebM outputs always use VP9/Opus, since WebM accepts no other common codecs.
+func (p *FFmpegProcessor) reencodeArgs(listFile, output string) []string {
+	args := []string{"-y"} // Overwrite output file
+	args = append(args, p.hwInputArgs(output)...)
+	args = append(args,
 		"-f", "concat", // Use concat demuxer
 		"-safe", "0", // Allow absolute paths
 		"-i", listFile, // Input file list
-		"-c:v", "libx264", // Video codec
-		"-preset", "fast", // Encoding speed preset
-		"-crf", "23", // Quality (lower = better, 23 is default)
-		"-c:a", "aac", // Audio codec
-		"-b:a", "128k", // Audio bitrate
+	)
+	return append(args, p.encodeArgs(output)...)
+}
+
+// filterJoinArgs builds the ffmpeg arguments joining videoPaths, described
+// by infos, through the concat filter with the same encoders as reencodeArgs.
+func (p *FFmpegProcessor) filterJoinArgs(videoPaths []string, infos []VideoInfo, output string) []string {
+	args := []string{"-y"}
+	for _, path := range videoPaths {
+		args = append(args, p.hwInputArgs(output)...)
+		args = append(args, "-i", path)
+	}
+
+	graph, hasAudio := concatFilterGraph(infos)
+	args = append(args, "-filter_complex", graph, "-map", "[v]")
+	if hasAudio {
+		args = append(args, "-map", "[a]")
+	}
+	return append(args, p.encodeArgs(output)...)
+}
+
+// ReplaceAudio writes to output the first video stream of video muxed with
+// the first audio stream of audio, so a joined video carries the original
+// soundtrack instead of the audio of its chunks. The video stream is copied
+// and the audio is encoded with the configured codec (Opus for WebM). The
+// output is not trimmed to the shorter stream, so the full audio track is
+// kept.
+func (p *FFmpegProcessor) ReplaceAudio(ctx context.Context, video, audio, output string) error {
+	return p.runFFmpeg(ctx, p.replaceAudioArgs(video, audio, output))
+}
+
+// replaceAudioArgs builds the ffmpeg arguments for ReplaceAudio.
+func (p *FFmpegProcessor) replaceAudioArgs(video, audio, output string) []string {
+	audioCodec := p.encode.AudioCodec
+	if OutputFormatFromPath(output) == OutputFormatWebM {
+		audioCodec = "libopus"
+	}
+	return []string{
+		"-y",
+		"-i", video,
+		"-i", audio,
+		"-map", "0:v:0", // Video from the joined chunks
+		"-map", "1:a:0", // Audio from the original upload
+		"-c:v", "copy",
+		"-c:a", audioCodec,
+		"-b:a", p.encode.AudioBitrate,
+		output,
+	}
+}
+
+// hwInputArgs returns the flags placed before each input to decode with the
+// configured hardware accelerator, or nil for software encoding and WebM.
+func (p *FFmpegProcessor) hwInputArgs(output string) []string {
+	if OutputFormatFromPath(output) == OutputFormatWebM {
+		return nil
+	}
+	if hw, ok := hwEncoders[p.encode.HWAccel]; ok {
+		return hw.inputArgs
+	}
+	return nil
+}
+
+// encodeArgs returns the codec flags of a re-encode followed by output.
+func (p *FFmpegProcessor) encodeArgs(output string) []string {
+	if OutputFormatFromPath(output) == OutputFormatWebM {
+		return []string{
+			"-c:v", "libvpx-vp9",
+			"-crf", strconv.Itoa(*p.encode.CRF), // Constant quality mode needs -b:v 0
+			"-b:v", "0",
+			"-c:a", "libopus",
+			"-b:a", p.encode.AudioBitrate,
+			output,
+		}
+	}
+
+	hw, useHW := hwEncoders[p.encode.HWAccel]
+
+	var args []string
+	if !useHW {
+		args = append(args,
+			"-c:v", p.encode.VideoCodec, // Video codec
+			"-preset", p.encode.Preset, // Encoding speed preset
+			"-crf", strconv.Itoa(*p.encode.CRF), // Quality (lower = better)
+		)
+	} else {
+		args = append(args, "-c:v", p.hwCodec(hw))
+		if hw.supportsPreset {
+			args = append(args, "-preset", p.encode.Preset)
+		}
+		if hw.qualityFlag != "" {
+			args = append(args, hw.qualityFlag, strconv.Itoa(*p.encode.CRF))
+		}
+	}
+
+	return append(args,
+		"-c:a", p.encode.AudioCodec, // Audio codec
+		"-b:a", p.encode.AudioBitrate, // Audio bitrate
 		output, // Output file
+	)
+}
+
+// hwCodec picks the HEVC hardware encoder when the software codec is H.265,
+// and the H.264 encoder otherwise.
+func (p *FFmpegProcessor) hwCodec(hw hwEncoder) string {
+	codec := strings.ToLower(p.encode.VideoCodec)
+	if strings.Contains(codec, "265") || strings.Contains(codec, "hevc") {
+		return hw.hevc
 	}
-	return p.runFFmpeg(ctx, args)
+	return hw.h264
+}
+
+// CheckHWAccel verifies that the configured hardware accelerator is known and
+// that the local ffmpeg build provides its encoder. It returns nil when no
+// hardware acceleration is configured.
+func (p *FFmpegProcessor) CheckHWAccel(ctx context.Context) error {
+	if p.encode.HWAccel == "" {
+		return nil
+	}
+	hw, ok := hwEncoders[p.encode.HWAccel]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedHWAccel, p.encode.HWAccel)
+	}
+
+	// #nosec G204 - ffmpegPath is set by the application, not user input
+	out, err := exec.CommandContext(ctx, p.ffmpegPath, "-hide_banner", "-encoders").Output()
+	if err != nil {
+		return fmt.Errorf("list ffmpeg encoders: %w", err)
+	}
+
+	codec := p.hwCodec(hw)
+	if !strings.Contains(string(out), " "+codec+" ") {
+		return fmt.Errorf("%w: %s", ErrEncoderUnavailable, codec)
+	}
+	return nil
 }
 
 // createConcatList creates a temporary file containing the list of video files
@@ -154,29 +611,285 @@ func (p *FFmpegProcessor) copyFile(src, dst string) error {
 	return nil
 }
 
+// ExtractFirstFrame returns the first frame of videoPath as PNG bytes.
+func (p *FFmpegProcessor) ExtractFirstFrame(ctx context.Context, videoPath string) ([]byte, error) {
+	return p.extractFrame(ctx, videoPath, "first",
+		[]string{"-ss", "0"},
+		[]string{"-frames:v", "1"},
+	)
+}
+
+// ExtractLastFrame returns the last frame of videoPath as PNG bytes.
+// It seeks close to the end of the input and keeps overwriting the output
+// image so the final decoded frame is the one that remains.
+func (p *FFmpegProcessor) ExtractLastFrame(ctx context.Context, videoPath string) ([]byte, error) {
+	return p.extractFrame(ctx, videoPath, "last",
+		[]string{"-sseof", "-0.5"},
+		[]string{"-update", "1"},
+	)
+}
+
+// extractFrame writes a single frame of videoPath to a temporary PNG using the
+// given seek (input) and frame selection (output) arguments, reads it back and
+// removes the temporary file.
+func (p *FFmpegProcessor) extractFrame(ctx context.Context, videoPath, which string, inputArgs, outputArgs []string) ([]byte, error) {
+	tmp, err := os.CreateTemp("", "frame_"+which+"_*.png")
+	if err != nil {
+		return nil, fmt.Errorf("create frame file: %w", err)
+	}
+	framePath := tmp.Name()
+	_ = tmp.Close()
+	defer func() { _ = os.Remove(framePath) }()
+
+	args := []string{"-y"}
+	args = append(args, inputArgs...)
+	args = append(args, "-i", videoPath)
+	args = append(args, outputArgs...)
+	args = append(args, "-c:v", "png", framePath)
+
+	if err := p.runFFmpeg(ctx, args); err != nil {
+		return nil, err
+	}
+
+	// #nosec G304 - framePath is created by this function
+	data, err := os.ReadFile(framePath)
+	if err != nil {
+		return nil, fmt.Errorf("read %s frame: %w", which, err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: no %s frame extracted from %s", ErrNoVideoStream, which, videoPath)
+	}
+
+	return data, nil
+}
+
+// GenerateThumbnail writes a single JPEG frame taken atSec seconds into
+// videoPath to dst. A non-positive atSec uses the video's mid-point.
+func (p *FFmpegProcessor) GenerateThumbnail(ctx context.Context, videoPath, dst string, atSec float64) error {
+	if atSec <= 0 {
+		info, err := p.ProbeVideo(ctx, videoPath)
+		if err != nil {
+			return fmt.Errorf("probe video for thumbnail: %w", err)
+		}
+		atSec = info.DurationSec / 2
+	}
+
+	args := []string{
+		"-y",
+		"-ss", strconv.FormatFloat(atSec, 'f', 3, 64), // Seek before decoding
+		"-i", videoPath,
+		"-frames:v", "1",
+		"-q:v", "2", // High JPEG quality
+		"-f", "image2",
+		"-c:v", "mjpeg",
+		dst,
+	}
+
+	return p.runFFmpeg(ctx, args)
+}
+
+// ProbeVideo returns the dimensions, duration, frame rate and codec of the
+// first video stream in path using a single ffprobe call.
+func (p *FFmpegProcessor) ProbeVideo(ctx context.Context, path string) (VideoInfo, error) {
+	data, err := p.probe(ctx, path)
+	if err != nil {
+		return VideoInfo{}, err
+	}
+	return parseProbeOutput(data)
+}
+
+// ProbeImage returns the dimensions and codec of the image in path.
+// ffprobe reports still images as a single-frame video stream.
+func (p *FFmpegProcessor) ProbeImage(ctx context.Context, path string) (ImageInfo, error) {
+	data, err := p.probe(ctx, path)
+	if err != nil {
+		return ImageInfo{}, err
+	}
+	return parseImageProbeOutput(data)
+}
+
+// ProbeAudio returns the duration, sample rate, channel count and codec of
+// the first audio stream in path.
+func (p *FFmpegProcessor) ProbeAudio(ctx context.Context, path string) (AudioInfo, error) {
+	data, err := p.probe(ctx, path)
+	if err != nil {
+		return AudioInfo{}, err
+	}
+	return parseAudioProbeOutput(data)
+}
+
+// probe runs ffprobe on path and returns its JSON description of the
+// streams and container.
+func (p *FFmpegProcessor) probe(ctx context.Context, path string) ([]byte, error) {
+	// #nosec G204 - ffprobePath is set by the application, not user input
+	cmd := exec.CommandContext(ctx, p.ffprobePath,
+		"-v", "error",
+		"-show_streams",
+		"-show_format",
+		"-of", "json",
+		path,
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("ffprobe cancelled: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("ffprobe error: %w, stderr: %s", err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
+
+// probeOutput mirrors the subset of ffprobe's JSON output used by ProbeVideo.
+type probeOutput struct {
+	Streams []struct {
+		CodecType    string `json:"codec_type"`
+		CodecName    string `json:"codec_name"`
+		Width        int    `json:"width"`
+		Height       int    `json:"height"`
+		AvgFrameRate string `json:"avg_frame_rate"`
+		RFrameRate   string `json:"r_frame_rate"`
+		Duration     string `json:"duration"`
+		SampleRate   string `json:"sample_rate"`
+		Channels     int    `json:"channels"`
+	} `json:"streams"`
+	Format struct {
+		Duration string `json:"duration"`
+		Size     string `json:"size"`
+	} `json:"format"`
+}
+
+// parseProbeOutput extracts VideoInfo from ffprobe JSON output.
+func parseProbeOutput(data []byte) (VideoInfo, error) {
+	var out probeOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return VideoInfo{}, fmt.Errorf("parse ffprobe output: %w", err)
+	}
+
+	for _, st := range out.Streams {
+		if st.CodecType != "video" {
+			continue
+		}
+		info := VideoInfo{
+			Width:  st.Width,
+			Height: st.Height,
+			Codec:  st.CodecName,
+			FPS:    parseFrameRate(st.AvgFrameRate),
+		}
+		if info.FPS == 0 {
+			info.FPS = parseFrameRate(st.RFrameRate)
+		}
+		// Prefer the container duration; fall back to the stream duration
+		if d, err := strconv.ParseFloat(out.Format.Duration, 64); err == nil {
+			info.DurationSec = d
+		} else if d, err := strconv.ParseFloat(st.Duration, 64); err == nil {
+			info.DurationSec = d
+		}
+		if size, err := strconv.ParseInt(out.Format.Size, 10, 64); err == nil {
+			info.SizeBytes = size
+		}
+		for _, as := range out.Streams {
+			if as.CodecType == "audio" {
+				info.AudioCodec = as.CodecName
+				break
+			}
+		}
+		return info, nil
+	}
+
+	return VideoInfo{}, ErrNoVideoStream
+}
+
+// parseImageProbeOutput extracts ImageInfo from ffprobe JSON output.
+func parseImageProbeOutput(data []byte) (ImageInfo, error) {
+	var out probeOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return ImageInfo{}, fmt.Errorf("parse ffprobe output: %w", err)
+	}
+
+	for _, st := range out.Streams {
+		if st.CodecType == "video" && st.Width > 0 && st.Height > 0 {
+			return ImageInfo{Width: st.Width, Height: st.Height, Codec: st.CodecName}, nil
+		}
+	}
+	return ImageInfo{}, ErrNoVideoStream
+}
+
+// parseAudioProbeOutput extracts AudioInfo from ffprobe JSON output.
+func parseAudioProbeOutput(data []byte) (AudioInfo, error) {
+	var out probeOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return AudioInfo{}, fmt.Errorf("parse ffprobe output: %w", err)
+	}
+
+	for _, st := range out.Streams {
+		if st.CodecType != "audio" {
+			continue
+		}
+		info := AudioInfo{Channels: st.Channels, Codec: st.CodecName}
+		info.SampleRate, _ = strconv.Atoi(st.SampleRate)
+		// Prefer the container duration; fall back to the stream duration
+		if d, err := strconv.ParseFloat(out.Format.Duration, 64); err == nil {
+			info.DurationSec = d
+		} else if d, err := strconv.ParseFloat(st.Duration, 64); err == nil {
+			info.DurationSec = d
+		}
+		return info, nil
+	}
+	return AudioInfo{}, ErrNoAudioStream
+}
+
+// parseFrameRate converts an ffprobe rational such as "25/1" or "30000/1001"
+// to frames per second. Invalid or zero rates return 0.
+func parseFrameRate(rate string) float64 {
+	num, den, found := strings.Cut(rate, "/")
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0
+	}
+	if !found {
+		return n
+	}
+	d, err := strconv.ParseFloat(den, 64)
+	if err != nil || d == 0 {
+		return 0
+	}
+	return n / d
+}
+
 // runFFmpeg executes ffmpeg with the given arguments and returns an error
 // containing stderr output if the command fails.
 func (p *FFmpegProcessor) runFFmpeg(ctx context.Context, args []string) error {
+	_, err := p.runFFmpegOutput(ctx, args)
+	return err
+}
+
+// runFFmpegOutput executes ffmpeg with the given arguments and returns its stdout.
+func (p *FFmpegProcessor) runFFmpegOutput(ctx context.Context, args []string) ([]byte, error) {
 	// #nosec G204 - ffmpegPath is set by the application, not user input
 	cmd := exec.CommandContext(ctx, p.ffmpegPath, args...)
 
-	var stderr bytes.Buffer
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
 	err := cmd.Run()
 	if err != nil {
 		// Check if context was cancelled
 		if ctx.Err() != nil {
-			return fmt.Errorf("ffmpeg cancelled: %w", ctx.Err())
+			return nil, fmt.Errorf("ffmpeg cancelled: %w", ctx.Err())
 		}
-		return &FFmpegError{
+		return nil, &FFmpegError{
 			Args:   args,
 			Stderr: stderr.String(),
 			Err:    err,
 		}
 	}
 
-	return nil
+	return stdout.Bytes(), nil
 }
 
 // FFmpegError represents an error from running ffmpeg, including the stderr output.
@@ -1,8 +1,12 @@
 package media
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -11,6 +15,9 @@ import (
 	"time"
 )
 
+// pngSignature is the 8-byte header every PNG file starts with.
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
 // skipIfNoFFmpeg skips the test if ffmpeg is not available.
 func skipIfNoFFmpeg(t *testing.T) {
 	t.Helper()
@@ -74,6 +81,189 @@ func TestNewFFmpegProcessor(t *testing.T) {
 	})
 }
 
+func TestNewFFmpegProcessorWithOptions(t *testing.T) {
+	t.Run("zero options fall back to defaults", func(t *testing.T) {
+		p := NewFFmpegProcessorWithOptions("", EncodeOptions{})
+		got, want := p.encode, DefaultEncodeOptions()
+		if got.CRF == nil || *got.CRF != *want.CRF {
+			t.Errorf("expected default CRF %d, got %v", *want.CRF, got.CRF)
+		}
+		got.CRF, want.CRF = nil, nil
+		if got != want {
+			t.Errorf("expected default encode options, got %+v", p.encode)
+		}
+	})
+
+	t.Run("partial options keep defaults for unset fields", func(t *testing.T) {
+		p := NewFFmpegProcessorWithOptions("", EncodeOptions{VideoCodec: "libx265"})
+		if p.encode.VideoCodec != "libx265" {
+			t.Errorf("expected codec libx265, got %q", p.encode.VideoCodec)
+		}
+		if *p.encode.CRF != DefaultCRF {
+			t.Errorf("expected default CRF %d, got %d", DefaultCRF, *p.encode.CRF)
+		}
+	})
+
+	t.Run("CRF 0 is kept for lossless encoding", func(t *testing.T) {
+		p := NewFFmpegProcessorWithOptions("", EncodeOptions{CRF: crf(0)})
+		if *p.encode.CRF != 0 {
+			t.Errorf("expected CRF 0, got %d", *p.encode.CRF)
+		}
+		if args := strings.Join(p.reencodeArgs("list.txt", "out.mp4"), " "); !strings.Contains(args, "-crf 0") {
+			t.Errorf("expected args to contain %q, got %q", "-crf 0", args)
+		}
+	})
+}
+
+func crf(n int) *int { return &n }
+
+func TestReencodeArgs(t *testing.T) {
+	t.Run("default options", func(t *testing.T) {
+		p := NewFFmpegProcessor("")
+		args := strings.Join(p.reencodeArgs("list.txt", "out.mp4"), " ")
+
+		for _, want := range []string{"-c:v libx264", "-preset fast", "-crf 23", "-c:a aac", "-b:a 128k"} {
+			if !strings.Contains(args, want) {
+				t.Errorf("expected args to contain %q, got %q", want, args)
+			}
+		}
+	})
+
+	t.Run("custom options", func(t *testing.T) {
+		p := NewFFmpegProcessorWithOptions("", EncodeOptions{
+			VideoCodec:   "libx265",
+			Preset:       "slow",
+			CRF:          crf(28),
+			AudioCodec:   "libopus",
+			AudioBitrate: "96k",
+		})
+		args := p.reencodeArgs("list.txt", "out.mp4")
+		joined := strings.Join(args, " ")
+
+		for _, want := range []string{"-c:v libx265", "-preset slow", "-crf 28", "-c:a libopus", "-b:a 96k"} {
+			if !strings.Contains(joined, want) {
+				t.Errorf("expected args to contain %q, got %q", want, joined)
+			}
+		}
+		if args[len(args)-1] != "out.mp4" {
+			t.Errorf("expected output as last arg, got %q", args[len(args)-1])
+		}
+	})
+}
+
+func TestReencodeArgs_WebM(t *testing.T) {
+	p := NewFFmpegProcessorWithOptions("", EncodeOptions{HWAccel: "nvenc", CRF: crf(31)})
+	args := p.reencodeArgs("list.txt", "out.webm")
+	joined := strings.Join(args, " ")
+
+	for _, want := range []string{"-c:v libvpx-vp9", "-crf 31", "-b:v 0", "-c:a libopus", "-b:a 128k"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected args to contain %q, got %q", want, joined)
+		}
+	}
+	for _, notWant := range []string{"-hwaccel", "libx264", "-c:a aac"} {
+		if strings.Contains(joined, notWant) {
+			t.Errorf("expected args not to contain %q, got %q", notWant, joined)
+		}
+	}
+	if args[len(args)-1] != "out.webm" {
+		t.Errorf("expected output as last arg, got %q", args[len(args)-1])
+	}
+}
+
+func TestReencodeArgs_HWAccel(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    EncodeOptions
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "nvenc h264",
+			opts:    EncodeOptions{HWAccel: "nvenc"},
+			want:    []string{"-hwaccel cuda", "-c:v h264_nvenc", "-preset fast", "-cq 23"},
+			notWant: []string{"libx264", "-crf"},
+		},
+		{
+			name:    "nvenc hevc",
+			opts:    EncodeOptions{HWAccel: "nvenc", VideoCodec: "libx265"},
+			want:    []string{"-c:v hevc_nvenc"},
+			notWant: []string{"libx265"},
+		},
+		{
+			name:    "qsv",
+			opts:    EncodeOptions{HWAccel: "qsv", CRF: crf(25)},
+			want:    []string{"-hwaccel qsv", "-c:v h264_qsv", "-global_quality 25"},
+			notWant: []string{"-crf"},
+		},
+		{
+			name:    "videotoolbox",
+			opts:    EncodeOptions{HWAccel: "videotoolbox"},
+			want:    []string{"-hwaccel videotoolbox", "-c:v h264_videotoolbox"},
+			notWant: []string{"-preset", "-crf"},
+		},
+		{
+			name:    "software",
+			opts:    EncodeOptions{},
+			want:    []string{"-c:v libx264", "-crf 23"},
+			notWant: []string{"-hwaccel"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewFFmpegProcessorWithOptions("", tc.opts)
+			args := strings.Join(p.reencodeArgs("list.txt", "out.mp4"), " ")
+			for _, w := range tc.want {
+				if !strings.Contains(args, w) {
+					t.Errorf("expected args to contain %q, got %q", w, args)
+				}
+			}
+			for _, nw := range tc.notWant {
+				if strings.Contains(args, nw) {
+					t.Errorf("expected args not to contain %q, got %q", nw, args)
+				}
+			}
+		})
+	}
+}
+
+func TestIsValidHWAccel(t *testing.T) {
+	for _, name := range []string{"", "nvenc", "qsv", "videotoolbox"} {
+		if !IsValidHWAccel(name) {
+			t.Errorf("expected %q to be valid", name)
+		}
+	}
+	if IsValidHWAccel("cuda-magic") {
+		t.Error("expected unknown accelerator to be invalid")
+	}
+}
+
+func TestCheckHWAccel(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("software needs no check", func(t *testing.T) {
+		p := NewFFmpegProcessor("/nonexistent/ffmpeg")
+		if err := p.CheckHWAccel(ctx); err != nil {
+			t.Errorf("expected nil error, got %v", err)
+		}
+	})
+
+	t.Run("unknown accelerator", func(t *testing.T) {
+		p := NewFFmpegProcessorWithOptions("", EncodeOptions{HWAccel: "bogus"})
+		if err := p.CheckHWAccel(ctx); !errors.Is(err, ErrUnsupportedHWAccel) {
+			t.Errorf("expected ErrUnsupportedHWAccel, got %v", err)
+		}
+	})
+
+	t.Run("missing ffmpeg binary", func(t *testing.T) {
+		p := NewFFmpegProcessorWithOptions("/nonexistent/ffmpeg", EncodeOptions{HWAccel: "nvenc"})
+		if err := p.CheckHWAccel(ctx); err == nil {
+			t.Error("expected error when ffmpeg is missing")
+		}
+	})
+}
+
 func TestResizeImageWithPadding(t *testing.T) {
 	skipIfNoFFmpeg(t)
 
@@ -202,6 +392,321 @@ func TestResizeImageWithPadding(t *testing.T) {
 	})
 }
 
+func TestSetPadColor(t *testing.T) {
+	valid := []string{"black", "white", "AliceBlue", "#1a2B3c"}
+	for _, color := range valid {
+		t.Run("valid "+color, func(t *testing.T) {
+			p := NewFFmpegProcessor("")
+			if err := p.SetPadColor(color); err != nil {
+				t.Fatalf("SetPadColor(%q) returned error: %v", color, err)
+			}
+			if p.padColor != color {
+				t.Errorf("expected padColor %q, got %q", color, p.padColor)
+			}
+		})
+	}
+
+	invalid := []string{"#fff", "#12345G", "red:eval=frame", "white,drawtext=text=x", "0xFFFFFF", "red@0.5", " white"}
+	for _, color := range invalid {
+		t.Run("invalid "+color, func(t *testing.T) {
+			p := NewFFmpegProcessor("")
+			err := p.SetPadColor(color)
+			if !errors.Is(err, ErrInvalidPadColor) {
+				t.Fatalf("expected ErrInvalidPadColor for %q, got %v", color, err)
+			}
+			if p.padColor != DefaultPadColor {
+				t.Errorf("padColor changed to %q after invalid input", p.padColor)
+			}
+		})
+	}
+
+	t.Run("empty restores default", func(t *testing.T) {
+		p := NewFFmpegProcessor("")
+		if err := p.SetPadColor("white"); err != nil {
+			t.Fatal(err)
+		}
+		if err := p.SetPadColor(""); err != nil {
+			t.Fatal(err)
+		}
+		if p.padColor != DefaultPadColor {
+			t.Errorf("expected padColor %q, got %q", DefaultPadColor, p.padColor)
+		}
+	})
+}
+
+func TestResizeImageWithPadding_PadColor(t *testing.T) {
+	skipIfNoFFmpeg(t)
+
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "landscape.png")
+	dst := filepath.Join(tmpDir, "padded_white.png")
+
+	// 100x50 red image padded to 64x64 leaves bars at the top and bottom
+	createTestImage(t, src, 100, 50)
+
+	p := NewFFmpegProcessor("")
+	if err := p.SetPadColor("white"); err != nil {
+		t.Fatalf("SetPadColor failed: %v", err)
+	}
+	if err := p.ResizeImageWithPadding(context.Background(), src, dst, 64, 64); err != nil {
+		t.Fatalf("ResizeImageWithPadding failed: %v", err)
+	}
+
+	r, g, b := samplePixel(t, dst, 32, 0)
+	if r == 0 && g == 0 && b == 0 {
+		t.Fatal("padded region is pure black, expected the configured pad color")
+	}
+	if r < 200 || g < 200 || b < 200 {
+		t.Errorf("expected a white padded region, got rgb(%d,%d,%d)", r, g, b)
+	}
+}
+
+// samplePixel returns the RGB value of the pixel at (x, y) in an image.
+func samplePixel(t *testing.T, path string, x, y int) (r, g, b byte) {
+	t.Helper()
+
+	cmd := exec.Command("ffmpeg",
+		"-v", "error",
+		"-i", path,
+		"-vf", fmt.Sprintf("crop=1:1:%d:%d", x, y),
+		"-frames:v", "1",
+		"-f", "rawvideo",
+		"-pix_fmt", "rgb24",
+		"-",
+	)
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("failed to sample pixel: %v", err)
+	}
+	if len(out) < 3 {
+		t.Fatalf("expected 3 bytes of rgb24 data, got %d", len(out))
+	}
+	return out[0], out[1], out[2]
+}
+
+func TestResizeImageCropToFill(t *testing.T) {
+	skipIfNoFFmpeg(t)
+
+	tmpDir := t.TempDir()
+	p := NewFFmpegProcessor("")
+	ctx := context.Background()
+
+	resizers := map[ResizeMode]func(context.Context, string, string, int, int) error{
+		ResizeModePad:  p.ResizeImageWithPadding,
+		ResizeModeCrop: p.ResizeImageCropToFill,
+	}
+
+	sources := []struct {
+		name string
+		w, h int
+	}{
+		{"landscape", 100, 50},
+		{"portrait", 50, 100},
+	}
+
+	targets := []struct {
+		w, h int
+	}{
+		{64, 64},
+		{384, 576},
+	}
+
+	for mode, resize := range resizers {
+		for _, src := range sources {
+			for _, target := range targets {
+				name := fmt.Sprintf("%s %s to %dx%d", mode, src.name, target.w, target.h)
+				t.Run(name, func(t *testing.T) {
+					srcPath := filepath.Join(tmpDir, fmt.Sprintf("%s_%s_%dx%d_src.png", mode, src.name, target.w, target.h))
+					dst := filepath.Join(tmpDir, fmt.Sprintf("%s_%s_%dx%d_dst.png", mode, src.name, target.w, target.h))
+					createTestImage(t, srcPath, src.w, src.h)
+
+					if err := resize(ctx, srcPath, dst, target.w, target.h); err != nil {
+						t.Fatalf("resize failed: %v", err)
+					}
+
+					verifyImageDimensions(t, dst, target.w, target.h)
+				})
+			}
+		}
+	}
+
+	t.Run("invalid dimensions", func(t *testing.T) {
+		err := p.ResizeImageCropToFill(ctx, "in.png", "out.png", 0, 64)
+		if !errors.Is(err, ErrInvalidDimensions) {
+			t.Errorf("expected ErrInvalidDimensions, got %v", err)
+		}
+	})
+}
+
+func TestResizeImageToPNG(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid dimensions", func(t *testing.T) {
+		p := NewFFmpegProcessor("")
+		_, err := p.ResizeImageToPNG(ctx, "in.png", 64, 0, ResizeModePad)
+		if !errors.Is(err, ErrInvalidDimensions) {
+			t.Errorf("expected ErrInvalidDimensions, got %v", err)
+		}
+	})
+
+	skipIfNoFFmpeg(t)
+
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "landscape.png")
+	createTestImage(t, src, 100, 50)
+
+	p := NewFFmpegProcessor("")
+	resizers := map[ResizeMode]func(context.Context, string, string, int, int) error{
+		ResizeModePad:  p.ResizeImageWithPadding,
+		ResizeModeCrop: p.ResizeImageCropToFill,
+	}
+
+	for mode, resize := range resizers {
+		t.Run(fmt.Sprintf("%s matches file output", mode), func(t *testing.T) {
+			dst := filepath.Join(tmpDir, fmt.Sprintf("resized_%s.png", mode))
+			if err := resize(ctx, src, dst, 64, 64); err != nil {
+				t.Fatalf("resize to file failed: %v", err)
+			}
+			fromFile, err := os.ReadFile(dst)
+			if err != nil {
+				t.Fatalf("failed to read resized file: %v", err)
+			}
+
+			inMemory, err := p.ResizeImageToPNG(ctx, src, 64, 64, mode)
+			if err != nil {
+				t.Fatalf("ResizeImageToPNG failed: %v", err)
+			}
+
+			if !bytes.HasPrefix(inMemory, pngSignature) {
+				t.Fatalf("expected PNG output, got %d bytes starting with %x", len(inMemory), inMemory[:min(8, len(inMemory))])
+			}
+			if base64.StdEncoding.EncodeToString(inMemory) != base64.StdEncoding.EncodeToString(fromFile) {
+				t.Errorf("in-memory base64 differs from file base64 (%d vs %d bytes)", len(inMemory), len(fromFile))
+			}
+		})
+	}
+}
+
+// skipIfNoEncoder skips the test when ffmpeg lacks the named encoder.
+func skipIfNoEncoder(t *testing.T, name string) {
+	t.Helper()
+	out, err := exec.Command("ffmpeg", "-hide_banner", "-encoders").Output()
+	if err != nil || !strings.Contains(string(out), " "+name+" ") {
+		t.Skipf("ffmpeg has no %s encoder, skipping test", name)
+	}
+}
+
+func TestNormalizeImage(t *testing.T) {
+	skipIfNoFFmpeg(t)
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	p := NewFFmpegProcessor("")
+
+	t.Run("webp", func(t *testing.T) {
+		skipIfNoEncoder(t, "libwebp")
+		src := filepath.Join(tmpDir, "input.webp")
+		createTestImage(t, src, 120, 80)
+
+		dst := filepath.Join(tmpDir, "webp.png")
+		if err := p.NormalizeImage(ctx, src, dst); err != nil {
+			t.Fatalf("NormalizeImage failed: %v", err)
+		}
+		info, err := p.ProbeImage(ctx, dst)
+		if err != nil {
+			t.Fatalf("failed to probe output: %v", err)
+		}
+		if info.Codec != "png" || info.Width != 120 || info.Height != 80 {
+			t.Errorf("expected a 120x80 png, got %dx%d %s", info.Width, info.Height, info.Codec)
+		}
+	})
+
+	t.Run("animated gif keeps first frame", func(t *testing.T) {
+		src := filepath.Join(tmpDir, "input.gif")
+		cmd := exec.Command("ffmpeg", "-y",
+			"-f", "lavfi", "-i", "color=c=blue:s=64x48:d=1:r=5",
+			src,
+		)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("failed to create test gif: %v\noutput: %s", err, output)
+		}
+
+		dst := filepath.Join(tmpDir, "gif.png")
+		if err := p.NormalizeImage(ctx, src, dst); err != nil {
+			t.Fatalf("NormalizeImage failed: %v", err)
+		}
+		data, err := os.ReadFile(dst)
+		if err != nil {
+			t.Fatalf("failed to read output: %v", err)
+		}
+		if !bytes.HasPrefix(data, pngSignature) {
+			t.Errorf("expected PNG output, got %d bytes", len(data))
+		}
+		verifyImageDimensions(t, dst, 64, 48)
+	})
+
+	t.Run("undecodable", func(t *testing.T) {
+		src := filepath.Join(tmpDir, "garbage.heic")
+		if err := os.WriteFile(src, []byte("not an image at all"), 0o600); err != nil {
+			t.Fatalf("failed to write input: %v", err)
+		}
+		err := p.NormalizeImage(ctx, src, filepath.Join(tmpDir, "garbage.png"))
+		if !errors.Is(err, ErrUnsupportedImageFormat) {
+			t.Errorf("expected ErrUnsupportedImageFormat, got %v", err)
+		}
+	})
+}
+
+func TestIsSupportedImageCodec(t *testing.T) {
+	for codec, want := range map[string]bool{
+		"png": true, "mjpeg": true, "webp": false, "gif": false, "hevc": false, "": false,
+	} {
+		if got := IsSupportedImageCodec(codec); got != want {
+			t.Errorf("IsSupportedImageCodec(%q) = %v, want %v", codec, got, want)
+		}
+	}
+}
+
+func BenchmarkResizeImage(b *testing.B) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		b.Skip("ffmpeg not found in PATH, skipping benchmark")
+	}
+
+	tmpDir := b.TempDir()
+	src := filepath.Join(tmpDir, "source.png")
+	cmd := exec.Command("ffmpeg", "-y", "-f", "lavfi", "-i", "color=c=red:s=1920x1080:d=1", "-frames:v", "1", src)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		b.Fatalf("failed to create test image: %v\noutput: %s", err, output)
+	}
+
+	p := NewFFmpegProcessor("")
+	ctx := context.Background()
+
+	b.Run("file", func(b *testing.B) {
+		dst := filepath.Join(tmpDir, "resized.png")
+		for i := 0; i < b.N; i++ {
+			if err := p.ResizeImageWithPadding(ctx, src, dst, 1024, 1024); err != nil {
+				b.Fatal(err)
+			}
+			data, err := os.ReadFile(dst)
+			if err != nil {
+				b.Fatal(err)
+			}
+			_ = base64.StdEncoding.EncodeToString(data)
+		}
+	})
+
+	b.Run("in-memory", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			data, err := p.ResizeImageToPNG(ctx, src, 1024, 1024, ResizeModePad)
+			if err != nil {
+				b.Fatal(err)
+			}
+			_ = base64.StdEncoding.EncodeToString(data)
+		}
+	})
+}
+
 func TestJoinVideos(t *testing.T) {
 	skipIfNoFFmpeg(t)
 
@@ -256,6 +761,31 @@ func TestJoinVideos(t *testing.T) {
 		}
 	})
 
+	t.Run("other container", func(t *testing.T) {
+		video1 := filepath.Join(tmpDir, "format1.mp4")
+		video2 := filepath.Join(tmpDir, "format2.mp4")
+		createTestVideo(t, video1, 0.5, "red")
+		createTestVideo(t, video2, 0.5, "blue")
+
+		for _, output := range []string{filepath.Join(tmpDir, "joined.mov"), filepath.Join(tmpDir, "single.mov")} {
+			inputs := []string{video1, video2}
+			if strings.HasPrefix(filepath.Base(output), "single") {
+				inputs = inputs[:1]
+			}
+			if err := p.JoinVideos(context.Background(), inputs, output); err != nil {
+				t.Fatalf("JoinVideos to %s failed: %v", output, err)
+			}
+			out, err := exec.Command("ffprobe", "-v", "error", "-show_entries", "format=format_name",
+				"-of", "default=noprint_wrappers=1:nokey=1", output).Output()
+			if err != nil {
+				t.Fatalf("ffprobe failed: %v", err)
+			}
+			if !strings.Contains(string(out), "mov") {
+				t.Errorf("expected a QuickTime container, got %q", out)
+			}
+		}
+	})
+
 	t.Run("empty video list", func(t *testing.T) {
 		ctx := context.Background()
 		err := p.JoinVideos(ctx, []string{}, filepath.Join(tmpDir, "empty.mp4"))
@@ -312,6 +842,195 @@ func TestJoinVideos(t *testing.T) {
 	})
 }
 
+func TestResizeVideo(t *testing.T) {
+	t.Run("invalid dimensions", func(t *testing.T) {
+		p := NewFFmpegProcessor("")
+		err := p.ResizeVideo(context.Background(), "in.mp4", "out.mp4", 0, 64, ResizeModePad)
+		if !errors.Is(err, ErrInvalidDimensions) {
+			t.Errorf("expected ErrInvalidDimensions, got %v", err)
+		}
+	})
+
+	skipIfNoFFmpeg(t)
+
+	tmpDir := t.TempDir()
+	p := NewFFmpegProcessor("")
+	ctx := context.Background()
+
+	src := filepath.Join(tmpDir, "square.mp4")
+	createTestVideo(t, src, 0.5, "red")
+
+	for _, mode := range []ResizeMode{ResizeModePad, ResizeModeCrop} {
+		t.Run(string(mode), func(t *testing.T) {
+			dst := filepath.Join(tmpDir, "resized_"+string(mode)+".mp4")
+			if err := p.ResizeVideo(ctx, src, dst, 96, 48, mode); err != nil {
+				t.Fatalf("ResizeVideo failed: %v", err)
+			}
+			info, err := p.ProbeVideo(ctx, dst)
+			if err != nil {
+				t.Fatalf("ProbeVideo failed: %v", err)
+			}
+			if info.Width != 96 || info.Height != 48 {
+				t.Errorf("expected 96x48, got %dx%d", info.Width, info.Height)
+			}
+			if info.AudioCodec != "aac" {
+				t.Errorf("expected the aac audio to be kept, got %q", info.AudioCodec)
+			}
+		})
+	}
+}
+
+func TestNormalizeFPS(t *testing.T) {
+	t.Run("invalid fps", func(t *testing.T) {
+		p := NewFFmpegProcessor("")
+		for _, fps := range []float64{0, -25} {
+			err := p.NormalizeFPS(context.Background(), "in.mp4", "out.mp4", fps)
+			if !errors.Is(err, ErrInvalidFPS) {
+				t.Errorf("fps %g: expected ErrInvalidFPS, got %v", fps, err)
+			}
+		}
+	})
+
+	skipIfNoFFmpeg(t)
+
+	tmpDir := t.TempDir()
+	p := NewFFmpegProcessor("")
+	ctx := context.Background()
+
+	t.Run("converts the frame rate", func(t *testing.T) {
+		src := filepath.Join(tmpDir, "src.mp4")
+		createTestVideo(t, src, 1, "red")
+
+		for _, fps := range []float64{30, 24} {
+			dst := filepath.Join(tmpDir, fmt.Sprintf("normalized_%g.mp4", fps))
+			if err := p.NormalizeFPS(ctx, src, dst, fps); err != nil {
+				t.Fatalf("NormalizeFPS(%g) failed: %v", fps, err)
+			}
+			info, err := p.ProbeVideo(ctx, dst)
+			if err != nil {
+				t.Fatalf("ProbeVideo failed: %v", err)
+			}
+			if info.FPS != fps {
+				t.Errorf("expected %g fps, got %.2f", fps, info.FPS)
+			}
+			if info.DurationSec < 0.9 || info.DurationSec > 1.1 {
+				t.Errorf("expected the duration to be kept at ~1.0s, got %.2f", info.DurationSec)
+			}
+		}
+	})
+
+	t.Run("normalized chunks join", func(t *testing.T) {
+		video1 := filepath.Join(tmpDir, "rate1.mp4")
+		video2 := filepath.Join(tmpDir, "rate2.mp4")
+		createTestVideo(t, video1, 0.5, "red")
+		createTestVideo(t, video2, 0.5, "blue")
+
+		var normalized []string
+		for _, src := range []string{video1, video2} {
+			dst := strings.TrimSuffix(src, ".mp4") + "_30fps.mp4"
+			if err := p.NormalizeFPS(ctx, src, dst, 30); err != nil {
+				t.Fatalf("NormalizeFPS failed: %v", err)
+			}
+			normalized = append(normalized, dst)
+		}
+
+		output := filepath.Join(tmpDir, "joined_30fps.mp4")
+		if err := p.JoinVideos(ctx, normalized, output); err != nil {
+			t.Fatalf("JoinVideos failed: %v", err)
+		}
+		info, err := p.ProbeVideo(ctx, output)
+		if err != nil {
+			t.Fatalf("ProbeVideo failed: %v", err)
+		}
+		if info.FPS != 30 {
+			t.Errorf("expected the joined video at 30 fps, got %.2f", info.FPS)
+		}
+	})
+
+	t.Run("non-existent video", func(t *testing.T) {
+		err := p.NormalizeFPS(ctx, "/nonexistent/video.mp4", filepath.Join(tmpDir, "missing.mp4"), 25)
+		if err == nil {
+			t.Error("expected error for non-existent video, got nil")
+		}
+	})
+}
+
+func TestReplaceAudioArgs(t *testing.T) {
+	p := NewFFmpegProcessor("")
+
+	args := strings.Join(p.replaceAudioArgs("joined.mp4", "audio.wav", "out.mp4"), " ")
+	for _, want := range []string{"-i joined.mp4 -i audio.wav", "-map 0:v:0 -map 1:a:0", "-c:v copy", "-c:a aac"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("expected args to contain %q, got %q", want, args)
+		}
+	}
+	if strings.Contains(args, "-shortest") {
+		t.Errorf("expected the audio not to be trimmed, got %q", args)
+	}
+
+	args = strings.Join(p.replaceAudioArgs("joined.webm", "audio.wav", "out.webm"), " ")
+	if !strings.Contains(args, "-c:a libopus") {
+		t.Errorf("expected WebM output to use Opus, got %q", args)
+	}
+}
+
+func TestReplaceAudio(t *testing.T) {
+	skipIfNoFFmpeg(t)
+
+	tmpDir := t.TempDir()
+	p := NewFFmpegProcessor("")
+	ctx := context.Background()
+
+	video := filepath.Join(tmpDir, "joined.mp4")
+	createTestVideo(t, video, 1.0, "red")
+
+	audio := filepath.Join(tmpDir, "original.wav")
+	cmd := exec.Command("ffmpeg",
+		"-y",
+		"-f", "lavfi",
+		"-i", "sine=frequency=440:sample_rate=16000:duration=2",
+		audio,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to create test audio: %v\noutput: %s", err, output)
+	}
+	source, err := p.ProbeAudio(ctx, audio)
+	if err != nil {
+		t.Fatalf("ProbeAudio failed: %v", err)
+	}
+
+	output := filepath.Join(tmpDir, "output.mp4")
+	if err := p.ReplaceAudio(ctx, video, audio, output); err != nil {
+		t.Fatalf("ReplaceAudio failed: %v", err)
+	}
+
+	got, err := p.ProbeAudio(ctx, output)
+	if err != nil {
+		t.Fatalf("ProbeAudio failed: %v", err)
+	}
+	if math.Abs(got.DurationSec-source.DurationSec) > 0.1 {
+		t.Errorf("expected the output audio to match the source track (%.2fs), got %.2fs", source.DurationSec, got.DurationSec)
+	}
+	if got.SampleRate != source.SampleRate {
+		t.Errorf("expected the source sample rate %d, got %d", source.SampleRate, got.SampleRate)
+	}
+
+	info, err := p.ProbeVideo(ctx, output)
+	if err != nil {
+		t.Fatalf("ProbeVideo failed: %v", err)
+	}
+	if info.Width != 64 || info.Height != 64 {
+		t.Errorf("expected the joined video stream (64x64), got %dx%d", info.Width, info.Height)
+	}
+
+	t.Run("missing audio", func(t *testing.T) {
+		err := p.ReplaceAudio(ctx, video, filepath.Join(tmpDir, "missing.wav"), filepath.Join(tmpDir, "missing.mp4"))
+		if err == nil {
+			t.Error("expected error for non-existent audio, got nil")
+		}
+	})
+}
+
 func TestFFmpegError(t *testing.T) {
 	err := &FFmpegError{
 		Args:   []string{"-i", "input.mp4", "-c", "copy", "output.mp4"},
@@ -343,52 +1062,346 @@ func TestFFmpegError(t *testing.T) {
 	}
 }
 
-// Helper functions
-
-func verifyImageDimensions(t *testing.T, path string, expectedW, expectedH int) {
+// frameTempFiles lists leftover temporary frame files for the given position.
+func frameTempFiles(t *testing.T, which string) []string {
 	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(os.TempDir(), "frame_"+which+"_*.png"))
+	if err != nil {
+		t.Fatalf("glob temp frames: %v", err)
+	}
+	return matches
+}
 
-	cmd := exec.Command("ffprobe",
-		"-v", "error",
-		"-select_streams", "v:0",
-		"-show_entries", "stream=width,height",
-		"-of", "csv=s=x:p=0",
-		path,
-	)
-	output, err := cmd.Output()
+func TestExtractFirstFrame(t *testing.T) {
+	skipIfNoFFmpeg(t)
+
+	tmpDir := t.TempDir()
+	p := NewFFmpegProcessor("")
+	ctx := context.Background()
+
+	videoPath := filepath.Join(tmpDir, "first.mp4")
+	createTestVideo(t, videoPath, 1.0, "green")
+
+	before := len(frameTempFiles(t, "first"))
+
+	data, err := p.ExtractFirstFrame(ctx, videoPath)
 	if err != nil {
-		t.Fatalf("ffprobe failed: %v", err)
+		t.Fatalf("ExtractFirstFrame failed: %v", err)
+	}
+	if !bytes.HasPrefix(data, pngSignature) {
+		t.Error("expected PNG data")
+	}
+
+	// Temp frame file must be cleaned up
+	if after := len(frameTempFiles(t, "first")); after != before {
+		t.Errorf("expected temp frame file to be removed, found %d leftover", after-before)
 	}
 
-	var w, h int
-	n, err := fmt.Sscanf(string(output), "%dx%d", &w, &h)
-	if err != nil || n != 2 {
-		t.Fatalf("failed to parse dimensions from ffprobe output: %s", output)
+	t.Run("non-existent video", func(t *testing.T) {
+		if _, err := p.ExtractFirstFrame(ctx, filepath.Join(tmpDir, "missing.mp4")); err == nil {
+			t.Error("expected error for non-existent video")
+		}
+		if after := len(frameTempFiles(t, "first")); after != before {
+			t.Errorf("expected temp frame file to be removed on error, found %d leftover", after-before)
+		}
+	})
+}
+
+func TestExtractLastFrame(t *testing.T) {
+	skipIfNoFFmpeg(t)
+
+	tmpDir := t.TempDir()
+	p := NewFFmpegProcessor("")
+	ctx := context.Background()
+
+	videoPath := filepath.Join(tmpDir, "last.mp4")
+	createTestVideo(t, videoPath, 2.0, "red")
+
+	before := len(frameTempFiles(t, "last"))
+
+	data, err := p.ExtractLastFrame(ctx, videoPath)
+	if err != nil {
+		t.Fatalf("ExtractLastFrame failed: %v", err)
+	}
+	if !bytes.HasPrefix(data, pngSignature) {
+		t.Error("expected PNG data")
 	}
 
-	if w != expectedW || h != expectedH {
-		t.Errorf("expected dimensions %dx%d, got %dx%d", expectedW, expectedH, w, h)
+	if after := len(frameTempFiles(t, "last")); after != before {
+		t.Errorf("expected temp frame file to be removed, found %d leftover", after-before)
 	}
+
+	t.Run("non-existent video", func(t *testing.T) {
+		if _, err := p.ExtractLastFrame(ctx, filepath.Join(tmpDir, "missing.mp4")); err == nil {
+			t.Error("expected error for non-existent video")
+		}
+	})
 }
 
-func getVideoDuration(t *testing.T, path string) float64 {
+func TestGenerateThumbnail(t *testing.T) {
+	skipIfNoFFmpeg(t)
+
+	tmpDir := t.TempDir()
+	videoPath := filepath.Join(tmpDir, "thumb_source.mp4")
+	createTestVideo(t, videoPath, 2.0, "blue")
+
+	p := NewFFmpegProcessor("")
+	ctx := context.Background()
+
+	for _, atSec := range []float64{0, 0.5} {
+		t.Run(fmt.Sprintf("at %.1fs", atSec), func(t *testing.T) {
+			dst := filepath.Join(tmpDir, fmt.Sprintf("thumb_%.1f.jpg", atSec))
+			if err := p.GenerateThumbnail(ctx, videoPath, dst, atSec); err != nil {
+				t.Fatalf("GenerateThumbnail failed: %v", err)
+			}
+
+			data, err := os.ReadFile(dst)
+			if err != nil {
+				t.Fatalf("failed to read thumbnail: %v", err)
+			}
+			if !bytes.HasPrefix(data, []byte{0xff, 0xd8, 0xff}) {
+				t.Fatalf("expected JPEG output, got %d bytes starting with %x", len(data), data[:min(4, len(data))])
+			}
+
+			verifyImageDimensions(t, dst, 64, 64)
+
+			r, g, b := samplePixel(t, dst, 32, 32)
+			if b < 200 || r > 60 || g > 60 {
+				t.Errorf("expected a blue frame, got rgb(%d,%d,%d)", r, g, b)
+			}
+		})
+	}
+
+	t.Run("missing video", func(t *testing.T) {
+		dst := filepath.Join(tmpDir, "missing.jpg")
+		if err := p.GenerateThumbnail(ctx, filepath.Join(tmpDir, "missing.mp4"), dst, 0); err == nil {
+			t.Error("expected error for missing video")
+		}
+	})
+}
+
+func TestProbeVideo(t *testing.T) {
+	skipIfNoFFmpeg(t)
+
+	tmpDir := t.TempDir()
+	videoPath := filepath.Join(tmpDir, "probe.mp4")
+	createTestVideo(t, videoPath, 2.0, "blue")
+
+	p := NewFFmpegProcessor("")
+	info, err := p.ProbeVideo(context.Background(), videoPath)
+	if err != nil {
+		t.Fatalf("ProbeVideo failed: %v", err)
+	}
+
+	if info.Width != 64 || info.Height != 64 {
+		t.Errorf("expected 64x64, got %dx%d", info.Width, info.Height)
+	}
+	if info.DurationSec < 1.8 || info.DurationSec > 2.2 {
+		t.Errorf("expected duration ~2s, got %.2f", info.DurationSec)
+	}
+	if info.FPS != 25 {
+		t.Errorf("expected 25 fps, got %.2f", info.FPS)
+	}
+	if info.Codec != "h264" {
+		t.Errorf("expected codec h264, got %q", info.Codec)
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := p.ProbeVideo(context.Background(), filepath.Join(tmpDir, "missing.mp4")); err == nil {
+			t.Error("expected error for missing file")
+		}
+	})
+}
+
+func TestParseProbeOutput(t *testing.T) {
+	t.Run("video and audio streams", func(t *testing.T) {
+		data := []byte(`{
+			"streams": [
+				{"codec_type": "audio", "codec_name": "aac", "duration": "4.000000"},
+				{"codec_type": "video", "codec_name": "h264", "width": 384, "height": 576,
+				 "avg_frame_rate": "30000/1001", "r_frame_rate": "30/1", "duration": "3.970000"}
+			],
+			"format": {"duration": "4.010000", "size": "123456"}
+		}`)
+
+		info, err := parseProbeOutput(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.Width != 384 || info.Height != 576 {
+			t.Errorf("expected 384x576, got %dx%d", info.Width, info.Height)
+		}
+		if info.Codec != "h264" || info.AudioCodec != "aac" {
+			t.Errorf("expected codecs h264/aac, got %q/%q", info.Codec, info.AudioCodec)
+		}
+		if info.SizeBytes != 123456 {
+			t.Errorf("expected size 123456, got %d", info.SizeBytes)
+		}
+		if info.FPS < 29.97 || info.FPS > 29.98 {
+			t.Errorf("expected ~29.97 fps, got %f", info.FPS)
+		}
+		if info.DurationSec != 4.01 {
+			t.Errorf("expected format duration 4.01, got %f", info.DurationSec)
+		}
+	})
+
+	t.Run("falls back to stream duration and r_frame_rate", func(t *testing.T) {
+		data := []byte(`{"streams": [{"codec_type": "video", "codec_name": "hevc", "width": 64, "height": 64,
+			"avg_frame_rate": "0/0", "r_frame_rate": "25/1", "duration": "1.5"}], "format": {}}`)
+
+		info, err := parseProbeOutput(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.FPS != 25 {
+			t.Errorf("expected 25 fps, got %f", info.FPS)
+		}
+		if info.DurationSec != 1.5 {
+			t.Errorf("expected stream duration 1.5, got %f", info.DurationSec)
+		}
+		if info.AudioCodec != "" || info.SizeBytes != 0 {
+			t.Errorf("expected no audio codec and unknown size, got %q and %d", info.AudioCodec, info.SizeBytes)
+		}
+	})
+
+	t.Run("no video stream", func(t *testing.T) {
+		data := []byte(`{"streams": [{"codec_type": "audio", "codec_name": "aac"}], "format": {"duration": "1.0"}}`)
+
+		_, err := parseProbeOutput(data)
+		if !errors.Is(err, ErrNoVideoStream) {
+			t.Errorf("expected ErrNoVideoStream, got %v", err)
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		if _, err := parseProbeOutput([]byte("not json")); err == nil {
+			t.Error("expected error for invalid JSON")
+		}
+	})
+}
+
+func TestParseImageProbeOutput(t *testing.T) {
+	t.Run("png image", func(t *testing.T) {
+		data := []byte(`{"streams": [{"codec_type": "video", "codec_name": "png", "width": 1024, "height": 1536}],
+			"format": {"format_name": "png_pipe"}}`)
+
+		info, err := parseImageProbeOutput(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.Width != 1024 || info.Height != 1536 {
+			t.Errorf("expected 1024x1536, got %dx%d", info.Width, info.Height)
+		}
+		if info.Codec != "png" {
+			t.Errorf("expected codec png, got %q", info.Codec)
+		}
+	})
+
+	t.Run("audio file", func(t *testing.T) {
+		data := []byte(`{"streams": [{"codec_type": "audio", "codec_name": "pcm_s16le"}], "format": {"duration": "1.0"}}`)
+
+		_, err := parseImageProbeOutput(data)
+		if !errors.Is(err, ErrNoVideoStream) {
+			t.Errorf("expected ErrNoVideoStream, got %v", err)
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		if _, err := parseImageProbeOutput([]byte("not json")); err == nil {
+			t.Error("expected error for invalid JSON")
+		}
+	})
+}
+
+func TestParseAudioProbeOutput(t *testing.T) {
+	t.Run("wav audio", func(t *testing.T) {
+		data := []byte(`{"streams": [{"codec_type": "audio", "codec_name": "pcm_s16le", "sample_rate": "16000",
+			"channels": 1, "duration": "2.000000"}], "format": {"duration": "2.500000"}}`)
+
+		info, err := parseAudioProbeOutput(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.DurationSec != 2.5 {
+			t.Errorf("expected format duration 2.5, got %f", info.DurationSec)
+		}
+		if info.SampleRate != 16000 || info.Channels != 1 {
+			t.Errorf("expected 16000 Hz mono, got %d Hz %d channels", info.SampleRate, info.Channels)
+		}
+		if info.Codec != "pcm_s16le" {
+			t.Errorf("expected codec pcm_s16le, got %q", info.Codec)
+		}
+	})
+
+	t.Run("falls back to stream duration", func(t *testing.T) {
+		data := []byte(`{"streams": [{"codec_type": "audio", "codec_name": "mp3", "duration": "3.25"}], "format": {}}`)
+
+		info, err := parseAudioProbeOutput(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.DurationSec != 3.25 {
+			t.Errorf("expected stream duration 3.25, got %f", info.DurationSec)
+		}
+	})
+
+	t.Run("image file", func(t *testing.T) {
+		data := []byte(`{"streams": [{"codec_type": "video", "codec_name": "png", "width": 64, "height": 64}], "format": {}}`)
+
+		_, err := parseAudioProbeOutput(data)
+		if !errors.Is(err, ErrNoAudioStream) {
+			t.Errorf("expected ErrNoAudioStream, got %v", err)
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		if _, err := parseAudioProbeOutput([]byte("not json")); err == nil {
+			t.Error("expected error for invalid JSON")
+		}
+	})
+}
+
+func TestProbePathFor(t *testing.T) {
+	tests := []struct {
+		ffmpeg string
+		want   string
+	}{
+		{"ffmpeg", "ffprobe"},
+		{"/opt/ffmpeg/bin/ffmpeg", "/opt/ffmpeg/bin/ffprobe"},
+		{"/usr/local/bin/ffmpeg-6", "ffprobe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ffmpeg, func(t *testing.T) {
+			if got := probePathFor(tt.ffmpeg); got != tt.want {
+				t.Errorf("probePathFor(%q) = %q, want %q", tt.ffmpeg, got, tt.want)
+			}
+		})
+	}
+}
+
+// Helper functions
+
+func verifyImageDimensions(t *testing.T, path string, expectedW, expectedH int) {
 	t.Helper()
 
-	cmd := exec.Command("ffprobe",
-		"-v", "error",
-		"-show_entries", "format=duration",
-		"-of", "csv=p=0",
-		path,
-	)
-	output, err := cmd.Output()
+	info, err := NewFFmpegProcessor("").ProbeVideo(context.Background(), path)
 	if err != nil {
-		t.Fatalf("ffprobe failed: %v", err)
+		t.Fatalf("ProbeVideo failed: %v", err)
 	}
 
-	var duration float64
-	if _, err := fmt.Sscanf(string(output), "%f", &duration); err != nil {
-		t.Fatalf("failed to parse duration: %s", output)
+	if info.Width != expectedW || info.Height != expectedH {
+		t.Errorf("expected dimensions %dx%d, got %dx%d", expectedW, expectedH, info.Width, info.Height)
+	}
+}
+
+func getVideoDuration(t *testing.T, path string) float64 {
+	t.Helper()
+
+	info, err := NewFFmpegProcessor("").ProbeVideo(context.Background(), path)
+	if err != nil {
+		t.Fatalf("ProbeVideo failed: %v", err)
 	}
 
-	return duration
+	return info.DurationSec
 }
@@ -0,0 +1,50 @@
+package media
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// OutputFormat is the container of a joined output video.
+type OutputFormat string
+
+const (
+	// OutputFormatMP4 is an MP4 container with H.264/AAC (default).
+	OutputFormatMP4 OutputFormat = "mp4"
+	// OutputFormatMOV is a QuickTime container with the same codecs as MP4.
+	OutputFormatMOV OutputFormat = "mov"
+	// OutputFormatWebM is a WebM container with VP9/Opus.
+	OutputFormatWebM OutputFormat = "webm"
+)
+
+// IsValid returns true if the format is supported.
+func (f OutputFormat) IsValid() bool {
+	return f == OutputFormatMP4 || f == OutputFormatMOV || f == OutputFormatWebM
+}
+
+// Extension returns the file extension of the format, including the dot.
+func (f OutputFormat) Extension() string {
+	return "." + string(f)
+}
+
+// ContentType returns the MIME type of videos in the format.
+func (f OutputFormat) ContentType() string {
+	switch f {
+	case OutputFormatMOV:
+		return "video/quicktime"
+	case OutputFormatWebM:
+		return "video/webm"
+	default:
+		return "video/mp4"
+	}
+}
+
+// OutputFormatFromPath returns the format matching the extension of path,
+// or OutputFormatMP4 when the extension is not a supported format.
+func OutputFormatFromPath(path string) OutputFormat {
+	f := OutputFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), "."))
+	if !f.IsValid() {
+		return OutputFormatMP4
+	}
+	return f
+}
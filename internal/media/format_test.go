@@ -0,0 +1,45 @@
+package media
+
+import "testing"
+
+func TestOutputFormat(t *testing.T) {
+	tests := []struct {
+		format      OutputFormat
+		valid       bool
+		extension   string
+		contentType string
+	}{
+		{OutputFormatMP4, true, ".mp4", "video/mp4"},
+		{OutputFormatMOV, true, ".mov", "video/quicktime"},
+		{OutputFormatWebM, true, ".webm", "video/webm"},
+		{"avi", false, ".avi", "video/mp4"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.format.IsValid(); got != tt.valid {
+			t.Errorf("%s: expected IsValid %v, got %v", tt.format, tt.valid, got)
+		}
+		if got := tt.format.Extension(); got != tt.extension {
+			t.Errorf("%s: expected extension %q, got %q", tt.format, tt.extension, got)
+		}
+		if got := tt.format.ContentType(); got != tt.contentType {
+			t.Errorf("%s: expected content type %q, got %q", tt.format, tt.contentType, got)
+		}
+	}
+}
+
+func TestOutputFormatFromPath(t *testing.T) {
+	tests := map[string]OutputFormat{
+		"/tmp/output_job-1.mp4":   OutputFormatMP4,
+		"/tmp/output_job-1.MOV":   OutputFormatMOV,
+		"videos/job-1.webm":       OutputFormatWebM,
+		"/tmp/output_job-1.avi":   OutputFormatMP4,
+		"/tmp/output_without_ext": OutputFormatMP4,
+	}
+
+	for path, want := range tests {
+		if got := OutputFormatFromPath(path); got != want {
+			t.Errorf("%s: expected %s, got %s", path, want, got)
+		}
+	}
+}
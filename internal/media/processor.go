@@ -7,12 +7,116 @@ import "context"
 // Implementations should use ffmpeg or similar tools for media manipulation.
 type Processor interface {
 	// ResizeImageWithPadding resizes an image to the specified dimensions while
-	// maintaining aspect ratio. Black padding is added to fill any remaining space.
+	// maintaining aspect ratio. Padding (black by default) fills any remaining space.
 	// The source image is read from src and the result is written to dst.
 	ResizeImageWithPadding(ctx context.Context, src, dst string, w, h int) error
 
+	// ResizeImageCropToFill scales an image to cover the specified dimensions
+	// while maintaining aspect ratio, then center-crops the overflow so the
+	// result fills the frame without padding.
+	ResizeImageCropToFill(ctx context.Context, src, dst string, w, h int) error
+
+	// ResizeImageToPNG resizes an image using the given mode and returns the
+	// result as PNG bytes without writing it to disk.
+	ResizeImageToPNG(ctx context.Context, src string, w, h int, mode ResizeMode) ([]byte, error)
+
+	// NormalizeImage converts the first frame of the image in src to a PNG
+	// written to dst, for formats providers do not accept (e.g. WEBP or
+	// animated GIF). Returns ErrUnsupportedImageFormat if src cannot be decoded.
+	NormalizeImage(ctx context.Context, src, dst string) error
+
 	// JoinVideos concatenates multiple video files into a single output file.
 	// It first attempts a fast copy (no re-encoding) and falls back to re-encoding
-	// with libx264/aac if the copy fails due to incompatible codecs.
+	// (libx264/aac by default) if the copy fails due to incompatible codecs.
+	// The output container is chosen from the extension of output.
 	JoinVideos(ctx context.Context, videoPaths []string, output string) error
+
+	// ReplaceAudio muxes the video stream of video with the audio stream of
+	// audio into output, replacing the audio video carried.
+	ReplaceAudio(ctx context.Context, video, audio, output string) error
+
+	// ResizeVideo re-encodes the video in src to exactly w x h, fitting the
+	// frames with mode, and writes it to dst.
+	ResizeVideo(ctx context.Context, src, dst string, w, h int, mode ResizeMode) error
+
+	// NormalizeFPS re-encodes the video in src to a constant fps frame rate
+	// and writes it to dst, so that chunks can be joined without re-encoding.
+	NormalizeFPS(ctx context.Context, src, dst string, fps float64) error
+
+	// ExtractFirstFrame returns the first frame of a video as PNG bytes.
+	ExtractFirstFrame(ctx context.Context, videoPath string) ([]byte, error)
+
+	// ExtractLastFrame returns the last frame of a video as PNG bytes.
+	// It is used to chain frames across chunks for visual continuity.
+	ExtractLastFrame(ctx context.Context, videoPath string) ([]byte, error)
+
+	// GenerateThumbnail writes a JPEG frame taken atSec seconds into the video
+	// to dst. A non-positive atSec uses the mid-point of the video.
+	GenerateThumbnail(ctx context.Context, videoPath, dst string, atSec float64) error
+
+	// ProbeVideo returns metadata for the first video stream in path.
+	ProbeVideo(ctx context.Context, path string) (VideoInfo, error)
+
+	// ProbeImage returns the dimensions of the image in path.
+	ProbeImage(ctx context.Context, path string) (ImageInfo, error)
+
+	// ProbeAudio returns metadata for the first audio stream in path.
+	ProbeAudio(ctx context.Context, path string) (AudioInfo, error)
+}
+
+// ResizeMode selects how an image is fitted to the target dimensions.
+type ResizeMode string
+
+const (
+	// ResizeModePad letterboxes the image with black bars (default).
+	ResizeModePad ResizeMode = "pad"
+	// ResizeModeCrop crops the image so it fills the target.
+	ResizeModeCrop ResizeMode = "crop"
+)
+
+// VideoInfo describes a video file as reported by ffprobe.
+type VideoInfo struct {
+	// Width is the frame width in pixels.
+	Width int
+	// Height is the frame height in pixels.
+	Height int
+	// DurationSec is the duration in seconds.
+	DurationSec float64
+	// FPS is the average frame rate.
+	FPS float64
+	// Codec is the video codec name (e.g. "h264").
+	Codec string
+	// AudioCodec is the codec of the first audio stream (e.g. "aac"), or empty
+	// when the video has no audio.
+	AudioCodec string
+	// SizeBytes is the size of the file as reported by the container, or 0 when unknown.
+	SizeBytes int64
+}
+
+// ImageInfo describes an image file as reported by ffprobe.
+type ImageInfo struct {
+	// Width is the image width in pixels.
+	Width int
+	// Height is the image height in pixels.
+	Height int
+	// Codec is the image codec name (e.g. "png", "mjpeg").
+	Codec string
+}
+
+// IsSupportedImageCodec reports whether codec, as reported by ProbeImage, is
+// PNG or JPEG, the image formats providers accept as-is.
+func IsSupportedImageCodec(codec string) bool {
+	return codec == "png" || codec == "mjpeg"
+}
+
+// AudioInfo describes an audio file as reported by ffprobe.
+type AudioInfo struct {
+	// DurationSec is the duration in seconds.
+	DurationSec float64
+	// SampleRate is the sample rate in Hz.
+	SampleRate int
+	// Channels is the number of audio channels.
+	Channels int
+	// Codec is the audio codec name (e.g. "pcm_s16le", "mp3").
+	Codec string
 }
@@ -0,0 +1,135 @@
+package media
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestParseFFmpegVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name: "debian package",
+			output: `ffmpeg version 5.1.6-0+deb12u1 Copyright (c) 2000-2024 the FFmpeg developers
+built with gcc 12 (Debian 12.2.0-14)
+configuration: --prefix=/usr --extra-version=0+deb12u1 --toolchain=hardened
+libavutil      57. 28.100 / 57. 28.100
+`,
+			want: "5.1.6-0+deb12u1",
+		},
+		{
+			name:   "ubuntu package",
+			output: "ffmpeg version 4.4.2-0ubuntu0.22.04.1 Copyright (c) 2000-2021 the FFmpeg developers\n",
+			want:   "4.4.2-0ubuntu0.22.04.1",
+		},
+		{
+			name:   "homebrew",
+			output: "ffmpeg version 7.1 Copyright (c) 2000-2024 the FFmpeg developers\nbuilt with Apple clang version 16.0.0\n",
+			want:   "7.1",
+		},
+		{
+			name:   "arch tag",
+			output: "ffmpeg version n6.1.1 Copyright (c) 2000-2023 the FFmpeg developers\n",
+			want:   "n6.1.1",
+		},
+		{
+			name:   "static git build",
+			output: "ffmpeg version N-113542-g1234abcd-static https://johnvansickle.com/ffmpeg/  Copyright (c) 2000-2024 the FFmpeg developers\n",
+			want:   "N-113542-g1234abcd-static",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFFmpegVersion(tt.output)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseFFmpegVersion_Unknown(t *testing.T) {
+	for _, output := range []string{"", "ffprobe version 6.0\n", "avconv version 12\n"} {
+		if _, err := parseFFmpegVersion(output); !errors.Is(err, ErrFFmpegVersionUnknown) {
+			t.Errorf("%q: expected ErrFFmpegVersionUnknown, got %v", output, err)
+		}
+	}
+}
+
+func TestCheckMinVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		tooOld  bool
+	}{
+		{"4.3", false},
+		{"4.4.2-0ubuntu0.22.04.1", false},
+		{"5.1.6-0+deb12u1", false},
+		{"n6.1.1", false},
+		{"10.0", false},
+		{"4.2.7-0ubuntu0.1", true},
+		{"3.4.11", true},
+		{"N-113542-g1234abcd-static", false},
+	}
+
+	for _, tt := range tests {
+		err := checkMinVersion(tt.version, MinFFmpegVersion)
+		if got := errors.Is(err, ErrFFmpegTooOld); got != tt.tooOld {
+			t.Errorf("%s: expected too old %v, got %v", tt.version, tt.tooOld, err)
+		}
+	}
+}
+
+func TestCheckFFmpeg(t *testing.T) {
+	t.Run("missing binary", func(t *testing.T) {
+		if _, err := CheckFFmpeg("/nonexistent/ffmpeg"); !errors.Is(err, ErrFFmpegUnavailable) {
+			t.Errorf("expected ErrFFmpegUnavailable, got %v", err)
+		}
+	})
+
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ffmpeg is a shell script")
+	}
+
+	// fakeFFmpeg writes a script that prints the first line of ffmpeg -version.
+	fakeFFmpeg := func(t *testing.T, firstLine string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "ffmpeg")
+		script := "#!/bin/sh\necho '" + firstLine + "'\n"
+		if err := os.WriteFile(path, []byte(script), 0o755); err != nil { // #nosec G306 - test executable
+			t.Fatalf("write fake ffmpeg: %v", err)
+		}
+		return path
+	}
+
+	t.Run("supported version", func(t *testing.T) {
+		path := fakeFFmpeg(t, "ffmpeg version 6.1.1-3ubuntu5 Copyright (c) 2000-2023 the FFmpeg developers")
+		version, err := CheckFFmpeg(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if version != "6.1.1-3ubuntu5" {
+			t.Errorf("expected version 6.1.1-3ubuntu5, got %q", version)
+		}
+	})
+
+	t.Run("too old", func(t *testing.T) {
+		path := fakeFFmpeg(t, "ffmpeg version 4.2.7 Copyright (c) 2000-2022 the FFmpeg developers")
+		version, err := CheckFFmpeg(path)
+		if !errors.Is(err, ErrFFmpegTooOld) {
+			t.Errorf("expected ErrFFmpegTooOld, got %v", err)
+		}
+		if version != "4.2.7" {
+			t.Errorf("expected the version to be reported, got %q", version)
+		}
+	})
+}
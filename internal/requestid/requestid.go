@@ -0,0 +1,34 @@
+// Package requestid carries a per-request correlation ID through contexts
+// so that logs from an HTTP request and its background work can be matched.
+package requestid
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Header is the HTTP header used to receive and echo the request ID.
+const Header = "X-Request-ID"
+
+// contextKey is the unexported type for the request ID context key.
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the request ID.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID stored in ctx, or "" if there is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Logger returns logger with a request_id attribute when ctx carries a
+// request ID, and logger unchanged otherwise.
+func Logger(ctx context.Context, logger *slog.Logger) *slog.Logger {
+	if id := FromContext(ctx); id != "" {
+		return logger.With(slog.String("request_id", id))
+	}
+	return logger
+}
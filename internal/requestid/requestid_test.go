@@ -0,0 +1,44 @@
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != "" {
+		t.Errorf("expected empty request ID, got %q", got)
+	}
+
+	ctx := NewContext(context.Background(), "req-123")
+	if got := FromContext(ctx); got != "req-123" {
+		t.Errorf("expected req-123, got %q", got)
+	}
+
+	// Detached contexts keep their values
+	if got := FromContext(context.WithoutCancel(ctx)); got != "req-123" {
+		t.Errorf("expected req-123 after WithoutCancel, got %q", got)
+	}
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	Logger(NewContext(context.Background(), "req-123"), logger).Info("with id")
+	Logger(context.Background(), logger).Info("without id")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d", len(lines))
+	}
+	if !strings.Contains(lines[0], "request_id=req-123") {
+		t.Errorf("expected request_id attribute, got %q", lines[0])
+	}
+	if strings.Contains(lines[1], "request_id") {
+		t.Errorf("expected no request_id attribute, got %q", lines[1])
+	}
+}
@@ -0,0 +1,190 @@
+package runpod
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrProviderUnavailable is returned without calling RunPod while the circuit breaker is open.
+var ErrProviderUnavailable = errors.New("runpod: provider unavailable")
+
+// Circuit breaker defaults used when no threshold or cooldown is configured.
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// BreakerState is the state of a CircuitBreaker.
+type BreakerState string
+
+// Circuit breaker states.
+const (
+	// BreakerClosed lets every request through.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen fails every request with ErrProviderUnavailable.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single trial request through to decide whether to close again.
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// CircuitBreaker is a Client that stops calling RunPod after repeated
+// failures. It opens after threshold consecutive provider failures and then
+// fails fast with ErrProviderUnavailable; once cooldown has passed it lets one
+// trial request through, closing again if it succeeds and reopening otherwise.
+//
+// Only failures that point at RunPod itself count: server errors, rate
+// limiting and transport errors. Rejected requests and cancellations by the
+// caller do not.
+type CircuitBreaker struct {
+	next      Client
+	threshold int
+	cooldown  time.Duration
+	// now returns the current time; replaced in tests.
+	now func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	// trial is set while the half-open trial request is in flight.
+	trial bool
+}
+
+// BreakerOption is a function that configures a CircuitBreaker.
+type BreakerOption func(*CircuitBreaker)
+
+// WithBreakerThreshold sets how many consecutive failures open the breaker. Values < 1 are ignored.
+func WithBreakerThreshold(n int) BreakerOption {
+	return func(b *CircuitBreaker) {
+		if n > 0 {
+			b.threshold = n
+		}
+	}
+}
+
+// WithBreakerCooldown sets how long the breaker stays open before a trial request. Values <= 0 are ignored.
+func WithBreakerCooldown(d time.Duration) BreakerOption {
+	return func(b *CircuitBreaker) {
+		if d > 0 {
+			b.cooldown = d
+		}
+	}
+}
+
+// NewCircuitBreaker wraps next in a closed circuit breaker.
+func NewCircuitBreaker(next Client, opts ...BreakerOption) *CircuitBreaker {
+	b := &CircuitBreaker{
+		next:      next,
+		threshold: DefaultBreakerThreshold,
+		cooldown:  DefaultBreakerCooldown,
+		now:       time.Now,
+		state:     BreakerClosed,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// State returns the current state of the breaker.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// Submit sends a job to RunPod unless the breaker is open.
+func (b *CircuitBreaker) Submit(ctx context.Context, imageB64, audioB64 string, opts SubmitOptions) (string, error) {
+	if err := b.allow(); err != nil {
+		return "", err
+	}
+	jobID, err := b.next.Submit(ctx, imageB64, audioB64, opts)
+	b.record(ctx, err)
+	return jobID, err
+}
+
+// Poll checks the status of a job unless the breaker is open.
+func (b *CircuitBreaker) Poll(ctx context.Context, jobID string) (PollResult, error) {
+	if err := b.allow(); err != nil {
+		return PollResult{}, err
+	}
+	result, err := b.next.Poll(ctx, jobID)
+	b.record(ctx, err)
+	return result, err
+}
+
+// Cancel requests cancellation of a job unless the breaker is open.
+func (b *CircuitBreaker) Cancel(ctx context.Context, jobID string) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+	err := b.next.Cancel(ctx, jobID)
+	b.record(ctx, err)
+	return err
+}
+
+// Ping checks that RunPod is reachable. It bypasses the breaker and does not
+// count towards it, so a readiness probe reports RunPod's own state and can
+// see it recover while the breaker is still open.
+func (b *CircuitBreaker) Ping(ctx context.Context) error {
+	return b.next.Ping(ctx)
+}
+
+// allow reports whether a request may be sent, moving an open breaker whose
+// cooldown has passed to half-open and admitting a single trial request.
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return ErrProviderUnavailable
+		}
+		b.state = BreakerHalfOpen
+	case BreakerHalfOpen:
+	default:
+		return nil
+	}
+
+	if b.trial {
+		return ErrProviderUnavailable
+	}
+	b.trial = true
+	return nil
+}
+
+// record updates the breaker with the outcome of a request sent with ctx.
+func (b *CircuitBreaker) record(ctx context.Context, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	halfOpen := b.state == BreakerHalfOpen
+	b.trial = false
+
+	switch {
+	case err != nil && ctx.Err() != nil:
+		// The caller gave up; this says nothing about RunPod.
+	case isProviderFailure(err):
+		b.failures++
+		if halfOpen || b.failures >= b.threshold {
+			b.state = BreakerOpen
+			b.openedAt = b.now()
+		}
+	default:
+		b.state = BreakerClosed
+		b.failures = 0
+	}
+}
+
+// isProviderFailure reports whether err means RunPod is failing rather than
+// the request being rejected.
+func isProviderFailure(err error) bool {
+	return err != nil && (isRetryable(err) || errors.Is(err, ErrServerError) || errors.Is(err, ErrRateLimited))
+}
@@ -0,0 +1,198 @@
+package runpod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// flakyClient is a Client whose calls fail with err while it is set.
+type flakyClient struct {
+	err   error
+	calls int
+}
+
+func (c *flakyClient) Submit(context.Context, string, string, SubmitOptions) (string, error) {
+	c.calls++
+	if c.err != nil {
+		return "", c.err
+	}
+	return "job-1", nil
+}
+
+func (c *flakyClient) Poll(context.Context, string) (PollResult, error) {
+	c.calls++
+	if c.err != nil {
+		return PollResult{}, c.err
+	}
+	return PollResult{Status: StatusCompleted}, nil
+}
+
+func (c *flakyClient) Cancel(context.Context, string) error {
+	c.calls++
+	return c.err
+}
+
+func (c *flakyClient) Ping(context.Context) error {
+	c.calls++
+	return c.err
+}
+
+// newTestBreaker returns a breaker around inner with a threshold of 3 and a
+// cooldown of one minute, and a function that advances its clock.
+func newTestBreaker(inner Client) (*CircuitBreaker, func(time.Duration)) {
+	b := NewCircuitBreaker(inner, WithBreakerThreshold(3), WithBreakerCooldown(time.Minute))
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b.now = func() time.Time { return now }
+	return b, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestCircuitBreaker_Transitions(t *testing.T) {
+	inner := &flakyClient{err: fmt.Errorf("runpod: max retries exceeded: %w", ErrServerError)}
+	b, advance := newTestBreaker(inner)
+	ctx := context.Background()
+
+	// closed: failures below the threshold are passed through
+	for i := range 3 {
+		if b.State() != BreakerClosed {
+			t.Fatalf("failure %d: expected closed, got %s", i, b.State())
+		}
+		if _, err := b.Submit(ctx, "img", "audio", SubmitOptions{}); !errors.Is(err, ErrServerError) {
+			t.Fatalf("failure %d: expected ErrServerError, got %v", i, err)
+		}
+	}
+
+	// open: requests fail fast without reaching RunPod
+	if b.State() != BreakerOpen {
+		t.Fatalf("expected open after 3 failures, got %s", b.State())
+	}
+	if _, err := b.Poll(ctx, "job-1"); !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("expected ErrProviderUnavailable, got %v", err)
+	}
+	if inner.calls != 3 {
+		t.Fatalf("expected 3 calls to RunPod, got %d", inner.calls)
+	}
+
+	// half-open: a failed trial reopens the breaker for another cooldown
+	advance(time.Minute)
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("expected half-open after the cooldown, got %s", b.State())
+	}
+	if _, err := b.Poll(ctx, "job-1"); !errors.Is(err, ErrServerError) {
+		t.Fatalf("expected the trial to reach RunPod, got %v", err)
+	}
+	if b.State() != BreakerOpen {
+		t.Fatalf("expected open after a failed trial, got %s", b.State())
+	}
+	if err := b.Cancel(ctx, "job-1"); !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("expected ErrProviderUnavailable, got %v", err)
+	}
+
+	// half-open: a successful trial closes the breaker
+	inner.err = nil
+	advance(time.Minute)
+	result, err := b.Poll(ctx, "job-1")
+	if err != nil || result.Status != StatusCompleted {
+		t.Fatalf("expected the trial to succeed, got %v, %v", result, err)
+	}
+	if b.State() != BreakerClosed {
+		t.Fatalf("expected closed after a successful trial, got %s", b.State())
+	}
+	if _, err := b.Submit(ctx, "img", "audio", SubmitOptions{}); err != nil {
+		t.Fatalf("expected requests to pass once closed, got %v", err)
+	}
+	if inner.calls != 6 {
+		t.Errorf("expected 6 calls to RunPod, got %d", inner.calls)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenAllowsSingleTrial(t *testing.T) {
+	inner := &flakyClient{err: ErrRateLimited}
+	b, advance := newTestBreaker(inner)
+	ctx := context.Background()
+
+	for range 3 {
+		_, _ = b.Poll(ctx, "job-1")
+	}
+	advance(time.Minute)
+
+	// Claim the trial without finishing it.
+	if err := b.allow(); err != nil {
+		t.Fatalf("expected the trial to be allowed, got %v", err)
+	}
+	if _, err := b.Poll(ctx, "job-1"); !errors.Is(err, ErrProviderUnavailable) {
+		t.Errorf("expected a second request to fail fast while the trial is in flight, got %v", err)
+	}
+}
+
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	inner := &flakyClient{err: &retryableError{err: errors.New("connection refused")}}
+	b, _ := newTestBreaker(inner)
+	ctx := context.Background()
+
+	for range 2 {
+		_, _ = b.Poll(ctx, "job-1")
+	}
+	inner.err = nil
+	_, _ = b.Poll(ctx, "job-1")
+	inner.err = ErrServerError
+	for range 2 {
+		_, _ = b.Poll(ctx, "job-1")
+	}
+
+	if b.State() != BreakerClosed {
+		t.Errorf("expected failures to be counted from the last success, got %s", b.State())
+	}
+}
+
+func TestCircuitBreaker_IgnoresNonProviderFailures(t *testing.T) {
+	inner := &flakyClient{err: fmt.Errorf("%w with status 400: bad input", ErrRequestFailed)}
+	b, _ := newTestBreaker(inner)
+
+	for range 5 {
+		_, _ = b.Submit(context.Background(), "img", "audio", SubmitOptions{})
+	}
+	if b.State() != BreakerClosed {
+		t.Errorf("expected rejected requests not to open the breaker, got %s", b.State())
+	}
+
+	inner.err = ErrServerError
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for range 5 {
+		_, _ = b.Poll(ctx, "job-1")
+	}
+	if b.State() != BreakerClosed {
+		t.Errorf("expected cancelled requests not to open the breaker, got %s", b.State())
+	}
+}
+
+func TestCircuitBreaker_PingBypassesBreaker(t *testing.T) {
+	inner := &flakyClient{err: fmt.Errorf("runpod: health check: %w", ErrServerError)}
+	b, _ := newTestBreaker(inner)
+	ctx := context.Background()
+
+	// Failed pings do not open the breaker
+	for range 3 {
+		if err := b.Ping(ctx); !errors.Is(err, ErrServerError) {
+			t.Fatalf("expected ErrServerError, got %v", err)
+		}
+	}
+	if b.State() != BreakerClosed {
+		t.Fatalf("expected closed, got %s", b.State())
+	}
+
+	// An open breaker still lets pings through
+	for range 3 {
+		_, _ = b.Submit(ctx, "img", "audio", SubmitOptions{})
+	}
+	inner.err = nil
+	if err := b.Ping(ctx); err != nil {
+		t.Errorf("expected ping to reach RunPod while open, got %v", err)
+	}
+	if b.State() != BreakerOpen {
+		t.Errorf("expected a ping to leave the breaker open, got %s", b.State())
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
 	"os"
 	"time"
@@ -32,6 +33,16 @@ var (
 	ErrRequestFailed = errors.New("runpod: request failed")
 )
 
+// DefaultMaxBackoff is the longest delay between retries when no maximum is configured.
+const DefaultMaxBackoff = 30 * time.Second
+
+// Default per-request timeouts. Submit uploads the base64 inputs, so it gets
+// far longer than a status poll.
+const (
+	DefaultSubmitTimeout = 2 * time.Minute
+	DefaultPollTimeout   = 30 * time.Second
+)
+
 // Client defines the interface for interacting with the RunPod API.
 type Client interface {
 	// Submit sends a lip-sync job to RunPod and returns the job ID.
@@ -39,6 +50,12 @@ type Client interface {
 
 	// Poll checks the status of a job and returns the result.
 	Poll(ctx context.Context, jobID string) (PollResult, error)
+
+	// Cancel requests cancellation of a queued or running job.
+	Cancel(ctx context.Context, jobID string) error
+
+	// Ping checks that the endpoint is reachable and the API key is accepted.
+	Ping(ctx context.Context) error
 }
 
 // HTTPClient is the HTTP implementation of the RunPod Client interface.
@@ -49,6 +66,16 @@ type HTTPClient struct {
 	httpClient  *http.Client
 	maxRetries  int
 	baseBackoff time.Duration
+	// maxBackoff caps the delay between retries before jitter is applied.
+	maxBackoff time.Duration
+	// jitter randomizes a retry delay; replaced in tests.
+	jitter func(time.Duration) time.Duration
+	// submitTimeout bounds each submit request; pollTimeout bounds each
+	// status and cancel request.
+	submitTimeout time.Duration
+	pollTimeout   time.Duration
+	// pollCache shares recent Poll results per job. Nil disables it.
+	pollCache *pollCache
 }
 
 // ClientOption is a function that configures an HTTPClient.
@@ -89,6 +116,42 @@ func WithBaseBackoff(d time.Duration) ClientOption {
 	}
 }
 
+// WithMaxBackoff caps the backoff between retries, which otherwise doubles
+// on every attempt. Values <= 0 keep DefaultMaxBackoff.
+func WithMaxBackoff(d time.Duration) ClientOption {
+	return func(hc *HTTPClient) {
+		if d > 0 {
+			hc.maxBackoff = d
+		}
+	}
+}
+
+// WithRequestTimeout sets how long a single submit and a single poll (or
+// cancel) request may take. Each attempt gets its own deadline, so a slow
+// submit never eats into the time of a poll. Values <= 0 keep the defaults.
+func WithRequestTimeout(submit, poll time.Duration) ClientOption {
+	return func(hc *HTTPClient) {
+		if submit > 0 {
+			hc.submitTimeout = submit
+		}
+		if poll > 0 {
+			hc.pollTimeout = poll
+		}
+	}
+}
+
+// WithPollCacheTTL makes polls of the same job within ttl of each other share
+// one status request, as do polls made while another is in flight. Values
+// <= 0 disable the cache, so every Poll calls RunPod.
+func WithPollCacheTTL(ttl time.Duration) ClientOption {
+	return func(hc *HTTPClient) {
+		hc.pollCache = nil
+		if ttl > 0 {
+			hc.pollCache = newPollCache(ttl)
+		}
+	}
+}
+
 // NewClient creates a new RunPod HTTP client.
 // The API key can be set via the WithAPIKey option. If not provided,
 // it is read from the environment variable RUNPOD_API_KEY.
@@ -101,9 +164,14 @@ func NewClient(endpointID string, opts ...ClientOption) (*HTTPClient, error) {
 	c := &HTTPClient{
 		endpointID:  endpointID,
 		baseURL:     "https://api.runpod.ai/v2",
-		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		httpClient:  &http.Client{},
 		maxRetries:  3,
 		baseBackoff: 1 * time.Second,
+		maxBackoff:  DefaultMaxBackoff,
+		jitter:      equalJitter,
+
+		submitTimeout: DefaultSubmitTimeout,
+		pollTimeout:   DefaultPollTimeout,
 	}
 
 	// Apply options first to allow WithAPIKey to set the API key
@@ -124,6 +192,7 @@ func NewClient(endpointID string, opts ...ClientOption) (*HTTPClient, error) {
 }
 
 // Submit sends a lip-sync job to RunPod and returns the job ID.
+// imageB64 holds the source video instead when opts.InputType is "video".
 func (c *HTTPClient) Submit(ctx context.Context, imageB64, audioB64 string, opts SubmitOptions) (string, error) {
 	// Apply defaults if not set
 	if opts.InputType == "" {
@@ -141,7 +210,6 @@ func (c *HTTPClient) Submit(ctx context.Context, imageB64, audioB64 string, opts
 			InputType:     opts.InputType,
 			PersonCount:   opts.PersonCount,
 			Prompt:        opts.Prompt,
-			ImageBase64:   imageB64,
 			WavBase64:     audioB64,
 			Width:         opts.Width,
 			Height:        opts.Height,
@@ -150,6 +218,13 @@ func (c *HTTPClient) Submit(ctx context.Context, imageB64, audioB64 string, opts
 		},
 	}
 
+	// The source media travels in the field matching its input type
+	if opts.InputType == "video" {
+		reqBody.Input.VideoBase64 = imageB64
+	} else {
+		reqBody.Input.ImageBase64 = imageB64
+	}
+
 	bodyBytes, err := json.Marshal(reqBody)
 	if err != nil {
 		return "", fmt.Errorf("runpod: marshal request: %w", err)
@@ -158,7 +233,7 @@ func (c *HTTPClient) Submit(ctx context.Context, imageB64, audioB64 string, opts
 	url := fmt.Sprintf("%s/%s/run", c.baseURL, c.endpointID)
 
 	var resp runResponse
-	if err := c.doRequestWithRetry(ctx, http.MethodPost, url, bodyBytes, &resp); err != nil {
+	if err := c.doRequestWithRetry(ctx, http.MethodPost, url, bodyBytes, &resp, c.submitTimeout); err != nil {
 		return "", err
 	}
 
@@ -177,11 +252,20 @@ func (c *HTTPClient) Poll(ctx context.Context, jobID string) (PollResult, error)
 	if jobID == "" {
 		return PollResult{}, ErrJobIDRequired
 	}
+	if c.pollCache == nil {
+		return c.poll(ctx, jobID)
+	}
+	return c.pollCache.get(ctx, jobID, func(ctx context.Context) (PollResult, error) {
+		return c.poll(ctx, jobID)
+	})
+}
 
+// poll requests the status of a job from RunPod.
+func (c *HTTPClient) poll(ctx context.Context, jobID string) (PollResult, error) {
 	url := fmt.Sprintf("%s/%s/status/%s", c.baseURL, c.endpointID, jobID)
 
 	var resp statusResponse
-	if err := c.doRequestWithRetry(ctx, http.MethodGet, url, nil, &resp); err != nil {
+	if err := c.doRequestWithRetry(ctx, http.MethodGet, url, nil, &resp, c.pollTimeout); err != nil {
 		return PollResult{}, err
 	}
 
@@ -219,22 +303,43 @@ func (c *HTTPClient) Poll(ctx context.Context, jobID string) (PollResult, error)
 	return result, nil
 }
 
+// Cancel requests cancellation of a queued or running job.
+// Cancellation is best-effort: RunPod may take time to stop the worker.
+func (c *HTTPClient) Cancel(ctx context.Context, jobID string) error {
+	if jobID == "" {
+		return ErrJobIDRequired
+	}
+
+	url := fmt.Sprintf("%s/%s/cancel/%s", c.baseURL, c.endpointID, jobID)
+	return c.doRequestWithRetry(ctx, http.MethodPost, url, nil, nil, c.pollTimeout)
+}
+
+// Ping requests the health of the endpoint, which reports its workers and
+// queue without running a job. It makes a single attempt so that a readiness
+// probe reflects the current state instead of waiting out the retries.
+func (c *HTTPClient) Ping(ctx context.Context) error {
+	url := fmt.Sprintf("%s/%s/health", c.baseURL, c.endpointID)
+	if err := c.doRequest(ctx, http.MethodGet, url, nil, nil, c.pollTimeout); err != nil {
+		return fmt.Errorf("runpod: health check: %w", err)
+	}
+	return nil
+}
+
 // doRequestWithRetry performs an HTTP request with exponential backoff retry.
-func (c *HTTPClient) doRequestWithRetry(ctx context.Context, method, url string, body []byte, result interface{}) error {
+// Each attempt is bounded by timeout; cancelling ctx stops all attempts.
+func (c *HTTPClient) doRequestWithRetry(ctx context.Context, method, url string, body []byte, result interface{}, timeout time.Duration) error {
 	var lastErr error
-	backoff := c.baseBackoff
 
 	for attempt := 0; attempt <= c.maxRetries; attempt++ {
 		if attempt > 0 {
 			select {
 			case <-ctx.Done():
 				return fmt.Errorf("runpod: context cancelled: %w", ctx.Err())
-			case <-time.After(backoff):
-				backoff *= 2 // Exponential backoff
+			case <-time.After(c.retryDelay(attempt)):
 			}
 		}
 
-		err := c.doRequest(ctx, method, url, body, result)
+		err := c.doRequest(ctx, method, url, body, result, timeout)
 		if err == nil {
 			return nil
 		}
@@ -250,8 +355,35 @@ func (c *HTTPClient) doRequestWithRetry(ctx context.Context, method, url string,
 	return fmt.Errorf("runpod: max retries exceeded: %w", lastErr)
 }
 
-// doRequest performs a single HTTP request.
-func (c *HTTPClient) doRequest(ctx context.Context, method, url string, body []byte, result interface{}) error {
+// retryDelay returns how long to wait before retry attempt (1-based): the
+// base backoff doubled for every earlier retry, capped at the maximum backoff
+// and jittered so that requests failing together do not retry in lockstep.
+func (c *HTTPClient) retryDelay(attempt int) time.Duration {
+	delay := c.baseBackoff
+	for i := 1; i < attempt && delay < c.maxBackoff; i++ {
+		delay *= 2
+	}
+	return c.jitter(min(delay, c.maxBackoff))
+}
+
+// equalJitter returns a random duration between d/2 and d, which keeps
+// retries spread out while still backing off by at least half of d.
+func equalJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half+1) // #nosec G404 - jitter does not need a secure source
+}
+
+// doRequest performs a single HTTP request that must finish within timeout.
+func (c *HTTPClient) doRequest(ctx context.Context, method, url string, body []byte, result interface{}, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	var bodyReader io.Reader
 	if body != nil {
 		bodyReader = bytes.NewReader(body)
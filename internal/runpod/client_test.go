@@ -3,6 +3,8 @@ package runpod
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -284,6 +286,119 @@ func TestPoll_AllStatuses(t *testing.T) {
 	}
 }
 
+func TestCancel_Success(t *testing.T) {
+	setTestEnv(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Verify request
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if r.URL.Path != "/test-endpoint/cancel/job-123" {
+			t.Errorf("expected /test-endpoint/cancel/job-123, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("expected Bearer test-key, got %s", r.Header.Get("Authorization"))
+		}
+
+		_ = json.NewEncoder(w).Encode(statusResponse{ID: "job-123", Status: "CANCELLED"})
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("test-endpoint", WithBaseURL(server.URL))
+
+	if err := client.Cancel(context.Background(), "job-123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCancel_Error(t *testing.T) {
+	setTestEnv(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":"job not found"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("test-endpoint", WithBaseURL(server.URL))
+
+	err := client.Cancel(context.Background(), "job-123")
+	if !errors.Is(err, ErrRequestFailed) {
+		t.Errorf("expected ErrRequestFailed, got %v", err)
+	}
+}
+
+func TestPing_Healthy(t *testing.T) {
+	setTestEnv(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		if r.URL.Path != "/test-endpoint/health" {
+			t.Errorf("expected /test-endpoint/health, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("expected Bearer test-key, got %s", r.Header.Get("Authorization"))
+		}
+		_, _ = w.Write([]byte(`{"jobs":{"inQueue":0,"inProgress":1},"workers":{"idle":1,"running":1}}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("test-endpoint", WithBaseURL(server.URL))
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPing_Unhealthy(t *testing.T) {
+	setTestEnv(t)
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("test-endpoint", WithBaseURL(server.URL), WithBaseBackoff(time.Millisecond))
+
+	if err := client.Ping(context.Background()); !errors.Is(err, ErrServerError) {
+		t.Errorf("expected ErrServerError, got %v", err)
+	}
+	// A ping is not retried
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("expected 1 request, got %d", got)
+	}
+}
+
+func TestPing_Unauthorized(t *testing.T) {
+	setTestEnv(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("test-endpoint", WithBaseURL(server.URL))
+
+	if err := client.Ping(context.Background()); !errors.Is(err, ErrRequestFailed) {
+		t.Errorf("expected ErrRequestFailed, got %v", err)
+	}
+}
+
+func TestCancel_EmptyJobID(t *testing.T) {
+	setTestEnv(t)
+
+	client, _ := NewClient("test-endpoint")
+
+	if err := client.Cancel(context.Background(), ""); !errors.Is(err, ErrJobIDRequired) {
+		t.Errorf("expected ErrJobIDRequired, got %v", err)
+	}
+}
+
 func TestPoll_EmptyJobID(t *testing.T) {
 	setTestEnv(t)
 
@@ -453,3 +568,207 @@ func TestSubmit_DefaultOptions(t *testing.T) {
 		t.Errorf("expected default Prompt 'high quality, realistic, speaking naturally', got %q", receivedReq.Input.Prompt)
 	}
 }
+
+func TestSubmit_VideoInput(t *testing.T) {
+	setTestEnv(t)
+
+	var raw map[string]map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&raw)
+		_ = json.NewEncoder(w).Encode(runResponse{ID: "job-123"})
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("test-endpoint", WithBaseURL(server.URL))
+
+	_, err := client.Submit(context.Background(), "video-data", "audio", SubmitOptions{InputType: "video"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	input := raw["input"]
+	if input["input_type"] != "video" {
+		t.Errorf("expected input_type 'video', got %v", input["input_type"])
+	}
+	if input["video_base64"] != "video-data" {
+		t.Errorf("expected video_base64 'video-data', got %v", input["video_base64"])
+	}
+	if _, ok := input["image_base64"]; ok {
+		t.Errorf("expected no image_base64 for video input, got %v", input["image_base64"])
+	}
+}
+
+// slowServer serves /run after submitDelay and /status after pollDelay,
+// returning early when the client gives up on the request.
+func slowServer(t *testing.T, submitDelay, pollDelay time.Duration) *httptest.Server {
+	t.Helper()
+	wait := func(r *http.Request, d time.Duration) bool {
+		// The server only notices a client hang-up once the body is consumed
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-time.After(d):
+			return true
+		case <-r.Context().Done():
+			return false
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /test-endpoint/run", func(w http.ResponseWriter, r *http.Request) {
+		if wait(r, submitDelay) {
+			_ = json.NewEncoder(w).Encode(runResponse{ID: "job-123"})
+		}
+	})
+	mux.HandleFunc("GET /test-endpoint/status/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if wait(r, pollDelay) {
+			_ = json.NewEncoder(w).Encode(statusResponse{Status: "IN_PROGRESS"})
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWithRequestTimeout(t *testing.T) {
+	setTestEnv(t)
+
+	client, _ := NewClient("test-endpoint", WithRequestTimeout(time.Minute, 0))
+	if client.submitTimeout != time.Minute {
+		t.Errorf("expected submit timeout 1m, got %v", client.submitTimeout)
+	}
+	if client.pollTimeout != DefaultPollTimeout {
+		t.Errorf("expected default poll timeout, got %v", client.pollTimeout)
+	}
+}
+
+func TestRequestTimeout_SubmitTimesOut(t *testing.T) {
+	setTestEnv(t)
+	server := slowServer(t, 300*time.Millisecond, 100*time.Millisecond)
+
+	client, _ := NewClient("test-endpoint",
+		WithBaseURL(server.URL),
+		WithMaxRetries(0),
+		WithRequestTimeout(50*time.Millisecond, time.Second),
+	)
+
+	_, err := client.Submit(context.Background(), "image-data", "audio-data", DefaultSubmitOptions())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected submit to time out, got %v", err)
+	}
+
+	// A poll slower than the submit timeout still succeeds
+	result, err := client.Poll(context.Background(), "job-123")
+	if err != nil {
+		t.Fatalf("expected poll to succeed, got %v", err)
+	}
+	if result.Status != StatusInProgress {
+		t.Errorf("expected IN_PROGRESS, got %s", result.Status)
+	}
+}
+
+func TestRequestTimeout_PollTimesOut(t *testing.T) {
+	setTestEnv(t)
+	server := slowServer(t, 100*time.Millisecond, 300*time.Millisecond)
+
+	client, _ := NewClient("test-endpoint",
+		WithBaseURL(server.URL),
+		WithMaxRetries(0),
+		WithRequestTimeout(time.Second, 50*time.Millisecond),
+	)
+
+	// A submit slower than the poll timeout still succeeds
+	jobID, err := client.Submit(context.Background(), "image-data", "audio-data", DefaultSubmitOptions())
+	if err != nil {
+		t.Fatalf("expected submit to succeed, got %v", err)
+	}
+
+	_, err = client.Poll(context.Background(), jobID)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected poll to time out, got %v", err)
+	}
+}
+
+func TestRequestTimeout_OuterContextCancelled(t *testing.T) {
+	setTestEnv(t)
+	server := slowServer(t, 5*time.Second, 5*time.Second)
+
+	client, _ := NewClient("test-endpoint",
+		WithBaseURL(server.URL),
+		WithRequestTimeout(time.Minute, time.Minute),
+		WithBaseBackoff(10*time.Millisecond),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := client.Submit(ctx, "image-data", "audio-data", DefaultSubmitOptions())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected submit to stop promptly after cancellation, took %v", elapsed)
+	}
+}
+
+func TestRetryDelay_DoublesUpToMaxBackoff(t *testing.T) {
+	setTestEnv(t)
+
+	client, _ := NewClient("test-endpoint",
+		WithBaseBackoff(100*time.Millisecond),
+		WithMaxBackoff(time.Second),
+	)
+	client.jitter = func(d time.Duration) time.Duration { return d }
+
+	want := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+	for i, w := range want {
+		if got := client.retryDelay(i + 1); got != w {
+			t.Errorf("attempt %d: expected delay %v, got %v", i+1, w, got)
+		}
+	}
+	// Many retries must not overflow past the cap
+	if got := client.retryDelay(100); got != time.Second {
+		t.Errorf("expected delay capped at 1s, got %v", got)
+	}
+}
+
+func TestRetryDelay_JitterStaysWithinBounds(t *testing.T) {
+	setTestEnv(t)
+
+	client, _ := NewClient("test-endpoint",
+		WithBaseBackoff(100*time.Millisecond),
+		WithMaxBackoff(time.Second),
+	)
+
+	for attempt := 1; attempt <= 6; attempt++ {
+		ceiling := min(100*time.Millisecond<<(attempt-1), time.Second)
+		seen := make(map[time.Duration]bool)
+		for range 200 {
+			got := client.retryDelay(attempt)
+			if got < ceiling/2 || got > ceiling {
+				t.Fatalf("attempt %d: delay %v outside [%v, %v]", attempt, got, ceiling/2, ceiling)
+			}
+			seen[got] = true
+		}
+		if len(seen) < 2 {
+			t.Errorf("attempt %d: expected jittered delays, got only %v", attempt, seen)
+		}
+	}
+}
+
+func TestWithMaxBackoff_IgnoresNonPositive(t *testing.T) {
+	setTestEnv(t)
+
+	client, _ := NewClient("test-endpoint", WithMaxBackoff(0))
+	if client.maxBackoff != DefaultMaxBackoff {
+		t.Errorf("expected default max backoff %v, got %v", DefaultMaxBackoff, client.maxBackoff)
+	}
+}
@@ -38,6 +38,63 @@ func (_m *MockClient) EXPECT() *MockClient_Expecter {
 	return &MockClient_Expecter{mock: &_m.Mock}
 }
 
+// Cancel provides a mock function for the type MockClient
+func (_mock *MockClient) Cancel(ctx context.Context, jobID string) error {
+	ret := _mock.Called(ctx, jobID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Cancel")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, jobID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockClient_Cancel_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Cancel'
+type MockClient_Cancel_Call struct {
+	*mock.Call
+}
+
+// Cancel is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jobID string
+func (_e *MockClient_Expecter) Cancel(ctx interface{}, jobID interface{}) *MockClient_Cancel_Call {
+	return &MockClient_Cancel_Call{Call: _e.mock.On("Cancel", ctx, jobID)}
+}
+
+func (_c *MockClient_Cancel_Call) Run(run func(ctx context.Context, jobID string)) *MockClient_Cancel_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_Cancel_Call) Return(err error) *MockClient_Cancel_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockClient_Cancel_Call) RunAndReturn(run func(ctx context.Context, jobID string) error) *MockClient_Cancel_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Poll provides a mock function for the type MockClient
 func (_mock *MockClient) Poll(ctx context.Context, jobID string) (runpod.PollResult, error) {
 	ret := _mock.Called(ctx, jobID)
@@ -0,0 +1,107 @@
+package runpod
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pollCache shares Poll results per RunPod job so that polls made within ttl
+// of each other, or while another poll of the same job is in flight, result
+// in a single status request.
+//
+// Failed polls are not cached, and neither are terminal results: the caller
+// is done polling by then and a completed result holds the whole video.
+type pollCache struct {
+	ttl time.Duration
+	// now returns the current time; replaced in tests.
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*pollEntry
+}
+
+// pollEntry is the result of one status request.
+type pollEntry struct {
+	// done is closed once result and err are set.
+	done    chan struct{}
+	result  PollResult
+	err     error
+	expires time.Time
+}
+
+// newPollCache creates a cache keeping results for ttl.
+func newPollCache(ttl time.Duration) *pollCache {
+	return &pollCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*pollEntry),
+	}
+}
+
+// get returns the cached result for jobID, waits for a poll already in
+// flight, or calls fetch and shares its result.
+func (pc *pollCache) get(ctx context.Context, jobID string, fetch func(context.Context) (PollResult, error)) (PollResult, error) {
+	for {
+		pc.mu.Lock()
+		e, ok := pc.entries[jobID]
+		if !ok {
+			break
+		}
+		select {
+		case <-e.done:
+			if pc.now().Before(e.expires) {
+				pc.mu.Unlock()
+				return e.result, nil
+			}
+			delete(pc.entries, jobID)
+			pc.mu.Unlock()
+			continue
+		default:
+		}
+		pc.mu.Unlock()
+
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			return PollResult{}, ctx.Err()
+		}
+		if e.err == nil {
+			return e.result, nil
+		}
+		// The shared poll failed, e.g. because its caller gave up; poll again.
+	}
+
+	e := &pollEntry{done: make(chan struct{})}
+	pc.entries[jobID] = e
+	pc.mu.Unlock()
+
+	result, err := fetch(ctx)
+
+	pc.mu.Lock()
+	e.result, e.err = result, err
+	e.expires = pc.now().Add(pc.ttl)
+	if err != nil || result.Status.IsTerminal() {
+		delete(pc.entries, jobID)
+	}
+	pc.pruneLocked()
+	close(e.done)
+	pc.mu.Unlock()
+
+	return result, err
+}
+
+// pruneLocked drops expired entries of jobs that are no longer polled.
+// pc.mu must be held.
+func (pc *pollCache) pruneLocked() {
+	now := pc.now()
+	for id, e := range pc.entries {
+		select {
+		case <-e.done:
+			if !now.Before(e.expires) {
+				delete(pc.entries, id)
+			}
+		default:
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package runpod
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newStatusServer returns a server answering every status request with
+// status, and a counter of the requests it received.
+func newStatusServer(t *testing.T, status string) (*httptest.Server, *int32) {
+	t.Helper()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_ = json.NewEncoder(w).Encode(statusResponse{ID: "job-1", Status: status})
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestPollCache_PollsWithinTTLHitServerOnce(t *testing.T) {
+	setTestEnv(t)
+	server, requests := newStatusServer(t, "IN_PROGRESS")
+
+	client, _ := NewClient("test-endpoint", WithBaseURL(server.URL), WithPollCacheTTL(time.Minute))
+
+	for i := range 2 {
+		result, err := client.Poll(context.Background(), "job-1")
+		if err != nil {
+			t.Fatalf("poll %d: unexpected error: %v", i, err)
+		}
+		if result.Status != StatusInProgress {
+			t.Errorf("poll %d: expected IN_PROGRESS, got %v", i, result.Status)
+		}
+	}
+	if got := atomic.LoadInt32(requests); got != 1 {
+		t.Errorf("expected 1 status request, got %d", got)
+	}
+
+	// Other jobs are not served from the cache
+	if _, err := client.Poll(context.Background(), "job-2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("expected 2 status requests, got %d", got)
+	}
+}
+
+func TestPollCache_DisabledByDefault(t *testing.T) {
+	setTestEnv(t)
+	server, requests := newStatusServer(t, "IN_PROGRESS")
+
+	client, _ := NewClient("test-endpoint", WithBaseURL(server.URL))
+
+	for range 2 {
+		if _, err := client.Poll(context.Background(), "job-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("expected 2 status requests, got %d", got)
+	}
+}
+
+func TestPollCache_Expires(t *testing.T) {
+	setTestEnv(t)
+	server, requests := newStatusServer(t, "IN_PROGRESS")
+
+	client, _ := NewClient("test-endpoint", WithBaseURL(server.URL), WithPollCacheTTL(500*time.Millisecond))
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client.pollCache.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	_, _ = client.Poll(ctx, "job-1")
+	now = now.Add(499 * time.Millisecond)
+	_, _ = client.Poll(ctx, "job-1")
+	if got := atomic.LoadInt32(requests); got != 1 {
+		t.Fatalf("expected 1 status request within the TTL, got %d", got)
+	}
+
+	now = now.Add(time.Millisecond)
+	_, _ = client.Poll(ctx, "job-1")
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("expected 2 status requests once the TTL passed, got %d", got)
+	}
+}
+
+func TestPollCache_TerminalResultsAreNotCached(t *testing.T) {
+	setTestEnv(t)
+	server, requests := newStatusServer(t, "COMPLETED")
+
+	client, _ := NewClient("test-endpoint", WithBaseURL(server.URL), WithPollCacheTTL(time.Minute))
+
+	for range 2 {
+		if _, err := client.Poll(context.Background(), "job-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("expected 2 status requests, got %d", got)
+	}
+	if n := len(client.pollCache.entries); n != 0 {
+		t.Errorf("expected no cached entries, got %d", n)
+	}
+}
+
+func TestPollCache_FailuresAreNotCached(t *testing.T) {
+	setTestEnv(t)
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("test-endpoint", WithBaseURL(server.URL), WithPollCacheTTL(time.Minute))
+
+	for range 2 {
+		if _, err := client.Poll(context.Background(), "job-1"); err == nil {
+			t.Fatal("expected an error")
+		}
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("expected 2 status requests, got %d", got)
+	}
+}
+
+func TestPollCache_ConcurrentPollsShareRequest(t *testing.T) {
+	setTestEnv(t)
+	var requests int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		_ = json.NewEncoder(w).Encode(statusResponse{ID: "job-1", Status: "IN_PROGRESS"})
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("test-endpoint", WithBaseURL(server.URL), WithPollCacheTTL(time.Minute))
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Poll(context.Background(), "job-1"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	// Let the first request reach the server before releasing it
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&requests) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("expected 1 status request, got %d", got)
+	}
+}
@@ -30,7 +30,7 @@ type SubmitOptions struct {
 	Prompt       string // Prompt text for lip-sync (default: "high quality, realistic, speaking naturally")
 	Width        int    // Video width in pixels (e.g., 384, 512)
 	Height       int    // Video height in pixels (e.g., 576, 512)
-	InputType    string // Input type (default: "image")
+	InputType    string // Input type, "image" or "video" (default: "image")
 	PersonCount  string // Person count (default: "single")
 	ForceOffload bool   // Whether to force offload (default: true)
 }
@@ -57,7 +57,8 @@ type runInput struct {
 	InputType     string `json:"input_type"`
 	PersonCount   string `json:"person_count"`
 	Prompt        string `json:"prompt"`
-	ImageBase64   string `json:"image_base64"`
+	ImageBase64   string `json:"image_base64,omitempty"`
+	VideoBase64   string `json:"video_base64,omitempty"`
 	// WavBase64 contains base64-encoded WAV audio in pcm_s16le format.
 	// This format ensures maximum compatibility with PyAV/librosa decoders.
 	WavBase64     string `json:"wav_base64"`
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxDimension is the largest width or height accepted by CreateJobRequest.
+const maxDimension = 4096
+
+// checkDimensions returns a VALIDATION_ERROR listing width and height in its
+// "fields" detail when they are not multiples of multiple, or nil when both
+// are. A multiple of 1 or less accepts any value.
+func checkDimensions(width, height, multiple int) *APIError {
+	if multiple <= 1 {
+		return nil
+	}
+
+	var fields []FieldError
+	var messages []string
+	for _, d := range []struct {
+		name  string
+		value int
+	}{{"width", width}, {"height", height}} {
+		if d.value%multiple == 0 {
+			continue
+		}
+		message := fmt.Sprintf("must be a multiple of %d (nearest valid value: %d)", multiple, snapDimension(d.value, multiple))
+		fields = append(fields, FieldError{Field: d.name, Tag: "multiple_of", Param: strconv.Itoa(multiple), Message: message})
+		messages = append(messages, fmt.Sprintf("%s %d %s", d.name, d.value, message))
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return NewAPIError(http.StatusBadRequest, "VALIDATION_ERROR",
+		strings.Join(messages, "; ")+"; pass ?snap=true to round automatically").
+		WithDetail("fields", fields)
+}
+
+// checkAllowedDimensions returns a VALIDATION_ERROR naming the allowed
+// presets when width x height is not one of them, or nil when it is.
+func checkAllowedDimensions(width, height int, presets []string) *APIError {
+	size := fmt.Sprintf("%dx%d", width, height)
+	if slices.Contains(presets, size) {
+		return nil
+	}
+
+	allowed := strings.Join(presets, ", ")
+	message := "must be one of the allowed dimensions: " + allowed
+	return NewAPIError(http.StatusBadRequest, "VALIDATION_ERROR",
+		fmt.Sprintf("dimensions %s are not allowed; allowed dimensions: %s", size, allowed)).
+		WithDetail("fields", []FieldError{
+			{Field: "width", Tag: "allowed_dimensions", Param: allowed, Message: message},
+			{Field: "height", Tag: "allowed_dimensions", Param: allowed, Message: message},
+		})
+}
+
+// snapDimension rounds v to the nearest multiple of multiple, staying within
+// 1..maxDimension. Ties round up.
+func snapDimension(v, multiple int) int {
+	if multiple <= 1 {
+		return v
+	}
+	snapped := (v + multiple/2) / multiple * multiple
+	return min(max(snapped, multiple), maxDimension/multiple*multiple)
+}
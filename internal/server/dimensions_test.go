@@ -0,0 +1,24 @@
+package server
+
+import "testing"
+
+func TestSnapDimension(t *testing.T) {
+	tests := []struct {
+		v, multiple, want int
+	}{
+		{v: 384, multiple: 16, want: 384},
+		{v: 391, multiple: 16, want: 384},
+		{v: 392, multiple: 16, want: 400}, // ties round up
+		{v: 1080, multiple: 16, want: 1088},
+		{v: 1, multiple: 16, want: 16},      // never below one multiple
+		{v: 4095, multiple: 48, want: 4080}, // never above maxDimension
+		{v: 383, multiple: 1, want: 383},
+		{v: 383, multiple: 0, want: 383},
+	}
+
+	for _, tt := range tests {
+		if got := snapDimension(tt.v, tt.multiple); got != tt.want {
+			t.Errorf("snapDimension(%d, %d) = %d, want %d", tt.v, tt.multiple, got, tt.want)
+		}
+	}
+}
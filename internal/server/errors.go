@@ -0,0 +1,189 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/maauso/infinitetalk-api/internal/job"
+	"github.com/maauso/infinitetalk-api/internal/runpod"
+	"github.com/maauso/infinitetalk-api/internal/storage"
+)
+
+// APIError is an error returned to API clients. Code is stable and meant for
+// programmatic handling; Message is for humans and may change.
+type APIError struct {
+	// Status is the HTTP status code of the response.
+	Status int
+	// Code is the machine-readable error code, e.g. "JOB_NOT_FOUND".
+	Code string
+	// Message is the human-readable error message.
+	Message string
+	// Details carries additional structured information, e.g. the failing
+	// fields of a validation error. Nil when there is none.
+	Details map[string]any
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// NewAPIError creates an APIError with the given status, code and message.
+func NewAPIError(status int, code, message string) *APIError {
+	return &APIError{Status: status, Code: code, Message: message}
+}
+
+// WithDetail returns e with key set to value in its Details.
+func (e *APIError) WithDetail(key string, value any) *APIError {
+	if e.Details == nil {
+		e.Details = make(map[string]any)
+	}
+	e.Details[key] = value
+	return e
+}
+
+// FieldError describes one request field that failed validation.
+type FieldError struct {
+	// Field is the JSON name of the field, e.g. "image_base64".
+	Field string `json:"field"`
+	// Tag is the validation rule that failed, e.g. "required".
+	Tag string `json:"tag"`
+	// Param is the rule's parameter, e.g. "1000" for max=1000. Empty for rules without one.
+	Param string `json:"param,omitempty"`
+	// Message is a human-readable description of the failure.
+	Message string `json:"message"`
+}
+
+// ValidationAPIError converts a validator error into a 400 VALIDATION_ERROR
+// whose "fields" detail lists each failing field and rule. Errors that are
+// not validator.ValidationErrors keep their message and carry no details.
+func ValidationAPIError(err error) *APIError {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return NewAPIError(http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	}
+
+	fields := make([]FieldError, 0, len(verrs))
+	messages := make([]string, 0, len(verrs))
+	for _, fe := range verrs {
+		f := FieldError{
+			Field:   fe.Field(),
+			Tag:     fe.Tag(),
+			Param:   fe.Param(),
+			Message: fieldMessage(fe),
+		}
+		fields = append(fields, f)
+		messages = append(messages, f.Field+" "+f.Message)
+	}
+
+	return NewAPIError(http.StatusBadRequest, "VALIDATION_ERROR",
+		"request validation failed: "+strings.Join(messages, "; ")).
+		WithDetail("fields", fields)
+}
+
+// fieldMessage describes why fe failed, without the field name.
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_if", "required_unless", "required_without":
+		return "is required"
+	case "excluded_with":
+		return "cannot be combined with " + fe.Param()
+	case "excluded_if":
+		return "is not allowed when " + strings.Replace(fe.Param(), " ", " is ", 1)
+	case "http_url":
+		return "must be an http or https URL"
+	case "base64":
+		return "must be valid base64"
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "min", "gte":
+		if fe.Kind() == reflect.String {
+			return "must be at least " + fe.Param() + " characters"
+		}
+		return "must be at least " + fe.Param()
+	case "max", "lte":
+		if fe.Kind() == reflect.String {
+			return "must be at most " + fe.Param() + " characters"
+		}
+		return "must be at most " + fe.Param()
+	default:
+		return fmt.Sprintf("failed the %q rule", fe.Tag())
+	}
+}
+
+// jsonTagName returns the name a struct field is encoded as in JSON, or ""
+// when the field has no JSON name.
+func jsonTagName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// domainErrors maps errors returned by the job service and the providers to
+// the API errors reported to clients. The first matching entry wins.
+var domainErrors = []struct {
+	err    error
+	status int
+	code   string
+	// message replaces the error text when set.
+	message string
+}{
+	{job.ErrJobNotFound, http.StatusNotFound, "JOB_NOT_FOUND", "job not found"},
+	{job.ErrJobNotDeletable, http.StatusConflict, "JOB_NOT_DELETABLE", "job is still being processed; cancel it first"},
+	{job.ErrJobNotCancellable, http.StatusConflict, "JOB_NOT_CANCELLABLE", "job is already in a terminal state"},
+	{job.ErrJobNotRetryable, http.StatusConflict, "JOB_NOT_RETRYABLE", "only failed or timed out jobs can be retried"},
+	{job.ErrJobNotStuck, http.StatusConflict, "JOB_NOT_STUCK", ""},
+	{job.ErrRetryInputsUnavailable, http.StatusConflict, "RETRY_INPUTS_UNAVAILABLE", "job inputs are no longer available"},
+	{job.ErrInvalidTransition, http.StatusConflict, "INVALID_TRANSITION", "job is not in a state that allows this operation"},
+	{job.ErrVideoNotPublishable, http.StatusConflict, "VIDEO_NOT_AVAILABLE", ""},
+	{job.ErrVideoNotFound, http.StatusNotFound, "VIDEO_NOT_FOUND", "video not found"},
+	{storage.ErrRemoteNotConfigured, http.StatusBadRequest, "REMOTE_STORAGE_NOT_CONFIGURED", "no remote storage (S3 or GCS) is configured"},
+	{job.ErrUnsupportedInputType, http.StatusBadRequest, "UNSUPPORTED_INPUT_TYPE", ""},
+	{job.ErrInvalidRetention, http.StatusBadRequest, "INVALID_RETENTION", ""},
+	{job.ErrInvalidImage, http.StatusBadRequest, "INVALID_IMAGE", ""},
+	{job.ErrInvalidVideo, http.StatusBadRequest, "INVALID_VIDEO", ""},
+	{job.ErrInvalidAudio, http.StatusBadRequest, "INVALID_AUDIO", ""},
+	{job.ErrAudioTooLong, http.StatusBadRequest, "AUDIO_TOO_LONG", ""},
+	{job.ErrImageTooLarge, http.StatusBadRequest, "IMAGE_TOO_LARGE", ""},
+	{job.ErrQueueFull, http.StatusServiceUnavailable, "QUEUE_FULL", "too many jobs are waiting to be processed; try again later"},
+	{job.ErrDispatcherClosed, http.StatusServiceUnavailable, "SHUTTING_DOWN", "the server is shutting down; try again later"},
+	{errIdempotencyConflict, http.StatusConflict, "IDEMPOTENCY_KEY_CONFLICT", ""},
+	{errIdempotencyInFlight, http.StatusConflict, "IDEMPOTENCY_KEY_IN_USE", ""},
+	{runpod.ErrProviderUnavailable, http.StatusServiceUnavailable, "PROVIDER_UNAVAILABLE", "the video provider is unavailable; try again later"},
+	{runpod.ErrRateLimited, http.StatusTooManyRequests, "PROVIDER_RATE_LIMITED", "the video provider is rate limiting requests; try again later"},
+	{runpod.ErrServerError, http.StatusBadGateway, "PROVIDER_ERROR", "the video provider returned a server error"},
+	{runpod.ErrSubmitFailed, http.StatusBadGateway, "PROVIDER_SUBMIT_FAILED", "the video provider rejected the job"},
+	{runpod.ErrRequestFailed, http.StatusBadGateway, "PROVIDER_REQUEST_FAILED", "the request to the video provider failed"},
+}
+
+// apiErrorFrom maps a known domain error to its APIError. It returns nil for
+// unknown errors, which callers report as internal errors.
+func apiErrorFrom(err error) *APIError {
+	for _, d := range domainErrors {
+		if !errors.Is(err, d.err) {
+			continue
+		}
+		message := d.message
+		if message == "" {
+			message = err.Error()
+		}
+		return NewAPIError(d.status, d.code, message)
+	}
+	return nil
+}
+
+// writeAPIError writes e in the standard error response format.
+func writeAPIError(w http.ResponseWriter, e *APIError) {
+	writeJSON(w, e.Status, ErrorResponse{
+		Error:   e.Message,
+		Code:    e.Code,
+		Details: e.Details,
+	})
+}
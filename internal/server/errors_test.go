@@ -0,0 +1,70 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maauso/infinitetalk-api/internal/job"
+	"github.com/maauso/infinitetalk-api/internal/runpod"
+)
+
+func TestAPIErrorFrom(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"job not found", job.ErrJobNotFound, http.StatusNotFound, "JOB_NOT_FOUND"},
+		{"wrapped job not found", fmt.Errorf("find job: %w", job.ErrJobNotFound), http.StatusNotFound, "JOB_NOT_FOUND"},
+		{"invalid transition", job.ErrInvalidTransition, http.StatusConflict, "INVALID_TRANSITION"},
+		{"not cancellable", job.ErrJobNotCancellable, http.StatusConflict, "JOB_NOT_CANCELLABLE"},
+		{"video not found", fmt.Errorf("%w: status RUNNING", job.ErrVideoNotFound), http.StatusNotFound, "VIDEO_NOT_FOUND"},
+		{"invalid audio", fmt.Errorf("%w: no audio stream", job.ErrInvalidAudio), http.StatusBadRequest, "INVALID_AUDIO"},
+		{"audio too long", fmt.Errorf("%w: 3600.0s exceeds the limit of 10m0s", job.ErrAudioTooLong), http.StatusBadRequest, "AUDIO_TOO_LONG"},
+		{"image too large", fmt.Errorf("%w: 10000x10000 exceeds the limit of 50000000 pixels", job.ErrImageTooLarge), http.StatusBadRequest, "IMAGE_TOO_LARGE"},
+		{"provider unavailable", fmt.Errorf("submit chunk: %w", runpod.ErrProviderUnavailable), http.StatusServiceUnavailable, "PROVIDER_UNAVAILABLE"},
+		{"rate limited", fmt.Errorf("%w: status 429", runpod.ErrRateLimited), http.StatusTooManyRequests, "PROVIDER_RATE_LIMITED"},
+		{"server error", runpod.ErrServerError, http.StatusBadGateway, "PROVIDER_ERROR"},
+		{"submit failed", runpod.ErrSubmitFailed, http.StatusBadGateway, "PROVIDER_SUBMIT_FAILED"},
+		{"request failed", runpod.ErrRequestFailed, http.StatusBadGateway, "PROVIDER_REQUEST_FAILED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := apiErrorFrom(tt.err)
+			require.NotNil(t, apiErr)
+			assert.Equal(t, tt.wantStatus, apiErr.Status)
+			assert.Equal(t, tt.wantCode, apiErr.Code)
+			assert.NotEmpty(t, apiErr.Message)
+		})
+	}
+}
+
+func TestAPIErrorFrom_Unknown(t *testing.T) {
+	assert.Nil(t, apiErrorFrom(errors.New("disk full")))
+}
+
+func TestWriteAPIError(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	writeAPIError(rec, NewAPIError(http.StatusConflict, "JOB_NOT_RETRYABLE", "cannot retry").
+		WithDetail("status", "RUNNING"))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.JSONEq(t, `{"error":"cannot retry","code":"JOB_NOT_RETRYABLE","details":{"status":"RUNNING"}}`, rec.Body.String())
+}
+
+func TestWriteError_OmitsDetails(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	writeError(rec, http.StatusNotFound, "job not found", "JOB_NOT_FOUND")
+
+	assert.JSONEq(t, `{"error":"job not found","code":"JOB_NOT_FOUND"}`, rec.Body.String())
+}
@@ -1,25 +1,65 @@
 package server
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"maps"
+	"math"
 	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 
 	"github.com/maauso/infinitetalk-api/internal/job"
+	"github.com/maauso/infinitetalk-api/internal/media"
+	"github.com/maauso/infinitetalk-api/internal/requestid"
+	"github.com/maauso/infinitetalk-api/internal/storage"
 )
 
+// queueRetryAfterSec is the Retry-After sent when the job queue is full.
+const queueRetryAfterSec = 5
+
 // Handlers contains the HTTP handlers for the API.
 type Handlers struct {
 	service            *job.ProcessVideoService
 	validator          *validator.Validate
 	logger             *slog.Logger
 	enableAsyncProcess bool
+	// videoReadBudget bounds how long GetJob may spend reading and encoding
+	// the output video. Zero means only the request context applies.
+	videoReadBudget time.Duration
+	// compressResults gzips GetJob responses that carry an inline video
+	// when the client accepts it.
+	compressResults bool
+	// readinessChecks are run by GET /readyz.
+	readinessChecks []HealthChecker
+	// maxRequestBytes caps the size of request bodies. Zero means no limit.
+	maxRequestBytes int64
+	// checkInputTypes rejects jobs whose image and audio inputs do not
+	// sniff as an image and as audio.
+	checkInputTypes bool
+	// dimensionMultiple is the factor width and height must be divisible by.
+	// Values of 1 or less disable the check.
+	dimensionMultiple int
+	// allowedDimensions are the WxH presets width and height must match.
+	// Empty allows any size within the request limits.
+	allowedDimensions []string
+	// idempotency remembers the jobs created for Idempotency-Key headers.
+	// Nil disables idempotency keys.
+	idempotency *idempotencyStore
+	// dispatcher processes created and retried jobs in the background.
+	dispatcher *job.Dispatcher
 }
 
 // HandlerOption is a function that configures a Handlers instance.
@@ -34,6 +74,83 @@ func WithAsyncProcessing(enabled bool) HandlerOption {
 	}
 }
 
+// WithVideoReadBudget sets the maximum time GetJob spends reading and
+// base64-encoding the output video before responding with 504.
+func WithVideoReadBudget(d time.Duration) HandlerOption {
+	return func(h *Handlers) {
+		h.videoReadBudget = d
+	}
+}
+
+// WithResultCompression enables gzip encoding of GetJob responses that carry
+// the video as base64, for clients that send Accept-Encoding: gzip or ?compress=gzip.
+func WithResultCompression(enabled bool) HandlerOption {
+	return func(h *Handlers) {
+		h.compressResults = enabled
+	}
+}
+
+// WithReadinessChecks adds dependency checks reported by GET /readyz.
+func WithReadinessChecks(checks ...HealthChecker) HandlerOption {
+	return func(h *Handlers) {
+		h.readinessChecks = append(h.readinessChecks, checks...)
+	}
+}
+
+// WithInputTypeCheck enables content sniffing of the image and audio inputs
+// in CreateJob, rejecting swapped or mistyped inputs with 400.
+func WithInputTypeCheck(enabled bool) HandlerOption {
+	return func(h *Handlers) {
+		h.checkInputTypes = enabled
+	}
+}
+
+// WithMaxRequestBytes limits request bodies to n bytes; larger bodies are
+// rejected with 413. Zero or negative values disable the limit.
+func WithMaxRequestBytes(n int64) HandlerOption {
+	return func(h *Handlers) {
+		h.maxRequestBytes = max(n, 0)
+	}
+}
+
+// WithDispatcher sets the worker pool that processes jobs in the background.
+// By default NewHandlers starts a dispatcher with the default worker count and queue depth.
+func WithDispatcher(d *job.Dispatcher) HandlerOption {
+	return func(h *Handlers) {
+		h.dispatcher = d
+	}
+}
+
+// WithIdempotencyTTL sets how long Idempotency-Key headers on POST /jobs are
+// remembered. Zero or negative values disable idempotency keys.
+func WithIdempotencyTTL(d time.Duration) HandlerOption {
+	return func(h *Handlers) {
+		h.idempotency = nil
+		if d > 0 {
+			h.idempotency = newIdempotencyStore(d)
+		}
+	}
+}
+
+// WithDimensionMultiple requires the width and height of new jobs to be
+// multiples of n, rejecting other values with 400 unless the request sets
+// ?snap=true, in which case they are rounded to the nearest multiple.
+// Values of 1 or less disable the check.
+func WithDimensionMultiple(n int) HandlerOption {
+	return func(h *Handlers) {
+		h.dimensionMultiple = n
+	}
+}
+
+// WithAllowedDimensions restricts the width and height of new jobs to the
+// given presets, written as WxH (e.g. "384x576"). Requests for any other size
+// are rejected with 400 and ?snap=true has no effect. No presets allows any size.
+func WithAllowedDimensions(presets ...string) HandlerOption {
+	return func(h *Handlers) {
+		h.allowedDimensions = presets
+	}
+}
+
 // NewHandlers creates a new Handlers instance.
 func NewHandlers(service *job.ProcessVideoService, logger *slog.Logger, opts ...HandlerOption) *Handlers {
 	if logger == nil {
@@ -41,41 +158,178 @@ func NewHandlers(service *job.ProcessVideoService, logger *slog.Logger, opts ...
 	}
 	h := &Handlers{
 		service:            service,
-		validator:          validator.New(),
+		validator:          newValidator(),
 		logger:             logger,
 		enableAsyncProcess: true, // Default to enabled
+		idempotency:        newIdempotencyStore(DefaultIdempotencyTTL),
 	}
 	for _, opt := range opts {
 		opt(h)
 	}
+	if h.dispatcher == nil {
+		h.dispatcher = job.NewDispatcher(service)
+		h.dispatcher.Start()
+	}
 	return h
 }
 
-// Health handles GET /health requests.
+// newValidator creates the request validator. Errors name fields by their
+// JSON names so they match what clients send.
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(jsonTagName)
+	v.RegisterStructValidation(validateCreateJobRequest, CreateJobRequest{})
+	return v
+}
+
+// validateCreateJobRequest checks the rules spanning several fields of a
+// CreateJobRequest: each input is given either as base64 or as a URL, never
+// both.
+func validateCreateJobRequest(sl validator.StructLevel) {
+	req := sl.Current().Interface().(CreateJobRequest)
+
+	if req.InputType != job.InputTypeVideo && req.ImageBase64 == "" && req.ImageURL == "" {
+		sl.ReportError(req.ImageBase64, "image_base64", "ImageBase64", "required_without", "image_url")
+	}
+	switch {
+	case req.ImageURL != "" && req.ImageBase64 != "":
+		sl.ReportError(req.ImageURL, "image_url", "ImageURL", "excluded_with", "image_base64")
+	case req.ImageURL != "" && req.InputType == job.InputTypeVideo:
+		sl.ReportError(req.ImageURL, "image_url", "ImageURL", "excluded_if", "input_type video")
+	}
+
+	if req.AudioBase64 == "" && req.AudioURL == "" {
+		sl.ReportError(req.AudioBase64, "audio_base64", "AudioBase64", "required_without", "audio_url")
+	}
+	if req.AudioURL != "" && req.AudioBase64 != "" {
+		sl.ReportError(req.AudioURL, "audio_url", "AudioURL", "excluded_with", "audio_base64")
+	}
+}
+
+// Drain waits for the background processing started by the handlers to
+// finish. It must be called after the HTTP server has shut down, so no new
+// processing is started. If ctx ends first, the jobs still being processed
+// are marked TIMED_OUT and ctx's error is returned.
+func (h *Handlers) Drain(ctx context.Context) error {
+	if err := h.dispatcher.Shutdown(ctx); err == nil {
+		return nil
+	}
+
+	timedOut := h.service.TimeoutActiveJobs(context.WithoutCancel(ctx))
+	h.logger.Warn("shutdown deadline reached before jobs finished",
+		slog.Int("timed_out_jobs", len(timedOut)),
+	)
+	return fmt.Errorf("drain jobs: %w", ctx.Err())
+}
+
+// log returns the handler logger, tagged with the request ID carried by ctx.
+func (h *Handlers) log(ctx context.Context) *slog.Logger {
+	return requestid.Logger(ctx, h.logger)
+}
+
+// Health handles GET /livez and its alias GET /health.
+// It only reports that the process is up; dependency checks live in Readyz.
 func (h *Handlers) Health(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, HealthResponse{Status: "ok"})
 }
 
+// Metrics handles GET /metrics requests.
+// It reports the provider calls currently in flight.
+func (h *Handlers) Metrics(w http.ResponseWriter, r *http.Request) {
+	resp := MetricsResponse{Providers: make(map[string]ProviderActivity)}
+	for provider, activity := range h.service.ProviderActivity() {
+		resp.Providers[provider] = ProviderActivity{
+			Submits: activity.Submits,
+			Polls:   activity.Polls,
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
 // CreateJob handles POST /jobs requests.
 func (h *Handlers) CreateJob(w http.ResponseWriter, r *http.Request) {
+	if h.maxRequestBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.maxRequestBytes)
+	}
+
 	var req CreateJobRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Warn("failed to decode request body",
+		h.log(r.Context()).Warn("failed to decode request body",
 			slog.String("error", err.Error()),
 		)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit), "PAYLOAD_TOO_LARGE")
+			return
+		}
 		writeError(w, http.StatusBadRequest, "invalid JSON body", "INVALID_JSON")
 		return
 	}
+	// The media payloads are redacted by CreateJobRequest.LogValue
+	h.log(r.Context()).Debug("create job request", slog.Any("request", req))
 
 	// Validate request
 	if err := h.validator.Struct(req); err != nil {
-		h.logger.Warn("request validation failed",
+		h.log(r.Context()).Warn("request validation failed",
 			slog.String("error", err.Error()),
 		)
-		writeError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		writeAPIError(w, ValidationAPIError(err))
+		return
+	}
+
+	width, height := req.Width, req.Height
+	snap := r.URL.Query().Get("snap") == "true"
+	var apiErr *APIError
+	switch {
+	case len(h.allowedDimensions) > 0:
+		apiErr = checkAllowedDimensions(width, height, h.allowedDimensions)
+	case snap:
+		width = snapDimension(width, h.dimensionMultiple)
+		height = snapDimension(height, h.dimensionMultiple)
+	default:
+		apiErr = checkDimensions(width, height, h.dimensionMultiple)
+	}
+	if apiErr != nil {
+		h.log(r.Context()).Warn("request validation failed",
+			slog.String("error", apiErr.Message),
+		)
+		writeAPIError(w, apiErr)
 		return
 	}
 
+	// Default input type to image if not specified
+	inputType := req.InputType
+	if inputType == "" {
+		inputType = job.InputTypeImage
+	}
+
+	if h.checkInputTypes {
+		// MP4 sniffs the same as M4A audio, so of a video input only the audio
+		// can be checked. URL inputs are only downloaded when the job runs.
+		var check error
+		switch {
+		case req.AudioURL != "" && (inputType == job.InputTypeVideo || req.ImageURL != ""):
+		case req.AudioURL != "":
+			check = checkImageType(req.ImageBase64)
+		case inputType == job.InputTypeVideo || req.ImageURL != "":
+			check = checkAudioType(req.AudioBase64)
+		default:
+			check = checkInputTypes(req.ImageBase64, req.AudioBase64)
+		}
+		if err := check; err != nil {
+			h.log(r.Context()).Warn("input type check failed",
+				slog.String("error", err.Error()),
+			)
+			code := "INVALID_INPUT_TYPE"
+			if errors.Is(err, errInputsSwapped) {
+				code = "INPUTS_SWAPPED"
+			}
+			writeError(w, http.StatusBadRequest, err.Error(), code)
+			return
+		}
+	}
+
 	// Default provider to runpod if not specified
 	provider := req.Provider
 	if provider == "" {
@@ -88,53 +342,168 @@ func (h *Handlers) CreateJob(w http.ResponseWriter, r *http.Request) {
 		forceOffload = *req.ForceOffload
 	}
 
+	// Default resize mode to pad if not specified
+	resizeMode := req.ResizeMode
+	if resizeMode == "" {
+		resizeMode = string(media.ResizeModePad)
+	}
+
+	// Default person count to single if not specified
+	personCount := req.PersonCount
+	if personCount == "" {
+		personCount = job.PersonCountSingle
+	}
+
+	// Default output format to mp4 if not specified
+	outputFormat := req.OutputFormat
+	if outputFormat == "" {
+		outputFormat = string(media.OutputFormatMP4)
+	}
+
+	// Default continuity mode to none if not specified
+	continuityMode := req.ContinuityMode
+	if continuityMode == "" {
+		continuityMode = job.ContinuityNone
+	}
+
 	// Create the job through the service
 	input := job.ProcessVideoInput{
-		ImageBase64:  req.ImageBase64,
-		AudioBase64:  req.AudioBase64,
-		Width:        req.Width,
-		Height:       req.Height,
-		Prompt:       req.Prompt,
-		Provider:     provider,
-		PushToS3:     req.PushToS3,
-		DryRun:       req.DryRun,
-		ForceOffload: forceOffload,
+		ImageBase64:      req.ImageBase64,
+		ImageURL:         req.ImageURL,
+		VideoBase64:      req.VideoBase64,
+		AudioBase64:      req.AudioBase64,
+		AudioURL:         req.AudioURL,
+		Width:            width,
+		Height:           height,
+		Prompt:           req.Prompt,
+		Provider:         provider,
+		PushToS3:         req.PushToS3,
+		DryRun:           req.DryRun,
+		ForceOffload:     forceOffload,
+		ResizeMode:       resizeMode,
+		PersonCount:      personCount,
+		InputType:        inputType,
+		Priority:         req.Priority,
+		OutputFormat:     outputFormat,
+		TargetFPS:        req.TargetFPS,
+		StrictDimensions: req.StrictDimensions,
+		UseOriginalAudio: req.UseOriginalAudio,
+		ContinuityMode:   continuityMode,
+		Metadata:         req.Metadata,
+		RetentionSec:     req.RetentionSec,
+	}
+
+	if req.ValidateOnly {
+		h.validateInputs(w, r, input)
+		return
+	}
+
+	// A retried request with the same Idempotency-Key gets the original job back
+	idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
+	if h.idempotency == nil {
+		idempotencyKey = ""
+	}
+	if idempotencyKey != "" {
+		if len(idempotencyKey) > maxIdempotencyKeyLen {
+			writeError(w, http.StatusBadRequest,
+				fmt.Sprintf("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLen), "INVALID_IDEMPOTENCY_KEY")
+			return
+		}
+		replayed, err := h.idempotency.reserve(idempotencyKey, requestFingerprint(req, snap))
+		if err != nil {
+			writeAPIError(w, apiErrorFrom(err))
+			return
+		}
+		if replayed != nil {
+			h.log(r.Context()).Info("replaying idempotent job creation",
+				slog.String("job_id", replayed.body.ID),
+			)
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			if replayed.body.RecommendedPollIntervalSec > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(replayed.body.RecommendedPollIntervalSec))
+			}
+			writeJSON(w, replayed.status, replayed.body)
+			return
+		}
+	}
+
+	// With ?dedup=true, identical inputs get the result of a completed job back
+	if r.URL.Query().Get("dedup") == "true" {
+		reused, err := h.service.FindReusableJob(r.Context(), input)
+		switch {
+		case err == nil:
+			h.log(r.Context()).Info("reusing completed job with identical inputs",
+				slog.String("job_id", reused.ID),
+			)
+			resp := CreateJobResponse{
+				ID:           reused.ID,
+				Status:       string(reused.Status),
+				Width:        reused.Width,
+				Height:       reused.Height,
+				ExpiresAt:    expiresAt(reused),
+				Deduplicated: true,
+				// Already completed: no poll interval, but the URL serves the result
+				PollURL: jobPath(reused.ID),
+			}
+			if idempotencyKey != "" {
+				h.idempotency.complete(idempotencyKey, http.StatusOK, resp)
+			}
+			writeJSON(w, http.StatusOK, resp)
+			return
+		case !errors.Is(err, job.ErrJobNotFound):
+			h.log(r.Context()).Warn("dedup lookup failed; creating a new job",
+				slog.String("error", err.Error()),
+			)
+		}
 	}
 
 	// Create job first (synchronously)
 	createdJob, err := h.service.CreateJob(r.Context(), input)
 	if err != nil {
-		h.logger.Error("failed to create job",
+		if idempotencyKey != "" {
+			h.idempotency.release(idempotencyKey)
+		}
+		if apiErr := apiErrorFrom(err); apiErr != nil {
+			writeAPIError(w, apiErr)
+			return
+		}
+		h.log(r.Context()).Error("failed to create job",
 			slog.String("error", err.Error()),
 		)
 		writeError(w, http.StatusInternalServerError, "failed to create job", "JOB_CREATION_FAILED")
 		return
 	}
 
-	// Start processing in background with a detached context
-	// Use context.WithoutCancel to prevent cancellation when the request ends
+	// Queue the job for background processing; a full queue rejects it
 	if h.enableAsyncProcess {
-		go func(ctx context.Context, jobID string, inp job.ProcessVideoInput) {
-			_, processErr := h.service.ProcessExistingJob(ctx, jobID, inp)
-			if processErr != nil {
-				h.logger.Error("background processing failed",
-					slog.String("job_id", jobID),
-					slog.String("error", processErr.Error()),
-				)
+		if err := h.dispatcher.Enqueue(r.Context(), createdJob.ID, input); err != nil {
+			if idempotencyKey != "" {
+				h.idempotency.release(idempotencyKey)
 			}
-		}(context.WithoutCancel(r.Context()), createdJob.ID, input)
+			writeQueueError(w, err)
+			return
+		}
 	}
 
-	h.logger.Info("job created",
+	h.log(r.Context()).Info("job created",
 		slog.String("job_id", createdJob.ID),
-		slog.Int("width", req.Width),
-		slog.Int("height", req.Height),
+		slog.Int("width", width),
+		slog.Int("height", height),
 	)
 
-	writeJSON(w, http.StatusAccepted, CreateJobResponse{
-		ID:     createdJob.ID,
-		Status: string(createdJob.Status),
-	})
+	resp := CreateJobResponse{
+		ID:        createdJob.ID,
+		Status:    string(createdJob.Status),
+		Width:     width,
+		Height:    height,
+		ExpiresAt: expiresAt(createdJob),
+	}
+	h.pollHints(w, &resp)
+	if idempotencyKey != "" {
+		h.idempotency.complete(idempotencyKey, http.StatusAccepted, resp)
+	}
+
+	writeJSON(w, http.StatusAccepted, resp)
 }
 
 // GetJob handles GET /jobs/{id} requests.
@@ -145,13 +514,23 @@ func (h *Handlers) GetJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	encoding, err := resultEncoding(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), "UNSUPPORTED_COMPRESSION")
+		return
+	}
+	if r.URL.Query().Get("compress") == "none" {
+		// Keep GzipMiddleware from compressing what the client asked to get uncompressed
+		w.Header().Set("Cache-Control", "no-transform")
+	}
+
 	foundJob, err := h.service.GetJob(r.Context(), jobID)
 	if err != nil {
-		if errors.Is(err, job.ErrJobNotFound) {
-			writeError(w, http.StatusNotFound, "job not found", "JOB_NOT_FOUND")
+		if apiErr := apiErrorFrom(err); apiErr != nil {
+			writeAPIError(w, apiErr)
 			return
 		}
-		h.logger.Error("failed to get job",
+		h.log(r.Context()).Error("failed to get job",
 			slog.String("job_id", jobID),
 			slog.String("error", err.Error()),
 		)
@@ -159,37 +538,626 @@ func (h *Handlers) GetJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	resp := h.jobResponse(r.Context(), foundJob)
+
+	// foundJob is a clone, so its chunks are safe to read while the job is processing
+	if includes(r, "chunks") {
+		resp.Chunks = chunkResponses(foundJob.Chunks)
+	}
+
+	// Include video content if completed and not pushed to S3
+	if foundJob.Status == job.StatusCompleted && foundJob.S3Key == "" && foundJob.OutputVideoPath != "" {
+		// Read video file and encode to base64 within the read budget
+		ctx := r.Context()
+		if h.videoReadBudget > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, h.videoReadBudget)
+			defer cancel()
+		}
+		videoB64, err := encodeFileBase64(ctx, foundJob.OutputVideoPath)
+		switch {
+		case err == nil:
+			resp.VideoBase64 = videoB64
+		case r.Context().Err() != nil:
+			// Client went away; nobody is left to answer
+			return
+		case errors.Is(err, context.DeadlineExceeded):
+			h.log(r.Context()).Warn("output video read exceeded budget",
+				slog.String("job_id", jobID),
+				slog.String("path", foundJob.OutputVideoPath),
+				slog.Duration("budget", h.videoReadBudget),
+			)
+			writeError(w, http.StatusGatewayTimeout, "reading the output video took too long", "VIDEO_READ_TIMEOUT")
+			return
+		default:
+			h.log(r.Context()).Error("failed to read output video",
+				slog.String("job_id", jobID),
+				slog.String("path", foundJob.OutputVideoPath),
+				slog.String("error", err.Error()),
+			)
+			// Don't fail the request, just log and omit video
+		}
+	}
+
+	// Only the inline base64 video is large enough to be worth compressing
+	if h.compressResults && encoding == "gzip" && resp.VideoBase64 != "" {
+		writeGzipJSON(w, http.StatusOK, resp)
+	} else {
+		writeJSON(w, http.StatusOK, resp)
+	}
+
+	// The inline video is a download like GET /jobs/{id}/video
+	if resp.VideoBase64 != "" {
+		h.markVideoDownloaded(r.Context(), jobID)
+	}
+}
+
+// ListJobs handles GET /jobs requests. Each ?tag=key:value parameter keeps
+// only the jobs whose metadata holds that pair. Inline videos are never
+// included; fetch a job with GET /jobs/{id} for its result.
+func (h *Handlers) ListJobs(w http.ResponseWriter, r *http.Request) {
+	tags, err := parseTagFilters(r.URL.Query()["tag"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	jobs, err := h.service.ListJobs(r.Context(), tags)
+	if err != nil {
+		h.log(r.Context()).Error("failed to list jobs",
+			slog.String("error", err.Error()),
+		)
+		writeError(w, http.StatusInternalServerError, "failed to list jobs", "JOB_FETCH_FAILED")
+		return
+	}
+
+	resp := JobListResponse{Jobs: make([]JobResponse, 0, len(jobs))}
+	for _, j := range jobs {
+		resp.Jobs = append(resp.Jobs, h.jobResponse(r.Context(), j))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// parseTagFilters parses ?tag=key:value parameters into the pairs a job's
+// metadata must hold. Values may contain ':'; keys may not.
+func parseTagFilters(params []string) (map[string]string, error) {
+	tags := make(map[string]string, len(params))
+	for _, p := range params {
+		key, value, ok := strings.Cut(p, ":")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("tag %q must be written as key:value", p)
+		}
+		if prev, dup := tags[key]; dup && prev != value {
+			return nil, fmt.Errorf("tag %q is given with more than one value", key)
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
+
+// AdminListJobs handles GET /admin/jobs requests. With ?stuck=true only the
+// RUNNING jobs that have not been updated for the stuck threshold are listed,
+// oldest update first; otherwise every job is listed, newest first.
+func (h *Handlers) AdminListJobs(w http.ResponseWriter, r *http.Request) {
+	stuck := false
+	if v := r.URL.Query().Get("stuck"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("stuck %q must be true or false", v), "VALIDATION_ERROR")
+			return
+		}
+		stuck = parsed
+	}
+
+	var jobs []*job.Job
+	var err error
+	if stuck {
+		jobs, err = h.service.StuckJobs(r.Context())
+	} else {
+		jobs, err = h.service.ListJobs(r.Context(), nil)
+	}
+	if err != nil {
+		h.log(r.Context()).Error("failed to list jobs",
+			slog.String("error", err.Error()),
+		)
+		writeError(w, http.StatusInternalServerError, "failed to list jobs", "JOB_FETCH_FAILED")
+		return
+	}
+
+	resp := AdminJobListResponse{Jobs: make([]AdminJobResponse, 0, len(jobs))}
+	for _, j := range jobs {
+		resp.Jobs = append(resp.Jobs, h.adminJobResponse(r.Context(), j))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// AdminTimeoutJob handles POST /admin/jobs/{id}/timeout requests. A stuck
+// job is marked TIMED_OUT and its partial artifacts are removed; it can then
+// be retried with POST /jobs/{id}/retry.
+func (h *Handlers) AdminTimeoutJob(w http.ResponseWriter, r *http.Request) {
+	jobID := r.PathValue("id")
+	if jobID == "" {
+		writeError(w, http.StatusBadRequest, "job ID is required", "MISSING_JOB_ID")
+		return
+	}
+
+	timedOut, err := h.service.TimeoutStuckJob(r.Context(), jobID)
+	if err != nil {
+		if apiErr := apiErrorFrom(err); apiErr != nil {
+			writeAPIError(w, apiErr)
+			return
+		}
+		h.log(r.Context()).Error("failed to time out job",
+			slog.String("job_id", jobID),
+			slog.String("error", err.Error()),
+		)
+		writeError(w, http.StatusInternalServerError, "failed to time out job", "JOB_TIMEOUT_FAILED")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.adminJobResponse(r.Context(), timedOut))
+}
+
+// adminJobResponse maps a job to its admin API representation.
+func (h *Handlers) adminJobResponse(ctx context.Context, j *job.Job) AdminJobResponse {
+	resp := AdminJobResponse{
+		JobResponse: h.jobResponse(ctx, j),
+		UpdatedAt:   j.UpdatedAt,
+	}
+	if !j.StartedAt.IsZero() {
+		startedAt := j.StartedAt
+		resp.StartedAt = &startedAt
+	}
+	return resp
+}
+
+// jobResponse maps a job to its API representation, without the inline
+// video or the chunk details. Remote storage URLs are resolved here, so
+// presigned ones are valid for their full TTL from the time of the request.
+func (h *Handlers) jobResponse(ctx context.Context, j *job.Job) JobResponse {
 	resp := JobResponse{
-		ID:       foundJob.ID,
-		Provider: string(foundJob.Provider),
-		Status:   string(foundJob.Status),
-		Progress: foundJob.Progress,
-		Error:    foundJob.Error,
-	}
-
-	// Include video content if completed
-	if foundJob.Status == job.StatusCompleted {
-		if foundJob.PushToS3 && foundJob.VideoURL != "" {
-			resp.VideoURL = foundJob.VideoURL
-		} else if foundJob.OutputVideoPath != "" {
-			// Read video file and encode to base64
-			videoData, err := os.ReadFile(foundJob.OutputVideoPath)
-			if err != nil {
-				h.logger.Error("failed to read output video",
-					slog.String("job_id", jobID),
-					slog.String("path", foundJob.OutputVideoPath),
-					slog.String("error", err.Error()),
-				)
-				// Don't fail the request, just log and omit video
-			} else {
-				resp.VideoBase64 = base64.StdEncoding.EncodeToString(videoData)
+		ID:        j.ID,
+		Provider:  string(j.Provider),
+		Status:    string(j.Status),
+		Stage:     string(j.Stage),
+		Priority:  string(j.Priority),
+		Progress:  j.Progress,
+		Error:     j.Error,
+		ExpiresAt: expiresAt(j),
+		Metadata:  j.Metadata,
+	}
+	if j.Retention > 0 || j.DeleteAfterDownload {
+		secs := int(j.Retention / time.Second)
+		resp.RetentionSec = &secs
+	}
+	if !j.VideoDownloadedAt.IsZero() {
+		downloadedAt := j.VideoDownloadedAt.UTC()
+		resp.VideoDownloadedAt = &downloadedAt
+	}
+	if j.Status == job.StatusInQueue {
+		if pos, ok := h.dispatcher.QueuePosition(j.ID); ok {
+			resp.QueuePosition = &pos
+		}
+	}
+	if eta, ok := h.service.EstimateRemaining(j); ok {
+		secs := int(math.Ceil(eta.Seconds()))
+		resp.EstimatedSecondsRemaining = &secs
+	}
+
+	if j.Status == job.StatusCompleted {
+		videoURL, err := h.service.VideoURL(ctx, j)
+		if err != nil {
+			h.log(ctx).Warn("failed to resolve video URL",
+				slog.String("job_id", j.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+		resp.VideoURL = videoURL
+
+		thumbnailURL, err := h.service.ThumbnailURL(ctx, j)
+		if err != nil {
+			h.log(ctx).Warn("failed to resolve thumbnail URL",
+				slog.String("job_id", j.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+		if thumbnailURL == "" && j.ThumbnailPath != "" {
+			thumbnailURL = "/jobs/" + j.ID + "/thumbnail"
+		}
+		resp.ThumbnailURL = thumbnailURL
+	}
+	return resp
+}
+
+// validateInputs probes the inputs of a validate_only request and responds
+// with what was detected, without creating a job.
+func (h *Handlers) validateInputs(w http.ResponseWriter, r *http.Request, input job.ProcessVideoInput) {
+	probe, err := h.service.ValidateInputs(r.Context(), input)
+	if err != nil {
+		if apiErr := apiErrorFrom(err); apiErr != nil {
+			writeAPIError(w, apiErr)
+			return
+		}
+		h.log(r.Context()).Error("failed to validate inputs",
+			slog.String("error", err.Error()),
+		)
+		writeError(w, http.StatusInternalServerError, "failed to validate inputs", "VALIDATION_FAILED")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CreateJobResponse{
+		Status: "VALIDATED",
+		Width:  input.Width,
+		Height: input.Height,
+		Probe: &InputProbeResponse{
+			ImageWidth:       probe.ImageWidth,
+			ImageHeight:      probe.ImageHeight,
+			AudioDurationSec: probe.AudioDurationSec,
+			EstimatedChunks:  probe.EstimatedChunks,
+		},
+	})
+}
+
+// expiresAt returns the expiry of a job for API responses, or nil if it does not expire.
+func expiresAt(j *job.Job) *time.Time {
+	if j.ExpiresAt.IsZero() {
+		return nil
+	}
+	t := j.ExpiresAt.UTC()
+	return &t
+}
+
+// includes reports whether the comma-separated ?include= parameter lists field.
+func includes(r *http.Request, field string) bool {
+	for _, v := range r.URL.Query()["include"] {
+		for _, f := range strings.Split(v, ",") {
+			if strings.TrimSpace(f) == field {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// chunkResponses maps job chunks to their API representation.
+func chunkResponses(chunks []job.Chunk) []ChunkResponse {
+	resp := make([]ChunkResponse, 0, len(chunks))
+	for _, c := range chunks {
+		cr := ChunkResponse{
+			Index:           c.Index,
+			Status:          string(c.Status),
+			RunPodJobID:     c.RunPodJobID,
+			Error:           c.Error,
+			Attempts:        c.Attempts,
+			FailureStage:    string(c.FailureStage),
+			ProviderStatus:  c.ProviderStatus,
+			ProviderError:   c.ProviderError,
+			CancelRequested: c.CancelRequested,
+			CancelConfirmed: c.CancelConfirmed,
+			CancelError:     c.CancelError,
+		}
+		if !c.StartedAt.IsZero() {
+			startedAt := c.StartedAt
+			cr.StartedAt = &startedAt
+		}
+		if !c.CompletedAt.IsZero() {
+			completedAt := c.CompletedAt
+			cr.CompletedAt = &completedAt
+		}
+		resp = append(resp, cr)
+	}
+	return resp
+}
+
+// resultEncoding picks the content encoding for a job result. An explicit
+// ?compress= parameter wins over Accept-Encoding; only gzip is supported.
+func resultEncoding(r *http.Request) (string, error) {
+	switch compress := r.URL.Query().Get("compress"); compress {
+	case "gzip":
+		return "gzip", nil
+	case "none":
+		return "", nil
+	case "":
+		if acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			return "gzip", nil
+		}
+		return "", nil
+	default:
+		return "", fmt.Errorf("unsupported compression %q: use gzip or none", compress)
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		// A zero quality value explicitly refuses the coding
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
 			}
 		}
+		return true
+	}
+	return false
+}
+
+// GetJobThumbnail handles GET /jobs/{id}/thumbnail requests.
+// It redirects to the S3 copy when one exists, otherwise serves the local JPEG.
+func (h *Handlers) GetJobThumbnail(w http.ResponseWriter, r *http.Request) {
+	jobID := r.PathValue("id")
+	if jobID == "" {
+		writeError(w, http.StatusBadRequest, "job ID is required", "MISSING_JOB_ID")
+		return
+	}
+
+	foundJob, err := h.service.GetJob(r.Context(), jobID)
+	if err != nil {
+		if apiErr := apiErrorFrom(err); apiErr != nil {
+			writeAPIError(w, apiErr)
+			return
+		}
+		h.log(r.Context()).Error("failed to get job",
+			slog.String("job_id", jobID),
+			slog.String("error", err.Error()),
+		)
+		writeError(w, http.StatusInternalServerError, "failed to get job", "JOB_FETCH_FAILED")
+		return
+	}
+
+	if foundJob.ThumbnailKey != "" {
+		thumbnailURL, err := h.service.ThumbnailURL(r.Context(), foundJob)
+		if err != nil {
+			h.log(r.Context()).Error("failed to resolve thumbnail URL",
+				slog.String("job_id", jobID),
+				slog.String("error", err.Error()),
+			)
+			writeError(w, http.StatusInternalServerError, "failed to resolve thumbnail URL", "THUMBNAIL_URL_FAILED")
+			return
+		}
+		http.Redirect(w, r, thumbnailURL, http.StatusFound)
+		return
+	}
+	if foundJob.ThumbnailPath == "" {
+		writeError(w, http.StatusNotFound, "thumbnail not found", "THUMBNAIL_NOT_FOUND")
+		return
 	}
 
+	f, err := os.Open(foundJob.ThumbnailPath)
+	if err != nil {
+		h.log(r.Context()).Error("failed to open thumbnail",
+			slog.String("job_id", jobID),
+			slog.String("path", foundJob.ThumbnailPath),
+			slog.String("error", err.Error()),
+		)
+		writeError(w, http.StatusNotFound, "thumbnail not found", "THUMBNAIL_NOT_FOUND")
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	http.ServeContent(w, r, filepath.Base(foundJob.ThumbnailPath), foundJob.CompletedAt, f)
+}
+
+// GetJobLogs handles GET /jobs/{id}/logs requests.
+// It returns the log lines captured for the job as JSON, or as plain text
+// with one line per entry when ?format=text.
+func (h *Handlers) GetJobLogs(w http.ResponseWriter, r *http.Request) {
+	jobID := r.PathValue("id")
+	if jobID == "" {
+		writeError(w, http.StatusBadRequest, "job ID is required", "MISSING_JOB_ID")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "text" {
+		writeError(w, http.StatusBadRequest, "format must be json or text", "INVALID_FORMAT")
+		return
+	}
+
+	foundJob, err := h.service.GetJob(r.Context(), jobID)
+	if err != nil {
+		if apiErr := apiErrorFrom(err); apiErr != nil {
+			writeAPIError(w, apiErr)
+			return
+		}
+		h.log(r.Context()).Error("failed to get job",
+			slog.String("job_id", jobID),
+			slog.String("error", err.Error()),
+		)
+		writeError(w, http.StatusInternalServerError, "failed to get job", "JOB_FETCH_FAILED")
+		return
+	}
+
+	entries, dropped := foundJob.Logs.Entries()
+
+	if format == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		for _, e := range entries {
+			_, _ = fmt.Fprintf(w, "%s %s %s%s\n", e.Time.UTC().Format(time.RFC3339Nano), e.Level, e.Message, formatLogAttrs(e.Attrs))
+		}
+		return
+	}
+
+	resp := JobLogsResponse{
+		ID:      foundJob.ID,
+		Lines:   make([]JobLogLine, 0, len(entries)),
+		Dropped: dropped,
+	}
+	for _, e := range entries {
+		resp.Lines = append(resp.Lines, JobLogLine{
+			Time:    e.Time,
+			Level:   e.Level,
+			Message: e.Message,
+			Attrs:   e.Attrs,
+		})
+	}
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// formatLogAttrs formats attrs as " key=value" pairs sorted by key.
+func formatLogAttrs(attrs map[string]string) string {
+	var b strings.Builder
+	for _, k := range slices.Sorted(maps.Keys(attrs)) {
+		fmt.Fprintf(&b, " %s=%s", k, strconv.Quote(attrs[k]))
+	}
+	return b.String()
+}
+
+// GetJobVideoInfo handles GET /jobs/{id}/video/info requests.
+// It returns the probed dimensions, duration, size and codecs of the output
+// video of a completed job without sending the video itself.
+func (h *Handlers) GetJobVideoInfo(w http.ResponseWriter, r *http.Request) {
+	jobID := r.PathValue("id")
+	if jobID == "" {
+		writeError(w, http.StatusBadRequest, "job ID is required", "MISSING_JOB_ID")
+		return
+	}
+
+	info, err := h.service.ProbeJobVideo(r.Context(), jobID)
+	if err != nil {
+		if apiErr := apiErrorFrom(err); apiErr != nil {
+			writeAPIError(w, apiErr)
+			return
+		}
+		h.log(r.Context()).Error("failed to probe job video",
+			slog.String("job_id", jobID),
+			slog.String("error", err.Error()),
+		)
+		writeError(w, http.StatusInternalServerError, "failed to probe video", "VIDEO_PROBE_FAILED")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, VideoInfoResponse{
+		ID:          jobID,
+		Width:       info.Width,
+		Height:      info.Height,
+		DurationSec: info.DurationSec,
+		SizeBytes:   info.SizeBytes,
+		FPS:         info.FPS,
+		VideoCodec:  info.Codec,
+		AudioCodec:  info.AudioCodec,
+	})
+}
+
+// GetJobVideo handles GET /jobs/{id}/video requests.
+// It streams the output video of a completed job: a video pushed to remote
+// storage is proxied from there, a local one is served with Range support.
+func (h *Handlers) GetJobVideo(w http.ResponseWriter, r *http.Request) {
+	jobID := r.PathValue("id")
+	if jobID == "" {
+		writeError(w, http.StatusBadRequest, "job ID is required", "MISSING_JOB_ID")
+		return
+	}
+
+	foundJob, err := h.service.GetJob(r.Context(), jobID)
+	if err != nil {
+		if apiErr := apiErrorFrom(err); apiErr != nil {
+			writeAPIError(w, apiErr)
+			return
+		}
+		h.log(r.Context()).Error("failed to get job",
+			slog.String("job_id", jobID),
+			slog.String("error", err.Error()),
+		)
+		writeError(w, http.StatusInternalServerError, "failed to get job", "JOB_FETCH_FAILED")
+		return
+	}
+
+	if foundJob.Status != job.StatusCompleted {
+		writeError(w, http.StatusConflict, "job has not completed", "VIDEO_NOT_READY")
+		return
+	}
+
+	// Uploaded videos are proxied from remote storage unless the local copy was kept
+	if foundJob.PushToS3 && foundJob.S3Key != "" && !fileExists(foundJob.OutputVideoPath) {
+		h.proxyRemoteVideo(w, r, foundJob)
+		return
+	}
+	if foundJob.OutputVideoPath == "" {
+		writeError(w, http.StatusNotFound, "video not found", "VIDEO_NOT_FOUND")
+		return
+	}
+
+	f, err := os.Open(foundJob.OutputVideoPath)
+	if err != nil {
+		h.log(r.Context()).Error("failed to open output video",
+			slog.String("job_id", jobID),
+			slog.String("path", foundJob.OutputVideoPath),
+			slog.String("error", err.Error()),
+		)
+		writeError(w, http.StatusNotFound, "video not found", "VIDEO_NOT_FOUND")
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	w.Header().Set("Content-Type", media.OutputFormatFromPath(foundJob.OutputVideoPath).ContentType())
+	rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+	http.ServeContent(rw, r, filepath.Base(foundJob.OutputVideoPath), foundJob.CompletedAt, f)
+
+	// A response carrying the whole file counts as a download, including a
+	// range request for all of it
+	if info, err := f.Stat(); err == nil && r.Method == http.MethodGet && rw.bytes > 0 && int64(rw.bytes) == info.Size() {
+		h.markVideoDownloaded(r.Context(), jobID)
+	}
+}
+
+// markVideoDownloaded records that a job's video was downloaded in full.
+// Failures are only logged, since the video has already been served.
+func (h *Handlers) markVideoDownloaded(ctx context.Context, jobID string) {
+	if err := h.service.MarkVideoDownloaded(context.WithoutCancel(ctx), jobID); err != nil {
+		h.log(ctx).Warn("failed to record video download",
+			slog.String("job_id", jobID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// fileExists reports whether path is set and names an existing file.
+func fileExists(path string) bool {
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// proxyRemoteVideo streams a job's video from remote storage to the client.
+func (h *Handlers) proxyRemoteVideo(w http.ResponseWriter, r *http.Request, foundJob *job.Job) {
+	video, err := h.service.DownloadJobVideo(r.Context(), foundJob)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			writeError(w, http.StatusNotFound, "video not found", "VIDEO_NOT_FOUND")
+			return
+		}
+		h.log(r.Context()).Error("failed to download video from remote storage",
+			slog.String("job_id", foundJob.ID),
+			slog.String("error", err.Error()),
+		)
+		writeError(w, http.StatusBadGateway, "failed to download video", "VIDEO_DOWNLOAD_FAILED")
+		return
+	}
+	defer func() { _ = video.Close() }()
+
+	w.Header().Set("Content-Type", media.OutputFormatFromPath(foundJob.S3Key).ContentType())
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, video); err != nil {
+		if r.Context().Err() == nil {
+			h.log(r.Context()).Warn("video proxy interrupted",
+				slog.String("job_id", foundJob.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+		return
+	}
+	h.markVideoDownloaded(r.Context(), foundJob.ID)
+}
+
 // DeleteJobVideo handles POST /jobs/{id}/video/delete requests.
 func (h *Handlers) DeleteJobVideo(w http.ResponseWriter, r *http.Request) {
 	jobID := r.PathValue("id")
@@ -200,11 +1168,11 @@ func (h *Handlers) DeleteJobVideo(w http.ResponseWriter, r *http.Request) {
 
 	err := h.service.DeleteJobVideo(r.Context(), jobID)
 	if err != nil {
-		if errors.Is(err, job.ErrJobNotFound) {
-			writeError(w, http.StatusNotFound, "job not found", "JOB_NOT_FOUND")
+		if apiErr := apiErrorFrom(err); apiErr != nil {
+			writeAPIError(w, apiErr)
 			return
 		}
-		h.logger.Error("failed to delete job video",
+		h.log(r.Context()).Error("failed to delete job video",
 			slog.String("job_id", jobID),
 			slog.String("error", err.Error()),
 		)
@@ -215,6 +1183,189 @@ func (h *Handlers) DeleteJobVideo(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// PublishJobVideo handles POST /jobs/{id}/publish requests.
+// The local video of a completed job is uploaded to remote storage and its URL returned.
+func (h *Handlers) PublishJobVideo(w http.ResponseWriter, r *http.Request) {
+	jobID := r.PathValue("id")
+	if jobID == "" {
+		writeError(w, http.StatusBadRequest, "job ID is required", "MISSING_JOB_ID")
+		return
+	}
+
+	published, err := h.service.PublishJobVideo(r.Context(), jobID)
+	if err != nil {
+		if apiErr := apiErrorFrom(err); apiErr != nil {
+			writeAPIError(w, apiErr)
+			return
+		}
+		h.log(r.Context()).Error("failed to publish job video",
+			slog.String("job_id", jobID),
+			slog.String("error", err.Error()),
+		)
+		writeError(w, http.StatusInternalServerError, "failed to publish video", "VIDEO_PUBLISH_FAILED")
+		return
+	}
+
+	videoURL, err := h.service.VideoURL(r.Context(), published)
+	if err != nil {
+		h.log(r.Context()).Error("failed to resolve published video URL",
+			slog.String("job_id", jobID),
+			slog.String("error", err.Error()),
+		)
+		writeError(w, http.StatusInternalServerError, "failed to publish video", "VIDEO_PUBLISH_FAILED")
+		return
+	}
+	// The thumbnail is optional, so a URL that cannot be resolved is left out
+	thumbnailURL, _ := h.service.ThumbnailURL(r.Context(), published)
+
+	writeJSON(w, http.StatusOK, PublishJobResponse{
+		ID:           published.ID,
+		VideoURL:     videoURL,
+		ThumbnailURL: thumbnailURL,
+	})
+}
+
+// DeleteJob handles DELETE /jobs/{id} and POST /jobs/{id}/delete requests.
+// The job record and all of its files are removed; 204 No Content is returned.
+func (h *Handlers) DeleteJob(w http.ResponseWriter, r *http.Request) {
+	jobID := r.PathValue("id")
+	if jobID == "" {
+		writeError(w, http.StatusBadRequest, "job ID is required", "MISSING_JOB_ID")
+		return
+	}
+
+	err := h.service.DeleteJob(r.Context(), jobID)
+	if err != nil {
+		if apiErr := apiErrorFrom(err); apiErr != nil {
+			writeAPIError(w, apiErr)
+			return
+		}
+		h.log(r.Context()).Error("failed to delete job",
+			slog.String("job_id", jobID),
+			slog.String("error", err.Error()),
+		)
+		writeError(w, http.StatusInternalServerError, "failed to delete job", "JOB_DELETE_FAILED")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CancelJob handles POST and DELETE /jobs/{id}/cancel requests.
+// The job is marked CANCELLED immediately and 202 Accepted is returned with
+// its chunks; cancellation of chunks already submitted to the provider
+// continues in the background, and its outcome shows up on the chunks of a
+// later GET /jobs/{id}?include=chunks.
+func (h *Handlers) CancelJob(w http.ResponseWriter, r *http.Request) {
+	jobID := r.PathValue("id")
+	if jobID == "" {
+		writeError(w, http.StatusBadRequest, "job ID is required", "MISSING_JOB_ID")
+		return
+	}
+
+	cancelledJob, err := h.service.CancelJob(r.Context(), jobID)
+	if err != nil {
+		if apiErr := apiErrorFrom(err); apiErr != nil {
+			writeAPIError(w, apiErr)
+			return
+		}
+		h.log(r.Context()).Error("failed to cancel job",
+			slog.String("job_id", jobID),
+			slog.String("error", err.Error()),
+		)
+		writeError(w, http.StatusInternalServerError, "failed to cancel job", "JOB_CANCEL_FAILED")
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, JobResponse{
+		ID:       cancelledJob.ID,
+		Provider: string(cancelledJob.Provider),
+		Status:   string(cancelledJob.Status),
+		Progress: cancelledJob.Progress,
+		Error:    cancelledJob.Error,
+		Chunks:   chunkResponses(cancelledJob.Chunks),
+	})
+}
+
+// RetryJob handles POST /jobs/{id}/retry requests.
+// A FAILED or TIMED_OUT job is reset to IN_QUEUE and reprocessed in the
+// background from its retained inputs; 202 Accepted is returned.
+func (h *Handlers) RetryJob(w http.ResponseWriter, r *http.Request) {
+	jobID := r.PathValue("id")
+	if jobID == "" {
+		writeError(w, http.StatusBadRequest, "job ID is required", "MISSING_JOB_ID")
+		return
+	}
+
+	retriedJob, err := h.service.RetryJob(r.Context(), jobID)
+	if err != nil {
+		if apiErr := apiErrorFrom(err); apiErr != nil {
+			writeAPIError(w, apiErr)
+			return
+		}
+		h.log(r.Context()).Error("failed to retry job",
+			slog.String("job_id", jobID),
+			slog.String("error", err.Error()),
+		)
+		writeError(w, http.StatusInternalServerError, "failed to retry job", "JOB_RETRY_FAILED")
+		return
+	}
+
+	if h.enableAsyncProcess {
+		if err := h.dispatcher.EnqueueRetry(r.Context(), jobID); err != nil {
+			writeQueueError(w, err)
+			return
+		}
+	}
+
+	h.log(r.Context()).Info("job retry accepted", slog.String("job_id", retriedJob.ID))
+
+	resp := CreateJobResponse{
+		ID:        retriedJob.ID,
+		Status:    string(retriedJob.Status),
+		ExpiresAt: expiresAt(retriedJob),
+	}
+	h.pollHints(w, &resp)
+	writeJSON(w, http.StatusAccepted, resp)
+}
+
+// encodeFileBase64 streams a file through a base64 encoder, stopping as soon
+// as ctx is done.
+func encodeFileBase64(ctx context.Context, path string) (string, error) {
+	f, err := os.Open(path) // #nosec G304 - path comes from the job record, not the request
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	var buf strings.Builder
+	if info, statErr := f.Stat(); statErr == nil {
+		buf.Grow(base64.StdEncoding.EncodedLen(int(info.Size())))
+	}
+
+	enc := base64.NewEncoder(base64.StdEncoding, &buf)
+	if _, err := io.Copy(enc, &ctxReader{ctx: ctx, r: f}); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ctxReader is an io.Reader that fails with the context error once ctx is done.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *ctxReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
 // writeJSON writes a JSON response.
 func writeJSON(w http.ResponseWriter, status int, data any) {
 	w.Header().Set("Content-Type", "application/json")
@@ -224,10 +1375,44 @@ func writeJSON(w http.ResponseWriter, status int, data any) {
 	}
 }
 
+// writeGzipJSON writes a gzip-encoded JSON response with the given status code.
+func writeGzipJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Encoding", "gzip")
+	w.Header().Add("Vary", "Accept-Encoding")
+	w.WriteHeader(status)
+
+	gz := gzip.NewWriter(w)
+	if err := json.NewEncoder(gz).Encode(data); err != nil {
+		slog.Error("failed to encode JSON response", slog.String("error", err.Error()))
+	}
+	if err := gz.Close(); err != nil {
+		slog.Error("failed to flush gzip response", slog.String("error", err.Error()))
+	}
+}
+
+// pollHints fills in where and how often to poll the job of resp and sets
+// the matching Retry-After header. Polling more often than the service polls
+// the provider cannot show progress any sooner.
+func (h *Handlers) pollHints(w http.ResponseWriter, resp *CreateJobResponse) {
+	resp.PollURL = jobPath(resp.ID)
+	resp.RecommendedPollIntervalSec = max(int(math.Ceil(h.service.PollInterval().Seconds())), 1)
+	w.Header().Set("Retry-After", strconv.Itoa(resp.RecommendedPollIntervalSec))
+}
+
+// jobPath returns the path of the job status endpoint of id.
+func jobPath(id string) string {
+	return "/jobs/" + id
+}
+
+// writeQueueError writes the 503 response for a job the dispatcher did not accept,
+// asking the client to retry shortly.
+func writeQueueError(w http.ResponseWriter, err error) {
+	w.Header().Set("Retry-After", strconv.Itoa(queueRetryAfterSec))
+	writeAPIError(w, apiErrorFrom(err))
+}
+
 // writeError writes an error response in the standard format.
 func writeError(w http.ResponseWriter, status int, message, code string) {
-	writeJSON(w, status, ErrorResponse{
-		Error: message,
-		Code:  code,
-	})
+	writeAPIError(w, NewAPIError(status, code, message))
 }
@@ -2,20 +2,30 @@ package server
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/maauso/infinitetalk-api/internal/audio"
 	"github.com/maauso/infinitetalk-api/internal/job"
+	"github.com/maauso/infinitetalk-api/internal/media"
 	"github.com/maauso/infinitetalk-api/internal/runpod"
+	"github.com/maauso/infinitetalk-api/internal/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -31,11 +41,52 @@ func (m *mockProcessor) ResizeImageWithPadding(ctx context.Context, src, dst str
 	return args.Error(0)
 }
 
+func (m *mockProcessor) ResizeImageCropToFill(ctx context.Context, src, dst string, w, h int) error {
+	args := m.Called(ctx, src, dst, w, h)
+	return args.Error(0)
+}
+
+func (m *mockProcessor) ResizeImageToPNG(ctx context.Context, src string, w, h int, mode media.ResizeMode) ([]byte, error) {
+	args := m.Called(ctx, src, w, h, mode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *mockProcessor) NormalizeImage(ctx context.Context, src, dst string) error {
+	args := m.Called(ctx, src, dst)
+	return args.Error(0)
+}
+
 func (m *mockProcessor) JoinVideos(ctx context.Context, videoPaths []string, output string) error {
 	args := m.Called(ctx, videoPaths, output)
 	return args.Error(0)
 }
 
+func (m *mockProcessor) ReplaceAudio(ctx context.Context, video, audio, output string) error {
+	args := m.Called(ctx, video, audio, output)
+	return args.Error(0)
+}
+
+func (m *mockProcessor) ResizeVideo(ctx context.Context, src, dst string, w, h int, mode media.ResizeMode) error {
+	args := m.Called(ctx, src, dst, w, h, mode)
+	return args.Error(0)
+}
+
+func (m *mockProcessor) NormalizeFPS(ctx context.Context, src, dst string, fps float64) error {
+	args := m.Called(ctx, src, dst, fps)
+	return args.Error(0)
+}
+
+func (m *mockProcessor) ExtractFirstFrame(ctx context.Context, videoPath string) ([]byte, error) {
+	args := m.Called(ctx, videoPath)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
 func (m *mockProcessor) ExtractLastFrame(ctx context.Context, videoPath string) ([]byte, error) {
 	args := m.Called(ctx, videoPath)
 	if args.Get(0) == nil {
@@ -44,6 +95,49 @@ func (m *mockProcessor) ExtractLastFrame(ctx context.Context, videoPath string)
 	return args.Get(0).([]byte), args.Error(1)
 }
 
+func (m *mockProcessor) GenerateThumbnail(ctx context.Context, videoPath, dst string, atSec float64) error {
+	args := m.Called(ctx, videoPath, dst, atSec)
+	return args.Error(0)
+}
+
+// The probe methods report valid media unless the test set expectations for
+// them, so that tests of the processing pipeline need not mock them. Videos
+// have the 384x576 size the tests request, so chunk videos need no resizing.
+
+func (m *mockProcessor) ProbeVideo(ctx context.Context, path string) (media.VideoInfo, error) {
+	if !m.expects("ProbeVideo") {
+		return media.VideoInfo{Width: 384, Height: 576, DurationSec: 10, Codec: "h264"}, nil
+	}
+	args := m.Called(ctx, path)
+	return args.Get(0).(media.VideoInfo), args.Error(1)
+}
+
+func (m *mockProcessor) ProbeImage(ctx context.Context, path string) (media.ImageInfo, error) {
+	if !m.expects("ProbeImage") {
+		return media.ImageInfo{Width: 1024, Height: 1024, Codec: "png"}, nil
+	}
+	args := m.Called(ctx, path)
+	return args.Get(0).(media.ImageInfo), args.Error(1)
+}
+
+func (m *mockProcessor) ProbeAudio(ctx context.Context, path string) (media.AudioInfo, error) {
+	if !m.expects("ProbeAudio") {
+		return media.AudioInfo{DurationSec: 10, SampleRate: 16000, Channels: 1, Codec: "pcm_s16le"}, nil
+	}
+	args := m.Called(ctx, path)
+	return args.Get(0).(media.AudioInfo), args.Error(1)
+}
+
+// expects reports whether the test set an expectation for method.
+func (m *mockProcessor) expects(method string) bool {
+	for _, call := range m.ExpectedCalls {
+		if call.Method == method {
+			return true
+		}
+	}
+	return false
+}
+
 // mockSplitter implements audio.Splitter for testing.
 type mockSplitter struct {
 	mock.Mock
@@ -72,6 +166,16 @@ func (m *mockRunpodClient) Poll(ctx context.Context, jobID string) (runpod.PollR
 	return args.Get(0).(runpod.PollResult), args.Error(1)
 }
 
+func (m *mockRunpodClient) Cancel(ctx context.Context, jobID string) error {
+	args := m.Called(ctx, jobID)
+	return args.Error(0)
+}
+
+func (m *mockRunpodClient) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
 // mockStorage implements storage.Storage for testing.
 type mockStorage struct {
 	mock.Mock
@@ -95,11 +199,29 @@ func (m *mockStorage) CleanupTemp(ctx context.Context, paths []string) error {
 	return args.Error(0)
 }
 
-func (m *mockStorage) UploadToS3(ctx context.Context, key string, data io.Reader) (string, error) {
+func (m *mockStorage) Upload(ctx context.Context, key string, data io.Reader) (string, error) {
 	args := m.Called(ctx, key, data)
 	return args.String(0), args.Error(1)
 }
 
+func (m *mockStorage) ObjectURL(ctx context.Context, key string) (string, error) {
+	args := m.Called(ctx, key)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockStorage) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *mockStorage) DeleteObject(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
 func newTestHandlers(t *testing.T) (*Handlers, *mockProcessor, *mockSplitter, *mockRunpodClient, *mockStorage, job.Repository) {
 	t.Helper()
 	repo := job.NewMemoryRepository()
@@ -134,6 +256,131 @@ func TestHealth(t *testing.T) {
 	assert.Equal(t, "ok", resp.Status)
 }
 
+func TestLivez(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/livez", nil)
+	rec := httptest.NewRecorder()
+
+	NewRouter(h, slog.New(slog.NewTextHandler(io.Discard, nil)), Config{}).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestReadyz_AllChecksPass(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+	h.readinessChecks = []HealthChecker{
+		NewHealthCheck("ffmpeg", func(context.Context) error { return nil }),
+		NewHealthCheck("temp_dir", func(context.Context) error { return nil }),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	rec := httptest.NewRecorder()
+
+	h.Readyz(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp ReadinessResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "ok", resp.Status)
+	assert.Equal(t, []CheckResult{
+		{Name: "ffmpeg", Status: CheckStatusOK},
+		{Name: "temp_dir", Status: CheckStatusOK},
+	}, resp.Checks)
+}
+
+func TestReadyz_CheckFails(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+	h.readinessChecks = []HealthChecker{
+		NewHealthCheck("ffmpeg", func(context.Context) error { return errors.New("not found in PATH") }),
+		NewHealthCheck("temp_dir", func(context.Context) error { return nil }),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	rec := httptest.NewRecorder()
+
+	h.Readyz(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var resp ReadinessResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "unavailable", resp.Status)
+	assert.Equal(t, []CheckResult{
+		{Name: "ffmpeg", Status: CheckStatusFail, Error: "not found in PATH"},
+		{Name: "temp_dir", Status: CheckStatusOK},
+	}, resp.Checks)
+}
+
+func TestReadyz_NoChecks(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	rec := httptest.NewRecorder()
+
+	h.Readyz(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestReadyz_ProviderPing(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		wantCode   int
+		wantStatus string
+	}{
+		{"healthy", http.StatusOK, http.StatusOK, CheckStatusOK},
+		{"unhealthy", http.StatusServiceUnavailable, http.StatusServiceUnavailable, CheckStatusFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/endpoint-1/health", r.URL.Path)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer provider.Close()
+
+			client, err := runpod.NewClient("endpoint-1", runpod.WithAPIKey("key"), runpod.WithBaseURL(provider.URL))
+			require.NoError(t, err)
+
+			h, _, _, _, _, _ := newTestHandlers(t)
+			h.readinessChecks = []HealthChecker{NewHealthCheck("runpod", client.Ping)}
+
+			rec := httptest.NewRecorder()
+			h.Readyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			var resp ReadinessResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			require.Len(t, resp.Checks, 1)
+			assert.Equal(t, "runpod", resp.Checks[0].Name)
+			assert.Equal(t, tt.wantStatus, resp.Checks[0].Status)
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+
+	h.Metrics(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp MetricsResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, map[string]ProviderActivity{
+		"runpod": {},
+		"beam":   {},
+	}, resp.Providers)
+}
+
 func TestCreateJob_Success(t *testing.T) {
 	h, _, _, _, _, _ := newTestHandlers(t)
 
@@ -161,6 +408,186 @@ func TestCreateJob_Success(t *testing.T) {
 	assert.Equal(t, "IN_QUEUE", resp.Status)
 }
 
+func TestCreateJob_ExpiresAt(t *testing.T) {
+	createJob := func(t *testing.T, h *Handlers) CreateJobResponse {
+		t.Helper()
+		bodyJSON, _ := json.Marshal(CreateJobRequest{
+			ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+			AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+			Width:       384,
+			Height:      576,
+		})
+		req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+		rec := httptest.NewRecorder()
+
+		h.CreateJob(rec, req)
+
+		require.Equal(t, http.StatusAccepted, rec.Code)
+		var resp CreateJobResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		return resp
+	}
+
+	t.Run("with TTL", func(t *testing.T) {
+		h, _, _, _, _, repo := newTestHandlers(t)
+		job.WithJobTTL(time.Hour)(h.service)
+
+		created := createJob(t, h)
+		require.NotNil(t, created.ExpiresAt)
+
+		saved, err := repo.FindByID(context.Background(), created.ID)
+		require.NoError(t, err)
+		assert.True(t, created.ExpiresAt.Equal(saved.CreatedAt.Add(time.Hour)),
+			"expires_at %v should be one hour after creation %v", created.ExpiresAt, saved.CreatedAt)
+
+		// GET /jobs/{id} reports the same expiry
+		req := httptest.NewRequest(http.MethodGet, "/jobs/"+created.ID, nil)
+		req.SetPathValue("id", created.ID)
+		rec := httptest.NewRecorder()
+		h.GetJob(rec, req)
+
+		var resp JobResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		require.NotNil(t, resp.ExpiresAt)
+		assert.True(t, resp.ExpiresAt.Equal(*created.ExpiresAt))
+	})
+
+	t.Run("retention disabled", func(t *testing.T) {
+		h, _, _, _, _, _ := newTestHandlers(t)
+
+		created := createJob(t, h)
+		assert.Nil(t, created.ExpiresAt)
+	})
+}
+
+func TestCreateJob_RetentionSec(t *testing.T) {
+	createJob := func(t *testing.T, h *Handlers, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.CreateJob(rec, req)
+		return rec
+	}
+	getJob := func(t *testing.T, h *Handlers, id string) JobResponse {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/jobs/"+id, nil)
+		req.SetPathValue("id", id)
+		rec := httptest.NewRecorder()
+		h.GetJob(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp JobResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		return resp
+	}
+	inputs := `"image_base64":"dGVzdC1pbWFnZQ==","audio_base64":"dGVzdC1hdWRpbw==","width":384,"height":576`
+
+	t.Run("overrides the TTL", func(t *testing.T) {
+		h, _, _, _, _, repo := newTestHandlers(t)
+		job.WithJobTTL(time.Hour)(h.service)
+
+		rec := createJob(t, h, `{`+inputs+`,"retention_sec":86400}`)
+		require.Equal(t, http.StatusAccepted, rec.Code)
+		var created CreateJobResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&created))
+
+		saved, err := repo.FindByID(context.Background(), created.ID)
+		require.NoError(t, err)
+		require.NotNil(t, created.ExpiresAt)
+		assert.True(t, created.ExpiresAt.Equal(saved.CreatedAt.Add(24*time.Hour)))
+
+		resp := getJob(t, h, created.ID)
+		require.NotNil(t, resp.RetentionSec)
+		assert.Equal(t, 86400, *resp.RetentionSec)
+		require.NotNil(t, resp.ExpiresAt)
+		assert.True(t, resp.ExpiresAt.Equal(*created.ExpiresAt))
+	})
+
+	t.Run("zero is reported", func(t *testing.T) {
+		h, _, _, _, _, _ := newTestHandlers(t)
+
+		rec := createJob(t, h, `{`+inputs+`,"retention_sec":0}`)
+		require.Equal(t, http.StatusAccepted, rec.Code)
+		var created CreateJobResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&created))
+		assert.Nil(t, created.ExpiresAt)
+
+		resp := getJob(t, h, created.ID)
+		require.NotNil(t, resp.RetentionSec)
+		assert.Equal(t, 0, *resp.RetentionSec)
+		assert.Nil(t, resp.VideoDownloadedAt)
+	})
+
+	t.Run("omitted without retention", func(t *testing.T) {
+		h, _, _, _, _, _ := newTestHandlers(t)
+
+		rec := createJob(t, h, `{`+inputs+`}`)
+		require.Equal(t, http.StatusAccepted, rec.Code)
+		var created CreateJobResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&created))
+
+		assert.Nil(t, getJob(t, h, created.ID).RetentionSec)
+	})
+
+	t.Run("above the maximum", func(t *testing.T) {
+		h, _, _, _, _, repo := newTestHandlers(t)
+		job.WithMaxRetention(time.Hour)(h.service)
+
+		rec := createJob(t, h, `{`+inputs+`,"retention_sec":3601}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var resp ErrorResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, "INVALID_RETENTION", resp.Code)
+
+		jobs, err := repo.List(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, jobs)
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		h, _, _, _, _, _ := newTestHandlers(t)
+
+		rec := createJob(t, h, `{`+inputs+`,"retention_sec":-1}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var resp ErrorResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, "VALIDATION_ERROR", resp.Code)
+	})
+}
+
+func TestCreateJob_PollHints(t *testing.T) {
+	tests := []struct {
+		name         string
+		pollInterval time.Duration
+		wantSec      int
+	}{
+		{"rounds sub-second intervals up to a second", 10 * time.Millisecond, 1},
+		{"rounds up to whole seconds", 7500 * time.Millisecond, 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _, _, _, _ := newTestHandlers(t)
+			job.WithPollInterval(tt.pollInterval)(h.service)
+
+			bodyJSON, _ := json.Marshal(CreateJobRequest{
+				ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+				AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+				Width:       384,
+				Height:      576,
+			})
+			rec := httptest.NewRecorder()
+			h.CreateJob(rec, httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON)))
+
+			require.Equal(t, http.StatusAccepted, rec.Code)
+			var resp CreateJobResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, "/jobs/"+resp.ID, resp.PollURL)
+			assert.Equal(t, tt.wantSec, resp.RecommendedPollIntervalSec)
+			assert.Equal(t, strconv.Itoa(tt.wantSec), rec.Header().Get("Retry-After"))
+		})
+	}
+}
+
 func TestCreateJob_InvalidJSON(t *testing.T) {
 	h, _, _, _, _, _ := newTestHandlers(t)
 
@@ -178,40 +605,111 @@ func TestCreateJob_InvalidJSON(t *testing.T) {
 	assert.Equal(t, "INVALID_JSON", resp.Code)
 }
 
-func TestCreateJob_ValidationError_MissingFields(t *testing.T) {
-	h, _, _, _, _, _ := newTestHandlers(t)
-
-	body := CreateJobRequest{
-		Width:  384,
-		Height: 576,
-	}
-	bodyJSON, _ := json.Marshal(body)
+func TestCreateJob_PayloadTooLarge(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	h.maxRequestBytes = 1024
 
+	bodyJSON, _ := json.Marshal(CreateJobRequest{
+		ImageBase64: base64.StdEncoding.EncodeToString(make([]byte, 2048)),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+		Width:       384,
+		Height:      576,
+	})
 	req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
 
 	h.CreateJob(rec, req)
 
-	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
 
 	var resp ErrorResponse
-	err := json.NewDecoder(rec.Body).Decode(&resp)
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "PAYLOAD_TOO_LARGE", resp.Code)
+
+	jobs, err := repo.List(context.Background())
 	require.NoError(t, err)
-	assert.Equal(t, "VALIDATION_ERROR", resp.Code)
+	assert.Empty(t, jobs)
 }
 
-func TestCreateJob_ValidationError_InvalidDimensions(t *testing.T) {
+func TestCreateJob_WithinMaxRequestBytes(t *testing.T) {
 	h, _, _, _, _, _ := newTestHandlers(t)
+	h.maxRequestBytes = 1024
 
-	body := CreateJobRequest{
+	bodyJSON, _ := json.Marshal(CreateJobRequest{
 		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
 		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
-		Width:       0,    // Invalid
-		Height:      5000, // Invalid (> 4096)
+		Width:       384,
+		Height:      576,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	h.CreateJob(rec, req)
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+}
+
+func TestCreateJob_InputTypeCheck(t *testing.T) {
+	png := base64.StdEncoding.EncodeToString(append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 32)...))
+	wav := base64.StdEncoding.EncodeToString(append([]byte("RIFF\x24\x00\x00\x00WAVEfmt "), make([]byte, 32)...))
+	text := base64.StdEncoding.EncodeToString([]byte("hello world"))
+
+	tests := []struct {
+		name       string
+		image      string
+		audio      string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "valid inputs", image: png, audio: wav, wantStatus: http.StatusAccepted},
+		{name: "swapped inputs", image: wav, audio: png, wantStatus: http.StatusBadRequest, wantCode: "INPUTS_SWAPPED"},
+		{name: "image is not an image", image: text, audio: wav, wantStatus: http.StatusBadRequest, wantCode: "INVALID_INPUT_TYPE"},
+		{name: "audio is not audio", image: png, audio: text, wantStatus: http.StatusBadRequest, wantCode: "INVALID_INPUT_TYPE"},
+		{name: "both inputs are images", image: png, audio: png, wantStatus: http.StatusBadRequest, wantCode: "INVALID_INPUT_TYPE"},
 	}
-	bodyJSON, _ := json.Marshal(body)
 
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _, _, _, _ := newTestHandlers(t)
+			h.checkInputTypes = true
+
+			bodyJSON, _ := json.Marshal(CreateJobRequest{
+				ImageBase64: tt.image,
+				AudioBase64: tt.audio,
+				Width:       384,
+				Height:      576,
+			})
+			req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			h.CreateJob(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantCode != "" {
+				var resp ErrorResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, tt.wantCode, resp.Code)
+			}
+		})
+	}
+}
+
+func TestCreateJob_InputTypeCheck_Swapped(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	h.checkInputTypes = true
+
+	jpeg := base64.StdEncoding.EncodeToString(append([]byte{0xFF, 0xD8, 0xFF, 0xE0}, make([]byte, 32)...))
+	mp3 := base64.StdEncoding.EncodeToString(append([]byte("ID3\x03\x00"), make([]byte, 32)...))
+
+	bodyJSON, _ := json.Marshal(CreateJobRequest{
+		ImageBase64: mp3,
+		AudioBase64: jpeg,
+		Width:       384,
+		Height:      576,
+	})
 	req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
@@ -221,349 +719,2823 @@ func TestCreateJob_ValidationError_InvalidDimensions(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 
 	var resp ErrorResponse
-	err := json.NewDecoder(rec.Body).Decode(&resp)
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "INPUTS_SWAPPED", resp.Code)
+	assert.Contains(t, resp.Error, "inputs appear swapped")
+
+	// The request is rejected before a job is created
+	jobs, err := repo.List(context.Background())
 	require.NoError(t, err)
-	assert.Equal(t, "VALIDATION_ERROR", resp.Code)
+	assert.Empty(t, jobs)
 }
 
-func TestGetJob_Success(t *testing.T) {
-	h, _, _, _, _, repo := newTestHandlers(t)
-	ctx := context.Background()
+func TestCreateJob_ValidateOnly(t *testing.T) {
+	h, processor, _, _, storage, repo := newTestHandlers(t)
 
-	// Create a job in the repository
-	testJob := job.New()
-	testJob.Width = 384
-	testJob.Height = 576
-	testJob.UpdateProgress(50)
-	err := repo.Save(ctx, testJob)
-	require.NoError(t, err)
+	storage.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+	storage.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+	storage.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil).Once()
+	processor.On("ProbeImage", mock.Anything, "/tmp/image.png").
+		Return(media.ImageInfo{Width: 1024, Height: 1536, Codec: "png"}, nil)
+	processor.On("ProbeAudio", mock.Anything, "/tmp/audio.wav").
+		Return(media.AudioInfo{DurationSec: 12.5, SampleRate: 16000, Channels: 1, Codec: "pcm_s16le"}, nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID, nil)
-	req.SetPathValue("id", testJob.ID)
+	bodyJSON, _ := json.Marshal(CreateJobRequest{
+		ImageBase64:  base64.StdEncoding.EncodeToString([]byte("test-image")),
+		AudioBase64:  base64.StdEncoding.EncodeToString([]byte("test-audio")),
+		Width:        384,
+		Height:       576,
+		ValidateOnly: true,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
 
-	h.GetJob(rec, req)
+	h.CreateJob(rec, req)
 
 	assert.Equal(t, http.StatusOK, rec.Code)
 
-	var resp JobResponse
-	err = json.NewDecoder(rec.Body).Decode(&resp)
+	var resp CreateJobResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Empty(t, resp.ID)
+	assert.Equal(t, "VALIDATED", resp.Status)
+	require.NotNil(t, resp.Probe)
+	assert.Equal(t, 1024, resp.Probe.ImageWidth)
+	assert.Equal(t, 1536, resp.Probe.ImageHeight)
+	assert.InDelta(t, 12.5, resp.Probe.AudioDurationSec, 0.001)
+	assert.Equal(t, 1, resp.Probe.EstimatedChunks)
+
+	// No job is created in validate-only mode
+	jobs, err := repo.List(context.Background())
 	require.NoError(t, err)
-	assert.Equal(t, testJob.ID, resp.ID)
-	assert.Equal(t, "IN_QUEUE", resp.Status)
-	assert.Equal(t, 50, resp.Progress)
+	assert.Empty(t, jobs)
+	storage.AssertExpectations(t)
 }
 
-func TestGetJob_NotFound(t *testing.T) {
-	h, _, _, _, _, _ := newTestHandlers(t)
-
-	req := httptest.NewRequest(http.MethodGet, "/jobs/nonexistent", nil)
-	req.SetPathValue("id", "nonexistent")
-	rec := httptest.NewRecorder()
+func TestCreateJob_ValidateOnly_InvalidInputs(t *testing.T) {
+	tests := []struct {
+		name     string
+		imageErr error
+		audioErr error
+		wantCode string
+	}{
+		{name: "invalid image", imageErr: media.ErrNoVideoStream, wantCode: "INVALID_IMAGE"},
+		{name: "invalid audio", audioErr: media.ErrNoAudioStream, wantCode: "INVALID_AUDIO"},
+	}
 
-	h.GetJob(rec, req)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, processor, _, _, storage, repo := newTestHandlers(t)
+
+			storage.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return("/tmp/image.png", nil).Once()
+			storage.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return("/tmp/audio.wav", nil).Once()
+			storage.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil).Once()
+			processor.On("ProbeImage", mock.Anything, "/tmp/image.png").
+				Return(media.ImageInfo{Width: 64, Height: 64}, tt.imageErr)
+			processor.On("ProbeAudio", mock.Anything, "/tmp/audio.wav").
+				Return(media.AudioInfo{DurationSec: 1}, tt.audioErr)
+
+			bodyJSON, _ := json.Marshal(CreateJobRequest{
+				ImageBase64:  base64.StdEncoding.EncodeToString([]byte("test-image")),
+				AudioBase64:  base64.StdEncoding.EncodeToString([]byte("test-audio")),
+				Width:        384,
+				Height:       576,
+				ValidateOnly: true,
+			})
+			req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			h.CreateJob(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+			var resp ErrorResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, tt.wantCode, resp.Code)
+
+			jobs, err := repo.List(context.Background())
+			require.NoError(t, err)
+			assert.Empty(t, jobs)
+		})
+	}
+}
 
-	assert.Equal(t, http.StatusNotFound, rec.Code)
+func TestCreateJob_PersonCount(t *testing.T) {
+	tests := []struct {
+		name        string
+		personCount string
+		wantStatus  int
+		want        string
+	}{
+		{name: "defaults to single", personCount: "", wantStatus: http.StatusAccepted, want: job.PersonCountSingle},
+		{name: "multi", personCount: "multi", wantStatus: http.StatusAccepted, want: job.PersonCountMulti},
+		{name: "invalid", personCount: "crowd", wantStatus: http.StatusBadRequest},
+	}
 
-	var resp ErrorResponse
-	err := json.NewDecoder(rec.Body).Decode(&resp)
-	require.NoError(t, err)
-	assert.Equal(t, "JOB_NOT_FOUND", resp.Code)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _, _, _, repo := newTestHandlers(t)
+
+			bodyJSON, _ := json.Marshal(CreateJobRequest{
+				ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+				AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+				Width:       384,
+				Height:      576,
+				PersonCount: tt.personCount,
+			})
+			req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			h.CreateJob(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus != http.StatusAccepted {
+				return
+			}
+
+			var resp CreateJobResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			created, err := repo.FindByID(context.Background(), resp.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, created.PersonCount)
+		})
+	}
 }
 
-func TestGetJob_MissingID(t *testing.T) {
-	h, _, _, _, _, _ := newTestHandlers(t)
+func TestCreateJob_PersonCountReachesProvider(t *testing.T) {
+	submitted := submitThroughProvider(t, CreateJobRequest{PersonCount: "multi"})
+	assert.Equal(t, "multi", submitted.PersonCount)
+}
 
-	req := httptest.NewRequest(http.MethodGet, "/jobs/", nil)
-	// Don't set path value to simulate missing ID
+// submitThroughProvider creates a job from req with background processing
+// enabled, waits for it to complete and returns the options it was submitted
+// to RunPod with. Inputs and dimensions are filled in when req leaves them empty.
+func submitThroughProvider(t *testing.T, req CreateJobRequest) runpod.SubmitOptions {
+	t.Helper()
+	h, processor, splitter, runpodClient, storageClient, repo := newTestHandlers(t)
+	h.enableAsyncProcess = true
+	tempDir := t.TempDir()
+	chunkPath := filepath.Join(tempDir, "chunk_000.wav")
+	require.NoError(t, os.WriteFile(chunkPath, []byte("audio"), 0600))
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return(filepath.Join(tempDir, "image.png"), nil)
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return(filepath.Join(tempDir, "audio.wav"), nil)
+	storageClient.On("SaveTemp", mock.Anything, mock.Anything, mock.Anything).Return(filepath.Join(tempDir, "chunk_0.mp4"), nil)
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+	processor.On("ResizeImageWithPadding", mock.Anything, mock.Anything, mock.Anything, 1024, 1024).
+		Run(func(args mock.Arguments) {
+			_ = os.WriteFile(args.String(2), []byte("resized"), 0600)
+		}).Return(nil)
+	processor.On("JoinVideos", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]string{chunkPath}, nil)
+
+	var submitted runpod.SubmitOptions
+	runpodClient.On("Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			submitted = args.Get(3).(runpod.SubmitOptions)
+		}).
+		Return("runpod-job-1", nil).Once()
+	runpodClient.On("Poll", mock.Anything, "runpod-job-1").
+		Return(runpod.PollResult{Status: runpod.StatusCompleted, VideoBase64: base64.StdEncoding.EncodeToString([]byte("video"))}, nil)
+
+	if req.ImageBase64 == "" {
+		req.ImageBase64 = base64.StdEncoding.EncodeToString([]byte("test-image"))
+	}
+	if req.AudioBase64 == "" {
+		req.AudioBase64 = base64.StdEncoding.EncodeToString([]byte("test-audio"))
+	}
+	if req.Width == 0 || req.Height == 0 {
+		req.Width, req.Height = 384, 576
+	}
+	bodyJSON, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+	httpReq.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
 
-	h.GetJob(rec, req)
+	h.CreateJob(rec, httpReq)
+	require.Equal(t, http.StatusAccepted, rec.Code)
 
-	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var resp CreateJobResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, h.Drain(ctx))
+
+	found, err := repo.FindByID(context.Background(), resp.ID)
+	require.NoError(t, err)
+	require.Equal(t, job.StatusCompleted, found.Status, found.Error)
+	runpodClient.AssertExpectations(t)
+	return submitted
+}
+
+func TestCreateJob_InputType(t *testing.T) {
+	image := base64.StdEncoding.EncodeToString([]byte("test-image"))
+	video := base64.StdEncoding.EncodeToString([]byte("test-video"))
+
+	tests := []struct {
+		name       string
+		req        CreateJobRequest
+		wantStatus int
+		wantCode   string
+		want       string
+	}{
+		{
+			name:       "defaults to image",
+			req:        CreateJobRequest{ImageBase64: image},
+			wantStatus: http.StatusAccepted,
+			want:       job.InputTypeImage,
+		},
+		{
+			name:       "video",
+			req:        CreateJobRequest{InputType: "video", VideoBase64: video},
+			wantStatus: http.StatusAccepted,
+			want:       job.InputTypeVideo,
+		},
+		{
+			name:       "video without video_base64",
+			req:        CreateJobRequest{InputType: "video", ImageBase64: image},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "VALIDATION_ERROR",
+		},
+		{
+			name:       "image without image_base64",
+			req:        CreateJobRequest{InputType: "image", VideoBase64: video},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "VALIDATION_ERROR",
+		},
+		{
+			name:       "unknown input type",
+			req:        CreateJobRequest{InputType: "gif", ImageBase64: image},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "VALIDATION_ERROR",
+		},
+		{
+			name:       "video on beam",
+			req:        CreateJobRequest{InputType: "video", VideoBase64: video, Provider: "beam"},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "UNSUPPORTED_INPUT_TYPE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _, _, _, repo := newTestHandlers(t)
+
+			tt.req.AudioBase64 = base64.StdEncoding.EncodeToString([]byte("test-audio"))
+			tt.req.Width = 384
+			tt.req.Height = 576
+			bodyJSON, _ := json.Marshal(tt.req)
+			req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			h.CreateJob(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantCode != "" {
+				var resp ErrorResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, tt.wantCode, resp.Code)
+				return
+			}
+
+			var resp CreateJobResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			created, err := repo.FindByID(context.Background(), resp.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, created.InputType)
+		})
+	}
+}
+
+func TestCreateJob_InputTypeCheck_Video(t *testing.T) {
+	mp4 := base64.StdEncoding.EncodeToString(append([]byte("\x00\x00\x00\x18ftypmp42"), make([]byte, 32)...))
+	wav := base64.StdEncoding.EncodeToString(append([]byte("RIFF\x24\x00\x00\x00WAVEfmt "), make([]byte, 32)...))
+	text := base64.StdEncoding.EncodeToString([]byte("hello world"))
+
+	tests := []struct {
+		name       string
+		audio      string
+		wantStatus int
+	}{
+		{name: "audio is audio", audio: wav, wantStatus: http.StatusAccepted},
+		{name: "audio is not audio", audio: text, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _, _, _, _ := newTestHandlers(t)
+			h.checkInputTypes = true
+
+			bodyJSON, _ := json.Marshal(CreateJobRequest{
+				InputType:   "video",
+				VideoBase64: mp4,
+				AudioBase64: tt.audio,
+				Width:       384,
+				Height:      576,
+			})
+			req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			h.CreateJob(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestCreateJob_URLInputs(t *testing.T) {
+	png := base64.StdEncoding.EncodeToString(append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 32)...))
+	wav := base64.StdEncoding.EncodeToString(append([]byte("RIFF\x24\x00\x00\x00WAVEfmt "), make([]byte, 32)...))
+	text := base64.StdEncoding.EncodeToString([]byte("hello world"))
+
+	tests := []struct {
+		name       string
+		req        CreateJobRequest
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "both by URL",
+			req:        CreateJobRequest{ImageURL: "https://cdn.example.com/face.png", AudioURL: "https://cdn.example.com/voice.wav"},
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:       "image by URL",
+			req:        CreateJobRequest{ImageURL: "http://cdn.example.com/face.png", AudioBase64: wav},
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:       "audio by URL",
+			req:        CreateJobRequest{ImageBase64: png, AudioURL: "https://cdn.example.com/voice.wav"},
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:       "inline audio is still checked",
+			req:        CreateJobRequest{ImageURL: "https://cdn.example.com/face.png", AudioBase64: text},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_INPUT_TYPE",
+		},
+		{
+			name:       "inline image is still checked",
+			req:        CreateJobRequest{ImageBase64: text, AudioURL: "https://cdn.example.com/voice.wav"},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_INPUT_TYPE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _, _, _, repo := newTestHandlers(t)
+			h.checkInputTypes = true
+
+			tt.req.Width, tt.req.Height = 384, 576
+			bodyJSON, _ := json.Marshal(tt.req)
+			req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			h.CreateJob(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantCode != "" {
+				var resp ErrorResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, tt.wantCode, resp.Code)
+				return
+			}
+
+			var resp CreateJobResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			_, err := repo.FindByID(context.Background(), resp.ID)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCreateJob_URLInputs_ValidationError(t *testing.T) {
+	png := base64.StdEncoding.EncodeToString([]byte("test-image"))
+	wav := base64.StdEncoding.EncodeToString([]byte("test-audio"))
+
+	tests := []struct {
+		name string
+		req  CreateJobRequest
+		want FieldError
+	}{
+		{
+			name: "image given twice",
+			req:  CreateJobRequest{ImageBase64: png, ImageURL: "https://cdn.example.com/face.png", AudioBase64: wav},
+			want: FieldError{Field: "image_url", Tag: "excluded_with", Param: "image_base64", Message: "cannot be combined with image_base64"},
+		},
+		{
+			name: "audio given twice",
+			req:  CreateJobRequest{ImageBase64: png, AudioBase64: wav, AudioURL: "https://cdn.example.com/voice.wav"},
+			want: FieldError{Field: "audio_url", Tag: "excluded_with", Param: "audio_base64", Message: "cannot be combined with audio_base64"},
+		},
+		{
+			name: "image URL for a video input",
+			req:  CreateJobRequest{InputType: "video", VideoBase64: png, ImageURL: "https://cdn.example.com/face.png", AudioBase64: wav},
+			want: FieldError{Field: "image_url", Tag: "excluded_if", Param: "input_type video", Message: "is not allowed when input_type is video"},
+		},
+		{
+			name: "file scheme",
+			req:  CreateJobRequest{ImageURL: "file:///etc/passwd", AudioBase64: wav},
+			want: FieldError{Field: "image_url", Tag: "http_url", Message: "must be an http or https URL"},
+		},
+		{
+			name: "ftp scheme",
+			req:  CreateJobRequest{ImageBase64: png, AudioURL: "ftp://cdn.example.com/voice.wav"},
+			want: FieldError{Field: "audio_url", Tag: "http_url", Message: "must be an http or https URL"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _, _, _, repo := newTestHandlers(t)
+
+			tt.req.Width, tt.req.Height = 384, 576
+			bodyJSON, _ := json.Marshal(tt.req)
+			req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			h.CreateJob(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+			var resp struct {
+				Code    string `json:"code"`
+				Details struct {
+					Fields []FieldError `json:"fields"`
+				} `json:"details"`
+			}
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, "VALIDATION_ERROR", resp.Code)
+			assert.Equal(t, []FieldError{tt.want}, resp.Details.Fields)
+
+			jobs, err := repo.List(context.Background())
+			require.NoError(t, err)
+			assert.Empty(t, jobs)
+		})
+	}
+}
+
+func TestCreateJob_ValidationError_MissingFields(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	body := CreateJobRequest{
+		Width:  384,
+		Height: 576,
+	}
+	bodyJSON, _ := json.Marshal(body)
+
+	req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	h.CreateJob(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var resp ErrorResponse
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	require.NoError(t, err)
+	assert.Equal(t, "VALIDATION_ERROR", resp.Code)
+}
+
+func TestCreateJob_ValidationError_FieldDetails(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	bodyJSON := []byte(`{"audio_base64":"not base64!","width":0,"height":576,"provider":"other"}`)
+	req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	h.CreateJob(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var resp struct {
+		Error   string `json:"error"`
+		Code    string `json:"code"`
+		Details struct {
+			Fields []FieldError `json:"fields"`
+		} `json:"details"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "VALIDATION_ERROR", resp.Code)
+	assert.NotContains(t, resp.Error, "Key: ", "message should not be the raw validator string")
+	assert.Equal(t, []FieldError{
+		{Field: "audio_base64", Tag: "base64", Message: "must be valid base64"},
+		{Field: "width", Tag: "required", Message: "is required"},
+		{Field: "provider", Tag: "oneof", Param: "runpod beam", Message: "must be one of: runpod, beam"},
+		{Field: "image_base64", Tag: "required_without", Param: "image_url", Message: "is required"},
+	}, resp.Details.Fields)
+}
+
+func TestCreateJob_ValidationError_EachMissingField(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader(`{"provider":"runpod"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	h.CreateJob(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var resp struct {
+		Error   string `json:"error"`
+		Code    string `json:"code"`
+		Details struct {
+			Fields []FieldError `json:"fields"`
+		} `json:"details"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "VALIDATION_ERROR", resp.Code)
+
+	rules := make(map[string]string, len(resp.Details.Fields))
+	for _, f := range resp.Details.Fields {
+		rules[f.Field] = f.Tag
+		assert.Equal(t, "is required", f.Message, f.Field)
+	}
+	assert.Equal(t, map[string]string{
+		"image_base64": "required_without",
+		"audio_base64": "required_without",
+		"width":        "required",
+		"height":       "required",
+	}, rules)
+	for field := range rules {
+		assert.Contains(t, resp.Error, field+" is required")
+	}
+}
+
+func TestCreateJob_ValidationError_DimensionFields(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+	WithDimensionMultiple(16)(h)
+
+	bodyJSON, _ := json.Marshal(CreateJobRequest{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+		Width:       383,
+		Height:      577,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	h.CreateJob(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var resp struct {
+		Code    string `json:"code"`
+		Details struct {
+			Fields []FieldError `json:"fields"`
+		} `json:"details"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "VALIDATION_ERROR", resp.Code)
+	assert.Equal(t, []FieldError{
+		{Field: "width", Tag: "multiple_of", Param: "16", Message: "must be a multiple of 16 (nearest valid value: 384)"},
+		{Field: "height", Tag: "multiple_of", Param: "16", Message: "must be a multiple of 16 (nearest valid value: 576)"},
+	}, resp.Details.Fields)
+}
+
+func TestCreateJob_ValidationError_InvalidDimensions(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	body := CreateJobRequest{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+		Width:       0,    // Invalid
+		Height:      5000, // Invalid (> 4096)
+	}
+	bodyJSON, _ := json.Marshal(body)
+
+	req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	h.CreateJob(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var resp ErrorResponse
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	require.NoError(t, err)
+	assert.Equal(t, "VALIDATION_ERROR", resp.Code)
+}
+
+func TestCreateJob_ValidationError_InvalidResizeMode(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	body := CreateJobRequest{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+		Width:       384,
+		Height:      576,
+		ResizeMode:  "stretch",
+	}
+	bodyJSON, _ := json.Marshal(body)
+
+	req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	h.CreateJob(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var resp ErrorResponse
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	require.NoError(t, err)
+	assert.Equal(t, "VALIDATION_ERROR", resp.Code)
+}
+
+func TestCreateJob_ValidationError_DimensionMultiple(t *testing.T) {
+	tests := []struct {
+		name       string
+		multiple   int
+		width      int
+		height     int
+		wantStatus int
+		wantError  string
+	}{
+		{name: "multiples accepted", multiple: 16, width: 384, height: 576, wantStatus: http.StatusAccepted},
+		{name: "odd width rejected", multiple: 16, width: 383, height: 576, wantStatus: http.StatusBadRequest, wantError: "width 383 must be a multiple of 16 (nearest valid value: 384)"},
+		{name: "odd height rejected", multiple: 8, width: 384, height: 577, wantStatus: http.StatusBadRequest, wantError: "height 577 must be a multiple of 8 (nearest valid value: 576)"},
+		{name: "check disabled", multiple: 0, width: 383, height: 577, wantStatus: http.StatusAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _, _, _, _ := newTestHandlers(t)
+			WithDimensionMultiple(tt.multiple)(h)
+
+			bodyJSON, _ := json.Marshal(CreateJobRequest{
+				ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+				AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+				Width:       tt.width,
+				Height:      tt.height,
+			})
+			req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			h.CreateJob(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantError != "" {
+				var resp ErrorResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, "VALIDATION_ERROR", resp.Code)
+				assert.Contains(t, resp.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestCreateJob_SnapDimensions(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	WithDimensionMultiple(16)(h)
+
+	bodyJSON, _ := json.Marshal(CreateJobRequest{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+		Width:       383,
+		Height:      570,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/jobs?snap=true", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	h.CreateJob(rec, req)
+
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	var resp CreateJobResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, 384, resp.Width)
+	assert.Equal(t, 576, resp.Height)
+
+	// The job is created with the snapped dimensions
+	saved, err := repo.FindByID(context.Background(), resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 384, saved.Width)
+	assert.Equal(t, 576, saved.Height)
+}
+
+func TestCreateJob_AllowedDimensions(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		query         string
+		wantStatus    int
+	}{
+		{name: "first preset", width: 384, height: 576, wantStatus: http.StatusAccepted},
+		{name: "second preset", width: 512, height: 512, wantStatus: http.StatusAccepted},
+		{name: "swapped preset", width: 576, height: 384, wantStatus: http.StatusBadRequest},
+		{name: "valid multiple not in presets", width: 640, height: 640, wantStatus: http.StatusBadRequest},
+		{name: "snap does not round to a preset", width: 383, height: 577, query: "?snap=true", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _, _, _, _ := newTestHandlers(t)
+			WithDimensionMultiple(16)(h)
+			WithAllowedDimensions("384x576", "512x512")(h)
+
+			bodyJSON, _ := json.Marshal(CreateJobRequest{
+				ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+				AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+				Width:       tt.width,
+				Height:      tt.height,
+			})
+			req := httptest.NewRequest(http.MethodPost, "/jobs"+tt.query, bytes.NewReader(bodyJSON))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			h.CreateJob(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantStatus != http.StatusBadRequest {
+				return
+			}
+
+			var resp struct {
+				Code    string `json:"code"`
+				Error   string `json:"error"`
+				Details struct {
+					Fields []FieldError `json:"fields"`
+				} `json:"details"`
+			}
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, "VALIDATION_ERROR", resp.Code)
+			assert.Contains(t, resp.Error, "384x576, 512x512")
+			require.Len(t, resp.Details.Fields, 2)
+			assert.Equal(t, "allowed_dimensions", resp.Details.Fields[0].Tag)
+			assert.Equal(t, "384x576, 512x512", resp.Details.Fields[0].Param)
+		})
+	}
+}
+
+// createJobWithMetadata creates a job through h with the given metadata and returns its ID.
+func createJobWithMetadata(t *testing.T, h *Handlers, metadata map[string]string) string {
+	t.Helper()
+	bodyJSON, _ := json.Marshal(CreateJobRequest{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+		Width:       384,
+		Height:      576,
+		Metadata:    metadata,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+	rec := httptest.NewRecorder()
+
+	h.CreateJob(rec, req)
+
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	var resp CreateJobResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	return resp.ID
+}
+
+func TestCreateJob_MetadataRoundTrip(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+	metadata := map[string]string{"customer": "acme", "source_row": "42"}
+	jobID := createJobWithMetadata(t, h, metadata)
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/"+jobID, nil)
+	req.SetPathValue("id", jobID)
+	rec := httptest.NewRecorder()
+
+	h.GetJob(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp JobResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, metadata, resp.Metadata)
+
+	// Jobs without metadata omit the field
+	plainID := createJobWithMetadata(t, h, nil)
+	req = httptest.NewRequest(http.MethodGet, "/jobs/"+plainID, nil)
+	req.SetPathValue("id", plainID)
+	rec = httptest.NewRecorder()
+
+	h.GetJob(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), `"metadata"`)
+}
+
+func TestCreateJob_ValidationError_Metadata(t *testing.T) {
+	tooMany := make(map[string]string)
+	for i := range 17 {
+		tooMany[fmt.Sprintf("key%d", i)] = "v"
+	}
+
+	tests := []struct {
+		name     string
+		metadata map[string]string
+	}{
+		{"too many entries", tooMany},
+		{"empty key", map[string]string{"": "v"}},
+		{"key too long", map[string]string{strings.Repeat("k", 65): "v"}},
+		{"key with colon", map[string]string{"customer:id": "v"}},
+		{"value too long", map[string]string{"customer": strings.Repeat("v", 257)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _, _, _, _ := newTestHandlers(t)
+
+			bodyJSON, _ := json.Marshal(CreateJobRequest{
+				ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+				AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+				Width:       384,
+				Height:      576,
+				Metadata:    tt.metadata,
+			})
+			req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+			rec := httptest.NewRecorder()
+
+			h.CreateJob(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			var resp ErrorResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, "VALIDATION_ERROR", resp.Code)
+		})
+	}
+}
+
+func TestListJobs_TagFilter(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+	acme1 := createJobWithMetadata(t, h, map[string]string{"customer": "acme", "row": "1"})
+	globex := createJobWithMetadata(t, h, map[string]string{"customer": "globex"})
+	acme2 := createJobWithMetadata(t, h, map[string]string{"customer": "acme", "row": "2", "note": "a:b"})
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"no filter", "", []string{acme1, globex, acme2}},
+		{"single tag", "?tag=customer:acme", []string{acme1, acme2}},
+		{"several tags", "?tag=customer:acme&tag=row:2", []string{acme2}},
+		{"value with colon", "?tag=note:a:b", []string{acme2}},
+		{"no match", "?tag=customer:initech", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/jobs"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			h.ListJobs(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			var resp JobListResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			got := make([]string, 0, len(resp.Jobs))
+			for _, j := range resp.Jobs {
+				got = append(got, j.ID)
+			}
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+
+	// Listed jobs carry their metadata
+	req := httptest.NewRequest(http.MethodGet, "/jobs?tag=customer:globex", nil)
+	rec := httptest.NewRecorder()
+	h.ListJobs(rec, req)
+	var resp JobListResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	require.Len(t, resp.Jobs, 1)
+	assert.Equal(t, map[string]string{"customer": "globex"}, resp.Jobs[0].Metadata)
+}
+
+func TestListJobs_InvalidTag(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	for _, query := range []string{"?tag=customer", "?tag=:acme", "?tag=customer:acme&tag=customer:globex"} {
+		req := httptest.NewRequest(http.MethodGet, "/jobs"+query, nil)
+		rec := httptest.NewRecorder()
+
+		h.ListJobs(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		var resp ErrorResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, "VALIDATION_ERROR", resp.Code, query)
+	}
+}
+
+// saveRunningJob saves a RUNNING job last updated at updatedAt.
+func saveRunningJob(t *testing.T, repo job.Repository, updatedAt time.Time) *job.Job {
+	t.Helper()
+	j := job.New()
+	j.Provider = job.ProviderRunPod
+	require.NoError(t, j.Start())
+	j.UpdatedAt = updatedAt
+	require.NoError(t, repo.Save(context.Background(), j))
+	return j
+}
+
+func TestAdminListJobs_Stuck(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	stuck := saveRunningJob(t, repo, time.Now().Add(-2*job.DefaultStuckJobThreshold))
+	active := saveRunningJob(t, repo, time.Now())
+
+	list := func(t *testing.T, query string) []AdminJobResponse {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/admin/jobs"+query, nil)
+		rec := httptest.NewRecorder()
+
+		h.AdminListJobs(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp AdminJobListResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		return resp.Jobs
+	}
+
+	jobs := list(t, "?stuck=true")
+	require.Len(t, jobs, 1)
+	assert.Equal(t, stuck.ID, jobs[0].ID)
+	assert.Equal(t, "RUNNING", jobs[0].Status)
+	assert.WithinDuration(t, stuck.UpdatedAt, jobs[0].UpdatedAt, time.Second)
+	assert.NotNil(t, jobs[0].StartedAt)
+
+	ids := []string{}
+	for _, j := range list(t, "") {
+		ids = append(ids, j.ID)
+	}
+	assert.ElementsMatch(t, []string{stuck.ID, active.ID}, ids)
+}
+
+func TestAdminListJobs_InvalidStuck(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/jobs?stuck=maybe", nil)
+	rec := httptest.NewRecorder()
+
+	h.AdminListJobs(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var resp ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "VALIDATION_ERROR", resp.Code)
+}
+
+func TestAdminTimeoutJob(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	stuck := saveRunningJob(t, repo, time.Now().Add(-2*job.DefaultStuckJobThreshold))
+	active := saveRunningJob(t, repo, time.Now())
+
+	timeout := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/jobs/"+id+"/timeout", nil)
+		req.SetPathValue("id", id)
+		rec := httptest.NewRecorder()
+		h.AdminTimeoutJob(rec, req)
+		return rec
+	}
+
+	rec := timeout(stuck.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp AdminJobResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "TIMED_OUT", resp.Status)
+	stored, err := repo.FindByID(context.Background(), stuck.ID)
+	require.NoError(t, err)
+	assert.Equal(t, job.StatusTimedOut, stored.Status)
+
+	for id, want := range map[string]struct {
+		status int
+		code   string
+	}{
+		stuck.ID:  {http.StatusConflict, "JOB_NOT_STUCK"},
+		active.ID: {http.StatusConflict, "JOB_NOT_STUCK"},
+		"missing": {http.StatusNotFound, "JOB_NOT_FOUND"},
+	} {
+		rec := timeout(id)
+		assert.Equal(t, want.status, rec.Code, id)
+		var errResp ErrorResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&errResp))
+		assert.Equal(t, want.code, errResp.Code, id)
+	}
+}
+
+func TestGetJob_Success(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	// Create a job in the repository
+	testJob := job.New()
+	testJob.Width = 384
+	testJob.Height = 576
+	testJob.UpdateProgress(50)
+	err := repo.Save(ctx, testJob)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID, nil)
+	req.SetPathValue("id", testJob.ID)
+	rec := httptest.NewRecorder()
+
+	h.GetJob(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp JobResponse
+	err = json.NewDecoder(rec.Body).Decode(&resp)
+	require.NoError(t, err)
+	assert.Equal(t, testJob.ID, resp.ID)
+	assert.Equal(t, "IN_QUEUE", resp.Status)
+	assert.Equal(t, 50, resp.Progress)
+}
+
+func TestGetJob_NotFound(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/nonexistent", nil)
+	req.SetPathValue("id", "nonexistent")
+	rec := httptest.NewRecorder()
+
+	h.GetJob(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	var resp ErrorResponse
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	require.NoError(t, err)
+	assert.Equal(t, "JOB_NOT_FOUND", resp.Code)
+}
+
+func TestGetJob_MissingID(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/", nil)
+	// Don't set path value to simulate missing ID
+	rec := httptest.NewRecorder()
+
+	h.GetJob(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var resp ErrorResponse
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	require.NoError(t, err)
+	assert.Equal(t, "MISSING_JOB_ID", resp.Code)
+}
+
+func TestGetJob_WithS3URL(t *testing.T) {
+	h, _, _, _, storageClient, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	// Create a completed job pushed to S3
+	testJob := job.New()
+	testJob.PushToS3 = true
+	testJob.S3Key = "videos/test.mp4"
+	storageClient.On("ObjectURL", mock.Anything, "videos/test.mp4").Return("https://s3.example.com/videos/test.mp4", nil)
+	err := testJob.Start()
+	require.NoError(t, err)
+	err = testJob.Complete()
+	require.NoError(t, err)
+	testJob.UpdateProgress(100)
+	err = repo.Save(ctx, testJob)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID, nil)
+	req.SetPathValue("id", testJob.ID)
+	rec := httptest.NewRecorder()
+
+	h.GetJob(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp JobResponse
+	err = json.NewDecoder(rec.Body).Decode(&resp)
+	require.NoError(t, err)
+	assert.Equal(t, "COMPLETED", resp.Status)
+	assert.Equal(t, "https://s3.example.com/videos/test.mp4", resp.VideoURL)
+	assert.Empty(t, resp.VideoBase64)
+}
+
+func TestGetJob_PresignsVideoURLOnRead(t *testing.T) {
+	const presignTTL = time.Hour
+	s3Storage, err := storage.NewS3Storage(t.TempDir(), storage.S3Config{
+		Bucket:          "test-bucket",
+		Region:          "us-east-1",
+		Endpoint:        "http://localhost:4566",
+		AccessKeyID:     "test-access-key",
+		SecretAccessKey: "test-secret-key",
+		PresignTTL:      presignTTL,
+	})
+	require.NoError(t, err)
+
+	repo := job.NewMemoryRepository()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := job.NewProcessVideoService(repo, &mockProcessor{}, &mockSplitter{}, &mockRunpodClient{}, nil, s3Storage, logger)
+	h := NewHandlers(svc, logger, WithAsyncProcessing(false))
+
+	// The video was uploaded well over the presign TTL ago
+	testJob := job.New()
+	testJob.PushToS3 = true
+	testJob.S3Key = "videos/" + testJob.ID + ".mp4"
+	require.NoError(t, testJob.Start())
+	require.NoError(t, testJob.Complete())
+	testJob.CreatedAt = time.Now().Add(-3 * presignTTL)
+	testJob.CompletedAt = time.Now().Add(-2 * presignTTL)
+	require.NoError(t, repo.Save(context.Background(), testJob))
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID, nil)
+	req.SetPathValue("id", testJob.ID)
+	rec := httptest.NewRecorder()
+	h.GetJob(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp JobResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	u, err := url.Parse(resp.VideoURL)
+	require.NoError(t, err)
+	assert.Equal(t, "/test-bucket/"+testJob.S3Key, u.Path)
+
+	q := u.Query()
+	assert.NotEmpty(t, q.Get("X-Amz-Signature"))
+	signedAt, err := time.Parse("20060102T150405Z", q.Get("X-Amz-Date"))
+	require.NoError(t, err)
+	expires, err := strconv.Atoi(q.Get("X-Amz-Expires"))
+	require.NoError(t, err)
+	assert.Equal(t, int(presignTTL/time.Second), expires)
+	// Signed when the job was read, so the URL is still valid now
+	assert.WithinDuration(t, time.Now(), signedAt, time.Minute)
+	assert.True(t, signedAt.Add(time.Duration(expires)*time.Second).After(time.Now()))
+}
+
+func TestGetJob_WithVideoBase64(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	// Create a temp video file
+	videoData := []byte("test video data")
+	tmpFile := "/tmp/test_video_output.mp4"
+	err := os.WriteFile(tmpFile, videoData, 0644)
+	require.NoError(t, err)
+	defer os.Remove(tmpFile)
+
+	// Create a completed job with local video path
+	testJob := job.New()
+	testJob.PushToS3 = false
+	testJob.OutputVideoPath = tmpFile
+	err = testJob.Start()
+	require.NoError(t, err)
+	err = testJob.Complete()
+	require.NoError(t, err)
+	testJob.UpdateProgress(100)
+	err = repo.Save(ctx, testJob)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID, nil)
+	req.SetPathValue("id", testJob.ID)
+	rec := httptest.NewRecorder()
+
+	h.GetJob(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp JobResponse
+	err = json.NewDecoder(rec.Body).Decode(&resp)
+	require.NoError(t, err)
+	assert.Equal(t, "COMPLETED", resp.Status)
+	assert.Empty(t, resp.VideoURL)
+	assert.NotEmpty(t, resp.VideoBase64)
+
+	// Verify the base64 content
+	decoded, err := base64.StdEncoding.DecodeString(resp.VideoBase64)
+	require.NoError(t, err)
+	assert.Equal(t, videoData, decoded)
+}
+
+func TestGetJob_VideoReadBudget(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	// Large enough that encoding cannot finish within a nanosecond budget
+	videoPath := filepath.Join(t.TempDir(), "large_output.mp4")
+	require.NoError(t, os.WriteFile(videoPath, make([]byte, 16<<20), 0644))
+
+	testJob := job.New()
+	testJob.OutputVideoPath = videoPath
+	require.NoError(t, testJob.Start())
+	require.NoError(t, testJob.Complete())
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	t.Run("budget exceeded returns 504", func(t *testing.T) {
+		h.videoReadBudget = time.Nanosecond
+
+		req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID, nil)
+		req.SetPathValue("id", testJob.ID)
+		rec := httptest.NewRecorder()
+
+		h.GetJob(rec, req)
+
+		assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+		var resp ErrorResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, "VIDEO_READ_TIMEOUT", resp.Code)
+	})
+
+	t.Run("within budget returns video", func(t *testing.T) {
+		h.videoReadBudget = time.Minute
+
+		req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID, nil)
+		req.SetPathValue("id", testJob.ID)
+		rec := httptest.NewRecorder()
+
+		h.GetJob(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var resp JobResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Len(t, resp.VideoBase64, base64.StdEncoding.EncodedLen(16<<20))
+	})
+
+	t.Run("client gone writes nothing", func(t *testing.T) {
+		h.videoReadBudget = time.Minute
+
+		reqCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID, nil).WithContext(reqCtx)
+		req.SetPathValue("id", testJob.ID)
+		rec := httptest.NewRecorder()
+
+		h.GetJob(rec, req)
+
+		assert.Empty(t, rec.Body.String())
+	})
+}
+
+func TestEncodeFileBase64(t *testing.T) {
+	data := []byte("some video bytes")
+	path := filepath.Join(t.TempDir(), "video.mp4")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+
+	t.Run("encodes file", func(t *testing.T) {
+		got, err := encodeFileBase64(context.Background(), path)
+		require.NoError(t, err)
+		assert.Equal(t, base64.StdEncoding.EncodeToString(data), got)
+	})
+
+	t.Run("expired context", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		_, err := encodeFileBase64(ctx, path)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := encodeFileBase64(context.Background(), filepath.Join(t.TempDir(), "missing.mp4"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestGetJob_IncludeChunks(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	started := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	completed := started.Add(90 * time.Second)
+
+	testJob := job.New()
+	require.NoError(t, testJob.Start())
+	testJob.SetChunks([]job.Chunk{
+		{ID: "chunk-0", Index: 0, Status: job.ChunkStatusCompleted, RunPodJobID: "rp-0", StartedAt: started, CompletedAt: completed, Attempts: 1},
+		{ID: "chunk-1", Index: 1, Status: job.ChunkStatusFailed, RunPodJobID: "rp-1", Error: "worker crashed", StartedAt: started, CompletedAt: completed, Attempts: 2,
+			FailureStage: job.FailureStagePoll, ProviderStatus: "FAILED", ProviderError: "CUDA out of memory"},
+		{ID: "chunk-2", Index: 2, Status: job.ChunkStatusProcessing, RunPodJobID: "rp-2", StartedAt: started},
+		{ID: "chunk-3", Index: 3, Status: job.ChunkStatusPending},
+	})
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	t.Run("omitted by default", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID, nil)
+		req.SetPathValue("id", testJob.ID)
+		rec := httptest.NewRecorder()
+
+		h.GetJob(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, rec.Body.String(), `"chunks"`)
+	})
+
+	t.Run("included on request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID+"?include=chunks", nil)
+		req.SetPathValue("id", testJob.ID)
+		rec := httptest.NewRecorder()
+
+		h.GetJob(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp struct {
+			Chunks []map[string]any `json:"chunks"`
+		}
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		require.Len(t, resp.Chunks, 4)
+
+		assert.Equal(t, map[string]any{
+			"index":         float64(0),
+			"status":        string(job.ChunkStatusCompleted),
+			"runpod_job_id": "rp-0",
+			"started_at":    "2025-01-02T03:04:05Z",
+			"completed_at":  "2025-01-02T03:05:35Z",
+			"attempts":      float64(1),
+		}, resp.Chunks[0])
+		assert.Equal(t, map[string]any{
+			"index":           float64(1),
+			"status":          string(job.ChunkStatusFailed),
+			"runpod_job_id":   "rp-1",
+			"error":           "worker crashed",
+			"started_at":      "2025-01-02T03:04:05Z",
+			"completed_at":    "2025-01-02T03:05:35Z",
+			"attempts":        float64(2),
+			"failure_stage":   "poll",
+			"provider_status": "FAILED",
+			"provider_error":  "CUDA out of memory",
+		}, resp.Chunks[1])
+		assert.Equal(t, map[string]any{
+			"index":         float64(2),
+			"status":        string(job.ChunkStatusProcessing),
+			"runpod_job_id": "rp-2",
+			"started_at":    "2025-01-02T03:04:05Z",
+		}, resp.Chunks[2])
+		assert.Equal(t, map[string]any{
+			"index":  float64(3),
+			"status": string(job.ChunkStatusPending),
+		}, resp.Chunks[3])
+	})
+}
+
+func TestGetJob_ResultCompression(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	videoData := bytes.Repeat([]byte("fake video content "), 1024)
+	videoPath := filepath.Join(t.TempDir(), "output.mp4")
+	require.NoError(t, os.WriteFile(videoPath, videoData, 0644))
+
+	testJob := job.New()
+	testJob.OutputVideoPath = videoPath
+	require.NoError(t, testJob.Start())
+	require.NoError(t, testJob.Complete())
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	pendingJob := job.New()
+	require.NoError(t, repo.Save(ctx, pendingJob))
+
+	tests := []struct {
+		name           string
+		enabled        bool
+		jobID          string
+		query          string
+		acceptEncoding string
+		wantGzip       bool
+	}{
+		{name: "accept-encoding gzip", enabled: true, jobID: testJob.ID, acceptEncoding: "br, gzip;q=0.8", wantGzip: true},
+		{name: "compress query param", enabled: true, jobID: testJob.ID, query: "?compress=gzip", wantGzip: true},
+		{name: "compress none overrides header", enabled: true, jobID: testJob.ID, query: "?compress=none", acceptEncoding: "gzip"},
+		{name: "gzip refused with q=0", enabled: true, jobID: testJob.ID, acceptEncoding: "gzip;q=0"},
+		{name: "no accept-encoding", enabled: true, jobID: testJob.ID},
+		{name: "disabled", enabled: false, jobID: testJob.ID, acceptEncoding: "gzip"},
+		{name: "no inline video", enabled: true, jobID: pendingJob.ID, acceptEncoding: "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h.compressResults = tt.enabled
+
+			req := httptest.NewRequest(http.MethodGet, "/jobs/"+tt.jobID+tt.query, nil)
+			req.SetPathValue("id", tt.jobID)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+
+			h.GetJob(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			var body io.Reader = rec.Body
+			if tt.wantGzip {
+				assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+				assert.Contains(t, rec.Header().Values("Vary"), "Accept-Encoding")
+				gz, err := gzip.NewReader(rec.Body)
+				require.NoError(t, err)
+				defer gz.Close()
+				body = gz
+			} else {
+				assert.Empty(t, rec.Header().Get("Content-Encoding"))
+			}
+
+			var resp JobResponse
+			require.NoError(t, json.NewDecoder(body).Decode(&resp))
+			if tt.jobID == testJob.ID {
+				decoded, err := base64.StdEncoding.DecodeString(resp.VideoBase64)
+				require.NoError(t, err)
+				assert.Equal(t, videoData, decoded)
+			}
+		})
+	}
+}
+
+func TestGetJob_UnsupportedCompression(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/some-job?compress=zstd", nil)
+	req.SetPathValue("id", "some-job")
+	rec := httptest.NewRecorder()
+
+	h.GetJob(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var resp ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "UNSUPPORTED_COMPRESSION", resp.Code)
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"GZIP", true},
+		{"deflate, gzip;q=1.0", true},
+		{"*", true},
+		{"gzip;q=0", false},
+		{"gzip; q=0.000", false},
+		{"br, deflate", false},
+		{"identity", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, acceptsGzip(tt.header))
+		})
+	}
+}
+
+func TestGetJob_WithThumbnail(t *testing.T) {
+	h, _, _, _, storageClient, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	localJob := job.New()
+	localJob.SetThumbnail("/tmp/thumbnail_local.jpg", "")
+	require.NoError(t, localJob.Start())
+	require.NoError(t, localJob.Complete())
+	require.NoError(t, repo.Save(ctx, localJob))
+
+	s3Job := job.New()
+	s3Job.PushToS3 = true
+	s3Job.SetThumbnail("/tmp/thumbnail_s3.jpg", "thumbnails/test.jpg")
+	storageClient.On("ObjectURL", mock.Anything, "thumbnails/test.jpg").Return("https://s3.example.com/thumbnails/test.jpg", nil)
+	require.NoError(t, s3Job.Start())
+	require.NoError(t, s3Job.Complete())
+	require.NoError(t, repo.Save(ctx, s3Job))
+
+	tests := []struct {
+		name string
+		id   string
+		want string
+	}{
+		{"local thumbnail", localJob.ID, "/jobs/" + localJob.ID + "/thumbnail"},
+		{"s3 thumbnail", s3Job.ID, "https://s3.example.com/thumbnails/test.jpg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/jobs/"+tt.id, nil)
+			req.SetPathValue("id", tt.id)
+			rec := httptest.NewRecorder()
+
+			h.GetJob(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			var resp JobResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, tt.want, resp.ThumbnailURL)
+		})
+	}
+}
+
+func TestGetJobLogs(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	testJob := job.New()
+	testJob.Logs = job.NewLogBuffer(10)
+	testJob.Logs.Add(job.LogEntry{Time: at, Level: "INFO", Message: "creating new job", Attrs: map[string]string{"provider": "runpod"}})
+	testJob.Logs.Add(job.LogEntry{Time: at.Add(time.Second), Level: "ERROR", Message: "chunk failed", Attrs: map[string]string{"error": "boom", "chunk": "0"}})
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	t.Run("json", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID+"/logs", nil)
+		req.SetPathValue("id", testJob.ID)
+		rec := httptest.NewRecorder()
+
+		h.GetJobLogs(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp JobLogsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, testJob.ID, resp.ID)
+		assert.Equal(t, 0, resp.Dropped)
+		require.Len(t, resp.Lines, 2)
+		assert.Equal(t, "creating new job", resp.Lines[0].Message)
+		assert.Equal(t, "runpod", resp.Lines[0].Attrs["provider"])
+		assert.Equal(t, "ERROR", resp.Lines[1].Level)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID+"/logs?format=text", nil)
+		req.SetPathValue("id", testJob.ID)
+		rec := httptest.NewRecorder()
+
+		h.GetJobLogs(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "2024-01-01T12:00:00Z INFO creating new job provider=\"runpod\"\n"+
+			"2024-01-01T12:00:01Z ERROR chunk failed chunk=\"0\" error=\"boom\"\n", rec.Body.String())
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID+"/logs?format=xml", nil)
+		req.SetPathValue("id", testJob.ID)
+		rec := httptest.NewRecorder()
+
+		h.GetJobLogs(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_FORMAT")
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/jobs/nonexistent/logs", nil)
+		req.SetPathValue("id", "nonexistent")
+		rec := httptest.NewRecorder()
+
+		h.GetJobLogs(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestGetJobThumbnail_Local(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	thumbData := []byte{0xff, 0xd8, 0xff, 0xe0, 'j', 'p', 'e', 'g'}
+	thumbPath := filepath.Join(t.TempDir(), "thumbnail.jpg")
+	require.NoError(t, os.WriteFile(thumbPath, thumbData, 0644))
+
+	testJob := job.New()
+	testJob.SetThumbnail(thumbPath, "")
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID+"/thumbnail", nil)
+	req.SetPathValue("id", testJob.ID)
+	rec := httptest.NewRecorder()
+
+	h.GetJobThumbnail(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/jpeg", rec.Header().Get("Content-Type"))
+	assert.Equal(t, thumbData, rec.Body.Bytes())
+}
+
+func TestGetJobThumbnail_S3Redirect(t *testing.T) {
+	h, _, _, _, storageClient, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	testJob := job.New()
+	testJob.SetThumbnail("/tmp/thumbnail.jpg", "thumbnails/test.jpg")
+	storageClient.On("ObjectURL", mock.Anything, "thumbnails/test.jpg").Return("https://s3.example.com/thumbnails/test.jpg", nil)
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID+"/thumbnail", nil)
+	req.SetPathValue("id", testJob.ID)
+	rec := httptest.NewRecorder()
+
+	h.GetJobThumbnail(rec, req)
+
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "https://s3.example.com/thumbnails/test.jpg", rec.Header().Get("Location"))
+}
+
+func TestGetJobThumbnail_NotFound(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	noThumb := job.New()
+	require.NoError(t, repo.Save(ctx, noThumb))
+
+	missingFile := job.New()
+	missingFile.SetThumbnail("/tmp/nonexistent_handler_thumbnail.jpg", "")
+	require.NoError(t, repo.Save(ctx, missingFile))
+
+	tests := []struct {
+		name     string
+		id       string
+		wantCode string
+	}{
+		{"unknown job", "nonexistent", "JOB_NOT_FOUND"},
+		{"no thumbnail", noThumb.ID, "THUMBNAIL_NOT_FOUND"},
+		{"file missing", missingFile.ID, "THUMBNAIL_NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/jobs/"+tt.id+"/thumbnail", nil)
+			req.SetPathValue("id", tt.id)
+			rec := httptest.NewRecorder()
+
+			h.GetJobThumbnail(rec, req)
+
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+			var resp ErrorResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, tt.wantCode, resp.Code)
+		})
+	}
+}
+
+func TestGetJobVideo_Local(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	videoData := []byte("local video bytes")
+	videoPath := filepath.Join(t.TempDir(), "output.mp4")
+	require.NoError(t, os.WriteFile(videoPath, videoData, 0644))
+
+	testJob := job.New()
+	require.NoError(t, testJob.Start())
+	require.NoError(t, testJob.Complete())
+	testJob.SetOutput(videoPath)
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID+"/video", nil)
+	req.Header.Set("Range", "bytes=0-4")
+	req.SetPathValue("id", testJob.ID)
+	rec := httptest.NewRecorder()
+
+	h.GetJobVideo(rec, req)
+
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, "video/mp4", rec.Header().Get("Content-Type"))
+	assert.Equal(t, videoData[:5], rec.Body.Bytes())
+}
+
+func TestGetJobVideo_LocalContentTypeFollowsFormat(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	videoPath := filepath.Join(t.TempDir(), "output.webm")
+	require.NoError(t, os.WriteFile(videoPath, []byte("webm video bytes"), 0644))
+
+	testJob := job.New()
+	require.NoError(t, testJob.Start())
+	require.NoError(t, testJob.Complete())
+	testJob.SetOutput(videoPath)
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID+"/video", nil)
+	req.SetPathValue("id", testJob.ID)
+	rec := httptest.NewRecorder()
+
+	h.GetJobVideo(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "video/webm", rec.Header().Get("Content-Type"))
+}
+
+func TestGetJobVideo_ProxiesRemote(t *testing.T) {
+	h, _, _, _, storageClient, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	testJob := job.New()
+	testJob.PushToS3 = true
+	require.NoError(t, testJob.Start())
+	require.NoError(t, testJob.Complete())
+	testJob.SetOutput("/tmp/already-cleaned-up.mp4")
+	testJob.SetS3Key("videos/" + testJob.ID + ".mp4")
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	storageClient.On("Download", mock.Anything, "videos/"+testJob.ID+".mp4").
+		Return(io.NopCloser(strings.NewReader("remote video bytes")), nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID+"/video", nil)
+	req.SetPathValue("id", testJob.ID)
+	rec := httptest.NewRecorder()
+
+	h.GetJobVideo(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "video/mp4", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "remote video bytes", rec.Body.String())
+	storageClient.AssertExpectations(t)
+}
+
+func TestGetJobVideo_RecordsDownload(t *testing.T) {
+	videoData := []byte("local video bytes")
+
+	tests := []struct {
+		name       string
+		rangeHdr   string
+		downloaded bool
+	}{
+		{name: "whole file", downloaded: true},
+		{name: "range covering the whole file", rangeHdr: "bytes=0-", downloaded: true},
+		{name: "partial range", rangeHdr: "bytes=0-4", downloaded: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _, _, _, repo := newTestHandlers(t)
+			ctx := context.Background()
+
+			videoPath := filepath.Join(t.TempDir(), "output.mp4")
+			require.NoError(t, os.WriteFile(videoPath, videoData, 0644))
+
+			testJob := job.New()
+			testJob.DeleteAfterDownload = true
+			require.NoError(t, testJob.Start())
+			require.NoError(t, testJob.Complete())
+			testJob.SetOutput(videoPath)
+			require.NoError(t, repo.Save(ctx, testJob))
+
+			req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID+"/video", nil)
+			if tt.rangeHdr != "" {
+				req.Header.Set("Range", tt.rangeHdr)
+			}
+			req.SetPathValue("id", testJob.ID)
+			rec := httptest.NewRecorder()
+
+			h.GetJobVideo(rec, req)
+			require.Less(t, rec.Code, 300)
+
+			saved, err := repo.FindByID(ctx, testJob.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.downloaded, !saved.VideoDownloadedAt.IsZero())
+			// The first full download makes a delete-after-download job expire
+			assert.Equal(t, tt.downloaded, !saved.ExpiresAt.IsZero())
+		})
+	}
+}
+
+func TestGetJob_InlineVideoRecordsDownload(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	videoPath := filepath.Join(t.TempDir(), "output.mp4")
+	require.NoError(t, os.WriteFile(videoPath, []byte("local video bytes"), 0644))
+
+	testJob := job.New()
+	testJob.DeleteAfterDownload = true
+	require.NoError(t, testJob.Start())
+	require.NoError(t, testJob.Complete())
+	testJob.SetOutput(videoPath)
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID, nil)
+	req.SetPathValue("id", testJob.ID)
+	rec := httptest.NewRecorder()
+
+	h.GetJob(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp JobResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	require.NotEmpty(t, resp.VideoBase64)
+
+	saved, err := repo.FindByID(ctx, testJob.ID)
+	require.NoError(t, err)
+	assert.False(t, saved.VideoDownloadedAt.IsZero())
+	// A delete-after-download job expires once its video was returned inline
+	assert.Equal(t, saved.VideoDownloadedAt, saved.ExpiresAt)
+}
+
+func TestGetJobVideo_ProxyRecordsDownload(t *testing.T) {
+	h, _, _, _, storageClient, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	testJob := job.New()
+	testJob.PushToS3 = true
+	testJob.DeleteAfterDownload = true
+	require.NoError(t, testJob.Start())
+	require.NoError(t, testJob.Complete())
+	testJob.SetOutput("/tmp/already-cleaned-up.mp4")
+	testJob.SetS3Key("videos/" + testJob.ID + ".mp4")
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	storageClient.On("Download", mock.Anything, "videos/"+testJob.ID+".mp4").
+		Return(io.NopCloser(strings.NewReader("remote video bytes")), nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID+"/video", nil)
+	req.SetPathValue("id", testJob.ID)
+	rec := httptest.NewRecorder()
+
+	h.GetJobVideo(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	saved, err := repo.FindByID(ctx, testJob.ID)
+	require.NoError(t, err)
+	assert.False(t, saved.VideoDownloadedAt.IsZero())
+	assert.Equal(t, saved.VideoDownloadedAt, saved.ExpiresAt)
+
+	storageClient.On("ObjectURL", mock.Anything, "videos/"+testJob.ID+".mp4").Return("https://s3.example.com/videos/test.mp4", nil)
+	resp := h.jobResponse(ctx, saved)
+	require.NotNil(t, resp.VideoDownloadedAt)
+	require.NotNil(t, resp.RetentionSec)
+	assert.Equal(t, 0, *resp.RetentionSec)
+}
+
+func TestGetJobVideo_ServesKeptLocalCopy(t *testing.T) {
+	h, _, _, _, storageClient, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	videoPath := filepath.Join(t.TempDir(), "output.mp4")
+	require.NoError(t, os.WriteFile(videoPath, []byte("local video bytes"), 0644))
+
+	testJob := job.New()
+	testJob.PushToS3 = true
+	require.NoError(t, testJob.Start())
+	require.NoError(t, testJob.Complete())
+	testJob.SetOutput(videoPath)
+	testJob.SetS3Key("videos/" + testJob.ID + ".mp4")
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID+"/video", nil)
+	req.SetPathValue("id", testJob.ID)
+	rec := httptest.NewRecorder()
+
+	h.GetJobVideo(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "local video bytes", rec.Body.String())
+	storageClient.AssertNotCalled(t, "Download", mock.Anything, mock.Anything)
+}
+
+func TestGetJobVideo_Errors(t *testing.T) {
+	h, _, _, _, storageClient, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	running := job.New()
+	require.NoError(t, running.Start())
+	require.NoError(t, repo.Save(ctx, running))
+
+	noVideo := job.New()
+	require.NoError(t, noVideo.Start())
+	require.NoError(t, noVideo.Complete())
+	require.NoError(t, repo.Save(ctx, noVideo))
+
+	remoteMissing := job.New()
+	remoteMissing.PushToS3 = true
+	require.NoError(t, remoteMissing.Start())
+	require.NoError(t, remoteMissing.Complete())
+	remoteMissing.SetS3Key("videos/" + remoteMissing.ID + ".mp4")
+	require.NoError(t, repo.Save(ctx, remoteMissing))
+
+	remoteFailing := job.New()
+	remoteFailing.PushToS3 = true
+	require.NoError(t, remoteFailing.Start())
+	require.NoError(t, remoteFailing.Complete())
+	remoteFailing.SetS3Key("videos/" + remoteFailing.ID + ".mp4")
+	require.NoError(t, repo.Save(ctx, remoteFailing))
+
+	storageClient.On("Download", mock.Anything, "videos/"+remoteMissing.ID+".mp4").
+		Return(nil, fmt.Errorf("download from S3: %w", storage.ErrObjectNotFound))
+	storageClient.On("Download", mock.Anything, "videos/"+remoteFailing.ID+".mp4").
+		Return(nil, errors.New("connection reset"))
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+		wantCode   string
+	}{
+		{"unknown job", "nonexistent", http.StatusNotFound, "JOB_NOT_FOUND"},
+		{"job not completed", running.ID, http.StatusConflict, "VIDEO_NOT_READY"},
+		{"no output video", noVideo.ID, http.StatusNotFound, "VIDEO_NOT_FOUND"},
+		{"remote object missing", remoteMissing.ID, http.StatusNotFound, "VIDEO_NOT_FOUND"},
+		{"remote download fails", remoteFailing.ID, http.StatusBadGateway, "VIDEO_DOWNLOAD_FAILED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/jobs/"+tt.id+"/video", nil)
+			req.SetPathValue("id", tt.id)
+			rec := httptest.NewRecorder()
+
+			h.GetJobVideo(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			var resp ErrorResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, tt.wantCode, resp.Code)
+		})
+	}
+}
+
+func TestGetJobVideoInfo(t *testing.T) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		t.Skip("ffmpeg not found in PATH, skipping test")
+	}
+	repo := job.NewMemoryRepository()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	svc := job.NewProcessVideoService(repo, media.NewFFmpegProcessor(""), &mockSplitter{}, &mockRunpodClient{}, nil, &mockStorage{}, logger)
+	h := NewHandlers(svc, logger, WithAsyncProcessing(false))
+
+	testJob := job.New()
+	testJob.Width, testJob.Height = 384, 576
+	videoPath := filepath.Join(t.TempDir(), "output.mp4")
+	cmd := exec.Command("ffmpeg", "-y",
+		"-f", "lavfi", "-i", fmt.Sprintf("color=c=blue:s=%dx%d:d=1", testJob.Width, testJob.Height),
+		"-f", "lavfi", "-i", "anullsrc=r=44100:cl=mono:d=1",
+		"-c:v", "libx264", "-preset", "ultrafast", "-c:a", "aac", "-shortest",
+		videoPath,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to create test video: %v\noutput: %s", err, output)
+	}
+	require.NoError(t, testJob.Start())
+	require.NoError(t, testJob.Complete())
+	testJob.SetOutput(videoPath)
+	require.NoError(t, repo.Save(context.Background(), testJob))
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID+"/video/info", nil)
+	req.SetPathValue("id", testJob.ID)
+	rec := httptest.NewRecorder()
+
+	h.GetJobVideoInfo(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp VideoInfoResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	stat, err := os.Stat(videoPath)
+	require.NoError(t, err)
+	assert.Equal(t, testJob.ID, resp.ID)
+	assert.Equal(t, testJob.Width, resp.Width)
+	assert.Equal(t, testJob.Height, resp.Height)
+	assert.InDelta(t, 1.0, resp.DurationSec, 0.2)
+	assert.Equal(t, stat.Size(), resp.SizeBytes)
+	assert.Equal(t, "h264", resp.VideoCodec)
+	assert.Equal(t, "aac", resp.AudioCodec)
+}
+
+func TestGetJobVideoInfo_NotFound(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	running := job.New()
+	require.NoError(t, running.Start())
+	require.NoError(t, repo.Save(ctx, running))
+
+	deleted := job.New()
+	require.NoError(t, deleted.Start())
+	require.NoError(t, deleted.Complete())
+	deleted.SetOutput("/tmp/nonexistent_handler_output.mp4")
+	require.NoError(t, repo.Save(ctx, deleted))
+
+	tests := []struct {
+		name     string
+		id       string
+		wantCode string
+	}{
+		{"unknown job", "nonexistent", "JOB_NOT_FOUND"},
+		{"job not completed", running.ID, "VIDEO_NOT_FOUND"},
+		{"video deleted", deleted.ID, "VIDEO_NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/jobs/"+tt.id+"/video/info", nil)
+			req.SetPathValue("id", tt.id)
+			rec := httptest.NewRecorder()
+
+			h.GetJobVideoInfo(rec, req)
+
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+			var resp ErrorResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, tt.wantCode, resp.Code)
+		})
+	}
+}
+
+func TestRouter_Integration(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	router := NewRouter(h, logger, DefaultConfig())
+
+	// Test health endpoint
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Test POST /jobs
+	body := CreateJobRequest{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+		Width:       384,
+		Height:      576,
+	}
+	bodyJSON, _ := json.Marshal(body)
+	req = httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	// Parse response to get job ID
+	var createResp CreateJobResponse
+	err := json.NewDecoder(rec.Body).Decode(&createResp)
+	require.NoError(t, err)
+
+	// Test GET /jobs/{id}
+	req = httptest.NewRequest(http.MethodGet, "/jobs/"+createResp.ID, nil)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRouter_TimeoutExemptions(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// Every request wrapped by the timeout middleware would get 503
+	cfg := DefaultConfig()
+	cfg.RequestTimeout = time.Nanosecond
+	router := NewRouter(h, logger, cfg)
+
+	body := CreateJobRequest{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+		Width:       384,
+		Height:      576,
+	}
+	bodyJSON, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	var createResp CreateJobResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&createResp))
+
+	req = httptest.NewRequest(http.MethodGet, "/jobs/"+createResp.ID, nil)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	cfg := Config{AllowedOrigins: []string{"https://example.com"}}
+	router := NewRouter(h, logger, cfg)
+
+	// Test with allowed origin
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.Header.Set("Origin", "https://example.com")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, "https://example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+
+	// Test OPTIONS preflight
+	req = httptest.NewRequest(http.MethodOptions, "/jobs", nil)
+	req.Header.Set("Origin", "https://example.com")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("hello"))
+	})
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/jobs/abc?include=chunks", nil)
+		req.RemoteAddr = "203.0.113.7:51234"
+		req.Header.Set("User-Agent", "curl/8.0")
+		return req
+	}
+
+	t.Run("slog", func(t *testing.T) {
+		var logBuf, outBuf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&logBuf, nil))
+
+		AccessLogMiddleware(AccessLogSlog, logger, &outBuf)(okHandler).ServeHTTP(httptest.NewRecorder(), newRequest())
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(logBuf.Bytes(), &entry))
+		assert.Equal(t, "http request", entry["msg"])
+		assert.Equal(t, "GET", entry["method"])
+		assert.Equal(t, "/jobs/abc", entry["path"])
+		assert.EqualValues(t, http.StatusCreated, entry["status"])
+		assert.Empty(t, outBuf.String())
+	})
+
+	t.Run("combined", func(t *testing.T) {
+		var logBuf, outBuf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&logBuf, nil))
+
+		AccessLogMiddleware(AccessLogCombined, logger, &outBuf)(okHandler).ServeHTTP(httptest.NewRecorder(), newRequest())
+
+		assert.Regexp(t,
+			`^203\.0\.113\.7 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "GET /jobs/abc\?include=chunks HTTP/1\.1" 201 5 "-" "curl/8\.0"\n$`,
+			outBuf.String())
+		assert.Empty(t, logBuf.String())
+	})
+
+	t.Run("none", func(t *testing.T) {
+		var logBuf, outBuf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&logBuf, nil))
+
+		rec := httptest.NewRecorder()
+		AccessLogMiddleware(AccessLogNone, logger, &outBuf)(okHandler).ServeHTTP(rec, newRequest())
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Empty(t, logBuf.String())
+		assert.Empty(t, outBuf.String())
+	})
+
+	t.Run("unknown format falls back to slog", func(t *testing.T) {
+		var logBuf, outBuf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&logBuf, nil))
+
+		AccessLogMiddleware("apache", logger, &outBuf)(okHandler).ServeHTTP(httptest.NewRecorder(), newRequest())
+
+		assert.Contains(t, logBuf.String(), "http request")
+		assert.Empty(t, outBuf.String())
+	})
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	// Create a handler that panics
+	panicHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("test panic")
+	})
+
+	handler := RecoveryMiddleware(logger)(panicHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	rec := httptest.NewRecorder()
+
+	// Should not panic
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
 
 	var resp ErrorResponse
 	err := json.NewDecoder(rec.Body).Decode(&resp)
 	require.NoError(t, err)
-	assert.Equal(t, "MISSING_JOB_ID", resp.Code)
+	assert.Equal(t, "INTERNAL_ERROR", resp.Code)
+}
+
+func TestCreateJob_DryRun(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	body := CreateJobRequest{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+		Width:       384,
+		Height:      576,
+		PushToS3:    false,
+		DryRun:      true,
+	}
+	bodyJSON, _ := json.Marshal(body)
+
+	req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	h.CreateJob(rec, req)
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	var resp CreateJobResponse
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.ID)
+	assert.Equal(t, "IN_QUEUE", resp.Status)
+}
+
+// startDryRunJob creates a dry-run job through the handler with background
+// processing enabled. split is run by the splitter, so it controls how long
+// processing takes.
+func startDryRunJob(t *testing.T, h *Handlers, processor *mockProcessor, splitter *mockSplitter, storageClient *mockStorage, split func(ctx context.Context)) string {
+	t.Helper()
+	tempDir := t.TempDir()
+	h.enableAsyncProcess = true
+
+	storageClient.On("SaveTemp", mock.Anything, "image.png", mock.Anything).Return(filepath.Join(tempDir, "image.png"), nil)
+	storageClient.On("SaveTemp", mock.Anything, "audio.wav", mock.Anything).Return(filepath.Join(tempDir, "audio.wav"), nil)
+	storageClient.On("CleanupTemp", mock.Anything, mock.Anything).Return(nil)
+	processor.On("ResizeImageWithPadding", mock.Anything, mock.Anything, mock.Anything, 1024, 1024).
+		Run(func(args mock.Arguments) {
+			_ = os.WriteFile(args.String(2), []byte("resized"), 0600)
+		}).Return(nil)
+	splitter.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			split(args.Get(0).(context.Context))
+		}).Return([]string{filepath.Join(tempDir, "chunk_000.wav")}, nil)
+
+	bodyJSON, _ := json.Marshal(CreateJobRequest{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+		Width:       384,
+		Height:      576,
+		DryRun:      true,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	h.CreateJob(rec, req)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	var resp CreateJobResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	return resp.ID
 }
 
-func TestGetJob_WithS3URL(t *testing.T) {
+func TestGetJob_EstimatedSecondsRemaining(t *testing.T) {
 	h, _, _, _, _, repo := newTestHandlers(t)
 	ctx := context.Background()
 
-	// Create a completed job with S3 URL
+	getETA := func(id string) *int {
+		req := httptest.NewRequest(http.MethodGet, "/jobs/"+id, nil)
+		req.SetPathValue("id", id)
+		rec := httptest.NewRecorder()
+		h.GetJob(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp JobResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		return resp.EstimatedSecondsRemaining
+	}
+
 	testJob := job.New()
-	testJob.PushToS3 = true
-	testJob.VideoURL = "https://s3.example.com/videos/test.mp4"
-	err := testJob.Start()
-	require.NoError(t, err)
-	err = testJob.Complete()
-	require.NoError(t, err)
-	testJob.UpdateProgress(100)
-	err = repo.Save(ctx, testJob)
+	require.NoError(t, testJob.Start())
+	testJob.SetChunks([]job.Chunk{
+		{Index: 0, Status: job.ChunkStatusPending},
+		{Index: 1, Status: job.ChunkStatusPending},
+	})
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	// No chunk has completed yet, so there is nothing to estimate from
+	assert.Nil(t, getETA(testJob.ID))
+
+	completed := time.Now().Add(-time.Second)
+	testJob.UpdateChunk(0, job.Chunk{Index: 0, Status: job.ChunkStatusCompleted, StartedAt: completed.Add(-45 * time.Second), CompletedAt: completed})
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	eta := getETA(testJob.ID)
+	require.NotNil(t, eta)
+	assert.Equal(t, 45, *eta)
+
+	require.NoError(t, testJob.Complete())
+	require.NoError(t, repo.Save(ctx, testJob))
+	assert.Nil(t, getETA(testJob.ID))
+}
+
+func TestDrain_WaitsForJobInProgress(t *testing.T) {
+	h, processor, splitter, _, storageClient, repo := newTestHandlers(t)
+
+	jobID := startDryRunJob(t, h, processor, splitter, storageClient, func(context.Context) {
+		time.Sleep(50 * time.Millisecond)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, h.Drain(ctx))
+
+	found, err := repo.FindByID(context.Background(), jobID)
 	require.NoError(t, err)
+	assert.Equal(t, job.StatusCompleted, found.Status)
+}
 
-	req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID, nil)
-	req.SetPathValue("id", testJob.ID)
-	rec := httptest.NewRecorder()
+func TestDrain_TimesOutJobsAfterDeadline(t *testing.T) {
+	h, processor, splitter, _, storageClient, repo := newTestHandlers(t)
 
-	h.GetJob(rec, req)
+	// Splitting only ends when the job is stopped
+	splitting := make(chan struct{})
+	jobID := startDryRunJob(t, h, processor, splitter, storageClient, func(ctx context.Context) {
+		close(splitting)
+		<-ctx.Done()
+	})
+	<-splitting
 
-	assert.Equal(t, http.StatusOK, rec.Code)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := h.Drain(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
 
-	var resp JobResponse
-	err = json.NewDecoder(rec.Body).Decode(&resp)
+	// Processing stops once the job is timed out
+	h.dispatcher.Wait()
+
+	found, err := repo.FindByID(context.Background(), jobID)
 	require.NoError(t, err)
-	assert.Equal(t, "COMPLETED", resp.Status)
-	assert.Equal(t, "https://s3.example.com/videos/test.mp4", resp.VideoURL)
-	assert.Empty(t, resp.VideoBase64)
+	assert.Equal(t, job.StatusTimedOut, found.Status)
 }
 
-func TestGetJob_WithVideoBase64(t *testing.T) {
+func TestDrain_NoJobs(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, h.Drain(ctx))
+}
+
+func TestDeleteJobVideo_Success(t *testing.T) {
 	h, _, _, _, _, repo := newTestHandlers(t)
 	ctx := context.Background()
 
 	// Create a temp video file
-	videoData := []byte("test video data")
-	tmpFile := "/tmp/test_video_output.mp4"
-	err := os.WriteFile(tmpFile, videoData, 0644)
+	videoPath := "/tmp/test_handler_delete_video.mp4"
+	err := os.WriteFile(videoPath, []byte("video data"), 0644)
 	require.NoError(t, err)
-	defer os.Remove(tmpFile)
+	defer os.Remove(videoPath)
 
-	// Create a completed job with local video path
+	// Create a job with the video path
 	testJob := job.New()
-	testJob.PushToS3 = false
-	testJob.OutputVideoPath = tmpFile
-	err = testJob.Start()
+	testJob.SetOutput(videoPath)
+	err = repo.Save(ctx, testJob)
 	require.NoError(t, err)
-	err = testJob.Complete()
+
+	req := httptest.NewRequest(http.MethodPost, "/jobs/"+testJob.ID+"/video/delete", nil)
+	req.SetPathValue("id", testJob.ID)
+	rec := httptest.NewRecorder()
+
+	h.DeleteJobVideo(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	// Verify file was deleted
+	_, statErr := os.Stat(videoPath)
+	assert.True(t, os.IsNotExist(statErr))
+
+	// Verify job output was cleared
+	updatedJob, err := repo.FindByID(ctx, testJob.ID)
 	require.NoError(t, err)
-	testJob.UpdateProgress(100)
-	err = repo.Save(ctx, testJob)
+	assert.Empty(t, updatedJob.OutputVideoPath)
+}
+
+func TestDeleteJobVideo_FileAlreadyMissing(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	// Create a job with a non-existent video path
+	testJob := job.New()
+	testJob.SetOutput("/tmp/nonexistent_handler_video.mp4")
+	err := repo.Save(ctx, testJob)
 	require.NoError(t, err)
 
-	req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID, nil)
+	req := httptest.NewRequest(http.MethodPost, "/jobs/"+testJob.ID+"/video/delete", nil)
 	req.SetPathValue("id", testJob.ID)
 	rec := httptest.NewRecorder()
 
-	h.GetJob(rec, req)
+	h.DeleteJobVideo(rec, req)
 
-	assert.Equal(t, http.StatusOK, rec.Code)
+	// Should still return 204 (idempotent)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
 
-	var resp JobResponse
-	err = json.NewDecoder(rec.Body).Decode(&resp)
+// publishJob posts to /jobs/{id}/publish.
+func publishJob(h *Handlers, jobID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/jobs/"+jobID+"/publish", nil)
+	req.SetPathValue("id", jobID)
+	rec := httptest.NewRecorder()
+	h.PublishJobVideo(rec, req)
+	return rec
+}
+
+func TestPublishJobVideo_Success(t *testing.T) {
+	h, _, _, _, storageClient, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	videoPath := filepath.Join(t.TempDir(), "output.mp4")
+	require.NoError(t, os.WriteFile(videoPath, []byte("video data"), 0600))
+	testJob := job.New()
+	require.NoError(t, testJob.Start())
+	require.NoError(t, testJob.Complete())
+	testJob.SetOutput(videoPath)
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	videoURL := "https://bucket.s3.amazonaws.com/videos/" + testJob.ID + ".mp4"
+	storageClient.On("Upload", mock.Anything, "videos/"+testJob.ID+".mp4", mock.Anything).Return(videoURL, nil).Once()
+	storageClient.On("ObjectURL", mock.Anything, "videos/"+testJob.ID+".mp4").Return(videoURL, nil).Once()
+
+	rec := publishJob(h, testJob.ID)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp PublishJobResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, testJob.ID, resp.ID)
+	assert.Equal(t, videoURL, resp.VideoURL)
+
+	updated, err := repo.FindByID(ctx, testJob.ID)
 	require.NoError(t, err)
-	assert.Equal(t, "COMPLETED", resp.Status)
-	assert.Empty(t, resp.VideoURL)
-	assert.NotEmpty(t, resp.VideoBase64)
+	assert.True(t, updated.PushToS3)
+	assert.Equal(t, "videos/"+testJob.ID+".mp4", updated.S3Key)
+	storageClient.AssertExpectations(t)
+}
 
-	// Verify the base64 content
-	decoded, err := base64.StdEncoding.DecodeString(resp.VideoBase64)
+func TestPublishJobVideo_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		deleteFile bool
+		uploadErr  error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "video file deleted", deleteFile: true, wantStatus: http.StatusConflict, wantCode: "VIDEO_NOT_AVAILABLE"},
+		{name: "remote storage not configured", uploadErr: storage.ErrRemoteNotConfigured, wantStatus: http.StatusBadRequest, wantCode: "REMOTE_STORAGE_NOT_CONFIGURED"},
+		{name: "upload fails", uploadErr: errors.New("network down"), wantStatus: http.StatusInternalServerError, wantCode: "VIDEO_PUBLISH_FAILED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _, _, storageClient, repo := newTestHandlers(t)
+
+			videoPath := filepath.Join(t.TempDir(), "output.mp4")
+			require.NoError(t, os.WriteFile(videoPath, []byte("video data"), 0600))
+			if tt.deleteFile {
+				require.NoError(t, os.Remove(videoPath))
+			}
+			testJob := job.New()
+			require.NoError(t, testJob.Start())
+			require.NoError(t, testJob.Complete())
+			testJob.SetOutput(videoPath)
+			require.NoError(t, repo.Save(context.Background(), testJob))
+			storageClient.On("Upload", mock.Anything, mock.Anything, mock.Anything).Return("", tt.uploadErr).Maybe()
+
+			rec := publishJob(h, testJob.ID)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			var resp ErrorResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, tt.wantCode, resp.Code)
+		})
+	}
+}
+
+func TestPublishJobVideo_JobNotFound(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	rec := publishJob(h, "nonexistent")
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDeleteJobVideo_JobNotFound(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/jobs/nonexistent/video/delete", nil)
+	req.SetPathValue("id", "nonexistent")
+	rec := httptest.NewRecorder()
+
+	h.DeleteJobVideo(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	var resp ErrorResponse
+	err := json.NewDecoder(rec.Body).Decode(&resp)
 	require.NoError(t, err)
-	assert.Equal(t, videoData, decoded)
+	assert.Equal(t, "JOB_NOT_FOUND", resp.Code)
 }
 
-func TestRouter_Integration(t *testing.T) {
+func TestDeleteJobVideo_MissingID(t *testing.T) {
 	h, _, _, _, _, _ := newTestHandlers(t)
-	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 
-	router := NewRouter(h, logger, DefaultConfig())
+	req := httptest.NewRequest(http.MethodPost, "/jobs//video/delete", nil)
+	// Don't set path value to simulate missing ID
+	rec := httptest.NewRecorder()
 
-	// Test health endpoint
-	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	h.DeleteJobVideo(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var resp ErrorResponse
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	require.NoError(t, err)
+	assert.Equal(t, "MISSING_JOB_ID", resp.Code)
+}
+
+func TestDeleteJob_Success(t *testing.T) {
+	h, _, _, _, storage, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	videoPath := filepath.Join(t.TempDir(), "output.mp4")
+	require.NoError(t, os.WriteFile(videoPath, []byte("video data"), 0644))
+
+	testJob := job.New()
+	testJob.InputImagePath = "/tmp/image.png"
+	testJob.SetOutput(videoPath)
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	storage.On("CleanupTemp", mock.Anything, []string{"/tmp/image.png"}).Return(nil).Once()
+
+	req := httptest.NewRequest(http.MethodDelete, "/jobs/"+testJob.ID, nil)
+	req.SetPathValue("id", testJob.ID)
 	rec := httptest.NewRecorder()
-	router.ServeHTTP(rec, req)
-	assert.Equal(t, http.StatusOK, rec.Code)
 
-	// Test POST /jobs
-	body := CreateJobRequest{
-		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
-		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
-		Width:       384,
-		Height:      576,
-	}
-	bodyJSON, _ := json.Marshal(body)
-	req = httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
-	req.Header.Set("Content-Type", "application/json")
+	h.DeleteJob(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	_, statErr := os.Stat(videoPath)
+	assert.True(t, os.IsNotExist(statErr))
+
+	_, err := repo.FindByID(ctx, testJob.ID)
+	assert.ErrorIs(t, err, job.ErrJobNotFound)
+	storage.AssertExpectations(t)
+
+	// A second delete reports the job as gone
 	rec = httptest.NewRecorder()
-	router.ServeHTTP(rec, req)
-	assert.Equal(t, http.StatusAccepted, rec.Code)
+	h.DeleteJob(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
 
-	// Parse response to get job ID
-	var createResp CreateJobResponse
-	err := json.NewDecoder(rec.Body).Decode(&createResp)
+func TestDeleteJob_Routes(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	ctx := context.Background()
+	router := NewRouter(h, slog.New(slog.NewTextHandler(io.Discard, nil)), Config{})
+
+	testJob := job.New()
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	// A bare POST to the job must not delete it
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/jobs/"+testJob.ID, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	_, err := repo.FindByID(ctx, testJob.ID)
 	require.NoError(t, err)
 
-	// Test GET /jobs/{id}
-	req = httptest.NewRequest(http.MethodGet, "/jobs/"+createResp.ID, nil)
 	rec = httptest.NewRecorder()
-	router.ServeHTTP(rec, req)
-	assert.Equal(t, http.StatusOK, rec.Code)
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/jobs/"+testJob.ID+"/delete", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	_, err = repo.FindByID(ctx, testJob.ID)
+	assert.ErrorIs(t, err, job.ErrJobNotFound)
 }
 
-func TestCORSMiddleware(t *testing.T) {
-	h, _, _, _, _, _ := newTestHandlers(t)
-	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+func TestDeleteJob_FilesAlreadyMissing(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	ctx := context.Background()
 
-	cfg := Config{AllowedOrigins: []string{"https://example.com"}}
-	router := NewRouter(h, logger, cfg)
+	testJob := job.New()
+	testJob.SetOutput("/tmp/nonexistent_handler_job_video.mp4")
+	require.NoError(t, repo.Save(ctx, testJob))
 
-	// Test with allowed origin
-	req := httptest.NewRequest(http.MethodGet, "/health", nil)
-	req.Header.Set("Origin", "https://example.com")
+	req := httptest.NewRequest(http.MethodPost, "/jobs/"+testJob.ID+"/delete", nil)
+	req.SetPathValue("id", testJob.ID)
 	rec := httptest.NewRecorder()
-	router.ServeHTTP(rec, req)
-
-	assert.Equal(t, "https://example.com", rec.Header().Get("Access-Control-Allow-Origin"))
 
-	// Test OPTIONS preflight
-	req = httptest.NewRequest(http.MethodOptions, "/jobs", nil)
-	req.Header.Set("Origin", "https://example.com")
-	rec = httptest.NewRecorder()
-	router.ServeHTTP(rec, req)
+	h.DeleteJob(rec, req)
 
+	// Should still return 204 (idempotent)
 	assert.Equal(t, http.StatusNoContent, rec.Code)
 }
 
-func TestRecoveryMiddleware(t *testing.T) {
-	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+func TestDeleteJob_JobNotFound(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
 
-	// Create a handler that panics
-	panicHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		panic("test panic")
-	})
+	req := httptest.NewRequest(http.MethodDelete, "/jobs/nonexistent", nil)
+	req.SetPathValue("id", "nonexistent")
+	rec := httptest.NewRecorder()
 
-	handler := RecoveryMiddleware(logger)(panicHandler)
+	h.DeleteJob(rec, req)
 
-	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	var resp ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "JOB_NOT_FOUND", resp.Code)
+}
+
+func TestCancelJob_Accepted(t *testing.T) {
+	h, _, _, runpodClient, _, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	testJob := job.New()
+	require.NoError(t, testJob.Start())
+	testJob.SetChunks([]job.Chunk{
+		{ID: "chunk-0", Index: 0, Status: job.ChunkStatusProcessing, RunPodJobID: "runpod-job-0"},
+	})
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	// Simulate a slow provider cancel
+	cancelled := make(chan struct{})
+	runpodClient.On("Cancel", mock.Anything, "runpod-job-0").
+		Run(func(args mock.Arguments) {
+			time.Sleep(100 * time.Millisecond)
+			close(cancelled)
+		}).
+		Return(nil).Once()
+
+	req := httptest.NewRequest(http.MethodDelete, "/jobs/"+testJob.ID+"/cancel", nil)
+	req.SetPathValue("id", testJob.ID)
 	rec := httptest.NewRecorder()
 
-	// Should not panic
-	handler.ServeHTTP(rec, req)
+	start := time.Now()
+	h.CancelJob(rec, req)
 
-	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	// The response must not wait for the provider cancel
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
 
-	var resp ErrorResponse
-	err := json.NewDecoder(rec.Body).Decode(&resp)
-	require.NoError(t, err)
-	assert.Equal(t, "INTERNAL_ERROR", resp.Code)
+	var resp JobResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, testJob.ID, resp.ID)
+	assert.Equal(t, string(job.StatusCancelled), resp.Status)
+	require.Len(t, resp.Chunks, 1)
+	assert.True(t, resp.Chunks[0].CancelRequested)
+	assert.False(t, resp.Chunks[0].CancelConfirmed)
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("provider cancel was not issued")
+	}
+
+	// The confirmation shows up on a later GET /jobs/{id}
+	assert.Eventually(t, func() bool {
+		req := httptest.NewRequest(http.MethodGet, "/jobs/"+testJob.ID+"?include=chunks", nil)
+		req.SetPathValue("id", testJob.ID)
+		rec := httptest.NewRecorder()
+		h.GetJob(rec, req)
+
+		var got JobResponse
+		if rec.Code != http.StatusOK || json.NewDecoder(rec.Body).Decode(&got) != nil || len(got.Chunks) != 1 {
+			return false
+		}
+		return got.Chunks[0].CancelRequested && got.Chunks[0].CancelConfirmed && got.Chunks[0].CancelError == ""
+	}, time.Second, 10*time.Millisecond)
 }
 
-func TestCreateJob_DryRun(t *testing.T) {
+func TestCancelJob_NotFound(t *testing.T) {
 	h, _, _, _, _, _ := newTestHandlers(t)
 
-	body := CreateJobRequest{
-		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
-		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
-		Width:       384,
-		Height:      576,
-		PushToS3:    false,
-		DryRun:      true,
-	}
-	bodyJSON, _ := json.Marshal(body)
-
-	req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
-	req.Header.Set("Content-Type", "application/json")
+	req := httptest.NewRequest(http.MethodDelete, "/jobs/nonexistent/cancel", nil)
+	req.SetPathValue("id", "nonexistent")
 	rec := httptest.NewRecorder()
 
-	h.CreateJob(rec, req)
+	h.CancelJob(rec, req)
 
-	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
 
-	var resp CreateJobResponse
-	err := json.NewDecoder(rec.Body).Decode(&resp)
-	require.NoError(t, err)
-	assert.NotEmpty(t, resp.ID)
-	assert.Equal(t, "IN_QUEUE", resp.Status)
+	var resp ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "JOB_NOT_FOUND", resp.Code)
 }
 
-func TestDeleteJobVideo_Success(t *testing.T) {
+func TestCancelJob_TerminalJob(t *testing.T) {
 	h, _, _, _, _, repo := newTestHandlers(t)
 	ctx := context.Background()
 
-	// Create a temp video file
-	videoPath := "/tmp/test_handler_delete_video.mp4"
-	err := os.WriteFile(videoPath, []byte("video data"), 0644)
-	require.NoError(t, err)
-	defer os.Remove(videoPath)
-
-	// Create a job with the video path
 	testJob := job.New()
-	testJob.SetOutput(videoPath, "")
-	err = repo.Save(ctx, testJob)
-	require.NoError(t, err)
+	require.NoError(t, testJob.Start())
+	require.NoError(t, testJob.Complete())
+	require.NoError(t, repo.Save(ctx, testJob))
 
-	req := httptest.NewRequest(http.MethodPost, "/jobs/"+testJob.ID+"/video/delete", nil)
+	req := httptest.NewRequest(http.MethodDelete, "/jobs/"+testJob.ID+"/cancel", nil)
 	req.SetPathValue("id", testJob.ID)
 	rec := httptest.NewRecorder()
 
-	h.DeleteJobVideo(rec, req)
-
-	assert.Equal(t, http.StatusNoContent, rec.Code)
+	h.CancelJob(rec, req)
 
-	// Verify file was deleted
-	_, statErr := os.Stat(videoPath)
-	assert.True(t, os.IsNotExist(statErr))
+	assert.Equal(t, http.StatusConflict, rec.Code)
 
-	// Verify job output was cleared
-	updatedJob, err := repo.FindByID(ctx, testJob.ID)
-	require.NoError(t, err)
-	assert.Empty(t, updatedJob.OutputVideoPath)
+	var resp ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "JOB_NOT_CANCELLABLE", resp.Code)
 }
 
-func TestDeleteJobVideo_FileAlreadyMissing(t *testing.T) {
+func TestRetryJob_Accepted(t *testing.T) {
 	h, _, _, _, _, repo := newTestHandlers(t)
 	ctx := context.Background()
 
-	// Create a job with a non-existent video path
+	dir := t.TempDir()
 	testJob := job.New()
-	testJob.SetOutput("/tmp/nonexistent_handler_video.mp4", "")
-	err := repo.Save(ctx, testJob)
-	require.NoError(t, err)
-
-	req := httptest.NewRequest(http.MethodPost, "/jobs/"+testJob.ID+"/video/delete", nil)
+	testJob.InputImagePath = filepath.Join(dir, "image.png")
+	testJob.InputAudioPath = filepath.Join(dir, "audio.wav")
+	require.NoError(t, os.WriteFile(testJob.InputImagePath, []byte("image"), 0644))
+	require.NoError(t, os.WriteFile(testJob.InputAudioPath, []byte("audio"), 0644))
+	require.NoError(t, testJob.Start())
+	require.NoError(t, testJob.Fail("provider error"))
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	req := httptest.NewRequest(http.MethodPost, "/jobs/"+testJob.ID+"/retry", nil)
 	req.SetPathValue("id", testJob.ID)
 	rec := httptest.NewRecorder()
 
-	h.DeleteJobVideo(rec, req)
+	h.RetryJob(rec, req)
 
-	// Should still return 204 (idempotent)
-	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	var resp CreateJobResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, testJob.ID, resp.ID)
+	assert.Equal(t, string(job.StatusInQueue), resp.Status)
+	assert.Equal(t, "/jobs/"+testJob.ID, resp.PollURL)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	stored, err := repo.FindByID(ctx, testJob.ID)
+	require.NoError(t, err)
+	assert.Equal(t, job.StatusInQueue, stored.Status)
+	assert.Empty(t, stored.Error)
 }
 
-func TestDeleteJobVideo_JobNotFound(t *testing.T) {
+func TestRetryJob_NotFound(t *testing.T) {
 	h, _, _, _, _, _ := newTestHandlers(t)
 
-	req := httptest.NewRequest(http.MethodPost, "/jobs/nonexistent/video/delete", nil)
+	req := httptest.NewRequest(http.MethodPost, "/jobs/nonexistent/retry", nil)
 	req.SetPathValue("id", "nonexistent")
 	rec := httptest.NewRecorder()
 
-	h.DeleteJobVideo(rec, req)
+	h.RetryJob(rec, req)
 
 	assert.Equal(t, http.StatusNotFound, rec.Code)
 
 	var resp ErrorResponse
-	err := json.NewDecoder(rec.Body).Decode(&resp)
-	require.NoError(t, err)
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
 	assert.Equal(t, "JOB_NOT_FOUND", resp.Code)
 }
 
-func TestDeleteJobVideo_MissingID(t *testing.T) {
-	h, _, _, _, _, _ := newTestHandlers(t)
+func TestRetryJob_NotRetryable(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	ctx := context.Background()
 
-	req := httptest.NewRequest(http.MethodPost, "/jobs//video/delete", nil)
-	// Don't set path value to simulate missing ID
+	testJob := job.New()
+	require.NoError(t, testJob.Start())
+	require.NoError(t, testJob.Complete())
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	req := httptest.NewRequest(http.MethodPost, "/jobs/"+testJob.ID+"/retry", nil)
+	req.SetPathValue("id", testJob.ID)
 	rec := httptest.NewRecorder()
 
-	h.DeleteJobVideo(rec, req)
+	h.RetryJob(rec, req)
 
-	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, http.StatusConflict, rec.Code)
 
 	var resp ErrorResponse
-	err := json.NewDecoder(rec.Body).Decode(&resp)
-	require.NoError(t, err)
-	assert.Equal(t, "MISSING_JOB_ID", resp.Code)
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "JOB_NOT_RETRYABLE", resp.Code)
+}
+
+func TestRetryJob_InputsUnavailable(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	testJob := job.New()
+	testJob.InputImagePath = filepath.Join(t.TempDir(), "gone.png")
+	testJob.InputAudioPath = filepath.Join(t.TempDir(), "gone.wav")
+	require.NoError(t, testJob.Start())
+	require.NoError(t, testJob.Timeout())
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	req := httptest.NewRequest(http.MethodPost, "/jobs/"+testJob.ID+"/retry", nil)
+	req.SetPathValue("id", testJob.ID)
+	rec := httptest.NewRecorder()
+
+	h.RetryJob(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	var resp ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "RETRY_INPUTS_UNAVAILABLE", resp.Code)
 }
 
 func TestCreateJob_ForceOffloadDefaultTrue(t *testing.T) {
@@ -658,6 +3630,27 @@ func TestCreateJob_ForceOffloadExplicitFalse(t *testing.T) {
 	assert.Equal(t, "IN_QUEUE", resp.Status)
 }
 
+func TestCreateJob_ForceOffloadReachesProvider(t *testing.T) {
+	enabled, disabled := true, false
+
+	tests := []struct {
+		name         string
+		forceOffload *bool
+		want         bool
+	}{
+		{name: "omitted defaults to true", forceOffload: nil, want: true},
+		{name: "explicit true", forceOffload: &enabled, want: true},
+		{name: "explicit false", forceOffload: &disabled, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			submitted := submitThroughProvider(t, CreateJobRequest{ForceOffload: tt.forceOffload})
+			assert.Equal(t, tt.want, submitted.ForceOffload)
+		})
+	}
+}
+
 func TestCreateJob_WithPrompt(t *testing.T) {
 	h, _, _, _, _, repo := newTestHandlers(t)
 	ctx := context.Background()
@@ -759,3 +3752,464 @@ func TestCreateJob_EmptyPromptUsesDefault(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "high quality, realistic, speaking naturally", createdJob.Prompt)
 }
+
+func TestCreateJob_PromptTooLong(t *testing.T) {
+	tests := []struct {
+		name       string
+		length     int
+		wantStatus int
+	}{
+		{name: "at the limit", length: 1000, wantStatus: http.StatusAccepted},
+		{name: "over the limit", length: 1001, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _, _, _, _ := newTestHandlers(t)
+
+			bodyJSON, _ := json.Marshal(CreateJobRequest{
+				ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+				AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+				Width:       384,
+				Height:      576,
+				Prompt:      strings.Repeat("a", tt.length),
+			})
+			req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			h.CreateJob(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusBadRequest {
+				var resp ErrorResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, "VALIDATION_ERROR", resp.Code)
+			}
+		})
+	}
+}
+
+func postJobWithKey(t *testing.T, h *Handlers, key string, body CreateJobRequest) *httptest.ResponseRecorder {
+	t.Helper()
+	bodyJSON, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	h.CreateJob(rec, req)
+	return rec
+}
+
+func idempotencyTestRequest() CreateJobRequest {
+	return CreateJobRequest{
+		ImageBase64: base64.StdEncoding.EncodeToString([]byte("test-image")),
+		AudioBase64: base64.StdEncoding.EncodeToString([]byte("test-audio")),
+		Width:       384,
+		Height:      576,
+	}
+}
+
+// postJobWithDedup posts body to /jobs with ?dedup=true and decodes the response.
+func postJobWithDedup(t *testing.T, h *Handlers, body CreateJobRequest) (int, CreateJobResponse) {
+	t.Helper()
+	bodyJSON, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/jobs?dedup=true", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.CreateJob(rec, req)
+
+	var resp CreateJobResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	return rec.Code, resp
+}
+
+// completeJob marks a created job COMPLETED with an output video.
+func completeJob(t *testing.T, repo job.Repository, jobID string) {
+	t.Helper()
+	ctx := context.Background()
+	j, err := repo.FindByID(ctx, jobID)
+	require.NoError(t, err)
+	require.NoError(t, j.Start())
+	require.NoError(t, j.Complete())
+	j.OutputVideoPath = "/tmp/output.mp4"
+	require.NoError(t, repo.Save(ctx, j))
+}
+
+func TestCreateJob_Dedup_ReusesCompletedJob(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+
+	status, first := postJobWithDedup(t, h, idempotencyTestRequest())
+	require.Equal(t, http.StatusAccepted, status)
+	assert.False(t, first.Deduplicated)
+
+	// A job that has not completed yet is not reused
+	status, pending := postJobWithDedup(t, h, idempotencyTestRequest())
+	require.Equal(t, http.StatusAccepted, status)
+	assert.NotEqual(t, first.ID, pending.ID)
+	require.NoError(t, repo.Delete(context.Background(), pending.ID))
+
+	completeJob(t, repo, first.ID)
+
+	status, second := postJobWithDedup(t, h, idempotencyTestRequest())
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, "COMPLETED", second.Status)
+	assert.True(t, second.Deduplicated)
+
+	jobs, err := repo.List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1, "no new job should be created")
+
+	// Without ?dedup=true identical inputs are processed again
+	rec := postJobWithKey(t, h, "", idempotencyTestRequest())
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+}
+
+func TestCreateJob_Dedup_IdempotentReplayKeepsStatus(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+
+	status, first := postJobWithDedup(t, h, idempotencyTestRequest())
+	require.Equal(t, http.StatusAccepted, status)
+	completeJob(t, repo, first.ID)
+
+	post := func() *httptest.ResponseRecorder {
+		bodyJSON, _ := json.Marshal(idempotencyTestRequest())
+		req := httptest.NewRequest(http.MethodPost, "/jobs?dedup=true", bytes.NewReader(bodyJSON))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(IdempotencyKeyHeader, "order-42")
+		rec := httptest.NewRecorder()
+		h.CreateJob(rec, req)
+		return rec
+	}
+
+	reused := post()
+	require.Equal(t, http.StatusOK, reused.Code)
+	assert.Empty(t, reused.Header().Get(IdempotentReplayedHeader))
+
+	// The replay answers like the original request did
+	replayed := post()
+	assert.Equal(t, http.StatusOK, replayed.Code)
+	assert.Equal(t, "true", replayed.Header().Get(IdempotentReplayedHeader))
+	var resp CreateJobResponse
+	require.NoError(t, json.NewDecoder(replayed.Body).Decode(&resp))
+	assert.Equal(t, first.ID, resp.ID)
+	assert.True(t, resp.Deduplicated)
+}
+
+func TestCreateJob_Dedup_DifferentOptionsCreateNewJob(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+
+	status, first := postJobWithDedup(t, h, idempotencyTestRequest())
+	require.Equal(t, http.StatusAccepted, status)
+	completeJob(t, repo, first.ID)
+
+	changed := idempotencyTestRequest()
+	changed.Prompt = "a woman laughing"
+	status, second := postJobWithDedup(t, h, changed)
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.NotEqual(t, first.ID, second.ID)
+	assert.False(t, second.Deduplicated)
+}
+
+func TestCreateJob_IdempotencyKey_ReplaysOriginalJob(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+
+	first := postJobWithKey(t, h, "order-42", idempotencyTestRequest())
+	require.Equal(t, http.StatusAccepted, first.Code)
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+	var firstResp CreateJobResponse
+	require.NoError(t, json.NewDecoder(first.Body).Decode(&firstResp))
+
+	second := postJobWithKey(t, h, "order-42", idempotencyTestRequest())
+	require.Equal(t, http.StatusAccepted, second.Code)
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+	var secondResp CreateJobResponse
+	require.NoError(t, json.NewDecoder(second.Body).Decode(&secondResp))
+
+	assert.Equal(t, firstResp, secondResp)
+	jobs, err := repo.List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1, "a repeated key must not create another job")
+
+	other := postJobWithKey(t, h, "order-43", idempotencyTestRequest())
+	require.Equal(t, http.StatusAccepted, other.Code)
+	var otherResp CreateJobResponse
+	require.NoError(t, json.NewDecoder(other.Body).Decode(&otherResp))
+	assert.NotEqual(t, firstResp.ID, otherResp.ID)
+}
+
+func TestCreateJob_IdempotencyKey_ConflictingBody(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+
+	first := postJobWithKey(t, h, "order-42", idempotencyTestRequest())
+	require.Equal(t, http.StatusAccepted, first.Code)
+
+	changed := idempotencyTestRequest()
+	changed.Prompt = "a different prompt"
+	rec := postJobWithKey(t, h, "order-42", changed)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	var resp ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "IDEMPOTENCY_KEY_CONFLICT", resp.Code)
+
+	jobs, err := repo.List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+}
+
+func TestCreateJob_IdempotencyKey_TooLong(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	rec := postJobWithKey(t, h, strings.Repeat("k", maxIdempotencyKeyLen+1), idempotencyTestRequest())
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var resp ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "INVALID_IDEMPOTENCY_KEY", resp.Code)
+}
+
+func TestCreateJob_IdempotencyKey_Disabled(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	WithIdempotencyTTL(0)(h)
+
+	for range 2 {
+		rec := postJobWithKey(t, h, "order-42", idempotencyTestRequest())
+		require.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Empty(t, rec.Header().Get(IdempotentReplayedHeader))
+	}
+
+	jobs, err := repo.List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, jobs, 2)
+}
+
+// fullQueueHandlers returns handlers with async processing enabled whose
+// dispatcher has no workers and no queue, so every job is rejected.
+func fullQueueHandlers(t *testing.T) (*Handlers, job.Repository) {
+	t.Helper()
+	h, _, _, _, _, repo := newTestHandlers(t)
+	h.enableAsyncProcess = true
+	WithDispatcher(job.NewDispatcher(h.service, job.WithDispatcherQueueDepth(0)))(h)
+	return h, repo
+}
+
+func TestCreateJob_QueueFull(t *testing.T) {
+	h, repo := fullQueueHandlers(t)
+
+	rec := postJobWithKey(t, h, "order-42", idempotencyTestRequest())
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "5", rec.Header().Get("Retry-After"))
+	var resp ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "QUEUE_FULL", resp.Code)
+
+	jobs, err := repo.List(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, jobs, "a rejected job must not be kept")
+
+	// The idempotency key is released so the client can retry with it
+	_, err = h.idempotency.reserve("order-42", requestFingerprint(idempotencyTestRequest(), false))
+	assert.NoError(t, err)
+}
+
+func TestGetJob_PriorityAndQueuePosition(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+	h.enableAsyncProcess = true
+	// Without workers the jobs stay in the queue
+	WithDispatcher(job.NewDispatcher(h.service, job.WithDispatcherQueueDepth(10)))(h)
+
+	ids := make(map[string]string)
+	for _, priority := range []string{"normal", "low", "high"} {
+		body := idempotencyTestRequest()
+		body.Priority = priority
+		if priority == "normal" {
+			body.Priority = ""
+		}
+		rec := postJobWithKey(t, h, "", body)
+		require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+		var created CreateJobResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&created))
+		ids[priority] = created.ID
+	}
+
+	for priority, want := range map[string]int{"high": 1, "normal": 2, "low": 3} {
+		req := httptest.NewRequest(http.MethodGet, "/jobs/"+ids[priority], nil)
+		req.SetPathValue("id", ids[priority])
+		rec := httptest.NewRecorder()
+		h.GetJob(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp JobResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, priority, resp.Priority)
+		require.NotNil(t, resp.QueuePosition, priority)
+		assert.Equal(t, want, *resp.QueuePosition, priority)
+	}
+}
+
+func TestGetJob_Stage(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	ctx := context.Background()
+
+	queued := job.New()
+	require.NoError(t, repo.Save(ctx, queued))
+	running := job.New()
+	require.NoError(t, running.Start())
+	running.SetStage(job.StageJoining)
+	running.UpdateProgress(90)
+	require.NoError(t, repo.Save(ctx, running))
+
+	for id, want := range map[string]string{queued.ID: "", running.ID: "joining"} {
+		req := httptest.NewRequest(http.MethodGet, "/jobs/"+id, nil)
+		req.SetPathValue("id", id)
+		rec := httptest.NewRecorder()
+		h.GetJob(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp JobResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, want, resp.Stage)
+	}
+}
+
+func TestCreateJob_InvalidPriority(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	body := idempotencyTestRequest()
+	body.Priority = "urgent"
+	rec := postJobWithKey(t, h, "", body)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var resp ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "VALIDATION_ERROR", resp.Code)
+}
+
+func TestCreateJob_OutputFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		wantCode int
+		want     string
+	}{
+		{"default", "", http.StatusAccepted, "mp4"},
+		{"webm", "webm", http.StatusAccepted, "webm"},
+		{"mov", "mov", http.StatusAccepted, "mov"},
+		{"unsupported", "avi", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _, _, _, repo := newTestHandlers(t)
+
+			body := idempotencyTestRequest()
+			body.OutputFormat = tt.format
+			rec := postJobWithKey(t, h, "", body)
+
+			require.Equal(t, tt.wantCode, rec.Code, rec.Body.String())
+			if tt.wantCode != http.StatusAccepted {
+				var resp ErrorResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, "VALIDATION_ERROR", resp.Code)
+				assert.Contains(t, resp.Error, "output_format")
+				return
+			}
+			var created CreateJobResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&created))
+			stored, err := repo.FindByID(context.Background(), created.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, stored.OutputFormat)
+		})
+	}
+}
+
+func TestCreateJob_TargetFPS(t *testing.T) {
+	tests := []struct {
+		name     string
+		fps      float64
+		wantCode int
+	}{
+		{"default", 0, http.StatusAccepted},
+		{"25 fps", 25, http.StatusAccepted},
+		{"NTSC", 29.97, http.StatusAccepted},
+		{"negative", -1, http.StatusBadRequest},
+		{"too high", 240, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _, _, _, repo := newTestHandlers(t)
+
+			body := idempotencyTestRequest()
+			body.TargetFPS = tt.fps
+			rec := postJobWithKey(t, h, "", body)
+
+			require.Equal(t, tt.wantCode, rec.Code, rec.Body.String())
+			if tt.wantCode != http.StatusAccepted {
+				var resp ErrorResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, "VALIDATION_ERROR", resp.Code)
+				assert.Contains(t, resp.Error, "target_fps")
+				return
+			}
+			var created CreateJobResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&created))
+			stored, err := repo.FindByID(context.Background(), created.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.fps, stored.TargetFPS)
+		})
+	}
+}
+
+func TestCreateJob_StrictDimensions(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		t.Run(fmt.Sprint(strict), func(t *testing.T) {
+			h, _, _, _, _, repo := newTestHandlers(t)
+
+			body := idempotencyTestRequest()
+			body.StrictDimensions = strict
+			rec := postJobWithKey(t, h, "", body)
+			require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+
+			var created CreateJobResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&created))
+			stored, err := repo.FindByID(context.Background(), created.ID)
+			require.NoError(t, err)
+			assert.Equal(t, strict, stored.StrictDimensions)
+		})
+	}
+}
+
+func TestRetryJob_QueueFull(t *testing.T) {
+	h, repo := fullQueueHandlers(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	testJob := job.New()
+	testJob.InputImagePath = filepath.Join(dir, "image.png")
+	testJob.InputAudioPath = filepath.Join(dir, "audio.wav")
+	require.NoError(t, os.WriteFile(testJob.InputImagePath, []byte("image"), 0644))
+	require.NoError(t, os.WriteFile(testJob.InputAudioPath, []byte("audio"), 0644))
+	require.NoError(t, testJob.Start())
+	require.NoError(t, testJob.Fail("provider error"))
+	require.NoError(t, repo.Save(ctx, testJob))
+
+	req := httptest.NewRequest(http.MethodPost, "/jobs/"+testJob.ID+"/retry", nil)
+	req.SetPathValue("id", testJob.ID)
+	rec := httptest.NewRecorder()
+
+	h.RetryJob(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	stored, err := repo.FindByID(ctx, testJob.ID)
+	require.NoError(t, err)
+	assert.Equal(t, job.StatusTimedOut, stored.Status)
+	assert.True(t, stored.IsRetryable())
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// readinessTimeout bounds how long all readiness checks may take together.
+const readinessTimeout = 5 * time.Second
+
+// Readiness check statuses.
+const (
+	CheckStatusOK   = "ok"
+	CheckStatusFail = "fail"
+)
+
+// HealthChecker is a named dependency check run by GET /readyz.
+type HealthChecker interface {
+	// Name identifies the check in the readiness response.
+	Name() string
+	// Check returns an error if the dependency is not healthy.
+	Check(ctx context.Context) error
+}
+
+// healthCheck adapts a function to the HealthChecker interface.
+type healthCheck struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// NewHealthCheck creates a HealthChecker from a name and a check function.
+func NewHealthCheck(name string, fn func(ctx context.Context) error) HealthChecker {
+	return healthCheck{name: name, fn: fn}
+}
+
+func (c healthCheck) Name() string                    { return c.name }
+func (c healthCheck) Check(ctx context.Context) error { return c.fn(ctx) }
+
+// Readyz handles GET /readyz requests.
+// All readiness checks run concurrently; if any fails the response is
+// 503 Service Unavailable and lists each check with its result.
+func (h *Handlers) Readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	results := make([]CheckResult, len(h.readinessChecks))
+	var wg sync.WaitGroup
+	for i, check := range h.readinessChecks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = CheckResult{Name: check.Name(), Status: CheckStatusOK}
+			if err := check.Check(ctx); err != nil {
+				results[i].Status = CheckStatusFail
+				results[i].Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	resp := ReadinessResponse{Status: "ok", Checks: results}
+	status := http.StatusOK
+	for _, result := range results {
+		if result.Status == CheckStatusFail {
+			resp.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	writeJSON(w, status, resp)
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeader is the request header clients set to make POST /jobs safe to retry.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set to "true" on responses replayed for a repeated idempotency key.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// DefaultIdempotencyTTL is how long idempotency keys are remembered when no TTL is configured.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// maxIdempotencyKeyLen is the longest idempotency key accepted.
+const maxIdempotencyKeyLen = 255
+
+// Errors returned by idempotencyStore.reserve.
+var (
+	// errIdempotencyConflict is returned when a key is reused with a different request body.
+	errIdempotencyConflict = errors.New("idempotency key was already used with a different request")
+	// errIdempotencyInFlight is returned when a request with the same key is still being handled.
+	errIdempotencyInFlight = errors.New("a request with this idempotency key is still in progress")
+)
+
+// idempotencyEntry is what is remembered about one idempotency key.
+type idempotencyEntry struct {
+	// fingerprint identifies the request the key was first used with.
+	fingerprint string
+	// response is the response returned for the key. Nil while the first request is in flight.
+	response *idempotentResponse
+	// expiresAt is when the key is forgotten.
+	expiresAt time.Time
+}
+
+// idempotentResponse is a stored POST /jobs response, replayed with the same
+// status code: 202 for a created job, 200 for a job reused by ?dedup=true.
+type idempotentResponse struct {
+	status int
+	body   CreateJobResponse
+}
+
+// idempotencyStore remembers the job created for each idempotency key so that
+// retried POST /jobs requests return the original job instead of a new one.
+type idempotencyStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*idempotencyEntry
+	// now returns the current time; replaced in tests.
+	now func() time.Time
+}
+
+// newIdempotencyStore creates a store that remembers keys for ttl.
+func newIdempotencyStore(ttl time.Duration) *idempotencyStore {
+	return &idempotencyStore{
+		ttl:     ttl,
+		entries: make(map[string]*idempotencyEntry),
+		now:     time.Now,
+	}
+}
+
+// reserve claims key for a request with the given fingerprint. It returns the
+// stored response when the key was already used with the same request, or nil
+// when the caller should handle the request and then call complete or release.
+// Returns errIdempotencyConflict or errIdempotencyInFlight otherwise.
+func (s *idempotencyStore) reserve(key, fingerprint string) (*idempotentResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.prune(now)
+
+	if entry, ok := s.entries[key]; ok {
+		switch {
+		case entry.fingerprint != fingerprint:
+			return nil, errIdempotencyConflict
+		case entry.response == nil:
+			return nil, errIdempotencyInFlight
+		}
+		resp := *entry.response
+		return &resp, nil
+	}
+
+	s.entries[key] = &idempotencyEntry{
+		fingerprint: fingerprint,
+		expiresAt:   now.Add(s.ttl),
+	}
+	return nil, nil
+}
+
+// complete stores resp, sent with status, as the response for key.
+func (s *idempotencyStore) complete(key string, status int, resp CreateJobResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[key]; ok {
+		entry.response = &idempotentResponse{status: status, body: resp}
+	}
+}
+
+// release forgets key so that a failed request can be retried with it.
+func (s *idempotencyStore) release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+}
+
+// prune removes expired keys. Must be called with s.mu held.
+func (s *idempotencyStore) prune(now time.Time) {
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// requestFingerprint identifies a create request by its decoded body and
+// whether dimensions are snapped, so that formatting differences in the JSON
+// do not count as a different request.
+func requestFingerprint(req CreateJobRequest, snap bool) string {
+	body, _ := json.Marshal(struct {
+		CreateJobRequest
+		Snap bool `json:"snap"`
+	}{req, snap})
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
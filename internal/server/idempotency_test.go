@@ -0,0 +1,70 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyStore_Reserve(t *testing.T) {
+	s := newIdempotencyStore(time.Hour)
+
+	resp, err := s.reserve("key", "body-a")
+	require.NoError(t, err)
+	assert.Nil(t, resp, "first use of a key should be handled by the caller")
+
+	_, err = s.reserve("key", "body-a")
+	assert.ErrorIs(t, err, errIdempotencyInFlight)
+
+	s.complete("key", http.StatusAccepted, CreateJobResponse{ID: "job-1", Status: "IN_QUEUE"})
+
+	resp, err = s.reserve("key", "body-a")
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, "job-1", resp.body.ID)
+	assert.Equal(t, http.StatusAccepted, resp.status)
+
+	_, err = s.reserve("key", "body-b")
+	assert.ErrorIs(t, err, errIdempotencyConflict)
+}
+
+func TestIdempotencyStore_Release(t *testing.T) {
+	s := newIdempotencyStore(time.Hour)
+
+	_, err := s.reserve("key", "body-a")
+	require.NoError(t, err)
+	s.release("key")
+
+	resp, err := s.reserve("key", "body-b")
+	require.NoError(t, err)
+	assert.Nil(t, resp, "a released key should be usable again")
+}
+
+func TestIdempotencyStore_Expiry(t *testing.T) {
+	s := newIdempotencyStore(time.Hour)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	_, err := s.reserve("key", "body-a")
+	require.NoError(t, err)
+	s.complete("key", http.StatusAccepted, CreateJobResponse{ID: "job-1"})
+
+	now = now.Add(2 * time.Hour)
+	resp, err := s.reserve("key", "body-b")
+	require.NoError(t, err)
+	assert.Nil(t, resp, "an expired key should be forgotten")
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	req := CreateJobRequest{ImageBase64: "aW1n", AudioBase64: "YXVk", Width: 384, Height: 576}
+
+	assert.Equal(t, requestFingerprint(req, false), requestFingerprint(req, false))
+	assert.NotEqual(t, requestFingerprint(req, false), requestFingerprint(req, true))
+
+	changed := req
+	changed.Width = 512
+	assert.NotEqual(t, requestFingerprint(req, false), requestFingerprint(changed, false))
+}
@@ -1,16 +1,41 @@
 package server
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"maps"
+	"net"
 	"net/http"
 	"runtime/debug"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/maauso/infinitetalk-api/internal/job/id"
+	"github.com/maauso/infinitetalk-api/internal/requestid"
+)
+
+// Access log formats supported by AccessLogMiddleware.
+const (
+	// AccessLogSlog logs requests as structured slog records.
+	AccessLogSlog = "slog"
+	// AccessLogCombined writes Apache/NCSA combined log lines.
+	AccessLogCombined = "combined"
+	// AccessLogNone disables request logging.
+	AccessLogNone = "none"
 )
 
-// responseWriter is a wrapper that captures the status code.
+// responseWriter is a wrapper that captures the status code and response size.
 type responseWriter struct {
 	http.ResponseWriter
 	statusCode int
+	bytes      int
 }
 
 // WriteHeader captures the status code before writing it.
@@ -19,6 +44,60 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Write counts the bytes written to the response body.
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytes += n
+	return n, err
+}
+
+// AccessLogMiddleware returns the request logging middleware for format.
+// Unknown formats fall back to AccessLogSlog. Combined lines are written to out.
+func AccessLogMiddleware(format string, logger *slog.Logger, out io.Writer) func(http.Handler) http.Handler {
+	switch format {
+	case AccessLogNone:
+		return func(next http.Handler) http.Handler { return next }
+	case AccessLogCombined:
+		return CombinedLogMiddleware(out)
+	default:
+		return LoggingMiddleware(logger)
+	}
+}
+
+// maxRequestIDLen bounds client-supplied request IDs.
+const maxRequestIDLen = 128
+
+// RequestIDMiddleware assigns each request an ID for log correlation.
+// It reuses a well-formed X-Request-ID header from the client or generates
+// a new ID, stores it in the request context and echoes it in the response.
+func RequestIDMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqID := r.Header.Get(requestid.Header)
+			if !validRequestID(reqID) {
+				reqID = id.Generate()
+			}
+
+			w.Header().Set(requestid.Header, reqID)
+			next.ServeHTTP(w, r.WithContext(requestid.NewContext(r.Context(), reqID)))
+		})
+	}
+}
+
+// validRequestID reports whether a client-supplied request ID is safe to log:
+// non-empty, bounded in length and made of printable ASCII without spaces.
+func validRequestID(s string) bool {
+	if s == "" || len(s) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] <= ' ' || s[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
 // LoggingMiddleware logs HTTP requests with structured logging.
 func LoggingMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -30,7 +109,7 @@ func LoggingMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
 
 			next.ServeHTTP(rw, r)
 
-			logger.Info("http request",
+			requestid.Logger(r.Context(), logger).Info("http request",
 				slog.String("method", r.Method),
 				slog.String("path", r.URL.Path),
 				slog.Int("status", rw.statusCode),
@@ -41,13 +120,53 @@ func LoggingMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
 	}
 }
 
+// CombinedLogMiddleware writes one Apache/NCSA combined log line per request to out.
+func CombinedLogMiddleware(out io.Writer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+			next.ServeHTTP(rw, r)
+
+			host, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				host = r.RemoteAddr
+			}
+			size := "-"
+			if rw.bytes > 0 {
+				size = fmt.Sprintf("%d", rw.bytes)
+			}
+
+			_, _ = fmt.Fprintf(out, "%s - - [%s] %q %d %s %q %q\n",
+				host,
+				start.Format("02/Jan/2006:15:04:05 -0700"),
+				r.Method+" "+r.URL.RequestURI()+" "+r.Proto,
+				rw.statusCode,
+				size,
+				orDash(r.Referer()),
+				orDash(r.UserAgent()),
+			)
+		})
+	}
+}
+
+// orDash returns "-" for empty access log fields.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 // RecoveryMiddleware recovers from panics and returns a 500 error.
 func RecoveryMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if err := recover(); err != nil {
-					logger.Error("panic recovered",
+					requestid.Logger(r.Context(), logger).Error("panic recovered",
 						slog.Any("error", err),
 						slog.String("stack", string(debug.Stack())),
 					)
@@ -59,6 +178,253 @@ func RecoveryMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
 	}
 }
 
+// GzipMiddleware compresses responses of at least minBytes for clients that
+// accept gzip. Responses that already carry a Content-Encoding, are marked
+// Cache-Control: no-transform, or hold already-compressed media (video,
+// audio, images, archives) are passed through unchanged.
+func GzipMiddleware(minBytes int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodHead || r.Header.Get("Range") != "" ||
+				!acceptsGzip(r.Header.Get("Accept-Encoding")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Accept-Encoding")
+			gw := &gzipResponseWriter{ResponseWriter: w, minBytes: minBytes}
+			defer gw.Close()
+
+			next.ServeHTTP(gw, r)
+		})
+	}
+}
+
+// gzipResponseWriter buffers the start of a response until it knows whether
+// the response is worth compressing, then either gzips or passes it through.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minBytes int
+
+	statusCode int
+	buf        []byte
+	decided    bool
+	gz         *gzip.Writer
+}
+
+// WriteHeader records the status code; it is sent once the encoding is decided.
+func (gw *gzipResponseWriter) WriteHeader(code int) {
+	if gw.statusCode == 0 {
+		gw.statusCode = code
+	}
+}
+
+// Write buffers up to minBytes, then commits to gzip or pass-through.
+func (gw *gzipResponseWriter) Write(b []byte) (int, error) {
+	if gw.statusCode == 0 {
+		gw.statusCode = http.StatusOK
+	}
+	if gw.decided {
+		if gw.gz != nil {
+			return gw.gz.Write(b)
+		}
+		return gw.ResponseWriter.Write(b)
+	}
+
+	gw.buf = append(gw.buf, b...)
+	if len(gw.buf) >= gw.minBytes {
+		if err := gw.commit(gw.compressible()); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Close flushes buffered data and finishes the gzip stream, if any.
+func (gw *gzipResponseWriter) Close() {
+	if !gw.decided {
+		// The response stayed below the threshold
+		if gw.statusCode == 0 {
+			return
+		}
+		_ = gw.commit(false)
+	}
+	if gw.gz != nil {
+		_ = gw.gz.Close()
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (gw *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return gw.ResponseWriter
+}
+
+// commit sends the headers and the buffered data, compressed or not.
+func (gw *gzipResponseWriter) commit(compress bool) error {
+	gw.decided = true
+	h := gw.ResponseWriter.Header()
+	if compress {
+		if h.Get("Content-Type") == "" {
+			// Sniff before compressing, or net/http would detect gzip bytes
+			h.Set("Content-Type", http.DetectContentType(gw.buf))
+		}
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+		gw.gz = gzip.NewWriter(gw.ResponseWriter)
+	}
+	gw.ResponseWriter.WriteHeader(gw.statusCode)
+
+	buf := gw.buf
+	gw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if gw.gz != nil {
+		_, err := gw.gz.Write(buf)
+		return err
+	}
+	_, err := gw.ResponseWriter.Write(buf)
+	return err
+}
+
+// compressible reports whether the buffered response should be gzipped.
+func (gw *gzipResponseWriter) compressible() bool {
+	h := gw.ResponseWriter.Header()
+	if h.Get("Content-Encoding") != "" ||
+		strings.Contains(h.Get("Cache-Control"), "no-transform") {
+		return false
+	}
+	if gw.statusCode < http.StatusOK || gw.statusCode == http.StatusNoContent ||
+		gw.statusCode == http.StatusNotModified {
+		return false
+	}
+
+	contentType := h.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(gw.buf)
+	}
+	for _, prefix := range []string{"video/", "audio/", "image/", "application/zip", "application/gzip", "application/x-gzip"} {
+		if strings.HasPrefix(contentType, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// AdminAuthMiddleware rejects requests that do not carry key, either as
+// "Authorization: Bearer <key>" or in the X-Admin-Key header, with 401 and
+// code UNAUTHORIZED. The key is compared in constant time.
+func AdminAuthMiddleware(key string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got := r.Header.Get("X-Admin-Key")
+			if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+				got = bearer
+			}
+			if got == "" || subtle.ConstantTimeCompare([]byte(got), []byte(key)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeError(w, http.StatusUnauthorized, "a valid admin key is required", "UNAUTHORIZED")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// TimeoutMiddleware bounds how long a handler may take. The handler runs with
+// a context that ends after d and its response is buffered; if it has not
+// finished by then, 503 with code REQUEST_TIMEOUT is returned instead and
+// whatever the handler writes afterwards is discarded. Because the response is
+// buffered, it must not wrap streaming endpoints. Zero or negative d disables it.
+func TimeoutMiddleware(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			tw := &timeoutWriter{header: make(http.Header)}
+			done := make(chan struct{})
+			panicked := make(chan any, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicked <- p
+					}
+				}()
+				next.ServeHTTP(tw, r.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case p := <-panicked:
+				panic(p) // let RecoveryMiddleware report it
+			case <-done:
+				tw.flushTo(w)
+			case <-ctx.Done():
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				tw.timedOut = true
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					writeError(w, http.StatusServiceUnavailable,
+						fmt.Sprintf("request did not complete within %s", d), "REQUEST_TIMEOUT")
+				}
+			}
+		})
+	}
+}
+
+// timeoutWriter buffers a response for TimeoutMiddleware until the handler
+// finishes or the request times out.
+type timeoutWriter struct {
+	mu       sync.Mutex
+	header   http.Header
+	buf      bytes.Buffer
+	code     int
+	timedOut bool
+}
+
+// Header returns the buffered response headers.
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+// WriteHeader records the status code.
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.code == 0 {
+		tw.code = code
+	}
+}
+
+// Write buffers b, or fails with http.ErrHandlerTimeout once the request timed out.
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+	return tw.buf.Write(b)
+}
+
+// flushTo writes the buffered response to w.
+func (tw *timeoutWriter) flushTo(w http.ResponseWriter) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	maps.Copy(w.Header(), tw.header)
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+	w.WriteHeader(tw.code)
+	_, _ = w.Write(tw.buf.Bytes())
+}
+
 // CORSMiddleware adds CORS headers to responses.
 func CORSMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -77,7 +443,8 @@ func CORSMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
 			if allowed && origin != "" {
 				w.Header().Set("Access-Control-Allow-Origin", origin)
 				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+requestid.Header+", "+IdempotencyKeyHeader)
+				w.Header().Set("Access-Control-Expose-Headers", requestid.Header+", "+IdempotentReplayedHeader)
 				w.Header().Set("Access-Control-Max-Age", "86400")
 			}
 
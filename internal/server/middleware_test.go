@@ -0,0 +1,295 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maauso/infinitetalk-api/internal/job"
+	"github.com/maauso/infinitetalk-api/internal/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantSame bool
+	}{
+		{name: "generates an ID when missing", header: ""},
+		{name: "reuses the client ID", header: "client-req-42", wantSame: true},
+		{name: "replaces an ID with spaces", header: "bad id"},
+		{name: "replaces an ID with control characters", header: "bad\nid"},
+		{name: "replaces an overlong ID", header: strings.Repeat("a", maxRequestIDLen+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ctxID string
+			handler := RequestIDMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctxID = requestid.FromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/health", nil)
+			if tt.header != "" {
+				req.Header.Set(requestid.Header, tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			echoed := rec.Header().Get(requestid.Header)
+			assert.NotEmpty(t, echoed)
+			assert.Equal(t, echoed, ctxID, "context and response header should carry the same ID")
+			if tt.wantSame {
+				assert.Equal(t, tt.header, echoed)
+			} else {
+				assert.NotEqual(t, tt.header, echoed)
+			}
+		})
+	}
+}
+
+func TestGzipMiddleware(t *testing.T) {
+	large := strings.Repeat(`{"status":"COMPLETED"}`, 100)
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		contentType    string
+		encoding       string
+		cacheControl   string
+		body           string
+		status         int
+		wantGzip       bool
+	}{
+		{name: "large JSON is compressed", acceptEncoding: "gzip", contentType: "application/json", body: large, status: http.StatusOK, wantGzip: true},
+		{name: "status code is preserved", acceptEncoding: "gzip", contentType: "application/json", body: large, status: http.StatusNotFound, wantGzip: true},
+		{name: "client without gzip", acceptEncoding: "", contentType: "application/json", body: large, status: http.StatusOK},
+		{name: "client refusing gzip", acceptEncoding: "gzip;q=0", contentType: "application/json", body: large, status: http.StatusOK},
+		{name: "small body", acceptEncoding: "gzip", contentType: "application/json", body: `{"status":"ok"}`, status: http.StatusOK},
+		{name: "video stream", acceptEncoding: "gzip", contentType: "video/mp4", body: large, status: http.StatusOK},
+		{name: "image", acceptEncoding: "gzip", contentType: "image/jpeg", body: large, status: http.StatusOK},
+		{name: "already encoded", acceptEncoding: "gzip", contentType: "application/json", encoding: "br", body: large, status: http.StatusOK},
+		{name: "no-transform", acceptEncoding: "gzip", contentType: "application/json", cacheControl: "no-transform", body: large, status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := GzipMiddleware(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				if tt.encoding != "" {
+					w.Header().Set("Content-Encoding", tt.encoding)
+				}
+				if tt.cacheControl != "" {
+					w.Header().Set("Cache-Control", tt.cacheControl)
+				}
+				w.WriteHeader(tt.status)
+				// Write in pieces so the threshold is crossed mid-response
+				for i := 0; i < len(tt.body); i += 100 {
+					_, _ = io.WriteString(w, tt.body[i:min(i+100, len(tt.body))])
+				}
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/jobs/job-1", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+			body := rec.Body.Bytes()
+			if tt.wantGzip {
+				assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+				assert.Less(t, len(body), len(tt.body))
+				gz, err := gzip.NewReader(bytes.NewReader(body))
+				require.NoError(t, err)
+				body, err = io.ReadAll(gz)
+				require.NoError(t, err)
+			} else {
+				assert.NotEqual(t, "gzip", rec.Header().Get("Content-Encoding"))
+			}
+			assert.Equal(t, tt.body, string(body))
+		})
+	}
+}
+
+func TestGzipMiddleware_EmptyResponse(t *testing.T) {
+	handler := GzipMiddleware(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodDelete, "/jobs/job-1", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Zero(t, rec.Body.Len())
+}
+
+func TestNewRouter_GzipDoesNotDoubleCompressJobResult(t *testing.T) {
+	h, _, _, _, _, repo := newTestHandlers(t)
+	h.compressResults = true
+	cfg := Config{GzipMinBytes: 1024}
+	router := NewRouter(h, h.logger, cfg)
+
+	videoPath := filepath.Join(t.TempDir(), "output.mp4")
+	require.NoError(t, os.WriteFile(videoPath, bytes.Repeat([]byte("video"), 1000), 0o644))
+	completed := job.New()
+	completed.Status = job.StatusCompleted
+	completed.OutputVideoPath = videoPath
+	require.NoError(t, repo.Save(context.Background(), completed))
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/"+completed.ID, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+
+	// A single gunzip yields the JSON document
+	gz, err := gzip.NewReader(rec.Body)
+	require.NoError(t, err)
+	var resp JobResponse
+	require.NoError(t, json.NewDecoder(gz).Decode(&resp))
+	assert.Equal(t, completed.ID, resp.ID)
+	assert.NotEmpty(t, resp.VideoBase64)
+
+	// An explicit opt-out is honored by the middleware as well
+	req = httptest.NewRequest(http.MethodGet, "/jobs/"+completed.ID+"?compress=none", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec = httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+}
+
+func TestTimeoutMiddleware_SlowHandler(t *testing.T) {
+	responded := make(chan struct{})
+	finished := make(chan error, 1)
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-responded
+		_, err := w.Write([]byte("too late"))
+		finished <- err
+	})
+
+	rec := httptest.NewRecorder()
+	TimeoutMiddleware(20*time.Millisecond)(slow).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/jobs", nil))
+	close(responded)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var resp ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "REQUEST_TIMEOUT", resp.Code)
+	assert.Contains(t, resp.Error, "20ms")
+
+	// Writes after the timeout are discarded
+	assert.ErrorIs(t, <-finished, http.ErrHandlerTimeout)
+	assert.NotContains(t, rec.Body.String(), "too late")
+}
+
+func TestTimeoutMiddleware_FastHandler(t *testing.T) {
+	fast := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline := r.Context().Deadline()
+		assert.True(t, hasDeadline, "handler context should carry the deadline")
+		w.Header().Set("X-Custom", "yes")
+		writeJSON(w, http.StatusAccepted, map[string]string{"status": "ok"})
+	})
+
+	rec := httptest.NewRecorder()
+	TimeoutMiddleware(time.Second)(fast).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/jobs", nil))
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, "yes", rec.Header().Get("X-Custom"))
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
+
+func TestTimeoutMiddleware_Panic(t *testing.T) {
+	panicky := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})
+	handler := ChainMiddleware(
+		RecoveryMiddleware(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		TimeoutMiddleware(time.Second),
+	)(panicky)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs/1", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestTimeoutMiddleware_Disabled(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline := r.Context().Deadline()
+		assert.False(t, hasDeadline)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	TimeoutMiddleware(0)(next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := AdminAuthMiddleware("admin-secret")(next)
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   int
+	}{
+		{"bearer token", "Authorization", "Bearer admin-secret", http.StatusNoContent},
+		{"admin key header", "X-Admin-Key", "admin-secret", http.StatusNoContent},
+		{"wrong key", "Authorization", "Bearer wrong", http.StatusUnauthorized},
+		{"basic auth", "Authorization", "Basic admin-secret", http.StatusUnauthorized},
+		{"missing key", "", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin/jobs", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Code)
+			if tt.want == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+				var resp ErrorResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, "UNAUTHORIZED", resp.Code)
+			}
+		})
+	}
+}
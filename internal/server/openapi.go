@@ -0,0 +1,68 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/maauso/infinitetalk-api/api"
+)
+
+// openAPIJSON converts the embedded OpenAPI specification to JSON once.
+var openAPIJSON = sync.OnceValues(func() ([]byte, error) {
+	return yamlToJSON(api.OpenAPIYAML)
+})
+
+// OpenAPISpec handles GET /openapi.json requests, serving the API specification.
+func (h *Handlers) OpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	spec, err := openAPIJSON()
+	if err != nil {
+		h.log(r.Context()).Error("failed to convert OpenAPI spec",
+			slog.String("error", err.Error()),
+		)
+		writeError(w, http.StatusInternalServerError, "OpenAPI specification unavailable", "INTERNAL_ERROR")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(spec)
+}
+
+// yamlToJSON converts a YAML document to JSON.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse YAML: %w", err)
+	}
+	return json.Marshal(jsonCompatible(doc))
+}
+
+// jsonCompatible converts the maps produced by the YAML decoder for
+// non-string keys into string-keyed maps, which encoding/json requires.
+func jsonCompatible(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = jsonCompatible(item)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, item := range v {
+			m[fmt.Sprint(k)] = jsonCompatible(item)
+		}
+		return m
+	case []any:
+		for i, item := range v {
+			v[i] = jsonCompatible(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openAPIDoc is the part of the OpenAPI document checked by the tests.
+type openAPIDoc struct {
+	OpenAPI    string                    `json:"openapi"`
+	Paths      map[string]map[string]any `json:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Required   []string `json:"required"`
+			Properties map[string]struct {
+				Type    string   `json:"type"`
+				Minimum *float64 `json:"minimum"`
+				Maximum *float64 `json:"maximum"`
+			} `json:"properties"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+func getOpenAPIDoc(t *testing.T) openAPIDoc {
+	t.Helper()
+	h, _, _, _, _, _ := newTestHandlers(t)
+	cfg := DefaultConfig()
+	cfg.AccessLogFormat = AccessLogNone
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	NewRouter(h, h.logger, cfg).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var doc openAPIDoc
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	return doc
+}
+
+func TestOpenAPISpec_Paths(t *testing.T) {
+	doc := getOpenAPIDoc(t)
+
+	assert.True(t, strings.HasPrefix(doc.OpenAPI, "3."), "expected an OpenAPI 3 document, got %q", doc.OpenAPI)
+
+	want := map[string][]string{
+		"/jobs":                   {"get", "post"},
+		"/jobs/{id}":              {"get", "delete"},
+		"/jobs/{id}/video":        {"get"},
+		"/jobs/{id}/video/delete": {"post"},
+		"/jobs/{id}/cancel":       {"post"},
+		"/jobs/{id}/retry":        {"post"},
+		"/health":                 {"get"},
+		"/openapi.json":           {"get"},
+	}
+	for path, methods := range want {
+		require.Contains(t, doc.Paths, path)
+		for _, method := range methods {
+			assert.Contains(t, doc.Paths[path], method, "%s should document %s", path, method)
+		}
+	}
+}
+
+// TestOpenAPISpec_CreateJobRequestMatchesValidation keeps the spec in step
+// with the validate tags of CreateJobRequest.
+func TestOpenAPISpec_CreateJobRequestMatchesValidation(t *testing.T) {
+	doc := getOpenAPIDoc(t)
+	schema, ok := doc.Components.Schemas["CreateJobRequest"]
+	require.True(t, ok, "CreateJobRequest schema is missing")
+
+	typ := reflect.TypeOf(CreateJobRequest{})
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		name := jsonTagName(field)
+		if name == "" {
+			continue
+		}
+		prop, ok := schema.Properties[name]
+		if !assert.True(t, ok, "property %q is not documented", name) {
+			continue
+		}
+
+		for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+			key, param, _ := strings.Cut(rule, "=")
+			switch key {
+			case "required":
+				assert.Contains(t, schema.Required, name)
+			case "min", "max":
+				if field.Type.Kind() != reflect.Int {
+					continue
+				}
+				limit, err := strconv.ParseFloat(param, 64)
+				require.NoError(t, err)
+				bound := prop.Minimum
+				if key == "max" {
+					bound = prop.Maximum
+				}
+				if assert.NotNil(t, bound, "%s should document %s", name, rule) {
+					assert.Equal(t, limit, *bound, "%s %s", name, key)
+				}
+			}
+		}
+	}
+}
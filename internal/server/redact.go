@@ -0,0 +1,45 @@
+package server
+
+import (
+	"log/slog"
+
+	"github.com/maauso/infinitetalk-api/internal/job"
+)
+
+// LogValue implements slog.LogValuer so that logging a request never writes
+// the base64 media. The payloads are replaced by their size.
+func (r CreateJobRequest) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("image_base64", job.RedactPayload(r.ImageBase64)),
+		slog.String("image_url", r.ImageURL),
+		slog.String("video_base64", job.RedactPayload(r.VideoBase64)),
+		slog.String("input_type", r.InputType),
+		slog.String("audio_base64", job.RedactPayload(r.AudioBase64)),
+		slog.String("audio_url", r.AudioURL),
+		slog.Int("width", r.Width),
+		slog.Int("height", r.Height),
+		slog.String("prompt", r.Prompt),
+		slog.String("provider", r.Provider),
+		slog.Bool("push_to_s3", r.PushToS3),
+		slog.Bool("dry_run", r.DryRun),
+		slog.Bool("validate_only", r.ValidateOnly),
+	}
+	if r.ForceOffload != nil {
+		attrs = append(attrs, slog.Bool("force_offload", *r.ForceOffload))
+	}
+	attrs = append(attrs,
+		slog.String("resize_mode", r.ResizeMode),
+		slog.String("person_count", r.PersonCount),
+		slog.String("priority", r.Priority),
+		slog.String("output_format", r.OutputFormat),
+		slog.Float64("target_fps", r.TargetFPS),
+		slog.Bool("strict_dimensions", r.StrictDimensions),
+		slog.Bool("use_original_audio", r.UseOriginalAudio),
+		slog.String("continuity_mode", r.ContinuityMode),
+		slog.Any("metadata", r.Metadata),
+	)
+	if r.RetentionSec != nil {
+		attrs = append(attrs, slog.Int("retention_sec", *r.RetentionSec))
+	}
+	return slog.GroupValue(attrs...)
+}
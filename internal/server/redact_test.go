@@ -0,0 +1,55 @@
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateJobRequest_LogValue(t *testing.T) {
+	image := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("image-bytes"), 100))
+	audio := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("audio-bytes"), 100))
+	forceOffload := false
+	retention := 3600
+	req := CreateJobRequest{
+		ImageBase64:  image,
+		AudioBase64:  audio,
+		Width:        384,
+		Height:       576,
+		Prompt:       "a person talking",
+		ForceOffload: &forceOffload,
+		RetentionSec: &retention,
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("create job request", slog.Any("request", req))
+
+	out := buf.String()
+	assert.NotContains(t, out, image[:64], "the image payload must not be logged")
+	assert.NotContains(t, out, audio[:64], "the audio payload must not be logged")
+
+	var entry struct {
+		Request map[string]any `json:"request"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, fmt.Sprintf("[redacted %d bytes]", len(image)), entry.Request["image_base64"])
+	assert.Equal(t, fmt.Sprintf("[redacted %d bytes]", len(audio)), entry.Request["audio_base64"])
+	assert.Equal(t, "", entry.Request["video_base64"])
+	assert.Equal(t, "a person talking", entry.Request["prompt"])
+
+	// Every request field is logged under its JSON name, so new fields are not
+	// silently left out of the log representation
+	typ := reflect.TypeOf(req)
+	for i := range typ.NumField() {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		assert.Contains(t, entry.Request, name, "field %s is not logged", typ.Field(i).Name)
+	}
+}
@@ -1,20 +1,43 @@
 package server
 
 import (
+	"io"
 	"log/slog"
 	"net/http"
+	"os"
+	"time"
 )
 
 // Config contains server configuration options.
 type Config struct {
 	// AllowedOrigins is the list of allowed CORS origins.
 	AllowedOrigins []string
+	// AccessLogFormat selects the request log format ("slog", "combined" or "none").
+	AccessLogFormat string
+	// AccessLogOutput receives combined access log lines. Defaults to stdout.
+	AccessLogOutput io.Writer
+	// GzipMinBytes is the smallest response gzipped for clients that accept it.
+	// Zero disables response compression.
+	GzipMinBytes int
+	// SeparateAdmin leaves the admin endpoints (/health, /livez, /readyz, /metrics, /admin/jobs)
+	// out of NewRouter so they can be served by NewAdminRouter on another port.
+	SeparateAdmin bool
+	// RequestTimeout bounds how long API handlers may take before 503 is
+	// returned. Streaming endpoints are exempt. Zero disables it.
+	RequestTimeout time.Duration
+	// AdminAPIKey guards the /admin/jobs endpoints (see AdminAuthMiddleware).
+	// Empty leaves them unregistered.
+	AdminAPIKey string
 }
 
 // DefaultConfig returns a Config with default values.
 func DefaultConfig() Config {
 	return Config{
-		AllowedOrigins: []string{"*"},
+		AllowedOrigins:  []string{"*"},
+		AccessLogFormat: AccessLogSlog,
+		AccessLogOutput: os.Stdout,
+		GzipMinBytes:    1024,
+		RequestTimeout:  60 * time.Second,
 	}
 }
 
@@ -24,17 +47,90 @@ func NewRouter(h *Handlers, logger *slog.Logger, cfg Config) http.Handler {
 	mux := http.NewServeMux()
 
 	// Register routes with method-based patterns (Go 1.22+)
-	mux.HandleFunc("GET /health", h.Health)
+	if !cfg.SeparateAdmin {
+		registerAdminRoutes(mux, h, cfg)
+	}
+	timeout := TimeoutMiddleware(cfg.RequestTimeout)
+	handle := func(pattern string, fn http.HandlerFunc) {
+		mux.Handle(pattern, timeout(fn))
+	}
+	handle("GET /jobs", h.ListJobs)
+	handle("DELETE /jobs/{id}", h.DeleteJob)
+	handle("POST /jobs/{id}/delete", h.DeleteJob)
+	handle("GET /jobs/{id}/thumbnail", h.GetJobThumbnail)
+	handle("GET /jobs/{id}/logs", h.GetJobLogs)
+	handle("GET /jobs/{id}/video/info", h.GetJobVideoInfo)
+	handle("POST /jobs/{id}/video/delete", h.DeleteJobVideo)
+	handle("POST /jobs/{id}/publish", h.PublishJobVideo)
+	handle("POST /jobs/{id}/cancel", h.CancelJob)
+	handle("DELETE /jobs/{id}/cancel", h.CancelJob)
+	handle("POST /jobs/{id}/retry", h.RetryJob)
+	handle("GET /openapi.json", h.OpenAPISpec)
+	// The video is streamed, so it is not buffered by the timeout
+	mux.HandleFunc("GET /jobs/{id}/video", h.GetJobVideo)
+	// A job creation cut off by the timeout would still create the job, and a
+	// retry would duplicate it. GetJob bounds reading the inline video with its
+	// own budget, which the timeout would preempt while buffering the video.
 	mux.HandleFunc("POST /jobs", h.CreateJob)
 	mux.HandleFunc("GET /jobs/{id}", h.GetJob)
-	mux.HandleFunc("POST /jobs/{id}/video/delete", h.DeleteJobVideo)
 
 	// Apply middleware chain
 	chain := ChainMiddleware(
+		RequestIDMiddleware(),
 		RecoveryMiddleware(logger),
-		LoggingMiddleware(logger),
+		AccessLogMiddleware(cfg.AccessLogFormat, logger, accessLogOutput(cfg)),
 		CORSMiddleware(cfg.AllowedOrigins),
+		gzipMiddleware(cfg),
+	)
+
+	return chain(mux)
+}
+
+// NewAdminRouter creates a router serving only the admin endpoints, for use
+// on a separate internal port together with Config.SeparateAdmin.
+// CORS is not applied since the admin port is not meant for browsers.
+func NewAdminRouter(h *Handlers, logger *slog.Logger, cfg Config) http.Handler {
+	mux := http.NewServeMux()
+	registerAdminRoutes(mux, h, cfg)
+
+	chain := ChainMiddleware(
+		RequestIDMiddleware(),
+		RecoveryMiddleware(logger),
+		AccessLogMiddleware(cfg.AccessLogFormat, logger, accessLogOutput(cfg)),
 	)
 
 	return chain(mux)
 }
+
+// registerAdminRoutes registers the operational endpoints on mux. The job
+// administration endpoints are only registered when cfg.AdminAPIKey is set,
+// and require that key.
+func registerAdminRoutes(mux *http.ServeMux, h *Handlers, cfg Config) {
+	mux.HandleFunc("GET /health", h.Health)
+	mux.HandleFunc("GET /livez", h.Health)
+	mux.HandleFunc("GET /readyz", h.Readyz)
+	mux.HandleFunc("GET /metrics", h.Metrics)
+
+	if cfg.AdminAPIKey == "" {
+		return
+	}
+	auth := AdminAuthMiddleware(cfg.AdminAPIKey)
+	mux.Handle("GET /admin/jobs", auth(http.HandlerFunc(h.AdminListJobs)))
+	mux.Handle("POST /admin/jobs/{id}/timeout", auth(http.HandlerFunc(h.AdminTimeoutJob)))
+}
+
+// gzipMiddleware returns GzipMiddleware, or a no-op when compression is disabled.
+func gzipMiddleware(cfg Config) func(http.Handler) http.Handler {
+	if cfg.GzipMinBytes <= 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return GzipMiddleware(cfg.GzipMinBytes)
+}
+
+// accessLogOutput returns the access log writer, defaulting to stdout.
+func accessLogOutput(cfg Config) io.Writer {
+	if cfg.AccessLogOutput == nil {
+		return os.Stdout
+	}
+	return cfg.AccessLogOutput
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRouter_SeparateAdmin(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+	logger := h.logger
+
+	cfg := DefaultConfig()
+	cfg.AccessLogFormat = AccessLogNone
+	cfg.SeparateAdmin = true
+
+	api := httptest.NewServer(NewRouter(h, logger, cfg))
+	defer api.Close()
+	admin := httptest.NewServer(NewAdminRouter(h, logger, cfg))
+	defer admin.Close()
+
+	get := func(t *testing.T, url string) int {
+		t.Helper()
+		resp, err := http.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return resp.StatusCode
+	}
+
+	for _, path := range []string{"/health", "/livez", "/readyz", "/metrics"} {
+		t.Run(path, func(t *testing.T) {
+			assert.Equal(t, http.StatusOK, get(t, admin.URL+path), "admin port should serve %s", path)
+			assert.Equal(t, http.StatusNotFound, get(t, api.URL+path), "API port should not serve %s", path)
+		})
+	}
+
+	t.Run("job API stays on the main port", func(t *testing.T) {
+		// An empty body reaches the handler on the main port and is rejected as invalid JSON
+		resp, err := http.Post(api.URL+"/jobs", "application/json", nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, err = http.Post(admin.URL+"/jobs", "application/json", nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestNewRouter_AdminOnMainPortByDefault(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	cfg := DefaultConfig()
+	cfg.AccessLogFormat = AccessLogNone
+
+	api := httptest.NewServer(NewRouter(h, h.logger, cfg))
+	defer api.Close()
+
+	for _, path := range []string{"/health", "/metrics"} {
+		resp, err := http.Get(api.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+	}
+}
+
+func TestNewRouter_AdminJobs(t *testing.T) {
+	h, _, _, _, _, _ := newTestHandlers(t)
+
+	status := func(t *testing.T, handler http.Handler, key string) int {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/admin/jobs?stuck=true", nil)
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Run("disabled without a key", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.AccessLogFormat = AccessLogNone
+
+		assert.Equal(t, http.StatusNotFound, status(t, NewRouter(h, h.logger, cfg), ""))
+	})
+
+	t.Run("requires the key", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.AccessLogFormat = AccessLogNone
+		cfg.AdminAPIKey = "admin-secret"
+		router := NewRouter(h, h.logger, cfg)
+
+		assert.Equal(t, http.StatusUnauthorized, status(t, router, ""))
+		assert.Equal(t, http.StatusUnauthorized, status(t, router, "wrong"))
+		assert.Equal(t, http.StatusOK, status(t, router, "admin-secret"))
+	})
+
+	t.Run("served on the admin port", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.AccessLogFormat = AccessLogNone
+		cfg.AdminAPIKey = "admin-secret"
+		cfg.SeparateAdmin = true
+
+		assert.Equal(t, http.StatusOK, status(t, NewAdminRouter(h, h.logger, cfg), "admin-secret"))
+		assert.Equal(t, http.StatusNotFound, status(t, NewRouter(h, h.logger, cfg), "admin-secret"))
+	})
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// sniffLen is the number of decoded bytes inspected, matching http.DetectContentType.
+const sniffLen = 512
+
+// Errors returned by checkInputTypes.
+var (
+	errInputsSwapped = errors.New("inputs appear swapped: image_base64 contains audio and audio_base64 contains an image")
+	errImageNotImage = errors.New("image_base64 does not contain a recognized image")
+	errAudioNotAudio = errors.New("audio_base64 does not contain recognized audio")
+)
+
+// mediaKind is the broad type of a sniffed input.
+type mediaKind int
+
+const (
+	kindUnknown mediaKind = iota
+	kindImage
+	kindAudio
+)
+
+// audioSignatures are magic prefixes of audio containers that
+// http.DetectContentType does not report as audio.
+var audioSignatures = [][]byte{
+	[]byte("fLaC"),           // FLAC
+	[]byte("OggS"),           // Ogg Vorbis/Opus
+	[]byte("#!AMR"),          // AMR
+	{0x1A, 0x45, 0xDF, 0xA3}, // Matroska/WebM
+}
+
+// imageBrands are ISO BMFF brands of HEIF and AVIF images, which
+// http.DetectContentType does not report as images.
+var imageBrands = []string{"heic", "heix", "mif1", "msf1", "avif"}
+
+// checkInputTypes verifies that the image and audio inputs sniff as an image
+// and as audio respectively. When each looks like the other, it reports
+// errInputsSwapped rather than two separate type errors.
+func checkInputTypes(imageB64, audioB64 string) error {
+	imageKind := sniffBase64(imageB64)
+	audioKind := sniffBase64(audioB64)
+
+	switch {
+	case imageKind == kindAudio && audioKind == kindImage:
+		return errInputsSwapped
+	case imageKind != kindImage:
+		return errImageNotImage
+	case audioKind != kindAudio:
+		return errAudioNotAudio
+	}
+	return nil
+}
+
+// checkAudioType verifies that the audio input sniffs as audio.
+func checkAudioType(audioB64 string) error {
+	if sniffBase64(audioB64) != kindAudio {
+		return errAudioNotAudio
+	}
+	return nil
+}
+
+// checkImageType verifies that the image input sniffs as an image.
+func checkImageType(imageB64 string) error {
+	if sniffBase64(imageB64) != kindImage {
+		return errImageNotImage
+	}
+	return nil
+}
+
+// sniffBase64 decodes the start of a base64 payload and classifies it.
+func sniffBase64(b64 string) mediaKind {
+	// Whole base64 quanta only, so the prefix decodes without padding errors
+	prefix := b64[:min(len(b64), base64.StdEncoding.EncodedLen(sniffLen))]
+	prefix = prefix[:len(prefix)/4*4]
+	data, err := base64.StdEncoding.DecodeString(prefix)
+	if err != nil {
+		return kindUnknown
+	}
+	return sniff(data)
+}
+
+// sniff classifies the leading bytes of a file as an image, audio or unknown.
+func sniff(data []byte) mediaKind {
+	contentType := http.DetectContentType(data)
+	switch {
+	case strings.HasPrefix(contentType, "image/"):
+		return kindImage
+	case strings.HasPrefix(contentType, "audio/"),
+		contentType == "application/ogg",
+		contentType == "video/mp4", // M4A shares the MP4 container
+		contentType == "video/webm":
+		return kindAudio
+	}
+
+	if len(data) >= 12 && string(data[4:8]) == "ftyp" && slices.Contains(imageBrands, string(data[8:12])) {
+		return kindImage
+	}
+	for _, sig := range audioSignatures {
+		if bytes.HasPrefix(data, sig) {
+			return kindAudio
+		}
+	}
+	// MPEG audio (MP3) and ADTS AAC frames start with an 11-bit sync word
+	if len(data) >= 2 && data[0] == 0xFF && data[1]&0xE0 == 0xE0 {
+		return kindAudio
+	}
+	return kindUnknown
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSniff(t *testing.T) {
+	pad := func(prefix []byte) []byte { return append(prefix, make([]byte, 32)...) }
+
+	tests := []struct {
+		name string
+		data []byte
+		want mediaKind
+	}{
+		{name: "png", data: pad([]byte("\x89PNG\r\n\x1a\n")), want: kindImage},
+		{name: "jpeg", data: pad([]byte{0xFF, 0xD8, 0xFF, 0xE0}), want: kindImage},
+		{name: "webp", data: pad([]byte("RIFF\x24\x00\x00\x00WEBPVP8 ")), want: kindImage},
+		{name: "gif", data: pad([]byte("GIF89a")), want: kindImage},
+		{name: "heic", data: pad([]byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00mif1heic")), want: kindImage},
+		{name: "wav", data: pad([]byte("RIFF\x24\x00\x00\x00WAVEfmt ")), want: kindAudio},
+		{name: "mp3 with id3 tag", data: pad([]byte("ID3\x03\x00")), want: kindAudio},
+		{name: "mp3 frame", data: pad([]byte{0xFF, 0xFB, 0x90, 0x64}), want: kindAudio},
+		{name: "aac adts", data: pad([]byte{0xFF, 0xF1, 0x50, 0x80}), want: kindAudio},
+		{name: "flac", data: pad([]byte("fLaC")), want: kindAudio},
+		{name: "ogg", data: pad([]byte("OggS")), want: kindAudio},
+		{name: "m4a", data: pad([]byte("\x00\x00\x00\x20ftypM4A \x00\x00\x00\x00M4A mp42isom")), want: kindAudio},
+		{name: "text", data: []byte("hello world"), want: kindUnknown},
+		{name: "empty", data: nil, want: kindUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sniff(tt.data))
+			assert.Equal(t, tt.want, sniffBase64(base64.StdEncoding.EncodeToString(tt.data)))
+		})
+	}
+}
+
+func TestSniffBase64_LargeInput(t *testing.T) {
+	// Only the prefix is decoded, so a long payload whose length is not a
+	// multiple of the sniff window still classifies correctly
+	data := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 100_001)...)
+	assert.Equal(t, kindImage, sniffBase64(base64.StdEncoding.EncodeToString(data)))
+}
+
+func TestCheckInputTypes(t *testing.T) {
+	png := base64.StdEncoding.EncodeToString([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR"))
+	wav := base64.StdEncoding.EncodeToString([]byte("RIFF\x24\x00\x00\x00WAVEfmt \x10\x00\x00\x00"))
+
+	assert.NoError(t, checkInputTypes(png, wav))
+	assert.ErrorIs(t, checkInputTypes(wav, png), errInputsSwapped)
+	assert.ErrorIs(t, checkInputTypes(wav, wav), errImageNotImage)
+	assert.ErrorIs(t, checkInputTypes(png, png), errAudioNotAudio)
+}
@@ -2,38 +2,117 @@
 // It includes handlers, middleware, routes, and DTOs separated from domain types.
 package server
 
+import "time"
+
 // CreateJobRequest is the HTTP request body for creating a new job.
 type CreateJobRequest struct {
-	// ImageBase64 is the base64-encoded source image.
-	ImageBase64 string `json:"image_base64" validate:"required,base64"`
+	// ImageBase64 is the base64-encoded source image. Not used when InputType is "video".
+	// Exactly one of ImageBase64 and ImageURL is required for image inputs.
+	ImageBase64 string `json:"image_base64" validate:"omitempty,base64"`
+	// ImageURL is an http(s) URL the source image is downloaded from when the
+	// job runs, instead of sending it as ImageBase64.
+	ImageURL string `json:"image_url" validate:"omitempty,http_url"`
+	// VideoBase64 is the base64-encoded source video, required when InputType is "video".
+	VideoBase64 string `json:"video_base64" validate:"required_if=InputType video,omitempty,base64"`
+	// InputType is the type of the source media: "image" or "video". Defaults to "image".
+	InputType string `json:"input_type" validate:"omitempty,oneof=image video"`
 	// AudioBase64 is the base64-encoded source audio.
 	// The audio will be processed and split into WAV PCM (pcm_s16le) chunks
 	// to ensure compatibility with RunPod workers (PyAV/librosa).
 	// Supported input formats: WAV, MP3, AAC, and other ffmpeg-compatible formats.
-	AudioBase64 string `json:"audio_base64" validate:"required,base64"`
+	// Exactly one of AudioBase64 and AudioURL is required.
+	AudioBase64 string `json:"audio_base64" validate:"omitempty,base64"`
+	// AudioURL is an http(s) URL the source audio is downloaded from when the
+	// job runs, instead of sending it as AudioBase64.
+	AudioURL string `json:"audio_url" validate:"omitempty,http_url"`
 	// Width is the target video width.
 	Width int `json:"width" validate:"required,min=1,max=4096"`
 	// Height is the target video height.
 	Height int `json:"height" validate:"required,min=1,max=4096"`
-	// Prompt is the text prompt for video generation. Defaults to "A person talking naturally" if not provided.
-	Prompt string `json:"prompt" validate:"omitempty"`
+	// Prompt is the text prompt for video generation, at most 1000 characters.
+	// Defaults to job.DefaultPrompt if empty.
+	Prompt string `json:"prompt" validate:"omitempty,max=1000"`
 	// Provider specifies the video generation provider ("runpod" or "beam"). Defaults to "runpod".
 	Provider string `json:"provider" validate:"omitempty,oneof=runpod beam"`
 	// PushToS3 indicates whether to upload the final video to S3.
 	PushToS3 bool `json:"push_to_s3"`
 	// DryRun skips RunPod calls and completes after preprocessing.
 	DryRun bool `json:"dry_run"`
+	// ValidateOnly only probes the inputs and reports what was detected,
+	// without creating a job.
+	ValidateOnly bool `json:"validate_only"`
 	// ForceOffload forces offload on the provider. Defaults to true if not specified.
 	// Use a pointer to distinguish between explicit false and not provided.
 	ForceOffload *bool `json:"force_offload,omitempty"`
+	// ResizeMode selects how the image is fitted: "pad" letterboxes with black bars,
+	// "crop" crops the image to fill the frame. Defaults to "pad".
+	ResizeMode string `json:"resize_mode" validate:"omitempty,oneof=pad crop"`
+	// PersonCount is the number of people to animate: "single" or "multi". Defaults to "single".
+	PersonCount string `json:"person_count" validate:"omitempty,oneof=single multi"`
+	// Priority orders the job in the queue: "high" jobs are picked up before
+	// "normal" ones, and "low" ones last. Defaults to "normal".
+	Priority string `json:"priority" validate:"omitempty,oneof=high normal low"`
+	// OutputFormat is the container of the output video: "mp4", "mov" or "webm".
+	// WebM videos are encoded with VP9/Opus. Defaults to "mp4".
+	OutputFormat string `json:"output_format" validate:"omitempty,oneof=mp4 mov webm"`
+	// TargetFPS normalizes every chunk to this frame rate before they are joined,
+	// so that chunks returned at slightly different rates join cleanly.
+	// Omitted or 0 keeps the provider's frame rate.
+	TargetFPS float64 `json:"target_fps" validate:"omitempty,gt=0,lte=120"`
+	// StrictDimensions fails the job when a generated chunk does not have the
+	// requested width and height. By default such chunks are resized to match.
+	StrictDimensions bool `json:"strict_dimensions"`
+	// UseOriginalAudio replaces the audio of the joined chunks with the
+	// uploaded audio, so the output carries the source track untouched.
+	UseOriginalAudio bool `json:"use_original_audio"`
+	// ContinuityMode selects the source image of each chunk: "none" animates
+	// every chunk from the uploaded image, "last_frame" from the last frame of
+	// the previous chunk so motion carries over. Defaults to "none".
+	ContinuityMode string `json:"continuity_mode" validate:"omitempty,oneof=none last_frame"`
+	// RetentionSec is how long after creation the job and its output are kept,
+	// instead of JOB_TTL_SEC, up to MAX_RETENTION_SEC. Zero deletes them once
+	// the video has been downloaded.
+	RetentionSec *int `json:"retention_sec" validate:"omitempty,min=0"`
+	// Metadata holds client-defined key/value pairs, such as a customer ID,
+	// that are stored with the job and returned in JobResponse. At most 16
+	// entries; keys are 1-64 characters without ':' and values up to 256.
+	Metadata map[string]string `json:"metadata" validate:"omitempty,max=16,dive,keys,min=1,max=64,excludes=:,endkeys,max=256"`
 }
 
 // CreateJobResponse is the HTTP response after creating a job.
 type CreateJobResponse struct {
-	// ID is the unique identifier for the created job.
-	ID string `json:"id"`
+	// ID is the unique identifier for the created job (omitted for validate_only).
+	ID string `json:"id,omitempty"`
 	// Status is the initial job status.
 	Status string `json:"status"`
+	// Width is the output video width, after snapping when ?snap=true.
+	Width int `json:"width"`
+	// Height is the output video height, after snapping when ?snap=true.
+	Height int `json:"height"`
+	// ExpiresAt is when the job results will be purged (omitted when retention is disabled).
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// Probe holds what was detected in the inputs (only for validate_only).
+	Probe *InputProbeResponse `json:"probe,omitempty"`
+	// Deduplicated is true when ?dedup=true returned an earlier completed job
+	// with identical inputs instead of creating a new one.
+	Deduplicated bool `json:"deduplicated,omitempty"`
+	// PollURL is where the job status can be polled (omitted for validate_only).
+	PollURL string `json:"poll_url,omitempty"`
+	// RecommendedPollIntervalSec is how often to poll PollURL; also sent as
+	// the Retry-After header of 202 responses.
+	RecommendedPollIntervalSec int `json:"recommended_poll_interval_sec,omitempty"`
+}
+
+// InputProbeResponse describes the probed inputs of a validate_only request.
+type InputProbeResponse struct {
+	// ImageWidth is the width of the input image in pixels.
+	ImageWidth int `json:"image_width"`
+	// ImageHeight is the height of the input image in pixels.
+	ImageHeight int `json:"image_height"`
+	// AudioDurationSec is the duration of the input audio in seconds.
+	AudioDurationSec float64 `json:"audio_duration_sec"`
+	// EstimatedChunks is how many chunks the audio is expected to be split into.
+	EstimatedChunks int `json:"estimated_chunks"`
 }
 
 // JobResponse is the HTTP response for getting job details.
@@ -44,14 +123,146 @@ type JobResponse struct {
 	Provider string `json:"provider"`
 	// Status is the current job status.
 	Status string `json:"status"`
+	// Stage is the processing phase: "preprocessing", "generating", "joining",
+	// "uploading" or "done" (omitted until processing starts).
+	Stage string `json:"stage,omitempty"`
+	// Priority is the priority the job was queued with.
+	Priority string `json:"priority"`
+	// QueuePosition is the 1-based position of the job among the jobs waiting
+	// for a worker (only while it is IN_QUEUE).
+	QueuePosition *int `json:"queue_position,omitempty"`
 	// Progress is the percentage of completion (0-100).
 	Progress int `json:"progress"`
+	// EstimatedSecondsRemaining estimates how long the remaining chunks will take
+	// (omitted until a chunk has completed, and once the job has finished).
+	EstimatedSecondsRemaining *int `json:"estimated_seconds_remaining,omitempty"`
 	// Error contains any error message if the job failed.
 	Error string `json:"error,omitempty"`
 	// VideoBase64 is the base64-encoded video content (if push_to_s3=false and completed).
 	VideoBase64 string `json:"video_base64,omitempty"`
 	// VideoURL is the S3 URL of the output video (if push_to_s3=true and completed).
 	VideoURL string `json:"video_url,omitempty"`
+	// ThumbnailURL is where the preview image can be fetched (if completed and generated).
+	// It is the S3 URL when push_to_s3=true, otherwise the /jobs/{id}/thumbnail path.
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	// ExpiresAt is when the job results will be purged (omitted when retention is disabled).
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// RetentionSec is the retention the job was created with (omitted when it
+	// uses JOB_TTL_SEC); 0 means it is deleted once the video is downloaded.
+	RetentionSec *int `json:"retention_sec,omitempty"`
+	// VideoDownloadedAt is when the video was first downloaded in full from
+	// GET /jobs/{id}/video (omitted until then).
+	VideoDownloadedAt *time.Time `json:"video_downloaded_at,omitempty"`
+	// Metadata holds the key/value pairs the job was created with.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Chunks contains per-chunk details (only with ?include=chunks, and always
+	// in the response to a cancel).
+	Chunks []ChunkResponse `json:"chunks,omitempty"`
+}
+
+// JobListResponse is the response of GET /jobs.
+type JobListResponse struct {
+	// Jobs are the matching jobs, newest first. Inline videos are never included.
+	Jobs []JobResponse `json:"jobs"`
+}
+
+// AdminJobResponse is a job as reported by the admin endpoints, with the
+// timestamps used to tell whether it is stuck.
+type AdminJobResponse struct {
+	JobResponse
+	// StartedAt is when processing started (omitted while queued).
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	// UpdatedAt is when the job was last updated.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AdminJobListResponse is the response of GET /admin/jobs.
+type AdminJobListResponse struct {
+	// Jobs are the matching jobs; stuck jobs are listed oldest update first.
+	Jobs []AdminJobResponse `json:"jobs"`
+}
+
+// ChunkResponse is the per-chunk detail included in JobResponse.
+type ChunkResponse struct {
+	// Index is the position of the chunk in the sequence.
+	Index int `json:"index"`
+	// Status is the current chunk status.
+	Status string `json:"status"`
+	// RunPodJobID is the provider job ID assigned to the chunk.
+	RunPodJobID string `json:"runpod_job_id,omitempty"`
+	// Error contains any error message if the chunk failed.
+	Error string `json:"error,omitempty"`
+	// StartedAt is when the chunk was last submitted to the provider.
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	// CompletedAt is when chunk processing finished.
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// Attempts is the number of times the chunk was submitted to the provider.
+	Attempts int `json:"attempts,omitempty"`
+	// FailureStage is the step in which the chunk failed: prepare, submit, poll or output.
+	FailureStage string `json:"failure_stage,omitempty"`
+	// ProviderStatus is the last provider status seen when the chunk failed.
+	ProviderStatus string `json:"provider_status,omitempty"`
+	// ProviderError is the error reported by the provider when the chunk failed.
+	ProviderError string `json:"provider_error,omitempty"`
+	// CancelRequested is true when a provider cancel was issued for the chunk.
+	CancelRequested bool `json:"cancel_requested,omitempty"`
+	// CancelConfirmed is true when the provider acknowledged the cancel.
+	CancelConfirmed bool `json:"cancel_confirmed,omitempty"`
+	// CancelError is the provider error if the cancel failed.
+	CancelError string `json:"cancel_error,omitempty"`
+}
+
+// VideoInfoResponse is the response of GET /jobs/{id}/video/info.
+type VideoInfoResponse struct {
+	// ID is the unique identifier for the job.
+	ID string `json:"id"`
+	// Width is the frame width in pixels.
+	Width int `json:"width"`
+	// Height is the frame height in pixels.
+	Height int `json:"height"`
+	// DurationSec is the duration in seconds.
+	DurationSec float64 `json:"duration_sec"`
+	// SizeBytes is the size of the video file.
+	SizeBytes int64 `json:"size_bytes"`
+	// FPS is the average frame rate.
+	FPS float64 `json:"fps"`
+	// VideoCodec is the video codec name (e.g. "h264").
+	VideoCodec string `json:"video_codec"`
+	// AudioCodec is the audio codec name (omitted when the video has no audio).
+	AudioCodec string `json:"audio_codec,omitempty"`
+}
+
+// JobLogsResponse is the response of GET /jobs/{id}/logs.
+type JobLogsResponse struct {
+	// ID is the unique identifier for the job.
+	ID string `json:"id"`
+	// Lines are the captured log lines, oldest first.
+	Lines []JobLogLine `json:"lines"`
+	// Dropped is how many older lines were discarded to stay within the limit.
+	Dropped int `json:"dropped"`
+}
+
+// JobLogLine is a log line captured while a job was created or processed.
+type JobLogLine struct {
+	// Time is when the line was logged.
+	Time time.Time `json:"time"`
+	// Level is the log level, e.g. "INFO".
+	Level string `json:"level"`
+	// Message is the log message.
+	Message string `json:"message"`
+	// Attrs holds the attributes of the line.
+	Attrs map[string]string `json:"attrs,omitempty"`
+}
+
+// PublishJobResponse is the response of POST /jobs/{id}/publish.
+type PublishJobResponse struct {
+	// ID is the unique identifier for the job.
+	ID string `json:"id"`
+	// VideoURL is the remote storage URL of the output video.
+	VideoURL string `json:"video_url"`
+	// ThumbnailURL is the remote storage URL of the preview image (omitted when
+	// there is none or it could not be uploaded).
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
 }
 
 // ErrorResponse is the standard error response format.
@@ -60,6 +271,9 @@ type ErrorResponse struct {
 	Error string `json:"error"`
 	// Code is the error code for programmatic handling.
 	Code string `json:"code"`
+	// Details carries structured information about the error, e.g. the
+	// "fields" that failed validation. Omitted when there is none.
+	Details map[string]any `json:"details,omitempty"`
 }
 
 // HealthResponse is the HTTP response for the health check endpoint.
@@ -67,3 +281,35 @@ type HealthResponse struct {
 	// Status is the health status of the service.
 	Status string `json:"status"`
 }
+
+// ReadinessResponse is the HTTP response for the readiness endpoint.
+type ReadinessResponse struct {
+	// Status is "ok" when every check passed, otherwise "unavailable".
+	Status string `json:"status"`
+	// Checks holds the result of each readiness check.
+	Checks []CheckResult `json:"checks"`
+}
+
+// CheckResult is the outcome of a single readiness check.
+type CheckResult struct {
+	// Name identifies the check.
+	Name string `json:"name"`
+	// Status is "ok" or "fail".
+	Status string `json:"status"`
+	// Error describes why the check failed.
+	Error string `json:"error,omitempty"`
+}
+
+// MetricsResponse is the HTTP response for the metrics endpoint.
+type MetricsResponse struct {
+	// Providers holds the in-flight provider calls, keyed by provider name.
+	Providers map[string]ProviderActivity `json:"providers"`
+}
+
+// ProviderActivity is the number of provider calls currently in flight.
+type ProviderActivity struct {
+	// Submits is the number of in-flight job submissions.
+	Submits int64 `json:"submits"`
+	// Polls is the number of in-flight status polls.
+	Polls int64 `json:"polls"`
+}
@@ -0,0 +1,452 @@
+package storage
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultGCSEndpoint is the Google Cloud Storage API endpoint.
+const DefaultGCSEndpoint = "https://storage.googleapis.com"
+
+// DefaultGCSTimeout bounds each GCS API request, uploads included, when no
+// HTTP client is configured.
+const DefaultGCSTimeout = 10 * time.Minute
+
+// defaultTokenURI is the OAuth2 token endpoint used for service account keys
+// that do not name one.
+const defaultTokenURI = "https://oauth2.googleapis.com/token"
+
+// gcsScope is the OAuth2 scope requested for service account tokens.
+const gcsScope = "https://www.googleapis.com/auth/devstorage.read_write"
+
+// metadataTokenURL serves access tokens for the instance's service account on
+// GCE, GKE and Cloud Run.
+const metadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// tokenExpiryMargin refreshes cached access tokens this long before they expire.
+const tokenExpiryMargin = time.Minute
+
+// Static errors for GCS storage.
+var (
+	// ErrGCSRequest is returned when the GCS API responds with a non-2xx status.
+	ErrGCSRequest = errors.New("GCS request failed")
+	// ErrGCSCredentials is returned when no access token can be obtained for GCS.
+	ErrGCSCredentials = errors.New("no usable GCS credentials")
+)
+
+// GCSConfig holds the configuration for Google Cloud Storage.
+type GCSConfig struct {
+	Bucket string
+	// Endpoint overrides the GCS API endpoint, e.g. for fake-gcs-server.
+	// Empty uses DefaultGCSEndpoint.
+	Endpoint string
+	// KeyPrefix is prepended to every object key, e.g. "prod/infinitetalk".
+	// Leading and trailing slashes are ignored.
+	KeyPrefix string
+	// TokenSource returns the OAuth2 access token sent with each request.
+	// Nil uses CredentialsFile when set, otherwise the service account from
+	// the GCP metadata server when Endpoint is empty, and no authentication
+	// for custom endpoints (emulators).
+	TokenSource func(ctx context.Context) (string, error)
+	// CredentialsFile is the path of a service account JSON key, as named by
+	// GOOGLE_APPLICATION_CREDENTIALS, for running outside GCP.
+	CredentialsFile string
+	// HTTPClient is used for API requests. Nil uses a client whose requests
+	// time out after Timeout.
+	HTTPClient *http.Client
+	// Timeout bounds each request of the default HTTP client.
+	// Zero uses DefaultGCSTimeout.
+	Timeout time.Duration
+}
+
+// GCSStorage wraps LocalStorage and adds Google Cloud Storage upload capability.
+// It uses LocalStorage for temporary file operations and GCS for final storage.
+type GCSStorage struct {
+	*LocalStorage
+	client    *http.Client
+	endpoint  string
+	bucket    string
+	keyPrefix string
+	token     func(ctx context.Context) (string, error)
+}
+
+// NewGCSStorage creates a new GCSStorage instance.
+// The tempDir parameter specifies where temporary files are stored.
+// The cfg parameter contains GCS configuration.
+func NewGCSStorage(tempDir string, cfg GCSConfig) (*GCSStorage, error) {
+	local, err := NewLocalStorage(tempDir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &GCSStorage{
+		LocalStorage: local,
+		client:       cfg.HTTPClient,
+		endpoint:     strings.TrimSuffix(cfg.Endpoint, "/"),
+		bucket:       cfg.Bucket,
+		keyPrefix:    strings.Trim(cfg.KeyPrefix, "/"),
+		token:        cfg.TokenSource,
+	}
+	if s.client == nil {
+		timeout := cfg.Timeout
+		if timeout <= 0 {
+			timeout = DefaultGCSTimeout
+		}
+		s.client = &http.Client{Timeout: timeout}
+	}
+
+	switch {
+	case s.token != nil:
+	case cfg.CredentialsFile != "":
+		src, err := newServiceAccountTokenSource(cfg.CredentialsFile, s.client)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrGCSCredentials, err)
+		}
+		s.token = src.Token
+	case s.endpoint == "":
+		s.token = (&metadataTokenSource{client: s.client, url: metadataTokenURL}).Token
+	}
+	if s.endpoint == "" {
+		s.endpoint = DefaultGCSEndpoint
+	}
+	return s, nil
+}
+
+// CheckCredentials fetches an access token to verify that requests can be
+// authenticated, e.g. at startup, so that missing credentials are reported
+// before the first upload. It succeeds when requests are not authenticated.
+func (s *GCSStorage) CheckCredentials(ctx context.Context) error {
+	if s.token == nil {
+		return nil
+	}
+	if _, err := s.token(ctx); err != nil {
+		return fmt.Errorf("%w: %w (outside GCP, set GOOGLE_APPLICATION_CREDENTIALS to a service account key)", ErrGCSCredentials, err)
+	}
+	return nil
+}
+
+// Upload uploads data to the GCS bucket under the configured key prefix
+// and returns the object's public URL. The content type is derived from the
+// key's extension so that browsers can play or display the object inline.
+func (s *GCSStorage) Upload(ctx context.Context, key string, data io.Reader) (string, error) {
+	objectKey := s.objectKey(key)
+
+	query := url.Values{"uploadType": {"media"}, "name": {objectKey}}
+	uploadURL := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?%s", s.endpoint, url.PathEscape(s.bucket), query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, data)
+	if err != nil {
+		return "", fmt.Errorf("upload to GCS: %w", err)
+	}
+	req.Header.Set("Content-Type", contentTypeForKey(objectKey))
+
+	if err := s.do(req); err != nil {
+		return "", fmt.Errorf("upload to GCS: %w", err)
+	}
+
+	return s.ObjectURL(ctx, key)
+}
+
+// ObjectURL returns the public URL of the object stored under key (with the
+// configured key prefix).
+func (s *GCSStorage) ObjectURL(_ context.Context, key string) (string, error) {
+	return fmt.Sprintf("%s/%s/%s", s.endpoint, s.bucket, escapeObjectPath(s.objectKey(key))), nil
+}
+
+// Download opens the object stored under key (with the configured key prefix).
+func (s *GCSStorage) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	downloadURL := fmt.Sprintf("%s/storage/v1/b/%s/o/%s?alt=media",
+		s.endpoint, url.PathEscape(s.bucket), url.PathEscape(s.objectKey(key)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("download from GCS: %w", err)
+	}
+
+	resp, err := s.send(req)
+	if err != nil {
+		return nil, fmt.Errorf("download from GCS: %w", err)
+	}
+	return resp.Body, nil
+}
+
+// DeleteObject removes the object stored under key (with the configured key prefix).
+// A missing object is treated as success so that deletes are idempotent.
+func (s *GCSStorage) DeleteObject(ctx context.Context, key string) error {
+	deleteURL := fmt.Sprintf("%s/storage/v1/b/%s/o/%s",
+		s.endpoint, url.PathEscape(s.bucket), url.PathEscape(s.objectKey(key)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, deleteURL, nil)
+	if err != nil {
+		return fmt.Errorf("delete from GCS: %w", err)
+	}
+	if err := s.do(req); err != nil && !errors.Is(err, ErrObjectNotFound) {
+		return fmt.Errorf("delete from GCS: %w", err)
+	}
+	return nil
+}
+
+// Ping verifies that the configured bucket exists and is accessible.
+func (s *GCSStorage) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("%s/storage/v1/b/%s", s.endpoint, url.PathEscape(s.bucket)), nil)
+	if err != nil {
+		return fmt.Errorf("get bucket %s: %w", s.bucket, err)
+	}
+	if err := s.do(req); err != nil {
+		return fmt.Errorf("get bucket %s: %w", s.bucket, err)
+	}
+	return nil
+}
+
+// do sends req and discards the response body.
+func (s *GCSStorage) do(req *http.Request) error {
+	resp, err := s.send(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.Body.Close()
+}
+
+// send authorizes and sends req, returning ErrGCSRequest for non-2xx
+// responses (wrapping ErrObjectNotFound for 404s). On success the caller
+// must close the response body.
+func (s *GCSStorage) send(req *http.Request) (*http.Response, error) {
+	if s.token != nil {
+		token, err := s.token(req.Context())
+		if err != nil {
+			return nil, fmt.Errorf("get access token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("%w: status %d: %s", ErrGCSRequest, resp.StatusCode, strings.TrimSpace(string(body)))
+		if resp.StatusCode == http.StatusNotFound {
+			err = fmt.Errorf("%w: %w", ErrObjectNotFound, err)
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+// objectKey prepends the configured key prefix to key.
+func (s *GCSStorage) objectKey(key string) string {
+	if s.keyPrefix == "" {
+		return key
+	}
+	return s.keyPrefix + "/" + strings.TrimPrefix(key, "/")
+}
+
+// escapeObjectPath escapes each segment of an object key for use in a URL path.
+func escapeObjectPath(objectKey string) string {
+	segments := strings.Split(objectKey, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// metadataTokenSource fetches access tokens from the GCP metadata server and
+// caches them until shortly before they expire.
+type metadataTokenSource struct {
+	client *http.Client
+	url    string
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// Token returns a valid access token, fetching a new one when needed.
+func (m *metadataTokenSource) Token(ctx context.Context) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.token != "" && time.Now().Before(m.expires) {
+		return m.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	token, expiresIn, err := requestToken(m.client, req)
+	if err != nil {
+		return "", fmt.Errorf("query metadata server: %w", err)
+	}
+
+	m.token = token
+	m.expires = time.Now().Add(expiresIn - tokenExpiryMargin)
+	return m.token, nil
+}
+
+// serviceAccountKey holds the fields of a service account JSON key used to
+// obtain access tokens.
+type serviceAccountKey struct {
+	Type        string `json:"type"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// serviceAccountTokenSource exchanges JWTs signed with a service account key
+// for access tokens (RFC 7523) and caches them until shortly before they expire.
+type serviceAccountTokenSource struct {
+	client   *http.Client
+	email    string
+	key      *rsa.PrivateKey
+	tokenURI string
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// newServiceAccountTokenSource reads the service account JSON key at path.
+func newServiceAccountTokenSource(path string, client *http.Client) (*serviceAccountTokenSource, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path comes from the operator's configuration
+	if err != nil {
+		return nil, fmt.Errorf("read credentials file: %w", err)
+	}
+
+	var key serviceAccountKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("parse credentials file %s: %w", path, err)
+	}
+	if key.Type != "service_account" || key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil, fmt.Errorf("credentials file %s is not a service account key", path)
+	}
+
+	block, _ := pem.Decode([]byte(key.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("credentials file %s: private key is not PEM encoded", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("credentials file %s: parse private key: %w", path, err)
+	}
+	rsaKey, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("credentials file %s: private key is not an RSA key", path)
+	}
+
+	tokenURI := key.TokenURI
+	if tokenURI == "" {
+		tokenURI = defaultTokenURI
+	}
+	return &serviceAccountTokenSource{client: client, email: key.ClientEmail, key: rsaKey, tokenURI: tokenURI}, nil
+}
+
+// Token returns a valid access token, fetching a new one when needed.
+func (s *serviceAccountTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Before(s.expires) {
+		return s.token, nil
+	}
+
+	assertion, err := s.assertion(time.Now())
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	token, expiresIn, err := requestToken(s.client, req)
+	if err != nil {
+		return "", fmt.Errorf("exchange service account token: %w", err)
+	}
+
+	s.token = token
+	s.expires = time.Now().Add(expiresIn - tokenExpiryMargin)
+	return s.token, nil
+}
+
+// assertion returns the RS256-signed JWT requesting a GCS token, issued at now.
+func (s *serviceAccountTokenSource) assertion(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iss":   s.email,
+		"scope": gcsScope,
+		"aud":   s.tokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	signingInput := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("sign service account assertion: %w", err)
+	}
+	return signingInput + "." + enc.EncodeToString(signature), nil
+}
+
+// requestToken sends req to an OAuth2 token endpoint and returns the access
+// token of the response and how long it is valid.
+func requestToken(client *http.Client, req *http.Request) (string, time.Duration, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", 0, fmt.Errorf("decode token: %w", err)
+	}
+	if body.AccessToken == "" {
+		return "", 0, errors.New("response has no access token")
+	}
+	return body.AccessToken, time.Duration(body.ExpiresIn) * time.Second, nil
+}
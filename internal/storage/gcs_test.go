@@ -0,0 +1,359 @@
+package storage
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func staticToken(token string) func(context.Context) (string, error) {
+	return func(context.Context) (string, error) { return token, nil }
+}
+
+func TestGCSStorage_Upload(t *testing.T) {
+	var gotPath, gotName, gotUploadType, gotContentType, gotAuth, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotName = r.URL.Query().Get("name")
+		gotUploadType = r.URL.Query().Get("uploadType")
+		gotContentType = r.Header.Get("Content-Type")
+		gotAuth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		_, _ = io.WriteString(w, `{"name":"ok"}`)
+	}))
+	defer server.Close()
+
+	storage, err := NewGCSStorage(t.TempDir(), GCSConfig{
+		Bucket:      "test-bucket",
+		Endpoint:    server.URL,
+		KeyPrefix:   "/prod/",
+		TokenSource: staticToken("test-token"),
+	})
+	if err != nil {
+		t.Fatalf("NewGCSStorage() error = %v", err)
+	}
+
+	url, err := storage.Upload(context.Background(), "videos/job 1.mp4", strings.NewReader("video data"))
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	if gotPath != "/upload/storage/v1/b/test-bucket/o" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if gotUploadType != "media" {
+		t.Errorf("uploadType = %q, want media", gotUploadType)
+	}
+	if gotName != "prod/videos/job 1.mp4" {
+		t.Errorf("name = %q, want prod/videos/job 1.mp4", gotName)
+	}
+	if gotContentType != "video/mp4" {
+		t.Errorf("Content-Type = %q, want video/mp4", gotContentType)
+	}
+	if gotAuth != "Bearer test-token" {
+		t.Errorf("Authorization = %q, want Bearer test-token", gotAuth)
+	}
+	if gotBody != "video data" {
+		t.Errorf("body = %q, want video data", gotBody)
+	}
+	if want := server.URL + "/test-bucket/prod/videos/job%201.mp4"; url != want {
+		t.Errorf("url = %q, want %q", url, want)
+	}
+}
+
+func TestGCSStorage_Upload_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	storage, err := NewGCSStorage(t.TempDir(), GCSConfig{Bucket: "test-bucket", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("NewGCSStorage() error = %v", err)
+	}
+
+	_, err = storage.Upload(context.Background(), "videos/job.mp4", strings.NewReader("data"))
+	if !errors.Is(err, ErrGCSRequest) {
+		t.Fatalf("expected ErrGCSRequest, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "403") {
+		t.Errorf("expected status in error, got %v", err)
+	}
+}
+
+func TestGCSStorage_Download(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/storage/v1/b/test-bucket/o/videos%2Fjob-1.mp4" || r.URL.Query().Get("alt") != "media" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, "stored video")
+	}))
+	defer server.Close()
+
+	storage, err := NewGCSStorage(t.TempDir(), GCSConfig{Bucket: "test-bucket", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("NewGCSStorage() error = %v", err)
+	}
+
+	rc, err := storage.Download(context.Background(), "videos/job-1.mp4")
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if string(data) != "stored video" {
+		t.Errorf("Download() = %q, want %q", data, "stored video")
+	}
+
+	if _, err := storage.Download(context.Background(), "videos/missing.mp4"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("expected ErrObjectNotFound, got %v", err)
+	}
+}
+
+func TestGCSStorage_DeleteObject(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		deleted = append(deleted, r.URL.EscapedPath())
+		if strings.HasSuffix(r.URL.Path, "missing.mp4") {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	storage, err := NewGCSStorage(t.TempDir(), GCSConfig{Bucket: "test-bucket", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("NewGCSStorage() error = %v", err)
+	}
+
+	if err := storage.DeleteObject(context.Background(), "videos/job-1.mp4"); err != nil {
+		t.Fatalf("DeleteObject() error = %v", err)
+	}
+	// Missing objects are ignored
+	if err := storage.DeleteObject(context.Background(), "videos/missing.mp4"); err != nil {
+		t.Fatalf("DeleteObject() of missing object error = %v", err)
+	}
+	if len(deleted) != 2 || deleted[0] != "/storage/v1/b/test-bucket/o/videos%2Fjob-1.mp4" {
+		t.Errorf("unexpected delete requests: %v", deleted)
+	}
+}
+
+func TestGCSStorage_Ping(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/storage/v1/b/test-bucket" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, `{"name":"test-bucket"}`)
+	}))
+	defer server.Close()
+
+	storage, err := NewGCSStorage(t.TempDir(), GCSConfig{Bucket: "test-bucket", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("NewGCSStorage() error = %v", err)
+	}
+	if err := storage.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+
+	storage.bucket = "missing-bucket"
+	if err := storage.Ping(context.Background()); !errors.Is(err, ErrGCSRequest) {
+		t.Errorf("expected ErrGCSRequest for missing bucket, got %v", err)
+	}
+}
+
+func TestMetadataTokenSource_CachesToken(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = io.WriteString(w, `{"access_token":"meta-token","expires_in":3600,"token_type":"Bearer"}`)
+	}))
+	defer server.Close()
+
+	src := &metadataTokenSource{client: server.Client(), url: server.URL}
+	for range 2 {
+		token, err := src.Token(context.Background())
+		if err != nil {
+			t.Fatalf("Token() error = %v", err)
+		}
+		if token != "meta-token" {
+			t.Errorf("token = %q, want meta-token", token)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 metadata request, got %d", calls.Load())
+	}
+}
+
+func TestNewGCSStorage_ClientTimeout(t *testing.T) {
+	storage, err := NewGCSStorage(t.TempDir(), GCSConfig{Bucket: "test-bucket", Endpoint: "http://localhost:4443"})
+	if err != nil {
+		t.Fatalf("NewGCSStorage() error = %v", err)
+	}
+	if storage.client.Timeout != DefaultGCSTimeout {
+		t.Errorf("client timeout = %v, want %v", storage.client.Timeout, DefaultGCSTimeout)
+	}
+
+	storage, err = NewGCSStorage(t.TempDir(), GCSConfig{Bucket: "test-bucket", Endpoint: "http://localhost:4443", Timeout: time.Minute})
+	if err != nil {
+		t.Fatalf("NewGCSStorage() error = %v", err)
+	}
+	if storage.client.Timeout != time.Minute {
+		t.Errorf("client timeout = %v, want %v", storage.client.Timeout, time.Minute)
+	}
+}
+
+// writeServiceAccountKey writes a service account JSON key for key whose
+// tokens are requested from tokenURI, and returns its path.
+func writeServiceAccountKey(t *testing.T, key *rsa.PrivateKey, tokenURI string) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	data, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "uploader@project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    tokenURI,
+	})
+	path := filepath.Join(t.TempDir(), "key.json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return path
+}
+
+func TestGCSStorage_ServiceAccountCredentials(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	var tokenRequests atomic.Int32
+	var gotAuth string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/token" {
+			gotAuth = r.Header.Get("Authorization")
+			_, _ = io.WriteString(w, `{"name":"ok"}`)
+			return
+		}
+		tokenRequests.Add(1)
+		if r.FormValue("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+			http.Error(w, "bad grant type", http.StatusBadRequest)
+			return
+		}
+		parts := strings.Split(r.FormValue("assertion"), ".")
+		if len(parts) != 3 {
+			http.Error(w, "bad assertion", http.StatusBadRequest)
+			return
+		}
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		claimsJSON, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		var claims map[string]any
+		_ = json.Unmarshal(claimsJSON, &claims)
+		if claims["iss"] != "uploader@project.iam.gserviceaccount.com" || claims["aud"] != server.URL+"/token" || claims["scope"] != gcsScope {
+			http.Error(w, "bad claims", http.StatusUnauthorized)
+			return
+		}
+		_, _ = io.WriteString(w, `{"access_token":"sa-token","expires_in":3600,"token_type":"Bearer"}`)
+	}))
+	defer server.Close()
+
+	storage, err := NewGCSStorage(t.TempDir(), GCSConfig{
+		Bucket:          "test-bucket",
+		Endpoint:        server.URL,
+		CredentialsFile: writeServiceAccountKey(t, key, server.URL+"/token"),
+	})
+	if err != nil {
+		t.Fatalf("NewGCSStorage() error = %v", err)
+	}
+
+	if err := storage.CheckCredentials(context.Background()); err != nil {
+		t.Fatalf("CheckCredentials() error = %v", err)
+	}
+	if _, err := storage.Upload(context.Background(), "videos/job-1.mp4", strings.NewReader("video")); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if gotAuth != "Bearer sa-token" {
+		t.Errorf("Authorization = %q, want Bearer sa-token", gotAuth)
+	}
+	if tokenRequests.Load() != 1 {
+		t.Errorf("expected the token to be cached, got %d token requests", tokenRequests.Load())
+	}
+}
+
+func TestNewGCSStorage_InvalidCredentialsFile(t *testing.T) {
+	notServiceAccount := filepath.Join(t.TempDir(), "user.json")
+	_ = os.WriteFile(notServiceAccount, []byte(`{"type":"authorized_user"}`), 0600)
+
+	for name, path := range map[string]string{
+		"missing file":          filepath.Join(t.TempDir(), "missing.json"),
+		"not a service account": notServiceAccount,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewGCSStorage(t.TempDir(), GCSConfig{Bucket: "test-bucket", CredentialsFile: path})
+			if !errors.Is(err, ErrGCSCredentials) {
+				t.Errorf("expected ErrGCSCredentials, got %v", err)
+			}
+		})
+	}
+}
+
+func TestGCSStorage_CheckCredentials(t *testing.T) {
+	// Emulators are not authenticated
+	storage, err := NewGCSStorage(t.TempDir(), GCSConfig{Bucket: "test-bucket", Endpoint: "http://localhost:4443"})
+	if err != nil {
+		t.Fatalf("NewGCSStorage() error = %v", err)
+	}
+	if err := storage.CheckCredentials(context.Background()); err != nil {
+		t.Errorf("CheckCredentials() error = %v", err)
+	}
+
+	// Outside GCP the metadata server cannot be reached
+	storage, err = NewGCSStorage(t.TempDir(), GCSConfig{
+		Bucket: "test-bucket",
+		TokenSource: func(context.Context) (string, error) {
+			return "", errors.New("query metadata server: no such host")
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewGCSStorage() error = %v", err)
+	}
+	if err := storage.CheckCredentials(context.Background()); !errors.Is(err, ErrGCSCredentials) {
+		t.Errorf("expected ErrGCSCredentials, got %v", err)
+	}
+}
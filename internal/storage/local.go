@@ -7,17 +7,21 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sync"
 )
 
-// ErrS3NotConfigured is returned when S3 operations are attempted
-// without proper configuration.
-var ErrS3NotConfigured = errors.New("S3 storage is not configured")
+// ErrRemoteNotConfigured is returned when uploads are attempted
+// without a remote storage backend configured.
+var ErrRemoteNotConfigured = errors.New("remote storage is not configured")
 
 // LocalStorage implements the Storage interface using local disk.
 // It stores temporary files in a configurable directory and does not
-// support S3 operations unless wrapped with S3Storage.
+// support uploads unless wrapped with S3Storage or GCSStorage.
 type LocalStorage struct {
 	tempDir string
+	// cleanupConcurrency is how many files CleanupTemp removes at once.
+	// Values < 2 remove them one at a time.
+	cleanupConcurrency int
 }
 
 // NewLocalStorage creates a new LocalStorage instance.
@@ -87,28 +91,108 @@ func (s *LocalStorage) LoadTemp(ctx context.Context, path string) (io.ReadCloser
 	return f, nil
 }
 
-// CleanupTemp removes the specified temporary files.
-// It continues cleanup even if some files fail to delete,
-// returning the first error encountered.
+// SetCleanupConcurrency sets how many files CleanupTemp removes at once.
+// Values < 1 are treated as 1, which removes them one at a time (default).
+// It must be called before the storage is used.
+func (s *LocalStorage) SetCleanupConcurrency(n int) {
+	s.cleanupConcurrency = max(n, 1)
+}
+
+// CleanupTemp removes the specified temporary files, up to the configured
+// cleanup concurrency at once. It continues cleanup even if some files fail
+// to delete, returning the error of the first failed path in paths order.
 func (s *LocalStorage) CleanupTemp(ctx context.Context, paths []string) error {
-	var firstErr error
-	for _, p := range paths {
+	if s.cleanupConcurrency < 2 || len(paths) < 2 {
+		var firstErr error
+		for _, p := range paths {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("context cancelled: %w", ctx.Err())
+			default:
+			}
+
+			if err := removeTemp(p); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	}
+
+	errs := make([]error, len(paths))
+	sem := make(chan struct{}, s.cleanupConcurrency)
+	var (
+		wg        sync.WaitGroup
+		cancelErr error
+	)
+	for i, p := range paths {
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("context cancelled: %w", ctx.Err())
-		default:
+		case sem <- struct{}{}:
+		}
+		if err := ctx.Err(); err != nil {
+			cancelErr = fmt.Errorf("context cancelled: %w", err)
+			break
 		}
 
-		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
-			if firstErr == nil {
-				firstErr = fmt.Errorf("remove temp file %s: %w", p, err)
-			}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = removeTemp(p)
+		}()
+	}
+	wg.Wait()
+
+	if cancelErr != nil {
+		return cancelErr
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
 		}
 	}
-	return firstErr
+	return nil
+}
+
+// removeTemp removes the temporary file at p. A file that no longer exists
+// is not an error.
+func removeTemp(p string) error {
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove temp file %s: %w", p, err)
+	}
+	return nil
+}
+
+// CheckWritable verifies that a file can be created in the temporary directory.
+func (s *LocalStorage) CheckWritable(_ context.Context) error {
+	f, err := os.CreateTemp(s.tempDir, ".writecheck-*")
+	if err != nil {
+		return fmt.Errorf("temp directory not writable: %w", err)
+	}
+	name := f.Name()
+	_ = f.Close()
+	if err := os.Remove(name); err != nil {
+		return fmt.Errorf("remove write check file: %w", err)
+	}
+	return nil
+}
+
+// Upload is not supported by LocalStorage and returns ErrRemoteNotConfigured.
+func (s *LocalStorage) Upload(_ context.Context, _ string, _ io.Reader) (string, error) {
+	return "", ErrRemoteNotConfigured
+}
+
+// ObjectURL is not supported by LocalStorage and returns ErrRemoteNotConfigured.
+func (s *LocalStorage) ObjectURL(_ context.Context, _ string) (string, error) {
+	return "", ErrRemoteNotConfigured
+}
+
+// Download is not supported by LocalStorage and returns ErrRemoteNotConfigured.
+func (s *LocalStorage) Download(_ context.Context, _ string) (io.ReadCloser, error) {
+	return nil, ErrRemoteNotConfigured
 }
 
-// UploadToS3 is not supported by LocalStorage and returns ErrS3NotConfigured.
-func (s *LocalStorage) UploadToS3(_ context.Context, _ string, _ io.Reader) (string, error) {
-	return "", ErrS3NotConfigured
+// DeleteObject is not supported by LocalStorage and returns ErrRemoteNotConfigured.
+func (s *LocalStorage) DeleteObject(_ context.Context, _ string) error {
+	return ErrRemoteNotConfigured
 }
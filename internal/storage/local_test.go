@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -173,16 +174,125 @@ func TestLocalStorage_CleanupTemp(t *testing.T) {
 	})
 }
 
-func TestLocalStorage_UploadToS3(t *testing.T) {
+func TestLocalStorage_CleanupTempConcurrent(t *testing.T) {
 	storage := setupTestStorage(t)
+	storage.SetCleanupConcurrency(4)
 	ctx := context.Background()
 
-	_, err := storage.UploadToS3(ctx, "key", bytes.NewReader([]byte("data")))
-	if err != ErrS3NotConfigured {
-		t.Errorf("expected ErrS3NotConfigured, got %v", err)
+	saveFiles := func(t *testing.T, n int) []string {
+		t.Helper()
+		paths := make([]string, n)
+		for i := range paths {
+			path, err := storage.SaveTemp(ctx, "cleanup", bytes.NewReader([]byte("data")))
+			if err != nil {
+				t.Fatalf("SaveTemp() error = %v", err)
+			}
+			paths[i] = path
+		}
+		return paths
+	}
+
+	t.Run("removes all files", func(t *testing.T) {
+		paths := saveFiles(t, 50)
+
+		if err := storage.CleanupTemp(ctx, paths); err != nil {
+			t.Fatalf("CleanupTemp() error = %v", err)
+		}
+		for _, p := range paths {
+			if _, err := os.Stat(p); !os.IsNotExist(err) {
+				t.Errorf("file %s still exists", p)
+			}
+		}
+	})
+
+	t.Run("failure does not stop the others", func(t *testing.T) {
+		paths := saveFiles(t, 50)
+		// A non-empty directory cannot be removed
+		blocked := filepath.Join(storage.TempDir(), "blocked")
+		if err := os.MkdirAll(filepath.Join(blocked, "sub"), 0750); err != nil {
+			t.Fatal(err)
+		}
+		paths = slices.Insert(paths, 25, blocked)
+
+		err := storage.CleanupTemp(ctx, paths)
+		if err == nil || !strings.Contains(err.Error(), blocked) {
+			t.Fatalf("expected an error naming %s, got %v", blocked, err)
+		}
+		for _, p := range paths {
+			if p == blocked {
+				continue
+			}
+			if _, err := os.Stat(p); !os.IsNotExist(err) {
+				t.Errorf("file %s still exists", p)
+			}
+		}
+	})
+
+	t.Run("respects context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := storage.CleanupTemp(ctx, []string{"/some/path", "/other/path"})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+}
+
+func TestLocalStorage_Upload(t *testing.T) {
+	storage := setupTestStorage(t)
+	ctx := context.Background()
+
+	_, err := storage.Upload(ctx, "key", bytes.NewReader([]byte("data")))
+	if err != ErrRemoteNotConfigured {
+		t.Errorf("expected ErrRemoteNotConfigured, got %v", err)
 	}
 }
 
+func TestLocalStorage_Download(t *testing.T) {
+	storage := setupTestStorage(t)
+
+	_, err := storage.Download(context.Background(), "key")
+	if !errors.Is(err, ErrRemoteNotConfigured) {
+		t.Errorf("expected ErrRemoteNotConfigured, got %v", err)
+	}
+}
+
+func TestLocalStorage_DeleteObject(t *testing.T) {
+	storage := setupTestStorage(t)
+
+	err := storage.DeleteObject(context.Background(), "key")
+	if !errors.Is(err, ErrRemoteNotConfigured) {
+		t.Errorf("expected ErrRemoteNotConfigured, got %v", err)
+	}
+}
+
+func TestLocalStorage_CheckWritable(t *testing.T) {
+	storage := setupTestStorage(t)
+	ctx := context.Background()
+
+	if err := storage.CheckWritable(ctx); err != nil {
+		t.Fatalf("CheckWritable() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(storage.TempDir())
+	if err != nil {
+		t.Fatalf("failed to read temp dir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected write check file to be removed, found %d entries", len(entries))
+	}
+
+	t.Run("missing directory", func(t *testing.T) {
+		if err := os.RemoveAll(storage.TempDir()); err != nil {
+			t.Fatalf("failed to remove temp dir: %v", err)
+		}
+		if err := storage.CheckWritable(ctx); err == nil {
+			t.Error("expected error for missing temp directory")
+		}
+	})
+}
+
 func setupTestStorage(t *testing.T) *LocalStorage {
 	t.Helper()
 	tempDir := filepath.Join(os.TempDir(), "infinitetalk_test_"+randomSuffix())
@@ -95,6 +95,131 @@ func (_c *MockStorage_CleanupTemp_Call) RunAndReturn(run func(ctx context.Contex
 	return _c
 }
 
+// DeleteObject provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteObject(ctx context.Context, key string) error {
+	ret := _mock.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteObject")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_DeleteObject_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteObject'
+type MockStorage_DeleteObject_Call struct {
+	*mock.Call
+}
+
+// DeleteObject is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *MockStorage_Expecter) DeleteObject(ctx interface{}, key interface{}) *MockStorage_DeleteObject_Call {
+	return &MockStorage_DeleteObject_Call{Call: _e.mock.On("DeleteObject", ctx, key)}
+}
+
+func (_c *MockStorage_DeleteObject_Call) Run(run func(ctx context.Context, key string)) *MockStorage_DeleteObject_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteObject_Call) Return(err error) *MockStorage_DeleteObject_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_DeleteObject_Call) RunAndReturn(run func(ctx context.Context, key string) error) *MockStorage_DeleteObject_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Download provides a mock function for the type MockStorage
+func (_mock *MockStorage) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	ret := _mock.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Download")
+	}
+
+	var r0 io.ReadCloser
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (io.ReadCloser, error)); ok {
+		return returnFunc(ctx, key)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) io.ReadCloser); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_Download_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Download'
+type MockStorage_Download_Call struct {
+	*mock.Call
+}
+
+// Download is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *MockStorage_Expecter) Download(ctx interface{}, key interface{}) *MockStorage_Download_Call {
+	return &MockStorage_Download_Call{Call: _e.mock.On("Download", ctx, key)}
+}
+
+func (_c *MockStorage_Download_Call) Run(run func(ctx context.Context, key string)) *MockStorage_Download_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_Download_Call) Return(readCloser io.ReadCloser, err error) *MockStorage_Download_Call {
+	_c.Call.Return(readCloser, err)
+	return _c
+}
+
+func (_c *MockStorage_Download_Call) RunAndReturn(run func(ctx context.Context, key string) (io.ReadCloser, error)) *MockStorage_Download_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // LoadTemp provides a mock function for the type MockStorage
 func (_mock *MockStorage) LoadTemp(ctx context.Context, path string) (io.ReadCloser, error) {
 	ret := _mock.Called(ctx, path)
@@ -163,6 +288,72 @@ func (_c *MockStorage_LoadTemp_Call) RunAndReturn(run func(ctx context.Context,
 	return _c
 }
 
+// ObjectURL provides a mock function for the type MockStorage
+func (_mock *MockStorage) ObjectURL(ctx context.Context, key string) (string, error) {
+	ret := _mock.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ObjectURL")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return returnFunc(ctx, key)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_ObjectURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObjectURL'
+type MockStorage_ObjectURL_Call struct {
+	*mock.Call
+}
+
+// ObjectURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *MockStorage_Expecter) ObjectURL(ctx interface{}, key interface{}) *MockStorage_ObjectURL_Call {
+	return &MockStorage_ObjectURL_Call{Call: _e.mock.On("ObjectURL", ctx, key)}
+}
+
+func (_c *MockStorage_ObjectURL_Call) Run(run func(ctx context.Context, key string)) *MockStorage_ObjectURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_ObjectURL_Call) Return(url string, err error) *MockStorage_ObjectURL_Call {
+	_c.Call.Return(url, err)
+	return _c
+}
+
+func (_c *MockStorage_ObjectURL_Call) RunAndReturn(run func(ctx context.Context, key string) (string, error)) *MockStorage_ObjectURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SaveTemp provides a mock function for the type MockStorage
 func (_mock *MockStorage) SaveTemp(ctx context.Context, name string, data io.Reader) (string, error) {
 	ret := _mock.Called(ctx, name, data)
@@ -235,12 +426,12 @@ func (_c *MockStorage_SaveTemp_Call) RunAndReturn(run func(ctx context.Context,
 	return _c
 }
 
-// UploadToS3 provides a mock function for the type MockStorage
-func (_mock *MockStorage) UploadToS3(ctx context.Context, key string, data io.Reader) (string, error) {
+// Upload provides a mock function for the type MockStorage
+func (_mock *MockStorage) Upload(ctx context.Context, key string, data io.Reader) (string, error) {
 	ret := _mock.Called(ctx, key, data)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UploadToS3")
+		panic("no return value specified for Upload")
 	}
 
 	var r0 string
@@ -261,20 +452,20 @@ func (_mock *MockStorage) UploadToS3(ctx context.Context, key string, data io.Re
 	return r0, r1
 }
 
-// MockStorage_UploadToS3_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UploadToS3'
-type MockStorage_UploadToS3_Call struct {
+// MockStorage_Upload_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Upload'
+type MockStorage_Upload_Call struct {
 	*mock.Call
 }
 
-// UploadToS3 is a helper method to define mock.On call
+// Upload is a helper method to define mock.On call
 //   - ctx context.Context
 //   - key string
 //   - data io.Reader
-func (_e *MockStorage_Expecter) UploadToS3(ctx interface{}, key interface{}, data interface{}) *MockStorage_UploadToS3_Call {
-	return &MockStorage_UploadToS3_Call{Call: _e.mock.On("UploadToS3", ctx, key, data)}
+func (_e *MockStorage_Expecter) Upload(ctx interface{}, key interface{}, data interface{}) *MockStorage_Upload_Call {
+	return &MockStorage_Upload_Call{Call: _e.mock.On("Upload", ctx, key, data)}
 }
 
-func (_c *MockStorage_UploadToS3_Call) Run(run func(ctx context.Context, key string, data io.Reader)) *MockStorage_UploadToS3_Call {
+func (_c *MockStorage_Upload_Call) Run(run func(ctx context.Context, key string, data io.Reader)) *MockStorage_Upload_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -297,12 +488,12 @@ func (_c *MockStorage_UploadToS3_Call) Run(run func(ctx context.Context, key str
 	return _c
 }
 
-func (_c *MockStorage_UploadToS3_Call) Return(url string, err error) *MockStorage_UploadToS3_Call {
+func (_c *MockStorage_Upload_Call) Return(url string, err error) *MockStorage_Upload_Call {
 	_c.Call.Return(url, err)
 	return _c
 }
 
-func (_c *MockStorage_UploadToS3_Call) RunAndReturn(run func(ctx context.Context, key string, data io.Reader) (string, error)) *MockStorage_UploadToS3_Call {
+func (_c *MockStorage_Upload_Call) RunAndReturn(run func(ctx context.Context, key string, data io.Reader) (string, error)) *MockStorage_Upload_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1,16 +1,28 @@
 package storage
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
+// ErrEndpointNotAllowed is returned when a custom S3 endpoint is not on the configured allowlist.
+var ErrEndpointNotAllowed = errors.New("S3 endpoint is not in the allowlist")
+
 // S3Config holds the configuration for S3 storage.
 type S3Config struct {
 	Bucket          string
@@ -18,21 +30,66 @@ type S3Config struct {
 	Endpoint        string // Optional: for custom S3-compatible endpoints
 	AccessKeyID     string // Optional: AWS access key ID
 	SecretAccessKey string // Optional: AWS secret access key
+	// VirtualHostedStyle addresses buckets on Endpoint as bucket.endpoint
+	// instead of the default path-style endpoint/bucket. Ignored without Endpoint.
+	VirtualHostedStyle bool
+	// AllowedEndpointHosts restricts Endpoint to these hosts ("host" or "host:port").
+	// Empty means any endpoint is accepted.
+	AllowedEndpointHosts []string
+	// KeyPrefix is prepended to every object key, e.g. "prod/infinitetalk".
+	// Leading and trailing slashes are ignored.
+	KeyPrefix string
+	// PresignTTL makes Upload and ObjectURL return presigned GET URLs valid
+	// for this long instead of public URLs, for private buckets. Zero returns
+	// public URLs.
+	PresignTTL time.Duration
+	// MultipartThreshold is the size from which uploads use multipart upload.
+	// Zero uses DefaultMultipartThreshold.
+	MultipartThreshold int64
+	// PartSize is the size of each multipart part. S3 requires at least 5MB
+	// for all parts but the last. Zero uses DefaultPartSize.
+	PartSize int64
+	// UploadConcurrency is how many parts are uploaded in parallel.
+	// Zero uses DefaultUploadConcurrency.
+	UploadConcurrency int
 }
 
 // S3Storage wraps LocalStorage and adds S3 upload capability.
 // It uses LocalStorage for temporary file operations and S3 for final storage.
 type S3Storage struct {
 	*LocalStorage
-	client *s3.Client
-	bucket string
-	region string
+	client     *s3.Client
+	presigner  *s3.PresignClient
+	bucket     string
+	region     string
+	keyPrefix  string
+	presignTTL time.Duration
+	// endpoint is the custom endpoint public URLs are built from; nil for AWS.
+	endpoint      *url.URL
+	virtualHosted bool
+
+	multipartThreshold int64
+	partSize           int64
+	uploadConcurrency  int
 }
 
 // NewS3Storage creates a new S3Storage instance.
 // The tempDir parameter specifies where temporary files are stored.
 // The cfg parameter contains S3 configuration.
 func NewS3Storage(tempDir string, cfg S3Config) (*S3Storage, error) {
+	if err := checkEndpointAllowed(cfg.Endpoint, cfg.AllowedEndpointHosts); err != nil {
+		return nil, err
+	}
+
+	var endpoint *url.URL
+	if cfg.Endpoint != "" {
+		u, err := url.Parse(cfg.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("parse S3 endpoint: %w", err)
+		}
+		endpoint = u
+	}
+
 	local, err := NewLocalStorage(tempDir)
 	if err != nil {
 		return nil, err
@@ -53,35 +110,202 @@ func NewS3Storage(tempDir string, cfg S3Config) (*S3Storage, error) {
 		return nil, fmt.Errorf("load AWS config: %w", err)
 	}
 
-	var clientOpts []func(*s3.Options)
-	if cfg.Endpoint != "" {
-		clientOpts = append(clientOpts, func(o *s3.Options) {
-			o.BaseEndpoint = aws.String(cfg.Endpoint)
-			o.UsePathStyle = true
-		})
-	}
-
-	client := s3.NewFromConfig(awsCfg, clientOpts...)
+	client := s3.NewFromConfig(awsCfg, clientOptions(cfg)...)
 
 	return &S3Storage{
 		LocalStorage: local,
 		client:       client,
+		presigner:    s3.NewPresignClient(client),
 		bucket:       cfg.Bucket,
 		region:       cfg.Region,
+		keyPrefix:    strings.Trim(cfg.KeyPrefix, "/"),
+		presignTTL:   cfg.PresignTTL,
+
+		endpoint:      endpoint,
+		virtualHosted: cfg.VirtualHostedStyle,
+
+		multipartThreshold: cmp.Or(cfg.MultipartThreshold, DefaultMultipartThreshold),
+		partSize:           cmp.Or(cfg.PartSize, DefaultPartSize),
+		uploadConcurrency:  cmp.Or(cfg.UploadConcurrency, DefaultUploadConcurrency),
 	}, nil
 }
 
-// UploadToS3 uploads data to S3 and returns the public URL.
-func (s *S3Storage) UploadToS3(ctx context.Context, key string, data io.Reader) (string, error) {
-	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+// clientOptions returns the S3 client options pointing the client at the
+// custom endpoint of cfg, if any.
+func clientOptions(cfg S3Config) []func(*s3.Options) {
+	if cfg.Endpoint == "" {
+		return nil
+	}
+	return []func(*s3.Options){func(o *s3.Options) {
+		o.BaseEndpoint = aws.String(cfg.Endpoint)
+		o.UsePathStyle = !cfg.VirtualHostedStyle
+	}}
+}
+
+// checkEndpointAllowed verifies endpoint against the allowlist. An entry without
+// a port matches the host on any port; hosts are compared case-insensitively.
+func checkEndpointAllowed(endpoint string, allowed []string) error {
+	if endpoint == "" || len(allowed) == 0 {
+		return nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("%w: %q is not a valid URL", ErrEndpointNotAllowed, endpoint)
+	}
+
+	for _, entry := range allowed {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == strings.ToLower(u.Host) || entry == strings.ToLower(u.Hostname()) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrEndpointNotAllowed, u.Host)
+}
+
+// Upload uploads data to S3 under the configured key prefix and returns
+// its URL: a presigned GET URL when PresignTTL is set, the public URL otherwise.
+// Data smaller than the multipart threshold is sent with a single PutObject;
+// larger data is sent as a multipart upload.
+// The content type is derived from the key's extension so that browsers can
+// play or display the object inline.
+func (s *S3Storage) Upload(ctx context.Context, key string, data io.Reader) (string, error) {
+	objectKey := s.objectKey(key)
+
+	// Read up to the threshold to find out whether the data is small
+	head, err := io.ReadAll(io.LimitReader(data, s.multipartThreshold))
+	if err != nil {
+		return "", fmt.Errorf("upload to S3: read data: %w", err)
+	}
+
+	if int64(len(head)) < s.multipartThreshold {
+		_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:      aws.String(s.bucket),
+			Key:         aws.String(objectKey),
+			Body:        bytes.NewReader(head),
+			ContentType: aws.String(contentTypeForKey(objectKey)),
+		})
+	} else {
+		err = s.uploadMultipart(ctx, objectKey, io.MultiReader(bytes.NewReader(head), data))
+	}
+	if err != nil {
+		return "", fmt.Errorf("upload to S3: %w", err)
+	}
+
+	return s.ObjectURL(ctx, key)
+}
+
+// ObjectURL returns the URL of the object stored under key (with the
+// configured key prefix): a presigned GET URL valid for PresignTTL from now
+// when PresignTTL is set, the public URL otherwise.
+func (s *S3Storage) ObjectURL(ctx context.Context, key string) (string, error) {
+	if s.presignTTL > 0 {
+		return s.PresignGetURL(ctx, key, s.presignTTL)
+	}
+	return s.publicURL(s.objectKey(key)), nil
+}
+
+// publicURL returns the unsigned URL of a full object key, addressed the same
+// way as the client: on the custom endpoint path-style (endpoint/bucket/key)
+// or virtual-hosted style (bucket.host/key), or on AWS otherwise.
+func (s *S3Storage) publicURL(objectKey string) string {
+	escaped := escapeObjectPath(objectKey)
+	switch {
+	case s.endpoint == nil:
+		return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.region, escaped)
+	case s.virtualHosted:
+		return fmt.Sprintf("%s://%s.%s/%s", s.endpoint.Scheme, s.bucket, s.endpoint.Host, escaped)
+	default:
+		return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(s.endpoint.String(), "/"), s.bucket, escaped)
+	}
+}
+
+// Download opens the object stored under key (with the configured key prefix).
+func (s *S3Storage) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(s.bucket),
-		Key:    aws.String(key),
-		Body:   data,
+		Key:    aws.String(s.objectKey(key)),
 	})
 	if err != nil {
-		return "", fmt.Errorf("upload to S3: %w", err)
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, fmt.Errorf("download from S3: %w: %s", ErrObjectNotFound, key)
+		}
+		return nil, fmt.Errorf("download from S3: %w", err)
+	}
+	return out.Body, nil
+}
+
+// DeleteObject removes the object stored under key (with the configured key prefix).
+// S3 reports success for keys that do not exist, so deletes are idempotent.
+func (s *S3Storage) DeleteObject(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.objectKey(key)),
+	})
+	if err != nil {
+		return fmt.Errorf("delete from S3: %w", err)
 	}
+	return nil
+}
+
+// PresignGetURL returns a URL that grants GET access to the object at key
+// (under the configured key prefix) for ttl, without making the bucket public.
+func (s *S3Storage) PresignGetURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return s.presignObject(ctx, s.objectKey(key), ttl)
+}
 
-	url := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.region, key)
-	return url, nil
+// presignObject presigns a GET request for a full object key.
+func (s *S3Storage) presignObject(ctx context.Context, objectKey string, ttl time.Duration) (string, error) {
+	req, err := s.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(objectKey),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("presign S3 URL: %w", err)
+	}
+	return req.URL, nil
+}
+
+// objectKey prepends the configured key prefix to key.
+func (s *S3Storage) objectKey(key string) string {
+	if s.keyPrefix == "" {
+		return key
+	}
+	return s.keyPrefix + "/" + strings.TrimPrefix(key, "/")
+}
+
+// contentTypes maps the extensions of uploaded artifacts to their MIME types.
+// mime.TypeByExtension is only a fallback since its built-in table lacks video types.
+var contentTypes = map[string]string{
+	".mp4":  "video/mp4",
+	".mov":  "video/quicktime",
+	".webm": "video/webm",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".wav":  "audio/wav",
+}
+
+// contentTypeForKey returns the MIME type for an object key based on its extension.
+func contentTypeForKey(key string) string {
+	ext := strings.ToLower(path.Ext(key))
+	if ct, ok := contentTypes[ext]; ok {
+		return ct
+	}
+	if ct := mime.TypeByExtension(ext); ct != "" {
+		return ct
+	}
+	return "application/octet-stream"
+}
+
+// Ping verifies that the configured bucket exists and is accessible.
+func (s *S3Storage) Ping(ctx context.Context) error {
+	_, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(s.bucket),
+	})
+	if err != nil {
+		return fmt.Errorf("head bucket %s: %w", s.bucket, err)
+	}
+	return nil
 }
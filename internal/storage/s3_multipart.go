@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// Multipart upload defaults.
+const (
+	// DefaultMultipartThreshold is the upload size from which multipart upload is used.
+	DefaultMultipartThreshold int64 = 32 << 20
+	// DefaultPartSize is the size of each multipart part.
+	DefaultPartSize int64 = 8 << 20
+	// DefaultUploadConcurrency is how many parts are uploaded in parallel.
+	DefaultUploadConcurrency = 4
+)
+
+// abortTimeout bounds the cleanup request of a failed multipart upload.
+const abortTimeout = 30 * time.Second
+
+// uploadMultipart streams data to objectKey as a multipart upload, sending up
+// to uploadConcurrency parts at once. At most uploadConcurrency+1 parts are
+// held in memory. The upload is aborted if any part fails.
+func (s *S3Storage) uploadMultipart(ctx context.Context, objectKey string, data io.Reader) error {
+	created, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:            aws.String(s.bucket),
+		Key:               aws.String(objectKey),
+		ContentType:       aws.String(contentTypeForKey(objectKey)),
+		ChecksumAlgorithm: types.ChecksumAlgorithmCrc32,
+	})
+	if err != nil {
+		return fmt.Errorf("create multipart upload: %w", err)
+	}
+	uploadID := created.UploadId
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		parts    []types.CompletedPart
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	sem := make(chan struct{}, s.uploadConcurrency)
+	for partNumber := int32(1); ctx.Err() == nil; partNumber++ {
+		buf := make([]byte, s.partSize)
+		n, readErr := io.ReadFull(data, buf)
+		if n > 0 {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(partNumber int32, body []byte) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				out, err := s.client.UploadPart(ctx, &s3.UploadPartInput{
+					Bucket:            aws.String(s.bucket),
+					Key:               aws.String(objectKey),
+					UploadId:          uploadID,
+					PartNumber:        aws.Int32(partNumber),
+					Body:              bytes.NewReader(body),
+					ChecksumAlgorithm: types.ChecksumAlgorithmCrc32,
+				})
+				if err != nil {
+					fail(fmt.Errorf("upload part %d: %w", partNumber, err))
+					return
+				}
+
+				mu.Lock()
+				parts = append(parts, types.CompletedPart{
+					PartNumber:    aws.Int32(partNumber),
+					ETag:          out.ETag,
+					ChecksumCRC32: out.ChecksumCRC32,
+				})
+				mu.Unlock()
+			}(partNumber, buf[:n])
+		}
+		if errors.Is(readErr, io.EOF) || errors.Is(readErr, io.ErrUnexpectedEOF) {
+			break
+		}
+		if readErr != nil {
+			fail(fmt.Errorf("read part %d: %w", partNumber, readErr))
+			break
+		}
+	}
+	wg.Wait()
+
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+	if firstErr != nil {
+		s.abortMultipart(ctx, objectKey, uploadID)
+		return firstErr
+	}
+
+	slices.SortFunc(parts, func(a, b types.CompletedPart) int {
+		return cmp.Compare(*a.PartNumber, *b.PartNumber)
+	})
+	_, err = s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(objectKey),
+		UploadId:        uploadID,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		s.abortMultipart(ctx, objectKey, uploadID)
+		return fmt.Errorf("complete multipart upload: %w", err)
+	}
+	return nil
+}
+
+// abortMultipart discards the parts of a failed upload so they are not billed.
+// It ignores cancellation of ctx since the upload context may already be cancelled.
+func (s *S3Storage) abortMultipart(ctx context.Context, objectKey string, uploadID *string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), abortTimeout)
+	defer cancel()
+	_, _ = s.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(objectKey),
+		UploadId: uploadID,
+	})
+}
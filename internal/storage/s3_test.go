@@ -3,13 +3,21 @@ package storage
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
 func TestNewS3Storage(t *testing.T) {
@@ -37,6 +45,82 @@ func TestNewS3Storage(t *testing.T) {
 	}
 }
 
+func TestNewS3Storage_EndpointAllowlist(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		allowed  []string
+		wantErr  bool
+	}{
+		{"no allowlist accepts any endpoint", "http://evil.example.com:9000", nil, false},
+		{"no endpoint with allowlist", "", []string{"minio.internal"}, false},
+		{"allowed host on any port", "http://minio.internal:9000", []string{"minio.internal"}, false},
+		{"allowed host and port", "http://localhost:4566", []string{"localhost:4566"}, false},
+		{"host match is case-insensitive", "https://MinIO.Internal", []string{"minio.internal"}, false},
+		{"disallowed host", "http://evil.example.com:9000", []string{"minio.internal"}, true},
+		{"disallowed port", "http://localhost:9000", []string{"localhost:4566"}, true},
+		{"subdomain is not a match", "http://minio.internal.evil.com", []string{"minio.internal"}, true},
+		{"invalid endpoint", "not a url", []string{"minio.internal"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := S3Config{
+				Bucket:               "test-bucket",
+				Region:               "us-east-1",
+				Endpoint:             tt.endpoint,
+				AccessKeyID:          "test-access-key",
+				SecretAccessKey:      "test-secret-key",
+				AllowedEndpointHosts: tt.allowed,
+			}
+
+			_, err := NewS3Storage(t.TempDir(), cfg)
+			if tt.wantErr {
+				if !errors.Is(err, ErrEndpointNotAllowed) {
+					t.Errorf("NewS3Storage() error = %v, want ErrEndpointNotAllowed", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("NewS3Storage() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           S3Config
+		wantEndpoint  string
+		wantPathStyle bool
+	}{
+		{"AWS", S3Config{}, "", false},
+		{"custom endpoint defaults to path-style", S3Config{Endpoint: "http://minio:9000"}, "http://minio:9000", true},
+		{"custom endpoint with virtual-hosted style", S3Config{Endpoint: "https://s3.wasabisys.com", VirtualHostedStyle: true}, "https://s3.wasabisys.com", false},
+		{"virtual-hosted style ignored without endpoint", S3Config{VirtualHostedStyle: true}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var o s3.Options
+			for _, opt := range clientOptions(tt.cfg) {
+				opt(&o)
+			}
+			var endpoint string
+			if o.BaseEndpoint != nil {
+				endpoint = *o.BaseEndpoint
+			}
+			if endpoint != tt.wantEndpoint {
+				t.Errorf("BaseEndpoint = %q, want %q", endpoint, tt.wantEndpoint)
+			}
+			if o.UsePathStyle != tt.wantPathStyle {
+				t.Errorf("UsePathStyle = %v, want %v", o.UsePathStyle, tt.wantPathStyle)
+			}
+		})
+	}
+}
+
 func TestS3Storage_InheritsLocalStorage(t *testing.T) {
 	tempDir := filepath.Join(os.TempDir(), "infinitetalk_s3_test_"+randomSuffix())
 	defer func() { _ = os.RemoveAll(tempDir) }()
@@ -85,7 +169,7 @@ func TestS3Storage_InheritsLocalStorage(t *testing.T) {
 	}
 }
 
-func TestS3Storage_UploadToS3_MockServer(t *testing.T) {
+func TestS3Storage_Upload_MockServer(t *testing.T) {
 	// Create a mock S3 server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPut {
@@ -125,13 +209,474 @@ func TestS3Storage_UploadToS3_MockServer(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	url, err := storage.UploadToS3(ctx, "test-key", bytes.NewReader([]byte("test content")))
+	url, err := storage.Upload(ctx, "test-key", bytes.NewReader([]byte("test content")))
 	if err != nil {
-		t.Fatalf("UploadToS3() error = %v", err)
+		t.Fatalf("Upload() error = %v", err)
 	}
 
-	expectedURL := "https://test-bucket.s3.us-east-1.amazonaws.com/test-key"
+	expectedURL := server.URL + "/test-bucket/test-key"
 	if url != expectedURL {
 		t.Errorf("url = %v, want %v", url, expectedURL)
 	}
 }
+
+func TestS3Storage_Upload_KeyPrefixAndContentType(t *testing.T) {
+	tests := []struct {
+		name            string
+		prefix          string
+		key             string
+		wantPath        string
+		wantContentType string
+	}{
+		{
+			name:            "video with prefix",
+			prefix:          "prod/infinitetalk",
+			key:             "videos/job-1.mp4",
+			wantPath:        "/test-bucket/prod/infinitetalk/videos/job-1.mp4",
+			wantContentType: "video/mp4",
+		},
+		{
+			name:            "surrounding slashes in prefix are ignored",
+			prefix:          "/prod/",
+			key:             "thumbnails/job-1.jpg",
+			wantPath:        "/test-bucket/prod/thumbnails/job-1.jpg",
+			wantContentType: "image/jpeg",
+		},
+		{
+			name:            "no prefix",
+			key:             "videos/job-1.mp4",
+			wantPath:        "/test-bucket/videos/job-1.mp4",
+			wantContentType: "video/mp4",
+		},
+		{
+			name:            "webm video",
+			key:             "videos/job-1.webm",
+			wantPath:        "/test-bucket/videos/job-1.webm",
+			wantContentType: "video/webm",
+		},
+		{
+			name:            "unknown extension",
+			key:             "blobs/job-1",
+			wantPath:        "/test-bucket/blobs/job-1",
+			wantContentType: "application/octet-stream",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath, gotContentType string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				gotContentType = r.Header.Get("Content-Type")
+				_, _ = io.Copy(io.Discard, r.Body)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			storage, err := NewS3Storage(t.TempDir(), S3Config{
+				Bucket:          "test-bucket",
+				Region:          "us-east-1",
+				Endpoint:        server.URL,
+				AccessKeyID:     "test-access-key",
+				SecretAccessKey: "test-secret-key",
+				KeyPrefix:       tt.prefix,
+			})
+			if err != nil {
+				t.Fatalf("NewS3Storage() error = %v", err)
+			}
+
+			url, err := storage.Upload(context.Background(), tt.key, bytes.NewReader([]byte("content")))
+			if err != nil {
+				t.Fatalf("Upload() error = %v", err)
+			}
+
+			if gotPath != tt.wantPath {
+				t.Errorf("PUT path = %v, want %v", gotPath, tt.wantPath)
+			}
+			if gotContentType != tt.wantContentType {
+				t.Errorf("Content-Type = %v, want %v", gotContentType, tt.wantContentType)
+			}
+			wantURL := server.URL + tt.wantPath
+			if url != wantURL {
+				t.Errorf("url = %v, want %v", url, wantURL)
+			}
+		})
+	}
+}
+
+func TestS3Storage_ObjectURL_Public(t *testing.T) {
+	tests := []struct {
+		name          string
+		endpoint      string
+		virtualHosted bool
+		want          string
+	}{
+		{
+			name: "AWS",
+			want: "https://test-bucket.s3.eu-west-1.amazonaws.com/videos/job%201.mp4",
+		},
+		{
+			name:     "custom endpoint, path style",
+			endpoint: "http://minio:9000/",
+			want:     "http://minio:9000/test-bucket/videos/job%201.mp4",
+		},
+		{
+			name:          "custom endpoint, virtual-hosted style",
+			endpoint:      "https://account.r2.cloudflarestorage.com",
+			virtualHosted: true,
+			want:          "https://test-bucket.account.r2.cloudflarestorage.com/videos/job%201.mp4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage, err := NewS3Storage(t.TempDir(), S3Config{
+				Bucket:             "test-bucket",
+				Region:             "eu-west-1",
+				Endpoint:           tt.endpoint,
+				VirtualHostedStyle: tt.virtualHosted,
+				AccessKeyID:        "test-access-key",
+				SecretAccessKey:    "test-secret-key",
+			})
+			if err != nil {
+				t.Fatalf("NewS3Storage() error = %v", err)
+			}
+
+			got, err := storage.ObjectURL(context.Background(), "videos/job 1.mp4")
+			if err != nil {
+				t.Fatalf("ObjectURL() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ObjectURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestS3Storage_PresignGetURL(t *testing.T) {
+	storage, err := NewS3Storage(t.TempDir(), S3Config{
+		Bucket:          "test-bucket",
+		Region:          "us-east-1",
+		Endpoint:        "http://localhost:4566",
+		AccessKeyID:     "test-access-key",
+		SecretAccessKey: "test-secret-key",
+		KeyPrefix:       "prod",
+	})
+	if err != nil {
+		t.Fatalf("NewS3Storage() error = %v", err)
+	}
+
+	presigned, err := storage.PresignGetURL(context.Background(), "videos/job-1.mp4", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("PresignGetURL() error = %v", err)
+	}
+
+	u, err := url.Parse(presigned)
+	if err != nil {
+		t.Fatalf("invalid presigned URL %q: %v", presigned, err)
+	}
+	if u.Path != "/test-bucket/prod/videos/job-1.mp4" {
+		t.Errorf("path = %v, want /test-bucket/prod/videos/job-1.mp4", u.Path)
+	}
+	q := u.Query()
+	for _, param := range []string{"X-Amz-Algorithm", "X-Amz-Credential", "X-Amz-Date", "X-Amz-SignedHeaders", "X-Amz-Signature"} {
+		if q.Get(param) == "" {
+			t.Errorf("presigned URL is missing %s: %s", param, presigned)
+		}
+	}
+	if got := q.Get("X-Amz-Expires"); got != "900" {
+		t.Errorf("X-Amz-Expires = %v, want 900", got)
+	}
+}
+
+func TestS3Storage_Upload_Presigned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	storage, err := NewS3Storage(t.TempDir(), S3Config{
+		Bucket:          "test-bucket",
+		Region:          "us-east-1",
+		Endpoint:        server.URL,
+		AccessKeyID:     "test-access-key",
+		SecretAccessKey: "test-secret-key",
+		PresignTTL:      time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Storage() error = %v", err)
+	}
+
+	got, err := storage.Upload(context.Background(), "videos/job-1.mp4", bytes.NewReader([]byte("video")))
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	if !strings.HasPrefix(got, server.URL+"/test-bucket/videos/job-1.mp4?") {
+		t.Errorf("expected a presigned URL for the uploaded object, got %s", got)
+	}
+	if !strings.Contains(got, "X-Amz-Signature=") || !strings.Contains(got, "X-Amz-Expires=3600") {
+		t.Errorf("expected signature and expiry query parameters, got %s", got)
+	}
+}
+
+// mockMultipartS3 is a minimal S3 server handling PutObject and the multipart
+// upload API (initiate, upload part, complete, abort) for one object.
+type mockMultipartS3 struct {
+	mu        sync.Mutex
+	parts     map[int][]byte
+	puts      int
+	completed []byte
+	aborted   bool
+	failPart  int
+}
+
+func (m *mockMultipartS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q := r.URL.Query()
+	body, _ := io.ReadAll(r.Body)
+	switch {
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		m.parts = make(map[int][]byte)
+		_, _ = io.WriteString(w, `<InitiateMultipartUploadResult><Bucket>test-bucket</Bucket><Key>k</Key><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>`)
+	case r.Method == http.MethodPut && q.Get("uploadId") == "upload-1":
+		n, _ := strconv.Atoi(q.Get("partNumber"))
+		if n == m.failPart {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		m.parts[n] = decodeAWSChunked(r, body)
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, n))
+	case r.Method == http.MethodPost && q.Get("uploadId") == "upload-1":
+		for i := 1; i <= len(m.parts); i++ {
+			m.completed = append(m.completed, m.parts[i]...)
+		}
+		_, _ = io.WriteString(w, `<CompleteMultipartUploadResult><Bucket>test-bucket</Bucket><Key>k</Key><ETag>"final"</ETag></CompleteMultipartUploadResult>`)
+	case r.Method == http.MethodDelete && q.Get("uploadId") == "upload-1":
+		m.aborted = true
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		m.puts++
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// decodeAWSChunked strips the aws-chunked framing the SDK uses when it sends
+// a trailing checksum, returning the raw payload.
+func decodeAWSChunked(r *http.Request, body []byte) []byte {
+	if !strings.Contains(r.Header.Get("Content-Encoding"), "aws-chunked") {
+		return body
+	}
+	var out []byte
+	for {
+		line, rest, ok := bytes.Cut(body, []byte("\r\n"))
+		if !ok {
+			return out
+		}
+		size, err := strconv.ParseInt(string(bytes.SplitN(line, []byte(";"), 2)[0]), 16, 64)
+		if err != nil || size == 0 {
+			return out
+		}
+		out = append(out, rest[:size]...)
+		body = rest[size+2:]
+	}
+}
+
+func newMultipartTestStorage(t *testing.T, serverURL string) *S3Storage {
+	t.Helper()
+	storage, err := NewS3Storage(t.TempDir(), S3Config{
+		Bucket:             "test-bucket",
+		Region:             "us-east-1",
+		Endpoint:           serverURL,
+		AccessKeyID:        "test-access-key",
+		SecretAccessKey:    "test-secret-key",
+		MultipartThreshold: 1024,
+		PartSize:           1024,
+		UploadConcurrency:  2,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Storage() error = %v", err)
+	}
+	return storage
+}
+
+func TestS3Storage_Upload_Multipart(t *testing.T) {
+	mock := &mockMultipartS3{}
+	server := httptest.NewServer(mock)
+	defer server.Close()
+	storage := newMultipartTestStorage(t, server.URL)
+
+	data := make([]byte, 5*1024+100)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	if _, err := storage.Upload(context.Background(), "videos/job-1.mp4", bytes.NewReader(data)); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	if mock.puts != 0 {
+		t.Errorf("expected no single PutObject, got %d", mock.puts)
+	}
+	if len(mock.parts) != 6 {
+		t.Errorf("expected 6 parts, got %d", len(mock.parts))
+	}
+	if !bytes.Equal(mock.completed, data) {
+		t.Errorf("reassembled object differs from input (%d vs %d bytes)", len(mock.completed), len(data))
+	}
+}
+
+func TestS3Storage_Upload_SmallUsesPutObject(t *testing.T) {
+	mock := &mockMultipartS3{}
+	server := httptest.NewServer(mock)
+	defer server.Close()
+	storage := newMultipartTestStorage(t, server.URL)
+
+	if _, err := storage.Upload(context.Background(), "videos/job-1.mp4", bytes.NewReader(make([]byte, 1023))); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	if mock.puts != 1 {
+		t.Errorf("expected a single PutObject, got %d", mock.puts)
+	}
+	if mock.parts != nil {
+		t.Error("expected no multipart upload for data below the threshold")
+	}
+}
+
+func TestS3Storage_Upload_MultipartAbortsOnFailure(t *testing.T) {
+	mock := &mockMultipartS3{failPart: 2}
+	server := httptest.NewServer(mock)
+	defer server.Close()
+	storage := newMultipartTestStorage(t, server.URL)
+
+	_, err := storage.Upload(context.Background(), "videos/job-1.mp4", bytes.NewReader(make([]byte, 4*1024)))
+	if err == nil {
+		t.Fatal("expected error when a part fails")
+	}
+	if !mock.aborted {
+		t.Error("expected the multipart upload to be aborted")
+	}
+	if mock.completed != nil {
+		t.Error("expected the multipart upload not to be completed")
+	}
+}
+
+func TestS3Storage_Download(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/test-bucket/prod/videos/job-1.mp4":
+			w.Header().Set("Content-Type", "video/mp4")
+			_, _ = io.WriteString(w, "stored video")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`)
+		}
+	}))
+	defer server.Close()
+
+	storage, err := NewS3Storage(t.TempDir(), S3Config{
+		Bucket:          "test-bucket",
+		Region:          "us-east-1",
+		Endpoint:        server.URL,
+		AccessKeyID:     "test-access-key",
+		SecretAccessKey: "test-secret-key",
+		KeyPrefix:       "prod",
+	})
+	if err != nil {
+		t.Fatalf("NewS3Storage() error = %v", err)
+	}
+
+	rc, err := storage.Download(context.Background(), "videos/job-1.mp4")
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	data, err := io.ReadAll(rc)
+	_ = rc.Close()
+	if err != nil {
+		t.Fatalf("read downloaded object: %v", err)
+	}
+	if string(data) != "stored video" {
+		t.Errorf("Download() = %q, want %q", data, "stored video")
+	}
+
+	if _, err := storage.Download(context.Background(), "videos/missing.mp4"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("expected ErrObjectNotFound, got %v", err)
+	}
+}
+
+func TestS3Storage_DeleteObject(t *testing.T) {
+	var gotMethod, gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	storage, err := NewS3Storage(t.TempDir(), S3Config{
+		Bucket:          "test-bucket",
+		Region:          "us-east-1",
+		Endpoint:        server.URL,
+		AccessKeyID:     "test-access-key",
+		SecretAccessKey: "test-secret-key",
+		KeyPrefix:       "prod",
+	})
+	if err != nil {
+		t.Fatalf("NewS3Storage() error = %v", err)
+	}
+
+	if err := storage.DeleteObject(context.Background(), "videos/job-1.mp4"); err != nil {
+		t.Fatalf("DeleteObject() error = %v", err)
+	}
+	if gotMethod != http.MethodDelete {
+		t.Errorf("method = %s, want DELETE", gotMethod)
+	}
+	if gotPath != "/test-bucket/prod/videos/job-1.mp4" {
+		t.Errorf("path = %s, want /test-bucket/prod/videos/job-1.mp4", gotPath)
+	}
+}
+
+func TestS3Storage_Ping(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "bucket reachable", status: http.StatusOK},
+		{name: "bucket missing", status: http.StatusNotFound, wantErr: true},
+		{name: "access denied", status: http.StatusForbidden, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodHead {
+					t.Errorf("expected HEAD method, got %s", r.Method)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			storage, err := NewS3Storage(t.TempDir(), S3Config{
+				Bucket:          "test-bucket",
+				Region:          "us-east-1",
+				Endpoint:        server.URL,
+				AccessKeyID:     "test-access-key",
+				SecretAccessKey: "test-secret-key",
+			})
+			if err != nil {
+				t.Fatalf("NewS3Storage() error = %v", err)
+			}
+
+			err = storage.Ping(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Ping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,16 +1,20 @@
 // Package storage provides temporary and persistent file storage capabilities.
 // It defines the Storage interface (port) for hexagonal architecture and
-// implementations for local disk and S3 storage.
+// implementations for local disk, S3 and Google Cloud Storage.
 package storage
 
 import (
 	"context"
+	"errors"
 	"io"
 )
 
+// ErrObjectNotFound is returned when a remote object does not exist.
+var ErrObjectNotFound = errors.New("object not found")
+
 // Storage defines the interface for temporary and persistent file storage.
 // Implementations must handle temporary files during processing and
-// optionally support S3 uploads for final video delivery.
+// optionally support uploads to remote storage for final video delivery.
 type Storage interface {
 	// SaveTemp saves data to a temporary file and returns the file path.
 	// The name parameter is used as a hint for the filename.
@@ -24,7 +28,24 @@ type Storage interface {
 	// It continues cleanup even if some files fail to delete.
 	CleanupTemp(ctx context.Context, paths []string) error
 
-	// UploadToS3 uploads data to S3 and returns the public URL.
-	// Returns ErrS3NotConfigured if S3 is not configured.
-	UploadToS3(ctx context.Context, key string, data io.Reader) (url string, err error)
+	// Upload uploads data to remote storage (S3, GCS) and returns its URL.
+	// Returns ErrRemoteNotConfigured if no remote storage is configured.
+	Upload(ctx context.Context, key string, data io.Reader) (url string, err error)
+
+	// ObjectURL returns a URL clients can fetch the object stored under key
+	// from. Storages that presign URLs return a fresh one on every call, so
+	// callers should resolve it when it is served rather than store it.
+	// Returns ErrRemoteNotConfigured if no remote storage is configured.
+	ObjectURL(ctx context.Context, key string) (url string, err error)
+
+	// Download opens an object previously stored with Upload under key.
+	// The caller is responsible for closing the returned ReadCloser.
+	// Returns ErrObjectNotFound if the object does not exist and
+	// ErrRemoteNotConfigured if no remote storage is configured.
+	Download(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// DeleteObject removes an object previously stored with Upload under key.
+	// Deleting an object that does not exist succeeds.
+	// Returns ErrRemoteNotConfigured if no remote storage is configured.
+	DeleteObject(ctx context.Context, key string) error
 }
@@ -0,0 +1,241 @@
+// Package urlsafe guards requests the server makes to URLs supplied by
+// clients against server-side request forgery (SSRF). A Guard rejects URLs
+// whose host resolves to a private, loopback, link-local or otherwise
+// internal address, and optionally restricts the schemes and hosts allowed.
+package urlsafe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Static errors for URL checks.
+var (
+	// ErrSchemeNotAllowed is returned for URLs whose scheme is not allowed.
+	ErrSchemeNotAllowed = errors.New("urlsafe: URL scheme not allowed")
+	// ErrHostNotAllowed is returned for URLs without a host or whose host is not in the allow-list.
+	ErrHostNotAllowed = errors.New("urlsafe: host not allowed")
+	// ErrBlockedAddress is returned when a host resolves to an internal address.
+	ErrBlockedAddress = errors.New("urlsafe: address not allowed")
+)
+
+// DefaultSchemes are the schemes allowed when none are configured.
+var DefaultSchemes = []string{"http", "https"}
+
+// blockedPrefixes are the internal and reserved ranges not covered by the
+// netip.Addr predicates used in IsBlocked.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),   // reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64, which can reach internal IPv4 addresses
+}
+
+// Resolver looks up the addresses of a host.
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// Guard checks URLs and dialed addresses before the server connects to them.
+// A Guard is safe for concurrent use.
+type Guard struct {
+	schemes      []string
+	hosts        []string
+	allowPrivate bool
+	resolver     Resolver
+}
+
+// Option is a function that configures a Guard.
+type Option func(*Guard)
+
+// WithAllowedSchemes restricts URLs to these schemes. Empty keeps DefaultSchemes.
+func WithAllowedSchemes(schemes ...string) Option {
+	return func(g *Guard) {
+		if schemes = normalize(schemes); len(schemes) > 0 {
+			g.schemes = schemes
+		}
+	}
+}
+
+// WithAllowedHosts restricts URLs to these hosts ("host" or "host:port").
+// Empty allows any host that does not resolve to an internal address.
+func WithAllowedHosts(hosts ...string) Option {
+	return func(g *Guard) {
+		g.hosts = normalize(hosts)
+	}
+}
+
+// WithPrivateNetworks allows hosts resolving to internal addresses, e.g. for
+// development against local services. Schemes and hosts are still checked.
+func WithPrivateNetworks(allow bool) Option {
+	return func(g *Guard) {
+		g.allowPrivate = allow
+	}
+}
+
+// WithResolver sets the resolver used to look up hosts.
+func WithResolver(r Resolver) Option {
+	return func(g *Guard) {
+		g.resolver = r
+	}
+}
+
+// New creates a Guard. By default it allows http and https URLs to any host
+// that does not resolve to an internal address.
+func New(opts ...Option) *Guard {
+	g := &Guard{
+		schemes:  DefaultSchemes,
+		resolver: net.DefaultResolver,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Check verifies that rawURL has an allowed scheme and host, and resolves the
+// host to verify that none of its addresses is internal.
+func (g *Guard) Check(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("urlsafe: parse URL: %w", err)
+	}
+	if !slices.Contains(g.schemes, strings.ToLower(u.Scheme)) {
+		return fmt.Errorf("%w: %q", ErrSchemeNotAllowed, u.Scheme)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("%w: missing host", ErrHostNotAllowed)
+	}
+	if len(g.hosts) > 0 && !slices.Contains(g.hosts, strings.ToLower(u.Host)) && !slices.Contains(g.hosts, strings.ToLower(host)) {
+		return fmt.Errorf("%w: %s", ErrHostNotAllowed, u.Host)
+	}
+	if g.allowPrivate {
+		return nil
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return g.checkAddr(host, addr)
+	}
+	ips, err := g.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("urlsafe: resolve %s: %w", host, err)
+	}
+	// Any internal address is rejected, as the dialer may pick any of them
+	for _, ip := range ips {
+		addr, ok := netip.AddrFromSlice(ip.IP)
+		if !ok {
+			return fmt.Errorf("%w: %s resolves to %s", ErrBlockedAddress, host, ip)
+		}
+		if err := g.checkAddr(host, addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Control is a net.Dialer Control function rejecting connections to internal
+// addresses. It checks the address actually dialed, so hosts that resolve
+// differently after Check (DNS rebinding) are still rejected.
+func (g *Guard) Control(_, address string, _ syscall.RawConn) error {
+	if g.allowPrivate {
+		return nil
+	}
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+	if IsBlocked(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+	return nil
+}
+
+// Client returns a copy of base that only dials allowed addresses and checks
+// every redirect with Check. The copy always uses an *http.Transport, cloned
+// from base's when it has one, and connects directly rather than through a
+// proxy, so the address dialed is the one checked.
+func (g *Guard) Client(base *http.Client) *http.Client {
+	c := *base
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if t, ok := base.Transport.(*http.Transport); ok {
+		transport = t.Clone()
+	}
+	transport.Proxy = nil
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   g.Control,
+	}
+	transport.DialContext = dialer.DialContext
+	c.Transport = transport
+
+	next := base.CheckRedirect
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := g.Check(req.Context(), req.URL.String()); err != nil {
+			return err
+		}
+		if next != nil {
+			return next(req, via)
+		}
+		// Same limit as the default policy of http.Client
+		if len(via) >= 10 {
+			return errors.New("urlsafe: stopped after 10 redirects")
+		}
+		return nil
+	}
+	return &c
+}
+
+// checkAddr rejects addr, which host resolved to, when it is internal.
+func (g *Guard) checkAddr(host string, addr netip.Addr) error {
+	if IsBlocked(addr) {
+		if host == addr.String() {
+			return fmt.Errorf("%w: %s", ErrBlockedAddress, addr)
+		}
+		return fmt.Errorf("%w: %s resolves to %s", ErrBlockedAddress, host, addr)
+	}
+	return nil
+}
+
+// IsBlocked reports whether addr is a private, loopback, link-local,
+// multicast, unspecified or reserved address the server must not connect to
+// on behalf of a client. IPv4-mapped IPv6 addresses are checked as IPv4.
+func IsBlocked(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return true
+	}
+	for _, p := range blockedPrefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize lowercases and trims the entries of a scheme or host list,
+// dropping empty ones.
+func normalize(entries []string) []string {
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e = strings.ToLower(strings.TrimSpace(e)); e != "" {
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,160 @@
+package urlsafe
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResolver resolves hosts from a fixed table.
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	ips := make([]net.IPAddr, 0, len(addrs))
+	for _, a := range addrs {
+		ips = append(ips, net.IPAddr{IP: net.ParseIP(a)})
+	}
+	return ips, nil
+}
+
+func TestIsBlocked(t *testing.T) {
+	blocked := []string{
+		"169.254.169.254", // cloud metadata
+		"127.0.0.1",
+		"127.1.2.3",
+		"10.0.0.1",
+		"10.255.255.255",
+		"172.16.0.1",
+		"192.168.1.1",
+		"100.64.0.1",
+		"0.0.0.0",
+		"255.255.255.255",
+		"224.0.0.1",
+		"::1",
+		"::",
+		"fe80::1",
+		"fd00::1",
+		"ff02::1",
+		"::ffff:127.0.0.1",
+		"::ffff:169.254.169.254",
+		"64:ff9b::a00:1",
+	}
+	for _, a := range blocked {
+		assert.True(t, IsBlocked(netip.MustParseAddr(a)), a)
+	}
+
+	public := []string{"93.184.216.34", "8.8.8.8", "172.32.0.1", "2606:4700:4700::1111", "::ffff:8.8.8.8"}
+	for _, a := range public {
+		assert.False(t, IsBlocked(netip.MustParseAddr(a)), a)
+	}
+}
+
+func TestGuard_Check(t *testing.T) {
+	g := New(WithResolver(fakeResolver{
+		"cdn.example.com":      {"93.184.216.34", "2606:2800:220:1::248"},
+		"internal.example.com": {"10.0.0.5"},
+		"rebind.example.com":   {"93.184.216.34", "127.0.0.1"},
+	}))
+
+	tests := []struct {
+		url     string
+		wantErr error
+	}{
+		{"https://cdn.example.com/face.png", nil},
+		{"http://93.184.216.34/face.png", nil},
+		{"http://[2606:4700:4700::1111]/face.png", nil},
+		{"http://169.254.169.254/latest/meta-data/", ErrBlockedAddress},
+		{"http://127.0.0.1:8080/jobs", ErrBlockedAddress},
+		{"http://10.1.2.3/face.png", ErrBlockedAddress},
+		{"http://[::1]/face.png", ErrBlockedAddress},
+		{"http://[::ffff:10.0.0.1]/face.png", ErrBlockedAddress},
+		{"http://internal.example.com/face.png", ErrBlockedAddress},
+		{"http://rebind.example.com/face.png", ErrBlockedAddress},
+		{"file:///etc/passwd", ErrSchemeNotAllowed},
+		{"gopher://cdn.example.com/", ErrSchemeNotAllowed},
+		{"http:///face.png", ErrHostNotAllowed},
+	}
+
+	for _, tt := range tests {
+		err := g.Check(context.Background(), tt.url)
+		if tt.wantErr == nil {
+			assert.NoError(t, err, tt.url)
+		} else {
+			assert.ErrorIs(t, err, tt.wantErr, tt.url)
+		}
+	}
+}
+
+func TestGuard_CheckResolveFailure(t *testing.T) {
+	g := New(WithResolver(fakeResolver{}))
+
+	err := g.Check(context.Background(), "https://missing.example.com/face.png")
+	var dnsErr *net.DNSError
+	assert.True(t, errors.As(err, &dnsErr), "expected a DNS error, got %v", err)
+}
+
+func TestGuard_AllowList(t *testing.T) {
+	g := New(
+		WithAllowedSchemes("HTTPS"),
+		WithAllowedHosts("CDN.example.com", " media.example.com:8443 "),
+		WithResolver(fakeResolver{
+			"cdn.example.com":   {"93.184.216.34"},
+			"media.example.com": {"93.184.216.35"},
+			"other.example.com": {"93.184.216.36"},
+		}),
+	)
+
+	assert.NoError(t, g.Check(context.Background(), "https://cdn.example.com/face.png"))
+	assert.NoError(t, g.Check(context.Background(), "https://media.example.com:8443/voice.wav"))
+	assert.ErrorIs(t, g.Check(context.Background(), "https://media.example.com/voice.wav"), ErrHostNotAllowed)
+	assert.ErrorIs(t, g.Check(context.Background(), "https://other.example.com/face.png"), ErrHostNotAllowed)
+	assert.ErrorIs(t, g.Check(context.Background(), "http://cdn.example.com/face.png"), ErrSchemeNotAllowed)
+}
+
+func TestGuard_PrivateNetworks(t *testing.T) {
+	g := New(WithPrivateNetworks(true), WithAllowedHosts("127.0.0.1:9000"))
+
+	assert.NoError(t, g.Check(context.Background(), "http://127.0.0.1:9000/bucket/face.png"))
+	assert.ErrorIs(t, g.Check(context.Background(), "http://10.0.0.1/face.png"), ErrHostNotAllowed)
+	assert.ErrorIs(t, g.Check(context.Background(), "ftp://127.0.0.1:9000/face.png"), ErrSchemeNotAllowed)
+	assert.NoError(t, g.Control("tcp", "10.0.0.1:80", nil))
+}
+
+func TestGuard_Control(t *testing.T) {
+	g := New()
+
+	for _, addr := range []string{"169.254.169.254:80", "127.0.0.1:8080", "10.0.0.1:443", "[::1]:80"} {
+		assert.ErrorIs(t, g.Control("tcp", addr, nil), ErrBlockedAddress, addr)
+	}
+	assert.NoError(t, g.Control("tcp", "93.184.216.34:443", nil))
+	assert.NoError(t, g.Control("tcp6", "[2606:4700:4700::1111]:443", nil))
+}
+
+func TestGuard_Client(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	base := &http.Client{}
+	client := New().Client(base)
+	require.NotSame(t, base, client)
+	assert.Nil(t, base.Transport, "the base client must not be modified")
+
+	// Dialing the loopback test server is rejected even without calling Check
+	_, err := client.Get(server.URL)
+	assert.ErrorIs(t, err, ErrBlockedAddress)
+	assert.Zero(t, requests)
+}